@@ -22,8 +22,11 @@ import (
 
 	"cloud.google.com/go/pubsub"
 
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
+	gstorage "github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
@@ -63,6 +66,17 @@ type envConfig struct {
 
 	// Max to 10m.
 	TimeoutPerEvent time.Duration `envconfig:"TIMEOUT_PER_EVENT"`
+
+	// CircuitBreakerFailureThreshold is the number of consecutive failed deliveries to a subscriber
+	// that opens its circuit breaker. Zero disables the circuit breakers.
+	CircuitBreakerFailureThreshold int `envconfig:"CIRCUIT_BREAKER_FAILURE_THRESHOLD" default:"5"`
+	// CircuitBreakerOpenDuration is how long deliveries to a subscriber are short-circuited
+	// before its circuit breaker lets a delivery through to probe it.
+	CircuitBreakerOpenDuration time.Duration `envconfig:"CIRCUIT_BREAKER_OPEN_DURATION" default:"30s"`
+
+	// ClaimCheckBucket is the GCS bucket the ingress offloads the data of large events to. The
+	// data of events is only read back from this bucket.
+	ClaimCheckBucket string `envconfig:"CLAIM_CHECK_BUCKET"`
 }
 
 func main() {
@@ -89,6 +103,19 @@ func main() {
 	}
 
 	syncSignal := poolSyncSignal(ctx, targetsUpdateCh)
+	var probeOpts []handler.ProbeOption
+	cb := circuitBreakers(env)
+	if cb != nil {
+		probeOpts = append(probeOpts, handler.WithProbeHandler(deliver.CircuitsPath, cb))
+	}
+	// Events offloaded to GCS by the ingress are delivered with their data. Events offloaded
+	// before the BrokerCell stopped offloading events fail to be delivered, as their bucket is
+	// no longer known.
+	storageClient, err := gstorage.NewClient(ctx)
+	if err != nil {
+		logger.Fatal("Failed to create GCS client for claim check", zap.Error(err))
+	}
+	handlerOpts := append(buildHandlerOptions(env, cb), handler.WithRehydrator(&claimcheck.Rehydrator{Client: storageClient, Bucket: env.ClaimCheckBucket}))
 	syncPool, err := InitializeSyncPool(
 		ctx,
 		clients.ProjectID(projectID),
@@ -98,12 +125,12 @@ func main() {
 			volume.WithPath(env.TargetsConfigPath),
 			volume.WithNotifyChan(targetsUpdateCh),
 		},
-		buildHandlerOptions(env)...,
+		handlerOpts...,
 	)
 	if err != nil {
 		logger.Fatal("Failed to create fanout sync pool", zap.Error(err))
 	}
-	if _, err := handler.StartSyncPool(ctx, syncPool, syncSignal, env.MaxStaleDuration, handler.DefaultProbeCheckPort, authcheck.NewDefault(env.AuthType)); err != nil {
+	if _, err := handler.StartSyncPool(ctx, syncPool, syncSignal, env.MaxStaleDuration, handler.DefaultProbeCheckPort, authcheck.NewDefault(env.AuthType), probeOpts...); err != nil {
 		logger.Fatalw("Failed to start fanout sync pool", zap.Error(err))
 	}
 
@@ -134,7 +161,7 @@ func poolSyncSignal(ctx context.Context, targetsUpdateCh chan struct{}) chan str
 	return ch
 }
 
-func buildHandlerOptions(env envConfig) []handler.Option {
+func buildHandlerOptions(env envConfig, cb *deliver.CircuitBreakers) []handler.Option {
 	rs := pubsub.DefaultReceiveSettings
 	var opts []handler.Option
 	if env.HandlerConcurrency > 0 {
@@ -155,6 +182,16 @@ func buildHandlerOptions(env envConfig) []handler.Option {
 		rs.MaxOutstandingMessages = env.MaxOutstandingMessages
 	}
 	opts = append(opts, handler.WithPubsubReceiveSettings(rs))
+	if cb != nil {
+		opts = append(opts, handler.WithCircuitBreakers(cb))
+	}
 	// The default CeClient is good?
 	return opts
 }
+
+func circuitBreakers(env envConfig) *deliver.CircuitBreakers {
+	if env.CircuitBreakerFailureThreshold <= 0 {
+		return nil
+	}
+	return deliver.NewCircuitBreakers(env.CircuitBreakerFailureThreshold, env.CircuitBreakerOpenDuration)
+}
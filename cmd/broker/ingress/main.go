@@ -17,6 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"context"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/ingress"
+	gstorage "github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
@@ -37,6 +42,15 @@ type envConfig struct {
 
 	// Default 300Mi.
 	PublishBufferedByteLimit int `envconfig:"PUBLISH_BUFFERED_BYTES_LIMIT" default:"314572800"`
+
+	// MaxRequestBodyBytes is the maximum size of the events the ingress accepts.
+	MaxRequestBodyBytes int64 `envconfig:"MAX_REQUEST_BODY_BYTES" default:"10000000"`
+
+	// ClaimCheckBucket is the GCS bucket the data of large events is offloaded to. If empty,
+	// the data of all the events is published to Pub/Sub.
+	ClaimCheckBucket string `envconfig:"CLAIM_CHECK_BUCKET"`
+	// ClaimCheckThresholdBytes is the data size above which the data of events is offloaded.
+	ClaimCheckThresholdBytes int64 `envconfig:"CLAIM_CHECK_THRESHOLD_BYTES" default:"1000000"`
 }
 
 const (
@@ -63,6 +77,11 @@ func main() {
 	}
 	logger.Desugar().Info("Starting ingress handler", zap.Any("envConfig", env), zap.Any("Project ID", projectID))
 
+	offloader, err := claimCheckOffloader(ctx, env)
+	if err != nil {
+		logger.Desugar().Fatal("Failed to create GCS client for claim check", zap.Error(err))
+	}
+
 	ingress, err := InitializeHandler(
 		ctx,
 		clients.Port(env.Port),
@@ -71,6 +90,8 @@ func main() {
 		metrics.ContainerName(component),
 		publishSetting(logger.Desugar(), env),
 		env.AuthType,
+		ingress.MaxRequestBodyBytes(env.MaxRequestBodyBytes),
+		offloader,
 	)
 	if err != nil {
 		logger.Desugar().Fatal("Unable to create ingress handler: ", zap.Error(err))
@@ -82,6 +103,21 @@ func main() {
 	}
 }
 
+func claimCheckOffloader(ctx context.Context, env envConfig) (*claimcheck.Offloader, error) {
+	if env.ClaimCheckBucket == "" {
+		return nil, nil
+	}
+	client, err := gstorage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &claimcheck.Offloader{
+		Client:         client,
+		Bucket:         env.ClaimCheckBucket,
+		ThresholdBytes: env.ClaimCheckThresholdBytes,
+	}, nil
+}
+
 func publishSetting(logger *zap.Logger, env envConfig) pubsub.PublishSettings {
 	s := pubsub.DefaultPublishSettings
 	if env.PublishBufferedByteLimit > 0 {
@@ -22,6 +22,7 @@ import (
 	"context"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/ingress"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -38,6 +39,8 @@ func InitializeHandler(
 	containerName metrics.ContainerName,
 	publishSettings pubsub.PublishSettings,
 	authType authcheck.AuthType,
+	maxRequestBodyBytes ingress.MaxRequestBodyBytes,
+	offloader *claimcheck.Offloader,
 ) (*ingress.Handler, error) {
 	panic(wire.Build(
 		ingress.HandlerSet,
@@ -8,6 +8,7 @@ package main
 import (
 	"cloud.google.com/go/pubsub"
 	"context"
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/ingress"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -17,7 +18,7 @@ import (
 
 // Injectors from wire.go:
 
-func InitializeHandler(ctx context.Context, port clients.Port, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, publishSettings pubsub.PublishSettings, authType authcheck.AuthType) (*ingress.Handler, error) {
+func InitializeHandler(ctx context.Context, port clients.Port, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, publishSettings pubsub.PublishSettings, authType authcheck.AuthType, maxRequestBodyBytes ingress.MaxRequestBodyBytes, offloader *claimcheck.Offloader) (*ingress.Handler, error) {
 	httpMessageReceiver := clients.NewHTTPMessageReceiverWithChecker(port, authType)
 	v := _wireValue
 	readonlyTargets, err := volume.NewTargetsFromFile(v...)
@@ -29,11 +30,12 @@ func InitializeHandler(ctx context.Context, port clients.Port, projectID clients
 		return nil, err
 	}
 	multiTopicDecoupleSink := ingress.NewMultiTopicDecoupleSink(ctx, readonlyTargets, client, publishSettings)
+	policyAuthorizer := ingress.NewPolicyAuthorizer(readonlyTargets)
 	ingressReporter, err := metrics.NewIngressReporter(podName, containerName)
 	if err != nil {
 		return nil, err
 	}
-	handler := ingress.NewHandler(ctx, httpMessageReceiver, multiTopicDecoupleSink, ingressReporter, authType)
+	handler := ingress.NewHandler(ctx, httpMessageReceiver, multiTopicDecoupleSink, policyAuthorizer, ingressReporter, authType, maxRequestBodyBytes, offloader)
 	return handler, nil
 }
 
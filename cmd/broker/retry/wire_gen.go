@@ -25,11 +25,16 @@ func InitializeSyncPool(ctx context.Context, projectID clients.ProjectID, podNam
 		return nil, err
 	}
 	httpClient := _wireClientValue
+	v := _wireValue
+	retryClient, err := handler.NewRetryClient(ctx, client, v...)
+	if err != nil {
+		return nil, err
+	}
 	deliveryReporter, err := metrics.NewDeliveryReporter(podName, containerName)
 	if err != nil {
 		return nil, err
 	}
-	retryPool, err := handler.NewRetryPool(readonlyTargets, client, httpClient, deliveryReporter, opts...)
+	retryPool, err := handler.NewRetryPool(readonlyTargets, client, httpClient, retryClient, deliveryReporter, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,4 +43,5 @@ func InitializeSyncPool(ctx context.Context, projectID clients.ProjectID, podNam
 
 var (
 	_wireClientValue = handler.DefaultHTTPClient
+	_wireValue       = handler.DefaultCEClientOpts
 )
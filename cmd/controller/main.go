@@ -27,12 +27,16 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/broker"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell"
 	"github.com/google/knative-gcp/pkg/reconciler/deployment"
+	"github.com/google/knative-gcp/pkg/reconciler/events/artifactregistry"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
+	"github.com/google/knative-gcp/pkg/reconciler/events/billingbudget"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
 	kedapullsubscription "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda"
+	pushpullsubscription "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/push"
 	staticpullsubscription "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/static"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/topic"
 	"github.com/google/knative-gcp/pkg/reconciler/messaging/channel"
@@ -59,8 +63,12 @@ func Controllers(
 	schedulerController scheduler.Constructor,
 	pubsubController pubsub.Constructor,
 	buildController build.Constructor,
+	artifactRegistryController artifactregistry.Constructor,
+	billingBudgetController billingbudget.Constructor,
+	monitoringAlertController monitoring.Constructor,
 	pullsubscriptionController staticpullsubscription.Constructor,
 	kedaPullsubscriptionController kedapullsubscription.Constructor,
+	pushPullsubscriptionController pushpullsubscription.Constructor,
 	topicController topic.Constructor,
 	channelController channel.Constructor,
 	triggerController trigger.Constructor,
@@ -74,8 +82,12 @@ func Controllers(
 		injection.ControllerConstructor(schedulerController),
 		injection.ControllerConstructor(pubsubController),
 		injection.ControllerConstructor(buildController),
+		injection.ControllerConstructor(artifactRegistryController),
+		injection.ControllerConstructor(billingBudgetController),
+		injection.ControllerConstructor(monitoringAlertController),
 		injection.ControllerConstructor(pullsubscriptionController),
 		injection.ControllerConstructor(kedaPullsubscriptionController),
+		injection.ControllerConstructor(pushPullsubscriptionController),
 		injection.ControllerConstructor(topicController),
 		injection.ControllerConstructor(channelController),
 		injection.ControllerConstructor(triggerController),
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/pubsub/push"
+	"github.com/google/knative-gcp/pkg/utils/appcredentials"
+	"github.com/google/knative-gcp/pkg/utils/clients"
+	"github.com/google/knative-gcp/pkg/utils/mainhelper"
+
+	"go.uber.org/zap"
+
+	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/pullsubscription"
+)
+
+type envConfig struct {
+	Port int `envconfig:"PORT" default:"8080"`
+
+	// Audience is the audience of the OIDC tokens Pub/Sub attaches to the push requests. The push
+	// PullSubscription controller uses the URL of the push receiver as the audience. All the push
+	// requests are rejected until it is set.
+	Audience string `envconfig:"PUSH_AUDIENCE"`
+
+	// ServiceAccount is the email of the Google service account the OIDC tokens are issued for.
+	// All the push requests are rejected until it is set.
+	ServiceAccount string `envconfig:"PUSH_SERVICE_ACCOUNT"`
+
+	// MaxRequestBodyBytes is the maximum size of the push requests the receiver accepts.
+	MaxRequestBodyBytes int64 `envconfig:"MAX_REQUEST_BODY_BYTES" default:"15000000"`
+}
+
+const (
+	component = "pubsub-push-receiver"
+
+	// TODO make this configurable
+	maxConnectionsPerHost = 1000
+)
+
+// main creates and starts the shared push receiver of the PullSubscriptions in the push delivery
+// mode. It listens on the port specified by the "PORT" env var, or 8080 if it is not set.
+func main() {
+	appcredentials.MustExistOrUnsetEnv()
+
+	var env envConfig
+	ctx, res := mainhelper.Init(component, mainhelper.WithEnv(&env))
+	defer res.Cleanup()
+	logger := res.Logger
+
+	logger.Desugar().Info("Starting push receiver", zap.Any("envConfig", env))
+
+	receiver := push.NewReceiver(
+		ctx,
+		pullsubscriptioninformers.Get(ctx).Lister(),
+		clients.NewHTTPClient(ctx, maxConnectionsPerHost),
+		converters.NewPubSubConverter(),
+		push.ReceiverArgs{
+			Audience:            env.Audience,
+			ServiceAccount:      env.ServiceAccount,
+			MaxRequestBodyBytes: env.MaxRequestBodyBytes,
+		},
+	)
+
+	if err := clients.NewHTTPMessageReceiver(clients.Port(env.Port)).StartListen(ctx, receiver); err != nil {
+		logger.Desugar().Fatal("Failed to start push receiver", zap.Error(err))
+	}
+}
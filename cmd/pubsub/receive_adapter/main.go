@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"time"
@@ -30,6 +31,7 @@ import (
 	"knative.dev/pkg/signals"
 	"knative.dev/pkg/tracing"
 
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	. "github.com/google/knative-gcp/pkg/pubsub/adapter"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	tracingconfig "github.com/google/knative-gcp/pkg/tracing"
@@ -77,6 +79,18 @@ type envConfig struct {
 	// subscription to use.
 	Subscription string `envconfig:"PUBSUB_SUBSCRIPTION_ID" required:"true"`
 
+	// EventMappingJson is a json string of the EventMapping of the PullSubscription, if any.
+	// It is only used by the mapping adapter type.
+	EventMappingJson string `envconfig:"K_EVENT_MAPPING"`
+
+	// ImageFilterJson is a json string of the ImageFilter of the PullSubscription, if any.
+	// It is only used by the artifactregistry adapter type.
+	ImageFilterJson string `envconfig:"K_IMAGE_FILTER"`
+
+	// BudgetFilterJson is a json string of the BudgetFilter of the PullSubscription, if any.
+	// It is only used by the billingbudget adapter type.
+	BudgetFilterJson string `envconfig:"K_BUDGET_FILTER"`
+
 	// ExtensionsBase64 is a based64 encoded json string of a map of
 	// CloudEvents extensions (key-value pairs) override onto the outbound
 	// event.
@@ -169,11 +183,38 @@ func main() {
 		logger.Error("Failed to convert base64 extensions to map: %v", zap.Error(err))
 	}
 
+	var eventMapping *duckv1.EventMapping
+	if env.EventMappingJson != "" {
+		eventMapping = &duckv1.EventMapping{}
+		if err := json.Unmarshal([]byte(env.EventMappingJson), eventMapping); err != nil {
+			logger.Fatal("Failed to process event mapping", zap.Error(err))
+		}
+	}
+
+	var imageFilter *duckv1.ImageFilter
+	if env.ImageFilterJson != "" {
+		imageFilter = &duckv1.ImageFilter{}
+		if err := json.Unmarshal([]byte(env.ImageFilterJson), imageFilter); err != nil {
+			logger.Fatal("Failed to process image filter", zap.Error(err))
+		}
+	}
+
+	var budgetFilter *duckv1.BudgetFilter
+	if env.BudgetFilterJson != "" {
+		budgetFilter = &duckv1.BudgetFilter{}
+		if err := json.Unmarshal([]byte(env.BudgetFilterJson), budgetFilter); err != nil {
+			logger.Fatal("Failed to process budget filter", zap.Error(err))
+		}
+	}
+
 	logger.Info("Initializing adapter", zap.String("projectID", projectID), zap.String("topicID", env.Topic), zap.String("subscriptionID", env.Subscription))
 
 	args := &AdapterArgs{
 		TopicID:        env.Topic,
 		ConverterType:  converters.ConverterType(env.AdapterType),
+		EventMapping:   eventMapping,
+		ImageFilter:    imageFilter,
+		BudgetFilter:   budgetFilter,
 		SinkURI:        env.Sink,
 		TransformerURI: env.Transformer,
 		Extensions:     extensions,
@@ -24,10 +24,14 @@ import (
 	"knative.dev/pkg/logging"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 )
 
 // SetDefaults sets the default field values for a Broker.
 func (b *Broker) SetDefaults(ctx context.Context) {
+	// Assign the Broker to the default BrokerCell unless it names another one.
+	inteventsv1alpha1.SetDefaultBrokerCell(b)
+
 	// Apply the default Broker delivery settings from the context.
 	withNS := apis.WithinParent(ctx, b.ObjectMeta)
 	deliverySpecDefaults := brokerdelivery.FromContextOrDefaults(withNS).BrokerDeliverySpecDefaults
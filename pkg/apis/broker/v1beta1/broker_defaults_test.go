@@ -104,6 +104,9 @@ func TestBroker_SetDefaults(t *testing.T) {
 	}{
 		"default everything from cluster": {
 			expected: Broker{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "default"},
+				},
 				Spec: eventingv1beta1.BrokerSpec{
 					Delivery: &eventingduckv1beta1.DeliverySpec{
 						BackoffDelay:  &clusterDefaultedBackoffDelay,
@@ -121,6 +124,9 @@ func TestBroker_SetDefaults(t *testing.T) {
 		},
 		"default backoff policy from cluster": {
 			expected: Broker{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "default"},
+				},
 				Spec: eventingv1beta1.BrokerSpec{
 					Delivery: &eventingduckv1beta1.DeliverySpec{
 						BackoffDelay:  &clusterDefaultedBackoffDelay,
@@ -155,7 +161,8 @@ func TestBroker_SetDefaults(t *testing.T) {
 			},
 			expected: Broker{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "mynamespace",
+					Namespace:   "mynamespace",
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "default"},
 				},
 				Spec: eventingv1beta1.BrokerSpec{
 					Delivery: &eventingduckv1beta1.DeliverySpec{
@@ -178,7 +185,8 @@ func TestBroker_SetDefaults(t *testing.T) {
 			},
 			expected: Broker{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "mynamespace2",
+					Namespace:   "mynamespace2",
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "default"},
 				},
 				Spec: eventingv1beta1.BrokerSpec{
 					Delivery: &eventingduckv1beta1.DeliverySpec{
@@ -199,7 +207,8 @@ func TestBroker_SetDefaults(t *testing.T) {
 			},
 			expected: Broker{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "mynamespace3",
+					Namespace:   "mynamespace3",
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "default"},
 				},
 				Spec: eventingv1beta1.BrokerSpec{
 					Delivery: &eventingduckv1beta1.DeliverySpec{
@@ -215,7 +224,8 @@ func TestBroker_SetDefaults(t *testing.T) {
 			},
 			expected: Broker{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "mynamespace4",
+					Namespace:   "mynamespace4",
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "default"},
 				},
 				Spec: eventingv1beta1.BrokerSpec{
 					Delivery: &eventingduckv1beta1.DeliverySpec{},
@@ -238,7 +248,8 @@ func TestBroker_SetDefaults(t *testing.T) {
 			},
 			expected: Broker{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "mynamespace4",
+					Namespace:   "mynamespace4",
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "default"},
 				},
 				Spec: eventingv1beta1.BrokerSpec{
 					Delivery: &eventingduckv1beta1.DeliverySpec{
@@ -253,6 +264,23 @@ func TestBroker_SetDefaults(t *testing.T) {
 				},
 			},
 		},
+		"keep the brokercell": {
+			initial: Broker{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "mynamespace4",
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "dedicated"},
+				},
+			},
+			expected: Broker{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "mynamespace4",
+					Annotations: map[string]string{"events.cloud.google.com/brokercell": "dedicated"},
+				},
+				Spec: eventingv1beta1.BrokerSpec{
+					Delivery: &eventingduckv1beta1.DeliverySpec{},
+				},
+			},
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -19,6 +19,8 @@ package v1beta1
 import (
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
+
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 )
 
 var brokerCondSet = apis.NewLivingConditionSet(
@@ -70,6 +72,14 @@ func (bs *BrokerStatus) SetAddress(url *apis.URL) {
 	}
 }
 
+// SetBrokerCell reports the name of the BrokerCell serving this Broker in the status annotations.
+func (bs *BrokerStatus) SetBrokerCell(name string) {
+	if bs.Annotations == nil {
+		bs.Annotations = make(map[string]string, 1)
+	}
+	bs.Annotations[inteventsv1alpha1.BrokerCellAnnotationKey] = name
+}
+
 func (bs *BrokerStatus) MarkBrokerCellUnknown(reason, format string, args ...interface{}) {
 	brokerCondSet.Manage(bs).MarkUnknown(BrokerConditionBrokerCell, reason, format, args...)
 }
@@ -385,3 +385,12 @@ func TestBrokerConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestBrokerSetBrokerCell(t *testing.T) {
+	bs := &BrokerStatus{}
+	bs.SetBrokerCell("dedicated")
+	want := map[string]string{"events.cloud.google.com/brokercell": "dedicated"}
+	if diff := cmp.Diff(want, bs.Annotations); diff != "" {
+		t.Errorf("unexpected status annotations (-want, +got) = %v", diff)
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -30,6 +32,17 @@ const (
 	// BrokerClass is the annotation value to use when creating a
 	// Google Cloud Broker object.
 	BrokerClass = "googlecloud"
+
+	// OrderingKeyAttributeAnnotationKey is the annotation key used to enable ordered delivery. Its
+	// value is the name of the CloudEvents attribute whose value is used as the Pub/Sub ordering
+	// key of the Broker's events. It can't be changed once the Broker is created, as Pub/Sub
+	// doesn't allow enabling message ordering on existing subscriptions.
+	OrderingKeyAttributeAnnotationKey = "events.cloud.google.com/ordering-key-attribute"
+
+	// IngressPolicyAnnotationKey is the annotation key used to specify an IngressPolicy encoded as
+	// JSON. When the annotation is present, the Broker's ingress only accepts events from senders
+	// that authenticate with a token of one of the policy's issuers and are allowed by its rules.
+	IngressPolicyAnnotationKey = "events.cloud.google.com/ingress-policy"
 )
 
 // +genclient
@@ -96,6 +109,62 @@ type BrokerStatus struct {
 	//SubscriptionID string `json:"subscriptionId,omitempty"`
 }
 
+// IngressPolicy authenticates and authorizes the senders of the events published to a Broker.
+// Senders present a JWT issued by one of the Issuers as a bearer token. If Rules is empty, any
+// authenticated sender may publish any event; otherwise an event is accepted only if at least
+// one rule allows it.
+type IngressPolicy struct {
+	// Issuers are the issuers whose tokens are accepted.
+	Issuers []IngressIssuer `json:"issuers"`
+
+	// Rules restrict which senders can publish which events.
+	// +optional
+	Rules []IngressRule `json:"rules,omitempty"`
+}
+
+// IngressIssuer is an issuer of the tokens accepted by a Broker's ingress.
+type IngressIssuer struct {
+	// Issuer is the value of the iss claim of the issuer's tokens.
+	Issuer string `json:"issuer"`
+
+	// JWKSURL is the HTTPS URL of the JSON Web Key Set used to verify the issuer's tokens. It is
+	// required, unless the issuer is a Kubernetes issuer, for which it must not be set.
+	// +optional
+	JWKSURL string `json:"jwksUrl,omitempty"`
+
+	// Kubernetes indicates that the issuer is the cluster's service account issuer. Its JWKS is
+	// fetched from the service account issuer discovery endpoint of the cluster's API server,
+	// with the credentials of the ingress pod.
+	// +optional
+	Kubernetes bool `json:"kubernetes,omitempty"`
+
+	// Audiences are the accepted values of the aud claim of the issuer's tokens. A token must
+	// have at least one of them.
+	Audiences []string `json:"audiences"`
+}
+
+// IngressRule allows some senders to publish some events. Each list matches if it is empty or if
+// one of its entries matches. An entry ending with "*" matches the values starting with the rest
+// of the entry.
+type IngressRule struct {
+	// Issuer restricts the rule to the senders authenticated by the issuer with this iss claim.
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// Subjects are the sub claims of the allowed senders, e.g.
+	// system:serviceaccount:<namespace>:<name> for Kubernetes service accounts.
+	// +optional
+	Subjects []string `json:"subjects,omitempty"`
+
+	// Types are the allowed event types.
+	// +optional
+	Types []string `json:"types,omitempty"`
+
+	// Sources are the allowed event sources.
+	// +optional
+	Sources []string `json:"sources,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // BrokerList is a collection of Brokers.
@@ -126,3 +195,23 @@ func (*Broker) GetConditionSet() apis.ConditionSet {
 func (b *Broker) GetStatus() *duckv1.Status {
 	return &b.Status.Status
 }
+
+// GetOrderingKeyAttribute returns the CloudEvents attribute used as the ordering key of the
+// Broker's events, or an empty string if the Broker doesn't deliver events in order.
+func (b *Broker) GetOrderingKeyAttribute() string {
+	return b.GetAnnotations()[OrderingKeyAttributeAnnotationKey]
+}
+
+// GetIngressPolicy returns the IngressPolicy specified by the IngressPolicyAnnotationKey
+// annotation. It returns nil if the annotation is not present.
+func (b *Broker) GetIngressPolicy() (*IngressPolicy, error) {
+	raw, ok := b.GetAnnotations()[IngressPolicyAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	policy := &IngressPolicy{}
+	if err := json.Unmarshal([]byte(raw), policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
@@ -18,18 +18,139 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"strings"
 
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 )
 
 // Validate verifies that the Broker is valid.
 func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
-	// We validate the GCP Broker's delivery spec. The eventing webhook will run
-	// the other usual validations.
+	// We validate the GCP Broker's delivery spec, ordering, ingress policy and BrokerCell
+	// annotations. The eventing webhook will run the other usual validations.
 	withNS := apis.AllowDifferentNamespace(apis.WithinParent(ctx, b.ObjectMeta))
-	return ValidateDeliverySpec(withNS, b.Spec.Delivery).ViaField("spec", "delivery")
+	errs := ValidateDeliverySpec(withNS, b.Spec.Delivery).ViaField("spec", "delivery").
+		Also(validateOrderingKeyAttribute(b).ViaField("metadata", "annotations")).
+		Also(validateIngressPolicyAnnotation(b).ViaField("metadata", "annotations")).
+		Also(inteventsv1alpha1.ValidateBrokerCellAnnotation(ctx, b).ViaField("metadata", "annotations"))
+	if apis.IsInUpdate(ctx) {
+		original := apis.GetBaseline(ctx).(*Broker)
+		errs = errs.Also(b.CheckImmutableFields(ctx, original))
+	}
+	return errs
+}
+
+// CheckImmutableFields checks that the ordering key attribute of the Broker is unchanged, as the
+// message ordering of its Pub/Sub subscriptions can't be changed, and that the Broker is still
+// assigned to the same BrokerCell.
+func (b *Broker) CheckImmutableFields(_ context.Context, original *Broker) *apis.FieldError {
+	if original == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if got, want := b.GetOrderingKeyAttribute(), original.GetOrderingKeyAttribute(); got != want {
+		errs = errs.Also((&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{apis.CurrentField},
+			Details: fmt.Sprintf("-%q +%q", want, got),
+		}).ViaKey(OrderingKeyAttributeAnnotationKey).ViaField("metadata", "annotations"))
+	}
+	return errs.Also(inteventsv1alpha1.CheckImmutableBrokerCellAnnotation(b, original).ViaField("metadata", "annotations"))
+}
+
+// validateOrderingKeyAttribute verifies that the OrderingKeyAttributeAnnotationKey annotation, if
+// present, is a valid CloudEvents attribute name.
+func validateOrderingKeyAttribute(b *Broker) *apis.FieldError {
+	attr, ok := b.GetAnnotations()[OrderingKeyAttributeAnnotationKey]
+	if ok && !validAttributeName.MatchString(attr) {
+		return apis.ErrInvalidValue(attr, apis.CurrentField).ViaKey(OrderingKeyAttributeAnnotationKey)
+	}
+	return nil
+}
+
+// validateIngressPolicyAnnotation verifies that the IngressPolicyAnnotationKey annotation, if
+// present, contains a valid IngressPolicy.
+func validateIngressPolicyAnnotation(b *Broker) *apis.FieldError {
+	policy, err := b.GetIngressPolicy()
+	if err != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("failed to parse ingress policy: %v", err), IngressPolicyAnnotationKey)
+	}
+	return ValidateIngressPolicy(policy).ViaKey(IngressPolicyAnnotationKey)
+}
+
+// ValidateIngressPolicy verifies that the policy has at least one issuer, that its issuers can be
+// used to verify tokens, and that its rules refer to its issuers.
+func ValidateIngressPolicy(policy *IngressPolicy) *apis.FieldError {
+	if policy == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if len(policy.Issuers) == 0 {
+		errs = errs.Also(apis.ErrMissingField("issuers"))
+	}
+	issuers := make(map[string]bool, len(policy.Issuers))
+	for i, issuer := range policy.Issuers {
+		if issuers[issuer.Issuer] {
+			errs = errs.Also(apis.ErrGeneric("duplicate issuer", "issuer").ViaFieldIndex("issuers", i))
+		}
+		issuers[issuer.Issuer] = true
+		errs = errs.Also(validateIngressIssuer(&issuer).ViaFieldIndex("issuers", i))
+	}
+	for i, rule := range policy.Rules {
+		if rule.Issuer != "" && !issuers[rule.Issuer] {
+			errs = errs.Also(apis.ErrGeneric("issuer must be one of the policy's issuers", "issuer").ViaFieldIndex("rules", i))
+		}
+		errs = errs.Also(validateIngressPatterns(rule.Subjects, "subjects").ViaFieldIndex("rules", i)).
+			Also(validateIngressPatterns(rule.Types, "types").ViaFieldIndex("rules", i)).
+			Also(validateIngressPatterns(rule.Sources, "sources").ViaFieldIndex("rules", i))
+	}
+	return errs
+}
+
+func validateIngressIssuer(issuer *IngressIssuer) *apis.FieldError {
+	var errs *apis.FieldError
+	if issuer.Issuer == "" {
+		errs = errs.Also(apis.ErrMissingField("issuer"))
+	}
+	switch {
+	case issuer.Kubernetes:
+		// The keys of the cluster's issuer are fetched with the ingress' credentials, which must
+		// only be sent to the API server.
+		if issuer.JWKSURL != "" {
+			errs = errs.Also(apis.ErrDisallowedFields("jwksUrl"))
+		}
+	case issuer.JWKSURL == "":
+		errs = errs.Also(apis.ErrMissingField("jwksUrl"))
+	default:
+		if u, err := url.Parse(issuer.JWKSURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			errs = errs.Also(apis.ErrGeneric("jwksUrl must be an absolute https URL", "jwksUrl"))
+		}
+	}
+	if len(issuer.Audiences) == 0 {
+		errs = errs.Also(apis.ErrMissingField("audiences"))
+	}
+	for i, aud := range issuer.Audiences {
+		if aud == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(aud, "audiences", i))
+		}
+	}
+	return errs
+}
+
+// validateIngressPatterns verifies that the patterns are not empty and only use "*" as a suffix.
+func validateIngressPatterns(patterns []string, field string) *apis.FieldError {
+	var errs *apis.FieldError
+	for i, pattern := range patterns {
+		if pattern == "" || strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+			errs = errs.Also(apis.ErrInvalidArrayValue(pattern, field, i))
+		}
+	}
+	return errs
 }
 
 func ValidateDeliverySpec(ctx context.Context, spec *eventingduckv1beta1.DeliverySpec) *apis.FieldError {
@@ -22,10 +22,14 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 )
 
 func TestBroker_Validate(t *testing.T) {
@@ -163,3 +167,185 @@ func TestBroker_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestBroker_ValidateOrderingKeyAttribute(t *testing.T) {
+	withOrdering := func(attr string) *Broker {
+		return &Broker{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{OrderingKeyAttributeAnnotationKey: attr},
+		}}
+	}
+	tests := []struct {
+		name     string
+		broker   *Broker
+		original *Broker
+		want     *apis.FieldError
+	}{{
+		name:   "valid attribute",
+		broker: withOrdering("partitionkey"),
+	}, {
+		name:   "invalid attribute",
+		broker: withOrdering("Partition-Key"),
+		want: apis.ErrInvalidValue("Partition-Key", apis.CurrentField).
+			ViaKey(OrderingKeyAttributeAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:     "unchanged attribute",
+		broker:   withOrdering("subject"),
+		original: withOrdering("subject"),
+	}, {
+		name:     "changed attribute",
+		broker:   withOrdering("subject"),
+		original: withOrdering("partitionkey"),
+		want: (&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{apis.CurrentField},
+			Details: `-"partitionkey" +"subject"`,
+		}).ViaKey(OrderingKeyAttributeAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:     "enabled ordering",
+		broker:   withOrdering("subject"),
+		original: &Broker{},
+		want: (&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{apis.CurrentField},
+			Details: `-"" +"subject"`,
+		}).ViaKey(OrderingKeyAttributeAnnotationKey).ViaField("metadata", "annotations"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.original != nil {
+				ctx = apis.WithinUpdate(ctx, test.original)
+			}
+			got := test.broker.Validate(ctx)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestBroker_ValidateBrokerCellAnnotation(t *testing.T) {
+	withBrokerCell := func(name string) *Broker {
+		return &Broker{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{inteventsv1alpha1.BrokerCellAnnotationKey: name},
+		}}
+	}
+	tests := []struct {
+		name     string
+		broker   *Broker
+		original *Broker
+		want     *apis.FieldError
+	}{{
+		name:   "valid brokercell",
+		broker: withBrokerCell("dedicated"),
+	}, {
+		name:   "invalid brokercell",
+		broker: withBrokerCell("Dedicated"),
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("Dedicated", apis.CurrentField)
+			fe.Details = strings.Join(validation.IsDNS1123Label("Dedicated"), "; ")
+			return fe.ViaKey(inteventsv1alpha1.BrokerCellAnnotationKey).ViaField("metadata", "annotations")
+		}(),
+	}, {
+		name:     "unchanged brokercell",
+		broker:   withBrokerCell("dedicated"),
+		original: withBrokerCell("dedicated"),
+	}, {
+		name:     "defaulted brokercell",
+		broker:   withBrokerCell(inteventsv1alpha1.DefaultBrokerCellName),
+		original: &Broker{},
+	}, {
+		name:     "changed brokercell",
+		broker:   withBrokerCell("dedicated"),
+		original: &Broker{},
+		want: (&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{apis.CurrentField},
+			Details: `-"default" +"dedicated"`,
+		}).ViaKey(inteventsv1alpha1.BrokerCellAnnotationKey).ViaField("metadata", "annotations"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.original != nil {
+				ctx = apis.WithinUpdate(ctx, test.original)
+			}
+			got := test.broker.Validate(ctx)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestBroker_ValidateIngressPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   *apis.FieldError
+	}{{
+		name:   "valid oidc issuer",
+		policy: `{"issuers":[{"issuer":"https://accounts.google.com","jwksUrl":"https://www.googleapis.com/oauth2/v3/certs","audiences":["broker"]}]}`,
+	}, {
+		name: "valid kubernetes issuer with rules",
+		policy: `{"issuers":[{"issuer":"https://kubernetes.default.svc","kubernetes":true,"audiences":["broker"]}],` +
+			`"rules":[{"issuer":"https://kubernetes.default.svc","subjects":["system:serviceaccount:team-a:*"],"types":["com.example.*"],"sources":["//team-a/*"]}]}`,
+	}, {
+		name:   "invalid json",
+		policy: `{"issuers":`,
+		want: apis.ErrInvalidValue("failed to parse ingress policy: unexpected end of JSON input", IngressPolicyAnnotationKey).
+			ViaField("metadata", "annotations"),
+	}, {
+		name:   "no issuers",
+		policy: `{"rules":[{"types":["com.example.created"]}]}`,
+		want:   apis.ErrMissingField("issuers").ViaKey(IngressPolicyAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:   "incomplete issuer",
+		policy: `{"issuers":[{"jwksUrl":"http://example.com/jwks","audiences":[""]}]}`,
+		want: apis.ErrMissingField("issuer").
+			Also(apis.ErrGeneric("jwksUrl must be an absolute https URL", "jwksUrl")).
+			Also(apis.ErrInvalidArrayValue("", "audiences", 0)).
+			ViaFieldIndex("issuers", 0).ViaKey(IngressPolicyAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:   "missing jwks url and audiences",
+		policy: `{"issuers":[{"issuer":"https://example.com"}]}`,
+		want: apis.ErrMissingField("jwksUrl").
+			Also(apis.ErrMissingField("audiences")).
+			ViaFieldIndex("issuers", 0).ViaKey(IngressPolicyAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:   "jwks url of kubernetes issuer",
+		policy: `{"issuers":[{"issuer":"https://kubernetes.default.svc","kubernetes":true,"jwksUrl":"https://example.com/jwks","audiences":["broker"]}]}`,
+		want: apis.ErrDisallowedFields("jwksUrl").
+			ViaFieldIndex("issuers", 0).ViaKey(IngressPolicyAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name: "duplicate issuer",
+		policy: `{"issuers":[{"issuer":"https://kubernetes.default.svc","kubernetes":true,"audiences":["broker"]},` +
+			`{"issuer":"https://kubernetes.default.svc","kubernetes":true,"audiences":["other"]}]}`,
+		want: apis.ErrGeneric("duplicate issuer", "issuer").
+			ViaFieldIndex("issuers", 1).ViaKey(IngressPolicyAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name: "invalid rule",
+		policy: `{"issuers":[{"issuer":"https://kubernetes.default.svc","kubernetes":true,"audiences":["broker"]}],` +
+			`"rules":[{"issuer":"https://example.com","subjects":[""],"types":["com.*.created"],"sources":["*"]}]}`,
+		want: apis.ErrGeneric("issuer must be one of the policy's issuers", "issuer").
+			Also(apis.ErrInvalidArrayValue("", "subjects", 0)).
+			Also(apis.ErrInvalidArrayValue("com.*.created", "types", 0)).
+			ViaFieldIndex("rules", 0).ViaKey(IngressPolicyAnnotationKey).ViaField("metadata", "annotations"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := Broker{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{IngressPolicyAnnotationKey: test.policy},
+				},
+			}
+			got := b.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
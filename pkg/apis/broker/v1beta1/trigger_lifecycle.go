@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
@@ -34,6 +36,20 @@ var triggerCondSet = apis.NewLivingConditionSet(
 const (
 	TriggerConditionTopic        apis.ConditionType = "TopicReady"
 	TriggerConditionSubscription apis.ConditionType = "SubscriptionReady"
+
+	// TriggerConditionCircuitBreaker is False while the circuit breaker of the Trigger's
+	// subscriber is open in the fanout. It doesn't affect the readiness of the Trigger, and is
+	// only present while the circuit isn't closed.
+	TriggerConditionCircuitBreaker apis.ConditionType = "CircuitBreakerClosed"
+
+	// TriggerConditionReplay is True once the replay subscription of the Trigger has been
+	// seeked to the starting point of its replay, and stays True with the Completed reason once
+	// the replay subscription is deleted. It doesn't affect the readiness of the Trigger, and is
+	// only present while the Trigger has a replay.
+	TriggerConditionReplay apis.ConditionType = "ReplayReady"
+
+	// replayCompletedReason is the reason of the TriggerConditionReplay of completed replays.
+	replayCompletedReason = "Completed"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -124,6 +140,55 @@ func (ts *TriggerStatus) MarkSubscriberResolvedUnknown(reason, messageFormat str
 	triggerCondSet.Manage(ts).MarkUnknown(eventingv1beta1.TriggerConditionSubscriberResolved, reason, messageFormat, messageA...)
 }
 
+// MarkCircuitBreakerOpen records that deliveries to the subscriber are short-circuited.
+func (ts *TriggerStatus) MarkCircuitBreakerOpen(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionCircuitBreaker, reason, messageFormat, messageA...)
+}
+
+// MarkCircuitBreakerClosed removes the condition set by MarkCircuitBreakerOpen.
+func (ts *TriggerStatus) MarkCircuitBreakerClosed() {
+	triggerCondSet.Manage(ts).ClearCondition(TriggerConditionCircuitBreaker)
+}
+
+func (ts *TriggerStatus) MarkReplayReady(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionReplay, reason, messageFormat, messageA...)
+}
+
+func (ts *TriggerStatus) MarkReplayFailed(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionReplay, reason, messageFormat, messageA...)
+}
+
+// MarkReplayCompleted records that the events of the replay were delivered and its subscription
+// deleted.
+func (ts *TriggerStatus) MarkReplayCompleted(messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionReplay, replayCompletedReason, messageFormat, messageA...)
+}
+
+// MarkReplayFinished removes the condition set by MarkReplayReady, MarkReplayFailed and
+// MarkReplayCompleted.
+func (ts *TriggerStatus) MarkReplayFinished() {
+	triggerCondSet.Manage(ts).ClearCondition(TriggerConditionReplay)
+}
+
+// IsReplayReady returns true if the events of the Trigger's replay can be delivered.
+func (ts *TriggerStatus) IsReplayReady() bool {
+	c := ts.GetCondition(TriggerConditionReplay)
+	return c.IsTrue() && c.Reason != replayCompletedReason
+}
+
+// IsReplayCompleted returns true if the replay of the Trigger completed.
+func (ts *TriggerStatus) IsReplayCompleted() bool {
+	c := ts.GetCondition(TriggerConditionReplay)
+	return c.IsTrue() && c.Reason == replayCompletedReason
+}
+
+// GetReplayEndTime returns the time the replay subscription of the Trigger was seeked, after
+// which the replay ends, and false if it doesn't have one.
+func (ts *TriggerStatus) GetReplayEndTime() (time.Time, bool) {
+	end, err := time.Parse(time.RFC3339Nano, ts.Annotations[ReplayEndTimeAnnotationKey])
+	return end, err == nil
+}
+
 func (ts *TriggerStatus) MarkDependencySucceeded() {
 	triggerCondSet.Manage(ts).MarkTrue(eventingv1beta1.TriggerConditionDependency)
 }
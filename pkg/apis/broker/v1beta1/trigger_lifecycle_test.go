@@ -428,3 +428,69 @@ func TestTriggerConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestTriggerCircuitBreakerCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkSubscriptionReady("")
+	ts.MarkTopicReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+
+	ts.MarkCircuitBreakerOpen("CircuitOpen", "induced open circuit")
+	if c := ts.GetCondition(TriggerConditionCircuitBreaker); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("circuit breaker condition got=%v, want False", c)
+	}
+	if !ts.IsReady() {
+		t.Error("an open circuit breaker made the Trigger not ready")
+	}
+
+	ts.MarkCircuitBreakerClosed()
+	if c := ts.GetCondition(TriggerConditionCircuitBreaker); c != nil {
+		t.Errorf("circuit breaker condition after closing got=%v, want none", c)
+	}
+	if !ts.IsReady() {
+		t.Error("Trigger not ready after closing the circuit breaker")
+	}
+}
+
+func TestTriggerReplayCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkSubscriptionReady("")
+	ts.MarkTopicReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+
+	ts.MarkReplayFailed("SeekFailed", "induced failure")
+	if ts.IsReplayReady() {
+		t.Error("failed replay is ready")
+	}
+	if !ts.IsReady() {
+		t.Error("a failed replay made the Trigger not ready")
+	}
+
+	ts.MarkReplayReady("Seeked", "induced seek")
+	if !ts.IsReplayReady() {
+		t.Error("replay not ready after MarkReplayReady")
+	}
+	if ts.IsReplayCompleted() {
+		t.Error("replay completed after MarkReplayReady")
+	}
+
+	ts.MarkReplayCompleted("induced completion")
+	if ts.IsReplayReady() {
+		t.Error("completed replay is ready")
+	}
+	if !ts.IsReplayCompleted() {
+		t.Error("replay not completed after MarkReplayCompleted")
+	}
+
+	ts.MarkReplayFinished()
+	if c := ts.GetCondition(TriggerConditionReplay); c != nil {
+		t.Errorf("replay condition after finishing got=%v, want none", c)
+	}
+	if !ts.IsReady() {
+		t.Error("Trigger not ready after finishing its replay")
+	}
+}
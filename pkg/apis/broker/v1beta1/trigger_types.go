@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -33,6 +35,34 @@ const (
 	// InjectionAnnotation is the annotation key used to enable knative eventing injection for a namespace and automatically create a default broker.
 	// This will be used when the client creates a trigger paired with default broker and the default broker doesn't exist in the namespace
 	InjectionAnnotation = "knative-eventing-injection"
+
+	// FiltersAnnotationKey is the annotation key used to specify a list of SubscriptionsAPIFilters
+	// encoded as JSON. When the annotation is present, all filters must match for an event to be
+	// delivered, and spec.filter is ignored.
+	FiltersAnnotationKey = "events.cloud.google.com/filters"
+
+	// TransformAnnotationKey is the annotation key used to specify an EventTransform encoded as
+	// JSON. The transform is applied to the events that pass the Trigger's filters before they are
+	// delivered to the subscriber.
+	TransformAnnotationKey = "events.cloud.google.com/transform"
+
+	// BatchingAnnotationKey is the annotation key used to specify EventBatching encoded as JSON.
+	// When the annotation is present, events are delivered to the subscriber in batches.
+	BatchingAnnotationKey = "events.cloud.google.com/batching"
+
+	// RateLimitAnnotationKey is the annotation key used to specify an EventRateLimit encoded as
+	// JSON. When the annotation is present, the deliveries to the subscriber are throttled.
+	RateLimitAnnotationKey = "events.cloud.google.com/rate-limit"
+
+	// ReplayAnnotationKey is the annotation key used to specify an EventReplay encoded as JSON.
+	// While the annotation is present, the events published to the Broker since the replay's
+	// starting point are redelivered to the Trigger. Changing the annotation starts a new replay.
+	ReplayAnnotationKey = "events.cloud.google.com/replay"
+
+	// ReplayEndTimeAnnotationKey is the key of the status annotation recording when the replay
+	// subscription was seeked, in RFC 3339 format. The events published since then are delivered
+	// by the fanout, so the replay ends there.
+	ReplayEndTimeAnnotationKey = "events.cloud.google.com/replay-end-time"
 )
 
 // +genclient
@@ -94,6 +124,127 @@ type TriggerStatus struct {
 	//SubscriptionID string `json:"subscriptionId,omitempty"`
 }
 
+// SubscriptionsAPIFilter allows defining a filter expression using the filter dialects of the
+// CloudEvents Subscriptions API. Exactly one dialect must be set on each filter.
+type SubscriptionsAPIFilter struct {
+	// All evaluates to true if all the nested expressions evaluate to true.
+	// +optional
+	All []SubscriptionsAPIFilter `json:"all,omitempty"`
+
+	// Any evaluates to true if at least one of the nested expressions evaluates to true.
+	// +optional
+	Any []SubscriptionsAPIFilter `json:"any,omitempty"`
+
+	// Not evaluates to true if the nested expression evaluates to false.
+	// +optional
+	Not *SubscriptionsAPIFilter `json:"not,omitempty"`
+
+	// Exact evaluates to true if the values of the matching CloudEvents attributes are all
+	// exactly equal to the specified values.
+	// +optional
+	Exact map[string]string `json:"exact,omitempty"`
+
+	// Prefix evaluates to true if the values of the matching CloudEvents attributes all start
+	// with the specified values.
+	// +optional
+	Prefix map[string]string `json:"prefix,omitempty"`
+
+	// Suffix evaluates to true if the values of the matching CloudEvents attributes all end
+	// with the specified values.
+	// +optional
+	Suffix map[string]string `json:"suffix,omitempty"`
+
+	// SQL is a CloudEvents SQL expression that will be evaluated to true or false against each
+	// CloudEvent.
+	// +optional
+	SQL string `json:"cesql,omitempty"`
+}
+
+// EventTransform rewrites the attributes and data of an event before it is delivered to the
+// subscriber.
+type EventTransform struct {
+	// SetAttributes sets the given CloudEvents attributes, including extensions, to static values.
+	// This can be used to rename the type of events.
+	// +optional
+	SetAttributes map[string]string `json:"setAttributes,omitempty"`
+
+	// RemoveAttributes removes the given extensions. They are removed before SetAttributes is
+	// applied.
+	// +optional
+	RemoveAttributes []string `json:"removeAttributes,omitempty"`
+
+	// DataPatch is a JSON patch (RFC 6902) applied to the data of events with JSON data.
+	// +optional
+	DataPatch []JSONPatchOperation `json:"dataPatch,omitempty"`
+}
+
+// JSONPatchOperation is a single operation of a JSON patch (RFC 6902).
+type JSONPatchOperation struct {
+	// Op is the operation to perform: add, remove, replace, move, copy or test.
+	Op string `json:"op"`
+
+	// Path is the JSON pointer to the location the operation is performed at.
+	Path string `json:"path"`
+
+	// From is the JSON pointer to the location to move or copy the value from.
+	// +optional
+	From string `json:"from,omitempty"`
+
+	// Value is the value to add, replace or test.
+	// +optional
+	Value *runtime.RawExtension `json:"value,omitempty"`
+}
+
+// EventBatching configures the delivery of events to the subscriber in batches, using the
+// CloudEvents HTTP batched content mode. A batch is sent as soon as any of its limits is reached.
+type EventBatching struct {
+	// MaxCount is the maximum number of events in a batch. Defaults to 100.
+	// +optional
+	MaxCount int32 `json:"maxCount,omitempty"`
+
+	// MaxBytes is the maximum size in bytes of the encoded events of a batch. Events larger than
+	// MaxBytes are sent in a batch of their own. Defaults to 1MiB.
+	// +optional
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+
+	// Linger is the maximum time an event waits for its batch to fill up, as an ISO-8601
+	// duration. Defaults to 100 milliseconds.
+	// +optional
+	Linger string `json:"linger,omitempty"`
+}
+
+// EventRateLimit throttles the requests sent to the subscriber. The limits are enforced by each
+// fanout and retry pod separately.
+type EventRateLimit struct {
+	// RequestsPerSecond is the maximum rate of requests sent to the subscriber.
+	// +optional
+	RequestsPerSecond int32 `json:"requestsPerSecond,omitempty"`
+
+	// Burst is the maximum number of requests sent at once when the rate allows it. Defaults to
+	// RequestsPerSecond.
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+
+	// MaxInFlight is the maximum number of concurrent requests sent to the subscriber.
+	// +optional
+	MaxInFlight int32 `json:"maxInFlight,omitempty"`
+}
+
+// EventReplay redelivers the events published to the Broker from a starting point, through the
+// Trigger's filters. Exactly one of Time and Snapshot must be set.
+type EventReplay struct {
+	// Time is the RFC 3339 time since which events are redelivered. Events older than the
+	// message retention of the Broker's decoupling subscription can't be redelivered.
+	// +optional
+	Time string `json:"time,omitempty"`
+
+	// Snapshot is the ID of a Pub/Sub snapshot of the Broker's decoupling subscription, in the
+	// project of the Broker. The events that were unacknowledged when the snapshot was created,
+	// and the ones published since, are redelivered.
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // TriggerList is a collection of Triggers.
@@ -123,3 +274,73 @@ func (*Trigger) GetConditionSet() apis.ConditionSet {
 func (t *Trigger) GetStatus() *duckv1.Status {
 	return &t.Status.Status
 }
+
+// GetFilters returns the SubscriptionsAPIFilters specified by the FiltersAnnotationKey annotation.
+// It returns nil if the annotation is not set.
+func (t *Trigger) GetFilters() ([]SubscriptionsAPIFilter, error) {
+	raw, ok := t.GetAnnotations()[FiltersAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	var filters []SubscriptionsAPIFilter
+	if err := json.Unmarshal([]byte(raw), &filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// GetTransform returns the EventTransform specified by the TransformAnnotationKey annotation. It
+// returns nil if the annotation is not set.
+func (t *Trigger) GetTransform() (*EventTransform, error) {
+	raw, ok := t.GetAnnotations()[TransformAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	transform := &EventTransform{}
+	if err := json.Unmarshal([]byte(raw), transform); err != nil {
+		return nil, err
+	}
+	return transform, nil
+}
+
+// GetBatching returns the EventBatching specified by the BatchingAnnotationKey annotation. It
+// returns nil if the annotation is not set.
+func (t *Trigger) GetBatching() (*EventBatching, error) {
+	raw, ok := t.GetAnnotations()[BatchingAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	batching := &EventBatching{}
+	if err := json.Unmarshal([]byte(raw), batching); err != nil {
+		return nil, err
+	}
+	return batching, nil
+}
+
+// GetRateLimit returns the EventRateLimit specified by the RateLimitAnnotationKey annotation. It
+// returns nil if the annotation is not set.
+func (t *Trigger) GetRateLimit() (*EventRateLimit, error) {
+	raw, ok := t.GetAnnotations()[RateLimitAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	rateLimit := &EventRateLimit{}
+	if err := json.Unmarshal([]byte(raw), rateLimit); err != nil {
+		return nil, err
+	}
+	return rateLimit, nil
+}
+
+// GetReplay returns the EventReplay specified by the ReplayAnnotationKey annotation. It returns
+// nil if the annotation is not present.
+func (t *Trigger) GetReplay() (*EventReplay, error) {
+	raw, ok := t.GetAnnotations()[ReplayAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	replay := &EventReplay{}
+	if err := json.Unmarshal([]byte(raw), replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
@@ -18,13 +18,318 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/rickb777/date/period"
+	"k8s.io/apimachinery/pkg/util/sets"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/utils/cesql"
 )
 
-// Validate the Trigger.
+// Only allow lowercase alphanumerics in CloudEvents attribute names.
+// See https://github.com/cloudevents/spec/blob/v1.0/spec.md#attribute-naming-convention
+var validAttributeName = regexp.MustCompile(`^[a-z0-9]+$`)
+
+var (
+	// contextAttributes are the CloudEvents context attributes that are not extensions.
+	contextAttributes = sets.NewString("id", "source", "specversion", "type", "datacontenttype", "dataschema", "subject", "time")
+	// settableContextAttributes are the context attributes an EventTransform may set.
+	settableContextAttributes = sets.NewString("source", "type", "dataschema", "subject")
+	// jsonPatchOps are the operations of a JSON patch.
+	jsonPatchOps = sets.NewString("add", "remove", "replace", "move", "copy", "test")
+)
+
+// internalExtensionPrefix is the prefix of the extensions the broker uses internally.
+const internalExtensionPrefix = "kgcp"
+
+// Upper bounds of EventBatching, which keep batches within the limits of a single request and the
+// latency of its events reasonable.
+const (
+	maxBatchCount  = 1000
+	maxBatchBytes  = 10 * 1024 * 1024
+	maxBatchLinger = 10 * time.Second
+)
+
+// Validate verifies that the Trigger is valid.
 func (t *Trigger) Validate(ctx context.Context) *apis.FieldError {
-	// The Google Cloud Broker doesn't have any custom validations. The
-	// eventing webhook will run the usual validations.
+	// We validate the GCP Trigger's filters and transform annotations and delivery spec. The
+	// eventing webhook will run the other usual validations.
+	withNS := apis.AllowDifferentNamespace(apis.WithinParent(ctx, t.ObjectMeta))
+	return validateFiltersAnnotation(t).Also(validateTransformAnnotation(t), validateBatchingAnnotation(t), validateRateLimitAnnotation(t), validateReplayAnnotation(t)).ViaField("metadata", "annotations").
+		Also(ValidateTriggerDeliverySpec(withNS, t.Spec.Delivery).ViaField("spec", "delivery"))
+}
+
+// ValidateTriggerDeliverySpec verifies the Trigger's own delivery spec. Unlike the Broker, a
+// Trigger's dead letter sink may be any addressable, in addition to a pubsub:// topic.
+func ValidateTriggerDeliverySpec(ctx context.Context, spec *eventingduckv1.DeliverySpec) *apis.FieldError {
+	if spec == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if spec.Retry != nil {
+		if spec.DeadLetterSink == nil {
+			errs = errs.Also(apis.ErrGeneric("need DeadLetterSink when retry is defined", "deadLetterSink"))
+		}
+		if *spec.Retry < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(*spec.Retry, "retry"))
+		}
+	}
+	if sink := spec.DeadLetterSink; sink != nil {
+		if sink.URI != nil && sink.URI.Scheme == "pubsub" {
+			errs = errs.Also(ValidateDeadLetterSink(ctx, sink).ViaField("deadLetterSink"))
+		} else {
+			errs = errs.Also(sink.Validate(ctx).ViaField("deadLetterSink"))
+		}
+	}
+	return errs
+}
+
+// validateFiltersAnnotation verifies that the FiltersAnnotationKey annotation, if present, contains
+// a valid list of SubscriptionsAPIFilters.
+func validateFiltersAnnotation(t *Trigger) *apis.FieldError {
+	filters, err := t.GetFilters()
+	if err != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("failed to parse filters: %v", err), FiltersAnnotationKey)
+	}
+	var errs *apis.FieldError
+	for i, f := range filters {
+		errs = errs.Also(ValidateSubscriptionsAPIFilter(&f).ViaIndex(i))
+	}
+	return errs.ViaKey(FiltersAnnotationKey)
+}
+
+// ValidateSubscriptionsAPIFilter verifies that exactly one dialect is set on the filter and that
+// the dialect, including nested filters, is valid.
+func ValidateSubscriptionsAPIFilter(f *SubscriptionsAPIFilter) *apis.FieldError {
+	if f == nil {
+		return nil
+	}
+	var set []string
+	var errs *apis.FieldError
+	if f.Exact != nil {
+		set = append(set, "exact")
+		errs = errs.Also(validateAttributesMap(f.Exact, false).ViaField("exact"))
+	}
+	if f.Prefix != nil {
+		set = append(set, "prefix")
+		errs = errs.Also(validateAttributesMap(f.Prefix, true).ViaField("prefix"))
+	}
+	if f.Suffix != nil {
+		set = append(set, "suffix")
+		errs = errs.Also(validateAttributesMap(f.Suffix, true).ViaField("suffix"))
+	}
+	if f.All != nil {
+		set = append(set, "all")
+		for i, nested := range f.All {
+			errs = errs.Also(ValidateSubscriptionsAPIFilter(&nested).ViaFieldIndex("all", i))
+		}
+	}
+	if f.Any != nil {
+		set = append(set, "any")
+		for i, nested := range f.Any {
+			errs = errs.Also(ValidateSubscriptionsAPIFilter(&nested).ViaFieldIndex("any", i))
+		}
+	}
+	if f.Not != nil {
+		set = append(set, "not")
+		errs = errs.Also(ValidateSubscriptionsAPIFilter(f.Not).ViaField("not"))
+	}
+	if f.SQL != "" {
+		set = append(set, "cesql")
+		if _, err := cesql.Parse(f.SQL); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid CESQL expression: %v", err), "cesql"))
+		}
+	}
+	switch len(set) {
+	case 0:
+		errs = errs.Also(apis.ErrMissingOneOf("exact", "prefix", "suffix", "all", "any", "not", "cesql"))
+	case 1:
+	default:
+		errs = errs.Also(apis.ErrMultipleOneOf(set...))
+	}
+	return errs
+}
+
+// validateTransformAnnotation verifies that the TransformAnnotationKey annotation, if present,
+// contains a valid EventTransform.
+func validateTransformAnnotation(t *Trigger) *apis.FieldError {
+	transform, err := t.GetTransform()
+	if err != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("failed to parse transform: %v", err), TransformAnnotationKey)
+	}
+	return ValidateEventTransform(transform).ViaKey(TransformAnnotationKey)
+}
+
+// ValidateEventTransform verifies that the transform only sets valid attributes, only removes
+// extensions and has a well formed data patch. The broker's internal extensions can't be
+// changed.
+func ValidateEventTransform(transform *EventTransform) *apis.FieldError {
+	if transform == nil {
+		return nil
+	}
+	if len(transform.SetAttributes) == 0 && len(transform.RemoveAttributes) == 0 && len(transform.DataPatch) == 0 {
+		return apis.ErrMissingOneOf("setAttributes", "removeAttributes", "dataPatch")
+	}
+	var errs *apis.FieldError
+	for attr, value := range transform.SetAttributes {
+		switch {
+		case !validAttributeName.MatchString(attr):
+			errs = errs.Also(apis.ErrInvalidKeyName(attr, "setAttributes", "attribute name must be a non-empty string of lowercase alphanumeric characters"))
+		case contextAttributes.Has(attr) && !settableContextAttributes.Has(attr),
+			strings.HasPrefix(attr, internalExtensionPrefix):
+			errs = errs.Also(apis.ErrInvalidKeyName(attr, "setAttributes", "attribute can't be set"))
+		case (attr == "type" || attr == "source") && value == "":
+			errs = errs.Also(apis.ErrInvalidValue(value, apis.CurrentField).ViaKey(attr).ViaField("setAttributes"))
+		}
+	}
+	for i, attr := range transform.RemoveAttributes {
+		switch {
+		case !validAttributeName.MatchString(attr):
+			errs = errs.Also(apis.ErrInvalidArrayValue(attr, "removeAttributes", i))
+		case contextAttributes.Has(attr), strings.HasPrefix(attr, internalExtensionPrefix):
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("only extensions can be removed, got %q", attr)).ViaFieldIndex("removeAttributes", i))
+		}
+	}
+	for i, op := range transform.DataPatch {
+		errs = errs.Also(validateJSONPatchOperation(&op).ViaFieldIndex("dataPatch", i))
+	}
+	return errs
+}
+
+func validateJSONPatchOperation(op *JSONPatchOperation) *apis.FieldError {
+	var errs *apis.FieldError
+	if !jsonPatchOps.Has(op.Op) {
+		errs = errs.Also(apis.ErrInvalidValue(op.Op, "op"))
+	}
+	if op.Path != "" && !strings.HasPrefix(op.Path, "/") {
+		errs = errs.Also(apis.ErrInvalidValue(op.Path, "path"))
+	}
+	switch op.Op {
+	case "move", "copy":
+		if op.From == "" {
+			errs = errs.Also(apis.ErrMissingField("from"))
+		}
+	case "add", "replace", "test":
+		if op.Value == nil {
+			errs = errs.Also(apis.ErrMissingField("value"))
+		}
+	}
+	return errs
+}
+
+// validateBatchingAnnotation verifies that the BatchingAnnotationKey annotation, if present,
+// contains valid EventBatching.
+func validateBatchingAnnotation(t *Trigger) *apis.FieldError {
+	batching, err := t.GetBatching()
+	if err != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("failed to parse batching: %v", err), BatchingAnnotationKey)
+	}
+	return ValidateEventBatching(batching).ViaKey(BatchingAnnotationKey)
+}
+
+// ValidateEventBatching verifies that the limits of the batching are within bounds. Unset limits
+// use their defaults.
+func ValidateEventBatching(batching *EventBatching) *apis.FieldError {
+	if batching == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if batching.MaxCount < 0 || batching.MaxCount > maxBatchCount {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(batching.MaxCount, 0, maxBatchCount, "maxCount"))
+	}
+	if batching.MaxBytes < 0 || batching.MaxBytes > maxBatchBytes {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(batching.MaxBytes, 0, maxBatchBytes, "maxBytes"))
+	}
+	if batching.Linger != "" {
+		p, err := period.Parse(batching.Linger)
+		if err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(batching.Linger, "linger"))
+		} else if d, _ := p.Duration(); d < 0 || d > maxBatchLinger {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(batching.Linger, "PT0S", "PT10S", "linger"))
+		}
+	}
+	return errs
+}
+
+// validateRateLimitAnnotation verifies that the RateLimitAnnotationKey annotation, if present,
+// contains a valid EventRateLimit.
+func validateRateLimitAnnotation(t *Trigger) *apis.FieldError {
+	rateLimit, err := t.GetRateLimit()
+	if err != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("failed to parse rate limit: %v", err), RateLimitAnnotationKey)
+	}
+	return ValidateEventRateLimit(rateLimit).ViaKey(RateLimitAnnotationKey)
+}
+
+// ValidateEventRateLimit verifies that the rate limit sets at least one limit and that its
+// limits are positive.
+func ValidateEventRateLimit(rateLimit *EventRateLimit) *apis.FieldError {
+	if rateLimit == nil {
+		return nil
+	}
+	if rateLimit.RequestsPerSecond == 0 && rateLimit.MaxInFlight == 0 {
+		return apis.ErrMissingOneOf("requestsPerSecond", "maxInFlight")
+	}
+	var errs *apis.FieldError
+	if rateLimit.RequestsPerSecond < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(rateLimit.RequestsPerSecond, "requestsPerSecond"))
+	}
+	if rateLimit.Burst < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(rateLimit.Burst, "burst"))
+	} else if rateLimit.Burst > 0 && rateLimit.RequestsPerSecond == 0 {
+		errs = errs.Also(apis.ErrGeneric("burst requires requestsPerSecond", "burst"))
+	}
+	if rateLimit.MaxInFlight < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(rateLimit.MaxInFlight, "maxInFlight"))
+	}
+	return errs
+}
+
+// validateReplayAnnotation verifies that the ReplayAnnotationKey annotation, if present, contains
+// a valid EventReplay.
+func validateReplayAnnotation(t *Trigger) *apis.FieldError {
+	replay, err := t.GetReplay()
+	if err != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("failed to parse replay: %v", err), ReplayAnnotationKey)
+	}
+	return ValidateEventReplay(replay).ViaKey(ReplayAnnotationKey)
+}
+
+// ValidateEventReplay verifies that the replay starts from either a valid time or a snapshot.
+func ValidateEventReplay(replay *EventReplay) *apis.FieldError {
+	if replay == nil {
+		return nil
+	}
+	switch {
+	case replay.Time == "" && replay.Snapshot == "":
+		return apis.ErrMissingOneOf("time", "snapshot")
+	case replay.Time != "" && replay.Snapshot != "":
+		return apis.ErrMultipleOneOf("time", "snapshot")
+	case replay.Time != "":
+		if _, err := time.Parse(time.RFC3339, replay.Time); err != nil {
+			return apis.ErrInvalidValue(replay.Time, "time")
+		}
+	}
 	return nil
 }
+
+func validateAttributesMap(attrs map[string]string, requireValue bool) *apis.FieldError {
+	if len(attrs) == 0 {
+		return apis.ErrGeneric("at least one attribute must be specified")
+	}
+	var errs *apis.FieldError
+	for attr, value := range attrs {
+		if !validAttributeName.MatchString(attr) {
+			errs = errs.Also(apis.ErrInvalidKeyName(attr, apis.CurrentField, "attribute name must be a non-empty string of lowercase alphanumeric characters"))
+		}
+		if requireValue && value == "" {
+			errs = errs.Also(apis.ErrInvalidValue(value, apis.CurrentField).ViaKey(attr))
+		}
+	}
+	return errs
+}
@@ -19,6 +19,12 @@ package v1beta1
 import (
 	"context"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 func TestTrigger_Validate(t *testing.T) {
@@ -27,3 +33,364 @@ func TestTrigger_Validate(t *testing.T) {
 		t.Errorf("expected nil, got %v", err)
 	}
 }
+
+func TestTrigger_ValidateFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters string
+		want    *apis.FieldError
+	}{{
+		name:    "valid filters",
+		filters: `[{"exact":{"type":"foo"}},{"prefix":{"type":"com.example."}},{"suffix":{"source":"/bucket"}}]`,
+	}, {
+		name:    "valid nested filters",
+		filters: `[{"all":[{"any":[{"exact":{"type":"a"}},{"not":{"cesql":"subject LIKE 'b%'"}}]}]}]`,
+	}, {
+		name:    "empty filter list",
+		filters: `[]`,
+	}, {
+		name:    "invalid json",
+		filters: `{"exact":`,
+		want: apis.ErrInvalidValue("failed to parse filters: unexpected end of JSON input", FiltersAnnotationKey).
+			ViaField("metadata", "annotations"),
+	}, {
+		name:    "no dialect",
+		filters: `[{}]`,
+		want: apis.ErrMissingOneOf("exact", "prefix", "suffix", "all", "any", "not", "cesql").
+			ViaIndex(0).ViaKey(FiltersAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:    "multiple dialects",
+		filters: `[{"exact":{"type":"a"},"prefix":{"type":"b"}}]`,
+		want: apis.ErrMultipleOneOf("exact", "prefix").
+			ViaIndex(0).ViaKey(FiltersAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:    "empty exact",
+		filters: `[{"exact":{}}]`,
+		want: apis.ErrGeneric("at least one attribute must be specified").ViaField("exact").
+			ViaIndex(0).ViaKey(FiltersAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:    "invalid attribute name",
+		filters: `[{"exact":{"Type":"a"}}]`,
+		want: apis.ErrInvalidKeyName("Type", apis.CurrentField, "attribute name must be a non-empty string of lowercase alphanumeric characters").ViaField("exact").
+			ViaIndex(0).ViaKey(FiltersAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:    "empty prefix value",
+		filters: `[{"prefix":{"type":""}}]`,
+		want: apis.ErrInvalidValue("", apis.CurrentField).ViaKey("type").ViaField("prefix").
+			ViaIndex(0).ViaKey(FiltersAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:    "invalid nested filter",
+		filters: `[{"any":[{"exact":{"type":"a"}},{"not":{}}]}]`,
+		want: apis.ErrMissingOneOf("exact", "prefix", "suffix", "all", "any", "not", "cesql").
+			ViaField("not").ViaFieldIndex("any", 1).
+			ViaIndex(0).ViaKey(FiltersAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:    "invalid cesql",
+		filters: `[{"cesql":"type ="}]`,
+		want: apis.ErrInvalidValue("invalid CESQL expression: unexpected end of expression at position 6", "cesql").
+			ViaIndex(0).ViaKey(FiltersAnnotationKey).ViaField("metadata", "annotations"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{FiltersAnnotationKey: test.filters},
+				},
+			}
+			got := trig.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestTrigger_ValidateDelivery(t *testing.T) {
+	retry := int32(3)
+	negativeRetry := int32(-1)
+	tests := []struct {
+		name     string
+		delivery *eventingduckv1.DeliverySpec
+		want     *apis.FieldError
+	}{{
+		name: "no delivery",
+	}, {
+		name: "pubsub dead letter sink",
+		delivery: &eventingduckv1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "topic"}},
+			Retry:          &retry,
+		},
+	}, {
+		name: "http dead letter sink",
+		delivery: &eventingduckv1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "dls.example.com"}},
+			Retry:          &retry,
+		},
+	}, {
+		name: "ref dead letter sink",
+		delivery: &eventingduckv1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{Ref: &duckv1.KReference{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Name:       "dls",
+				Namespace:  "ns",
+			}},
+		},
+	}, {
+		name: "empty pubsub topic",
+		delivery: &eventingduckv1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub"}},
+		},
+		want: apis.ErrInvalidValue("Dead letter topic must not be empty", "uri").
+			ViaField("deadLetterSink").ViaField("spec", "delivery"),
+	}, {
+		name: "empty dead letter sink",
+		delivery: &eventingduckv1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{},
+		},
+		want: apis.ErrGeneric("expected at least one, got none", "ref", "uri").
+			ViaField("deadLetterSink").ViaField("spec", "delivery"),
+	}, {
+		name: "retry without dead letter sink",
+		delivery: &eventingduckv1.DeliverySpec{
+			Retry: &retry,
+		},
+		want: apis.ErrGeneric("need DeadLetterSink when retry is defined", "deadLetterSink").
+			ViaField("spec", "delivery"),
+	}, {
+		name: "negative retry",
+		delivery: &eventingduckv1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "dls.example.com"}},
+			Retry:          &negativeRetry,
+		},
+		want: apis.ErrInvalidValue(negativeRetry, "retry").ViaField("spec", "delivery"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns"},
+			}
+			trig.Spec.Delivery = test.delivery
+			got := trig.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestTrigger_ValidateTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform string
+		want      *apis.FieldError
+	}{{
+		name:      "valid transform",
+		transform: `{"setAttributes":{"type":"com.example.new","region":"us"},"removeAttributes":["traceparent"],"dataPatch":[{"op":"add","path":"/foo","value":{"bar":1}},{"op":"remove","path":"/baz"},{"op":"move","from":"/a","path":"/b"}]}`,
+	}, {
+		name:      "invalid json",
+		transform: `{"setAttributes":`,
+		want: apis.ErrInvalidValue("failed to parse transform: unexpected end of JSON input", TransformAnnotationKey).
+			ViaField("metadata", "annotations"),
+	}, {
+		name:      "empty transform",
+		transform: `{}`,
+		want: apis.ErrMissingOneOf("setAttributes", "removeAttributes", "dataPatch").
+			ViaKey(TransformAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "invalid attribute name",
+		transform: `{"setAttributes":{"Type":"a"}}`,
+		want: apis.ErrInvalidKeyName("Type", "setAttributes", "attribute name must be a non-empty string of lowercase alphanumeric characters").
+			ViaKey(TransformAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "set immutable attribute",
+		transform: `{"setAttributes":{"id":"a"}}`,
+		want: apis.ErrInvalidKeyName("id", "setAttributes", "attribute can't be set").
+			ViaKey(TransformAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "set internal extension",
+		transform: `{"setAttributes":{"kgcphops":"1"}}`,
+		want: apis.ErrInvalidKeyName("kgcphops", "setAttributes", "attribute can't be set").
+			ViaKey(TransformAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "empty type",
+		transform: `{"setAttributes":{"type":""}}`,
+		want: apis.ErrInvalidValue("", apis.CurrentField).ViaKey("type").ViaField("setAttributes").
+			ViaKey(TransformAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "remove context attribute",
+		transform: `{"removeAttributes":["subject"]}`,
+		want: apis.ErrGeneric(`only extensions can be removed, got "subject"`).ViaFieldIndex("removeAttributes", 0).
+			ViaKey(TransformAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "invalid patch operation",
+		transform: `{"dataPatch":[{"op":"merge","path":"foo"}]}`,
+		want: apis.ErrInvalidValue("merge", "op").Also(apis.ErrInvalidValue("foo", "path")).
+			ViaFieldIndex("dataPatch", 0).ViaKey(TransformAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "patch operations missing fields",
+		transform: `{"dataPatch":[{"op":"copy","path":"/a"},{"op":"replace","path":"/b"}]}`,
+		want: apis.ErrMissingField("from").ViaFieldIndex("dataPatch", 0).
+			Also(apis.ErrMissingField("value").ViaFieldIndex("dataPatch", 1)).
+			ViaKey(TransformAnnotationKey).ViaField("metadata", "annotations"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{TransformAnnotationKey: test.transform},
+				},
+			}
+			got := trig.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestTrigger_ValidateBatching(t *testing.T) {
+	tests := []struct {
+		name     string
+		batching string
+		want     *apis.FieldError
+	}{{
+		name:     "valid batching",
+		batching: `{"maxCount":500,"maxBytes":2097152,"linger":"PT0.5S"}`,
+	}, {
+		name:     "defaults",
+		batching: `{}`,
+	}, {
+		name:     "invalid json",
+		batching: `{"maxCount":`,
+		want: apis.ErrInvalidValue("failed to parse batching: unexpected end of JSON input", BatchingAnnotationKey).
+			ViaField("metadata", "annotations"),
+	}, {
+		name:     "limits out of bounds",
+		batching: `{"maxCount":1001,"maxBytes":-1,"linger":"PT1M"}`,
+		want: apis.ErrOutOfBoundsValue(1001, 0, maxBatchCount, "maxCount").
+			Also(apis.ErrOutOfBoundsValue(-1, 0, maxBatchBytes, "maxBytes")).
+			Also(apis.ErrOutOfBoundsValue("PT1M", "PT0S", "PT10S", "linger")).
+			ViaKey(BatchingAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:     "invalid linger",
+		batching: `{"linger":"100ms"}`,
+		want: apis.ErrInvalidValue("100ms", "linger").
+			ViaKey(BatchingAnnotationKey).ViaField("metadata", "annotations"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{BatchingAnnotationKey: test.batching},
+				},
+			}
+			got := trig.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestTrigger_ValidateRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit string
+		want      *apis.FieldError
+	}{{
+		name:      "valid rate limit",
+		rateLimit: `{"requestsPerSecond":10,"burst":20,"maxInFlight":5}`,
+	}, {
+		name:      "only max in flight",
+		rateLimit: `{"maxInFlight":1}`,
+	}, {
+		name:      "invalid json",
+		rateLimit: `{"maxInFlight":`,
+		want: apis.ErrInvalidValue("failed to parse rate limit: unexpected end of JSON input", RateLimitAnnotationKey).
+			ViaField("metadata", "annotations"),
+	}, {
+		name:      "no limit",
+		rateLimit: `{"burst":10}`,
+		want: apis.ErrMissingOneOf("requestsPerSecond", "maxInFlight").
+			ViaKey(RateLimitAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "negative limits",
+		rateLimit: `{"requestsPerSecond":-1,"burst":-1,"maxInFlight":-1}`,
+		want: apis.ErrInvalidValue(-1, "requestsPerSecond").
+			Also(apis.ErrInvalidValue(-1, "burst")).
+			Also(apis.ErrInvalidValue(-1, "maxInFlight")).
+			ViaKey(RateLimitAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:      "burst without rate",
+		rateLimit: `{"burst":10,"maxInFlight":1}`,
+		want: apis.ErrGeneric("burst requires requestsPerSecond", "burst").
+			ViaKey(RateLimitAnnotationKey).ViaField("metadata", "annotations"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{RateLimitAnnotationKey: test.rateLimit},
+				},
+			}
+			got := trig.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestTrigger_ValidateReplay(t *testing.T) {
+	tests := []struct {
+		name   string
+		replay string
+		want   *apis.FieldError
+	}{{
+		name:   "valid time",
+		replay: `{"time":"2021-03-04T05:06:07Z"}`,
+	}, {
+		name:   "valid snapshot",
+		replay: `{"snapshot":"before-bug"}`,
+	}, {
+		name:   "invalid json",
+		replay: `{"time":`,
+		want: apis.ErrInvalidValue("failed to parse replay: unexpected end of JSON input", ReplayAnnotationKey).
+			ViaField("metadata", "annotations"),
+	}, {
+		name:   "no starting point",
+		replay: `{}`,
+		want: apis.ErrMissingOneOf("time", "snapshot").
+			ViaKey(ReplayAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:   "time and snapshot",
+		replay: `{"time":"2021-03-04T05:06:07Z","snapshot":"before-bug"}`,
+		want: apis.ErrMultipleOneOf("time", "snapshot").
+			ViaKey(ReplayAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name:   "invalid time",
+		replay: `{"time":"yesterday"}`,
+		want: apis.ErrInvalidValue("yesterday", "time").
+			ViaKey(ReplayAnnotationKey).ViaField("metadata", "annotations"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ReplayAnnotationKey: test.replay},
+				},
+			}
+			got := trig.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
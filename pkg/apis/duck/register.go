@@ -47,6 +47,15 @@ const (
 	// Pub/Sub subscription that Keda uses in order to decide when and by how much to scale out.
 	KedaAutoscalingSubscriptionSizeAnnotation = KEDA + "/subscriptionSize"
 
+	// DeliveryModeAnnotation is the annotation for the mode in which the Pub/Sub messages of a
+	// particular resource are delivered.
+	DeliveryModeAnnotation = "events.cloud.google.com/delivery-mode"
+	// DeliveryModePull is the default delivery mode, in which a receive adapter pulls the messages.
+	DeliveryModePull = "pull"
+	// DeliveryModePush is the delivery mode in which Pub/Sub pushes the messages to the shared push
+	// receiver.
+	DeliveryModePush = "push"
+
 	// defaultMinScale is the default minimum set of Pods the scaler should
 	// downscale the resource to.
 	defaultMinScale = "0"
@@ -0,0 +1,32 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// BudgetFilter selects the Cloud Billing budget notifications that are delivered.
+type BudgetFilter struct {
+	// ThresholdPercent is the minimum alert threshold, in percent of the budget amount, of the
+	// notifications that are delivered, e.g. 90. Only the notifications of the cost crossing an
+	// alert threshold of at least ThresholdPercent percent are delivered, once per threshold and
+	// cost interval. All the notifications are delivered when it is zero.
+	// +optional
+	ThresholdPercent int32 `json:"thresholdPercent,omitempty"`
+}
+
+// IsEmpty returns whether the BudgetFilter selects all the notifications.
+func (f *BudgetFilter) IsEmpty() bool {
+	return f.ThresholdPercent == 0
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+)
+
+func TestBudgetFilterValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter BudgetFilter
+		want   *apis.FieldError
+	}{{
+		name:   "empty",
+		filter: BudgetFilter{},
+	}, {
+		name:   "valid",
+		filter: BudgetFilter{ThresholdPercent: 90},
+	}, {
+		name:   "above the budget amount",
+		filter: BudgetFilter{ThresholdPercent: 150},
+	}, {
+		name:   "negative threshold",
+		filter: BudgetFilter{ThresholdPercent: -10},
+		want:   apis.ErrInvalidValue(int32(-10), "thresholdPercent"),
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.filter.Validate(context.Background())
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// MessageProperty is a property of a Pub/Sub message.
+type MessageProperty string
+
+const (
+	// MessageID is the ID of the message.
+	MessageID MessageProperty = "id"
+	// MessagePublishTime is the publish time of the message, in RFC 3339 format.
+	MessagePublishTime MessageProperty = "publishTime"
+	// MessageOrderingKey is the ordering key of the message.
+	MessageOrderingKey MessageProperty = "orderingKey"
+)
+
+// EventMapping declares how the CloudEvents sent to the sink are derived from the Pub/Sub
+// messages, instead of the conversion built into the receive adapter. The data of the
+// CloudEvents is the data of the messages.
+type EventMapping struct {
+	// ID is the id of the CloudEvents. Defaults to the ID of the messages.
+	// +optional
+	ID *ValueMapping `json:"id,omitempty"`
+
+	// Type is the type of the CloudEvents. Defaults to
+	// 'google.cloud.pubsub.topic.v1.messagePublished'.
+	// +optional
+	Type *ValueMapping `json:"type,omitempty"`
+
+	// Source is the source of the CloudEvents. Defaults to the topic of the messages, in the
+	// form '//pubsub.googleapis.com/projects/<project>/topics/<topic>'.
+	// +optional
+	Source *ValueMapping `json:"source,omitempty"`
+
+	// Subject is the subject of the CloudEvents. The CloudEvents have no subject by default.
+	// +optional
+	Subject *ValueMapping `json:"subject,omitempty"`
+
+	// Time is the time of the CloudEvents, in RFC 3339 format. Defaults to the publish time of
+	// the messages.
+	// +optional
+	Time *ValueMapping `json:"time,omitempty"`
+
+	// Extensions are the extensions of the CloudEvents, by name. Their names must consist of at
+	// most 20 lower-case letters or digits.
+	// +optional
+	Extensions map[string]ValueMapping `json:"extensions,omitempty"`
+
+	// DataContentType is the content type of the data of the CloudEvents. Defaults to
+	// 'application/json' if a field of the data is mapped, and to 'application/octet-stream'
+	// otherwise.
+	// +optional
+	DataContentType string `json:"dataContentType,omitempty"`
+}
+
+// ValueMapping is the value of an attribute of a CloudEvent. Exactly one of Value, Attribute,
+// Property and Field must be set.
+type ValueMapping struct {
+	// Value is a constant value.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Attribute is the name of an attribute of the message.
+	// +optional
+	Attribute string `json:"attribute,omitempty"`
+
+	// Property is a property of the message, one of 'id', 'publishTime' and 'orderingKey'.
+	// +optional
+	Property MessageProperty `json:"property,omitempty"`
+
+	// Field is the path of a field of the JSON data of the message, with the keys separated by
+	// dots, e.g. 'order.id'. The field must hold a string, a number or a boolean.
+	// +optional
+	Field string `json:"field,omitempty"`
+
+	// Default is the value used when the attribute, property or field is missing or empty.
+	// Without it, the messages it is missing from are acknowledged without being delivered.
+	// +optional
+	Default *string `json:"default,omitempty"`
+}
+
+// HasField returns whether the EventMapping maps a field of the data of the messages.
+func (m *EventMapping) HasField() bool {
+	for _, v := range []*ValueMapping{m.ID, m.Type, m.Source, m.Subject, m.Time} {
+		if v != nil && v.Field != "" {
+			return true
+		}
+	}
+	for _, v := range m.Extensions {
+		if v.Field != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/apis"
+)
+
+var (
+	extensionNameRegex = regexp.MustCompile(`^[a-z0-9]{1,20}$`)
+
+	reservedCloudEventAttributes = sets.NewString("id", "source", "specversion", "type", "datacontenttype",
+		"dataschema", "subject", "time", "data")
+)
+
+// ValidateExtensionAttributeName validates the name of an attribute that becomes a CloudEvents
+// extension.
+func ValidateExtensionAttributeName(name string) *apis.FieldError {
+	if !extensionNameRegex.MatchString(name) {
+		return &apis.FieldError{
+			Message: "attribute names must consist of at most 20 lower-case letters or digits",
+			Paths:   []string{apis.CurrentField},
+		}
+	}
+	if reservedCloudEventAttributes.Has(name) {
+		return &apis.FieldError{
+			Message: "attribute names must not be CloudEvents context attributes",
+			Paths:   []string{apis.CurrentField},
+		}
+	}
+	return nil
+}
+
+// Validate validates the EventMapping.
+func (m *EventMapping) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	for field, v := range map[string]*ValueMapping{
+		"id":      m.ID,
+		"type":    m.Type,
+		"source":  m.Source,
+		"subject": m.Subject,
+		"time":    m.Time,
+	} {
+		if v != nil {
+			errs = errs.Also(v.Validate(ctx).ViaField(field))
+		}
+	}
+	for name, v := range m.Extensions {
+		errs = errs.Also(ValidateExtensionAttributeName(name).ViaKey(name).ViaField("extensions"))
+		errs = errs.Also(v.Validate(ctx).ViaKey(name).ViaField("extensions"))
+	}
+	return errs
+}
+
+// Validate validates the ValueMapping.
+func (v *ValueMapping) Validate(ctx context.Context) *apis.FieldError {
+	var set []string
+	if v.Value != "" {
+		set = append(set, "value")
+	}
+	if v.Attribute != "" {
+		set = append(set, "attribute")
+	}
+	if v.Property != "" {
+		set = append(set, "property")
+	}
+	if v.Field != "" {
+		set = append(set, "field")
+	}
+	switch len(set) {
+	case 0:
+		return apis.ErrMissingOneOf("value", "attribute", "property", "field")
+	case 1:
+	default:
+		return apis.ErrMultipleOneOf(set...)
+	}
+	var errs *apis.FieldError
+	switch v.Property {
+	case "", MessageID, MessagePublishTime, MessageOrderingKey:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(v.Property, "property"))
+	}
+	if v.Field != "" {
+		for _, key := range strings.Split(v.Field, ".") {
+			if key == "" {
+				errs = errs.Also(apis.ErrInvalidValue(v.Field, "field"))
+				break
+			}
+		}
+	}
+	if v.Default != nil && v.Value != "" {
+		errs = errs.Also(apis.ErrDisallowedFields("default"))
+	}
+	return errs
+}
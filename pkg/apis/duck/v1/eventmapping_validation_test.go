@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+)
+
+func TestEventMappingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping EventMapping
+		want    *apis.FieldError
+	}{{
+		name:    "empty",
+		mapping: EventMapping{},
+	}, {
+		name: "valid",
+		mapping: EventMapping{
+			ID:      &ValueMapping{Field: "order.id"},
+			Type:    &ValueMapping{Value: "com.example.order"},
+			Source:  &ValueMapping{Attribute: "origin", Default: ptr.String("//example.com")},
+			Subject: &ValueMapping{Property: MessageOrderingKey},
+			Time:    &ValueMapping{Property: MessagePublishTime},
+			Extensions: map[string]ValueMapping{
+				"region": {Attribute: "region"},
+			},
+		},
+	}, {
+		name: "no value",
+		mapping: EventMapping{
+			Type: &ValueMapping{},
+		},
+		want: apis.ErrMissingOneOf("type.value", "type.attribute", "type.property", "type.field"),
+	}, {
+		name: "several values",
+		mapping: EventMapping{
+			Type: &ValueMapping{Value: "com.example.order", Attribute: "type"},
+		},
+		want: apis.ErrMultipleOneOf("type.value", "type.attribute"),
+	}, {
+		name: "unknown property",
+		mapping: EventMapping{
+			Subject: &ValueMapping{Property: "size"},
+		},
+		want: apis.ErrInvalidValue("size", "subject.property"),
+	}, {
+		name: "invalid field path",
+		mapping: EventMapping{
+			ID: &ValueMapping{Field: "order..id"},
+		},
+		want: apis.ErrInvalidValue("order..id", "id.field"),
+	}, {
+		name: "default of a constant value",
+		mapping: EventMapping{
+			ID: &ValueMapping{Value: "id", Default: ptr.String("other")},
+		},
+		want: apis.ErrDisallowedFields("id.default"),
+	}, {
+		name: "invalid extension name",
+		mapping: EventMapping{
+			Extensions: map[string]ValueMapping{
+				"Region": {Attribute: "region"},
+			},
+		},
+		want: &apis.FieldError{
+			Message: "attribute names must consist of at most 20 lower-case letters or digits",
+			Paths:   []string{"extensions[Region]"},
+		},
+	}, {
+		name: "extension is a context attribute",
+		mapping: EventMapping{
+			Extensions: map[string]ValueMapping{
+				"subject": {Attribute: "subject"},
+			},
+		},
+		want: &apis.FieldError{
+			Message: "attribute names must not be CloudEvents context attributes",
+			Paths:   []string{"extensions[subject]"},
+		},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.mapping.Validate(context.Background())
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+const (
+	// ImageActionInsert is the action of the notifications of pushed images.
+	ImageActionInsert = "INSERT"
+	// ImageActionDelete is the action of the notifications of deleted images.
+	ImageActionDelete = "DELETE"
+)
+
+// ImageFilter selects the Artifact Registry and Container Registry notifications of the
+// container images that are delivered. A notification must match every non-empty list of the
+// filter, and any entry of each list.
+type ImageFilter struct {
+	// Repositories are the images, or the repositories of images, whose notifications are
+	// delivered, e.g. 'us-docker.pkg.dev/my-project/my-repo' or 'gcr.io/my-project/my-image'.
+	// +optional
+	Repositories []string `json:"repositories,omitempty"`
+
+	// Tags are the patterns of the tags of the images whose notifications are delivered, e.g.
+	// 'v1.*'. The patterns use the syntax of https://golang.org/pkg/path/#Match. Images
+	// without a tag don't match.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Digests are the digests of the images whose notifications are delivered, e.g.
+	// 'sha256:6ec128e26cd5...'.
+	// +optional
+	Digests []string `json:"digests,omitempty"`
+
+	// Actions are the actions whose notifications are delivered, either 'INSERT' or 'DELETE'.
+	// +optional
+	Actions []string `json:"actions,omitempty"`
+}
+
+// IsEmpty returns whether the ImageFilter selects all the notifications.
+func (f *ImageFilter) IsEmpty() bool {
+	return len(f.Repositories) == 0 && len(f.Tags) == 0 && len(f.Digests) == 0 && len(f.Actions) == 0
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+// digestRegex matches the digests of container images.
+var digestRegex = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]{32,}$`)
+
+// Validate validates the ImageFilter. The field errors are relative to the spec embedding it.
+func (f *ImageFilter) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	for i, r := range f.Repositories {
+		if r == "" || strings.ContainsAny(r, ":@") || strings.HasSuffix(r, "/") {
+			errs = errs.Also(apis.ErrInvalidArrayValue(r, "repositories", i))
+		}
+	}
+	for i, t := range f.Tags {
+		// path.Match only reports malformed patterns when it reaches them.
+		if _, err := path.Match(t, ""); t == "" || err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(t, "tags", i))
+		}
+	}
+	for i, d := range f.Digests {
+		if !digestRegex.MatchString(d) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(d, "digests", i))
+		}
+	}
+	for i, a := range f.Actions {
+		if a != ImageActionInsert && a != ImageActionDelete {
+			errs = errs.Also(apis.ErrInvalidArrayValue(a, "actions", i))
+		}
+	}
+	return errs
+}
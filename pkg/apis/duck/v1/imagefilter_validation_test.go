@@ -0,0 +1,82 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+)
+
+func TestImageFilterValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ImageFilter
+		want   *apis.FieldError
+	}{{
+		name:   "empty",
+		filter: ImageFilter{},
+	}, {
+		name: "valid",
+		filter: ImageFilter{
+			Repositories: []string{"us-docker.pkg.dev/my-project/my-repo", "gcr.io/my-project/my-image"},
+			Tags:         []string{"v1.*", "latest"},
+			Digests:      []string{"sha256:6ec128e26cd5ab8d12d1bba4b9fa5d1b3c9d8c3d6a8c2c1d1e2f3a4b5c6d7e8f"},
+			Actions:      []string{ImageActionInsert, ImageActionDelete},
+		},
+	}, {
+		name: "repository with a tag",
+		filter: ImageFilter{
+			Repositories: []string{"gcr.io/my-project/my-image:latest"},
+		},
+		want: apis.ErrInvalidArrayValue("gcr.io/my-project/my-image:latest", "repositories", 0),
+	}, {
+		name: "repository with a trailing slash",
+		filter: ImageFilter{
+			Repositories: []string{"gcr.io/my-project", "gcr.io/my-project/"},
+		},
+		want: apis.ErrInvalidArrayValue("gcr.io/my-project/", "repositories", 1),
+	}, {
+		name: "malformed tag pattern",
+		filter: ImageFilter{
+			Tags: []string{"v1.[0-"},
+		},
+		want: apis.ErrInvalidArrayValue("v1.[0-", "tags", 0),
+	}, {
+		name: "invalid digest",
+		filter: ImageFilter{
+			Digests: []string{"6ec128e26cd5"},
+		},
+		want: apis.ErrInvalidArrayValue("6ec128e26cd5", "digests", 0),
+	}, {
+		name: "unknown action",
+		filter: ImageFilter{
+			Actions: []string{"UPDATE"},
+		},
+		want: apis.ErrInvalidArrayValue("UPDATE", "actions", 0),
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.filter.Validate(context.Background())
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
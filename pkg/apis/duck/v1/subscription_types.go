@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+)
+
+const (
+	// DefaultMaxDeliveryAttempts is the default number of delivery attempts of a message before
+	// it is forwarded to the dead letter topic.
+	DefaultMaxDeliveryAttempts = 5
+	// MinMaxDeliveryAttempts and MaxMaxDeliveryAttempts bound the number of delivery attempts
+	// accepted by Pub/Sub.
+	MinMaxDeliveryAttempts = 5
+	MaxMaxDeliveryAttempts = 100
+
+	// DefaultMinimumBackoff and DefaultMaximumBackoff are the backoffs Pub/Sub uses when they
+	// aren't set.
+	DefaultMinimumBackoff = 10 * time.Second
+	DefaultMaximumBackoff = 600 * time.Second
+	// MaxBackoff is the largest backoff accepted by Pub/Sub.
+	MaxBackoff = 600 * time.Second
+
+	// MaxFilterLength is the maximum length of a subscription filter in bytes.
+	MaxFilterLength = 256
+)
+
+// SubscriptionPolicySpec configures which messages the Pub/Sub subscription of a source
+// delivers, and what happens to the messages that fail to be delivered.
+type SubscriptionPolicySpec struct {
+	// Filter is an expression in the Pub/Sub filter language over the attributes of the
+	// messages, for example 'attributes.type = "order"'. Pub/Sub only delivers the messages
+	// that match it, and acknowledges the others. Pub/Sub can't change the filter of a
+	// subscription, so changing it recreates the subscription, which drops its backlog.
+	// See https://cloud.google.com/pubsub/docs/filtering.
+	// +optional
+	Filter string `json:"filter,omitempty"`
+
+	// DeadLetterPolicy forwards the messages that fail to be delivered to a dead letter topic.
+	// +optional
+	DeadLetterPolicy *DeadLetterPolicy `json:"deadLetterPolicy,omitempty"`
+
+	// RetryPolicy is the backoff between the deliveries of a message that failed to be
+	// delivered. If omitted, messages are redelivered immediately.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// DeadLetterPolicy is the dead letter policy of a Pub/Sub subscription.
+type DeadLetterPolicy struct {
+	// Topic is the ID of the dead letter topic, in the project of the subscription. It must
+	// be the ID of the topic, not its entire name.
+	Topic string `json:"topic"`
+
+	// MaxDeliveryAttempts is the number of delivery attempts of a message before it is
+	// forwarded to the dead letter topic, between 5 and 100. Defaults to 5.
+	// +optional
+	MaxDeliveryAttempts *int32 `json:"maxDeliveryAttempts,omitempty"`
+}
+
+// RetryPolicy is the retry policy of a Pub/Sub subscription.
+type RetryPolicy struct {
+	// MinimumBackoff is the minimum delay between the deliveries of a message, between '0s'
+	// and '600s'. Defaults to 10 seconds ('10s').
+	// +optional
+	MinimumBackoff *string `json:"minimumBackoff,omitempty"`
+
+	// MaximumBackoff is the maximum delay between the deliveries of a message, between '0s'
+	// and '600s'. Defaults to 600 seconds ('600s').
+	// +optional
+	MaximumBackoff *string `json:"maximumBackoff,omitempty"`
+}
+
+// GetMaxDeliveryAttempts returns MaxDeliveryAttempts, or the default if it isn't set.
+func (p DeadLetterPolicy) GetMaxDeliveryAttempts() int32 {
+	if p.MaxDeliveryAttempts != nil {
+		return *p.MaxDeliveryAttempts
+	}
+	return DefaultMaxDeliveryAttempts
+}
+
+// GetMinimumBackoff parses MinimumBackoff and returns the default if an error occurs.
+func (p RetryPolicy) GetMinimumBackoff() time.Duration {
+	return parseBackoff(p.MinimumBackoff, DefaultMinimumBackoff)
+}
+
+// GetMaximumBackoff parses MaximumBackoff and returns the default if an error occurs.
+func (p RetryPolicy) GetMaximumBackoff() time.Duration {
+	return parseBackoff(p.MaximumBackoff, DefaultMaximumBackoff)
+}
+
+func parseBackoff(backoff *string, def time.Duration) time.Duration {
+	if backoff != nil {
+		if duration, err := time.ParseDuration(*backoff); err == nil {
+			return duration
+		}
+	}
+	return def
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+// topicIDRegex matches the valid Pub/Sub topic IDs.
+// See https://cloud.google.com/pubsub/docs/admin#resource_names.
+var topicIDRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9\-._~%+]{2,254}$`)
+
+// Validate validates the SubscriptionPolicySpec. The field errors are relative to the spec
+// embedding it.
+func (s *SubscriptionPolicySpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if len(s.Filter) > MaxFilterLength {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("filter must be at most %d bytes long", MaxFilterLength),
+			Paths:   []string{"filter"},
+		})
+	}
+	if s.DeadLetterPolicy != nil {
+		errs = errs.Also(s.DeadLetterPolicy.Validate(ctx).ViaField("deadLetterPolicy"))
+	}
+	if s.RetryPolicy != nil {
+		errs = errs.Also(s.RetryPolicy.Validate(ctx).ViaField("retryPolicy"))
+	}
+	return errs
+}
+
+// Validate validates the DeadLetterPolicy.
+func (p *DeadLetterPolicy) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if p.Topic == "" {
+		errs = errs.Also(apis.ErrMissingField("topic"))
+	} else if !topicIDRegex.MatchString(p.Topic) || strings.HasPrefix(p.Topic, "goog") {
+		errs = errs.Also(apis.ErrInvalidValue(p.Topic, "topic"))
+	}
+	if p.MaxDeliveryAttempts != nil && (*p.MaxDeliveryAttempts < MinMaxDeliveryAttempts || *p.MaxDeliveryAttempts > MaxMaxDeliveryAttempts) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*p.MaxDeliveryAttempts, MinMaxDeliveryAttempts, MaxMaxDeliveryAttempts, "maxDeliveryAttempts"))
+	}
+	return errs
+}
+
+// Validate validates the RetryPolicy.
+func (p *RetryPolicy) Validate(ctx context.Context) *apis.FieldError {
+	minErr := validateBackoff(p.MinimumBackoff, "minimumBackoff")
+	maxErr := validateBackoff(p.MaximumBackoff, "maximumBackoff")
+	errs := minErr.Also(maxErr)
+	if minErr == nil && maxErr == nil && p.GetMinimumBackoff() > p.GetMaximumBackoff() {
+		errs = errs.Also(&apis.FieldError{
+			Message: "minimumBackoff must not be greater than maximumBackoff",
+			Paths:   []string{"minimumBackoff", "maximumBackoff"},
+		})
+	}
+	return errs
+}
+
+func validateBackoff(backoff *string, field string) *apis.FieldError {
+	if backoff == nil {
+		return nil
+	}
+	d, err := time.ParseDuration(*backoff)
+	if err != nil {
+		return apis.ErrInvalidValue(*backoff, field)
+	}
+	if d < 0 || d > MaxBackoff {
+		return apis.ErrOutOfBoundsValue(*backoff, "0s", MaxBackoff.String(), field)
+	}
+	return nil
+}
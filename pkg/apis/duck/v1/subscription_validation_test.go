@@ -0,0 +1,141 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+)
+
+func TestSubscriptionPolicySpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec SubscriptionPolicySpec
+		want *apis.FieldError
+	}{{
+		name: "empty",
+		spec: SubscriptionPolicySpec{},
+	}, {
+		name: "valid",
+		spec: SubscriptionPolicySpec{
+			Filter: `attributes.type = "order"`,
+			DeadLetterPolicy: &DeadLetterPolicy{
+				Topic:               "dead-letter",
+				MaxDeliveryAttempts: ptr.Int32(10),
+			},
+			RetryPolicy: &RetryPolicy{
+				MinimumBackoff: ptr.String("1s"),
+				MaximumBackoff: ptr.String("1m"),
+			},
+		},
+	}, {
+		name: "filter too long",
+		spec: SubscriptionPolicySpec{
+			Filter: strings.Repeat("a", MaxFilterLength+1),
+		},
+		want: &apis.FieldError{
+			Message: "filter must be at most 256 bytes long",
+			Paths:   []string{"filter"},
+		},
+	}, {
+		name: "dead letter topic missing",
+		spec: SubscriptionPolicySpec{
+			DeadLetterPolicy: &DeadLetterPolicy{},
+		},
+		want: apis.ErrMissingField("deadLetterPolicy.topic"),
+	}, {
+		name: "dead letter topic is a full name",
+		spec: SubscriptionPolicySpec{
+			DeadLetterPolicy: &DeadLetterPolicy{
+				Topic: "projects/project/topics/dead-letter",
+			},
+		},
+		want: apis.ErrInvalidValue("projects/project/topics/dead-letter", "deadLetterPolicy.topic"),
+	}, {
+		name: "dead letter topic starts with goog",
+		spec: SubscriptionPolicySpec{
+			DeadLetterPolicy: &DeadLetterPolicy{
+				Topic: "google-dead-letter",
+			},
+		},
+		want: apis.ErrInvalidValue("google-dead-letter", "deadLetterPolicy.topic"),
+	}, {
+		name: "max delivery attempts out of bounds",
+		spec: SubscriptionPolicySpec{
+			DeadLetterPolicy: &DeadLetterPolicy{
+				Topic:               "dead-letter",
+				MaxDeliveryAttempts: ptr.Int32(4),
+			},
+		},
+		want: apis.ErrOutOfBoundsValue(4, MinMaxDeliveryAttempts, MaxMaxDeliveryAttempts, "deadLetterPolicy.maxDeliveryAttempts"),
+	}, {
+		name: "invalid backoff",
+		spec: SubscriptionPolicySpec{
+			RetryPolicy: &RetryPolicy{
+				MinimumBackoff: ptr.String("soon"),
+			},
+		},
+		want: apis.ErrInvalidValue("soon", "retryPolicy.minimumBackoff"),
+	}, {
+		name: "backoff out of bounds",
+		spec: SubscriptionPolicySpec{
+			RetryPolicy: &RetryPolicy{
+				MaximumBackoff: ptr.String("11m"),
+			},
+		},
+		want: apis.ErrOutOfBoundsValue("11m", "0s", "10m0s", "retryPolicy.maximumBackoff"),
+	}, {
+		name: "minimum backoff greater than maximum backoff",
+		spec: SubscriptionPolicySpec{
+			RetryPolicy: &RetryPolicy{
+				MinimumBackoff: ptr.String("2m"),
+				MaximumBackoff: ptr.String("1m"),
+			},
+		},
+		want: &apis.FieldError{
+			Message: "minimumBackoff must not be greater than maximumBackoff",
+			Paths:   []string{"retryPolicy.minimumBackoff", "retryPolicy.maximumBackoff"},
+		},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.spec.Validate(context.Background())
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDefaults(t *testing.T) {
+	p := RetryPolicy{MinimumBackoff: ptr.String("1s")}
+	if got, want := p.GetMinimumBackoff(), time.Second; got != want {
+		t.Errorf("GetMinimumBackoff got=%v, want=%v", got, want)
+	}
+	if got, want := p.GetMaximumBackoff(), DefaultMaximumBackoff; got != want {
+		t.Errorf("GetMaximumBackoff got=%v, want=%v", got, want)
+	}
+	if got, want := (DeadLetterPolicy{}).GetMaxDeliveryAttempts(), int32(DefaultMaxDeliveryAttempts); got != want {
+		t.Errorf("GetMaxDeliveryAttempts got=%v, want=%v", got, want)
+	}
+}
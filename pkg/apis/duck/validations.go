@@ -68,6 +68,28 @@ func ValidateAutoscalingAnnotations(ctx context.Context, annotations map[string]
 	return errs
 }
 
+// ValidateDeliveryModeAnnotation validates the delivery mode annotation. The push delivery mode has
+// no receive adapter to autoscale.
+func ValidateDeliveryModeAnnotation(annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
+	mode, ok := annotations[DeliveryModeAnnotation]
+	if !ok {
+		return errs
+	}
+	switch mode {
+	case DeliveryModePull:
+	case DeliveryModePush:
+		if _, ok := annotations[AutoscalingClassAnnotation]; ok {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("the %s delivery mode can't be autoscaled", DeliveryModePush),
+				Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", DeliveryModeAnnotation), fmt.Sprintf("[%s]", AutoscalingClassAnnotation)},
+			})
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(mode, fmt.Sprintf("metadata.annotations[%s]", DeliveryModeAnnotation)))
+	}
+	return errs
+}
+
 func validateAnnotation(annotations map[string]string, annotation string, minimumValue int, errs *apis.FieldError) (int, *apis.FieldError) {
 	var value int
 	if val, ok := annotations[annotation]; !ok {
@@ -116,6 +138,19 @@ func CheckImmutableAutoscalingClassAnnotations(current *metav1.ObjectMeta, origi
 	return errs
 }
 
+// CheckImmutableDeliveryModeAnnotation checks the DeliveryModeAnnotation annotation is immutable.
+func CheckImmutableDeliveryModeAnnotation(current *metav1.ObjectMeta, original *metav1.ObjectMeta, errs *apis.FieldError) *apis.FieldError {
+	// The DeliveryModeAnnotation should be immutable no matter if it was defined or not.
+	if diff := cmp.Diff(original.Annotations[DeliveryModeAnnotation], current.Annotations[DeliveryModeAnnotation]); diff != "" {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", DeliveryModeAnnotation)},
+			Details: diff,
+		})
+	}
+	return errs
+}
+
 // ValidateCredential checks secret and service account.
 func ValidateCredential(secret *corev1.SecretKeySelector, kServiceAccountName string) *apis.FieldError {
 	if secret != nil && kServiceAccountName != "" {
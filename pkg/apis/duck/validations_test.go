@@ -287,6 +287,126 @@ func TestCheckImmutableAutoscalingClassAnnotation(t *testing.T) {
 	}
 }
 
+func TestValidateDeliveryModeAnnotation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		error       bool
+	}{
+		"ok no delivery mode": {
+			annotations: nil,
+			error:       false,
+		},
+		"ok pull": {
+			annotations: map[string]string{
+				DeliveryModeAnnotation: DeliveryModePull,
+			},
+			error: false,
+		},
+		"ok push": {
+			annotations: map[string]string{
+				DeliveryModeAnnotation: DeliveryModePush,
+			},
+			error: false,
+		},
+		"ok pull with keda scaling": {
+			annotations: map[string]string{
+				DeliveryModeAnnotation:     DeliveryModePull,
+				AutoscalingClassAnnotation: KEDA,
+			},
+			error: false,
+		},
+		"invalid push with keda scaling": {
+			annotations: map[string]string{
+				DeliveryModeAnnotation:     DeliveryModePush,
+				AutoscalingClassAnnotation: KEDA,
+			},
+			error: true,
+		},
+		"unsupported delivery mode": {
+			annotations: map[string]string{
+				DeliveryModeAnnotation: "invalid",
+			},
+			error: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var errs *apis.FieldError
+			err := ValidateDeliveryModeAnnotation(tc.annotations, errs)
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckImmutableDeliveryModeAnnotation(t *testing.T) {
+	testCases := map[string]struct {
+		original *v1.ObjectMeta
+		current  *v1.ObjectMeta
+		error    bool
+	}{
+		"unchanged nil annotation": {
+			original: &v1.ObjectMeta{},
+			current:  &v1.ObjectMeta{},
+			error:    false,
+		},
+		"update nil annotation": {
+			original: &v1.ObjectMeta{},
+			current: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryModeAnnotation: DeliveryModePush,
+				},
+			},
+			error: true,
+		},
+		"update non-empty annotation": {
+			original: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryModeAnnotation: DeliveryModePush,
+				},
+			},
+			current: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryModeAnnotation: DeliveryModePull,
+				},
+			},
+			error: true,
+		},
+		"delete annotation": {
+			original: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryModeAnnotation: DeliveryModePush,
+				},
+			},
+			current: &v1.ObjectMeta{},
+			error:   true,
+		},
+		"unchanged annotation": {
+			original: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryModeAnnotation: DeliveryModePush,
+				},
+			},
+			current: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryModeAnnotation: DeliveryModePush,
+				},
+			},
+			error: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var err *apis.FieldError
+			err = CheckImmutableDeliveryModeAnnotation(tc.current, tc.original, err)
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
 func TestValidateCredential(t *testing.T) {
 	testCases := []struct {
 		name           string
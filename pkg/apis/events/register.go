@@ -22,6 +22,9 @@ import "k8s.io/apimachinery/pkg/runtime/schema"
 const (
 	GroupName       = "events.cloud.google.com"
 	CloudBuildTopic = "cloud-builds"
+	// ArtifactRegistryTopic is the topic on which Artifact Registry and Container Registry
+	// publish the notifications of the images of a project.
+	ArtifactRegistryTopic = "gcr"
 )
 
 var (
@@ -50,4 +53,19 @@ var (
 		Group:    GroupName,
 		Resource: "cloudbuildsources",
 	}
+	// CloudArtifactRegistrySourcesResource represents a CloudArtifactRegistrySource.
+	CloudArtifactRegistrySourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudartifactregistrysources",
+	}
+	// CloudBillingBudgetSourcesResource represents a CloudBillingBudgetSource.
+	CloudBillingBudgetSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudbillingbudgetsources",
+	}
+	// CloudMonitoringAlertSourcesResource represents a CloudMonitoringAlertSource.
+	CloudMonitoringAlertSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudmonitoringalertsources",
+	}
 )
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	kngcpduckv1 "github.com/google/knative-gcp/pkg/duck/v1"
+)
+
+// CloudArtifactRegistrySource is a specification for a CloudArtifactRegistrySource resource.
+// It sends the notifications of the container images pushed to or deleted from the Artifact
+// Registry and Container Registry repositories of a project.
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CloudArtifactRegistrySource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudArtifactRegistrySourceSpec   `json:"spec,omitempty"`
+	Status CloudArtifactRegistrySourceStatus `json:"status,omitempty"`
+}
+
+var (
+	_ apis.Convertible             = (*CloudArtifactRegistrySource)(nil)
+	_ apis.Defaultable             = (*CloudArtifactRegistrySource)(nil)
+	_ apis.Validatable             = (*CloudArtifactRegistrySource)(nil)
+	_ runtime.Object               = (*CloudArtifactRegistrySource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudArtifactRegistrySource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudArtifactRegistrySource)(nil)
+	_ kngcpduckv1.PubSubable       = (*CloudArtifactRegistrySource)(nil)
+	_ kngcpduckv1.Identifiable     = (*CloudArtifactRegistrySource)(nil)
+	_ kngcpduckv1.ImageFilterable  = (*CloudArtifactRegistrySource)(nil)
+	_                              = duck.VerifyType(&CloudArtifactRegistrySource{}, &duckv1.Conditions{})
+	_ duckv1.KRShaped              = (*CloudArtifactRegistrySource)(nil)
+)
+
+// CloudArtifactRegistrySourceSpec defines the desired state of the CloudArtifactRegistrySource.
+type CloudArtifactRegistrySourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret and Project.
+	gcpduckv1.PubSubSpec `json:",inline"`
+
+	// ImageFilter selects the images whose notifications are sent. Includes:
+	// Repositories, Tags, Digests and Actions. All the notifications are sent when it is empty.
+	gcpduckv1.ImageFilter `json:",inline"`
+}
+
+const (
+	// CloudArtifactRegistrySourceConditionReady has status True when the
+	// CloudArtifactRegistrySource is ready to send events.
+	CloudArtifactRegistrySourceConditionReady = apis.ConditionReady
+)
+
+var artifactRegistryCondSet = apis.NewLivingConditionSet(
+	gcpduckv1.PullSubscriptionReady,
+)
+
+// CloudArtifactRegistrySourceStatus defines the observed state of CloudArtifactRegistrySource.
+type CloudArtifactRegistrySourceStatus struct {
+	gcpduckv1.PubSubStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudArtifactRegistrySourceList contains a list of CloudArtifactRegistrySources.
+type CloudArtifactRegistrySourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudArtifactRegistrySource `json:"items"`
+}
+
+// Methods for pubsubable interface
+func (*CloudArtifactRegistrySource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudArtifactRegistrySource")
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudArtifactRegistrySource) IdentitySpec() *gcpduckv1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudArtifactRegistrySource) IdentityStatus() *gcpduckv1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudArtifactRegistrySource) PubSubSpec() *gcpduckv1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudArtifactRegistrySource) PubSubStatus() *gcpduckv1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// ImageFilter returns the ImageFilter of the Spec, or nil if it is empty.
+func (s *CloudArtifactRegistrySource) ImageFilter() *gcpduckv1.ImageFilter {
+	if s.Spec.ImageFilter.IsEmpty() {
+		return nil
+	}
+	return &s.Spec.ImageFilter
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (s *CloudArtifactRegistrySource) ConditionSet() *apis.ConditionSet {
+	return &artifactRegistryCondSet
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*CloudArtifactRegistrySource) GetConditionSet() apis.ConditionSet {
+	return artifactRegistryCondSet
+}
+
+// GetStatus retrieves the status of the CloudArtifactRegistrySource. Implements the KRShaped interface.
+func (s *CloudArtifactRegistrySource) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
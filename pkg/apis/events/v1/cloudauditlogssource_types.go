@@ -83,6 +83,50 @@ type CloudAuditLogsSourceSpec struct {
 	// operation. The name is a scheme-less URI, not including the
 	// API service name.
 	ResourceName string `json:"resourceName,omitempty"`
+
+	// Filter is an advanced logs filter
+	// (https://cloud.google.com/logging/docs/view/advanced-queries)
+	// the audit logs must also match, e.g. to match on the principal
+	// email, severity or resource labels of the logs.
+	// ServiceName and MethodName are not required if Filter is set.
+	// +optional
+	Filter string `json:"filter,omitempty"`
+
+	// Parent is the resource whose audit logs are exported to the
+	// source. Defaults to the project of the source.
+	// +optional
+	Parent *AuditLogsParent `json:"parent,omitempty"`
+}
+
+// AuditLogsParentType is the type of resource a CloudAuditLogsSource
+// exports the audit logs of.
+type AuditLogsParentType string
+
+const (
+	// AuditLogsParentProject exports the audit logs of a project.
+	AuditLogsParentProject AuditLogsParentType = "project"
+	// AuditLogsParentFolder exports the audit logs of a folder and of
+	// the folders and projects it contains.
+	AuditLogsParentFolder AuditLogsParentType = "folder"
+	// AuditLogsParentOrganization exports the audit logs of an
+	// organization and of the folders and projects it contains.
+	AuditLogsParentOrganization AuditLogsParentType = "organization"
+	// AuditLogsParentBillingAccount exports the audit logs of a
+	// billing account.
+	AuditLogsParentBillingAccount AuditLogsParentType = "billingAccount"
+)
+
+// AuditLogsParent is the resource whose audit logs a
+// CloudAuditLogsSource exports.
+type AuditLogsParent struct {
+	// Type is the type of the resource, one of project, folder,
+	// organization or billingAccount.
+	Type AuditLogsParentType `json:"type"`
+
+	// ID is the ID of the resource. It defaults to the project of the
+	// source if Type is project, and is required otherwise.
+	// +optional
+	ID string `json:"id,omitempty"`
 }
 
 type CloudAuditLogsSourceStatus struct {
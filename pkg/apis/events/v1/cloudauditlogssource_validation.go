@@ -18,6 +18,8 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -28,6 +30,16 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+const (
+	// maxFilterLength is the maximum length of the filter of a Cloud Logging sink.
+	maxFilterLength = 20000
+)
+
+var (
+	numericIDRegex        = regexp.MustCompile(`^[0-9]+$`)
+	billingAccountIDRegex = regexp.MustCompile(`^[0-9A-F]{6}-[0-9A-F]{6}-[0-9A-F]{6}$`)
+)
+
 func (current *CloudAuditLogsSource) Validate(ctx context.Context) *apis.FieldError {
 	err := current.Spec.Validate(ctx).ViaField("spec")
 
@@ -48,13 +60,23 @@ func (current *CloudAuditLogsSourceSpec) Validate(ctx context.Context) *apis.Fie
 		errs = errs.Also(err.ViaField("sink"))
 	}
 
-	// ServiceName [required]
-	if current.ServiceName == "" {
-		errs = errs.Also(apis.ErrMissingField("serviceName"))
+	// ServiceName and MethodName [required unless Filter is set]
+	if current.Filter == "" {
+		if current.ServiceName == "" {
+			errs = errs.Also(apis.ErrMissingField("serviceName"))
+		}
+		if current.MethodName == "" {
+			errs = errs.Also(apis.ErrMissingField("methodName"))
+		}
+	} else if len(current.Filter) > maxFilterLength {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("filter must be at most %d characters long", maxFilterLength),
+			Paths:   []string{"filter"},
+		})
 	}
-	// MethodName [required]
-	if current.MethodName == "" {
-		errs = errs.Also(apis.ErrMissingField("methodName"))
+
+	if current.Parent != nil {
+		errs = errs.Also(current.Parent.Validate(ctx).ViaField("parent"))
 	}
 
 	if err := duck.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
@@ -64,13 +86,40 @@ func (current *CloudAuditLogsSourceSpec) Validate(ctx context.Context) *apis.Fie
 	return errs
 }
 
+func (current *AuditLogsParent) Validate(ctx context.Context) *apis.FieldError {
+	switch current.Type {
+	case AuditLogsParentProject:
+		return nil
+	case AuditLogsParentFolder, AuditLogsParentOrganization:
+		if current.ID == "" {
+			return apis.ErrMissingField("id")
+		}
+		if !numericIDRegex.MatchString(current.ID) {
+			return invalidValue(current.ID, "id", "the ID of a folder or organization must be numeric")
+		}
+		return nil
+	case AuditLogsParentBillingAccount:
+		if current.ID == "" {
+			return apis.ErrMissingField("id")
+		}
+		if !billingAccountIDRegex.MatchString(current.ID) {
+			return invalidValue(current.ID, "id", "the ID of a billing account must have the format XXXXXX-XXXXXX-XXXXXX")
+		}
+		return nil
+	case "":
+		return apis.ErrMissingField("type")
+	default:
+		return invalidValue(current.Type, "type", "type must be one of project, folder, organization or billingAccount")
+	}
+}
+
 func (current *CloudAuditLogsSource) CheckImmutableFields(ctx context.Context, original *CloudAuditLogsSource) *apis.FieldError {
 	if original == nil {
 		return nil
 	}
 
 	var errs *apis.FieldError
-	// Modification of Topic, Secret, ServiceAccountName, Project, ServiceName, MethodName and ResourceName are not allowed.
+	// Modification of Topic, Secret, ServiceAccountName, Project, ServiceName, MethodName, ResourceName, Filter and Parent are not allowed.
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudAuditLogsSourceSpec{},
@@ -84,7 +133,15 @@ func (current *CloudAuditLogsSource) CheckImmutableFields(ctx context.Context, o
 	}
 	// Modification of AutoscalingClassAnnotations is not allowed.
 	errs = duck.CheckImmutableAutoscalingClassAnnotations(&current.ObjectMeta, &original.ObjectMeta, errs)
+	// Modification of the DeliveryModeAnnotation is not allowed.
+	errs = duck.CheckImmutableDeliveryModeAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
 
 	// Modification of non-empty cluster name annotation is not allowed.
 	return duck.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
 }
+
+func invalidValue(value interface{}, field, details string) *apis.FieldError {
+	err := apis.ErrInvalidValue(value, field)
+	err.Details = details
+	return err
+}
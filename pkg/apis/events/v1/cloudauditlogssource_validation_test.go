@@ -18,6 +18,7 @@ package v1
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/knative-gcp/pkg/apis/duck"
@@ -106,6 +107,96 @@ func TestCloudAuditLogsSourceValidationFields(t *testing.T) {
 			}(),
 			error: true,
 		},
+		"filter without ServiceName and MethodName": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.ServiceName = ""
+				obj.MethodName = ""
+				obj.Filter = `protoPayload.methodName:"SetIamPolicy" AND severity>=NOTICE`
+				return *obj
+			}(),
+			error: false,
+		},
+		"filter too long": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Filter = strings.Repeat("a", 20001)
+				return *obj
+			}(),
+			error: true,
+		},
+		"project parent": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{Type: AuditLogsParentProject}
+				return *obj
+			}(),
+			error: false,
+		},
+		"folder parent": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{Type: AuditLogsParentFolder, ID: "123456789"}
+				return *obj
+			}(),
+			error: false,
+		},
+		"organization parent": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{Type: AuditLogsParentOrganization, ID: "123456789"}
+				return *obj
+			}(),
+			error: false,
+		},
+		"billing account parent": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{Type: AuditLogsParentBillingAccount, ID: "012345-6789AB-CDEF01"}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad parent, missing type": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{ID: "123456789"}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad parent, unknown type": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{Type: "workspace", ID: "123456789"}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad parent, missing folder id": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{Type: AuditLogsParentFolder}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad parent, non-numeric organization id": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{Type: AuditLogsParentOrganization, ID: "organizations/123"}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad parent, malformed billing account id": {
+			spec: func() CloudAuditLogsSourceSpec {
+				obj := auditLogsSourceSpec.DeepCopy()
+				obj.Parent = &AuditLogsParent{Type: AuditLogsParentBillingAccount, ID: "0123456789"}
+				return *obj
+			}(),
+			error: true,
+		},
 		"bad sink, name": {
 			spec: func() CloudAuditLogsSourceSpec {
 				obj := auditLogsSourceSpec.DeepCopy()
@@ -241,6 +332,28 @@ func TestCloudAuditLogsSourceCheckImmutableFields(t *testing.T) {
 			},
 			allowed: false,
 		},
+		"Filter changed": {
+			orig: &auditLogsSourceSpec,
+			updated: CloudAuditLogsSourceSpec{
+				MethodName:   auditLogsSourceSpec.MethodName,
+				PubSubSpec:   auditLogsSourceSpec.PubSubSpec,
+				ResourceName: auditLogsSourceSpec.ResourceName,
+				ServiceName:  auditLogsSourceSpec.ServiceName,
+				Filter:       "severity>=ERROR",
+			},
+			allowed: false,
+		},
+		"Parent changed": {
+			orig: &auditLogsSourceSpec,
+			updated: CloudAuditLogsSourceSpec{
+				MethodName:   auditLogsSourceSpec.MethodName,
+				PubSubSpec:   auditLogsSourceSpec.PubSubSpec,
+				ResourceName: auditLogsSourceSpec.ResourceName,
+				ServiceName:  auditLogsSourceSpec.ServiceName,
+				Parent:       &AuditLogsParent{Type: AuditLogsParentFolder, ID: "123456789"},
+			},
+			allowed: false,
+		},
 		"Project changed": {
 			orig: &auditLogsSourceSpec,
 			updated: CloudAuditLogsSourceSpec{
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	kngcpduckv1 "github.com/google/knative-gcp/pkg/duck/v1"
+)
+
+// CloudBillingBudgetSource is a specification for a CloudBillingBudgetSource resource.
+// It sends the Cloud Billing budget notifications published to a Pub/Sub topic.
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CloudBillingBudgetSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudBillingBudgetSourceSpec   `json:"spec,omitempty"`
+	Status CloudBillingBudgetSourceStatus `json:"status,omitempty"`
+}
+
+var (
+	_ apis.Convertible             = (*CloudBillingBudgetSource)(nil)
+	_ apis.Defaultable             = (*CloudBillingBudgetSource)(nil)
+	_ apis.Validatable             = (*CloudBillingBudgetSource)(nil)
+	_ runtime.Object               = (*CloudBillingBudgetSource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudBillingBudgetSource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudBillingBudgetSource)(nil)
+	_ kngcpduckv1.PubSubable       = (*CloudBillingBudgetSource)(nil)
+	_ kngcpduckv1.Identifiable     = (*CloudBillingBudgetSource)(nil)
+	_ kngcpduckv1.BudgetFilterable = (*CloudBillingBudgetSource)(nil)
+	_                              = duck.VerifyType(&CloudBillingBudgetSource{}, &duckv1.Conditions{})
+	_ duckv1.KRShaped              = (*CloudBillingBudgetSource)(nil)
+)
+
+// CloudBillingBudgetSourceSpec defines the desired state of the CloudBillingBudgetSource.
+type CloudBillingBudgetSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret and Project.
+	gcpduckv1.PubSubSpec `json:",inline"`
+
+	// Topic is the ID of the Pub/Sub topic to which the budget notifications are published. It
+	// must be in the form of the unique identifier within the project, not the entire name. E.g.
+	// it must be 'budgets', not 'projects/my-proj/topics/budgets'.
+	Topic string `json:"topic"`
+
+	// BudgetFilter selects the notifications that are sent. Includes: ThresholdPercent. All the
+	// notifications are sent when it is empty.
+	gcpduckv1.BudgetFilter `json:",inline"`
+}
+
+const (
+	// CloudBillingBudgetSourceConditionReady has status True when the
+	// CloudBillingBudgetSource is ready to send events.
+	CloudBillingBudgetSourceConditionReady = apis.ConditionReady
+)
+
+var billingBudgetCondSet = apis.NewLivingConditionSet(
+	gcpduckv1.PullSubscriptionReady,
+)
+
+// CloudBillingBudgetSourceStatus defines the observed state of CloudBillingBudgetSource.
+type CloudBillingBudgetSourceStatus struct {
+	gcpduckv1.PubSubStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudBillingBudgetSourceList contains a list of CloudBillingBudgetSources.
+type CloudBillingBudgetSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudBillingBudgetSource `json:"items"`
+}
+
+// Methods for pubsubable interface
+func (*CloudBillingBudgetSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudBillingBudgetSource")
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudBillingBudgetSource) IdentitySpec() *gcpduckv1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudBillingBudgetSource) IdentityStatus() *gcpduckv1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudBillingBudgetSource) PubSubSpec() *gcpduckv1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudBillingBudgetSource) PubSubStatus() *gcpduckv1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// BudgetFilter returns the BudgetFilter of the Spec, or nil if it is empty.
+func (s *CloudBillingBudgetSource) BudgetFilter() *gcpduckv1.BudgetFilter {
+	if s.Spec.BudgetFilter.IsEmpty() {
+		return nil
+	}
+	return &s.Spec.BudgetFilter
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (s *CloudBillingBudgetSource) ConditionSet() *apis.ConditionSet {
+	return &billingBudgetCondSet
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*CloudBillingBudgetSource) GetConditionSet() apis.ConditionSet {
+	return billingBudgetCondSet
+}
+
+// GetStatus retrieves the status of the CloudBillingBudgetSource. Implements the KRShaped interface.
+func (s *CloudBillingBudgetSource) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
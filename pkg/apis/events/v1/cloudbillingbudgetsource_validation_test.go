@@ -0,0 +1,541 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	billingBudgetSourceSpec = CloudBillingBudgetSourceSpec{
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "secret-name",
+				},
+				Key: "secret-key",
+			},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			Project: "my-eventing-project",
+		},
+		Topic: "budgets",
+	}
+
+	billingBudgetSourceSpecWithKSA = CloudBillingBudgetSourceSpec{
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			IdentitySpec: gcpduckv1.IdentitySpec{
+				ServiceAccountName: "old-service-account",
+			},
+			Project: "my-eventing-project",
+		},
+		Topic: "budgets",
+	}
+)
+
+func TestCloudBillingBudgetSourceCheckValidationFields(t *testing.T) {
+	testCases := map[string]struct {
+		spec  CloudBillingBudgetSourceSpec
+		error bool
+	}{
+		"ok": {
+			spec:  billingBudgetSourceSpec,
+			error: false,
+		},
+		"bad sink, name": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Sink.Ref.Name = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, apiVersion": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Sink.Ref.APIVersion = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, kind": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Sink.Ref.Kind = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, empty": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri scheme": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Host: "example.com",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri host": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Scheme: "http",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri and ref": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Scheme: "http",
+						Host:   "example.com",
+					},
+					Ref: &duckv1.KReference{
+						Name: "foo",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"invalid secret, missing key": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Secret = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "name",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"nil service account": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				return *obj
+			}(),
+			error: false,
+		},
+		"invalid k8s service account": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.ServiceAccountName = invalidServiceAccountName
+				return *obj
+			}(),
+			error: true,
+		},
+		"missing topic": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Topic = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"valid budget filter": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.ThresholdPercent = 90
+				return *obj
+			}(),
+			error: false,
+		},
+		"invalid budget filter": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.ThresholdPercent = -1
+				return *obj
+			}(),
+			error: true,
+		},
+		"have k8s service account and secret at the same time": {
+			spec: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.ServiceAccountName = validServiceAccountName
+				obj.Secret = &gcpauthtesthelper.Secret
+				return *obj
+			}(),
+			error: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.spec.Validate(context.TODO())
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestCloudBillingBudgetSourceCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		orig              interface{}
+		updated           CloudBillingBudgetSourceSpec
+		origAnnotation    map[string]string
+		updatedAnnotation map[string]string
+		allowed           bool
+	}{
+		"nil orig": {
+			updated: billingBudgetSourceSpec,
+			allowed: true,
+		},
+		"ClusterName annotation changed": {
+			origAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "old",
+			},
+			updatedAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "new",
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation changed": {
+			origAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			updatedAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA + "new",
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation added": {
+			origAnnotation: map[string]string{},
+			updatedAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation deleted": {
+			origAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			updatedAnnotation: map[string]string{},
+			allowed:           false,
+		},
+		"Topic changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.Topic = "other-budgets"
+				return *obj
+			}(),
+			allowed: false,
+		},
+		"BudgetFilter changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: func() CloudBillingBudgetSourceSpec {
+				obj := billingBudgetSourceSpec.DeepCopy()
+				obj.ThresholdPercent = 90
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Secret.Name changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "some-other-name",
+						},
+						Key: billingBudgetSourceSpec.Secret.Key,
+					},
+					Project: billingBudgetSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: billingBudgetSourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"Secret.Key changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: billingBudgetSourceSpec.Secret.Name,
+						},
+						Key: "some-other-key",
+					},
+					Project: billingBudgetSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: billingBudgetSourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"Project changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: billingBudgetSourceSpec.Secret.Name,
+						},
+						Key: billingBudgetSourceSpec.Secret.Key,
+					},
+					Project: "some-other-project",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: billingBudgetSourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"ServiceAccountName changed": {
+			orig: &billingBudgetSourceSpecWithKSA,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					IdentitySpec: gcpduckv1.IdentitySpec{
+						ServiceAccountName: "new-service-account",
+					},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: billingBudgetSourceSpecWithKSA.Sink,
+					},
+					Project: billingBudgetSourceSpecWithKSA.Project,
+				},
+			},
+			allowed: false,
+		},
+		"ServiceAccountName added": {
+			orig: &billingBudgetSourceSpec,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: billingBudgetSourceSpec.Secret.Name,
+						},
+						Key: billingBudgetSourceSpec.Secret.Key,
+					},
+					Project: billingBudgetSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: billingBudgetSourceSpec.Sink,
+					},
+					IdentitySpec: gcpduckv1.IdentitySpec{
+						ServiceAccountName: "old-service-account",
+					},
+				},
+			},
+			allowed: false,
+		},
+		"ClusterName annotation added": {
+			origAnnotation: nil,
+			updatedAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "new",
+			},
+			allowed: true,
+		},
+		"Sink.APIVersion changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: billingBudgetSourceSpec.Secret.Name,
+						},
+						Key: billingBudgetSourceSpec.Secret.Key,
+					},
+					Project: billingBudgetSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "some-other-api-version",
+								Kind:       billingBudgetSourceSpec.Sink.Ref.Kind,
+								Namespace:  billingBudgetSourceSpec.Sink.Ref.Namespace,
+								Name:       billingBudgetSourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Kind changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: billingBudgetSourceSpec.Secret.Name,
+						},
+						Key: billingBudgetSourceSpec.Secret.Key,
+					},
+					Project: billingBudgetSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: billingBudgetSourceSpec.Sink.Ref.APIVersion,
+								Kind:       "some-other-kind",
+								Namespace:  billingBudgetSourceSpec.Sink.Ref.Namespace,
+								Name:       billingBudgetSourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Namespace changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: billingBudgetSourceSpec.Secret.Name,
+						},
+						Key: billingBudgetSourceSpec.Secret.Key,
+					},
+					Project: billingBudgetSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: billingBudgetSourceSpec.Sink.Ref.APIVersion,
+								Kind:       billingBudgetSourceSpec.Sink.Ref.Kind,
+								Namespace:  "some-other-namespace",
+								Name:       billingBudgetSourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Name changed": {
+			orig: &billingBudgetSourceSpec,
+			updated: CloudBillingBudgetSourceSpec{
+				Topic: billingBudgetSourceSpec.Topic,
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: billingBudgetSourceSpec.Secret.Name,
+						},
+						Key: billingBudgetSourceSpec.Secret.Key,
+					},
+					Project: billingBudgetSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: billingBudgetSourceSpec.Sink.Ref.APIVersion,
+								Kind:       billingBudgetSourceSpec.Sink.Ref.Kind,
+								Namespace:  billingBudgetSourceSpec.Sink.Ref.Namespace,
+								Name:       "some-other-name",
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"no change": {
+			orig:    &billingBudgetSourceSpec,
+			updated: billingBudgetSourceSpec,
+			allowed: true,
+		},
+		"no spec": {
+			orig:    []string{"wrong"},
+			updated: billingBudgetSourceSpec,
+			allowed: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var orig *CloudBillingBudgetSource
+
+			if tc.origAnnotation != nil {
+				orig = &CloudBillingBudgetSource{
+					ObjectMeta: v1.ObjectMeta{
+						Annotations: tc.origAnnotation,
+					},
+				}
+			} else if tc.orig != nil {
+				if spec, ok := tc.orig.(*CloudBillingBudgetSourceSpec); ok {
+					orig = &CloudBillingBudgetSource{
+						Spec: *spec,
+					}
+				}
+			}
+			updated := &CloudBillingBudgetSource{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: tc.updatedAnnotation,
+				},
+				Spec: tc.updated,
+			}
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
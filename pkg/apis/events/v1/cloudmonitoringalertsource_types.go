@@ -0,0 +1,152 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	kngcpduckv1 "github.com/google/knative-gcp/pkg/duck/v1"
+)
+
+// CloudMonitoringAlertSource is a specification for a CloudMonitoringAlertSource resource.
+// It sends the incidents of the Cloud Monitoring alerting policies that notify its Pub/Sub
+// notification channel.
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CloudMonitoringAlertSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudMonitoringAlertSourceSpec   `json:"spec,omitempty"`
+	Status CloudMonitoringAlertSourceStatus `json:"status,omitempty"`
+}
+
+var (
+	_ apis.Convertible             = (*CloudMonitoringAlertSource)(nil)
+	_ apis.Defaultable             = (*CloudMonitoringAlertSource)(nil)
+	_ apis.Validatable             = (*CloudMonitoringAlertSource)(nil)
+	_ runtime.Object               = (*CloudMonitoringAlertSource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudMonitoringAlertSource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudMonitoringAlertSource)(nil)
+	_ kngcpduckv1.PubSubable       = (*CloudMonitoringAlertSource)(nil)
+	_ kngcpduckv1.Identifiable     = (*CloudMonitoringAlertSource)(nil)
+	_                              = duck.VerifyType(&CloudMonitoringAlertSource{}, &duckv1.Conditions{})
+	_ duckv1.KRShaped              = (*CloudMonitoringAlertSource)(nil)
+)
+
+// CloudMonitoringAlertSourceSpec defines the desired state of the CloudMonitoringAlertSource.
+type CloudMonitoringAlertSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret and Project.
+	gcpduckv1.PubSubSpec `json:",inline"`
+
+	// DisplayName is the display name of the notification channel shown
+	// in the Cloud Console when adding it to alerting policies. Defaults to
+	// the namespace and name of the CloudMonitoringAlertSource.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+const (
+	// CloudMonitoringAlertSourceConditionReady has status True when the
+	// CloudMonitoringAlertSource is ready to send events.
+	CloudMonitoringAlertSourceConditionReady = apis.ConditionReady
+
+	// NotificationChannelReady has status True when the Cloud Monitoring
+	// notification channel of the CloudMonitoringAlertSource has been
+	// successfully created.
+	NotificationChannelReady apis.ConditionType = "NotificationChannelReady"
+)
+
+var monitoringAlertCondSet = apis.NewLivingConditionSet(
+	gcpduckv1.PullSubscriptionReady,
+	gcpduckv1.TopicReady,
+	NotificationChannelReady,
+)
+
+// CloudMonitoringAlertSourceStatus defines the observed state of CloudMonitoringAlertSource.
+type CloudMonitoringAlertSourceStatus struct {
+	// This brings in our GCP PubSub based events importers
+	// duck/v1 Status, SinkURI, ProjectID, TopicID and SubscriptionID
+	gcpduckv1.PubSubStatus `json:",inline"`
+
+	// NotificationChannelName is the name of the created notification
+	// channel on success, for example
+	// projects/PROJECT_ID/notificationChannels/CHANNEL_ID. Add it to the
+	// alerting policies whose incidents are sent.
+	// +optional
+	NotificationChannelName string `json:"notificationChannelName,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudMonitoringAlertSourceList contains a list of CloudMonitoringAlertSources.
+type CloudMonitoringAlertSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudMonitoringAlertSource `json:"items"`
+}
+
+// Methods for pubsubable interface
+func (*CloudMonitoringAlertSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudMonitoringAlertSource")
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudMonitoringAlertSource) IdentitySpec() *gcpduckv1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudMonitoringAlertSource) IdentityStatus() *gcpduckv1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudMonitoringAlertSource) PubSubSpec() *gcpduckv1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudMonitoringAlertSource) PubSubStatus() *gcpduckv1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (s *CloudMonitoringAlertSource) ConditionSet() *apis.ConditionSet {
+	return &monitoringAlertCondSet
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*CloudMonitoringAlertSource) GetConditionSet() apis.ConditionSet {
+	return monitoringAlertCondSet
+}
+
+// GetStatus retrieves the status of the CloudMonitoringAlertSource. Implements the KRShaped interface.
+func (s *CloudMonitoringAlertSource) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
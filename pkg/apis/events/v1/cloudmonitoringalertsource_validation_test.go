@@ -0,0 +1,505 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	monitoringAlertSourceSpec = CloudMonitoringAlertSourceSpec{
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "secret-name",
+				},
+				Key: "secret-key",
+			},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			Project: "my-eventing-project",
+		},
+	}
+
+	monitoringAlertSourceSpecWithKSA = CloudMonitoringAlertSourceSpec{
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			IdentitySpec: gcpduckv1.IdentitySpec{
+				ServiceAccountName: "old-service-account",
+			},
+			Project: "my-eventing-project",
+		},
+	}
+)
+
+func TestCloudMonitoringAlertSourceCheckValidationFields(t *testing.T) {
+	testCases := map[string]struct {
+		spec  CloudMonitoringAlertSourceSpec
+		error bool
+	}{
+		"ok": {
+			spec:  monitoringAlertSourceSpec,
+			error: false,
+		},
+		"bad sink, name": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.Sink.Ref.Name = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, apiVersion": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.Sink.Ref.APIVersion = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, kind": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.Sink.Ref.Kind = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, empty": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri scheme": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Host: "example.com",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri host": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Scheme: "http",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri and ref": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Scheme: "http",
+						Host:   "example.com",
+					},
+					Ref: &duckv1.KReference{
+						Name: "foo",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"invalid secret, missing key": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.Secret = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "name",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"nil service account": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				return *obj
+			}(),
+			error: false,
+		},
+		"invalid k8s service account": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.ServiceAccountName = invalidServiceAccountName
+				return *obj
+			}(),
+			error: true,
+		},
+		"valid display name": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.DisplayName = "On-call incidents"
+				return *obj
+			}(),
+			error: false,
+		},
+		"have k8s service account and secret at the same time": {
+			spec: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.ServiceAccountName = validServiceAccountName
+				obj.Secret = &gcpauthtesthelper.Secret
+				return *obj
+			}(),
+			error: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.spec.Validate(context.TODO())
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestCloudMonitoringAlertSourceCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		orig              interface{}
+		updated           CloudMonitoringAlertSourceSpec
+		origAnnotation    map[string]string
+		updatedAnnotation map[string]string
+		allowed           bool
+	}{
+		"nil orig": {
+			updated: monitoringAlertSourceSpec,
+			allowed: true,
+		},
+		"ClusterName annotation changed": {
+			origAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "old",
+			},
+			updatedAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "new",
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation changed": {
+			origAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			updatedAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA + "new",
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation added": {
+			origAnnotation: map[string]string{},
+			updatedAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation deleted": {
+			origAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			updatedAnnotation: map[string]string{},
+			allowed:           false,
+		},
+		"DisplayName changed": {
+			orig: &monitoringAlertSourceSpec,
+			updated: func() CloudMonitoringAlertSourceSpec {
+				obj := monitoringAlertSourceSpec.DeepCopy()
+				obj.DisplayName = "On-call incidents"
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Secret.Name changed": {
+			orig: &monitoringAlertSourceSpec,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "some-other-name",
+						},
+						Key: monitoringAlertSourceSpec.Secret.Key,
+					},
+					Project: monitoringAlertSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: monitoringAlertSourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"Secret.Key changed": {
+			orig: &monitoringAlertSourceSpec,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: monitoringAlertSourceSpec.Secret.Name,
+						},
+						Key: "some-other-key",
+					},
+					Project: monitoringAlertSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: monitoringAlertSourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"Project changed": {
+			orig: &monitoringAlertSourceSpec,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: monitoringAlertSourceSpec.Secret.Name,
+						},
+						Key: monitoringAlertSourceSpec.Secret.Key,
+					},
+					Project: "some-other-project",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: monitoringAlertSourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"ServiceAccountName changed": {
+			orig: &monitoringAlertSourceSpecWithKSA,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					IdentitySpec: gcpduckv1.IdentitySpec{
+						ServiceAccountName: "new-service-account",
+					},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: monitoringAlertSourceSpecWithKSA.Sink,
+					},
+					Project: monitoringAlertSourceSpecWithKSA.Project,
+				},
+			},
+			allowed: false,
+		},
+		"ServiceAccountName added": {
+			orig: &monitoringAlertSourceSpec,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: monitoringAlertSourceSpec.Secret.Name,
+						},
+						Key: monitoringAlertSourceSpec.Secret.Key,
+					},
+					Project: monitoringAlertSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: monitoringAlertSourceSpec.Sink,
+					},
+					IdentitySpec: gcpduckv1.IdentitySpec{
+						ServiceAccountName: "old-service-account",
+					},
+				},
+			},
+			allowed: false,
+		},
+		"ClusterName annotation added": {
+			origAnnotation: nil,
+			updatedAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "new",
+			},
+			allowed: true,
+		},
+		"Sink.APIVersion changed": {
+			orig: &monitoringAlertSourceSpec,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: monitoringAlertSourceSpec.Secret.Name,
+						},
+						Key: monitoringAlertSourceSpec.Secret.Key,
+					},
+					Project: monitoringAlertSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "some-other-api-version",
+								Kind:       monitoringAlertSourceSpec.Sink.Ref.Kind,
+								Namespace:  monitoringAlertSourceSpec.Sink.Ref.Namespace,
+								Name:       monitoringAlertSourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Kind changed": {
+			orig: &monitoringAlertSourceSpec,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: monitoringAlertSourceSpec.Secret.Name,
+						},
+						Key: monitoringAlertSourceSpec.Secret.Key,
+					},
+					Project: monitoringAlertSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: monitoringAlertSourceSpec.Sink.Ref.APIVersion,
+								Kind:       "some-other-kind",
+								Namespace:  monitoringAlertSourceSpec.Sink.Ref.Namespace,
+								Name:       monitoringAlertSourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Namespace changed": {
+			orig: &monitoringAlertSourceSpec,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: monitoringAlertSourceSpec.Secret.Name,
+						},
+						Key: monitoringAlertSourceSpec.Secret.Key,
+					},
+					Project: monitoringAlertSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: monitoringAlertSourceSpec.Sink.Ref.APIVersion,
+								Kind:       monitoringAlertSourceSpec.Sink.Ref.Kind,
+								Namespace:  "some-other-namespace",
+								Name:       monitoringAlertSourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Name changed": {
+			orig: &monitoringAlertSourceSpec,
+			updated: CloudMonitoringAlertSourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: monitoringAlertSourceSpec.Secret.Name,
+						},
+						Key: monitoringAlertSourceSpec.Secret.Key,
+					},
+					Project: monitoringAlertSourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: monitoringAlertSourceSpec.Sink.Ref.APIVersion,
+								Kind:       monitoringAlertSourceSpec.Sink.Ref.Kind,
+								Namespace:  monitoringAlertSourceSpec.Sink.Ref.Namespace,
+								Name:       "some-other-name",
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"no change": {
+			orig:    &monitoringAlertSourceSpec,
+			updated: monitoringAlertSourceSpec,
+			allowed: true,
+		},
+		"no spec": {
+			orig:    []string{"wrong"},
+			updated: monitoringAlertSourceSpec,
+			allowed: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var orig *CloudMonitoringAlertSource
+
+			if tc.origAnnotation != nil {
+				orig = &CloudMonitoringAlertSource{
+					ObjectMeta: v1.ObjectMeta{
+						Annotations: tc.origAnnotation,
+					},
+				}
+			} else if tc.orig != nil {
+				if spec, ok := tc.orig.(*CloudMonitoringAlertSourceSpec); ok {
+					orig = &CloudMonitoringAlertSource{
+						Spec: *spec,
+					}
+				}
+			}
+			updated := &CloudMonitoringAlertSource{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: tc.updatedAnnotation,
+				},
+				Spec: tc.updated,
+			}
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
@@ -48,15 +48,17 @@ type CloudPubSubSource struct {
 
 // Verify that CloudPubSubSource matches various duck types.
 var (
-	_ apis.Convertible             = (*CloudPubSubSource)(nil)
-	_ apis.Defaultable             = (*CloudPubSubSource)(nil)
-	_ apis.Validatable             = (*CloudPubSubSource)(nil)
-	_ runtime.Object               = (*CloudPubSubSource)(nil)
-	_ kmeta.OwnerRefable           = (*CloudPubSubSource)(nil)
-	_ resourcesemantics.GenericCRD = (*CloudPubSubSource)(nil)
-	_ kngcpduck.Identifiable       = (*CloudPubSubSource)(nil)
-	_ kngcpduck.PubSubable         = (*CloudPubSubSource)(nil)
-	_ duckv1.KRShaped              = (*CloudPubSubSource)(nil)
+	_ apis.Convertible                 = (*CloudPubSubSource)(nil)
+	_ apis.Defaultable                 = (*CloudPubSubSource)(nil)
+	_ apis.Validatable                 = (*CloudPubSubSource)(nil)
+	_ runtime.Object                   = (*CloudPubSubSource)(nil)
+	_ kmeta.OwnerRefable               = (*CloudPubSubSource)(nil)
+	_ resourcesemantics.GenericCRD     = (*CloudPubSubSource)(nil)
+	_ kngcpduck.Identifiable           = (*CloudPubSubSource)(nil)
+	_ kngcpduck.PubSubable             = (*CloudPubSubSource)(nil)
+	_ kngcpduck.SubscriptionPolicyable = (*CloudPubSubSource)(nil)
+	_ kngcpduck.EventMappable          = (*CloudPubSubSource)(nil)
+	_ duckv1.KRShaped                  = (*CloudPubSubSource)(nil)
 )
 
 // CloudPubSubSourceSpec defines the desired state of the CloudPubSubSource.
@@ -89,6 +91,17 @@ type CloudPubSubSourceSpec struct {
 	// shorter than 10 minutes. Defaults to 7 days ('7d').
 	// +optional
 	RetentionDuration *string `json:"retentionDuration,omitempty"`
+
+	// This brings in the Filter, DeadLetterPolicy and RetryPolicy of the
+	// subscription, which can be changed after the CloudPubSubSource is created.
+	gcpduckv1.SubscriptionPolicySpec `json:",inline"`
+
+	// EventMapping declares how the CloudEvents are derived from the Pub/Sub
+	// messages, e.g. to consume a third-party feed whose messages describe
+	// events of their own. By default, the CloudEvents are of type
+	// 'google.cloud.pubsub.topic.v1.messagePublished' and hold the messages.
+	// +optional
+	EventMapping *gcpduckv1.EventMapping `json:"eventMapping,omitempty"`
 }
 
 // GetAckDeadline parses AckDeadline and returns the default if an error occurs.
@@ -169,6 +182,16 @@ func (s *CloudPubSubSource) PubSubStatus() *gcpduckv1.PubSubStatus {
 	return &s.Status.PubSubStatus
 }
 
+// SubscriptionPolicySpec returns the SubscriptionPolicySpec portion of the Spec.
+func (s *CloudPubSubSource) SubscriptionPolicySpec() *gcpduckv1.SubscriptionPolicySpec {
+	return &s.Spec.SubscriptionPolicySpec
+}
+
+// EventMapping returns the EventMapping of the Spec.
+func (s *CloudPubSubSource) EventMapping() *gcpduckv1.EventMapping {
+	return s.Spec.EventMapping
+}
+
 // GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
 func (*CloudPubSubSource) GetConditionSet() apis.ConditionSet {
 	return pubSubCondSet
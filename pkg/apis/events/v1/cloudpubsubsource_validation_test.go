@@ -114,6 +114,27 @@ func TestCloudPubSubSourceCheckValidationFields(t *testing.T) {
 			}(),
 			error: true,
 		},
+		"bad DeadLetterPolicy": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.DeadLetterPolicy = &gcpduckv1.DeadLetterPolicy{
+					Topic:               "dead-letter",
+					MaxDeliveryAttempts: ptr.Int32(1000),
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad RetryPolicy": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.RetryPolicy = &gcpduckv1.RetryPolicy{
+					MinimumBackoff: ptr.String("wrong"),
+				}
+				return *obj
+			}(),
+			error: true,
+		},
 		"bad AckDeadline": {
 			spec: func() CloudPubSubSourceSpec {
 				obj := pubSubSourceSpec.DeepCopy()
@@ -337,6 +358,21 @@ func TestCloudPubSubSourceCheckImmutableFields(t *testing.T) {
 			},
 			allowed: false,
 		},
+		"SubscriptionPolicySpec changed": {
+			orig: &pubSubSourceSpec,
+			updated: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Filter = `attributes.type = "order"`
+				obj.DeadLetterPolicy = &gcpduckv1.DeadLetterPolicy{
+					Topic: "dead-letter",
+				}
+				obj.RetryPolicy = &gcpduckv1.RetryPolicy{
+					MinimumBackoff: ptr.String("1s"),
+				}
+				return *obj
+			}(),
+			allowed: true,
+		},
 		"Project changed": {
 			orig: &pubSubSourceSpec,
 			updated: CloudPubSubSourceSpec{
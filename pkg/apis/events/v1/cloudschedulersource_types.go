@@ -58,6 +58,9 @@ var (
 const (
 	// CloudSchedulerSourceJobName is the Pub/Sub message attribute key with the CloudSchedulerSource's job name.
 	CloudSchedulerSourceJobName = "jobName"
+	// CloudSchedulerSourceDataContentType is the Pub/Sub message attribute key with the content
+	// type of the CloudSchedulerSource's data.
+	CloudSchedulerSourceDataContentType = "dataContentType"
 )
 
 // CloudSchedulerSourceSpec is the spec for a CloudSchedulerSource resource.
@@ -75,6 +78,61 @@ type CloudSchedulerSourceSpec struct {
 
 	// What data to send
 	Data string `json:"data"`
+
+	// DataContentType is the content type of Data, one of text/plain,
+	// application/json or application/octet-stream. Data must be a JSON
+	// document if it is application/json, and the base64 encoding of the
+	// payload if it is application/octet-stream. Defaults to text/plain.
+	// +optional
+	DataContentType string `json:"dataContentType,omitempty"`
+
+	// TimeZone is the time zone of Schedule, in the tz database
+	// (https://en.wikipedia.org/wiki/List_of_tz_database_time_zones),
+	// for example "America/New_York". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Attributes are added to the Pub/Sub messages published by the Job,
+	// and become extensions of the CloudEvents sent to the Sink. Their
+	// names must be valid CloudEvents extension names.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Paused pauses the Job while true.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// RetryConfig is the retry configuration of the Job. The defaults of
+	// Cloud Scheduler are used for the fields that are not set.
+	// +optional
+	RetryConfig *SchedulerRetryConfig `json:"retryConfig,omitempty"`
+}
+
+const (
+	// SchedulerDataContentTypeText is the content type of plain text Data.
+	SchedulerDataContentTypeText = "text/plain"
+	// SchedulerDataContentTypeJSON is the content type of JSON Data.
+	SchedulerDataContentTypeJSON = "application/json"
+	// SchedulerDataContentTypeBinary is the content type of base64 encoded binary Data.
+	SchedulerDataContentTypeBinary = "application/octet-stream"
+)
+
+// SchedulerRetryConfig is the retry configuration of a Cloud Scheduler Job.
+type SchedulerRetryConfig struct {
+	// RetryCount is the number of times the Job is retried when it fails to
+	// publish its message, between 0 and 5.
+	// +optional
+	RetryCount *int32 `json:"retryCount,omitempty"`
+
+	// MinBackoffDuration is the minimum time to wait before retrying the Job,
+	// as an ISO-8601 duration, for example PT5S.
+	// +optional
+	MinBackoffDuration *string `json:"minBackoffDuration,omitempty"`
+
+	// MaxBackoffDuration is the maximum time to wait before retrying the Job,
+	// as an ISO-8601 duration, for example PT1H.
+	// +optional
+	MaxBackoffDuration *string `json:"maxBackoffDuration,omitempty"`
 }
 
 const (
@@ -99,6 +157,15 @@ type CloudSchedulerSourceStatus struct {
 	// JobName is the name of the created scheduler Job on success.
 	// +optional
 	JobName string `json:"jobName,omitempty"`
+
+	// JobState is the state of the scheduler Job, for example ENABLED or
+	// PAUSED.
+	// +optional
+	JobState string `json:"jobState,omitempty"`
+
+	// RetryConfig is the retry configuration of the scheduler Job.
+	// +optional
+	RetryConfig *SchedulerRetryConfig `json:"retryConfig,omitempty"`
 }
 
 func (scheduler *CloudSchedulerSource) GetGroupVersionKind() schema.GroupVersionKind {
@@ -18,15 +18,27 @@ package v1
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	"github.com/rickb777/date/period"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+const (
+	// maxSchedulerRetryCount is the maximum retry count of a Cloud Scheduler Job.
+	maxSchedulerRetryCount = 5
+	// maxSchedulerBackoff is the maximum backoff duration of a Cloud Scheduler Job.
+	maxSchedulerBackoff = time.Hour
+)
+
 func (current *CloudSchedulerSource) Validate(ctx context.Context) *apis.FieldError {
 	errs := current.Spec.Validate(ctx).ViaField("spec")
 
@@ -34,6 +46,7 @@ func (current *CloudSchedulerSource) Validate(ctx context.Context) *apis.FieldEr
 		original := apis.GetBaseline(ctx).(*CloudSchedulerSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = duck.ValidateDeliveryModeAnnotation(current.Annotations, errs)
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -60,6 +73,22 @@ func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.Fie
 	// Data [required]
 	if current.Data == "" {
 		errs = errs.Also(apis.ErrMissingField("data"))
+	} else {
+		errs = errs.Also(validateSchedulerData(current.Data, current.DataContentType))
+	}
+
+	if current.TimeZone != "" {
+		if _, err := time.LoadLocation(current.TimeZone); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(current.TimeZone, "timeZone"))
+		}
+	}
+
+	for name := range current.Attributes {
+		errs = errs.Also(gcpduckv1.ValidateExtensionAttributeName(name).ViaKey(name).ViaField("attributes"))
+	}
+
+	if current.RetryConfig != nil {
+		errs = errs.Also(current.RetryConfig.Validate(ctx).ViaField("retryConfig"))
 	}
 
 	if err := duck.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
@@ -69,16 +98,74 @@ func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.Fie
 	return errs
 }
 
+func validateSchedulerData(data, contentType string) *apis.FieldError {
+	switch contentType {
+	case "", SchedulerDataContentTypeText:
+		return nil
+	case SchedulerDataContentTypeJSON:
+		if !json.Valid([]byte(data)) {
+			return &apis.FieldError{
+				Message: "data must be a JSON document if dataContentType is application/json",
+				Paths:   []string{"data"},
+			}
+		}
+		return nil
+	case SchedulerDataContentTypeBinary:
+		if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+			return &apis.FieldError{
+				Message: "data must be base64 encoded if dataContentType is application/octet-stream",
+				Paths:   []string{"data"},
+				Details: err.Error(),
+			}
+		}
+		return nil
+	default:
+		return apis.ErrInvalidValue(contentType, "dataContentType")
+	}
+}
+
+func (current *SchedulerRetryConfig) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if current.RetryCount != nil && (*current.RetryCount < 0 || *current.RetryCount > maxSchedulerRetryCount) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*current.RetryCount, 0, maxSchedulerRetryCount, "retryCount"))
+	}
+	minBackoff, minErr := validateSchedulerBackoff(current.MinBackoffDuration, "minBackoffDuration")
+	maxBackoff, maxErr := validateSchedulerBackoff(current.MaxBackoffDuration, "maxBackoffDuration")
+	errs = errs.Also(minErr, maxErr)
+	if minErr == nil && maxErr == nil && current.MinBackoffDuration != nil && current.MaxBackoffDuration != nil && minBackoff > maxBackoff {
+		errs = errs.Also(&apis.FieldError{
+			Message: "minBackoffDuration must not be greater than maxBackoffDuration",
+			Paths:   []string{"minBackoffDuration", "maxBackoffDuration"},
+		})
+	}
+	return errs
+}
+
+func validateSchedulerBackoff(backoff *string, field string) (time.Duration, *apis.FieldError) {
+	if backoff == nil {
+		return 0, nil
+	}
+	p, err := period.Parse(*backoff)
+	if err != nil {
+		return 0, apis.ErrInvalidValue(*backoff, field)
+	}
+	d, _ := p.Duration()
+	if d < 0 || d > maxSchedulerBackoff {
+		return 0, apis.ErrOutOfBoundsValue(*backoff, "PT0S", "PT1H", field)
+	}
+	return d, nil
+}
+
 func (current *CloudSchedulerSource) CheckImmutableFields(ctx context.Context, original *CloudSchedulerSource) *apis.FieldError {
 	if original == nil {
 		return nil
 	}
 
 	var errs *apis.FieldError
-	// Modification of Location, Schedule, Data, Secret, ServiceAccountName, Project are not allowed.
-	// Everything else is mutable.
+	// Modification of Location, Schedule, Data, DataContentType, TimeZone, Attributes, RetryConfig, Secret,
+	// ServiceAccountName, Project are not allowed. Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
-		cmpopts.IgnoreFields(CloudSchedulerSourceSpec{}, "Sink", "CloudEventOverrides")); diff != "" {
+		cmpopts.IgnoreFields(CloudSchedulerSourceSpec{}, "Sink", "CloudEventOverrides", "Paused")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
@@ -87,6 +174,8 @@ func (current *CloudSchedulerSource) CheckImmutableFields(ctx context.Context, o
 	}
 	// Modification of AutoscalingClassAnnotations is not allowed.
 	errs = duck.CheckImmutableAutoscalingClassAnnotations(&current.ObjectMeta, &original.ObjectMeta, errs)
+	// Modification of the DeliveryModeAnnotation is not allowed.
+	errs = duck.CheckImmutableDeliveryModeAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
 
 	// Modification of non-empty cluster name annotation is not allowed.
 	return duck.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
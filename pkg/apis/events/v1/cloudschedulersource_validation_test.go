@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
 )
 
 var (
@@ -372,6 +373,131 @@ func TestCloudSchedulerSourceSpecValidationFields(t *testing.T) {
 
 }
 
+func TestCloudSchedulerSourceSpecValidationOptionalFields(t *testing.T) {
+	testCases := []struct {
+		name    string
+		modify  func(*CloudSchedulerSourceSpec)
+		wantErr bool
+	}{{
+		name: "text data",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.DataContentType = SchedulerDataContentTypeText
+		},
+	}, {
+		name: "json data",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.DataContentType = SchedulerDataContentTypeJSON
+			s.Data = `{"job": "nightly"}`
+		},
+	}, {
+		name: "invalid json data",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.DataContentType = SchedulerDataContentTypeJSON
+			s.Data = `{"job": `
+		},
+		wantErr: true,
+	}, {
+		name: "binary data",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.DataContentType = SchedulerDataContentTypeBinary
+			s.Data = "AAECAw=="
+		},
+	}, {
+		name: "invalid binary data",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.DataContentType = SchedulerDataContentTypeBinary
+			s.Data = "not base64!"
+		},
+		wantErr: true,
+	}, {
+		name: "unknown data content type",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.DataContentType = "application/xml"
+		},
+		wantErr: true,
+	}, {
+		name: "time zone",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.TimeZone = "America/New_York"
+		},
+	}, {
+		name: "unknown time zone",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.TimeZone = "Mars/Olympus_Mons"
+		},
+		wantErr: true,
+	}, {
+		name: "attributes",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.Attributes = map[string]string{"team": "billing", "env": "prod"}
+		},
+	}, {
+		name: "attribute name with upper-case letters",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.Attributes = map[string]string{"Team": "billing"}
+		},
+		wantErr: true,
+	}, {
+		name: "attribute name too long",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.Attributes = map[string]string{"abcdefghijklmnopqrstu": "value"}
+		},
+		wantErr: true,
+	}, {
+		name: "reserved attribute name",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.Attributes = map[string]string{"source": "value"}
+		},
+		wantErr: true,
+	}, {
+		name: "retry config",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.RetryConfig = &SchedulerRetryConfig{
+				RetryCount:         ptr.Int32(3),
+				MinBackoffDuration: ptr.String("PT10S"),
+				MaxBackoffDuration: ptr.String("PT10M"),
+			}
+		},
+	}, {
+		name: "retry count out of bounds",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.RetryConfig = &SchedulerRetryConfig{RetryCount: ptr.Int32(6)}
+		},
+		wantErr: true,
+	}, {
+		name: "invalid backoff duration",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.RetryConfig = &SchedulerRetryConfig{MinBackoffDuration: ptr.String("10s")}
+		},
+		wantErr: true,
+	}, {
+		name: "backoff duration out of bounds",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.RetryConfig = &SchedulerRetryConfig{MaxBackoffDuration: ptr.String("PT2H")}
+		},
+		wantErr: true,
+	}, {
+		name: "min backoff duration greater than max backoff duration",
+		modify: func(s *CloudSchedulerSourceSpec) {
+			s.RetryConfig = &SchedulerRetryConfig{
+				MinBackoffDuration: ptr.String("PT10M"),
+				MaxBackoffDuration: ptr.String("PT1M"),
+			}
+		},
+		wantErr: true,
+	}}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			spec := minimalCloudSchedulerSourceSpec.DeepCopy()
+			test.modify(spec)
+			err := spec.Validate(context.TODO())
+			if test.wantErr != (err != nil) {
+				t.Errorf("Validate CloudSchedulerSourceSpec got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
 func TestCloudSchedulerSourceSpecCheckImmutableFields(t *testing.T) {
 	testCases := map[string]struct {
 		orig              interface{}
@@ -446,6 +572,28 @@ func TestCloudSchedulerSourceSpecCheckImmutableFields(t *testing.T) {
 			},
 			allowed: false,
 		},
+		"TimeZone changed": {
+			orig: &schedulerWithSecret,
+			updated: CloudSchedulerSourceSpec{
+				Location:   schedulerWithSecret.Location,
+				Schedule:   schedulerWithSecret.Schedule,
+				Data:       schedulerWithSecret.Data,
+				TimeZone:   "Europe/Paris",
+				PubSubSpec: schedulerWithSecret.PubSubSpec,
+			},
+			allowed: false,
+		},
+		"Paused changed": {
+			orig: &schedulerWithSecret,
+			updated: CloudSchedulerSourceSpec{
+				Location:   schedulerWithSecret.Location,
+				Schedule:   schedulerWithSecret.Schedule,
+				Data:       schedulerWithSecret.Data,
+				Paused:     true,
+				PubSubSpec: schedulerWithSecret.PubSubSpec,
+			},
+			allowed: true,
+		},
 		"Secret.Name changed": {
 			orig: &schedulerWithSecret,
 			updated: CloudSchedulerSourceSpec{
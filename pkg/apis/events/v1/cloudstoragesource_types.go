@@ -61,8 +61,19 @@ type CloudStorageSourceSpec struct {
 	// Sink, CloudEventOverrides, Secret and Project
 	gcpduckv1.PubSubSpec `json:",inline"`
 
-	// Bucket to subscribe to.
-	Bucket string `json:"bucket"`
+	// Bucket to subscribe to. Exactly one of Bucket, Buckets and BucketSelector must be set.
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// Buckets to subscribe to.
+	// +optional
+	Buckets []string `json:"buckets,omitempty"`
+
+	// BucketSelector selects the buckets of the project to subscribe to by their labels. The
+	// selector is resolved again on every resync, so that notifications are added to the
+	// buckets that start matching it, and removed from the buckets that stop matching it.
+	// +optional
+	BucketSelector *metav1.LabelSelector `json:"bucketSelector,omitempty"`
 
 	// EventTypes to subscribe to. If unspecified, then subscribe to all events.
 	// +optional
@@ -71,8 +82,27 @@ type CloudStorageSourceSpec struct {
 	// ObjectNamePrefix limits the notifications to objects with this prefix
 	// +optional
 	ObjectNamePrefix string `json:"objectNamePrefix,omitempty"`
+
+	// Attributes are custom attributes added to the notifications, which become extensions
+	// of the CloudEvents sent to the sink.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// PayloadFormat is the payload format of the notifications, either JSON_API_V1 or NONE.
+	// Notifications with no payload only carry the metadata of the objects in their
+	// attributes, and the CloudEvents sent to the sink have no data. Defaults to JSON_API_V1.
+	// +optional
+	PayloadFormat string `json:"payloadFormat,omitempty"`
 }
 
+const (
+	// CloudStorageSourcePayloadFormatJSON is the payload format of notifications holding the
+	// JSON representation of the objects.
+	CloudStorageSourcePayloadFormatJSON = "JSON_API_V1"
+	// CloudStorageSourcePayloadFormatNone is the payload format of notifications with no payload.
+	CloudStorageSourcePayloadFormatNone = "NONE"
+)
+
 const (
 	// CloudStorageSourceConditionReady has status True when the CloudStorageSource is ready to send events.
 	CloudStorageSourceConditionReady = apis.ConditionReady
@@ -93,9 +123,31 @@ type CloudStorageSourceStatus struct {
 	// duck/v1 Status, SinkURI, ProjectID, TopicID and SubscriptionID
 	gcpduckv1.PubSubStatus `json:",inline"`
 
-	// NotificationID is the ID that GCS identifies this notification as.
+	// NotificationID is the ID that GCS identifies this notification as. It is only set
+	// when the source subscribes to a single Bucket.
+	// +optional
+	NotificationID string `json:"notificationId,omitempty"`
+
+	// Notifications are the statuses of the notifications of each bucket.
+	// +optional
+	Notifications []BucketNotificationStatus `json:"notifications,omitempty"`
+}
+
+// BucketNotificationStatus is the status of the notification of a bucket.
+type BucketNotificationStatus struct {
+	// Bucket is the name of the bucket.
+	Bucket string `json:"bucket"`
+
+	// NotificationID is the ID that GCS identifies the notification of the bucket as.
 	// +optional
 	NotificationID string `json:"notificationId,omitempty"`
+
+	// Ready is true when the notification of the bucket exists.
+	Ready bool `json:"ready"`
+
+	// Message explains why the notification of the bucket is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 func (storage *CloudStorageSource) GetGroupVersionKind() schema.GroupVersionKind {
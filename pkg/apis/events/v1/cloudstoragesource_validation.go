@@ -20,12 +20,15 @@ import (
 	"context"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 )
 
 func (current *CloudStorageSource) Validate(ctx context.Context) *apis.FieldError {
@@ -35,6 +38,7 @@ func (current *CloudStorageSource) Validate(ctx context.Context) *apis.FieldErro
 		original := apis.GetBaseline(ctx).(*CloudStorageSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = duck.ValidateDeliveryModeAnnotation(current.Annotations, errs)
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -48,9 +52,17 @@ func (current *CloudStorageSourceSpec) Validate(ctx context.Context) *apis.Field
 		errs = errs.Also(err.ViaField("sink"))
 	}
 
-	// Bucket [required]
-	if current.Bucket == "" {
-		errs = errs.Also(apis.ErrMissingField("bucket"))
+	// Exactly one of Bucket, Buckets and BucketSelector [required]
+	errs = errs.Also(current.validateBuckets())
+
+	for name := range current.Attributes {
+		errs = errs.Also(gcpduckv1.ValidateExtensionAttributeName(name).ViaKey(name).ViaField("attributes"))
+	}
+
+	switch current.PayloadFormat {
+	case "", CloudStorageSourcePayloadFormatJSON, CloudStorageSourcePayloadFormatNone:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(current.PayloadFormat, "payloadFormat"))
 	}
 
 	if err := duck.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
@@ -60,13 +72,55 @@ func (current *CloudStorageSourceSpec) Validate(ctx context.Context) *apis.Field
 	return errs
 }
 
+func (current *CloudStorageSourceSpec) validateBuckets() *apis.FieldError {
+	var set []string
+	if current.Bucket != "" {
+		set = append(set, "bucket")
+	}
+	if len(current.Buckets) > 0 {
+		set = append(set, "buckets")
+	}
+	if current.BucketSelector != nil {
+		set = append(set, "bucketSelector")
+	}
+	switch len(set) {
+	case 0:
+		return apis.ErrMissingOneOf("bucket", "buckets", "bucketSelector")
+	case 1:
+	default:
+		return apis.ErrMultipleOneOf(set...)
+	}
+
+	var errs *apis.FieldError
+	seen := sets.NewString()
+	for i, bucket := range current.Buckets {
+		if bucket == "" {
+			errs = errs.Also(apis.ErrMissingField(apis.CurrentField).ViaFieldIndex("buckets", i))
+		} else if seen.Has(bucket) {
+			errs = errs.Also(apis.ErrGeneric("duplicate bucket", apis.CurrentField).ViaFieldIndex("buckets", i))
+		}
+		seen.Insert(bucket)
+	}
+	if current.BucketSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(current.BucketSelector); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: "invalid bucketSelector",
+				Paths:   []string{"bucketSelector"},
+				Details: err.Error(),
+			})
+		}
+	}
+	return errs
+}
+
 func (current *CloudStorageSource) CheckImmutableFields(ctx context.Context, original *CloudStorageSource) *apis.FieldError {
 	if original == nil {
 		return nil
 	}
 
 	var errs *apis.FieldError
-	// Modification of EventType, Secret, ServiceAccountName, Project, Bucket, PayloadFormat, EventType, ObjectNamePrefix are not allowed.
+	// Modification of EventType, Secret, ServiceAccountName, Project, Bucket, Buckets, BucketSelector, PayloadFormat,
+	// EventType, ObjectNamePrefix, Attributes are not allowed.
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudStorageSourceSpec{},
@@ -79,6 +133,8 @@ func (current *CloudStorageSource) CheckImmutableFields(ctx context.Context, ori
 	}
 	// Modification of AutoscalingClassAnnotations is not allowed.
 	errs = duck.CheckImmutableAutoscalingClassAnnotations(&current.ObjectMeta, &original.ObjectMeta, errs)
+	// Modification of the DeliveryModeAnnotation is not allowed.
+	errs = duck.CheckImmutableDeliveryModeAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
 
 	// Modification of non-empty cluster name annotation is not allowed.
 	return duck.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
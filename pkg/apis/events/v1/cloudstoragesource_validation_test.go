@@ -131,8 +131,8 @@ func TestValidationFields(t *testing.T) {
 		name: "empty",
 		s:    &CloudStorageSource{Spec: CloudStorageSourceSpec{}},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("spec.bucket", "spec.sink")
-			return fe
+			fe := apis.ErrMissingField("spec.sink")
+			return fe.Also(apis.ErrMissingOneOf("bucket", "buckets", "bucketSelector").ViaField("spec"))
 		}(),
 	}, {
 		name: "missing sink",
@@ -161,8 +161,8 @@ func TestSpecValidationFields(t *testing.T) {
 		name: "empty",
 		spec: &CloudStorageSourceSpec{},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("bucket", "sink")
-			return fe
+			fe := apis.ErrMissingField("sink")
+			return fe.Also(apis.ErrMissingOneOf("bucket", "buckets", "bucketSelector"))
 		}(),
 	}, {
 		name: "missing sink",
@@ -205,9 +205,83 @@ func TestSpecValidationFields(t *testing.T) {
 			},
 		},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("bucket")
+			fe := apis.ErrMissingOneOf("bucket", "buckets", "bucketSelector")
+			return fe
+		}(),
+	}, {
+		name: "bucket and buckets",
+		spec: &CloudStorageSourceSpec{
+			Bucket:     "my-test-bucket",
+			Buckets:    []string{"my-other-bucket"},
+			PubSubSpec: storageSourceSpec.PubSubSpec,
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMultipleOneOf("bucket", "buckets")
 			return fe
 		}(),
+	}, {
+		name: "empty and duplicate buckets",
+		spec: &CloudStorageSourceSpec{
+			Buckets:    []string{"my-test-bucket", "", "my-test-bucket"},
+			PubSubSpec: storageSourceSpec.PubSubSpec,
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField(apis.CurrentField).ViaFieldIndex("buckets", 1)
+			return fe.Also(apis.ErrGeneric("duplicate bucket", apis.CurrentField).ViaFieldIndex("buckets", 2))
+		}(),
+	}, {
+		name: "invalid bucket selector",
+		spec: &CloudStorageSourceSpec{
+			BucketSelector: &v1.LabelSelector{
+				MatchExpressions: []v1.LabelSelectorRequirement{{
+					Key:      "team",
+					Operator: "Unknown",
+				}},
+			},
+			PubSubSpec: storageSourceSpec.PubSubSpec,
+		},
+		want: func() *apis.FieldError {
+			fe := &apis.FieldError{
+				Message: "invalid bucketSelector",
+				Paths:   []string{"bucketSelector"},
+				Details: `"Unknown" is not a valid pod selector operator`,
+			}
+			return fe
+		}(),
+	}, {
+		name: "invalid attributes and payload format",
+		spec: &CloudStorageSourceSpec{
+			Bucket: "my-test-bucket",
+			Attributes: map[string]string{
+				"Team": "billing",
+				"type": "billing",
+			},
+			PayloadFormat: "XML",
+			PubSubSpec:    storageSourceSpec.PubSubSpec,
+		},
+		want: func() *apis.FieldError {
+			fe := &apis.FieldError{
+				Message: "attribute names must consist of at most 20 lower-case letters or digits",
+				Paths:   []string{"attributes[Team]"},
+			}
+			return fe.Also(&apis.FieldError{
+				Message: "attribute names must not be CloudEvents context attributes",
+				Paths:   []string{"attributes[type]"},
+			}, apis.ErrInvalidValue("XML", "payloadFormat"))
+		}(),
+	}, {
+		name: "valid bucket selector with attributes and no payload",
+		spec: &CloudStorageSourceSpec{
+			BucketSelector: &v1.LabelSelector{
+				MatchLabels: map[string]string{"team": "billing"},
+			},
+			Attributes: map[string]string{
+				"team": "billing",
+			},
+			PayloadFormat: CloudStorageSourcePayloadFormatNone,
+			PubSubSpec:    storageSourceSpec.PubSubSpec,
+		},
+		want: nil,
 	}, {
 		name: "invalid secret, missing name",
 		spec: &CloudStorageSourceSpec{
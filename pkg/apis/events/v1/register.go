@@ -45,10 +45,16 @@ var (
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&CloudArtifactRegistrySource{},
+		&CloudArtifactRegistrySourceList{},
 		&CloudAuditLogsSource{},
 		&CloudAuditLogsSourceList{},
 		&CloudBuildSource{},
 		&CloudBuildSourceList{},
+		&CloudBillingBudgetSource{},
+		&CloudBillingBudgetSourceList{},
+		&CloudMonitoringAlertSource{},
+		&CloudMonitoringAlertSourceList{},
 		&CloudPubSubSource{},
 		&CloudPubSubSourceList{},
 		&CloudSchedulerSource{},
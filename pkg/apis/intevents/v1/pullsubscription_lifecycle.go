@@ -85,6 +85,12 @@ func (s *PullSubscriptionStatus) MarkNoSubscription(reason, messageFormat string
 	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionSubscribed, reason, messageFormat, messageA...)
 }
 
+// MarkPushDeployed sets the condition that the data plane is deployed, as the messages of push
+// subscriptions are delivered by the shared push receiver.
+func (s *PullSubscriptionStatus) MarkPushDeployed() {
+	pullSubscriptionCondSet.Manage(s).MarkTrue(PullSubscriptionConditionDeployed)
+}
+
 func (s *PullSubscriptionStatus) MarkDeployedFailed(reason, messageFormat string, messageA ...interface{}) {
 	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionDeployed, reason, messageFormat, messageA...)
 }
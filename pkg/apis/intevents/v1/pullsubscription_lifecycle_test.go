@@ -273,6 +273,18 @@ func TestPullSubscriptionStatusIsReady(t *testing.T) {
 			}(),
 			wantConditionStatus: corev1.ConditionTrue,
 			want:                true,
+		}, {
+			name: "mark push deployed, sink, subscribed",
+			s: func() *PullSubscriptionStatus {
+				s := &PullSubscriptionStatus{}
+				s.InitializeConditions()
+				s.MarkPushDeployed()
+				s.MarkSubscribed("subID")
+				s.MarkSink(apis.HTTP("example"))
+				return s
+			}(),
+			wantConditionStatus: corev1.ConditionTrue,
+			want:                true,
 		}}
 
 	for _, test := range tests {
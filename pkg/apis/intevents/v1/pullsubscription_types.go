@@ -85,6 +85,25 @@ type PullSubscriptionSpec struct {
 	// +optional
 	RetentionDuration *string `json:"retentionDuration,omitempty"`
 
+	// This brings in the Filter, DeadLetterPolicy and RetryPolicy of the
+	// subscription, which can be changed after the PullSubscription is created.
+	v1.SubscriptionPolicySpec `json:",inline"`
+
+	// EventMapping declares how the CloudEvents are derived from the Pub/Sub
+	// messages. If set, it replaces the conversion of the AdapterType.
+	// +optional
+	EventMapping *v1.EventMapping `json:"eventMapping,omitempty"`
+
+	// ImageFilter selects the container image notifications that are converted
+	// by the receive adapter. The other messages are acknowledged and dropped.
+	// +optional
+	ImageFilter *v1.ImageFilter `json:"imageFilter,omitempty"`
+
+	// BudgetFilter selects the budget notifications that are converted by the
+	// receive adapter. The other messages are acknowledged and dropped.
+	// +optional
+	BudgetFilter *v1.BudgetFilter `json:"budgetFilter,omitempty"`
+
 	// Transformer is a reference to an object that will resolve to a domain
 	// name or a URI directly to use as the transformer or a URI directly.
 	// +optional
@@ -18,6 +18,7 @@ package v1
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/knative-gcp/pkg/apis/duck"
@@ -120,6 +121,22 @@ func TestPullSubscriptionCheckValidationFields(t *testing.T) {
 			}(),
 			error: true,
 		},
+		"bad Filter": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Filter = strings.Repeat("a", 1000)
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad DeadLetterPolicy": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.DeadLetterPolicy = &v1.DeadLetterPolicy{}
+				return *obj
+			}(),
+			error: true,
+		},
 		"bad AckDeadline": {
 			spec: func() PullSubscriptionSpec {
 				obj := pullSubscriptionSpec.DeepCopy()
@@ -328,6 +345,18 @@ func TestPullSubscriptionCheckImmutableFields(t *testing.T) {
 			},
 			allowed: false,
 		},
+		"SubscriptionPolicySpec changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Filter = `attributes.type = "order"`
+				obj.RetryPolicy = &v1.RetryPolicy{
+					MaximumBackoff: ptr.String("1m"),
+				}
+				return *obj
+			}(),
+			allowed: true,
+		},
 		"Project changed": {
 			orig: &pullSubscriptionSpec,
 			updated: PullSubscriptionSpec{
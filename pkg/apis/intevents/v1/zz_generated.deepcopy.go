@@ -21,10 +21,11 @@ limitations under the License.
 package v1
 
 import (
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	apis "knative.dev/pkg/apis"
-	duckv1 "knative.dev/pkg/apis/duck/v1"
+	apisduckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -102,9 +103,25 @@ func (in *PullSubscriptionSpec) DeepCopyInto(out *PullSubscriptionSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	in.SubscriptionPolicySpec.DeepCopyInto(&out.SubscriptionPolicySpec)
+	if in.EventMapping != nil {
+		in, out := &in.EventMapping, &out.EventMapping
+		*out = new(duckv1.EventMapping)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageFilter != nil {
+		in, out := &in.ImageFilter, &out.ImageFilter
+		*out = new(duckv1.ImageFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BudgetFilter != nil {
+		in, out := &in.BudgetFilter, &out.BudgetFilter
+		*out = new(duckv1.BudgetFilter)
+		**out = **in
+	}
 	if in.Transformer != nil {
 		in, out := &in.Transformer, &out.Transformer
-		*out = new(duckv1.Destination)
+		*out = new(apisduckv1.Destination)
 		(*in).DeepCopyInto(*out)
 	}
 	return
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// BrokerCellAnnotationKey is the annotation key used to assign a Broker or a Channel to a
+	// BrokerCell. Its value is the name of a BrokerCell in the system namespace. The webhook
+	// defaults it to DefaultBrokerCellName, and it can't be changed once the Broker or Channel is
+	// created. The same key is used in the status annotations of the Broker or Channel to report
+	// the BrokerCell serving it.
+	BrokerCellAnnotationKey = "events.cloud.google.com/brokercell"
+
+	// DefaultBrokerCellName is the name of the BrokerCell serving the Brokers and Channels that
+	// aren't assigned to another BrokerCell. It is the only BrokerCell created on demand, the
+	// other BrokerCells must be created by the cluster operator.
+	DefaultBrokerCellName = "default"
+)
+
+// BrokerCellGetter returns the BrokerCell with the given name from the system namespace.
+type BrokerCellGetter func(name string) (*BrokerCell, error)
+
+type brokerCellGetterKey struct{}
+
+// WithBrokerCellGetter returns a context whose BrokerCell annotations are validated against the
+// BrokerCells returned by getter.
+func WithBrokerCellGetter(ctx context.Context, getter BrokerCellGetter) context.Context {
+	return context.WithValue(ctx, brokerCellGetterKey{}, getter)
+}
+
+func brokerCellGetterFrom(ctx context.Context) BrokerCellGetter {
+	getter, _ := ctx.Value(brokerCellGetterKey{}).(BrokerCellGetter)
+	return getter
+}
+
+// AllowsNamespace returns true if the Brokers and Channels of the namespace can be assigned to
+// the BrokerCell.
+func (bc *BrokerCell) AllowsNamespace(namespace string) bool {
+	if len(bc.Spec.AllowedNamespaces) == 0 {
+		return true
+	}
+	for _, allowed := range bc.Spec.AllowedNamespaces {
+		if allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// BrokerCellNameOf returns the name of the BrokerCell that the Broker or Channel is assigned to.
+func BrokerCellNameOf(obj metav1.Object) string {
+	if name := obj.GetAnnotations()[BrokerCellAnnotationKey]; name != "" {
+		return name
+	}
+	// Brokers and Channels created before the webhook assigned them a BrokerCell are served by
+	// the default BrokerCell.
+	return DefaultBrokerCellName
+}
+
+// SetDefaultBrokerCell assigns the Broker or Channel to the default BrokerCell, unless it is
+// already assigned to one.
+func SetDefaultBrokerCell(obj metav1.Object) {
+	if _, ok := obj.GetAnnotations()[BrokerCellAnnotationKey]; ok {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[BrokerCellAnnotationKey] = DefaultBrokerCellName
+	obj.SetAnnotations(annotations)
+}
+
+// ValidateBrokerCellAnnotation verifies that the BrokerCellAnnotationKey annotation, if present,
+// is a valid BrokerCell name. When the context has a BrokerCellGetter, new Brokers and Channels
+// can only be assigned to existing BrokerCells that allow their namespace, or to the default
+// BrokerCell before it is created.
+func ValidateBrokerCellAnnotation(ctx context.Context, obj metav1.Object) *apis.FieldError {
+	name, ok := obj.GetAnnotations()[BrokerCellAnnotationKey]
+	if !ok {
+		return nil
+	}
+	// The BrokerCell name is used as the prefix of the names of its deployments and services.
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		fe := apis.ErrInvalidValue(name, apis.CurrentField)
+		fe.Details = strings.Join(errs, "; ")
+		return fe.ViaKey(BrokerCellAnnotationKey)
+	}
+	getter := brokerCellGetterFrom(ctx)
+	if getter == nil || !apis.IsInCreate(ctx) {
+		// The assignment can't change once created. The reconcilers stop serving Brokers and
+		// Channels whose namespace is no longer allowed by their BrokerCell.
+		return nil
+	}
+	bc, err := getter(name)
+	switch {
+	case apierrs.IsNotFound(err):
+		if name == DefaultBrokerCellName {
+			return nil
+		}
+		fe := apis.ErrInvalidValue(name, apis.CurrentField)
+		fe.Details = fmt.Sprintf("BrokerCell %q does not exist", name)
+		return fe.ViaKey(BrokerCellAnnotationKey)
+	case err != nil:
+		// Leave it to the reconcilers, which check the BrokerCell as well.
+		return nil
+	case !bc.AllowsNamespace(obj.GetNamespace()):
+		fe := apis.ErrInvalidValue(name, apis.CurrentField)
+		fe.Details = fmt.Sprintf("BrokerCell %q does not allow namespace %q", name, obj.GetNamespace())
+		return fe.ViaKey(BrokerCellAnnotationKey)
+	}
+	return nil
+}
+
+// CheckImmutableBrokerCellAnnotation checks that the Broker or Channel is still assigned to the
+// same BrokerCell, as moving it to another BrokerCell would change its address.
+func CheckImmutableBrokerCellAnnotation(current, original metav1.Object) *apis.FieldError {
+	if got, want := BrokerCellNameOf(current), BrokerCellNameOf(original); got != want {
+		return (&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{apis.CurrentField},
+			Details: fmt.Sprintf("-%q +%q", want, got),
+		}).ViaKey(BrokerCellAnnotationKey)
+	}
+	return nil
+}
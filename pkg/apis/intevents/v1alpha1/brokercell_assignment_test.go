@@ -0,0 +1,155 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestBrokerCellNameOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{{
+		name: "no annotations",
+		want: DefaultBrokerCellName,
+	}, {
+		name:        "empty brokercell",
+		annotations: map[string]string{BrokerCellAnnotationKey: ""},
+		want:        DefaultBrokerCellName,
+	}, {
+		name:        "assigned brokercell",
+		annotations: map[string]string{BrokerCellAnnotationKey: "dedicated"},
+		want:        "dedicated",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := BrokerCellNameOf(&metav1.ObjectMeta{Annotations: test.annotations}); got != test.want {
+				t.Errorf("BrokerCellNameOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSetDefaultBrokerCell(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+	}{{
+		name: "no annotations",
+		want: map[string]string{BrokerCellAnnotationKey: DefaultBrokerCellName},
+	}, {
+		name:        "other annotations",
+		annotations: map[string]string{"foo": "bar"},
+		want:        map[string]string{"foo": "bar", BrokerCellAnnotationKey: DefaultBrokerCellName},
+	}, {
+		name:        "assigned brokercell",
+		annotations: map[string]string{BrokerCellAnnotationKey: "dedicated"},
+		want:        map[string]string{BrokerCellAnnotationKey: "dedicated"},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: test.annotations}
+			SetDefaultBrokerCell(obj)
+			if diff := cmp.Diff(test.want, obj.Annotations); diff != "" {
+				t.Errorf("SetDefaultBrokerCell() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateBrokerCellAnnotationWithGetter(t *testing.T) {
+	cells := map[string]*BrokerCell{
+		DefaultBrokerCellName: {},
+		"dedicated":           {Spec: BrokerCellSpec{AllowedNamespaces: []string{"team-a"}}},
+	}
+	getter := func(name string) (*BrokerCell, error) {
+		if name == "broken" {
+			return nil, errors.New("lister failed")
+		}
+		if bc, ok := cells[name]; ok {
+			return bc, nil
+		}
+		return nil, apierrs.NewNotFound(Resource("brokercells"), name)
+	}
+	invalid := func(name, details string) *apis.FieldError {
+		fe := apis.ErrInvalidValue(name, apis.CurrentField)
+		fe.Details = details
+		return fe.ViaKey(BrokerCellAnnotationKey)
+	}
+	tests := []struct {
+		name      string
+		namespace string
+		cell      string
+		update    bool
+		want      *apis.FieldError
+	}{{
+		name:      "default brokercell",
+		namespace: "team-b",
+		cell:      DefaultBrokerCellName,
+	}, {
+		name:      "allowed namespace",
+		namespace: "team-a",
+		cell:      "dedicated",
+	}, {
+		name:      "disallowed namespace",
+		namespace: "team-b",
+		cell:      "dedicated",
+		want:      invalid("dedicated", `BrokerCell "dedicated" does not allow namespace "team-b"`),
+	}, {
+		name:      "disallowed namespace on update",
+		namespace: "team-b",
+		cell:      "dedicated",
+		update:    true,
+	}, {
+		name:      "missing brokercell",
+		namespace: "team-a",
+		cell:      "missing",
+		want:      invalid("missing", `BrokerCell "missing" does not exist`),
+	}, {
+		name:      "getter error",
+		namespace: "team-a",
+		cell:      "broken",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithBrokerCellGetter(context.Background(), getter)
+			obj := &metav1.ObjectMeta{
+				Namespace:   test.namespace,
+				Annotations: map[string]string{BrokerCellAnnotationKey: test.cell},
+			}
+			if test.update {
+				ctx = apis.WithinUpdate(ctx, obj)
+			} else {
+				ctx = apis.WithinCreate(ctx)
+			}
+			got := ValidateBrokerCellAnnotation(ctx, obj)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("ValidateBrokerCellAnnotation (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -48,6 +48,14 @@ const (
 	memoryLimitRetry      string = "1500Mi"
 	minReplicas           int32  = 1
 	maxReplicas           int32  = 10
+
+	defaultUndeliveredMessagesTarget     int64 = 100
+	defaultOldestUnackedMessageAgeTarget int64 = 60
+	defaultPollingIntervalSeconds        int32 = 15
+	defaultCooldownPeriodSeconds         int32 = 120
+	// The minimums match the ones of the KEDA PullSubscriptions.
+	minimumPollingIntervalSeconds int32 = 5
+	minimumCooldownPeriodSeconds  int32 = 15
 )
 
 // SetDefaults sets the default field values for a BrokerCell.
@@ -73,6 +81,33 @@ func (bcs *BrokerCellSpec) SetDefaults(ctx context.Context) {
 		bcs.Components.Retry = makeComponent(cpuRequestRetry, cpuLimitRetry, memoryRequestRetry, memoryLimitRetry, avgCPUUtilizationRetry, avgMemoryUsageRetry)
 	}
 	bcs.Components.Retry.setAutoScalingDefaults()
+	if bcs.Autoscaling != nil {
+		bcs.Autoscaling.setDefaults()
+	}
+}
+
+func (as *AutoscalingSpec) setDefaults() {
+	if as.Class == "" {
+		as.Class = ResourceAutoscalingClass
+	}
+	if bas := as.Backlog; bas != nil {
+		if bas.Metric == "" {
+			bas.Metric = UndeliveredMessagesBacklogMetric
+		}
+		if bas.Target == nil {
+			if bas.Metric == OldestUnackedMessageAgeBacklogMetric {
+				bas.Target = ptr.Int64(defaultOldestUnackedMessageAgeTarget)
+			} else {
+				bas.Target = ptr.Int64(defaultUndeliveredMessagesTarget)
+			}
+		}
+		if bas.PollingIntervalSeconds == nil {
+			bas.PollingIntervalSeconds = ptr.Int32(defaultPollingIntervalSeconds)
+		}
+		if bas.CooldownPeriodSeconds == nil {
+			bas.CooldownPeriodSeconds = ptr.Int32(defaultCooldownPeriodSeconds)
+		}
+	}
 }
 
 func makeComponent(cpuRequest, cpuLimit, memoryRequest, memoryLimit string, avgCPUUtilization int32, targetMemoryUsage string) *ComponentParameters {
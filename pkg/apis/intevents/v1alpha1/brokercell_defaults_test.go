@@ -67,7 +67,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 				},
 			},
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: &ComponentParameters{
 						CPURequest:        fanoutSpecPrefix + customCPURequest,
 						CPULimit:          fanoutSpecPrefix + customCPULimit,
@@ -108,7 +108,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 				},
 			},
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: &ComponentParameters{
 						CPURequest:        fanoutSpecPrefix + customCPURequest,
 						CPULimit:          fanoutSpecPrefix + customCPULimit,
@@ -146,7 +146,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 		name: "Defaulting for resource specification is not applied when some of the parameters are specified",
 		start: &BrokerCell{
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: &ComponentParameters{
 						CPURequest: "10000",
 					},
@@ -155,7 +155,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 		},
 		want: &BrokerCell{
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: (&ComponentParameters{
 						CPURequest:        "10000",
 						CPULimit:          "",
@@ -173,7 +173,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 		name: "Defaulting for resource specification is not applied when a target CPU or memory parameter is specified",
 		start: &BrokerCell{
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: &ComponentParameters{
 						AvgCPUUtilization: ptr.Int32(95),
 					},
@@ -182,7 +182,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 		},
 		want: &BrokerCell{
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: (&ComponentParameters{
 						AvgCPUUtilization: ptr.Int32(95),
 						AvgMemoryUsage:    nil,
@@ -196,6 +196,77 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "Autoscaling class defaults",
+		start: &BrokerCell{
+			Spec: BrokerCellSpec{
+				Autoscaling: &AutoscalingSpec{},
+			},
+		},
+		want: &BrokerCell{
+			Spec: (func() BrokerCellSpec {
+				spec := MakeDefaultBrokerCellSpec()
+				spec.Autoscaling = &AutoscalingSpec{Class: ResourceAutoscalingClass}
+				return spec
+			}()),
+		},
+	}, {
+		name: "Backlog autoscaling defaults",
+		start: &BrokerCell{
+			Spec: BrokerCellSpec{
+				Autoscaling: &AutoscalingSpec{
+					Class:   BacklogAutoscalingClass,
+					Backlog: &BacklogAutoscalingSpec{TriggerAuthentication: "keda-gcp"},
+				},
+			},
+		},
+		want: &BrokerCell{
+			Spec: (func() BrokerCellSpec {
+				spec := MakeDefaultBrokerCellSpec()
+				spec.Autoscaling = &AutoscalingSpec{
+					Class: BacklogAutoscalingClass,
+					Backlog: &BacklogAutoscalingSpec{
+						Metric:                 UndeliveredMessagesBacklogMetric,
+						Target:                 ptr.Int64(defaultUndeliveredMessagesTarget),
+						TriggerAuthentication:  "keda-gcp",
+						PollingIntervalSeconds: ptr.Int32(defaultPollingIntervalSeconds),
+						CooldownPeriodSeconds:  ptr.Int32(defaultCooldownPeriodSeconds),
+					},
+				}
+				return spec
+			}()),
+		},
+	}, {
+		name: "Backlog autoscaling target defaults to the metric's",
+		start: &BrokerCell{
+			Spec: BrokerCellSpec{
+				Autoscaling: &AutoscalingSpec{
+					Class: BacklogAutoscalingClass,
+					Backlog: &BacklogAutoscalingSpec{
+						Metric:                 OldestUnackedMessageAgeBacklogMetric,
+						TriggerAuthentication:  "keda-gcp",
+						PollingIntervalSeconds: ptr.Int32(30),
+						CooldownPeriodSeconds:  ptr.Int32(300),
+					},
+				},
+			},
+		},
+		want: &BrokerCell{
+			Spec: (func() BrokerCellSpec {
+				spec := MakeDefaultBrokerCellSpec()
+				spec.Autoscaling = &AutoscalingSpec{
+					Class: BacklogAutoscalingClass,
+					Backlog: &BacklogAutoscalingSpec{
+						Metric:                 OldestUnackedMessageAgeBacklogMetric,
+						Target:                 ptr.Int64(defaultOldestUnackedMessageAgeTarget),
+						TriggerAuthentication:  "keda-gcp",
+						PollingIntervalSeconds: ptr.Int32(30),
+						CooldownPeriodSeconds:  ptr.Int32(300),
+					},
+				}
+				return spec
+			}()),
+		},
 	}}
 
 	for _, test := range tests {
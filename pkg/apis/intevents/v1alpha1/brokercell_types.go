@@ -124,6 +124,146 @@ type BrokerCellSpec struct {
 	// Components specifies parameters of each component (fanout, ingress,
 	// retry) of a BrokerCell.
 	Components ComponentsParametersSpec `json:"components,omitempty"`
+
+	// Payload specifies the limits of the payloads of the events the ingress
+	// accepts, and how large payloads are handled.
+	// +optional
+	Payload *PayloadSpec `json:"payload,omitempty"`
+
+	// TargetsConfig specifies how the configuration of the Brokers and
+	// Channels of the BrokerCell is distributed to the data plane.
+	// +optional
+	TargetsConfig *TargetsConfigSpec `json:"targetsConfig,omitempty"`
+
+	// Autoscaling specifies the signal the fanout and retry deployments are
+	// scaled on. By default they are scaled on their CPU and memory usage.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+
+	// AllowedNamespaces lists the namespaces whose Brokers and Channels can
+	// be assigned to the BrokerCell. The BrokerCell serves all namespaces if
+	// it is empty.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// PubSubMaxMessageBytes is the message size limit of Pub/Sub.
+const PubSubMaxMessageBytes = 10000000
+
+// PayloadSpec specifies how a BrokerCell handles the payloads of events.
+type PayloadSpec struct {
+	// MaxSizeBytes is the maximum size of the events the ingress accepts.
+	// Larger events are rejected with 413. Defaults to 10000000 bytes, the
+	// message size limit of Pub/Sub. It can only be larger with ClaimCheck.
+	// +optional
+	MaxSizeBytes *int64 `json:"maxSizeBytes,omitempty"`
+
+	// ClaimCheck offloads the data of large events to a GCS bucket, so that
+	// the events published to Pub/Sub only carry a reference to their data.
+	// +optional
+	ClaimCheck *ClaimCheckSpec `json:"claimCheck,omitempty"`
+}
+
+// ClaimCheckSpec specifies the GCS bucket large payloads are offloaded to.
+type ClaimCheckSpec struct {
+	// Bucket is the name of the GCS bucket the data of large events is
+	// written to. The objects are not deleted once the events are delivered,
+	// so the bucket should have a lifecycle rule to delete them.
+	Bucket string `json:"bucket"`
+
+	// ThresholdBytes is the data size above which the data of events is
+	// offloaded. Defaults to 1000000 bytes.
+	// +optional
+	ThresholdBytes *int64 `json:"thresholdBytes,omitempty"`
+}
+
+// MaxTargetsConfigShards is the maximum number of ConfigMaps the targets config
+// of a BrokerCell can be split across.
+const MaxTargetsConfigShards = 32
+
+// TargetsConfigSpec specifies how the targets config of a BrokerCell is stored.
+type TargetsConfigSpec struct {
+	// Shards is the number of ConfigMaps the targets config is split across.
+	// Each Broker and Channel is stored in one of them, picked by the hash of
+	// its key. Defaults to 1. Each ConfigMap is limited to 1MiB, so the cells
+	// with many Triggers or Channel subscribers need more shards. Changing it
+	// rolls out the data plane.
+	// +optional
+	Shards *int32 `json:"shards,omitempty"`
+}
+
+// AutoscalingClass is the signal the fanout and retry deployments of a
+// BrokerCell are scaled on.
+type AutoscalingClass string
+
+const (
+	// ResourceAutoscalingClass scales the fanout and retry deployments with an
+	// HPA on the CPU and memory usage targeted in their ComponentParameters.
+	ResourceAutoscalingClass AutoscalingClass = "resource"
+
+	// BacklogAutoscalingClass scales the fanout and retry deployments with a
+	// KEDA ScaledObject on the backlog of the Pub/Sub subscriptions they pull
+	// from: the decouple subscriptions for fanout, the retry subscriptions for
+	// retry.
+	BacklogAutoscalingClass AutoscalingClass = "backlog"
+)
+
+// BacklogMetric is the Pub/Sub subscription metric the backlog is measured
+// with.
+type BacklogMetric string
+
+const (
+	// UndeliveredMessagesBacklogMetric is the number of undelivered messages.
+	UndeliveredMessagesBacklogMetric BacklogMetric = "UndeliveredMessages"
+
+	// OldestUnackedMessageAgeBacklogMetric is the age in seconds of the oldest
+	// unacked message.
+	OldestUnackedMessageAgeBacklogMetric BacklogMetric = "OldestUnackedMessageAge"
+)
+
+// AutoscalingSpec specifies how the fanout and retry deployments of a
+// BrokerCell are autoscaled. The ingress is always scaled on its CPU and
+// memory usage.
+type AutoscalingSpec struct {
+	// Class is the autoscaling class, either resource or backlog. Defaults to
+	// resource.
+	// +optional
+	Class AutoscalingClass `json:"class,omitempty"`
+
+	// Backlog configures the backlog class. It is required with it.
+	// +optional
+	Backlog *BacklogAutoscalingSpec `json:"backlog,omitempty"`
+}
+
+// BacklogAutoscalingSpec specifies how the fanout and retry deployments are
+// scaled on the backlog of their subscriptions. The minimum and maximum
+// replicas are still taken from their ComponentParameters.
+type BacklogAutoscalingSpec struct {
+	// Metric is the backlog metric, either UndeliveredMessages or
+	// OldestUnackedMessageAge. Defaults to UndeliveredMessages.
+	// +optional
+	Metric BacklogMetric `json:"metric,omitempty"`
+
+	// Target is the value of the metric targeted per replica: a number of
+	// messages for UndeliveredMessages, a number of seconds for
+	// OldestUnackedMessageAge. Defaults to 100 messages or 60 seconds.
+	// +optional
+	Target *int64 `json:"target,omitempty"`
+
+	// TriggerAuthentication is the name of the KEDA TriggerAuthentication, in
+	// the namespace of the BrokerCell, KEDA reads the metrics of the
+	// subscriptions from Cloud Monitoring with.
+	TriggerAuthentication string `json:"triggerAuthentication"`
+
+	// PollingIntervalSeconds is the interval KEDA polls the metrics at.
+	// Defaults to 15 seconds.
+	// +optional
+	PollingIntervalSeconds *int32 `json:"pollingIntervalSeconds,omitempty"`
+
+	// CooldownPeriodSeconds is the period KEDA waits after the last active
+	// trigger before scaling down. Defaults to 120 seconds.
+	// +optional
+	CooldownPeriodSeconds *int32 `json:"cooldownPeriodSeconds,omitempty"`
 }
 
 // BrokerCellStatus represents the current state of a BrokerCell.
@@ -19,9 +19,12 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 
 	resourceutil "github.com/google/knative-gcp/pkg/utils/resource"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -42,6 +45,94 @@ func (bcs *BrokerCellSpec) Validate(ctx context.Context) *apis.FieldError {
 	if bcs.Components.Retry != nil {
 		fieldErrors = bcs.Components.Retry.ValidateResourceRequirementSpecification(fieldErrors, "components.retry")
 	}
+	if bcs.Payload != nil {
+		fieldErrors = fieldErrors.Also(bcs.Payload.Validate(ctx).ViaField("payload"))
+	}
+	if bcs.TargetsConfig != nil {
+		fieldErrors = fieldErrors.Also(bcs.TargetsConfig.Validate(ctx).ViaField("targetsConfig"))
+	}
+	if bcs.Autoscaling != nil {
+		fieldErrors = fieldErrors.Also(bcs.Autoscaling.Validate(ctx).ViaField("autoscaling"))
+	}
+	for i, namespace := range bcs.AllowedNamespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			invalidValueError := apis.ErrInvalidArrayValue(namespace, "allowedNamespaces", i)
+			invalidValueError.Details = strings.Join(errs, "; ")
+			fieldErrors = fieldErrors.Also(invalidValueError)
+		}
+	}
+	return fieldErrors
+}
+
+func (as *AutoscalingSpec) Validate(ctx context.Context) *apis.FieldError {
+	var fieldErrors *apis.FieldError
+	switch as.Class {
+	case "", ResourceAutoscalingClass:
+	case BacklogAutoscalingClass:
+		if as.Backlog == nil {
+			fieldErrors = fieldErrors.Also(apis.ErrMissingField("backlog"))
+		}
+	default:
+		fieldErrors = fieldErrors.Also(apis.ErrInvalidValue(as.Class, "class"))
+	}
+	if as.Backlog != nil {
+		fieldErrors = fieldErrors.Also(as.Backlog.Validate(ctx).ViaField("backlog"))
+	}
+	return fieldErrors
+}
+
+func (bas *BacklogAutoscalingSpec) Validate(ctx context.Context) *apis.FieldError {
+	var fieldErrors *apis.FieldError
+	switch bas.Metric {
+	case "", UndeliveredMessagesBacklogMetric, OldestUnackedMessageAgeBacklogMetric:
+	default:
+		fieldErrors = fieldErrors.Also(apis.ErrInvalidValue(bas.Metric, "metric"))
+	}
+	if t := bas.Target; t != nil && *t < 1 {
+		fieldErrors = fieldErrors.Also(apis.ErrOutOfBoundsValue(*t, 1, math.MaxInt64, "target"))
+	}
+	if bas.TriggerAuthentication == "" {
+		fieldErrors = fieldErrors.Also(apis.ErrMissingField("triggerAuthentication"))
+	} else if errs := validation.IsDNS1123Subdomain(bas.TriggerAuthentication); len(errs) > 0 {
+		invalidValueError := apis.ErrInvalidValue(bas.TriggerAuthentication, "triggerAuthentication")
+		invalidValueError.Details = strings.Join(errs, "; ")
+		fieldErrors = fieldErrors.Also(invalidValueError)
+	}
+	if p := bas.PollingIntervalSeconds; p != nil && *p < minimumPollingIntervalSeconds {
+		fieldErrors = fieldErrors.Also(apis.ErrOutOfBoundsValue(*p, minimumPollingIntervalSeconds, math.MaxInt32, "pollingIntervalSeconds"))
+	}
+	if c := bas.CooldownPeriodSeconds; c != nil && *c < minimumCooldownPeriodSeconds {
+		fieldErrors = fieldErrors.Also(apis.ErrOutOfBoundsValue(*c, minimumCooldownPeriodSeconds, math.MaxInt32, "cooldownPeriodSeconds"))
+	}
+	return fieldErrors
+}
+
+func (tcs *TargetsConfigSpec) Validate(ctx context.Context) *apis.FieldError {
+	if s := tcs.Shards; s != nil && (*s < 1 || *s > MaxTargetsConfigShards) {
+		return apis.ErrOutOfBoundsValue(*s, 1, MaxTargetsConfigShards, "shards")
+	}
+	return nil
+}
+
+func (ps *PayloadSpec) Validate(ctx context.Context) *apis.FieldError {
+	var fieldErrors *apis.FieldError
+	if ps.MaxSizeBytes != nil {
+		if *ps.MaxSizeBytes <= 0 {
+			fieldErrors = fieldErrors.Also(apis.ErrOutOfBoundsValue(*ps.MaxSizeBytes, 1, math.MaxInt64, "maxSizeBytes"))
+		} else if ps.ClaimCheck == nil && *ps.MaxSizeBytes > PubSubMaxMessageBytes {
+			invalidValueError := apis.ErrInvalidValue(*ps.MaxSizeBytes, "maxSizeBytes")
+			invalidValueError.Details = fmt.Sprintf("maxSizeBytes can only exceed the message size limit of Pub/Sub (%d bytes) with claimCheck", PubSubMaxMessageBytes)
+			fieldErrors = fieldErrors.Also(invalidValueError)
+		}
+	}
+	if ps.ClaimCheck != nil {
+		if ps.ClaimCheck.Bucket == "" {
+			fieldErrors = fieldErrors.Also(apis.ErrMissingField("claimCheck.bucket"))
+		}
+		if t := ps.ClaimCheck.ThresholdBytes; t != nil && (*t <= 0 || *t >= PubSubMaxMessageBytes) {
+			fieldErrors = fieldErrors.Also(apis.ErrOutOfBoundsValue(*t, 1, PubSubMaxMessageBytes-1, "claimCheck.thresholdBytes"))
+		}
+	}
 	return fieldErrors
 }
 
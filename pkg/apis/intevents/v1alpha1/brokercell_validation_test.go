@@ -18,9 +18,12 @@ package v1alpha1
 
 import (
 	"context"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
 )
@@ -180,6 +183,174 @@ func TestBrokerCell_Validate(t *testing.T) {
 			},
 			want: nil,
 		},
+		{
+			name: "Valid payload with claim check",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Payload = &PayloadSpec{
+						MaxSizeBytes: ptr.Int64(100000000),
+						ClaimCheck: &ClaimCheckSpec{
+							Bucket:         "bucket",
+							ThresholdBytes: ptr.Int64(1000000),
+						},
+					}
+					return spec
+				}()),
+			},
+			want: nil,
+		},
+		{
+			name: "Payload max size must be positive",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Payload = &PayloadSpec{MaxSizeBytes: ptr.Int64(0)}
+					return spec
+				}()),
+			},
+			want: apis.ErrOutOfBoundsValue(0, 1, math.MaxInt64, "spec.payload.maxSizeBytes"),
+		},
+		{
+			name: "Payload max size above the Pub/Sub limit requires claim check",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Payload = &PayloadSpec{MaxSizeBytes: ptr.Int64(20000000)}
+					return spec
+				}()),
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue(20000000, "spec.payload.maxSizeBytes")
+				fe.Details = "maxSizeBytes can only exceed the message size limit of Pub/Sub (10000000 bytes) with claimCheck"
+				return fe
+			}(),
+		},
+		{
+			name: "Claim check requires a bucket and a threshold below the Pub/Sub limit",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Payload = &PayloadSpec{
+						ClaimCheck: &ClaimCheckSpec{ThresholdBytes: ptr.Int64(10000000)},
+					}
+					return spec
+				}()),
+			},
+			want: apis.ErrMissingField("spec.payload.claimCheck.bucket").Also(
+				apis.ErrOutOfBoundsValue(10000000, 1, 9999999, "spec.payload.claimCheck.thresholdBytes")),
+		},
+		{
+			name: "Valid targets config shards",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.TargetsConfig = &TargetsConfigSpec{Shards: ptr.Int32(4)}
+					return spec
+				}()),
+			},
+			want: nil,
+		},
+		{
+			name: "Targets config shards out of bounds",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.TargetsConfig = &TargetsConfigSpec{Shards: ptr.Int32(0)}
+					return spec
+				}()),
+			},
+			want: apis.ErrOutOfBoundsValue(0, 1, MaxTargetsConfigShards, "spec.targetsConfig.shards"),
+		},
+		{
+			name: "Valid allowed namespaces",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.AllowedNamespaces = []string{"team-a", "team-b"}
+					return spec
+				}()),
+			},
+			want: nil,
+		},
+		{
+			name: "Invalid allowed namespace",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.AllowedNamespaces = []string{"team-a", "Team_B"}
+					return spec
+				}()),
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidArrayValue("Team_B", "spec.allowedNamespaces", 1)
+				fe.Details = strings.Join(validation.IsDNS1123Label("Team_B"), "; ")
+				return fe
+			}(),
+		},
+		{
+			name: "Valid backlog autoscaling",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Autoscaling = &AutoscalingSpec{
+						Class: BacklogAutoscalingClass,
+						Backlog: &BacklogAutoscalingSpec{
+							Metric:                OldestUnackedMessageAgeBacklogMetric,
+							Target:                ptr.Int64(30),
+							TriggerAuthentication: "keda-gcp",
+						},
+					}
+					return spec
+				}()),
+			},
+			want: nil,
+		},
+		{
+			name: "Invalid autoscaling class",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Autoscaling = &AutoscalingSpec{Class: "cpu"}
+					return spec
+				}()),
+			},
+			want: apis.ErrInvalidValue("cpu", "spec.autoscaling.class"),
+		},
+		{
+			name: "Backlog autoscaling without backlog",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Autoscaling = &AutoscalingSpec{Class: BacklogAutoscalingClass}
+					return spec
+				}()),
+			},
+			want: apis.ErrMissingField("spec.autoscaling.backlog"),
+		},
+		{
+			name: "Invalid backlog autoscaling",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Autoscaling = &AutoscalingSpec{
+						Class: BacklogAutoscalingClass,
+						Backlog: &BacklogAutoscalingSpec{
+							Metric:                 "Throughput",
+							Target:                 ptr.Int64(0),
+							PollingIntervalSeconds: ptr.Int32(1),
+							CooldownPeriodSeconds:  ptr.Int32(1),
+						},
+					}
+					return spec
+				}()),
+			},
+			want: apis.ErrInvalidValue("Throughput", "spec.autoscaling.backlog.metric").Also(
+				apis.ErrOutOfBoundsValue(0, 1, math.MaxInt64, "spec.autoscaling.backlog.target"),
+				apis.ErrMissingField("spec.autoscaling.backlog.triggerAuthentication"),
+				apis.ErrOutOfBoundsValue(1, minimumPollingIntervalSeconds, math.MaxInt32, "spec.autoscaling.backlog.pollingIntervalSeconds"),
+				apis.ErrOutOfBoundsValue(1, minimumCooldownPeriodSeconds, math.MaxInt32, "spec.autoscaling.backlog.cooldownPeriodSeconds")),
+		},
 	}
 
 	for _, test := range tests {
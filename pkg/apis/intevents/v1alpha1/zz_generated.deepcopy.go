@@ -24,6 +24,58 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingSpec) DeepCopyInto(out *AutoscalingSpec) {
+	*out = *in
+	if in.Backlog != nil {
+		in, out := &in.Backlog, &out.Backlog
+		*out = new(BacklogAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingSpec.
+func (in *AutoscalingSpec) DeepCopy() *AutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BacklogAutoscalingSpec) DeepCopyInto(out *BacklogAutoscalingSpec) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PollingIntervalSeconds != nil {
+		in, out := &in.PollingIntervalSeconds, &out.PollingIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownPeriodSeconds != nil {
+		in, out := &in.CooldownPeriodSeconds, &out.CooldownPeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BacklogAutoscalingSpec.
+func (in *BacklogAutoscalingSpec) DeepCopy() *BacklogAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BacklogAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BrokerCell) DeepCopyInto(out *BrokerCell) {
 	*out = *in
@@ -89,6 +141,26 @@ func (in *BrokerCellList) DeepCopyObject() runtime.Object {
 func (in *BrokerCellSpec) DeepCopyInto(out *BrokerCellSpec) {
 	*out = *in
 	in.Components.DeepCopyInto(&out.Components)
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = new(PayloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetsConfig != nil {
+		in, out := &in.TargetsConfig, &out.TargetsConfig
+		*out = new(TargetsConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -119,6 +191,27 @@ func (in *BrokerCellStatus) DeepCopy() *BrokerCellStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimCheckSpec) DeepCopyInto(out *ClaimCheckSpec) {
+	*out = *in
+	if in.ThresholdBytes != nil {
+		in, out := &in.ThresholdBytes, &out.ThresholdBytes
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimCheckSpec.
+func (in *ClaimCheckSpec) DeepCopy() *ClaimCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentParameters) DeepCopyInto(out *ComponentParameters) {
 	*out = *in
@@ -186,6 +279,32 @@ func (in *ComponentsParametersSpec) DeepCopy() *ComponentsParametersSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PayloadSpec) DeepCopyInto(out *PayloadSpec) {
+	*out = *in
+	if in.MaxSizeBytes != nil {
+		in, out := &in.MaxSizeBytes, &out.MaxSizeBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ClaimCheck != nil {
+		in, out := &in.ClaimCheck, &out.ClaimCheck
+		*out = new(ClaimCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PayloadSpec.
+func (in *PayloadSpec) DeepCopy() *PayloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PayloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSpecification) DeepCopyInto(out *ResourceSpecification) {
 	*out = *in
@@ -219,3 +338,24 @@ func (in *SystemResource) DeepCopy() *SystemResource {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetsConfigSpec) DeepCopyInto(out *TargetsConfigSpec) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetsConfigSpec.
+func (in *TargetsConfigSpec) DeepCopy() *TargetsConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetsConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -21,6 +21,7 @@ import (
 
 	"knative.dev/pkg/apis"
 
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/apis/messaging/internal"
 	"knative.dev/eventing/pkg/apis/messaging"
 )
@@ -43,6 +44,8 @@ func (c *Channel) SetDefaults(ctx context.Context) {
 	if _, present := c.Annotations[messaging.SubscribableDuckVersionAnnotation]; !present {
 		c.Annotations[messaging.SubscribableDuckVersionAnnotation] = internal.StoredChannelVersion
 	}
+	// Assign the Channel to the default BrokerCell unless it names another one.
+	inteventsv1alpha1.SetDefaultBrokerCell(c)
 	c.Spec.SetDefaults(ctx)
 }
 
@@ -42,6 +42,7 @@ func TestChannelDefaults(t *testing.T) {
 			want: Channel{
 				ObjectMeta: v1.ObjectMeta{
 					Annotations: map[string]string{
+						"events.cloud.google.com/brokercell": "default",
 						"messaging.knative.dev/subscribable": "v1beta1",
 					},
 				},
@@ -59,6 +60,25 @@ func TestChannelDefaults(t *testing.T) {
 			want: Channel{
 				ObjectMeta: v1.ObjectMeta{
 					Annotations: map[string]string{
+						"events.cloud.google.com/brokercell": "default",
+						"messaging.knative.dev/subscribable": "v1beta1",
+					},
+				},
+				Spec: ChannelSpec{},
+			},
+		},
+		"with a brokercell annotation": {
+			in: Channel{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{
+						"events.cloud.google.com/brokercell": "dedicated",
+					},
+				},
+			},
+			want: Channel{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{
+						"events.cloud.google.com/brokercell": "dedicated",
 						"messaging.knative.dev/subscribable": "v1beta1",
 					},
 				},
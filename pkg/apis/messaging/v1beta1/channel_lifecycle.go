@@ -19,6 +19,8 @@ package v1beta1
 import (
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 )
 
 // GetCondition returns the condition currently associated with the given type,
@@ -57,6 +59,14 @@ func (cs *ChannelStatus) SetAddress(url *apis.URL) {
 	}
 }
 
+// SetBrokerCell reports the name of the BrokerCell serving this Channel in the status annotations.
+func (cs *ChannelStatus) SetBrokerCell(name string) {
+	if cs.Annotations == nil {
+		cs.Annotations = make(map[string]string, 1)
+	}
+	cs.Annotations[inteventsv1alpha1.BrokerCellAnnotationKey] = name
+}
+
 // MarkTopicReady sets the condition that the topic has been created and ready.
 func (cs *ChannelStatus) MarkTopicReady() {
 	channelCondSet.Manage(cs).MarkTrue(ChannelConditionTopicReady)
@@ -356,3 +356,12 @@ func UnknownTopicStatus() *v1beta1.TopicStatus {
 	ts.InitializeConditions()
 	return ts
 }
+
+func TestChannelSetBrokerCell(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.SetBrokerCell("dedicated")
+	want := map[string]string{"events.cloud.google.com/brokercell": "dedicated"}
+	if diff := cmp.Diff(want, cs.Annotations); diff != "" {
+		t.Errorf("unexpected status annotations (-want, +got) = %v", diff)
+	}
+}
@@ -23,11 +23,13 @@ import (
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 
 	"github.com/google/knative-gcp/pkg/apis/duck"
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"knative.dev/pkg/apis"
 )
 
 func (c *Channel) Validate(ctx context.Context) *apis.FieldError {
-	err := c.Spec.Validate(ctx).ViaField("spec")
+	err := c.Spec.Validate(ctx).ViaField("spec").
+		Also(inteventsv1alpha1.ValidateBrokerCellAnnotation(ctx, c).ViaField("metadata", "annotations"))
 
 	if apis.IsInUpdate(ctx) {
 		original := apis.GetBaseline(ctx).(*Channel)
@@ -66,6 +68,9 @@ func (current *Channel) CheckImmutableFields(ctx context.Context, original *Chan
 	// Modification of AutoscalingClassAnnotations is not allowed.
 	errs = duck.CheckImmutableAutoscalingClassAnnotations(&current.ObjectMeta, &original.ObjectMeta, errs)
 
+	// Modification of the BrokerCell annotation is not allowed.
+	errs = errs.Also(inteventsv1alpha1.CheckImmutableBrokerCellAnnotation(current, original).ViaField("metadata", "annotations"))
+
 	// Modification of non-empty cluster name annotation is not allowed.
 	return duck.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
 }
@@ -18,14 +18,19 @@ package v1beta1
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	pkgduckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/webhook/resourcesemantics"
+
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 )
 
 var (
@@ -117,6 +122,18 @@ func TestChannelValidation(t *testing.T) {
 			errs = errs.Also(fe.ViaField("spec.delivery.subscriber[0].deadLetterSink"))
 			return errs
 		}(),
+	}, {
+		name: "invalid brokercell annotation",
+		cr: &Channel{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{inteventsv1alpha1.BrokerCellAnnotationKey: "-dedicated"},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("-dedicated", apis.CurrentField)
+			fe.Details = strings.Join(validation.IsDNS1123Label("-dedicated"), "; ")
+			return fe.ViaKey(inteventsv1alpha1.BrokerCellAnnotationKey).ViaField("metadata", "annotations")
+		}(),
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -130,14 +147,29 @@ func TestChannelValidation(t *testing.T) {
 
 func TestCheckImmutableFields(t *testing.T) {
 	testCases := map[string]struct {
-		orig    interface{}
-		updated ChannelSpec
-		allowed bool
+		orig               interface{}
+		origAnnotations    map[string]string
+		updated            ChannelSpec
+		updatedAnnotations map[string]string
+		allowed            bool
 	}{
 		"nil orig": {
 			updated: ChannelSpec{},
 			allowed: true,
 		},
+		"defaulted brokercell": {
+			orig:               &ChannelSpec{},
+			updated:            ChannelSpec{},
+			updatedAnnotations: map[string]string{inteventsv1alpha1.BrokerCellAnnotationKey: inteventsv1alpha1.DefaultBrokerCellName},
+			allowed:            true,
+		},
+		"changed brokercell": {
+			orig:               &ChannelSpec{},
+			origAnnotations:    map[string]string{inteventsv1alpha1.BrokerCellAnnotationKey: inteventsv1alpha1.DefaultBrokerCellName},
+			updated:            ChannelSpec{},
+			updatedAnnotations: map[string]string{inteventsv1alpha1.BrokerCellAnnotationKey: "dedicated"},
+			allowed:            false,
+		},
 	}
 
 	for n, tc := range testCases {
@@ -147,12 +179,14 @@ func TestCheckImmutableFields(t *testing.T) {
 			if tc.orig != nil {
 				if spec, ok := tc.orig.(*ChannelSpec); ok {
 					orig = &Channel{
-						Spec: *spec,
+						ObjectMeta: metav1.ObjectMeta{Annotations: tc.origAnnotations},
+						Spec:       *spec,
 					}
 				}
 			}
 			updated := &Channel{
-				Spec: tc.updated,
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.updatedAnnotations},
+				Spec:       tc.updated,
 			}
 			err := updated.CheckImmutableFields(context.TODO(), orig)
 			if tc.allowed != (err == nil) {
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package claimcheck offloads the data of large events to a GCS bucket, so that the events
+// published to Pub/Sub only carry a reference to their data.
+package claimcheck
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
+)
+
+const (
+	// Extension is the CloudEvents extension holding the reference to the offloaded data of an
+	// event, in the form gs://<bucket>/<object>.
+	Extension = "knativeclaimcheck"
+
+	referencePrefix = "gs://"
+)
+
+// Offloader writes the data of events larger than a threshold to a GCS bucket.
+type Offloader struct {
+	// Client is the GCS client.
+	Client storage.Client
+	// Bucket is the bucket the data is written to.
+	Bucket string
+	// ThresholdBytes is the data size above which the data is offloaded.
+	ThresholdBytes int64
+}
+
+// Offload writes the data of the event to the bucket if it is larger than the threshold, and
+// replaces it with a reference to the object. It returns whether the data was offloaded.
+func (o *Offloader) Offload(ctx context.Context, broker *config.CellTenantKey, e *event.Event) (bool, error) {
+	data := e.Data()
+	if int64(len(data)) <= o.ThresholdBytes {
+		return false, nil
+	}
+	name := broker.PersistenceString() + "/" + uuid.New().String()
+	w := o.Client.Bucket(o.Bucket).Object(name).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return false, fmt.Errorf("failed to write event data to gs://%s/%s: %w", o.Bucket, name, err)
+	}
+	if err := w.Close(); err != nil {
+		return false, fmt.Errorf("failed to write event data to gs://%s/%s: %w", o.Bucket, name, err)
+	}
+	// Keep the content type, so that the data is delivered as it was received.
+	e.DataEncoded = nil
+	e.DataBase64 = false
+	e.SetExtension(Extension, referencePrefix+o.Bucket+"/"+name)
+	return true, nil
+}
+
+// Rehydrator reads the offloaded data of events back from GCS.
+type Rehydrator struct {
+	// Client is the GCS client.
+	Client storage.Client
+	// Bucket is the bucket the data is offloaded to. Data is only read from this bucket, under
+	// the prefix of the event's broker. If empty, the data of no event can be read back.
+	Bucket string
+}
+
+// Rehydrate returns the event of the broker with its offloaded data. The event is returned as is
+// if its data wasn't offloaded, and a copy with the data otherwise.
+func (r *Rehydrator) Rehydrate(ctx context.Context, broker *config.CellTenantKey, e *event.Event) (*event.Event, error) {
+	v, ok := e.Extensions()[Extension]
+	if !ok {
+		return e, nil
+	}
+	ref, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("claim check reference %v is not a string", v)
+	}
+	bucket, name, err := parseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	// Only read the data the ingress offloaded for the broker, so that the broker's identity
+	// isn't used to read other objects.
+	if bucket != r.Bucket || !strings.HasPrefix(name, broker.PersistenceString()+"/") {
+		return nil, fmt.Errorf("claim check reference %q is not in the bucket %q of broker %s", ref, r.Bucket, broker)
+	}
+	rc, err := r.Client.Bucket(bucket).Object(name).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event data from %s: %w", ref, err)
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event data from %s: %w", ref, err)
+	}
+
+	rehydrated := e.Clone()
+	rehydrated.DataEncoded = data
+	rehydrated.SetExtension(Extension, nil)
+	return &rehydrated, nil
+}
+
+func parseReference(ref string) (bucket, name string, err error) {
+	pieces := strings.SplitN(strings.TrimPrefix(ref, referencePrefix), "/", 2)
+	if !strings.HasPrefix(ref, referencePrefix) || len(pieces) != 2 || pieces[0] == "" || pieces[1] == "" {
+		return "", "", fmt.Errorf("malformed claim check reference %q, expect format 'gs://<bucket>/<object>'", ref)
+	}
+	return pieces[0], pieces[1], nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claimcheck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
+	gstoragetesting "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+)
+
+func newTestClient(t *testing.T, data gstoragetesting.TestBucketData) storage.Client {
+	t.Helper()
+	client, err := gstoragetesting.TestClientCreator(gstoragetesting.TestClientData{BucketData: data})(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func newTestEvent(t *testing.T, data string) *event.Event {
+	t.Helper()
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	// Events received over HTTP hold their data as is.
+	e.SetDataContentType("application/json")
+	e.DataEncoded = []byte(data)
+	return &e
+}
+
+func TestOffloadAndRehydrate(t *testing.T) {
+	ctx := context.Background()
+	objects := make(map[string][]byte)
+	client := newTestClient(t, gstoragetesting.TestBucketData{Objects: objects})
+	o := &Offloader{Client: client, Bucket: "bucket", ThresholdBytes: 10}
+	r := &Rehydrator{Client: client, Bucket: "bucket"}
+	broker := config.TestOnlyBrokerKey("ns", "broker")
+
+	small := newTestEvent(t, `{"a":1}`)
+	if offloaded, err := o.Offload(ctx, broker, small); err != nil || offloaded {
+		t.Fatalf("Offload of small event got=(%v, %v), want=(false, nil)", offloaded, err)
+	}
+	if got, err := r.Rehydrate(ctx, broker, small); err != nil || got != small {
+		t.Errorf("Rehydrate of event without claim check got=(%v, %v), want the event as is", got, err)
+	}
+
+	data := `{"message":"a large payload"}`
+	large := newTestEvent(t, data)
+	if offloaded, err := o.Offload(ctx, broker, large); err != nil || !offloaded {
+		t.Fatalf("Offload of large event got=(%v, %v), want=(true, nil)", offloaded, err)
+	}
+	if len(large.Data()) != 0 {
+		t.Errorf("offloaded event still has data %q", large.Data())
+	}
+	ref, _ := large.Extensions()[Extension].(string)
+	if !strings.HasPrefix(ref, "gs://bucket/ns/broker/") {
+		t.Errorf("claim check reference got=%q, want prefix gs://bucket/ns/broker/", ref)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("objects in bucket got=%d, want=1", len(objects))
+	}
+
+	got, err := r.Rehydrate(ctx, broker, large)
+	if err != nil {
+		t.Fatalf("unexpected error from Rehydrate: %v", err)
+	}
+	if diff := cmp.Diff(newTestEvent(t, data), got); diff != "" {
+		t.Errorf("unexpected rehydrated event (-want, +got) = %v", diff)
+	}
+	if _, ok := large.Extensions()[Extension]; !ok {
+		t.Error("Rehydrate modified the original event")
+	}
+}
+
+func TestOffloadWriteError(t *testing.T) {
+	wantErr := errors.New("write failed")
+	o := &Offloader{
+		Client: newTestClient(t, gstoragetesting.TestBucketData{WriteErr: wantErr}),
+		Bucket: "bucket",
+	}
+	e := newTestEvent(t, `{"a":1}`)
+	if _, err := o.Offload(context.Background(), config.TestOnlyBrokerKey("ns", "broker"), e); !errors.Is(err, wantErr) {
+		t.Errorf("Offload error got=%v, want=%v", err, wantErr)
+	}
+	if _, ok := e.Extensions()[Extension]; ok {
+		t.Error("event has a claim check reference after failing to offload")
+	}
+}
+
+func TestRehydrateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+	}{{
+		name: "malformed reference",
+		ref:  "bucket/object",
+	}, {
+		name: "missing object name",
+		ref:  "gs://bucket",
+	}, {
+		name: "object does not exist",
+		ref:  "gs://bucket/ns/broker/missing",
+	}, {
+		name: "object of another bucket",
+		ref:  "gs://other/ns/broker/object",
+	}, {
+		name: "object of another broker",
+		ref:  "gs://bucket/ns/other/object",
+	}, {
+		name: "object outside of the broker's prefix",
+		ref:  "gs://bucket/ns/broker",
+	}}
+	r := &Rehydrator{
+		Client: newTestClient(t, gstoragetesting.TestBucketData{Objects: map[string][]byte{
+			"ns/broker/object": []byte("data"),
+			"ns/other/object":  []byte("data"),
+			"ns/broker":        []byte("data"),
+		}}),
+		Bucket: "bucket",
+	}
+	broker := config.TestOnlyBrokerKey("ns", "broker")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newTestEvent(t, "")
+			e.SetExtension(Extension, tc.ref)
+			if _, err := r.Rehydrate(context.Background(), broker, e); err == nil {
+				t.Error("Rehydrate got no error")
+			}
+		})
+	}
+}
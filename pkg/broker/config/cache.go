@@ -108,10 +108,11 @@ func (ct *CachedTargets) RangeCellTenants(f func(*CellTenant) bool) {
 	}
 }
 
-// Bytes serializes all the targets.
+// Bytes serializes all the targets. The serialization is deterministic, so that equal targets
+// can be compared by their bytes.
 func (ct *CachedTargets) Bytes() ([]byte, error) {
 	val := ct.Load()
-	return proto.Marshal(val)
+	return proto.MarshalOptions{Deterministic: true}.Marshal(val)
 }
 
 // DebugString returns the text format of all the targets. It is for _debug_ purposes only. The
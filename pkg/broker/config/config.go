@@ -48,6 +48,12 @@ type CellTenantMutation interface {
 	SetDecoupleQueue(q *Queue) CellTenantMutation
 	// SetState sets the CellTenant's state.
 	SetState(s State) CellTenantMutation
+	// SetOrderingKeyAttribute sets the event attribute used as the ordering key of the
+	// CellTenant's events.
+	SetOrderingKeyAttribute(attr string) CellTenantMutation
+	// SetIngressPolicy sets the policy authenticating and authorizing the senders of the
+	// CellTenant's events.
+	SetIngressPolicy(p *IngressPolicy) CellTenantMutation
 	// UpsertTargets upserts Targets to the CellTenant.
 	// The targets' namespace, CellTenantType, and CellTenantName will be set to the CellTenant's
 	// value.
@@ -54,6 +54,18 @@ func (m *cellTenantMutation) SetState(s config.State) config.CellTenantMutation
 	return m
 }
 
+func (m *cellTenantMutation) SetOrderingKeyAttribute(attr string) config.CellTenantMutation {
+	m.delete = false
+	m.b.OrderingKeyAttribute = attr
+	return m
+}
+
+func (m *cellTenantMutation) SetIngressPolicy(p *config.IngressPolicy) config.CellTenantMutation {
+	m.delete = false
+	m.b.IngressPolicy = p
+	return m
+}
+
 func (m *cellTenantMutation) UpsertTargets(targets ...*config.Target) config.CellTenantMutation {
 	m.delete = false
 	if m.b.Targets == nil {
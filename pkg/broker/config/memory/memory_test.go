@@ -96,6 +96,28 @@ func TestMutateBroker(t *testing.T) {
 		assertBroker(t, wantBroker, targets)
 	})
 
+	t.Run("update broker ordering key attribute", func(t *testing.T) {
+		wantBroker.OrderingKeyAttribute = "partitionkey"
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetOrderingKeyAttribute("partitionkey")
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
+	t.Run("update broker ingress policy", func(t *testing.T) {
+		policy := &config.IngressPolicy{
+			Issuers: []*config.IngressIssuer{{
+				Issuer:    "https://kubernetes.default.svc",
+				Audiences: []string{"broker"},
+			}},
+		}
+		wantBroker.IngressPolicy = policy
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetIngressPolicy(policy)
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
 	t1 := &config.Target{
 		Id:             "uid-1",
 		Address:        "consumer1.example.com",
@@ -176,6 +198,8 @@ func TestMutateBroker(t *testing.T) {
 			m.Delete()
 			// Then make some changes which should "recreate" the broker.
 			m.SetID("b-uid").SetAddress("external.broker.example.com").SetState(config.State_READY)
+			m.SetOrderingKeyAttribute("partitionkey")
+			m.SetIngressPolicy(wantBroker.IngressPolicy)
 			m.SetDecoupleQueue(&config.Queue{
 				Topic:        "topic",
 				Subscription: "sub",
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"hash/fnv"
+)
+
+// Shard returns the index of the shard the CellTenant with the given persistence key is stored
+// in, out of the given number of shards.
+func Shard(cellTenantKey string, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(cellTenantKey))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// SplitTargetsConfig splits the CellTenants of the TargetsConfig into the given number of
+// shards. Every shard is returned, even if it is empty, so that stale shards are overwritten.
+func SplitTargetsConfig(tc *TargetsConfig, shards int) []*TargetsConfig {
+	if shards < 1 {
+		shards = 1
+	}
+	split := make([]*TargetsConfig, shards)
+	for i := range split {
+		split[i] = &TargetsConfig{CellTenants: make(map[string]*CellTenant)}
+	}
+	for key, ct := range tc.GetCellTenants() {
+		split[Shard(key, shards)].CellTenants[key] = ct
+	}
+	return split
+}
+
+// MergeTargetsConfigs merges the CellTenants of the shards into a single TargetsConfig.
+func MergeTargetsConfigs(shards ...*TargetsConfig) *TargetsConfig {
+	merged := &TargetsConfig{CellTenants: make(map[string]*CellTenant)}
+	for _, shard := range shards {
+		for key, ct := range shard.GetCellTenants() {
+			merged.CellTenants[key] = ct
+		}
+	}
+	return merged
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestSplitAndMergeTargetsConfig(t *testing.T) {
+	tc := &TargetsConfig{CellTenants: make(map[string]*CellTenant)}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("ns/broker-%d", i)
+		tc.CellTenants[key] = &CellTenant{Id: key, Name: fmt.Sprintf("broker-%d", i), Namespace: "ns"}
+	}
+
+	for _, shards := range []int{0, 1, 4} {
+		t.Run(fmt.Sprintf("%d shards", shards), func(t *testing.T) {
+			split := SplitTargetsConfig(tc, shards)
+			wantLen := shards
+			if wantLen < 1 {
+				wantLen = 1
+			}
+			if len(split) != wantLen {
+				t.Fatalf("Unexpected number of shards, got %d, want %d", len(split), wantLen)
+			}
+			for i, shard := range split {
+				if wantLen > 1 && len(shard.CellTenants) == len(tc.CellTenants) {
+					t.Errorf("Shard %d has all the CellTenants", i)
+				}
+				for key := range shard.CellTenants {
+					if got := Shard(key, wantLen); got != i {
+						t.Errorf("CellTenant %q is in shard %d, want %d", key, i, got)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc, MergeTargetsConfigs(split...), protocmp.Transform()); diff != "" {
+				t.Errorf("Unexpected merged TargetsConfig (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitEmptyTargetsConfig(t *testing.T) {
+	split := SplitTargetsConfig(&TargetsConfig{}, 3)
+	if len(split) != 3 {
+		t.Fatalf("Unexpected number of shards, got %d, want 3", len(split))
+	}
+	for i, shard := range split {
+		if shard == nil || len(shard.CellTenants) != 0 {
+			t.Errorf("Shard %d is not empty: %v", i, shard)
+		}
+	}
+}
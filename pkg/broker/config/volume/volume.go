@@ -21,6 +21,8 @@ import (
 	"io/ioutil"
 	"log"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/google/knative-gcp/pkg/broker/config"
@@ -31,9 +33,20 @@ const (
 	defaultPath = "/var/run/events-system/broker/targets"
 )
 
+// ShardPath returns the path of the file the given shard of the targets config
+// is loaded from. The first shard is loaded from the path itself, and the
+// others from "<path>-<shard>".
+func ShardPath(path string, shard int) string {
+	if shard == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s-%d", path, shard)
+}
+
 // Targets implements config.ReadonlyTargets with data
-// loaded from a file.
-// It also watches the file for any changes and will automatically
+// loaded from a file, and from the files of the other
+// shards of the targets config next to it, if any.
+// It also watches the files for any changes and will automatically
 // refresh the in memory cache.
 type Targets struct {
 	config.CachedTargets
@@ -91,7 +104,7 @@ func (t *Targets) watchWith(watcher *fsnotify.Watcher) error {
 				// Re-sync if the file was updated/created or
 				// if the real file was replaced.
 				const writeOrCreateMask = fsnotify.Write | fsnotify.Create
-				if (filepath.Clean(event.Name) == configFile &&
+				if (isConfigFile(configFile, filepath.Clean(event.Name)) &&
 					event.Op&writeOrCreateMask != 0) ||
 					(currentConfigFile != "" && currentConfigFile != realConfigFile) {
 					realConfigFile = currentConfigFile
@@ -114,21 +127,63 @@ func (t *Targets) watchWith(watcher *fsnotify.Watcher) error {
 	return nil
 }
 
+// isConfigFile returns true if the file is the config file or the file of
+// another shard.
+func isConfigFile(configFile, file string) bool {
+	if file == configFile {
+		return true
+	}
+	_, ok := shardOf(configFile, file)
+	return ok
+}
+
+// shardOf returns the shard of the file, if it is a shard of the config file
+// other than the first.
+func shardOf(configFile, file string) (int, bool) {
+	suffix := strings.TrimPrefix(file, configFile+"-")
+	if suffix == file {
+		return 0, false
+	}
+	shard, err := strconv.Atoi(suffix)
+	return shard, err == nil && shard > 0
+}
+
 func (t *Targets) sync() error {
-	b, err := t.readFile()
+	files, err := t.shardFiles()
 	if err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
+		return fmt.Errorf("failed to list config files: %w", err)
 	}
-
-	var val config.TargetsConfig
-	if err := proto.Unmarshal(b, &val); err != nil {
-		return fmt.Errorf("failed to unmarshal config file: %w", err)
+	shards := make([]*config.TargetsConfig, 0, len(files))
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		var val config.TargetsConfig
+		if err := proto.Unmarshal(b, &val); err != nil {
+			return fmt.Errorf("failed to unmarshal config file %s: %w", f, err)
+		}
+		shards = append(shards, &val)
 	}
 
-	t.Store(&val)
+	// Store all the shards at once, so that the data plane never sees a partial config.
+	t.Store(config.MergeTargetsConfigs(shards...))
 	return nil
 }
 
-func (t *Targets) readFile() ([]byte, error) {
-	return ioutil.ReadFile(t.path)
+// shardFiles returns the config file followed by the files of the other
+// shards that exist.
+func (t *Targets) shardFiles() ([]string, error) {
+	configFile := filepath.Clean(t.path)
+	matches, err := filepath.Glob(configFile + "-*")
+	if err != nil {
+		return nil, err
+	}
+	files := []string{configFile}
+	for _, m := range matches {
+		if _, ok := shardOf(configFile, m); ok {
+			files = append(files, m)
+		}
+	}
+	return files, nil
 }
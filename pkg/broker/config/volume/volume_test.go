@@ -17,8 +17,10 @@ limitations under the License.
 package volume
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -181,6 +183,72 @@ func TestSyncConfigFromFile(t *testing.T) {
 	}
 }
 
+func TestSyncShardedConfigFromFiles(t *testing.T) {
+	data := &config.TargetsConfig{CellTenants: make(map[string]*config.CellTenant)}
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("broker%d", i)
+		data.CellTenants["ns/"+name] = &config.CellTenant{
+			Id:        "b-uid-" + name,
+			Address:   name + ".ns.example.com",
+			Name:      name,
+			Type:      config.CellTenantType_BROKER,
+			Namespace: "ns",
+			State:     config.State_READY,
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "configtest-*")
+	if err != nil {
+		t.Fatalf("unexpected error from creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "targets")
+	shards := config.SplitTargetsConfig(data, 3)
+	for i, shard := range shards {
+		b, _ := proto.Marshal(shard)
+		if err := ioutil.WriteFile(ShardPath(path, i), b, 0644); err != nil {
+			t.Fatalf("unexpected error from writing config file: %v", err)
+		}
+	}
+	// Files that are not shards of the config are ignored.
+	if err := ioutil.WriteFile(path+"-backup", []byte("not a config"), 0644); err != nil {
+		t.Fatalf("unexpected error from writing file: %v", err)
+	}
+
+	ch := make(chan struct{}, 1)
+
+	targets, err := NewTargetsFromFile(WithPath(path), WithNotifyChan(ch))
+	if err != nil {
+		t.Fatalf("unexpected error from NewTargetsFromFile: %v", err)
+	}
+
+	gotTargets := targets.(*Targets).Load()
+	if !proto.Equal(data, gotTargets) {
+		t.Errorf("initial targets got=%+v, want=%+v", gotTargets, data)
+	}
+
+	// Update a CellTenant of the last shard.
+	var key string
+	for k := range shards[2].CellTenants {
+		key = k
+		break
+	}
+	data.CellTenants[key].State = config.State_UNKNOWN
+	b, _ := proto.Marshal(config.SplitTargetsConfig(data, 3)[2])
+	atomicWriteFile(t, ShardPath(path, 2), b)
+
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timeout waiting for the notification")
+	}
+
+	gotTargets = targets.(*Targets).Load()
+	if !proto.Equal(data, gotTargets) {
+		t.Errorf("updated targets got=%+v, want=%+v", gotTargets, data)
+	}
+}
+
 func atomicWriteFile(t *testing.T, file string, bytes []byte) {
 	t.Helper()
 	// In order to more closely replicate how K8s writes ConfigMaps to the file system, we will
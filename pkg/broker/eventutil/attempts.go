@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// AttemptsAttribute counts the failed delivery attempts of an event to a
+	// single target. It is only set on events in a target's retry queue and is
+	// removed before the event is delivered.
+	// Intentionally make it short because the additional attribute
+	// increases Pubsub message size and could incur additional cost.
+	AttemptsAttribute = "kgcpattempts"
+
+	// ArrivalTimeAttribute holds the time the event was received by the broker ingress. The
+	// format is an RFC3339 time in string format.
+	ArrivalTimeAttribute = "knativearrivaltime"
+)
+
+// GetDeliveryAttempts returns the number of failed delivery attempts recorded in the event.
+// If there is no existing value or an invalid one, 0 is returned.
+func GetDeliveryAttempts(ctx context.Context, event *event.Event) int32 {
+	raw, ok := event.Extensions()[AttemptsAttribute]
+	if !ok {
+		return 0
+	}
+	attempts, err := cetypes.ToInteger(raw)
+	if err != nil || attempts < 0 {
+		logging.FromContext(ctx).Warn("Failed to convert existing delivery attempts value into integer, regarding it as there is no delivery attempts value.",
+			zap.String("event.id", event.ID()),
+			zap.Any(AttemptsAttribute, raw),
+			zap.Error(err),
+		)
+		return 0
+	}
+	return attempts
+}
+
+// SetDeliveryAttempts records the number of failed delivery attempts in the event.
+func SetDeliveryAttempts(event *event.Event, attempts int32) {
+	event.SetExtension(AttemptsAttribute, attempts)
+}
+
+// GetArrivalTime returns the time the event was received by the broker ingress. It returns false
+// if the event has no valid arrival time.
+func GetArrivalTime(event *event.Event) (time.Time, bool) {
+	raw, ok := event.Extensions()[ArrivalTimeAttribute]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := cetypes.ToTime(raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
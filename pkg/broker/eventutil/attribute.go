@@ -0,0 +1,61 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+)
+
+// AttributeValue returns the string form of the named context attribute or extension of the
+// event. It returns false if the event does not have the attribute or its value is empty.
+func AttributeValue(e *event.Event, name string) (string, bool) {
+	var v string
+	switch name {
+	case "specversion":
+		v = e.SpecVersion()
+	case "id":
+		v = e.ID()
+	case "type":
+		v = e.Type()
+	case "source":
+		v = e.Source()
+	case "subject":
+		v = e.Subject()
+	case "dataschema":
+		v = e.DataSchema()
+	case "datacontenttype":
+		v = e.DataContentType()
+	case "time":
+		if !e.Time().IsZero() {
+			v = e.Time().Format(time.RFC3339Nano)
+		}
+	default:
+		raw, ok := e.Extensions()[name]
+		if !ok {
+			return "", false
+		}
+		s, err := cetypes.Format(raw)
+		if err != nil {
+			return "", false
+		}
+		v = s
+	}
+	return v, v != ""
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestAttributeValue(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetType("type")
+	e.SetSource("source")
+	e.SetSubject("subject")
+	e.SetExtension("partitionkey", "key")
+	e.SetExtension("sequence", 3)
+
+	tests := []struct {
+		name   string
+		attr   string
+		want   string
+		wantOK bool
+	}{{
+		name:   "context attribute",
+		attr:   "subject",
+		want:   "subject",
+		wantOK: true,
+	}, {
+		name:   "string extension",
+		attr:   "partitionkey",
+		want:   "key",
+		wantOK: true,
+	}, {
+		name:   "integer extension",
+		attr:   "sequence",
+		want:   "3",
+		wantOK: true,
+	}, {
+		name: "unset context attribute",
+		attr: "dataschema",
+	}, {
+		name: "missing extension",
+		attr: "missing",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := AttributeValue(&e, tc.attr)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("AttributeValue(%q) got (%q, %v), want (%q, %v)", tc.attr, got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
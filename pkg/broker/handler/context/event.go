@@ -0,0 +1,71 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// The key used to store/retrieve the original event in the context.
+type originalEventKey struct{}
+
+// The key used to store/retrieve the publish time of the event in the context.
+type publishTimeKey struct{}
+
+// The key used to store/retrieve the delivery attempt of the event in the context.
+type deliveryAttemptKey struct{}
+
+// WithOriginalEvent sets the event as it was before being transformed in the context.
+func WithOriginalEvent(ctx context.Context, e *event.Event) context.Context {
+	return context.WithValue(ctx, originalEventKey{}, e)
+}
+
+// GetOriginalEvent gets the event as it was before being transformed from the context. If the
+// event wasn't transformed, the given event is returned.
+func GetOriginalEvent(ctx context.Context, e *event.Event) *event.Event {
+	if original, ok := ctx.Value(originalEventKey{}).(*event.Event); ok {
+		return original
+	}
+	return e
+}
+
+// WithPublishTime sets the time the event's Pub/Sub message was published in the context.
+func WithPublishTime(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, publishTimeKey{}, t)
+}
+
+// GetPublishTime gets the time the event's Pub/Sub message was published from the context.
+func GetPublishTime(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(publishTimeKey{}).(time.Time)
+	return t, ok
+}
+
+// WithDeliveryAttempt sets the number of times the event's Pub/Sub message was delivered in the
+// context.
+func WithDeliveryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, deliveryAttemptKey{}, attempt)
+}
+
+// GetDeliveryAttempt gets the number of times the event's Pub/Sub message was delivered from the
+// context. Pub/Sub only counts the deliveries of subscriptions with a dead letter policy.
+func GetDeliveryAttempt(ctx context.Context) (int, bool) {
+	attempt, ok := ctx.Value(deliveryAttemptKey{}).(int)
+	return attempt, ok
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestOriginalEvent(t *testing.T) {
+	transformed := event.New()
+	if got := GetOriginalEvent(context.Background(), &transformed); got != &transformed {
+		t.Errorf("GetOriginalEvent without original got=%v, want=%v", got, &transformed)
+	}
+	original := event.New()
+	ctx := WithOriginalEvent(context.Background(), &original)
+	if got := GetOriginalEvent(ctx, &transformed); got != &original {
+		t.Errorf("GetOriginalEvent got=%v, want=%v", got, &original)
+	}
+}
+
+func TestPublishTime(t *testing.T) {
+	if _, ok := GetPublishTime(context.Background()); ok {
+		t.Error("GetPublishTime without publish time got ok")
+	}
+	want := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got, ok := GetPublishTime(WithPublishTime(context.Background(), want)); !ok || !got.Equal(want) {
+		t.Errorf("GetPublishTime got=(%v, %v), want=%v", got, ok, want)
+	}
+}
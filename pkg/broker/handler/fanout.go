@@ -34,6 +34,7 @@ import (
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/fanout"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/transform"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
@@ -57,6 +58,9 @@ type FanoutPool struct {
 	// For sending retry events. We only need a shared client.
 	// And we can set retry topic dynamically.
 	deliverRetryClient ceclient.Client
+	// For publishing events with an ordering key to the retry topics of
+	// targets of ordered brokers.
+	orderedRetryTopics *deliver.OrderedRetryTopics
 	// For initial events delivery. We only need a shared client.
 	// And we can set target address dynamically.
 	deliverClient *http.Client
@@ -117,6 +121,7 @@ func NewFanoutPool(
 		options:            options,
 		pool:               &syncMapBrokerKey{},
 		pubsubClient:       pubsubClient,
+		orderedRetryTopics: deliver.NewOrderedRetryTopics(pubsubClient),
 		deliverClient:      deliverClient,
 		deliverRetryClient: retryClient,
 		statsReporter:      statsReporter,
@@ -164,13 +169,17 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 			processors.ChainProcessors(
 				&fanout.Processor{MaxConcurrency: p.options.MaxConcurrencyPerEvent, Targets: p.targets},
 				&filter.Processor{Targets: p.targets},
+				&transform.Processor{Targets: p.targets, Rehydrator: p.options.Rehydrator},
 				&deliver.Processor{
 					DeliverClient:      p.deliverClient,
 					Targets:            p.targets,
 					RetryOnFailure:     true,
 					DeliverRetryClient: p.deliverRetryClient,
+					OrderedRetryTopics: p.orderedRetryTopics,
 					DeliverTimeout:     p.options.DeliveryTimeout,
 					StatsReporter:      p.statsReporter,
+					CircuitBreakers:    p.options.CircuitBreakers,
+					Rehydrator:         p.options.Rehydrator,
 				},
 			),
 			p.options.TimeoutPerEvent,
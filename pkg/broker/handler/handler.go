@@ -25,6 +25,7 @@ import (
 	"cloud.google.com/go/pubsub"
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	"github.com/google/knative-gcp/pkg/logging"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -90,6 +91,10 @@ func (h *Handler) IsAlive() bool {
 // receive converts message to events and invoke processor chain.
 func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
 	ctx = metrics.StartEventProcessing(ctx)
+	ctx = handlerctx.WithPublishTime(ctx, msg.PublishTime)
+	if msg.DeliveryAttempt != nil {
+		ctx = handlerctx.WithDeliveryAttempt(ctx, *msg.DeliveryAttempt)
+	}
 	event, err := binding.ToEvent(ctx, cepubsub.NewMessage(msg))
 	if isNonRetryable(err) {
 		logEventConversionError(ctx, msg, err, "failed to convert received message to an event, check the msg format")
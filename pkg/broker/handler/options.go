@@ -21,6 +21,9 @@ import (
 	"time"
 
 	"cloud.google.com/go/pubsub"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 )
 
 var (
@@ -50,6 +53,12 @@ type Options struct {
 	DeliveryTimeout time.Duration
 	// PubsubReceiveSettings is the pubsub receive settings.
 	PubsubReceiveSettings pubsub.ReceiveSettings
+	// CircuitBreakers holds the circuit breakers of the targets.
+	// If nil, deliveries are never short-circuited.
+	CircuitBreakers *deliver.CircuitBreakers
+	// Rehydrator reads the data of events offloaded to GCS back before delivery.
+	// If nil, such events are delivered with the reference to their data.
+	Rehydrator *claimcheck.Rehydrator
 }
 
 // NewOptions creates a Options.
@@ -107,3 +116,17 @@ func WithDeliveryTimeout(t time.Duration) Option {
 		o.DeliveryTimeout = t
 	}
 }
+
+// WithCircuitBreakers sets the CircuitBreakers.
+func WithCircuitBreakers(cb *deliver.CircuitBreakers) Option {
+	return func(o *Options) {
+		o.CircuitBreakers = cb
+	}
+}
+
+// WithRehydrator sets the Rehydrator.
+func WithRehydrator(r *claimcheck.Rehydrator) Option {
+	return func(o *Options) {
+		o.Rehydrator = r
+	}
+}
@@ -22,6 +22,9 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 )
 
 func TestWithHandlerConcurrency(t *testing.T) {
@@ -96,3 +99,25 @@ func TestWithDeliveryTimeout(t *testing.T) {
 		t.Errorf("options timeout per event got=%v, want=%v", opt.DeliveryTimeout, want)
 	}
 }
+
+func TestWithCircuitBreakers(t *testing.T) {
+	want := deliver.NewCircuitBreakers(5, time.Minute)
+	opt, err := NewOptions(WithCircuitBreakers(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.CircuitBreakers != want {
+		t.Errorf("options circuit breakers got=%v, want=%v", opt.CircuitBreakers, want)
+	}
+}
+
+func TestWithRehydrator(t *testing.T) {
+	want := &claimcheck.Rehydrator{}
+	opt, err := NewOptions(WithRehydrator(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.Rehydrator != want {
+		t.Errorf("options rehydrator got=%v, want=%v", opt.Rehydrator, want)
+	}
+}
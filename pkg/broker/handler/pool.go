@@ -45,6 +45,21 @@ type probeChecker struct {
 	maxStaleDuration time.Duration
 	port             int
 	authCheck        authcheck.AuthenticationCheck
+	// handlers are served in addition to the health check.
+	handlers map[string]http.Handler
+}
+
+// ProbeOption configures the probe checker of a sync pool.
+type ProbeOption func(*probeChecker)
+
+// WithProbeHandler serves h at path on the probe check port.
+func WithProbeHandler(path string, h http.Handler) ProbeOption {
+	return func(c *probeChecker) {
+		if c.handlers == nil {
+			c.handlers = make(map[string]http.Handler)
+		}
+		c.handlers[path] = h
+	}
 }
 
 func (c *probeChecker) reportHealth() {
@@ -80,6 +95,10 @@ func (c *probeChecker) start(ctx context.Context) {
 }
 
 func (c *probeChecker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h, ok := c.handlers[req.URL.Path]; ok {
+		h.ServeHTTP(w, req)
+		return
+	}
 	if req.URL.Path != "/healthz" {
 		w.WriteHeader(http.StatusNotFound)
 		return
@@ -110,6 +129,7 @@ func StartSyncPool(
 	maxStaleDuration time.Duration,
 	probeCheckPort int,
 	authCheck authcheck.AuthenticationCheck,
+	opts ...ProbeOption,
 ) (SyncPool, error) {
 
 	if err := syncPool.SyncOnce(ctx); err != nil {
@@ -121,6 +141,9 @@ func StartSyncPool(
 		port:             probeCheckPort,
 		authCheck:        authCheck,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
 	go c.start(ctx)
 	if syncSignal != nil {
 		go watch(ctx, syncPool, syncSignal, c)
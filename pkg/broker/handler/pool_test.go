@@ -84,6 +84,34 @@ func TestSyncPool(t *testing.T) {
 		// False because it exceeds StaleDuration.
 		assertProbeCheckResult(t, p, false, "healthz")
 	})
+
+	t.Run("StartSyncPool serves probe handlers", func(t *testing.T) {
+		syncPool := &fakeSyncPool{
+			returnErr:  false,
+			syncCalled: make(chan struct{}, 1),
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		p, err := GetFreePort()
+		if err != nil {
+			t.Fatalf("failed to get random free port: %v", err)
+		}
+
+		h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		if _, err := StartSyncPool(ctx, syncPool, nil, 0, p, &authcheck.FakeAuthenticationCheck{}, WithProbeHandler("/extra", h)); err != nil {
+			t.Errorf("StartSyncPool got unexpected error: %v", err)
+		}
+		syncPool.verifySyncOnceCalled(t)
+		// Make sure the probe checker is up.
+		time.Sleep(500 * time.Millisecond)
+
+		assertProbeCheckResult(t, p, true, "healthz")
+		assertProbeCheckResult(t, p, true, "extra")
+		assertProbeCheckResult(t, p, false, "empty")
+	})
 }
 
 func assertProbeCheckResult(t *testing.T, port int, ok bool, path string) {
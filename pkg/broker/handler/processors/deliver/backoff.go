@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+const (
+	// maxBackoff caps the delay between retries. It is the same as the largest
+	// maximum backoff allowed by the Pub/Sub retry policy.
+	maxBackoff = 600 * time.Second
+
+	// jitterFactor is the fraction of the backoff that is randomly added or
+	// subtracted, so that events failing at the same time don't all get retried
+	// at the same time.
+	jitterFactor = 0.2
+
+	// defaultBackoffDelay is the base delay of targets without a backoff delay. It is the same as
+	// the minimum backoff of the retry subscriptions of such targets.
+	defaultBackoffDelay = time.Second
+)
+
+// backoff returns the delay before retrying an event whose delivery has failed
+// attempts times since it entered the retry queue.
+func backoff(spec *config.DeliverySpec, attempts int32) time.Duration {
+	if attempts <= 0 {
+		return 0
+	}
+	delay := defaultBackoffDelay
+	if spec.GetBackoffDelay() != nil {
+		delay = spec.GetBackoffDelay().AsDuration()
+	}
+	if delay <= 0 {
+		return 0
+	}
+	switch spec.GetBackoffPolicy() {
+	case config.BackoffPolicy_LINEAR:
+		delay *= time.Duration(attempts)
+	default:
+		// Don't shift past the cap to avoid overflows.
+		for i := int32(1); i < attempts && delay < maxBackoff; i++ {
+			delay *= 2
+		}
+	}
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	jitter := time.Duration(float64(delay) * jitterFactor * (2*rand.Float64() - 1))
+	if delay += jitter; delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// retryDelay returns the backoff delay before retrying an event whose delivery
+// has failed attempts times, or the delay requested by the subscriber's
+// Retry-After if it is longer.
+func retryDelay(spec *config.DeliverySpec, attempts int32, deliveryErr error) time.Duration {
+	delay := backoff(spec, attempts)
+	var derr *deliveryError
+	if errors.As(deliveryErr, &derr) && derr.retryAfter > delay {
+		delay = derr.retryAfter
+	}
+	return delay
+}
+
+// requeueTime returns how long the handler can wait from now before it must
+// requeue an event to avoid timing out. It returns false if ctx has no deadline.
+func requeueTime(ctx context.Context, now time.Time) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return deadline.Sub(now) - requeueCushion, true
+}
+
+// sleep waits for delay on clk. It returns false if ctx is done first.
+func sleep(ctx context.Context, clk clock.Clock, delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+	timer := clk.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// retryAfter returns the delay requested by the Retry-After header of a 429 or
+// 503 response, or 0 if there is none. The header is either a number of seconds
+// or an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		delay = date.Sub(now)
+	}
+	if delay < 0 {
+		return 0
+	}
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		name     string
+		spec     *config.DeliverySpec
+		attempts int32
+		want     time.Duration
+	}{{
+		name:     "no delivery spec",
+		attempts: 1,
+		want:     defaultBackoffDelay,
+	}, {
+		name:     "no backoff delay",
+		spec:     &config.DeliverySpec{},
+		attempts: 3,
+		want:     4 * defaultBackoffDelay,
+	}, {
+		name:     "zero backoff delay",
+		spec:     &config.DeliverySpec{BackoffDelay: durationpb.New(0)},
+		attempts: 1,
+	}, {
+		name:     "no attempts",
+		spec:     &config.DeliverySpec{BackoffDelay: durationpb.New(time.Second)},
+		attempts: 0,
+	}, {
+		name:     "exponential first attempt",
+		spec:     &config.DeliverySpec{BackoffDelay: durationpb.New(time.Second)},
+		attempts: 1,
+		want:     time.Second,
+	}, {
+		name:     "exponential",
+		spec:     &config.DeliverySpec{BackoffDelay: durationpb.New(time.Second)},
+		attempts: 4,
+		want:     8 * time.Second,
+	}, {
+		name: "linear",
+		spec: &config.DeliverySpec{
+			BackoffDelay:  durationpb.New(time.Second),
+			BackoffPolicy: config.BackoffPolicy_LINEAR,
+		},
+		attempts: 4,
+		want:     4 * time.Second,
+	}, {
+		name:     "exponential capped",
+		spec:     &config.DeliverySpec{BackoffDelay: durationpb.New(time.Second)},
+		attempts: 100,
+		want:     maxBackoff,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			jitter := time.Duration(float64(tc.want) * jitterFactor)
+			for i := 0; i < 10; i++ {
+				got := backoff(tc.spec, tc.attempts)
+				if got < tc.want-jitter || got > tc.want+jitter || got > maxBackoff {
+					t.Errorf("backoff got=%v, want=%v±%v", got, tc.want, jitter)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name       string
+		statusCode int
+		header     string
+		want       time.Duration
+	}{{
+		name:       "too many requests with seconds",
+		statusCode: http.StatusTooManyRequests,
+		header:     "10",
+		want:       10 * time.Second,
+	}, {
+		name:       "service unavailable with date",
+		statusCode: http.StatusServiceUnavailable,
+		header:     now.Add(time.Minute).UTC().Format(http.TimeFormat),
+		want:       time.Minute,
+	}, {
+		name:       "date in the past",
+		statusCode: http.StatusServiceUnavailable,
+		header:     now.Add(-time.Minute).UTC().Format(http.TimeFormat),
+	}, {
+		name:       "capped",
+		statusCode: http.StatusTooManyRequests,
+		header:     "3600",
+		want:       maxBackoff,
+	}, {
+		name:       "invalid header",
+		statusCode: http.StatusTooManyRequests,
+		header:     "soon",
+	}, {
+		name:       "no header",
+		statusCode: http.StatusTooManyRequests,
+	}, {
+		name:       "ignored for other status codes",
+		statusCode: http.StatusInternalServerError,
+		header:     "10",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tc.statusCode, Header: make(http.Header)}
+			if tc.header != "" {
+				resp.Header.Set("Retry-After", tc.header)
+			}
+			// HTTP dates have second precision.
+			if got := retryAfter(resp, now); got < tc.want-time.Second || got > tc.want {
+				t.Errorf("retryAfter got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
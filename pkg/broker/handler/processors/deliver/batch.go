@@ -0,0 +1,304 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+const (
+	// batchContentType is the content type of the CloudEvents HTTP batched content mode.
+	batchContentType = "application/cloudevents-batch+json"
+
+	// Defaults for the limits a target's batching leaves unset.
+	defaultBatchMaxCount = 100
+	defaultBatchMaxBytes = 1024 * 1024
+	defaultBatchLinger   = 100 * time.Millisecond
+)
+
+// batchSettings are the limits of the batches delivered to a target.
+type batchSettings struct {
+	maxCount int
+	maxBytes int
+	linger   time.Duration
+}
+
+func newBatchSettings(b *config.Batching) batchSettings {
+	s := batchSettings{
+		maxCount: int(b.GetMaxCount()),
+		maxBytes: int(b.GetMaxBytes()),
+		linger:   b.GetLinger().AsDuration(),
+	}
+	if s.maxCount <= 0 {
+		s.maxCount = defaultBatchMaxCount
+	}
+	if s.maxBytes <= 0 {
+		s.maxBytes = defaultBatchMaxBytes
+	}
+	if s.linger <= 0 {
+		s.linger = defaultBatchLinger
+	}
+	return s
+}
+
+// batchItem is an event waiting in a batch for the result of its delivery.
+type batchItem struct {
+	// ctx is the context the event is processed with. It is only used to report metrics.
+	ctx     context.Context
+	attempt int32
+	// body is the event encoded in the structured content mode.
+	body   []byte
+	result chan error
+}
+
+// batch is a group of events delivered to a target in a single request.
+type batch struct {
+	target   *config.Target
+	address  string
+	settings batchSettings
+	items    []*batchItem
+	size     int
+	timer    *time.Timer
+}
+
+// deliverInBatch adds the event to the pending batch of the target and waits for the result of
+// the batch's delivery. Replies to batches are not supported.
+func (p *Processor) deliverInBatch(ctx context.Context, target *config.Target, e *event.Event, attempt int32) error {
+	// Remove hops and delivery attempts from forwarded event.
+	forwarded := e.Clone()
+	forwarded.SetExtension(eventutil.HopsAttribute, nil)
+	forwarded.SetExtension(eventutil.AttemptsAttribute, nil)
+	body, err := json.Marshal(forwarded)
+	if err != nil {
+		return fmt.Errorf("failed to encode event for batch: %w", err)
+	}
+	item := &batchItem{
+		ctx:     ctx,
+		attempt: attempt,
+		body:    body,
+		result:  make(chan error, 1),
+	}
+	p.addToBatch(target, item)
+	select {
+	case err := <-item.result:
+		return err
+	case <-ctx.Done():
+		// The batch may still be delivered, in which case the event is delivered again later.
+		return ctx.Err()
+	}
+}
+
+// addToBatch adds the item to the pending batch of the target. The batch is dispatched once it is
+// full, or when its linger time elapses.
+func (p *Processor) addToBatch(target *config.Target, item *batchItem) {
+	key := *target.Key()
+	settings := newBatchSettings(target.Batching)
+
+	p.batchesMu.Lock()
+	defer p.batchesMu.Unlock()
+	if p.batches == nil {
+		p.batches = make(map[config.TargetKey]*batch)
+	}
+	b := p.batches[key]
+	if b != nil && (b.address != target.Address || b.settings != settings || b.size+len(item.body) > settings.maxBytes) {
+		// The target changed or the event doesn't fit, send the pending batch as is.
+		p.dispatchBatchLocked(key, b)
+		b = nil
+	}
+	if b == nil {
+		b = &batch{
+			target:   target,
+			address:  target.Address,
+			settings: settings,
+		}
+		p.batches[key] = b
+		b.timer = time.AfterFunc(settings.linger, func() {
+			p.batchesMu.Lock()
+			defer p.batchesMu.Unlock()
+			// The batch may already have been dispatched because it filled up.
+			if p.batches[key] == b {
+				p.dispatchBatchLocked(key, b)
+			}
+		})
+	}
+	b.items = append(b.items, item)
+	b.size += len(item.body)
+	if len(b.items) >= settings.maxCount || b.size >= settings.maxBytes {
+		p.dispatchBatchLocked(key, b)
+	}
+}
+
+// dispatchBatchLocked removes the pending batch of the target and sends it. It must be called
+// with batchesMu held.
+func (p *Processor) dispatchBatchLocked(key config.TargetKey, b *batch) {
+	delete(p.batches, key)
+	b.timer.Stop()
+	go p.sendBatch(b)
+}
+
+// sendBatch delivers the batch to its target and hands the result to each of its events.
+func (p *Processor) sendBatch(b *batch) {
+	err := p.sendBatchRequest(b)
+	for _, item := range b.items {
+		item.result <- err
+	}
+}
+
+func (p *Processor) sendBatchRequest(b *batch) error {
+	ctx, cancel := batchContext(b.items)
+	defer cancel()
+	logger := logging.FromContext(ctx)
+
+	done, err := p.allowDelivery(ctx, b.target)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	// A batch is throttled as a single request.
+	release, throttled, err := p.throttle(ctx, b.target)
+	if b.target.RateLimit != nil {
+		for _, item := range b.items {
+			p.StatsReporter.ReportThrottleTime(item.ctx, throttled)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if p.DeliverTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.DeliverTimeout)
+		defer cancel()
+	}
+
+	// The batched content mode encodes the events as a JSON array of structured events.
+	var body bytes.Buffer
+	body.WriteByte('[')
+	for i, item := range b.items {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(item.body)
+	}
+	body.WriteByte(']')
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.address, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", batchContentType)
+
+	startTime := time.Now()
+	resp, err := p.DeliverClient.Do(req)
+	if err != nil {
+		p.recordDelivery(b.items[0].ctx, b.target, 0, err)
+		var result *url.Error
+		if errors.As(err, &result) && result.Timeout() {
+			// If the delivery is cancelled because of timeout, report event dispatch time without resp status code.
+			for _, item := range b.items {
+				p.StatsReporter.ReportEventDispatchTime(item.ctx, time.Since(startTime))
+				p.StatsReporter.ReportDeliveryAttempt(item.ctx, item.attempt)
+			}
+		}
+		return fmt.Errorf("failed to send batch to subscriber: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Warn("failed to close response body", zap.Error(err))
+		}
+	}()
+
+	p.recordDelivery(b.items[0].ctx, b.target, resp.StatusCode, nil)
+
+	dispatchTime := time.Since(startTime)
+	for _, item := range b.items {
+		// Insert status code tag into context.
+		cctx, err := metrics.AddRespStatusCodeTags(item.ctx, resp.StatusCode)
+		if err != nil {
+			logger.Error("failed to add status code tags to context", zap.Error(err))
+		}
+		p.StatsReporter.ReportEventDispatchTime(cctx, dispatchTime)
+		p.StatsReporter.ReportDeliveryAttempt(cctx, item.attempt)
+		trace.FromContext(item.ctx).Annotate(
+			[]trace.Attribute{
+				trace.Int64Attribute("batch_size", int64(len(b.items))),
+				trace.Int64Attribute("status_code", int64(resp.StatusCode)),
+			},
+			"event delivered in batch",
+		)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Keep the beginning of the response body for dead letter sinks.
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorDataSize))
+		return fmt.Errorf("failed to send batch to subscriber: %w", &deliveryError{
+			statusCode: resp.StatusCode,
+			body:       body,
+			retryAfter: retryAfter(resp, time.Now()),
+		})
+	}
+	if resp.ContentLength != 0 {
+		logger.Debug("ignoring the response body to a batch, replies are not supported in batched mode")
+	}
+	return nil
+}
+
+// batchContext returns the context of the batch's delivery. It carries the values of the first
+// event's context, and is done once the contexts of all events of the batch are done, as no event
+// waits for the result anymore.
+func batchContext(items []*batchItem) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(valuesContext{items[0].ctx})
+	go func() {
+		for _, item := range items {
+			select {
+			case <-item.ctx.Done():
+			case <-ctx.Done():
+				return
+			}
+		}
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+// valuesContext keeps the values of a context but is never done.
+type valuesContext struct {
+	context.Context
+}
+
+func (valuesContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (valuesContext) Done() <-chan struct{}       { return nil }
+func (valuesContext) Err() error                  { return nil }
@@ -0,0 +1,239 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+// batchHandler records the batches it receives and responds with respCode.
+type batchHandler struct {
+	t        *testing.T
+	respCode int
+
+	mu      sync.Mutex
+	batches [][]event.Event
+}
+
+func (h *batchHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if got := req.Header.Get("Content-Type"); got != batchContentType {
+		h.t.Errorf("batch content type got=%q, want=%q", got, batchContentType)
+	}
+	var events []event.Event
+	if err := json.NewDecoder(req.Body).Decode(&events); err != nil {
+		h.t.Errorf("failed to decode batch: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.mu.Lock()
+	h.batches = append(h.batches, events)
+	h.mu.Unlock()
+	w.WriteHeader(h.respCode)
+}
+
+// batchIDs returns the sorted event IDs of each batch received.
+func (h *batchHandler) batchIDs() [][]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var ids [][]string
+	for _, batch := range h.batches {
+		var batchIDs []string
+		for _, e := range batch {
+			batchIDs = append(batchIDs, e.ID())
+			if _, ok := e.Extensions()[eventutil.HopsAttribute]; ok {
+				h.t.Errorf("event %q in batch has hops extension", e.ID())
+			}
+		}
+		sort.Strings(batchIDs)
+		ids = append(ids, batchIDs)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i][0] < ids[j][0] })
+	return ids
+}
+
+func TestDeliverInBatch(t *testing.T) {
+	cases := []struct {
+		name        string
+		batching    *config.Batching
+		respCode    int
+		events      int
+		eventSize   int
+		wantBatches [][]string
+		wantErr     bool
+	}{{
+		name:        "batch filled up",
+		batching:    &config.Batching{MaxCount: 3, Linger: durationpb.New(time.Minute)},
+		respCode:    http.StatusAccepted,
+		events:      3,
+		wantBatches: [][]string{{"0", "1", "2"}},
+	}, {
+		name:        "batch sent after linger",
+		batching:    &config.Batching{MaxCount: 10, Linger: durationpb.New(50 * time.Millisecond)},
+		respCode:    http.StatusAccepted,
+		events:      2,
+		wantBatches: [][]string{{"0", "1"}},
+	}, {
+		name:        "batches split by size",
+		batching:    &config.Batching{MaxBytes: 1500, Linger: durationpb.New(50 * time.Millisecond)},
+		respCode:    http.StatusAccepted,
+		events:      2,
+		eventSize:   1000,
+		wantBatches: [][]string{{"0"}, {"1"}},
+	}, {
+		name:        "batch delivery failure",
+		batching:    &config.Batching{MaxCount: 2, Linger: durationpb.New(time.Minute)},
+		respCode:    http.StatusInternalServerError,
+		events:      2,
+		wantBatches: [][]string{{"0", "1"}},
+		wantErr:     true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			handler := &batchHandler{t: t, respCode: tc.respCode}
+			targetSvr := httptest.NewServer(handler)
+			defer targetSvr.Close()
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				Batching:       tc.batching,
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Without RetryOnFailure, delivery errors of events whose Pub/Sub message counts its
+			// deliveries are returned as is.
+			ctx = handlerctx.WithDeliveryAttempt(ctx, 1)
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				StatsReporter: r,
+			}
+
+			var wg sync.WaitGroup
+			errs := make([]error, tc.events)
+			for i := 0; i < tc.events; i++ {
+				e := newSampleEvent()
+				e.SetID(fmt.Sprint(i))
+				e.SetData(event.TextPlain, make([]byte, tc.eventSize))
+				eventutil.UpdateRemainingHops(ctx, e, 10)
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					errs[i] = p.Process(ctx, e)
+				}(i)
+				// Keep the order of the events, so that size limited batches are predictable.
+				time.Sleep(10 * time.Millisecond)
+			}
+			wg.Wait()
+
+			for i, err := range errs {
+				var derr *deliveryError
+				if tc.wantErr && !errors.As(err, &derr) {
+					t.Errorf("event %d: want delivery error, got %v", i, err)
+				}
+				if !tc.wantErr && err != nil {
+					t.Errorf("event %d: unexpected error: %v", i, err)
+				}
+			}
+			if diff := cmp.Diff(tc.wantBatches, handler.batchIDs()); diff != "" {
+				t.Errorf("batches (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestBatchContext(t *testing.T) {
+	ctx1, cancel1 := context.WithCancel(handlerctx.WithBrokerKey(context.Background(), config.TestOnlyBrokerKey("ns", "broker")))
+	defer cancel1()
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+
+	ctx, cancel := batchContext([]*batchItem{{ctx: ctx1}, {ctx: ctx2}})
+	defer cancel()
+	if _, err := handlerctx.GetBrokerKey(ctx); err != nil {
+		t.Errorf("batch context doesn't carry the values of the first event's context: %v", err)
+	}
+
+	cancel1()
+	select {
+	case <-ctx.Done():
+		t.Fatal("batch context done while an event still waits for the result")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel2()
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch context not done after the contexts of all events are done")
+	}
+}
+
+func TestNewBatchSettings(t *testing.T) {
+	got := newBatchSettings(&config.Batching{MaxBytes: 10})
+	want := batchSettings{
+		maxCount: defaultBatchMaxCount,
+		maxBytes: 10,
+		linger:   defaultBatchLinger,
+	}
+	if got != want {
+		t.Errorf("newBatchSettings got=%+v, want=%+v", got, want)
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+// CircuitsPath is the path CircuitBreakers serves the circuits that are not closed at.
+const CircuitsPath = "/circuits"
+
+// errCircuitOpen is returned when a delivery is short-circuited because the circuit of its target
+// is open.
+var errCircuitOpen = errors.New("delivery short-circuited: the subscriber's circuit breaker is open")
+
+// CircuitStatus is the status of a target's circuit that is not closed.
+type CircuitStatus struct {
+	Namespace      string `json:"namespace"`
+	Name           string `json:"name"`
+	CellTenantType string `json:"cellTenantType"`
+	// State is either "Open" or "HalfOpen".
+	State string `json:"state"`
+	// Since is when the circuit last opened.
+	Since time.Time `json:"since"`
+}
+
+// CircuitBreakers holds the circuit breakers of the targets delivered to by a handler pool. The
+// circuit of a target opens after FailureThreshold consecutive failed requests, and deliveries to
+// it fail right away while it is open. Once OpenDuration has elapsed, a single request is let
+// through to probe the target. The circuit closes if the probe succeeds, and opens again
+// otherwise.
+type CircuitBreakers struct {
+	// FailureThreshold is the number of consecutive failed requests that opens a circuit.
+	FailureThreshold int
+	// OpenDuration is how long a circuit stays open before it is probed.
+	OpenDuration time.Duration
+
+	mu       sync.Mutex
+	breakers map[config.TargetKey]*circuitBreaker
+}
+
+// NewCircuitBreakers creates CircuitBreakers.
+func NewCircuitBreakers(failureThreshold int, openDuration time.Duration) *CircuitBreakers {
+	return &CircuitBreakers{
+		FailureThreshold: failureThreshold,
+		OpenDuration:     openDuration,
+		breakers:         make(map[config.TargetKey]*circuitBreaker),
+	}
+}
+
+// breakerFor returns the circuit breaker of the target. The breakers of targets that are no longer
+// in targets are pruned whenever a breaker is created.
+func (c *CircuitBreakers) breakerFor(targets config.ReadonlyTargets, target *config.Target) *circuitBreaker {
+	key := *target.Key()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.breakers[key]
+	if !ok {
+		c.pruneBreakersLocked(targets)
+		b = &circuitBreaker{
+			namespace:      target.Namespace,
+			name:           target.Name,
+			cellTenantType: target.CellTenantType,
+		}
+		c.breakers[key] = b
+	}
+	return b
+}
+
+// pruneBreakersLocked removes the breakers of the targets that are no longer in targets. It must
+// be called with mu held.
+func (c *CircuitBreakers) pruneBreakersLocked(targets config.ReadonlyTargets) {
+	for key := range c.breakers {
+		key := key
+		if _, ok := targets.GetTargetByKey(&key); !ok {
+			delete(c.breakers, key)
+		}
+	}
+}
+
+// Statuses returns the statuses of the circuits that are not closed.
+func (c *CircuitBreakers) Statuses() []CircuitStatus {
+	c.mu.Lock()
+	breakers := make([]*circuitBreaker, 0, len(c.breakers))
+	for _, b := range c.breakers {
+		breakers = append(breakers, b)
+	}
+	c.mu.Unlock()
+
+	statuses := []CircuitStatus{}
+	for _, b := range breakers {
+		if s, ok := b.status(); ok {
+			statuses = append(statuses, s)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Namespace != statuses[j].Namespace {
+			return statuses[i].Namespace < statuses[j].Namespace
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// ServeHTTP serves the statuses of the circuits that are not closed as a JSON array.
+func (c *CircuitBreakers) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(c.Statuses()); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// circuitBreaker is the circuit breaker of a single target.
+type circuitBreaker struct {
+	namespace      string
+	name           string
+	cellTenantType config.CellTenantType
+
+	mu       sync.Mutex
+	state    int64
+	failures int
+	openedAt time.Time
+	// probing is true while the request probing a half-open circuit is in flight.
+	probing bool
+}
+
+// allow returns true if a request can be sent to the target, and whether that request probes the
+// target. The previous state is returned so that transitions can be reported.
+func (b *circuitBreaker) allow(openDuration time.Duration) (allowed, probe bool, from, to int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	from = b.state
+	switch b.state {
+	case metrics.CircuitOpen:
+		if time.Since(b.openedAt) < openDuration {
+			return false, false, from, b.state
+		}
+		b.state = metrics.CircuitHalfOpen
+		b.probing = true
+		return true, true, from, b.state
+	case metrics.CircuitHalfOpen:
+		if b.probing {
+			return false, false, from, b.state
+		}
+		b.probing = true
+		return true, true, from, b.state
+	default:
+		return true, false, from, b.state
+	}
+}
+
+// record records the outcome of a request sent to the target. Outcomes of requests sent before
+// the circuit opened are ignored.
+func (b *circuitBreaker) record(failed bool, failureThreshold int) (from, to int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	from = b.state
+	switch b.state {
+	case metrics.CircuitClosed:
+		if !failed {
+			b.failures = 0
+			break
+		}
+		b.failures++
+		if b.failures >= failureThreshold {
+			b.open()
+		}
+	case metrics.CircuitHalfOpen:
+		b.probing = false
+		if failed {
+			b.open()
+		} else {
+			b.state = metrics.CircuitClosed
+			b.failures = 0
+		}
+	}
+	return from, b.state
+}
+
+func (b *circuitBreaker) open() {
+	b.state = metrics.CircuitOpen
+	b.openedAt = time.Now()
+}
+
+// endProbe lets another request probe a half-open circuit if the probe didn't send any request.
+func (b *circuitBreaker) endProbe() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) status() (CircuitStatus, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := CircuitStatus{
+		Namespace:      b.namespace,
+		Name:           b.name,
+		CellTenantType: b.cellTenantType.String(),
+		Since:          b.openedAt,
+	}
+	switch b.state {
+	case metrics.CircuitOpen:
+		s.State = "Open"
+	case metrics.CircuitHalfOpen:
+		s.State = "HalfOpen"
+	default:
+		return CircuitStatus{}, false
+	}
+	return s, true
+}
+
+// allowDelivery checks the circuit of the target before a request is sent to it. It returns a
+// function that must be called once the request completes, or errCircuitOpen if the request must
+// not be sent.
+func (p *Processor) allowDelivery(ctx context.Context, target *config.Target) (func(), error) {
+	if p.CircuitBreakers == nil {
+		return func() {}, nil
+	}
+	b := p.CircuitBreakers.breakerFor(p.Targets, target)
+	allowed, probe, from, to := b.allow(p.CircuitBreakers.OpenDuration)
+	p.reportCircuitTransition(ctx, target, from, to)
+	if !allowed {
+		return nil, errCircuitOpen
+	}
+	if probe {
+		return b.endProbe, nil
+	}
+	return func() {}, nil
+}
+
+// recordDelivery records the outcome of a request sent to the target in its circuit. The request
+// failed if it couldn't reach the subscriber, timed out, or was answered with a status code
+// showing that the subscriber is unavailable or overloaded.
+func (p *Processor) recordDelivery(ctx context.Context, target *config.Target, statusCode int, err error) {
+	if p.CircuitBreakers == nil {
+		return
+	}
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		// The handler gave up on the event, which says nothing about the subscriber.
+		return
+	}
+	failed := err != nil || statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests
+	from, to := p.CircuitBreakers.breakerFor(p.Targets, target).record(failed, p.CircuitBreakers.FailureThreshold)
+	p.reportCircuitTransition(ctx, target, from, to)
+}
+
+func (p *Processor) reportCircuitTransition(ctx context.Context, target *config.Target, from, to int64) {
+	if from == to {
+		return
+	}
+	p.StatsReporter.ReportCircuitState(ctx, to)
+	if to == metrics.CircuitOpen {
+		logging.FromContext(ctx).Warn("subscriber circuit breaker opened",
+			zap.String("namespace", target.Namespace),
+			zap.String("target", target.Name),
+			zap.Duration("openDuration", p.CircuitBreakers.OpenDuration),
+		)
+	} else {
+		logging.FromContext(ctx).Info("subscriber circuit breaker changed state",
+			zap.String("namespace", target.Namespace),
+			zap.String("target", target.Name),
+			zap.Int64("state", to),
+		)
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	b := &circuitBreaker{}
+	openDuration := 50 * time.Millisecond
+
+	for i := 0; i < 2; i++ {
+		if allowed, _, _, _ := b.allow(openDuration); !allowed {
+			t.Fatalf("closed circuit did not allow request %d", i)
+		}
+		b.record(true, 3)
+	}
+	// A success resets the consecutive failures.
+	b.record(false, 3)
+	for i := 0; i < 3; i++ {
+		b.record(true, 3)
+	}
+	if b.state != metrics.CircuitOpen {
+		t.Fatalf("circuit state after 3 consecutive failures got=%d, want open", b.state)
+	}
+	if allowed, _, _, _ := b.allow(openDuration); allowed {
+		t.Error("open circuit allowed a request")
+	}
+
+	time.Sleep(openDuration)
+	allowed, probe, from, to := b.allow(openDuration)
+	if !allowed || !probe || from != metrics.CircuitOpen || to != metrics.CircuitHalfOpen {
+		t.Errorf("allow after open duration got=(%v, %v, %d, %d), want a probe into half-open", allowed, probe, from, to)
+	}
+	if allowed, _, _, _ := b.allow(openDuration); allowed {
+		t.Error("half-open circuit allowed a second request while probing")
+	}
+	// A failed probe opens the circuit again.
+	b.record(true, 3)
+	if b.state != metrics.CircuitOpen {
+		t.Errorf("circuit state after failed probe got=%d, want open", b.state)
+	}
+
+	time.Sleep(openDuration)
+	if allowed, probe, _, _ := b.allow(openDuration); !allowed || !probe {
+		t.Fatal("circuit did not allow a probe after open duration")
+	}
+	if _, to := b.record(false, 3); to != metrics.CircuitClosed {
+		t.Errorf("circuit state after successful probe got=%d, want closed", to)
+	}
+}
+
+func TestCircuitBreakerEndProbe(t *testing.T) {
+	b := &circuitBreaker{state: metrics.CircuitHalfOpen}
+	if allowed, probe, _, _ := b.allow(time.Minute); !allowed || !probe {
+		t.Fatal("half-open circuit did not allow a probe")
+	}
+	// The probe didn't send any request, so another request may probe.
+	b.endProbe()
+	if allowed, probe, _, _ := b.allow(time.Minute); !allowed || !probe {
+		t.Error("half-open circuit did not allow a probe after the previous probe ended")
+	}
+}
+
+// statusHandler responds with the status code it holds.
+type statusHandler struct {
+	statusCode int32
+	requests   int32
+}
+
+func (h *statusHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	io.Copy(ioutil.Discard, req.Body)
+	atomic.AddInt32(&h.requests, 1)
+	w.WriteHeader(int(atomic.LoadInt32(&h.statusCode)))
+}
+
+func TestDeliverWithCircuitBreaker(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	handler := &statusHandler{statusCode: http.StatusServiceUnavailable}
+	targetSvr := httptest.NewServer(handler)
+	defer targetSvr.Close()
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+	// Failed retries of events whose Pub/Sub message counts its deliveries are nacked.
+	ctx = handlerctx.WithDeliveryAttempt(ctx, 1)
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	openDuration := 100 * time.Millisecond
+	p := &Processor{
+		DeliverClient:   http.DefaultClient,
+		Targets:         testTargets,
+		StatsReporter:   r,
+		CircuitBreakers: NewCircuitBreakers(2, openDuration),
+	}
+
+	for i := 0; i < 4; i++ {
+		err := p.Process(ctx, newSampleEvent())
+		if err == nil {
+			t.Fatalf("delivery %d to an unavailable subscriber succeeded", i)
+		}
+		if wantOpen := i >= 2; errors.Is(err, errCircuitOpen) != wantOpen {
+			t.Errorf("delivery %d short-circuited got=%v, want=%v: %v", i, !wantOpen, wantOpen, err)
+		}
+	}
+	if got := atomic.LoadInt32(&handler.requests); got != 2 {
+		t.Errorf("requests sent to the subscriber got=%d, want=2", got)
+	}
+
+	wantStatuses := []CircuitStatus{{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: "BROKER",
+		State:          "Open",
+	}}
+	if diff := cmp.Diff(wantStatuses, p.CircuitBreakers.Statuses(), cmpopts.IgnoreFields(CircuitStatus{}, "Since")); diff != "" {
+		t.Errorf("unexpected circuit statuses (-want, +got) = %v", diff)
+	}
+	rec := httptest.NewRecorder()
+	p.CircuitBreakers.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, CircuitsPath, nil))
+	var served []CircuitStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatalf("failed to decode served circuit statuses: %v", err)
+	}
+	if diff := cmp.Diff(wantStatuses, served, cmpopts.IgnoreFields(CircuitStatus{}, "Since")); diff != "" {
+		t.Errorf("unexpected served circuit statuses (-want, +got) = %v", diff)
+	}
+
+	// The subscriber recovers, and the probe sent after the open duration closes the circuit.
+	atomic.StoreInt32(&handler.statusCode, http.StatusAccepted)
+	time.Sleep(openDuration)
+	if err := p.Process(ctx, newSampleEvent()); err != nil {
+		t.Errorf("unexpected error from the probe: %v", err)
+	}
+	if got := p.CircuitBreakers.Statuses(); len(got) != 0 {
+		t.Errorf("circuit statuses after a successful probe got=%v, want none", got)
+	}
+}
+
+func TestFanoutShortCircuitIsNotAnAttempt(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	handler := &statusHandler{statusCode: http.StatusServiceUnavailable}
+	targetSvr := httptest.NewServer(handler)
+	defer targetSvr.Close()
+	deadLetter := &statusHandler{statusCode: http.StatusAccepted}
+	deadLetterSvr := httptest.NewServer(deadLetter)
+	defer deadLetterSvr.Close()
+
+	srv, c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+	if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+		t.Fatalf("failed to create test pubsub topc: %v", err)
+	}
+	ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+	if err != nil {
+		t.Fatalf("failed to create pubsub protocol: %v", err)
+	}
+	deliverRetryClient, err := ceclient.New(ps)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents client: %v", err)
+	}
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+		// The target doesn't want any retries.
+		DeliverySpec: &config.DeliverySpec{
+			DeadLetterAddress: deadLetterSvr.URL,
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: deliverRetryClient,
+		StatsReporter:      r,
+		CircuitBreakers:    NewCircuitBreakers(1, time.Hour),
+	}
+
+	// The failed delivery opens the circuit, and the event is dead lettered.
+	if err := p.Process(ctx, newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	// The short-circuited event was never attempted, so it is retried rather than dead lettered.
+	if err := p.Process(ctx, newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got := atomic.LoadInt32(&handler.requests); got != 1 {
+		t.Errorf("requests sent to the subscriber got=%d, want=1", got)
+	}
+	if got := atomic.LoadInt32(&deadLetter.requests); got != 1 {
+		t.Errorf("requests sent to the dead letter sink got=%d, want=1", got)
+	}
+	if msgs := srv.Messages(); len(msgs) != 1 {
+		t.Errorf("retry topic got %d messages, want 1", len(msgs))
+	}
+}
+
+func TestBreakersPruned(t *testing.T) {
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	newTarget := func(name string) *config.Target {
+		return &config.Target{
+			Namespace:      "ns",
+			Name:           name,
+			CellTenantType: config.CellTenantType_BROKER,
+			CellTenantName: "broker",
+		}
+	}
+	removed, kept := newTarget("removed"), newTarget("kept")
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(removed, kept)
+	})
+	c := NewCircuitBreakers(1, time.Hour)
+	c.breakerFor(testTargets, removed).record(true, c.FailureThreshold)
+	c.breakerFor(testTargets, kept)
+
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.DeleteTargets(removed)
+	})
+	added := newTarget("added")
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(added)
+	})
+	c.breakerFor(testTargets, added)
+
+	var got []string
+	for key := range c.breakers {
+		got = append(got, key.String())
+	}
+	sort.Strings(got)
+	want := []string{added.Key().String(), kept.Key().String()}
+	sort.Strings(want)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected breakers (-want, +got) = %v", diff)
+	}
+	if statuses := c.Statuses(); len(statuses) != 0 {
+		t.Errorf("unexpected statuses of pruned breakers: %v", statuses)
+	}
+}
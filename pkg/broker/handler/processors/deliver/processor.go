@@ -18,11 +18,15 @@ package deliver
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
@@ -31,10 +35,13 @@ import (
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/event"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/clock"
 
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
@@ -42,7 +49,44 @@ import (
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
-const defaultEventHopsLimit int32 = 255
+const (
+	defaultEventHopsLimit int32 = 255
+
+	// maxErrorDataSize is the maximum number of bytes of a failed response body that is attached
+	// to events sent to a dead letter sink.
+	maxErrorDataSize = 1024
+
+	// Extensions describing the delivery failure of events sent to a dead letter sink.
+	// See https://github.com/knative/eventing/blob/master/docs/delivery/README.md.
+	errorDestExtension = "knativeerrordest"
+	errorCodeExtension = "knativeerrorcode"
+	errorDataExtension = "knativeerrordata"
+
+	// requeueCushion is the time left to requeue an event for retry when the
+	// backoff is capped by the timeout of the event.
+	requeueCushion = 5 * time.Second
+
+	// minOrderedRetryDelay is the minimum delay between in-process retries of
+	// events with an ordering key.
+	minOrderedRetryDelay = time.Second
+
+	// retryRetention is how long the events of targets without a dead letter sink are retried
+	// since they arrived at the broker. It is the default message retention of the retry
+	// subscriptions, which bounded the retries of such events when they were nacked.
+	retryRetention = 7 * 24 * time.Hour
+)
+
+// deliveryError is returned when the subscriber responds with a non-2xx status code.
+type deliveryError struct {
+	statusCode int
+	body       []byte
+	// retryAfter is the delay requested by the subscriber's Retry-After header.
+	retryAfter time.Duration
+}
+
+func (e *deliveryError) Error() string {
+	return fmt.Sprintf("event delivery failed: HTTP status code %d", e.statusCode)
+}
 
 // Processor delivers events based on the broker/target in the context.
 type Processor struct {
@@ -59,19 +103,50 @@ type Processor struct {
 	RetryOnFailure bool
 
 	// DeliverRetryClient is the cloudevents client to send events
-	// to the retry topic.
+	// to the retry topic. It is also used to requeue events of targets
+	// with a dead letter sink when the retry delivery fails.
 	DeliverRetryClient ceclient.Client
 
+	// OrderedRetryTopics publishes events with an ordering key to the retry topic, so that they
+	// are retried in order. If nil, such events are sent with the DeliverRetryClient unordered.
+	OrderedRetryTopics *OrderedRetryTopics
+
 	// DeliverTimeout is the timeout applied to cancel delivery.
 	// If zero, not additional timeout is applied.
 	DeliverTimeout time.Duration
 
 	// StatsReporter is used to report delivery metrics.
 	StatsReporter *metrics.DeliveryReporter
+
+	// batches holds the pending batch of each target with batching.
+	batches   map[config.TargetKey]*batch
+	batchesMu sync.Mutex
+
+	// CircuitBreakers holds the circuit breakers of the targets. If nil, deliveries are never
+	// short-circuited.
+	CircuitBreakers *CircuitBreakers
+
+	// Rehydrator reads the data of events offloaded to GCS by the ingress back before they are
+	// delivered. If nil, such events are delivered with the reference to their data.
+	Rehydrator *claimcheck.Rehydrator
+
+	// limiters holds the limiter of each target with a rate limit.
+	limiters   map[config.TargetKey]*targetLimiter
+	limitersMu sync.Mutex
+
+	// Clock is used to wait between retries. If nil, the real clock is used.
+	Clock clock.Clock
 }
 
 var _ processors.Interface = (*Processor)(nil)
 
+func (p *Processor) clock() clock.Clock {
+	if p.Clock == nil {
+		return clock.RealClock{}
+	}
+	return p.Clock
+}
+
 // Process delivers the event based on the broker/target in the context.
 func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 	bk, err := handlerctx.GetBrokerKey(ctx)
@@ -119,39 +194,252 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 		hops -= 1
 	}
 
-	p.StatsReporter.FinishEventProcessing(ctx)
-
-	dctx := ctx
-	if p.DeliverTimeout > 0 {
-		var cancel context.CancelFunc
-		dctx, cancel = context.WithTimeout(dctx, p.DeliverTimeout)
-		defer cancel()
+	// The delivery attempt of the event to the target, starting at 1 for the
+	// initial delivery by the fanout.
+	attempt := int32(1)
+	if !p.RetryOnFailure {
+		attempt = failedAttempts(ctx, e) + 1
 	}
 
-	if err := p.deliver(dctx, target, broker, eventutil.NewImmutableEventMessage(e), hops); err != nil {
+	p.StatsReporter.FinishEventProcessing(ctx)
+
+	if err := p.deliverWithTimeout(ctx, target, broker, e, hops, attempt); err != nil {
+		// Failed events are retried and dead lettered as they were received, so that they are
+		// transformed again when they are retried.
+		original := handlerctx.GetOriginalEvent(ctx, e)
 		if !p.RetryOnFailure {
-			return err
+			if !attempted(err) {
+				// Short-circuited and throttled deliveries are not attempts, so let Pub/Sub
+				// redeliver the event with the backoff of the retry subscription.
+				return err
+			}
+			return p.retryOrDeadLetter(ctx, target, broker, original, err)
+		}
+
+		var retries int32
+		if hasOrderingKey(broker, original) {
+			// Pub/Sub holds back later events with the same ordering key until this one is acked
+			// for all targets, so retrying in process delivers them after it. Once it gives up,
+			// the event is enqueued for retry and the later events may be delivered before it.
+			retries, err = p.retryInOrder(ctx, target, broker, e, hops, err)
+			if err == nil {
+				return p.Next().Process(ctx, e)
+			}
+			if ctx.Err() != nil {
+				// Let Pub/Sub redeliver the event in order.
+				return err
+			}
 		}
 
 		logging.FromContext(ctx).Warn("target delivery failed", zap.Stringer("target", tk), zap.Error(err))
+		// Short-circuited and throttled deliveries are not attempts, so the event is retried even if
+		// the target doesn't want any retries.
+		if hasDeadLetterSink(target) && retries >= target.DeliverySpec.Retry && attempted(err) {
+			// The target doesn't want any (more) retries.
+			return p.sendToDeadLetterSink(ctx, target, original, err)
+		}
 		trace.FromContext(ctx).Annotate(
 			[]trace.Attribute{trace.StringAttribute("error_message", err.Error())},
 			"enqueueing for retry",
 		)
 
-		return p.sendToRetryTopic(ctx, target, e)
+		if retries > 0 {
+			requeued := original.Clone()
+			eventutil.SetDeliveryAttempts(&requeued, retries)
+			original = &requeued
+		}
+		return p.sendToRetryTopic(ctx, target, broker, original)
 	}
 	// For post-delivery processing.
 	return p.Next().Process(ctx, e)
 }
 
+// deliverWithTimeout delivers the event to the target, applying the DeliverTimeout if there is one.
+// Events of targets with batching are delivered in a batch instead.
+func (p *Processor) deliverWithTimeout(ctx context.Context, target *config.Target, broker *config.CellTenant, e *event.Event, hops, attempt int32) error {
+	if p.Rehydrator != nil && target.Address != "" {
+		// Events are only rehydrated for delivery, so that they are retried with the reference.
+		rehydrated, err := p.Rehydrator.Rehydrate(ctx, broker.Key(), e)
+		if err != nil {
+			return err
+		}
+		e = rehydrated
+	}
+	if target.Batching != nil && target.Address != "" {
+		return p.deliverInBatch(ctx, target, e, attempt)
+	}
+	if target.Address != "" {
+		done, err := p.allowDelivery(ctx, target)
+		if err != nil {
+			return err
+		}
+		defer done()
+	}
+	release, throttled, err := p.throttle(ctx, target)
+	if target.RateLimit != nil {
+		p.StatsReporter.ReportThrottleTime(ctx, throttled)
+	}
+	if err != nil {
+		return err
+	}
+	defer release()
+	if p.DeliverTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.DeliverTimeout)
+		defer cancel()
+	}
+	return p.deliver(ctx, target, broker, eventutil.NewImmutableEventMessage(e), hops, attempt)
+}
+
+// hasOrderingKey returns true if the broker is ordered and the event was published with an
+// ordering key.
+func hasOrderingKey(broker *config.CellTenant, e *event.Event) bool {
+	if broker.OrderingKeyAttribute == "" {
+		return false
+	}
+	_, ok := eventutil.AttributeValue(e, broker.OrderingKeyAttribute)
+	return ok
+}
+
+// retryInOrder retries the failed delivery of an event with an ordering key in process. It stops
+// once the target's retries are exhausted for its dead letter sink, or when waiting for the next
+// retry would leave no time to requeue the event before the handler times out. It returns the
+// number of failed retries, not counting short-circuited and throttled ones, and the last delivery
+// error, which is nil if a retry succeeded.
+func (p *Processor) retryInOrder(ctx context.Context, target *config.Target, broker *config.CellTenant, e *event.Event, hops int32, deliveryErr error) (int32, error) {
+	var retries int32
+	for {
+		if hasDeadLetterSink(target) && retries >= target.DeliverySpec.Retry && attempted(deliveryErr) {
+			return retries, deliveryErr
+		}
+		delay := retryDelay(target.DeliverySpec, retries+1, deliveryErr)
+		if delay < minOrderedRetryDelay {
+			delay = minOrderedRetryDelay
+		}
+		if remaining, ok := requeueTime(ctx, p.clock().Now()); ok && delay > remaining {
+			return retries, deliveryErr
+		}
+		if !sleep(ctx, p.clock(), delay) {
+			return retries, deliveryErr
+		}
+		trace.FromContext(ctx).Annotate(
+			[]trace.Attribute{
+				trace.StringAttribute("error_message", deliveryErr.Error()),
+				trace.Int64Attribute("retries", int64(retries)),
+			},
+			"retrying in order",
+		)
+		if deliveryErr = p.deliverWithTimeout(ctx, target, broker, e, hops, retries+2); deliveryErr == nil {
+			return retries, nil
+		}
+		if attempted(deliveryErr) {
+			retries++
+		}
+	}
+}
+
+// attempted returns false if the failed delivery was never sent to the subscriber, because its
+// circuit is open or its rate limit throttled it. Such deliveries don't count as attempts.
+func attempted(deliveryErr error) bool {
+	return !errors.Is(deliveryErr, errCircuitOpen) && !errors.Is(deliveryErr, errThrottled)
+}
+
+// hasDeadLetterSink returns true if failed events of the target should be sent to an addressable
+// dead letter sink once its retries are exhausted.
+func hasDeadLetterSink(target *config.Target) bool {
+	return target.DeliverySpec != nil && target.DeliverySpec.DeadLetterAddress != ""
+}
+
+// failedAttempts returns the number of failed delivery attempts of the event in the retry queue,
+// counting the one being processed. Events requeued by the retry handler record the attempts of
+// their previous messages, and Pub/Sub counts the deliveries of the current message if the retry
+// subscription has a dead letter policy.
+func failedAttempts(ctx context.Context, e *event.Event) int32 {
+	attempts := eventutil.GetDeliveryAttempts(ctx, e) + 1
+	if delivered, ok := handlerctx.GetDeliveryAttempt(ctx); ok && delivered > 1 {
+		attempts += int32(delivered) - 1
+	}
+	return attempts
+}
+
+// retryOrDeadLetter handles a failed retry delivery of an event.
+//
+// Events of targets with a dead letter topic are nacked, as the dead letter policy of the retry
+// subscription relies on Pub/Sub counting the deliveries of their message. Pub/Sub backs them off
+// with the retry policy of the subscription, after the delay the subscriber's Retry-After asks for.
+//
+// Other events are sent to the target's dead letter sink if its retries are exhausted, and are
+// dropped if the target has no dead letter sink and they arrived more than retryRetention ago.
+// Otherwise they are requeued in the retry topic with their attempts incremented after the backoff
+// delay. The subscriber's Retry-After is honoured if it asks for a longer delay. The delay is
+// capped so that the event can be requeued before the handler times out.
+func (p *Processor) retryOrDeadLetter(ctx context.Context, target *config.Target, broker *config.CellTenant, e *event.Event, deliveryErr error) error {
+	if _, ok := handlerctx.GetDeliveryAttempt(ctx); ok && !hasDeadLetterSink(target) {
+		// Without attempts, the delay is only the one asked for by the subscriber.
+		delay := retryDelay(nil, 0, deliveryErr)
+		if remaining, ok := requeueTime(ctx, p.clock().Now()); ok && delay > remaining {
+			delay = remaining
+		}
+		sleep(ctx, p.clock(), delay)
+		return deliveryErr
+	}
+
+	attempts := failedAttempts(ctx, e)
+	if hasDeadLetterSink(target) && attempts >= target.DeliverySpec.Retry {
+		return p.sendToDeadLetterSink(ctx, target, e, deliveryErr)
+	}
+	arrival, ok := eventutil.GetArrivalTime(e)
+	if !ok {
+		// Events published before the ingress recorded their arrival are retried from now on.
+		arrival = p.clock().Now()
+	}
+	if !hasDeadLetterSink(target) && p.clock().Since(arrival) >= retryRetention {
+		logging.FromContext(ctx).Warn("dropping event retried for longer than the retry retention",
+			zap.String("target", target.Name),
+			zap.String("event.id", e.ID()),
+			zap.Int32(eventutil.AttemptsAttribute, attempts),
+			zap.Error(deliveryErr),
+		)
+		trace.FromContext(ctx).Annotate(
+			[]trace.Attribute{trace.StringAttribute("error_message", deliveryErr.Error())},
+			"event dropped: retry retention exceeded",
+		)
+		return nil
+	}
+
+	delay := retryDelay(target.DeliverySpec, attempts, deliveryErr)
+	if remaining, ok := requeueTime(ctx, p.clock().Now()); ok && delay > remaining {
+		delay = remaining
+	}
+	if !sleep(ctx, p.clock(), delay) {
+		// Let Pub/Sub redeliver the event without counting this attempt.
+		return deliveryErr
+	}
+
+	logging.FromContext(ctx).Debug("requeueing event for retry",
+		zap.String("event.id", e.ID()),
+		zap.Int32(eventutil.AttemptsAttribute, attempts),
+	)
+	trace.FromContext(ctx).Annotate(
+		[]trace.Attribute{
+			trace.StringAttribute("error_message", deliveryErr.Error()),
+			trace.Int64Attribute("attempts", int64(attempts)),
+		},
+		"requeueing for retry",
+	)
+	requeued := e.Clone()
+	eventutil.SetDeliveryAttempts(&requeued, attempts)
+	requeued.SetExtension(eventutil.ArrivalTimeAttribute, cetypes.Timestamp{Time: arrival})
+	return p.sendToRetryTopic(ctx, target, broker, &requeued)
+}
+
 // deliver delivers msg to target and sends the target's reply to the broker ingress.
-func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *config.CellTenant, msg binding.Message, hops int32) error {
+func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *config.CellTenant, msg binding.Message, hops, attempt int32) error {
 	// Channels can have a reply address without a subscriber. So default the replyMessage to the
 	// original message. If there is a subscriber, then replyMessage is overwritten.
 	replyMessage := msg
 	if target.Address != "" {
-		replyMsg, cleanUp, err := p.sendToSubscriber(ctx, target, msg, hops)
+		replyMsg, cleanUp, err := p.sendToSubscriber(ctx, target, msg, hops, attempt)
 		defer cleanUp()
 		if err != nil {
 			return fmt.Errorf("failed to send event to subscriber: %w", err)
@@ -203,18 +491,21 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 	return nil
 }
 
-func (p *Processor) sendToSubscriber(ctx context.Context, target *config.Target, msg binding.Message, hops int32) (*cehttp.Message, func(), error) {
+func (p *Processor) sendToSubscriber(ctx context.Context, target *config.Target, msg binding.Message, hops, attempt int32) (*cehttp.Message, func(), error) {
 	transformers := []binding.Transformer{
-		// Remove hops from forwarded event.
+		// Remove hops and delivery attempts from forwarded event.
 		transformer.DeleteExtension(eventutil.HopsAttribute),
+		transformer.DeleteExtension(eventutil.AttemptsAttribute),
 	}
 	startTime := time.Now()
 	resp, err := p.sendMsg(ctx, target.Address, msg, transformers...)
 	if err != nil {
+		p.recordDelivery(ctx, target, 0, err)
 		var result *url.Error
 		if errors.As(err, &result) && result.Timeout() {
 			// If the delivery is cancelled because of timeout, report event dispatch time without resp status code.
 			p.StatsReporter.ReportEventDispatchTime(ctx, time.Since(startTime))
+			p.StatsReporter.ReportDeliveryAttempt(ctx, attempt)
 		}
 		return nil, func() {}, err
 	}
@@ -225,6 +516,8 @@ func (p *Processor) sendToSubscriber(ctx context.Context, target *config.Target,
 		}
 	}
 
+	p.recordDelivery(ctx, target, resp.StatusCode, nil)
+
 	// Insert status code tag into context.
 	cctx, err := metrics.AddRespStatusCodeTags(ctx, resp.StatusCode)
 	if err != nil {
@@ -232,9 +525,16 @@ func (p *Processor) sendToSubscriber(ctx context.Context, target *config.Target,
 	}
 	// Report event dispatch time with resp status code.
 	p.StatsReporter.ReportEventDispatchTime(cctx, time.Since(startTime))
+	p.StatsReporter.ReportDeliveryAttempt(cctx, attempt)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, closeBody, fmt.Errorf("event delivery failed: HTTP status code %d", resp.StatusCode)
+		// Keep the beginning of the response body for dead letter sinks.
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorDataSize))
+		return nil, closeBody, &deliveryError{
+			statusCode: resp.StatusCode,
+			body:       body,
+			retryAfter: retryAfter(resp, time.Now()),
+		}
 	}
 
 	// Pre-check the reply response header, if it's not in structured mode/batched mode or binary mode,
@@ -311,10 +611,58 @@ func (p *Processor) sendMsg(ctx context.Context, address string, msg binding.Mes
 	return p.DeliverClient.Do(req)
 }
 
-func (p *Processor) sendToRetryTopic(ctx context.Context, target *config.Target, event *event.Event) error {
+// sendToRetryTopic sends the event to the target's retry topic. Events with an ordering key are
+// published with it, so that they are retried in order.
+func (p *Processor) sendToRetryTopic(ctx context.Context, target *config.Target, broker *config.CellTenant, event *event.Event) error {
+	if p.OrderedRetryTopics != nil && hasOrderingKey(broker, event) {
+		key, _ := eventutil.AttributeValue(event, broker.OrderingKeyAttribute)
+		if err := p.OrderedRetryTopics.Publish(ctx, target.RetryQueue.Topic, key, event); err != nil {
+			return fmt.Errorf("failed to send event to retry topic: %w", err)
+		}
+		return nil
+	}
 	pctx := cecontext.WithTopic(ctx, target.RetryQueue.Topic)
 	if err := p.DeliverRetryClient.Send(pctx, *event); err != nil {
 		return fmt.Errorf("failed to send event to retry topic: %w", err)
 	}
 	return nil
 }
+
+// sendToDeadLetterSink sends the event to the target's dead letter sink, along with extensions
+// describing the delivery failure.
+func (p *Processor) sendToDeadLetterSink(ctx context.Context, target *config.Target, e *event.Event, deliveryErr error) error {
+	transformers := []binding.Transformer{
+		transformer.DeleteExtension(eventutil.HopsAttribute),
+		transformer.DeleteExtension(eventutil.AttemptsAttribute),
+		transformer.AddExtension(errorDestExtension, target.Address),
+	}
+	var derr *deliveryError
+	if errors.As(deliveryErr, &derr) {
+		transformers = append(transformers, transformer.AddExtension(errorCodeExtension, derr.statusCode))
+		if len(derr.body) > 0 {
+			transformers = append(transformers, transformer.AddExtension(errorDataExtension, base64.StdEncoding.EncodeToString(derr.body)))
+		}
+	}
+
+	resp, err := p.sendMsg(ctx, target.DeliverySpec.DeadLetterAddress, eventutil.NewImmutableEventMessage(e), transformers...)
+	if err != nil {
+		return fmt.Errorf("failed to send event to dead letter sink: %w", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		logging.FromContext(ctx).Warn("Failed to close dead letter sink response body", zap.Error(err))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event delivery failed sending to the dead letter sink: HTTP status code %d", resp.StatusCode)
+	}
+
+	logging.FromContext(ctx).Warn("event sent to dead letter sink",
+		zap.String("target", target.Name),
+		zap.String("event.id", e.ID()),
+		zap.Error(deliveryErr),
+	)
+	trace.FromContext(ctx).Annotate(
+		[]trace.Attribute{trace.StringAttribute("error_message", deliveryErr.Error())},
+		"event sent to dead letter sink",
+	)
+	return nil
+}
@@ -19,12 +19,16 @@ package deliver
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -42,13 +46,17 @@ import (
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"knative.dev/pkg/logging"
 	logtest "knative.dev/pkg/logging/testing"
 
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	gstoragetesting "github.com/google/knative-gcp/pkg/gclient/storage/testing"
 	"github.com/google/knative-gcp/pkg/metrics"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
 
@@ -336,6 +344,8 @@ func TestProcess_WithoutSubscriberAddress(t *testing.T) {
 			})
 			ctx = handlerctx.WithBrokerKey(ctx, ct.Key())
 			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			// Failed retries of events whose Pub/Sub message counts its deliveries are nacked.
+			ctx = handlerctx.WithDeliveryAttempt(ctx, 1)
 
 			r, err := metrics.NewDeliveryReporter("pod", "container")
 			if err != nil {
@@ -511,6 +521,10 @@ func TestDeliverFailure(t *testing.T) {
 			})
 			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
 			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			if !tc.withRetry {
+				// Failed retries of events whose Pub/Sub message counts its deliveries are nacked.
+				ctx = handlerctx.WithDeliveryAttempt(ctx, 1)
+			}
 
 			r, err := metrics.NewDeliveryReporter("pod", "container")
 			if err != nil {
@@ -537,6 +551,835 @@ func TestDeliverFailure(t *testing.T) {
 	}
 }
 
+// deadLetterHandler records the events sent to a dead letter sink and responds with respCode.
+type deadLetterHandler struct {
+	t        *testing.T
+	respCode int
+	events   []event.Event
+}
+
+func (h *deadLetterHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+	if err != nil {
+		h.t.Errorf("Failed to convert dead letter request to event: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.events = append(h.events, *e)
+	w.WriteHeader(h.respCode)
+}
+
+func TestDeliverFailureWithDeadLetterSink(t *testing.T) {
+	cases := []struct {
+		name           string
+		withRetry      bool
+		retry          int32
+		attempts       int32
+		dlsRespCode    int
+		wantDeadLetter bool
+		wantAttempts   string
+		wantErr        bool
+	}{{
+		name:           "fanout without retries sends to dead letter sink",
+		withRetry:      true,
+		retry:          0,
+		dlsRespCode:    http.StatusAccepted,
+		wantDeadLetter: true,
+	}, {
+		name:         "fanout with retries enqueues for retry",
+		withRetry:    true,
+		retry:        3,
+		dlsRespCode:  http.StatusAccepted,
+		wantAttempts: "",
+	}, {
+		name:         "retry with remaining attempts requeues",
+		retry:        3,
+		attempts:     1,
+		dlsRespCode:  http.StatusAccepted,
+		wantAttempts: "2",
+	}, {
+		name:           "retry with exhausted attempts sends to dead letter sink",
+		retry:          3,
+		attempts:       2,
+		dlsRespCode:    http.StatusAccepted,
+		wantDeadLetter: true,
+	}, {
+		name:           "dead letter sink failure",
+		retry:          1,
+		dlsRespCode:    http.StatusInternalServerError,
+		wantDeadLetter: true,
+		wantErr:        true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetSvr := httptest.NewServer(&targetWithFailureHandler{
+				t:        t,
+				respCode: http.StatusInternalServerError,
+				respBody: "boom",
+			})
+			defer targetSvr.Close()
+			dlsHandler := &deadLetterHandler{t: t, respCode: tc.dlsRespCode}
+			dlsSvr := httptest.NewServer(dlsHandler)
+			defer dlsSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+				DeliverySpec: &config.DeliverySpec{
+					DeadLetterAddress: dlsSvr.URL,
+					Retry:             tc.retry,
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				RetryOnFailure:     tc.withRetry,
+				DeliverRetryClient: deliverRetryClient,
+				StatsReporter:      r,
+			}
+
+			origin := newSampleEvent()
+			if tc.attempts > 0 {
+				eventutil.SetDeliveryAttempts(origin, tc.attempts)
+			}
+			err = p.Process(ctx, origin)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("processing got error=%v, want=%v", err, tc.wantErr)
+			}
+
+			msgs := srv.Messages()
+			if tc.wantDeadLetter {
+				if len(msgs) != 0 {
+					t.Errorf("unexpected retry messages: %v", msgs)
+				}
+				if len(dlsHandler.events) != 1 {
+					t.Fatalf("dead letter sink got %d events, want 1", len(dlsHandler.events))
+				}
+				wantExtensions := map[string]interface{}{
+					"knativeerrordest": targetSvr.URL,
+					"knativeerrorcode": strconv.Itoa(http.StatusInternalServerError),
+					"knativeerrordata": base64.StdEncoding.EncodeToString([]byte("boom")),
+				}
+				if diff := cmp.Diff(wantExtensions, dlsHandler.events[0].Extensions()); diff != "" {
+					t.Errorf("dead letter event extensions (-want,+got): %v", diff)
+				}
+				return
+			}
+			if len(dlsHandler.events) != 0 {
+				t.Errorf("unexpected dead letter events: %v", dlsHandler.events)
+			}
+			if len(msgs) != 1 {
+				t.Fatalf("retry topic got %d messages, want 1", len(msgs))
+			}
+			if got := msgs[0].Attributes["ce-"+eventutil.AttemptsAttribute]; got != tc.wantAttempts {
+				t.Errorf("retry message attempts got=%q, want=%q", got, tc.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDeliverFailureEnqueuesOriginalEvent(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	targetSvr := httptest.NewServer(RetryHandler{})
+	defer targetSvr.Close()
+
+	srv, c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+	if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+		t.Fatalf("failed to create test pubsub topc: %v", err)
+	}
+	ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+	if err != nil {
+		t.Fatalf("failed to create pubsub protocol: %v", err)
+	}
+	deliverRetryClient, err := ceclient.New(ps)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents client: %v", err)
+	}
+
+	broker := &config.CellTenant{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		RetryQueue:     &config.Queue{Topic: "test-retry-topic"},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: deliverRetryClient,
+		StatsReporter:      r,
+	}
+
+	origin := newSampleEvent()
+	transformed := origin.Clone()
+	transformed.SetType("transformed")
+	if err := p.Process(handlerctx.WithOriginalEvent(ctx, origin), &transformed); err != nil {
+		t.Errorf("unexpected error from processing: %v", err)
+	}
+
+	msgs := srv.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("retry topic got %d messages, want 1", len(msgs))
+	}
+	if got := msgs[0].Attributes["ce-type"]; got != origin.Type() {
+		t.Errorf("retry message type got=%q, want=%q", got, origin.Type())
+	}
+}
+
+// recordingHandler records the last request it receives.
+type recordingHandler struct {
+	header http.Header
+	body   []byte
+}
+
+func (h *recordingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.header = req.Header
+	h.body, _ = ioutil.ReadAll(req.Body)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestDeliverRehydratesClaimCheck(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	handler := &recordingHandler{}
+	targetSvr := httptest.NewServer(handler)
+	defer targetSvr.Close()
+
+	broker := &config.CellTenant{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+	// Failed retries of events whose Pub/Sub message counts its deliveries are nacked.
+	ctx = handlerctx.WithDeliveryAttempt(ctx, 1)
+
+	storageClient, err := gstoragetesting.TestClientCreator(gstoragetesting.TestClientData{
+		BucketData: gstoragetesting.TestBucketData{
+			Objects: map[string][]byte{
+				"ns/broker/object": []byte(`{"foo":"bar"}`),
+				"ns/other/object":  []byte(`{"secret":"data"}`),
+			},
+		},
+	})(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+		Rehydrator:    &claimcheck.Rehydrator{Client: storageClient, Bucket: "bucket"},
+	}
+
+	e := newSampleEvent()
+	e.SetDataContentType("application/json")
+	e.SetExtension(claimcheck.Extension, "gs://bucket/ns/broker/object")
+	if err := p.Process(ctx, e); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got := string(handler.body); got != `{"foo":"bar"}` {
+		t.Errorf("delivered data got=%q, want the offloaded data", got)
+	}
+	if got := handler.header.Get("Ce-" + claimcheck.Extension); got != "" {
+		t.Errorf("delivered event has claim check reference %q", got)
+	}
+	if _, ok := e.Extensions()[claimcheck.Extension]; !ok {
+		t.Error("processed event lost its claim check reference")
+	}
+
+	// Events whose data can't be read back are not delivered.
+	handler.body = nil
+	e.SetExtension(claimcheck.Extension, "gs://bucket/ns/broker/missing")
+	if err := p.Process(ctx, e); err == nil {
+		t.Error("processing event with missing data got no error")
+	}
+	if handler.body != nil {
+		t.Error("event with missing data was delivered")
+	}
+
+	// The data of other brokers is not read.
+	e.SetExtension(claimcheck.Extension, "gs://bucket/ns/other/object")
+	if err := p.Process(ctx, e); err == nil {
+		t.Error("processing event with the data of another broker got no error")
+	}
+	if handler.body != nil {
+		t.Errorf("event with the data of another broker was delivered: %q", handler.body)
+	}
+}
+
+// retryAfterHandler responds with 503 and the Retry-After header.
+type retryAfterHandler struct {
+	retryAfter string
+}
+
+func (h retryAfterHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	io.Copy(ioutil.Discard, req.Body)
+	w.Header().Set("Retry-After", h.retryAfter)
+	w.WriteHeader(http.StatusServiceUnavailable)
+}
+
+// steppingClock is a fake clock whose timers fire right away by stepping the clock. It records
+// how long was waited.
+type steppingClock struct {
+	*clock.FakeClock
+	mu     sync.Mutex
+	waited time.Duration
+}
+
+func (c *steppingClock) NewTimer(d time.Duration) clock.Timer {
+	timer := c.FakeClock.NewTimer(d)
+	c.mu.Lock()
+	c.waited += d
+	c.mu.Unlock()
+	c.Step(d)
+	return timer
+}
+
+func (c *steppingClock) Waited() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.waited
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	cases := []struct {
+		name         string
+		retryAfter   string
+		backoffDelay time.Duration
+		attempts     int32
+		timeout      time.Duration
+		minDelay     time.Duration
+		maxDelay     time.Duration
+		wantAttempts string
+	}{{
+		name:         "backoff delay",
+		backoffDelay: 100 * time.Millisecond,
+		attempts:     2,
+		timeout:      time.Minute,
+		// Third failed attempt with exponential backoff and 20% jitter.
+		minDelay:     320 * time.Millisecond,
+		maxDelay:     480 * time.Millisecond,
+		wantAttempts: "3",
+	}, {
+		name:         "retry after longer than backoff delay",
+		retryAfter:   "1",
+		backoffDelay: 100 * time.Millisecond,
+		timeout:      time.Minute,
+		minDelay:     time.Second,
+		maxDelay:     time.Second,
+		wantAttempts: "1",
+	}, {
+		name:         "backoff capped by timeout",
+		retryAfter:   "60",
+		timeout:      requeueCushion + 500*time.Millisecond,
+		minDelay:     400 * time.Millisecond,
+		maxDelay:     500 * time.Millisecond,
+		wantAttempts: "1",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetSvr := httptest.NewServer(retryAfterHandler{retryAfter: tc.retryAfter})
+			defer targetSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+				DeliverySpec: &config.DeliverySpec{
+					DeadLetterAddress: "http://dead-letter.example.com",
+					Retry:             10,
+					BackoffDelay:      durationpb.New(tc.backoffDelay),
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			ctx, cancel := context.WithTimeout(ctx, tc.timeout)
+			defer cancel()
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			clk := &steppingClock{FakeClock: clock.NewFakeClock(time.Now())}
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				DeliverRetryClient: deliverRetryClient,
+				StatsReporter:      r,
+				Clock:              clk,
+			}
+
+			origin := newSampleEvent()
+			if tc.attempts > 0 {
+				eventutil.SetDeliveryAttempts(origin, tc.attempts)
+			}
+			if err := p.Process(ctx, origin); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+			if waited := clk.Waited(); waited < tc.minDelay || waited > tc.maxDelay {
+				t.Errorf("retry delay got=%v, want between %v and %v", waited, tc.minDelay, tc.maxDelay)
+			}
+
+			msgs := srv.Messages()
+			if len(msgs) != 1 {
+				t.Fatalf("retry topic got %d messages, want 1", len(msgs))
+			}
+			if got := msgs[0].Attributes["ce-"+eventutil.AttemptsAttribute]; got != tc.wantAttempts {
+				t.Errorf("retry message attempts got=%q, want=%q", got, tc.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryWithoutDeadLetterSink(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name       string
+		retryAfter string
+		attempts   int32
+		arrival    time.Time
+		// delivered is the number of deliveries of the Pub/Sub message, if the retry subscription
+		// has a dead letter policy.
+		delivered    int
+		minDelay     time.Duration
+		maxDelay     time.Duration
+		wantErr      bool
+		wantAttempts string
+	}{{
+		name:     "requeued after backoff delay",
+		attempts: 2,
+		arrival:  now.Add(-time.Hour),
+		// Third failed attempt with exponential backoff and 20% jitter.
+		minDelay:     3200 * time.Millisecond,
+		maxDelay:     4800 * time.Millisecond,
+		wantAttempts: "3",
+	}, {
+		name:         "requeued after retry after",
+		retryAfter:   "30",
+		arrival:      now.Add(-time.Hour),
+		minDelay:     30 * time.Second,
+		maxDelay:     30 * time.Second,
+		wantAttempts: "1",
+	}, {
+		name:         "requeued without arrival time",
+		minDelay:     800 * time.Millisecond,
+		maxDelay:     1200 * time.Millisecond,
+		wantAttempts: "1",
+	}, {
+		name:     "dropped after retry retention",
+		attempts: 100,
+		arrival:  now.Add(-retryRetention),
+	}, {
+		name:       "nacked with dead letter topic",
+		retryAfter: "30",
+		arrival:    now.Add(-time.Hour),
+		delivered:  3,
+		minDelay:   30 * time.Second,
+		maxDelay:   30 * time.Second,
+		wantErr:    true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetSvr := httptest.NewServer(retryAfterHandler{retryAfter: tc.retryAfter})
+			defer targetSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			// Targets without a delivery spec are backed off from the default delay.
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			if tc.delivered > 0 {
+				ctx = handlerctx.WithDeliveryAttempt(ctx, tc.delivered)
+			}
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			clk := &steppingClock{FakeClock: clock.NewFakeClock(now)}
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				DeliverRetryClient: deliverRetryClient,
+				StatsReporter:      r,
+				Clock:              clk,
+			}
+
+			origin := newSampleEvent()
+			if tc.attempts > 0 {
+				eventutil.SetDeliveryAttempts(origin, tc.attempts)
+			}
+			if !tc.arrival.IsZero() {
+				origin.SetExtension(eventutil.ArrivalTimeAttribute, tc.arrival)
+			}
+			if err := p.Process(ctx, origin); (err != nil) != tc.wantErr {
+				t.Errorf("processing got error=%v, want=%v", err, tc.wantErr)
+			}
+			if waited := clk.Waited(); waited < tc.minDelay || waited > tc.maxDelay {
+				t.Errorf("retry delay got=%v, want between %v and %v", waited, tc.minDelay, tc.maxDelay)
+			}
+
+			msgs := srv.Messages()
+			if tc.wantAttempts == "" {
+				if len(msgs) != 0 {
+					t.Errorf("retry topic got %d messages, want 0", len(msgs))
+				}
+				return
+			}
+			if len(msgs) != 1 {
+				t.Fatalf("retry topic got %d messages, want 1", len(msgs))
+			}
+			if got := msgs[0].Attributes["ce-"+eventutil.AttemptsAttribute]; got != tc.wantAttempts {
+				t.Errorf("retry message attempts got=%q, want=%q", got, tc.wantAttempts)
+			}
+			// The arrival time is kept, so that requeueing the event doesn't extend its retries.
+			arrival := tc.arrival
+			if arrival.IsZero() {
+				arrival = now
+			}
+			got, err := time.Parse(time.RFC3339Nano, msgs[0].Attributes["ce-"+eventutil.ArrivalTimeAttribute])
+			if err != nil || !got.Equal(arrival) {
+				t.Errorf("retry message arrival time got=(%v, %v), want=%v", got, err, arrival)
+			}
+		})
+	}
+}
+
+func TestFailedAttempts(t *testing.T) {
+	cases := []struct {
+		name      string
+		attempts  int32
+		delivered int
+		want      int32
+	}{{
+		name: "first retry",
+		want: 1,
+	}, {
+		name:     "requeued event",
+		attempts: 2,
+		want:     3,
+	}, {
+		name:      "redelivered message",
+		delivered: 4,
+		want:      4,
+	}, {
+		name:      "redelivered message of requeued event",
+		attempts:  2,
+		delivered: 2,
+		want:      4,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.delivered > 0 {
+				ctx = handlerctx.WithDeliveryAttempt(ctx, tc.delivered)
+			}
+			e := newSampleEvent()
+			if tc.attempts > 0 {
+				eventutil.SetDeliveryAttempts(e, tc.attempts)
+			}
+			if got := failedAttempts(ctx, e); got != tc.want {
+				t.Errorf("failedAttempts got=%d, want=%d", got, tc.want)
+			}
+		})
+	}
+}
+
+// flakyHandler fails the first failures requests with 500 and accepts the rest.
+type flakyHandler struct {
+	failures int32
+	requests int32
+}
+
+func (h *flakyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	io.Copy(ioutil.Discard, req.Body)
+	if atomic.AddInt32(&h.requests, 1) <= h.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestDeliverInOrder(t *testing.T) {
+	cases := []struct {
+		name           string
+		orderingKey    string
+		failures       int32
+		retry          int32
+		deadLetter     bool
+		timeout        time.Duration
+		wantRequests   int32
+		wantRetryAttrs []string
+		wantRetryKeys  []string
+		wantDeadLetter int
+	}{{
+		name:           "event without ordering key is requeued",
+		failures:       1,
+		timeout:        time.Minute,
+		wantRequests:   1,
+		wantRetryAttrs: []string{""},
+		wantRetryKeys:  []string{""},
+	}, {
+		name:         "retried in process until delivered",
+		orderingKey:  "key",
+		failures:     1,
+		timeout:      time.Minute,
+		wantRequests: 2,
+	}, {
+		name:        "requeued with retries when out of time",
+		orderingKey: "key",
+		failures:    100,
+		// Enough time for a single retry after the minimum delay.
+		timeout:        requeueCushion + 1500*time.Millisecond,
+		wantRequests:   2,
+		wantRetryAttrs: []string{"1"},
+		wantRetryKeys:  []string{"key"},
+	}, {
+		name:           "dead lettered when retries exhausted",
+		orderingKey:    "key",
+		failures:       100,
+		retry:          1,
+		deadLetter:     true,
+		timeout:        time.Minute,
+		wantRequests:   2,
+		wantDeadLetter: 1,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetHandler := &flakyHandler{failures: tc.failures}
+			targetSvr := httptest.NewServer(targetHandler)
+			defer targetSvr.Close()
+			dlsHandler := &deadLetterHandler{t: t, respCode: http.StatusAccepted}
+			dlsSvr := httptest.NewServer(dlsHandler)
+			defer dlsSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:                 config.CellTenantType_BROKER,
+				Namespace:            "ns",
+				Name:                 "broker",
+				OrderingKeyAttribute: "partitionkey",
+			}
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+				DeliverySpec: &config.DeliverySpec{
+					Retry:        tc.retry,
+					BackoffDelay: durationpb.New(100 * time.Millisecond),
+				},
+			}
+			if tc.deadLetter {
+				target.DeliverySpec.DeadLetterAddress = dlsSvr.URL
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.SetOrderingKeyAttribute(broker.OrderingKeyAttribute)
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			ctx, cancel := context.WithTimeout(ctx, tc.timeout)
+			defer cancel()
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				RetryOnFailure:     true,
+				DeliverRetryClient: deliverRetryClient,
+				OrderedRetryTopics: NewOrderedRetryTopics(c),
+				StatsReporter:      r,
+			}
+
+			origin := newSampleEvent()
+			if tc.orderingKey != "" {
+				origin.SetExtension("partitionkey", tc.orderingKey)
+			}
+			if err := p.Process(ctx, origin); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+
+			if got := atomic.LoadInt32(&targetHandler.requests); got != tc.wantRequests {
+				t.Errorf("target requests got=%d, want=%d", got, tc.wantRequests)
+			}
+			var gotRetryAttrs, gotRetryKeys []string
+			for _, msg := range srv.Messages() {
+				gotRetryAttrs = append(gotRetryAttrs, msg.Attributes["ce-"+eventutil.AttemptsAttribute])
+				gotRetryKeys = append(gotRetryKeys, msg.OrderingKey)
+			}
+			if diff := cmp.Diff(tc.wantRetryAttrs, gotRetryAttrs); diff != "" {
+				t.Errorf("retry message attempts (-want,+got): %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantRetryKeys, gotRetryKeys); diff != "" {
+				t.Errorf("retry message ordering keys (-want,+got): %v", diff)
+			}
+			if got := len(dlsHandler.events); got != tc.wantDeadLetter {
+				t.Errorf("dead letter events got=%d, want=%d", got, tc.wantDeadLetter)
+			}
+		})
+	}
+}
+
 type NoReplyHandler struct{}
 
 func (NoReplyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// errThrottled is returned when a delivery can't be sent before the handler times out because of
+// the target's rate limit.
+var errThrottled = errors.New("delivery throttled by rate limit")
+
+// rateLimitSettings are the limits of a target's rate limit.
+type rateLimitSettings struct {
+	requestsPerSecond int
+	burst             int
+	maxInFlight       int
+}
+
+func newRateLimitSettings(r *config.RateLimit) rateLimitSettings {
+	s := rateLimitSettings{
+		requestsPerSecond: int(r.GetRequestsPerSecond()),
+		burst:             int(r.GetBurst()),
+		maxInFlight:       int(r.GetMaxInFlight()),
+	}
+	if s.burst <= 0 {
+		s.burst = s.requestsPerSecond
+	}
+	return s
+}
+
+// targetLimiter throttles the requests sent to a target with a token bucket and a cap on the
+// number of requests in flight.
+type targetLimiter struct {
+	settings rateLimitSettings
+	// tokens is nil if the rate isn't limited.
+	tokens *rate.Limiter
+	// inFlight holds a token for each request in flight. It is nil if the concurrency isn't
+	// capped.
+	inFlight chan struct{}
+}
+
+func newTargetLimiter(settings rateLimitSettings) *targetLimiter {
+	l := &targetLimiter{settings: settings}
+	if settings.requestsPerSecond > 0 {
+		l.tokens = rate.NewLimiter(rate.Limit(settings.requestsPerSecond), settings.burst)
+	}
+	if settings.maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, settings.maxInFlight)
+	}
+	return l
+}
+
+// acquire waits until a request can be sent. It returns a function that must be called once the
+// request completes.
+func (l *targetLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+			release = func() { <-l.inFlight }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if l.tokens != nil {
+		// Wait fails right away if the wait would exceed the deadline of ctx.
+		if err := l.tokens.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// throttle waits until the target's rate limit allows another request to be sent. It returns a
+// function that must be called once the request completes, and the time spent waiting.
+func (p *Processor) throttle(ctx context.Context, target *config.Target) (func(), time.Duration, error) {
+	if target.RateLimit == nil {
+		return func() {}, 0, nil
+	}
+	start := time.Now()
+	release, err := p.limiterFor(target).acquire(ctx)
+	if err != nil {
+		return nil, time.Since(start), fmt.Errorf("%w: %v", errThrottled, err)
+	}
+	return release, time.Since(start), nil
+}
+
+// limiterFor returns the limiter of the target, replacing it if the target's rate limit changed.
+// The limiters of targets that were removed or lost their rate limit are pruned whenever a limiter
+// is created.
+func (p *Processor) limiterFor(target *config.Target) *targetLimiter {
+	key := *target.Key()
+	settings := newRateLimitSettings(target.RateLimit)
+
+	p.limitersMu.Lock()
+	defer p.limitersMu.Unlock()
+	if p.limiters == nil {
+		p.limiters = make(map[config.TargetKey]*targetLimiter)
+	}
+	l, ok := p.limiters[key]
+	if !ok || l.settings != settings {
+		p.pruneLimitersLocked()
+		l = newTargetLimiter(settings)
+		p.limiters[key] = l
+	}
+	return l
+}
+
+// pruneLimitersLocked removes the limiters of the targets that no longer have a rate limit. It
+// must be called with limitersMu held.
+func (p *Processor) pruneLimitersLocked() {
+	for key := range p.limiters {
+		key := key
+		if target, ok := p.Targets.GetTargetByKey(&key); !ok || target.RateLimit == nil {
+			delete(p.limiters, key)
+		}
+	}
+}
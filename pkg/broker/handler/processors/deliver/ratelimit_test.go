@@ -0,0 +1,294 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	"github.com/google/go-cmp/cmp"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func TestTargetLimiterMaxInFlight(t *testing.T) {
+	l := newTargetLimiter(newRateLimitSettings(&config.RateLimit{MaxInFlight: 1}))
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); err == nil {
+		t.Error("acquire succeeded with a request in flight, want error")
+	}
+
+	release()
+	release, err = l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error after release: %v", err)
+	}
+	release()
+}
+
+func TestTargetLimiterRate(t *testing.T) {
+	l := newTargetLimiter(newRateLimitSettings(&config.RateLimit{RequestsPerSecond: 10, Burst: 1}))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := l.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+	// The first request uses the burst, the next ones wait 100ms each.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("3 requests at 10 requests per second took %v, want at least 150ms", elapsed)
+	}
+}
+
+func TestNewRateLimitSettings(t *testing.T) {
+	got := newRateLimitSettings(&config.RateLimit{RequestsPerSecond: 5})
+	want := rateLimitSettings{requestsPerSecond: 5, burst: 5}
+	if got != want {
+		t.Errorf("newRateLimitSettings got=%+v, want=%+v", got, want)
+	}
+}
+
+// concurrencyHandler records the maximum number of concurrent requests it serves.
+type concurrencyHandler struct {
+	delay       time.Duration
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (h *concurrencyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	io.Copy(ioutil.Discard, req.Body)
+	n := atomic.AddInt32(&h.inFlight, 1)
+	defer atomic.AddInt32(&h.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&h.maxInFlight)
+		if n <= max || atomic.CompareAndSwapInt32(&h.maxInFlight, max, n) {
+			break
+		}
+	}
+	time.Sleep(h.delay)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestDeliverWithMaxInFlight(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	handler := &concurrencyHandler{delay: 50 * time.Millisecond}
+	targetSvr := httptest.NewServer(handler)
+	defer targetSvr.Close()
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		RateLimit:      &config.RateLimit{MaxInFlight: 2},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := p.Process(ctx, newSampleEvent()); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&handler.maxInFlight); got != 2 {
+		t.Errorf("max requests in flight got=%d, want=2", got)
+	}
+}
+
+func TestFanoutThrottleIsNotAnAttempt(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	handler := &statusHandler{statusCode: http.StatusInternalServerError}
+	targetSvr := httptest.NewServer(handler)
+	defer targetSvr.Close()
+	deadLetter := &statusHandler{statusCode: http.StatusAccepted}
+	deadLetterSvr := httptest.NewServer(deadLetter)
+	defer deadLetterSvr.Close()
+
+	srv, c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+	if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+		t.Fatalf("failed to create test pubsub topc: %v", err)
+	}
+	ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+	if err != nil {
+		t.Fatalf("failed to create pubsub protocol: %v", err)
+	}
+	deliverRetryClient, err := ceclient.New(ps)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents client: %v", err)
+	}
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+		// The target doesn't want any retries.
+		DeliverySpec: &config.DeliverySpec{
+			DeadLetterAddress: deadLetterSvr.URL,
+		},
+		RateLimit: &config.RateLimit{RequestsPerSecond: 1},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+	ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: deliverRetryClient,
+		StatsReporter:      r,
+	}
+
+	// The failed delivery is dead lettered.
+	if err := p.Process(ctx, newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	// The throttled event was never attempted, so it is retried rather than dead lettered.
+	if err := p.Process(ctx, newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got := atomic.LoadInt32(&handler.requests); got != 1 {
+		t.Errorf("requests sent to the subscriber got=%d, want=1", got)
+	}
+	if got := atomic.LoadInt32(&deadLetter.requests); got != 1 {
+		t.Errorf("requests sent to the dead letter sink got=%d, want=1", got)
+	}
+	if msgs := srv.Messages(); len(msgs) != 1 {
+		t.Errorf("retry topic got %d messages, want 1", len(msgs))
+	}
+}
+
+func TestLimitersPruned(t *testing.T) {
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	newTarget := func(name string) *config.Target {
+		return &config.Target{
+			Namespace:      "ns",
+			Name:           name,
+			CellTenantType: config.CellTenantType_BROKER,
+			CellTenantName: "broker",
+			RateLimit:      &config.RateLimit{MaxInFlight: 1},
+		}
+	}
+	removed, unlimited, kept := newTarget("removed"), newTarget("unlimited"), newTarget("kept")
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(removed, unlimited, kept)
+	})
+	p := &Processor{Targets: testTargets}
+	p.limiterFor(removed)
+	p.limiterFor(unlimited)
+	p.limiterFor(kept)
+
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.DeleteTargets(removed)
+		unlimited := newTarget("unlimited")
+		unlimited.RateLimit = nil
+		bm.UpsertTargets(unlimited)
+	})
+	added := newTarget("added")
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(added)
+	})
+	p.limiterFor(added)
+
+	var got []string
+	for key := range p.limiters {
+		got = append(got, key.String())
+	}
+	sort.Strings(got)
+	want := []string{added.Key().String(), kept.Key().String()}
+	sort.Strings(want)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected limiters (-want, +got) = %v", diff)
+	}
+}
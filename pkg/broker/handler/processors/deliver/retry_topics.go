@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"go.opencensus.io/trace"
+)
+
+// OrderedRetryTopics publishes events with an ordering key to the retry topics of targets, so
+// that the ordered retry subscriptions deliver them in order. The CloudEvents Pub/Sub protocol
+// can't set ordering keys, so they are published with the Pub/Sub client instead.
+type OrderedRetryTopics struct {
+	client *pubsub.Client
+	// topics holds a publisher with message ordering for each retry topic.
+	topics map[string]*pubsub.Topic
+	mu     sync.Mutex
+}
+
+// NewOrderedRetryTopics creates an OrderedRetryTopics publishing with the given client.
+func NewOrderedRetryTopics(client *pubsub.Client) *OrderedRetryTopics {
+	return &OrderedRetryTopics{
+		client: client,
+		topics: make(map[string]*pubsub.Topic),
+	}
+}
+
+// Publish publishes the event to the topic with the ordering key and waits for the result.
+func (t *OrderedRetryTopics) Publish(ctx context.Context, topicID, orderingKey string, e *event.Event) error {
+	dt := extensions.FromSpanContext(trace.FromContext(ctx).SpanContext())
+	msg := new(pubsub.Message)
+	if err := cepubsub.WritePubSubMessage(ctx, binding.ToMessage(e), msg, dt.WriteTransformer()); err != nil {
+		return err
+	}
+	msg.OrderingKey = orderingKey
+
+	topic := t.topic(topicID)
+	if _, err := topic.Publish(ctx, msg).Get(ctx); err != nil {
+		// A failed publish pauses publishing for the ordering key. Resume it so that the event,
+		// which is redelivered by Pub/Sub, can be requeued later.
+		topic.ResumePublish(orderingKey)
+		return err
+	}
+	return nil
+}
+
+func (t *OrderedRetryTopics) topic(topicID string) *pubsub.Topic {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	topic, ok := t.topics[topicID]
+	if !ok {
+		topic = t.client.Topic(topicID)
+		topic.EnableMessageOrdering = true
+		t.topics[topicID] = topic
+	}
+	return topic
+}
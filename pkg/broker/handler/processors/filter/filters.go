@@ -0,0 +1,213 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/utils/cesql"
+)
+
+// Expressions holds the parsed CESQL expressions of the filters of each target, so that an
+// expression is parsed once per target instead of once per event. The config stores a new copy of
+// a target whenever it changes, so the expressions of a target are parsed again when its copy
+// changes, and they are dropped once the target is no longer in the config. The zero value is
+// ready to use.
+type Expressions struct {
+	mu      sync.RWMutex
+	targets map[config.TargetKey]*targetExpressions
+}
+
+// targetExpressions are the parsed CESQL expressions of a copy of a target, keyed by their source
+// text. Expressions that failed to parse are nil.
+type targetExpressions struct {
+	target *config.Target
+	exprs  map[string]cesql.Expression
+}
+
+// PassTargetFilters checks given event against the filters of the target, like the function
+// PassTargetFilters, with the CESQL expressions of the target parsed once. targets is the config
+// the target belongs to.
+func (e *Expressions) PassTargetFilters(ctx context.Context, targets config.ReadonlyTargets, target *config.Target, event *event.Event) bool {
+	if len(target.Filters) > 0 {
+		return passFilters(ctx, target.Filters, e.forTarget(ctx, targets, target), event)
+	}
+	return PassTargetFilters(ctx, target, event)
+}
+
+// forTarget returns the parsed CESQL expressions of the target, parsing them if the target
+// changed. The expressions of the targets that are no longer in targets are pruned whenever a
+// target's expressions are parsed.
+func (e *Expressions) forTarget(ctx context.Context, targets config.ReadonlyTargets, target *config.Target) map[string]cesql.Expression {
+	key := *target.Key()
+	e.mu.RLock()
+	te, ok := e.targets[key]
+	e.mu.RUnlock()
+	if ok && te.target == target {
+		return te.exprs
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if te, ok := e.targets[key]; ok && te.target == target {
+		return te.exprs
+	}
+	if e.targets == nil {
+		e.targets = make(map[config.TargetKey]*targetExpressions)
+	}
+	e.pruneLocked(targets)
+	te = &targetExpressions{target: target, exprs: parseExpressions(ctx, target.Filters)}
+	e.targets[key] = te
+	return te.exprs
+}
+
+// pruneLocked removes the expressions of the targets that are no longer in targets, or whose copy
+// changed. It must be called with mu held.
+func (e *Expressions) pruneLocked(targets config.ReadonlyTargets) {
+	for key, te := range e.targets {
+		key := key
+		if target, ok := targets.GetTargetByKey(&key); !ok || target != te.target {
+			delete(e.targets, key)
+		}
+	}
+}
+
+// parseExpressions parses the CESQL expressions of the filters and of their nested filters.
+func parseExpressions(ctx context.Context, filters []*config.Filter) map[string]cesql.Expression {
+	exprs := make(map[string]cesql.Expression)
+	var parse func(f *config.Filter)
+	parse = func(f *config.Filter) {
+		for _, nested := range f.All {
+			parse(nested)
+		}
+		for _, nested := range f.Any {
+			parse(nested)
+		}
+		if f.Not != nil {
+			parse(f.Not)
+		}
+		if f.Cesql == "" {
+			return
+		}
+		if _, ok := exprs[f.Cesql]; ok {
+			return
+		}
+		expr, err := cesql.Parse(f.Cesql)
+		if err != nil {
+			// The webhook validates expressions, so this should not happen.
+			logging.FromContext(ctx).Error("Failed to parse CESQL expression", zap.String("expression", f.Cesql), zap.Error(err))
+			expr = nil
+		}
+		exprs[f.Cesql] = expr
+	}
+	for _, f := range filters {
+		parse(f)
+	}
+	return exprs
+}
+
+// PassFilters checks given event against all the filters. The event passes only if it passes
+// every filter. The CESQL expressions of the filters are parsed on each call.
+func PassFilters(ctx context.Context, filters []*config.Filter, event *event.Event) bool {
+	return passFilters(ctx, filters, parseExpressions(ctx, filters), event)
+}
+
+func passFilters(ctx context.Context, filters []*config.Filter, exprs map[string]cesql.Expression, event *event.Event) bool {
+	ce := eventAttributes(event)
+	for _, f := range filters {
+		if !passFilter(ctx, f, exprs, ce, event) {
+			return false
+		}
+	}
+	return true
+}
+
+func passFilter(ctx context.Context, f *config.Filter, exprs map[string]cesql.Expression, ce map[string]interface{}, event *event.Event) bool {
+	switch {
+	case len(f.Exact) > 0:
+		return matchAttributes(ctx, "exact", f.Exact, ce, func(value, want string) bool { return value == want })
+	case len(f.Prefix) > 0:
+		return matchAttributes(ctx, "prefix", f.Prefix, ce, strings.HasPrefix)
+	case len(f.Suffix) > 0:
+		return matchAttributes(ctx, "suffix", f.Suffix, ce, strings.HasSuffix)
+	case len(f.All) > 0:
+		for _, nested := range f.All {
+			if !passFilter(ctx, nested, exprs, ce, event) {
+				return false
+			}
+		}
+		return true
+	case len(f.Any) > 0:
+		for _, nested := range f.Any {
+			if passFilter(ctx, nested, exprs, ce, event) {
+				return true
+			}
+		}
+		return false
+	case f.Not != nil:
+		return !passFilter(ctx, f.Not, exprs, ce, event)
+	case f.Cesql != "":
+		return passSQL(ctx, f.Cesql, exprs[f.Cesql], event)
+	}
+	// An empty filter matches everything.
+	return true
+}
+
+func matchAttributes(ctx context.Context, dialect string, attrs map[string]string, ce map[string]interface{}, match func(value, want string) bool) bool {
+	for k, want := range attrs {
+		v, ok := ce[k]
+		if !ok {
+			logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", k), zap.String("dialect", dialect))
+			trace.FromContext(ctx).Annotatef(nil, "event missing %s filter attribute %q", dialect, k)
+			return false
+		}
+		value, err := types.Format(v)
+		if err != nil || !match(value, want) {
+			logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", k), zap.String("dialect", dialect), zap.String("filter", want), zap.Any("received", v))
+			trace.FromContext(ctx).Annotatef(nil, "event attribute %q does not match %s filter value %q", k, dialect, want)
+			return false
+		}
+	}
+	return true
+}
+
+func passSQL(ctx context.Context, sql string, expr cesql.Expression, event *event.Event) bool {
+	if expr == nil {
+		// The expression failed to parse, which was logged then.
+		trace.FromContext(ctx).Annotatef(nil, "invalid CESQL expression %q", sql)
+		return false
+	}
+	pass, err := cesql.Match(expr, *event)
+	if err != nil {
+		logging.FromContext(ctx).Debug("Failed to evaluate CESQL expression", zap.String("expression", sql), zap.Error(err))
+		trace.FromContext(ctx).Annotatef(nil, "event failed to evaluate CESQL expression %q: %v", sql, err)
+		return false
+	}
+	if !pass {
+		trace.FromContext(ctx).Annotatef(nil, "event does not match CESQL expression %q", sql)
+	}
+	return pass
+}
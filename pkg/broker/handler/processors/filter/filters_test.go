@@ -0,0 +1,218 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+)
+
+func TestPassFilters(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("//storage.googleapis.com/buckets/bucket")
+	e.SetType("com.example.order.created")
+	e.SetSubject("orders/42")
+	e.SetExtension("priority", 3)
+
+	cases := []struct {
+		name       string
+		filters    []*config.Filter
+		shouldPass bool
+	}{{
+		name:       "no filters pass",
+		shouldPass: true,
+	}, {
+		name:       "exact pass",
+		filters:    []*config.Filter{{Exact: map[string]string{"type": "com.example.order.created", "id": "id"}}},
+		shouldPass: true,
+	}, {
+		name:       "exact not pass",
+		filters:    []*config.Filter{{Exact: map[string]string{"type": "com.example.order"}}},
+		shouldPass: false,
+	}, {
+		name:       "exact missing attribute not pass",
+		filters:    []*config.Filter{{Exact: map[string]string{"region": "us"}}},
+		shouldPass: false,
+	}, {
+		name:       "exact extension pass",
+		filters:    []*config.Filter{{Exact: map[string]string{"priority": "3"}}},
+		shouldPass: true,
+	}, {
+		name:       "prefix pass",
+		filters:    []*config.Filter{{Prefix: map[string]string{"type": "com.example.order."}}},
+		shouldPass: true,
+	}, {
+		name:       "prefix not pass",
+		filters:    []*config.Filter{{Prefix: map[string]string{"type": "com.example.user."}}},
+		shouldPass: false,
+	}, {
+		name:       "suffix pass",
+		filters:    []*config.Filter{{Suffix: map[string]string{"type": ".created", "subject": "/42"}}},
+		shouldPass: true,
+	}, {
+		name:       "suffix not pass",
+		filters:    []*config.Filter{{Suffix: map[string]string{"type": ".created", "subject": "/43"}}},
+		shouldPass: false,
+	}, {
+		name: "all pass",
+		filters: []*config.Filter{{All: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.example."}},
+			{Suffix: map[string]string{"type": ".created"}},
+		}}},
+		shouldPass: true,
+	}, {
+		name: "all not pass",
+		filters: []*config.Filter{{All: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.example."}},
+			{Suffix: map[string]string{"type": ".deleted"}},
+		}}},
+		shouldPass: false,
+	}, {
+		name: "any pass",
+		filters: []*config.Filter{{Any: []*config.Filter{
+			{Suffix: map[string]string{"type": ".deleted"}},
+			{Suffix: map[string]string{"type": ".created"}},
+		}}},
+		shouldPass: true,
+	}, {
+		name: "any not pass",
+		filters: []*config.Filter{{Any: []*config.Filter{
+			{Suffix: map[string]string{"type": ".deleted"}},
+			{Suffix: map[string]string{"type": ".updated"}},
+		}}},
+		shouldPass: false,
+	}, {
+		name:       "not pass",
+		filters:    []*config.Filter{{Not: &config.Filter{Exact: map[string]string{"subject": "orders/43"}}}},
+		shouldPass: true,
+	}, {
+		name:       "not not pass",
+		filters:    []*config.Filter{{Not: &config.Filter{Exact: map[string]string{"subject": "orders/42"}}}},
+		shouldPass: false,
+	}, {
+		name:       "cesql pass",
+		filters:    []*config.Filter{{Cesql: "type LIKE 'com.example.order.%' AND priority > 2"}},
+		shouldPass: true,
+	}, {
+		name:       "cesql not pass",
+		filters:    []*config.Filter{{Cesql: "priority > 5"}},
+		shouldPass: false,
+	}, {
+		name:       "cesql evaluation error not pass",
+		filters:    []*config.Filter{{Cesql: "missing = 'x'"}},
+		shouldPass: false,
+	}, {
+		name:       "cesql invalid expression not pass",
+		filters:    []*config.Filter{{Cesql: "type ="}},
+		shouldPass: false,
+	}, {
+		name: "multiple filters pass",
+		filters: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.example."}},
+			{Cesql: "subject = 'orders/42'"},
+		},
+		shouldPass: true,
+	}, {
+		name: "multiple filters not pass",
+		filters: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.example."}},
+			{Cesql: "subject = 'orders/43'"},
+		},
+		shouldPass: false,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PassFilters(context.Background(), tc.filters, &e); got != tc.shouldPass {
+				t.Errorf("PassFilters got=%v, want=%v", got, tc.shouldPass)
+			}
+		})
+	}
+}
+
+func TestPassTargetFiltersIgnoresFilterAttributes(t *testing.T) {
+	e := event.New()
+	e.SetType("com.example.order.created")
+
+	target := &config.Target{
+		FilterAttributes: map[string]string{"type": "other"},
+		Filters:          []*config.Filter{{Prefix: map[string]string{"type": "com.example."}}},
+	}
+	if !PassTargetFilters(context.Background(), target, &e) {
+		t.Error("PassTargetFilters got=false, want=true")
+	}
+
+	target.Filters = nil
+	if PassTargetFilters(context.Background(), target, &e) {
+		t.Error("PassTargetFilters without filters got=true, want=false")
+	}
+}
+
+func TestExpressionsFollowTargetChanges(t *testing.T) {
+	e := event.New()
+	e.SetType("com.example.order.created")
+
+	ctx := context.Background()
+	targets := memory.NewEmptyTargets()
+	brokerKey := config.TestOnlyBrokerKey("ns", "broker")
+	targetKey := func(name string) *config.TargetKey {
+		return (&config.Target{Namespace: "ns", CellTenantType: config.CellTenantType_BROKER, CellTenantName: "broker", Name: name}).Key()
+	}
+	target := func(name string) *config.Target {
+		target, ok := targets.GetTargetByKey(targetKey(name))
+		if !ok {
+			t.Fatalf("target %q not found", name)
+		}
+		return target
+	}
+	upsert := func(name, sql string) {
+		targets.MutateCellTenant(brokerKey, func(m config.CellTenantMutation) {
+			m.UpsertTargets(&config.Target{Name: name, Filters: []*config.Filter{{Cesql: sql}}})
+		})
+	}
+	var exprs Expressions
+
+	upsert("t1", "type = 'com.example.order.created'")
+	if !exprs.PassTargetFilters(ctx, targets, target("t1"), &e) {
+		t.Error("PassTargetFilters got=false, want=true")
+	}
+
+	upsert("t1", "type = 'com.example.order.deleted'")
+	if exprs.PassTargetFilters(ctx, targets, target("t1"), &e) {
+		t.Error("PassTargetFilters after the filter changed got=true, want=false")
+	}
+
+	targets.MutateCellTenant(brokerKey, func(m config.CellTenantMutation) {
+		m.DeleteTargets(&config.Target{Name: "t1"})
+	})
+	upsert("t2", "type LIKE 'com.example.%'")
+	if !exprs.PassTargetFilters(ctx, targets, target("t2"), &e) {
+		t.Error("PassTargetFilters got=false, want=true")
+	}
+	if _, ok := exprs.targets[*targetKey("t1")]; ok {
+		t.Error("Expressions of the deleted target were not pruned")
+	}
+	if got := len(exprs.targets); got != 1 {
+		t.Errorf("Expressions got %d targets, want 1", got)
+	}
+}
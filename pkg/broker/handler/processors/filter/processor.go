@@ -39,6 +39,9 @@ type Processor struct {
 
 	// Targets is the targets from config.
 	Targets config.ReadonlyTargets
+
+	// expressions holds the parsed CESQL expressions of the targets.
+	expressions Expressions
 }
 
 var _ processors.Interface = (*Processor)(nil)
@@ -64,11 +67,7 @@ func (p *Processor) Process(ctx context.Context, event *event.Event) error {
 	ctx, span := startSpan(ctx, trigger, event)
 	defer span.End()
 
-	if target.FilterAttributes == nil {
-		return p.Next().Process(ctx, event)
-	}
-
-	if PassFilter(ctx, target.FilterAttributes, event) {
+	if p.expressions.PassTargetFilters(ctx, p.Targets, target, event) {
 		return p.Next().Process(ctx, event)
 	}
 	logging.FromContext(ctx).Debug("event does not pass filter for target", zap.Any("target", target))
@@ -93,9 +92,45 @@ func startSpan(ctx context.Context, trigger types.NamespacedName, event *event.E
 	return tracing.WithLogging(ctx, span), span
 }
 
+// PassTargetFilters checks given event against the filters of the target. If the target has
+// Subscriptions API filters, all of them must pass and the filter attributes are ignored.
+// Otherwise the event is checked against the filter attributes. The CESQL expressions of the
+// filters are parsed on each call, Expressions caches them.
+func PassTargetFilters(ctx context.Context, target *config.Target, event *event.Event) bool {
+	if len(target.Filters) > 0 {
+		return PassFilters(ctx, target.Filters, event)
+	}
+	if target.FilterAttributes == nil {
+		return true
+	}
+	return PassFilter(ctx, target.FilterAttributes, event)
+}
+
 // PassFilter checks given event against attributes available in the attrs map to determine
 // if the event should pass or not.
 func PassFilter(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	ce := eventAttributes(event)
+	for k, v := range attrs {
+		var value interface{}
+		value, ok := ce[k]
+		// If the attribute does not exist in the event, return false.
+		if !ok {
+			logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", k))
+			trace.FromContext(ctx).Annotatef(nil, "event missing filter attribute %q", k)
+			return false
+		}
+		// If the attribute is not set to any and is different than the one from the event, return false.
+		if v != "" && v != value {
+			logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", k), zap.String("filter", v), zap.Any("received", value))
+			trace.FromContext(ctx).Annotatef(nil, "event attribute %q does not match filter value %q", k, v)
+			return false
+		}
+	}
+	return true
+}
+
+// eventAttributes returns the context attributes and extensions of the event keyed by name.
+func eventAttributes(event *event.Event) map[string]interface{} {
 	// Set standard context attributes. The attributes available may not be
 	// exactly the same as the attributes defined in the current version of the
 	// CloudEvents spec.
@@ -116,22 +151,5 @@ func PassFilter(ctx context.Context, attrs map[string]string, event *event.Event
 	for k, v := range ext {
 		ce[k] = v
 	}
-
-	for k, v := range attrs {
-		var value interface{}
-		value, ok := ce[k]
-		// If the attribute does not exist in the event, return false.
-		if !ok {
-			logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", k))
-			trace.FromContext(ctx).Annotatef(nil, "event missing filter attribute %q", k)
-			return false
-		}
-		// If the attribute is not set to any and is different than the one from the event, return false.
-		if v != "" && v != value {
-			logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", k), zap.String("filter", v), zap.Any("received", value))
-			trace.FromContext(ctx).Annotatef(nil, "event attribute %q does not match filter value %q", k, v)
-			return false
-		}
-	}
-	return true
+	return ce
 }
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// Processor drops the events of a target's replay queue that were published at or after the end
+// of its replay. The replay subscription keeps receiving the events published to the decouple
+// topic, which the fanout delivers to the target already.
+type Processor struct {
+	processors.BaseProcessor
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process passes the event to the next processor if it was published before the end of the
+// target's replay. Otherwise it simply returns, so that the event is acked.
+func (p *Processor) Process(ctx context.Context, e *event.Event) error {
+	tk, err := handlerctx.GetTargetKey(ctx)
+	if err != nil {
+		return err
+	}
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok {
+		// If the target no longer exists, then there is nothing to process.
+		logging.FromContext(ctx).Warn("target no longer exist in the config", zap.Stringer("target", tk))
+		return nil
+	}
+	if target.ReplayEndTime == nil {
+		return p.Next().Process(ctx, e)
+	}
+	published, ok := handlerctx.GetPublishTime(ctx)
+	if ok && !published.Before(target.ReplayEndTime.AsTime()) {
+		logging.FromContext(ctx).Debug("event was published after the end of the replay",
+			zap.Stringer("target", tk), zap.String("event.id", e.ID()), zap.Time("publishTime", published))
+		return nil
+	}
+	return p.Next().Process(ctx, e)
+}
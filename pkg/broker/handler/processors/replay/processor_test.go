@@ -0,0 +1,97 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+)
+
+func TestInvalidContext(t *testing.T) {
+	p := &Processor{}
+	e := event.New()
+	err := p.Process(context.Background(), &e)
+	if err != handlerctx.ErrTargetKeyNotPresent {
+		t.Errorf("Process error got=%v, want=%v", err, handlerctx.ErrTargetKeyNotPresent)
+	}
+}
+
+func TestReplayProcessor(t *testing.T) {
+	end := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	cases := []struct {
+		name        string
+		endTime     *timestamppb.Timestamp
+		publishTime time.Time
+		wantPass    bool
+	}{{
+		name:        "unbounded replay",
+		publishTime: end,
+		wantPass:    true,
+	}, {
+		name:        "published before the end",
+		endTime:     timestamppb.New(end),
+		publishTime: end.Add(-time.Second),
+		wantPass:    true,
+	}, {
+		name:        "published at the end",
+		endTime:     timestamppb.New(end),
+		publishTime: end,
+	}, {
+		name:        "published after the end",
+		endTime:     timestamppb.New(end),
+		publishTime: end.Add(time.Second),
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := &config.Target{
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Namespace:      "ns",
+				ReplayEndTime:  tc.endTime,
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(target.Key().ParentKey(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx := handlerctx.WithTargetKey(context.Background(), target.Key())
+			ctx = handlerctx.WithPublishTime(ctx, tc.publishTime)
+
+			next := &processors.FakeProcessor{PrevEventsCh: make(chan *event.Event, 1)}
+			p := &Processor{Targets: testTargets}
+			p.WithNext(next)
+
+			e := event.New()
+			if err := p.Process(ctx, &e); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+			if gotPass := len(next.PrevEventsCh) == 1; gotPass != tc.wantPass {
+				t.Errorf("event passed got=%v, want=%v", gotPass, tc.wantPass)
+			}
+		})
+	}
+}
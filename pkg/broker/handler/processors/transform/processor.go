@@ -0,0 +1,88 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// Processor is the processor to transform events based on the target's transform.
+type Processor struct {
+	processors.BaseProcessor
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	// Rehydrator reads the data of events offloaded to GCS by the ingress back before they are
+	// transformed. If nil, such events are transformed with the reference to their data.
+	Rehydrator *claimcheck.Rehydrator
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process passes the transformed event to the next processor, along with the original event in
+// the context so that failed events are retried as they were received. Events the transform
+// fails on are not delivered to the target, as retrying them wouldn't help. Events whose offloaded
+// data can't be read back are failed, so that they are redelivered.
+func (p *Processor) Process(ctx context.Context, e *event.Event) error {
+	tk, err := handlerctx.GetTargetKey(ctx)
+	if err != nil {
+		return err
+	}
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok {
+		// If the target no longer exists, then there is nothing to process.
+		logging.FromContext(ctx).Warn("target no longer exist in the config", zap.Stringer("target", tk))
+		return nil
+	}
+	if target.Transform == nil {
+		return p.Next().Process(ctx, e)
+	}
+
+	in := e
+	if p.Rehydrator != nil {
+		// The original event keeps the reference to its data, so that it is retried with it.
+		if in, err = p.Rehydrator.Rehydrate(ctx, tk.ParentKey(), e); err != nil {
+			trace.FromContext(ctx).Annotate(
+				[]trace.Attribute{trace.StringAttribute("error_message", err.Error())},
+				"event rehydration failed",
+			)
+			return err
+		}
+	}
+	transformed, err := Apply(target.Transform, in)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to transform event, not delivering it to the target",
+			zap.Stringer("target", tk), zap.String("event.id", e.ID()), zap.Error(err))
+		trace.FromContext(ctx).Annotate(
+			[]trace.Attribute{trace.StringAttribute("error_message", err.Error())},
+			"event transform failed",
+		)
+		return nil
+	}
+	return p.Next().Process(handlerctx.WithOriginalEvent(ctx, e), transformed)
+}
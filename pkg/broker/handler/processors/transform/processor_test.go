@@ -0,0 +1,169 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	gstoragetesting "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+)
+
+func TestInvalidContext(t *testing.T) {
+	p := &Processor{}
+	e := event.New()
+	err := p.Process(context.Background(), &e)
+	if err != handlerctx.ErrTargetKeyNotPresent {
+		t.Errorf("Process error got=%v, want=%v", err, handlerctx.ErrTargetKeyNotPresent)
+	}
+}
+
+func TestTransformProcessor(t *testing.T) {
+	cases := []struct {
+		name         string
+		transform    *config.Transform
+		want         *event.Event
+		wantOriginal bool
+	}{{
+		name: "no transform",
+		want: newEvent(t, "", nil),
+	}, {
+		name:      "transform",
+		transform: &config.Transform{SetAttributes: map[string]string{"type": "com.example.order.v2"}},
+		want: func() *event.Event {
+			e := newEvent(t, "", nil)
+			e.SetType("com.example.order.v2")
+			return e
+		}(),
+		wantOriginal: true,
+	}, {
+		name:      "failed transform",
+		transform: &config.Transform{DataPatch: `[{"op":"add","path":"/status","value":"new"}]`},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, testTargets := newTestTargets(tc.transform)
+			origin := newEvent(t, "", nil)
+			var gotOriginal *event.Event
+			next := &processors.FakeProcessor{
+				PrevEventsCh: make(chan *event.Event, 1),
+				InterceptFunc: func(ctx context.Context, e *event.Event) *event.Event {
+					gotOriginal = handlerctx.GetOriginalEvent(ctx, e)
+					return e
+				},
+			}
+			p := &Processor{Targets: testTargets}
+			p.WithNext(next)
+
+			if err := p.Process(ctx, origin); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+
+			if tc.want == nil {
+				if len(next.PrevEventsCh) != 0 {
+					t.Errorf("event got passed to the next processor: %v", <-next.PrevEventsCh)
+				}
+				return
+			}
+			got := <-next.PrevEventsCh
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("processed event (-want,+got): %v", diff)
+			}
+			if tc.wantOriginal && gotOriginal != origin {
+				t.Errorf("original event got=%v, want=%v", gotOriginal, origin)
+			}
+		})
+	}
+}
+
+func TestTransformProcessorRehydratesClaimCheck(t *testing.T) {
+	ctx, testTargets := newTestTargets(&config.Transform{DataPatch: `[{"op":"add","path":"/status","value":"new"}]`})
+	storageClient, err := gstoragetesting.TestClientCreator(gstoragetesting.TestClientData{
+		BucketData: gstoragetesting.TestBucketData{
+			Objects: map[string][]byte{"ns/broker/object": []byte(`{"foo":"bar"}`)},
+		},
+	})(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotOriginal *event.Event
+	next := &processors.FakeProcessor{
+		PrevEventsCh: make(chan *event.Event, 1),
+		InterceptFunc: func(ctx context.Context, e *event.Event) *event.Event {
+			gotOriginal = handlerctx.GetOriginalEvent(ctx, e)
+			return e
+		},
+	}
+	p := &Processor{Targets: testTargets, Rehydrator: &claimcheck.Rehydrator{Client: storageClient, Bucket: "bucket"}}
+	p.WithNext(next)
+
+	origin := newEvent(t, "", nil)
+	origin.SetDataContentType(event.ApplicationJSON)
+	origin.SetExtension(claimcheck.Extension, "gs://bucket/ns/broker/object")
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	got := <-next.PrevEventsCh
+	var data map[string]string
+	if err := json.Unmarshal(got.Data(), &data); err != nil {
+		t.Fatalf("transformed data %q is not JSON: %v", got.Data(), err)
+	}
+	if diff := cmp.Diff(map[string]string{"foo": "bar", "status": "new"}, data); diff != "" {
+		t.Errorf("transformed data (-want,+got): %v", diff)
+	}
+	if _, ok := got.Extensions()[claimcheck.Extension]; ok {
+		t.Error("transformed event has the claim check reference")
+	}
+	if gotOriginal != origin {
+		t.Errorf("original event got=%v, want=%v", gotOriginal, origin)
+	}
+
+	// Events whose data can't be read back are failed rather than dropped.
+	origin.SetExtension(claimcheck.Extension, "gs://bucket/ns/broker/missing")
+	if err := p.Process(ctx, origin); err == nil {
+		t.Error("processing event with missing data got no error")
+	}
+	if len(next.PrevEventsCh) != 0 {
+		t.Errorf("event got passed to the next processor: %v", <-next.PrevEventsCh)
+	}
+}
+
+func newTestTargets(transform *config.Transform) (context.Context, config.Targets) {
+	testTarget := &config.Target{
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Namespace:      "ns",
+		Transform:      transform,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(testTarget.Key().ParentKey(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(testTarget)
+	})
+	ctx := handlerctx.WithTargetKey(context.Background(), testTarget.Key())
+	return ctx, testTargets
+}
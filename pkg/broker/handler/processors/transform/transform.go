@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	jsonpatch "github.com/evanphx/json-patch"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// Apply returns a copy of the event rewritten by the transform. The extensions to remove are
+// removed first, then the attributes are set and finally the data is patched.
+func Apply(transform *config.Transform, e *event.Event) (*event.Event, error) {
+	out := e.Clone()
+	for _, attr := range transform.RemoveAttributes {
+		out.SetExtension(attr, nil)
+	}
+	for attr, value := range transform.SetAttributes {
+		switch attr {
+		case "type":
+			out.SetType(value)
+		case "source":
+			out.SetSource(value)
+		case "subject":
+			out.SetSubject(value)
+		case "dataschema":
+			out.SetDataSchema(value)
+		default:
+			out.SetExtension(attr, value)
+		}
+	}
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid transformed event: %w", err)
+	}
+	if transform.DataPatch != "" {
+		data, err := patchData(transform.DataPatch, &out)
+		if err != nil {
+			return nil, fmt.Errorf("failed to patch event data: %w", err)
+		}
+		out.DataEncoded = data
+	}
+	return &out, nil
+}
+
+// patchData applies the JSON patch to the data of the event.
+func patchData(rawPatch string, e *event.Event) ([]byte, error) {
+	if mt := e.DataMediaType(); mt != "" && mt != event.ApplicationJSON && !strings.HasSuffix(mt, "+json") {
+		return nil, fmt.Errorf("data media type %q is not JSON", mt)
+	}
+	if len(e.Data()) == 0 {
+		return nil, errors.New("event has no data")
+	}
+	patch, err := jsonpatch.DecodePatch([]byte(rawPatch))
+	if err != nil {
+		return nil, err
+	}
+	return patch.Apply(e.Data())
+}
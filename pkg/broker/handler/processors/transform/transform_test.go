@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func newEvent(t *testing.T, contentType string, data []byte) *event.Event {
+	t.Helper()
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("com.example.order.created")
+	e.SetExtension("region", "us")
+	e.SetExtension("kgcphops", 3)
+	if data != nil {
+		if err := e.SetData(contentType, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &e
+}
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		name      string
+		transform *config.Transform
+		event     *event.Event
+		want      *event.Event
+		wantErr   bool
+	}{{
+		name:      "empty transform",
+		transform: &config.Transform{},
+		event:     newEvent(t, "", nil),
+		want:      newEvent(t, "", nil),
+	}, {
+		name: "set and remove attributes",
+		transform: &config.Transform{
+			SetAttributes:    map[string]string{"type": "com.example.order.v2", "subject": "orders", "tier": "gold", "region": "eu"},
+			RemoveAttributes: []string{"region", "missing"},
+		},
+		event: newEvent(t, "", nil),
+		want: func() *event.Event {
+			e := newEvent(t, "", nil)
+			e.SetType("com.example.order.v2")
+			e.SetSubject("orders")
+			e.SetExtension("tier", "gold")
+			e.SetExtension("region", "eu")
+			return e
+		}(),
+	}, {
+		name:      "remove extension",
+		transform: &config.Transform{RemoveAttributes: []string{"region"}},
+		event:     newEvent(t, "", nil),
+		want: func() *event.Event {
+			e := newEvent(t, "", nil)
+			e.SetExtension("region", nil)
+			return e
+		}(),
+	}, {
+		name:      "invalid source",
+		transform: &config.Transform{SetAttributes: map[string]string{"source": ""}},
+		event:     newEvent(t, "", nil),
+		wantErr:   true,
+	}, {
+		name:      "patch data",
+		transform: &config.Transform{DataPatch: `[{"op":"add","path":"/status","value":"new"},{"op":"remove","path":"/internal"}]`},
+		event:     newEvent(t, event.ApplicationJSON, []byte(`{"id":1,"internal":true}`)),
+		want:      newEvent(t, event.ApplicationJSON, []byte(`{"id":1,"status":"new"}`)),
+	}, {
+		name:      "patch data without content type",
+		transform: &config.Transform{DataPatch: `[{"op":"replace","path":"/id","value":2}]`},
+		event:     newEvent(t, "", []byte(`{"id":1}`)),
+		want:      newEvent(t, "", []byte(`{"id":2}`)),
+	}, {
+		name:      "patch non JSON data",
+		transform: &config.Transform{DataPatch: `[{"op":"add","path":"/status","value":"new"}]`},
+		event:     newEvent(t, "text/plain", []byte("hello")),
+		wantErr:   true,
+	}, {
+		name:      "patch no data",
+		transform: &config.Transform{DataPatch: `[{"op":"add","path":"/status","value":"new"}]`},
+		event:     newEvent(t, "", nil),
+		wantErr:   true,
+	}, {
+		name:      "failed patch",
+		transform: &config.Transform{DataPatch: `[{"op":"test","path":"/id","value":2}]`},
+		event:     newEvent(t, event.ApplicationJSON, []byte(`{"id":1}`)),
+		wantErr:   true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := tc.event.Clone()
+			got, err := Apply(tc.transform, tc.event)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Apply got error=%v, want=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(&original, tc.event); diff != "" {
+				t.Errorf("Apply modified the event (-want,+got): %v", diff)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Apply (-want,+got): %v", diff)
+			}
+		})
+	}
+}
@@ -25,12 +25,15 @@ import (
 	"go.uber.org/zap"
 
 	"cloud.google.com/go/pubsub"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/replay"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/transform"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
@@ -38,12 +41,22 @@ import (
 // For each trigger in the config, it will attempt to create a handler.
 // It will also stop/delete the handler if the corresponding trigger is deleted
 // in the config.
+// Triggers replaying the events of their broker get another handler that pulls
+// from their replay subscription.
 type RetryPool struct {
 	options *Options
 	targets config.ReadonlyTargets
 	pool    *syncMapTargetKey
+	// replayPool holds the handlers of the replay subscriptions.
+	replayPool *syncMapTargetKey
 	// Pubsub client used to pull events from decoupling topics.
 	pubsubClient *pubsub.Client
+	// For requeueing events of targets with a dead letter sink. We only need
+	// a shared client. And we can set retry topic dynamically.
+	deliverRetryClient ceclient.Client
+	// For publishing events with an ordering key to the retry topics of
+	// targets of ordered brokers.
+	orderedRetryTopics *deliver.OrderedRetryTopics
 	// For initial events delivery. We only need a shared client.
 	// And we can set target address dynamically.
 	deliverClient *http.Client
@@ -53,6 +66,16 @@ type RetryPool struct {
 type retryHandlerCache struct {
 	Handler
 	t *config.Target
+	// queue returns the queue of the target the handler pulls from.
+	queue func(*config.Target) *config.Queue
+}
+
+func retryQueue(t *config.Target) *config.Queue {
+	return t.RetryQueue
+}
+
+func replayQueue(t *config.Target) *config.Queue {
+	return t.ReplayQueue
 }
 
 // If somehow the existing handler's setting has deviated from the current target config,
@@ -63,11 +86,11 @@ func (hc *retryHandlerCache) shouldRenew(t *config.Target) bool {
 	}
 	// If this really happens, it means a data corruption.
 	// The handler creation will fail (which is expected).
-	if t == nil || t.RetryQueue == nil {
+	if t == nil || hc.queue(t) == nil {
 		return true
 	}
-	if t.RetryQueue.Topic != hc.t.RetryQueue.Topic ||
-		t.RetryQueue.Subscription != hc.t.RetryQueue.Subscription {
+	if hc.queue(t).Topic != hc.queue(hc.t).Topic ||
+		hc.queue(t).Subscription != hc.queue(hc.t).Subscription {
 		return true
 	}
 	return false
@@ -78,6 +101,7 @@ func NewRetryPool(
 	targets config.ReadonlyTargets,
 	pubsubClient *pubsub.Client,
 	deliverClient *http.Client,
+	retryClient RetryClient,
 	statsReporter *metrics.DeliveryReporter,
 	opts ...Option) (*RetryPool, error) {
 	options, err := NewOptions(opts...)
@@ -86,12 +110,15 @@ func NewRetryPool(
 	}
 
 	p := &RetryPool{
-		targets:       targets,
-		options:       options,
-		pool:          &syncMapTargetKey{},
-		pubsubClient:  pubsubClient,
-		deliverClient: deliverClient,
-		statsReporter: statsReporter,
+		targets:            targets,
+		options:            options,
+		pool:               &syncMapTargetKey{},
+		replayPool:         &syncMapTargetKey{},
+		pubsubClient:       pubsubClient,
+		orderedRetryTopics: deliver.NewOrderedRetryTopics(pubsubClient),
+		deliverClient:      deliverClient,
+		deliverRetryClient: retryClient,
+		statsReporter:      statsReporter,
 	}
 	return p, nil
 }
@@ -103,24 +130,37 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 		logging.FromContext(ctx).Error("failed to add tags to context", zap.Error(err))
 	}
 
-	p.pool.Range(func(key config.TargetKey, value *retryHandlerCache) bool {
+	p.syncHandlers(ctx, p.pool, retryQueue, false)
+	p.syncHandlers(ctx, p.replayPool, replayQueue, true)
+	return nil
+}
+
+// syncHandlers syncs the handlers in pool with the targets that have the queue.
+// Events pulled from replay subscriptions are delivered like in the fanout pods,
+// so the events that fail to be delivered are sent to the retry topic. Those
+// published after the end of the replay are dropped, as the fanout delivers them.
+func (p *RetryPool) syncHandlers(ctx context.Context, pool *syncMapTargetKey, queue func(*config.Target) *config.Queue, replaying bool) {
+	pool.Range(func(key config.TargetKey, value *retryHandlerCache) bool {
 		// Each target represents a trigger.
-		if _, ok := p.targets.GetTargetByKey(&key); !ok {
+		if t, ok := p.targets.GetTargetByKey(&key); !ok || queue(t) == nil {
 			value.Stop()
-			p.pool.Delete(key)
+			pool.Delete(key)
 		}
 		return true
 	})
 
 	p.targets.RangeAllTargets(func(t *config.Target) bool {
-		if value, ok := p.pool.Load(*t.Key()); ok {
+		if queue(t) == nil {
+			return true
+		}
+		if value, ok := pool.Load(*t.Key()); ok {
 			// Skip if we don't need to renew the handler.
 			if !value.shouldRenew(t) {
 				return true
 			}
 			// Stop and clean up the old handler before we start a new one.
 			value.Stop()
-			p.pool.Delete(*t.Key())
+			pool.Delete(*t.Key())
 		}
 
 		// Don't start the handler if the target is not ready.
@@ -129,24 +169,33 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 			return true
 		}
 
-		sub := p.pubsubClient.Subscription(t.RetryQueue.Subscription)
+		subID := queue(t).Subscription
+		sub := p.pubsubClient.Subscription(subID)
 		sub.ReceiveSettings = p.options.PubsubReceiveSettings
 
-		h := NewHandler(
-			sub,
-			processors.ChainProcessors(
-				&filter.Processor{Targets: p.targets},
-				&deliver.Processor{
-					DeliverClient: p.deliverClient,
-					Targets:       p.targets,
-					StatsReporter: p.statsReporter,
-				},
-			),
-			p.options.TimeoutPerEvent,
+		var chain []processors.ChainableProcessor
+		if replaying {
+			chain = append(chain, &replay.Processor{Targets: p.targets})
+		}
+		chain = append(chain,
+			&filter.Processor{Targets: p.targets},
+			&transform.Processor{Targets: p.targets, Rehydrator: p.options.Rehydrator},
+			&deliver.Processor{
+				DeliverClient:      p.deliverClient,
+				Targets:            p.targets,
+				RetryOnFailure:     replaying,
+				DeliverRetryClient: p.deliverRetryClient,
+				OrderedRetryTopics: p.orderedRetryTopics,
+				StatsReporter:      p.statsReporter,
+				CircuitBreakers:    p.options.CircuitBreakers,
+				Rehydrator:         p.options.Rehydrator,
+			},
 		)
+		h := NewHandler(sub, processors.ChainProcessors(chain[0], chain[1:]...), p.options.TimeoutPerEvent)
 		hc := &retryHandlerCache{
 			Handler: *h,
 			t:       t,
+			queue:   queue,
 		}
 
 		ctx, err := metrics.AddTargetTags(ctx, t)
@@ -161,17 +210,15 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 		hc.Start(ctx, func(err error) {
 			// We will anyway get an error because of https://github.com/cloudevents/sdk-go/issues/470
 			if err != nil {
-				logging.FromContext(ctx).Error("handler for trigger has stopped with error", zap.Stringer("trigger", t.Key()), zap.Error(err))
+				logging.FromContext(ctx).Error("handler for trigger has stopped with error", zap.Stringer("trigger", t.Key()), zap.String("subscription", subID), zap.Error(err))
 			} else {
-				logging.FromContext(ctx).Info("handler for trigger has stopped", zap.Stringer("trigger", t.Key()))
+				logging.FromContext(ctx).Info("handler for trigger has stopped", zap.Stringer("trigger", t.Key()), zap.String("subscription", subID))
 			}
 		})
 
-		p.pool.Store(*t.Key(), hc)
+		pool.Store(*t.Key(), hc)
 		return true
 	})
-
-	return nil
 }
 
 // syncMapTargetKey is a typed version of sync.Map.
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"cloud.google.com/go/pubsub"
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
@@ -51,7 +52,7 @@ func TestRetryWatchAndSync(t *testing.T) {
 	defer helper.Close()
 
 	signal := make(chan struct{})
-	syncPool, err := InitializeTestRetryPool(helper.Targets, retryPod, retryContainer, helper.PubsubClient)
+	syncPool, err := InitializeTestRetryPool(ctx, helper.Targets, retryPod, retryContainer, helper.PubsubClient)
 	if err != nil {
 		t.Errorf("unexpected error from getting sync pool: %v", err)
 	}
@@ -148,7 +149,7 @@ func TestRetrySyncPoolE2E(t *testing.T) {
 	expectMetrics.AddTrigger(t, trigger(t3), wantRetryTags())
 
 	signal := make(chan struct{})
-	syncPool, err := InitializeTestRetryPool(helper.Targets, retryPod, retryContainer, helper.PubsubClient)
+	syncPool, err := InitializeTestRetryPool(ctx, helper.Targets, retryPod, retryContainer, helper.PubsubClient)
 	if err != nil {
 		t.Errorf("unexpected error from getting sync pool: %v", err)
 	}
@@ -321,6 +322,83 @@ func TestRetrySyncPoolE2E(t *testing.T) {
 	})
 }
 
+func TestRetryPoolReplay(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	testProject := "test-project"
+
+	helper, err := handlertesting.NewHelper(ctx, testProject)
+	if err != nil {
+		t.Fatalf("failed to create pool testing helper: %v", err)
+	}
+	defer helper.Close()
+
+	b := helper.GenerateBroker(ctx, t, "ns")
+	t1 := helper.GenerateTarget(ctx, t, b.Key(), nil)
+
+	// The replay subscription of the target pulls from the decouple topic of the broker.
+	replaySub := "replay-sub-" + t1.Name
+	if _, err := helper.PubsubClient.CreateSubscription(ctx, replaySub, pubsub.SubscriptionConfig{
+		Topic: helper.PubsubClient.Topic(b.DecoupleQueue.Topic),
+	}); err != nil {
+		t.Fatalf("failed to create test target replay subscription: %v", err)
+	}
+	t1.ReplayQueue = &config.Queue{
+		Topic:        b.DecoupleQueue.Topic,
+		Subscription: replaySub,
+	}
+	helper.Targets.MutateCellTenant(b.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(t1)
+	})
+
+	signal := make(chan struct{})
+	syncPool, err := InitializeTestRetryPool(ctx, helper.Targets, retryPod, retryContainer, helper.PubsubClient)
+	if err != nil {
+		t.Errorf("unexpected error from getting sync pool: %v", err)
+	}
+	p, err := GetFreePort()
+	if err != nil {
+		t.Fatalf("failed to get random free port: %v", err)
+	}
+	if _, err := StartSyncPool(ctx, syncPool, signal, time.Minute, p, &authcheck.FakeAuthenticationCheck{}); err != nil {
+		t.Errorf("unexpected error from starting sync pool: %v", err)
+	}
+	if _, ok := syncPool.replayPool.Load(*t1.Key()); !ok {
+		t.Error("no replay handler created for target with a replay queue")
+	}
+
+	t.Run("target receives replayed events", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		e := genTestEvent("foo", "bar", "id", "source")
+
+		group, ctx := errgroup.WithContext(ctx)
+		group.Go(func() error {
+			helper.VerifyNextTargetEvent(ctx, t, t1.Key(), &e)
+			return nil
+		})
+		helper.SendEventToDecoupleQueue(ctx, t, b.Key(), &e)
+		if err := group.Wait(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("replay handler stopped once the replay is removed", func(t *testing.T) {
+		t1.ReplayQueue = nil
+		helper.Targets.MutateCellTenant(b.Key(), func(bm config.CellTenantMutation) {
+			bm.UpsertTargets(t1)
+		})
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		if _, ok := syncPool.replayPool.Load(*t1.Key()); ok {
+			t.Error("replay handler not stopped after the replay queue is removed")
+		}
+		assertRetryHandlers(t, syncPool, helper.Targets)
+	})
+}
+
 func assertRetryHandlers(t *testing.T, p *RetryPool, targets config.Targets) {
 	t.Helper()
 	gotHandlers := make(map[config.TargetKey]bool)
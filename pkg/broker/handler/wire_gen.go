@@ -37,13 +37,18 @@ var (
 	_wireValue       = DefaultCEClientOpts
 )
 
-func InitializeTestRetryPool(targets config.ReadonlyTargets, podName metrics.PodName, containerName metrics.ContainerName, pubsubClient *pubsub.Client, opts ...Option) (*RetryPool, error) {
+func InitializeTestRetryPool(ctx context.Context, targets config.ReadonlyTargets, podName metrics.PodName, containerName metrics.ContainerName, pubsubClient *pubsub.Client, opts ...Option) (*RetryPool, error) {
 	client := _wireHttpClientValue
+	v := _wireValue2
+	retryClient, err := NewRetryClient(ctx, pubsubClient, v...)
+	if err != nil {
+		return nil, err
+	}
 	deliveryReporter, err := metrics.NewDeliveryReporter(podName, containerName)
 	if err != nil {
 		return nil, err
 	}
-	retryPool, err := NewRetryPool(targets, pubsubClient, client, deliveryReporter, opts...)
+	retryPool, err := NewRetryPool(targets, pubsubClient, client, retryClient, deliveryReporter, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -52,4 +57,5 @@ func InitializeTestRetryPool(targets config.ReadonlyTargets, podName metrics.Pod
 
 var (
 	_wireHttpClientValue = DefaultHTTPClient
+	_wireValue2          = DefaultCEClientOpts
 )
@@ -28,3 +28,30 @@ var ErrIncomplete = errors.New("incomplete config")
 
 // ErrNotReady is the error when a broker is not ready.
 var ErrNotReady = errors.New("not ready")
+
+// ErrUnauthenticated is the error when a request to a broker with an ingress policy doesn't carry a
+// valid token of one of the policy's issuers.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ErrForbidden is the error when the ingress policy of a broker doesn't allow the sender to send
+// the event.
+var ErrForbidden = errors.New("forbidden")
+
+// ErrKeySetUnavailable is the error when the key set needed to verify a token can't be fetched.
+var ErrKeySetUnavailable = errors.New("key set unavailable")
+
+// policyError is the error when a request is rejected by the ingress policy of a broker. It wraps
+// ErrUnauthenticated, ErrForbidden or ErrKeySetUnavailable.
+type policyError struct {
+	// reason is a short description of the rejection, used to tag metrics.
+	reason string
+	err    error
+}
+
+func (e *policyError) Error() string {
+	return e.err.Error()
+}
+
+func (e *policyError) Unwrap() error {
+	return e.err
+}
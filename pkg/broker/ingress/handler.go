@@ -22,7 +22,9 @@ import (
 	nethttp "net/http"
 	"time"
 
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 
 	cev2 "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
@@ -51,14 +53,15 @@ const (
 	// TODO(liu-cong) configurable timeout
 	decoupleSinkTimeout = 30 * time.Second
 
-	// Limit for request payload in bytes (10Mb -- corresponds to message size limit on PubSub as of 09/2020)
-	maxRequestBodyBytes = 10000000
+	// DefaultMaxRequestBodyBytes is the default limit for request payload in bytes (10Mb --
+	// corresponds to message size limit on PubSub as of 09/2020).
+	DefaultMaxRequestBodyBytes = 10000000
 
 	// EventArrivalTime is used to access the metadata stored on a
 	// CloudEvent to measure the time difference between when an event is
 	// received on a broker and before it is dispatched to the trigger function.
 	// The format is an RFC3339 time in string format. For example: 2019-08-26T23:38:17.834384404Z.
-	EventArrivalTime = "knativearrivaltime"
+	EventArrivalTime = eventutil.ArrivalTimeAttribute
 
 	// for permission denied error msg
 	// TODO(cathyzhyi) point to official doc rather than github doc
@@ -73,6 +76,8 @@ var HandlerSet wire.ProviderSet = wire.NewSet(
 	wire.Bind(new(HttpMessageReceiver), new(*kncloudevents.HTTPMessageReceiver)),
 	NewMultiTopicDecoupleSink,
 	wire.Bind(new(DecoupleSink), new(*multiTopicDecoupleSink)),
+	NewPolicyAuthorizer,
+	wire.Bind(new(SenderAuthorizer), new(*policyAuthorizer)),
 	clients.NewPubsubClient,
 	metrics.NewIngressReporter,
 )
@@ -83,6 +88,10 @@ type DecoupleSink interface {
 	Send(ctx context.Context, broker *config.CellTenantKey, event cev2.Event) protocol.Result
 }
 
+// MaxRequestBodyBytes is the limit for request payload in bytes. Requests with larger payloads
+// are rejected with 413.
+type MaxRequestBodyBytes int64
+
 // HttpMessageReceiver is an interface to listen on http requests.
 type HttpMessageReceiver interface {
 	StartListen(ctx context.Context, handler nethttp.Handler) error
@@ -94,19 +103,33 @@ type Handler struct {
 	httpReceiver HttpMessageReceiver
 	// decouple is the client to send events to a decouple sink.
 	decouple DecoupleSink
-	logger   *zap.Logger
-	reporter *metrics.IngressReporter
-	authType authcheck.AuthType
+	// authorizer authenticates and authorizes the senders of events with the brokers' ingress
+	// policies.
+	authorizer SenderAuthorizer
+	logger     *zap.Logger
+	reporter   *metrics.IngressReporter
+	authType   authcheck.AuthType
+	// maxRequestBodyBytes is the limit for request payload in bytes.
+	maxRequestBodyBytes int64
+	// offloader offloads the data of large events to GCS. If nil, the data of all the events is
+	// published to the decouple sink.
+	offloader *claimcheck.Offloader
 }
 
 // NewHandler creates a new ingress handler.
-func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, reporter *metrics.IngressReporter, authType authcheck.AuthType) *Handler {
+func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, authorizer SenderAuthorizer, reporter *metrics.IngressReporter, authType authcheck.AuthType, maxRequestBodyBytes MaxRequestBodyBytes, offloader *claimcheck.Offloader) *Handler {
+	if maxRequestBodyBytes <= 0 {
+		maxRequestBodyBytes = DefaultMaxRequestBodyBytes
+	}
 	return &Handler{
-		httpReceiver: httpReceiver,
-		decouple:     decouple,
-		reporter:     reporter,
-		logger:       logging.FromContext(ctx),
-		authType:     authType,
+		httpReceiver:        httpReceiver,
+		decouple:            decouple,
+		authorizer:          authorizer,
+		reporter:            reporter,
+		logger:              logging.FromContext(ctx),
+		authType:            authType,
+		maxRequestBodyBytes: int64(maxRequestBodyBytes),
+		offloader:           offloader,
 	}
 }
 
@@ -118,8 +141,10 @@ func (h *Handler) Start(ctx context.Context) error {
 // ServeHTTP implements net/http Handler interface method.
 // 1. Performs basic validation of the request.
 // 2. Parse request URL to get namespace and broker.
-// 3. Convert request to event.
-// 4. Send event to decouple sink.
+// 3. Authenticate the sender with the broker's ingress policy.
+// 4. Convert request to event.
+// 5. Authorize the sender to send the event.
+// 6. Send event to decouple sink.
 func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
 	ctx := request.Context()
 	ctx = logging.WithLogger(ctx, h.logger)
@@ -130,11 +155,11 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 		return
 	}
 
-	if request.ContentLength > maxRequestBodyBytes {
+	if request.ContentLength > h.maxRequestBodyBytes {
 		response.WriteHeader(nethttp.StatusRequestEntityTooLarge)
 		return
 	}
-	request.Body = nethttp.MaxBytesReader(nil, request.Body, maxRequestBodyBytes)
+	request.Body = nethttp.MaxBytesReader(nil, request.Body, h.maxRequestBodyBytes)
 
 	broker, err := config.CellTenantKeyFromPersistenceString(request.URL.Path)
 	if err != nil {
@@ -145,6 +170,14 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 	ctx = logging.With(ctx, zap.Stringer("broker", broker))
 	ctx = metricskey.WithResource(ctx, broker.MetricsResource())
 
+	// Authenticate before reading the body, so that unauthenticated senders can't make the
+	// ingress parse large payloads.
+	sender, err := h.authorizer.Authenticate(ctx, broker, request.Header)
+	if err != nil {
+		h.rejectByPolicy(ctx, response, "", err)
+		return
+	}
+
 	event, err := h.toEvent(ctx, request)
 	if err != nil {
 		httpStatus := nethttp.StatusBadRequest
@@ -152,10 +185,18 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 			httpStatus = nethttp.StatusRequestEntityTooLarge
 		}
 		nethttp.Error(response, err.Error(), httpStatus)
-		h.reportMetrics(ctx, "_invalid_cloud_event_", httpStatus)
+		h.reportMetrics(ctx, "_invalid_cloud_event_", httpStatus, "")
+		return
+	}
+
+	if err := h.authorizer.Authorize(ctx, broker, sender, event); err != nil {
+		h.rejectByPolicy(ctx, response, event.Type(), err)
 		return
 	}
 
+	// Only the ingress sets the claim check reference of events, so that senders can't make the
+	// broker deliver GCS objects they have no access to.
+	event.SetExtension(claimcheck.Extension, nil)
 	event.SetExtension(EventArrivalTime, cev2.Timestamp{Time: time.Now()})
 
 	span := trace.FromContext(ctx)
@@ -172,13 +213,22 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 		)
 	}
 
+	if h.offloader != nil {
+		if _, err := h.offloader.Offload(ctx, broker, event); err != nil {
+			logging.FromContext(ctx).Error("Error offloading event data to GCS", zap.Error(err))
+			nethttp.Error(response, "Failed to offload event data", nethttp.StatusInternalServerError)
+			h.reportMetrics(ctx, event.Type(), nethttp.StatusInternalServerError, "")
+			return
+		}
+	}
+
 	// Optimistically set status code to StatusAccepted. It will be updated if there is an error.
 	// According to the data plane spec (https://github.com/knative/eventing/blob/master/docs/spec/data-plane.md), a
 	// non-callable SINK (which broker is) MUST respond with 202 Accepted if the request is accepted.
 	statusCode := nethttp.StatusAccepted
 	ctx, cancel := context.WithTimeout(ctx, decoupleSinkTimeout)
 	defer cancel()
-	defer func() { h.reportMetrics(ctx, event.Type(), statusCode) }()
+	defer func() { h.reportMetrics(ctx, event.Type(), statusCode, "") }()
 	if res := h.decouple.Send(ctx, broker, *event); !cev2.IsACK(res) {
 		logging.FromContext(ctx).Error("Error publishing to PubSub", zap.Error(res))
 		statusCode = nethttp.StatusInternalServerError
@@ -201,6 +251,36 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 	response.WriteHeader(statusCode)
 }
 
+// rejectByPolicy responds to a request rejected by the broker's ingress policy. Unauthenticated
+// senders get a 401 and unauthorized senders a 403.
+func (h *Handler) rejectByPolicy(ctx context.Context, response nethttp.ResponseWriter, eventType string, err error) {
+	reason := ""
+	var pe *policyError
+	if errors.As(err, &pe) {
+		reason = pe.reason
+	}
+	statusCode := nethttp.StatusForbidden
+	message := err.Error()
+	switch {
+	case errors.Is(err, ErrKeySetUnavailable):
+		logging.FromContext(ctx).Error("Unable to verify sender credentials", zap.Error(err))
+		statusCode = nethttp.StatusServiceUnavailable
+		message = "Failed to verify credentials"
+	case errors.Is(err, ErrUnauthenticated):
+		logging.FromContext(ctx).Debug("Unauthenticated request", zap.Error(err))
+		statusCode = nethttp.StatusUnauthorized
+		if reason == reasonMissingToken {
+			response.Header().Set("WWW-Authenticate", "Bearer")
+		} else {
+			response.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		}
+	default:
+		logging.FromContext(ctx).Debug("Unauthorized request", zap.Error(err))
+	}
+	nethttp.Error(response, message, statusCode)
+	h.reportMetrics(ctx, eventType, statusCode, reason)
+}
+
 // toEvent converts an http request to an event.
 func (h *Handler) toEvent(ctx context.Context, request *nethttp.Request) (*cev2.Event, error) {
 	message := http.NewMessageFromHttpRequest(request)
@@ -222,10 +302,11 @@ func (h *Handler) toEvent(ctx context.Context, request *nethttp.Request) (*cev2.
 	return event, nil
 }
 
-func (h *Handler) reportMetrics(ctx context.Context, eventType string, statusCode int) {
+func (h *Handler) reportMetrics(ctx context.Context, eventType string, statusCode int, reason string) {
 	args := metrics.IngressReportArgs{
 		EventType:    eventType,
 		ResponseCode: statusCode,
+		Reason:       reason,
 	}
 	if err := h.reporter.ReportEventCount(ctx, args); err != nil {
 		logging.FromContext(ctx).Warn("Failed to record metrics.", zap.Error(err))
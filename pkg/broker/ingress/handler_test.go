@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	nethttp "net/http"
 	"net/http/httptest"
@@ -38,8 +39,10 @@ import (
 	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	gstoragetesting "github.com/google/knative-gcp/pkg/gclient/storage/testing"
 	"github.com/google/knative-gcp/pkg/metrics"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
 	kgcptesting "github.com/google/knative-gcp/pkg/testing"
@@ -132,6 +135,13 @@ type testCase struct {
 	decouple        DecoupleSink
 	contentLength   *int64
 	timeout         time.Duration
+	// maxRequestBodyBytes is the payload limit of the ingress. If zero, the default is used.
+	maxRequestBodyBytes MaxRequestBodyBytes
+	offloader           *claimcheck.Offloader
+	// authorizer authorizes the senders. If nil, the ingress policies of brokerConfig are used.
+	authorizer SenderAuthorizer
+	// wantHeader are the headers expected in the response.
+	wantHeader nethttp.Header
 }
 
 type fakeOverloadedDecoupleSink struct{}
@@ -140,6 +150,23 @@ func (m *fakeOverloadedDecoupleSink) Send(_ context.Context, _ *config.CellTenan
 	return bundler.ErrOverflow
 }
 
+// fakeAuthorizer rejects the requests with the given errors.
+type fakeAuthorizer struct {
+	authenticateErr error
+	authorizeErr    error
+}
+
+func (a *fakeAuthorizer) Authenticate(_ context.Context, _ *config.CellTenantKey, _ nethttp.Header) (*Sender, error) {
+	if a.authenticateErr != nil {
+		return nil, a.authenticateErr
+	}
+	return &Sender{Issuer: "issuer", Subject: "subject"}, nil
+}
+
+func (a *fakeAuthorizer) Authorize(_ context.Context, _ *config.CellTenantKey, _ *Sender, _ *cev2.Event) error {
+	return a.authorizeErr
+}
+
 func TestHandler(t *testing.T) {
 	tests := []testCase{
 		{
@@ -206,6 +233,76 @@ func TestHandler(t *testing.T) {
 				metricskey.ContainerName:          container,
 			},
 		},
+		{
+			name:                "an event larger than the configured limit",
+			method:              "POST",
+			path:                "/ns1/broker1",
+			event:               createTestEventWithPayloadSize("test-event", 2000),
+			maxRequestBodyBytes: 1000,
+			wantCode:            nethttp.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "an event with a large payload offloaded to GCS",
+			path:           "/ns1/broker1",
+			event:          createTestEventWithPayloadSize("test-event", 1000),
+			offloader:      newTestOffloader(t, nil),
+			wantCode:       nethttp.StatusAccepted,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "202",
+				metricskey.LabelResponseCodeClass: "2xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+			eventAssertions: []eventAssertion{assertExtensionsExist(claimcheck.Extension), assertNoData},
+		},
+		{
+			name:           "a claim check reference set by the sender is removed",
+			path:           "/ns1/broker1",
+			event:          createTestEventWithExtension("test-event", claimcheck.Extension, "gs://bucket/ns2/broker2/object"),
+			wantCode:       nethttp.StatusAccepted,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "202",
+				metricskey.LabelResponseCodeClass: "2xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+			eventAssertions: []eventAssertion{assertExtensionsNotExist(claimcheck.Extension)},
+		},
+		{
+			name:           "a claim check reference set by the sender is removed before offloading",
+			path:           "/ns1/broker1",
+			event:          createTestEventWithExtension("test-event", claimcheck.Extension, "gs://bucket/ns2/broker2/object"),
+			offloader:      newTestOffloader(t, nil),
+			wantCode:       nethttp.StatusAccepted,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "202",
+				metricskey.LabelResponseCodeClass: "2xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+			eventAssertions: []eventAssertion{assertExtensionsNotExist(claimcheck.Extension)},
+		},
+		{
+			name:           "failure to offload the payload of an event to GCS",
+			path:           "/ns1/broker1",
+			event:          createTestEventWithPayloadSize("test-event", 1000),
+			offloader:      newTestOffloader(t, errors.New("write failed")),
+			wantCode:       nethttp.StatusInternalServerError,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "500",
+				metricskey.LabelResponseCodeClass: "5xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+		},
 		{
 			name:     "malformed path",
 			path:     "/ns1/broker1/and/something/else",
@@ -311,6 +408,95 @@ func TestHandler(t *testing.T) {
 			},
 			decouple: &fakeOverloadedDecoupleSink{},
 		},
+		{
+			name:           "authorized sender",
+			path:           "/ns1/broker1",
+			event:          createTestEvent("test-event"),
+			authorizer:     &fakeAuthorizer{},
+			wantCode:       nethttp.StatusAccepted,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "202",
+				metricskey.LabelResponseCodeClass: "2xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+		},
+		{
+			name:  "missing token",
+			path:  "/ns1/broker1",
+			event: createTestEvent("test-event"),
+			authorizer: &fakeAuthorizer{
+				authenticateErr: &policyError{reason: reasonMissingToken, err: ErrUnauthenticated},
+			},
+			wantCode:       nethttp.StatusUnauthorized,
+			wantHeader:     nethttp.Header{"Www-Authenticate": {"Bearer"}},
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         "custom",
+				metricskey.LabelResponseCode:      "401",
+				metricskey.LabelResponseCodeClass: "4xx",
+				"reason":                          reasonMissingToken,
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+		},
+		{
+			name:  "invalid token",
+			path:  "/ns1/broker1",
+			event: createTestEvent("test-event"),
+			authorizer: &fakeAuthorizer{
+				authenticateErr: &policyError{reason: reasonInvalidToken, err: ErrUnauthenticated},
+			},
+			wantCode:       nethttp.StatusUnauthorized,
+			wantHeader:     nethttp.Header{"Www-Authenticate": {`Bearer error="invalid_token"`}},
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         "custom",
+				metricskey.LabelResponseCode:      "401",
+				metricskey.LabelResponseCodeClass: "4xx",
+				"reason":                          reasonInvalidToken,
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+		},
+		{
+			name:  "key set unavailable",
+			path:  "/ns1/broker1",
+			event: createTestEvent("test-event"),
+			authorizer: &fakeAuthorizer{
+				authenticateErr: &policyError{reason: reasonKeySetUnavailable, err: ErrKeySetUnavailable},
+			},
+			wantCode:       nethttp.StatusServiceUnavailable,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         "custom",
+				metricskey.LabelResponseCode:      "503",
+				metricskey.LabelResponseCodeClass: "5xx",
+				"reason":                          reasonKeySetUnavailable,
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+		},
+		{
+			name:  "event not allowed",
+			path:  "/ns1/broker1",
+			event: createTestEvent("test-event"),
+			authorizer: &fakeAuthorizer{
+				authorizeErr: &policyError{reason: reasonEventNotAllowed, err: ErrForbidden},
+			},
+			wantCode:       nethttp.StatusForbidden,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "403",
+				metricskey.LabelResponseCodeClass: "4xx",
+				"reason":                          reasonEventNotAllowed,
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+		},
 	}
 
 	client := nethttp.Client{}
@@ -334,7 +520,12 @@ func TestHandler(t *testing.T) {
 				decouple = NewMultiTopicDecoupleSink(ctx, memory.NewTargets(brokerConfig), createPubsubClient(ctx, t, psSrv), pubsub.DefaultPublishSettings)
 			}
 
-			url := createAndStartIngress(ctx, t, psSrv, decouple)
+			authorizer := tc.authorizer
+			if authorizer == nil {
+				authorizer = NewPolicyAuthorizer(memory.NewTargets(brokerConfig))
+			}
+
+			url := createAndStartIngress(ctx, t, psSrv, decouple, authorizer, tc.maxRequestBodyBytes, tc.offloader)
 			rec := setupTestReceiver(ctx, t, psSrv)
 			req := createRequest(tc, url)
 			if tc.contentLength != nil {
@@ -347,6 +538,11 @@ func TestHandler(t *testing.T) {
 			if res.StatusCode != tc.wantCode {
 				t.Errorf("StatusCode mismatch. got: %v, want: %v", res.StatusCode, tc.wantCode)
 			}
+			for key := range tc.wantHeader {
+				if got, want := res.Header.Get(key), tc.wantHeader.Get(key); got != want {
+					t.Errorf("Header %s mismatch. got: %q, want: %q", key, got, want)
+				}
+			}
 			verifyMetrics(t, tc)
 
 			// If event is accepted, check that it's stored in the decouple sink.
@@ -414,7 +610,7 @@ func runIngressHandlerBenchmark(b *testing.B, eventSize int, targetCounts int) {
 	if err != nil {
 		b.Fatal(err)
 	}
-	h := NewHandler(ctx, nil, decouple, statsReporter, "")
+	h := NewHandler(ctx, nil, decouple, NewPolicyAuthorizer(memory.NewTargets(brokerConfig)), statsReporter, "", DefaultMaxRequestBodyBytes, nil)
 
 	if _, err := psClient.CreateTopic(ctx, topicID); err != nil {
 		b.Fatal(err)
@@ -516,13 +712,13 @@ func setupTestReceiver(ctx context.Context, t testing.TB, psSrv *pstest.Server)
 }
 
 // createAndStartIngress creates an ingress and calls its Start() method in a goroutine.
-func createAndStartIngress(ctx context.Context, t testing.TB, psSrv *pstest.Server, decouple DecoupleSink) string {
+func createAndStartIngress(ctx context.Context, t testing.TB, psSrv *pstest.Server, decouple DecoupleSink, authorizer SenderAuthorizer, maxRequestBodyBytes MaxRequestBodyBytes, offloader *claimcheck.Offloader) string {
 	receiver := &testHttpMessageReceiver{urlCh: make(chan string)}
 	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
 	if err != nil {
 		t.Fatal(err)
 	}
-	h := NewHandler(ctx, receiver, decouple, statsReporter, "")
+	h := NewHandler(ctx, receiver, decouple, authorizer, statsReporter, "", maxRequestBodyBytes, offloader)
 
 	errCh := make(chan error, 1)
 	go func() {
@@ -553,6 +749,12 @@ func createTestEventWithPayloadSize(id string, payloadSizeBytes int) *cloudevent
 	return testEvent
 }
 
+func createTestEventWithExtension(id, name, value string) *cloudevents.Event {
+	testEvent := createTestEvent(id)
+	testEvent.SetExtension(name, value)
+	return testEvent
+}
+
 // createRequest creates an http request from the test case. If event is specified, it converts the event to a request.
 func createRequest(tc testCase, url string) *nethttp.Request {
 	method := "POST"
@@ -601,6 +803,26 @@ func assertTraceID(id string) eventAssertion {
 	}
 }
 
+func assertNoData(t *testing.T, e *cloudevents.Event) {
+	if len(e.Data()) != 0 {
+		t.Errorf("event has %d bytes of data, want none", len(e.Data()))
+	}
+}
+
+// newTestOffloader returns an offloader of the data larger than 100 bytes to a fake bucket.
+func newTestOffloader(t *testing.T, writeErr error) *claimcheck.Offloader {
+	client, err := gstoragetesting.TestClientCreator(gstoragetesting.TestClientData{
+		BucketData: gstoragetesting.TestBucketData{
+			Objects:  make(map[string][]byte),
+			WriteErr: writeErr,
+		},
+	})(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &claimcheck.Offloader{Client: client, Bucket: "bucket", ThresholdBytes: 100}
+}
+
 func assertExtensionsExist(extensions ...string) eventAssertion {
 	return func(t *testing.T, e *cloudevents.Event) {
 		for _, extension := range extensions {
@@ -611,6 +833,16 @@ func assertExtensionsExist(extensions ...string) eventAssertion {
 	}
 }
 
+func assertExtensionsNotExist(extensions ...string) eventAssertion {
+	return func(t *testing.T, e *cloudevents.Event) {
+		for _, extension := range extensions {
+			if v, ok := e.Extensions()[extension]; ok {
+				t.Errorf("Extension %v exists with value %v.", extension, v)
+			}
+		}
+	}
+}
+
 // testHttpMessageReceiver implements HttpMessageReceiver. When created, it creates an httptest.Server,
 // which starts a server with any available port.
 type testHttpMessageReceiver struct {
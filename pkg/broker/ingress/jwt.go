@@ -0,0 +1,288 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	nethttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+	"k8s.io/client-go/rest"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// keySetTTL is how long a fetched key set is used before it is fetched again.
+	keySetTTL = 10 * time.Minute
+	// keySetMinRefreshInterval is the minimum time between two fetches of a key set. It bounds the
+	// fetches caused by tokens signed with unknown keys.
+	keySetMinRefreshInterval = 30 * time.Second
+	// keySetFetchTimeout is the timeout of a key set fetch.
+	keySetFetchTimeout = 10 * time.Second
+	// maxKeySetBytes is the maximum size of a key set response.
+	maxKeySetBytes = 1 << 20
+
+	// kubernetesJWKSPath is the path of the service account issuer discovery JWKS endpoint of the
+	// Kubernetes API server.
+	kubernetesJWKSPath = "/openid/v1/jwks"
+
+	// clockSkew is the leeway given to the time based claims of tokens.
+	clockSkew = time.Minute
+)
+
+// signingAlgorithms are the algorithms of the accepted tokens. Only asymmetric algorithms are
+// accepted, as the keys are public.
+var signingAlgorithms = map[jose.SignatureAlgorithm]bool{
+	jose.RS256: true,
+	jose.RS384: true,
+	jose.RS512: true,
+	jose.PS256: true,
+	jose.PS384: true,
+	jose.PS512: true,
+	jose.ES256: true,
+	jose.ES384: true,
+	jose.ES512: true,
+}
+
+// parseToken parses a JWS compact serialized JWT signed with one of the signingAlgorithms. The
+// returned claims are not verified.
+func parseToken(raw string) (*jwt.JSONWebToken, *jwt.Claims, error) {
+	t, err := jwt.ParseSigned(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(t.Headers) != 1 {
+		return nil, nil, errors.New("token must have exactly one signature")
+	}
+	if alg := jose.SignatureAlgorithm(t.Headers[0].Algorithm); !signingAlgorithms[alg] {
+		return nil, nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	claims := &jwt.Claims{}
+	if err := t.UnsafeClaimsWithoutVerification(claims); err != nil {
+		return nil, nil, err
+	}
+	return t, claims, nil
+}
+
+// validateClaims verifies that the claims are valid at the given time and for one of the
+// audiences.
+func validateClaims(claims *jwt.Claims, now time.Time, audiences []string) error {
+	if claims.Expiry == nil {
+		return errors.New("token has no expiry")
+	}
+	if err := claims.ValidateWithLeeway(jwt.Expected{Time: now}, clockSkew); err != nil {
+		return err
+	}
+	for _, aud := range audiences {
+		if claims.Audience.Contains(aud) {
+			return nil
+		}
+	}
+	return errors.New("token audience is not accepted")
+}
+
+// keySet is a cached JSON Web Key Set.
+type keySet struct {
+	mu sync.Mutex
+	// keys are the signing keys of the set by key ID.
+	keys map[string][]jose.JSONWebKey
+	// fetched is the time the keys were fetched.
+	fetched time.Time
+	// attempted is the time the last fetch attempt ended, successful or not.
+	attempted time.Time
+	// err is the error of the last fetch attempt.
+	err error
+	// fetching is closed once the ongoing fetch ends. It is nil if there is no ongoing fetch.
+	fetching chan struct{}
+}
+
+// keySets fetches and caches the JSON Web Key Sets of the issuers of ingress policies.
+type keySets struct {
+	client *nethttp.Client
+	// kubernetesSource returns the JWKS URL of the cluster's service account issuer and an HTTP
+	// client authenticated as the pod's service account.
+	kubernetesSource func() (string, *nethttp.Client, error)
+	now              func() time.Time
+
+	mu   sync.Mutex
+	sets map[string]*keySet
+}
+
+func newKeySets() *keySets {
+	var (
+		once       sync.Once
+		kubeURL    string
+		kubeClient *nethttp.Client
+		kubeErr    error
+	)
+	return &keySets{
+		client: &nethttp.Client{Timeout: keySetFetchTimeout},
+		kubernetesSource: func() (string, *nethttp.Client, error) {
+			once.Do(func() {
+				kubeURL, kubeClient, kubeErr = inClusterKeySetSource()
+			})
+			return kubeURL, kubeClient, kubeErr
+		},
+		now:  time.Now,
+		sets: make(map[string]*keySet),
+	}
+}
+
+// inClusterKeySetSource returns the service account issuer discovery JWKS URL of the API server
+// and an HTTP client that authenticates with the pod's service account token and trusts the
+// cluster's CA.
+func inClusterKeySetSource() (string, *nethttp.Client, error) {
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	transport, err := rest.TransportFor(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.TrimSuffix(cfg.Host, "/") + kubernetesJWKSPath, &nethttp.Client{Transport: transport, Timeout: keySetFetchTimeout}, nil
+}
+
+// keys returns the keys of the issuer's key set with the key ID, or all its keys if the key ID is
+// empty. The key set is fetched if it isn't cached, if it is stale, or if it doesn't have the key
+// ID and wasn't fetched recently. Concurrent callers share a single fetch, which isn't cancelled
+// when they are, so that a sender hanging up can't fail the fetch for the others.
+func (k *keySets) keys(ctx context.Context, issuer *config.IngressIssuer, keyID string) ([]jose.JSONWebKey, error) {
+	url, client := issuer.JwksUrl, k.client
+	if issuer.Kubernetes {
+		// The client authenticates as the pod's service account, so it is only used for the API
+		// server's own endpoint, never for a URL taken from the policy.
+		kubeURL, kubeClient, err := k.kubernetesSource()
+		if err != nil {
+			return nil, fmt.Errorf("unable to use the in-cluster service account issuer: %w", err)
+		}
+		url, client = kubeURL, kubeClient
+	}
+
+	k.mu.Lock()
+	set, ok := k.sets[url]
+	if !ok {
+		set = &keySet{}
+		k.sets[url] = set
+	}
+	k.mu.Unlock()
+
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	now := k.now()
+	_, known := set.keys[keyID]
+	stale := set.keys == nil || now.Sub(set.fetched) > keySetTTL || (keyID != "" && !known)
+	if stale && set.fetching == nil && now.Sub(set.attempted) >= keySetMinRefreshInterval {
+		set.fetching = make(chan struct{})
+		go k.fetch(logging.FromContext(ctx), set, client, url)
+	}
+	if fetching := set.fetching; fetching != nil {
+		// Don't hold the lock during the fetch, so that requests using the cached keys aren't
+		// stalled by requests waiting for it.
+		set.mu.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			set.mu.Lock()
+			return nil, ctx.Err()
+		}
+		set.mu.Lock()
+	}
+	if set.keys == nil {
+		return nil, fmt.Errorf("key set %q is unavailable: %v", url, set.err)
+	}
+	if keyID == "" {
+		var all []jose.JSONWebKey
+		for _, keys := range set.keys {
+			all = append(all, keys...)
+		}
+		return all, nil
+	}
+	return set.keys[keyID], nil
+}
+
+// fetch fetches the key set with its own timeout, and ends the ongoing fetch of the set.
+func (k *keySets) fetch(logger *zap.Logger, set *keySet, client *nethttp.Client, url string) {
+	ctx, cancel := context.WithTimeout(context.Background(), keySetFetchTimeout)
+	defer cancel()
+	keys, err := fetchKeySet(logging.WithLogger(ctx, logger), client, url)
+
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	now := k.now()
+	set.attempted, set.err = now, err
+	if err != nil {
+		logger.Warn("Failed to fetch key set", zap.String("url", url), zap.Error(err))
+	} else {
+		set.keys, set.fetched = keys, now
+	}
+	close(set.fetching)
+	set.fetching = nil
+}
+
+// fetchKeySet fetches the public signing keys of a JSON Web Key Set. Keys that can't be parsed are
+// skipped, as a set may have keys of types that aren't supported.
+func fetchKeySet(ctx context.Context, client *nethttp.Client, url string) (map[string][]jose.JSONWebKey, error) {
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxKeySetBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string][]jose.JSONWebKey, len(set.Keys))
+	for _, raw := range set.Keys {
+		var key jose.JSONWebKey
+		if err := key.UnmarshalJSON(raw); err != nil {
+			logging.FromContext(ctx).Debug("Skipping key", zap.String("url", url), zap.Error(err))
+			continue
+		}
+		if !key.IsPublic() || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		keys[key.KeyID] = append(keys[key.KeyID], key)
+	}
+	return keys, nil
+}
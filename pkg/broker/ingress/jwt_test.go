@@ -0,0 +1,192 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestKeySetsCaching(t *testing.T) {
+	issuer := newTestIssuer(t)
+	ks := newKeySets()
+	now := time.Now()
+	ks.now = func() time.Time { return now }
+	ingressIssuer := &config.IngressIssuer{Issuer: testIssuerName, JwksUrl: issuer.server.URL}
+
+	steps := []struct {
+		name        string
+		advance     time.Duration
+		keyID       string
+		wantKeys    int
+		wantFetches int32
+	}{{
+		name:        "first use fetches the key set",
+		keyID:       "rsa",
+		wantKeys:    1,
+		wantFetches: 1,
+	}, {
+		name:        "known key is cached",
+		advance:     time.Minute,
+		keyID:       "ec",
+		wantKeys:    1,
+		wantFetches: 1,
+	}, {
+		name:        "tokens without key ID use all keys",
+		keyID:       "",
+		wantKeys:    2,
+		wantFetches: 1,
+	}, {
+		name:        "unknown key refetches the key set",
+		keyID:       "rotated",
+		wantKeys:    0,
+		wantFetches: 2,
+	}, {
+		name:        "unknown key doesn't refetch the key set too often",
+		advance:     keySetMinRefreshInterval / 2,
+		keyID:       "rotated",
+		wantKeys:    0,
+		wantFetches: 2,
+	}, {
+		name:        "stale key set is refetched",
+		advance:     keySetTTL,
+		keyID:       "rsa",
+		wantKeys:    1,
+		wantFetches: 3,
+	}}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		keys, err := ks.keys(context.Background(), ingressIssuer, step.keyID)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if len(keys) != step.wantKeys {
+			t.Errorf("%s: got %d keys, want %d", step.name, len(keys), step.wantKeys)
+		}
+		if got := atomic.LoadInt32(&issuer.fetches); got != step.wantFetches {
+			t.Errorf("%s: got %d fetches, want %d", step.name, got, step.wantFetches)
+		}
+	}
+
+	// Stale keys are used while the key set can't be fetched.
+	issuer.server.Close()
+	now = now.Add(keySetTTL + time.Second)
+	if keys, err := ks.keys(context.Background(), ingressIssuer, "rsa"); err != nil || len(keys) != 1 {
+		t.Errorf("Stale keys: got %d keys and error %v, want 1 key", len(keys), err)
+	}
+}
+
+func TestKeySetsFetchOutlivesCaller(t *testing.T) {
+	issuer := newTestIssuer(t)
+	release := make(chan struct{})
+	slow := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, _ *nethttp.Request) {
+		<-release
+		resp, err := nethttp.Get(issuer.server.URL)
+		if err != nil {
+			w.WriteHeader(nethttp.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		io.Copy(w, resp.Body)
+	}))
+	defer slow.Close()
+	ks := newKeySets()
+	ingressIssuer := &config.IngressIssuer{Issuer: testIssuerName, JwksUrl: slow.URL}
+
+	// A sender hanging up doesn't cancel the fetch of the key set of a new issuer.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ks.keys(cancelled, ingressIssuer, "rsa"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Got error %v, want %v", err, context.Canceled)
+	}
+
+	// Concurrent senders share the ongoing fetch.
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if keys, err := ks.keys(ctx, ingressIssuer, "rsa"); err != nil || len(keys) != 1 {
+				t.Errorf("Got %d keys and error %v, want 1 key", len(keys), err)
+			}
+		}()
+	}
+	close(release)
+	wg.Wait()
+	if got := atomic.LoadInt32(&issuer.fetches); got != 1 {
+		t.Errorf("Got %d fetches, want 1", got)
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"a.b",
+		"a.b.c.d",
+		"!!.e30.",
+		"e30.!!.",
+		"e30.e30.!!",
+		"e30.eyJhdWQiOjF9.",
+		// Unsigned and symmetrically signed tokens are rejected.
+		"eyJhbGciOiJub25lIn0.e30.c2ln",
+		"eyJhbGciOiJIUzI1NiJ9.e30.c2ln",
+	} {
+		if _, _, err := parseToken(raw); err == nil {
+			t.Errorf("parseToken(%q) succeeded, want error", raw)
+		}
+	}
+}
+
+func TestKeySetsKubernetesIssuerIgnoresJWKSURL(t *testing.T) {
+	kube := newTestIssuer(t)
+	attacker := newTestIssuer(t)
+	ks := newKeySets()
+	ks.kubernetesSource = func() (string, *nethttp.Client, error) {
+		return kube.server.URL + kubernetesJWKSPath, kube.server.Client(), nil
+	}
+
+	// The in-cluster client carries the pod's credentials, so it must never be sent to a URL of
+	// the policy.
+	keys, err := ks.keys(context.Background(), &config.IngressIssuer{
+		Issuer:     kubernetesIssuer,
+		JwksUrl:    attacker.server.URL,
+		Kubernetes: true,
+	}, "rsa")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("Got %d keys, want 1", len(keys))
+	}
+	if got := atomic.LoadInt32(&attacker.fetches); got != 0 {
+		t.Errorf("Got %d fetches of the policy's JWKS URL, want 0", got)
+	}
+	if got := atomic.LoadInt32(&kube.fetches); got != 1 {
+		t.Errorf("Got %d fetches of the API server's JWKS, want 1", got)
+	}
+}
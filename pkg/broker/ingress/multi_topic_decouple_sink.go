@@ -32,6 +32,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
 	"github.com/google/knative-gcp/pkg/logging"
 )
@@ -68,13 +69,15 @@ type multiTopicDecoupleSink struct {
 	// brokerConfig holds configurations for all brokers. It's a view of a configmap populated by
 	// the broker controller.
 	brokerConfig config.ReadonlyTargets
+	// expressions holds the parsed CESQL expressions of the targets in brokerConfig.
+	expressions filter.Expressions
 	// TODO(#1804): remove this field when enabling the feature by default.
 	enableEventFiltering bool
 }
 
 // Send sends incoming event to its corresponding pubsub topic based on which broker it belongs to.
 func (m *multiTopicDecoupleSink) Send(ctx context.Context, broker *config.CellTenantKey, event cev2.Event) protocol.Result {
-	topic, err := m.getTopicForBroker(ctx, broker)
+	topic, orderingKeyAttribute, err := m.getTopicForBroker(ctx, broker)
 	if err != nil {
 		trace.FromContext(ctx).Annotate(
 			[]trace.Attribute{
@@ -99,13 +102,24 @@ func (m *multiTopicDecoupleSink) Send(ctx context.Context, broker *config.CellTe
 		return err
 	}
 
+	// Events of an ordered broker that carry the ordering key attribute are published with the
+	// attribute's value as the ordering key. Events without it are published unordered.
+	if orderingKeyAttribute != "" {
+		msg.OrderingKey, _ = eventutil.AttributeValue(&event, orderingKeyAttribute)
+	}
+
 	_, err = topic.Publish(ctx, msg).Get(ctx)
+	if err != nil && msg.OrderingKey != "" {
+		// A failed publish pauses publishing for the ordering key. Resume it so that later events
+		// with the same key are accepted; the sender is told about this failure and may retry.
+		topic.ResumePublish(msg.OrderingKey)
+	}
 	return err
 }
 
 // eventFilterFunc is used to see if a target is interested in an event.
 // It is used as a vaiable to allow stubbing out in unit tests.
-var eventFilterFunc = filter.PassFilter
+var eventFilterFunc = (*filter.Expressions).PassTargetFilters
 
 // enableEventFilterFunc is a temporary function to control enabling and
 // disabling trigger-less event filtering in ingress.
@@ -122,7 +136,7 @@ func isEventFilteringEnabled() bool {
 func (m *multiTopicDecoupleSink) hasTrigger(ctx context.Context, event *cev2.Event) bool {
 	hasTrigger := false
 	m.brokerConfig.RangeAllTargets(func(target *config.Target) bool {
-		if eventFilterFunc(ctx, target.FilterAttributes, event) {
+		if eventFilterFunc(&m.expressions, ctx, m.brokerConfig, target, event) {
 			hasTrigger = true
 			return false
 		}
@@ -134,16 +148,17 @@ func (m *multiTopicDecoupleSink) hasTrigger(ctx context.Context, event *cev2.Eve
 }
 
 // getTopicForBroker finds the corresponding decouple topic for the broker from the mounted broker configmap volume.
-func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker *config.CellTenantKey) (*pubsub.Topic, error) {
-	topicID, err := m.getTopicIDForBroker(ctx, broker)
+// It also returns the broker's ordering key attribute, which is empty if the broker is not ordered.
+func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker *config.CellTenantKey) (*pubsub.Topic, string, error) {
+	topicID, orderingKeyAttribute, err := m.getTopicIDForBroker(ctx, broker)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if topic, ok := m.getExistingTopic(broker); ok {
-		// Check that the broker's topic ID hasn't changed.
-		if topic.ID() == topicID {
-			return topic, nil
+		// Check that the broker's topic ID and ordering haven't changed.
+		if topic.ID() == topicID && topic.EnableMessageOrdering == (orderingKeyAttribute != "") {
+			return topic, orderingKeyAttribute, nil
 		}
 	}
 
@@ -151,47 +166,49 @@ func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker *
 	return m.updateTopicForBroker(ctx, broker)
 }
 
-func (m *multiTopicDecoupleSink) updateTopicForBroker(ctx context.Context, broker *config.CellTenantKey) (*pubsub.Topic, error) {
+func (m *multiTopicDecoupleSink) updateTopicForBroker(ctx context.Context, broker *config.CellTenantKey) (*pubsub.Topic, string, error) {
 	m.topicsMut.Lock()
 	defer m.topicsMut.Unlock()
 	// Fetch latest decouple topic ID under lock.
-	topicID, err := m.getTopicIDForBroker(ctx, broker)
+	topicID, orderingKeyAttribute, err := m.getTopicIDForBroker(ctx, broker)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if topic, ok := m.topics[*broker]; ok {
-		if topic.ID() == topicID {
+		if topic.ID() == topicID && topic.EnableMessageOrdering == (orderingKeyAttribute != "") {
 			// Topic already updated.
-			return topic, nil
+			return topic, orderingKeyAttribute, nil
 		}
 		// Stop old topic.
 		m.topics[*broker].Stop()
 	}
 	topic := m.pubsub.Topic(topicID)
 	topic.PublishSettings = m.publishSettings
+	topic.EnableMessageOrdering = orderingKeyAttribute != ""
 	m.topics[*broker] = topic
-	return topic, nil
+	return topic, orderingKeyAttribute, nil
 }
 
-func (m *multiTopicDecoupleSink) getTopicIDForBroker(ctx context.Context, broker *config.CellTenantKey) (string, error) {
+// getTopicIDForBroker returns the decouple topic ID and the ordering key attribute of the broker.
+func (m *multiTopicDecoupleSink) getTopicIDForBroker(ctx context.Context, broker *config.CellTenantKey) (string, string, error) {
 	brokerConfig, ok := m.brokerConfig.GetCellTenantByKey(broker)
 	if !ok {
 		// There is an propagation delay between the controller reconciles the broker config and
 		// the config being pushed to the configmap volume in the ingress pod. So sometimes we return
 		// an error even if the request is valid.
 		logging.FromContext(ctx).Warn("config is not found for")
-		return "", fmt.Errorf("%q: %w", broker, ErrNotFound)
+		return "", "", fmt.Errorf("%q: %w", broker, ErrNotFound)
 	}
 	if brokerConfig.DecoupleQueue == nil || brokerConfig.DecoupleQueue.Topic == "" {
 		logging.FromContext(ctx).Error("DecoupleQueue or topic missing for broker, this should NOT happen.", zap.Any("brokerConfig", brokerConfig))
-		return "", fmt.Errorf("decouple queue of %q: %w", broker, ErrIncomplete)
+		return "", "", fmt.Errorf("decouple queue of %q: %w", broker, ErrIncomplete)
 	}
 	if brokerConfig.DecoupleQueue.State != config.State_READY {
 		logging.FromContext(ctx).Debug("decouple queue is not ready")
-		return "", fmt.Errorf("%q: %w", broker, ErrNotReady)
+		return "", "", fmt.Errorf("%q: %w", broker, ErrNotReady)
 	}
-	return brokerConfig.DecoupleQueue.Topic, nil
+	return brokerConfig.DecoupleQueue.Topic, brokerConfig.OrderingKeyAttribute, nil
 }
 
 func (m *multiTopicDecoupleSink) getExistingTopic(broker *config.CellTenantKey) (*pubsub.Topic, bool) {
@@ -31,6 +31,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
 	logtest "knative.dev/pkg/logging/testing"
 )
 
@@ -598,7 +599,7 @@ func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
 	filterCalled := false
 	origEventFilterFunc := eventFilterFunc
 	defer func() { eventFilterFunc = origEventFilterFunc }()
-	eventFilterFunc = func(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	eventFilterFunc = func(_ *filter.Expressions, ctx context.Context, _ config.ReadonlyTargets, target *config.Target, event *event.Event) bool {
 		filterCalled = true
 		return true
 	}
@@ -654,7 +655,7 @@ func TestMultiTopicDecoupleSinkSendDoesNotChecksFilterWhenFeatureDisabled(t *tes
 	filterCalled := false
 	origEventFilterFunc := eventFilterFunc
 	defer func() { eventFilterFunc = origEventFilterFunc }()
-	eventFilterFunc = func(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	eventFilterFunc = func(_ *filter.Expressions, ctx context.Context, _ config.ReadonlyTargets, target *config.Target, event *event.Event) bool {
 		filterCalled = true
 		return true
 	}
@@ -751,3 +752,68 @@ func TestMultiTopicDecoupleSinkUsesPublishSettings(t *testing.T) {
 		t.Fatalf("Unexpected error, expected %q, actually %q", want, got)
 	}
 }
+
+func TestMultiTopicDecoupleSinkSetsOrderingKey(t *testing.T) {
+	tests := []struct {
+		name                 string
+		orderingKeyAttribute string
+		extensions           map[string]interface{}
+		wantOrderingKey      string
+	}{{
+		name:       "broker not ordered",
+		extensions: map[string]interface{}{"partitionkey": "key"},
+	}, {
+		name:                 "ordered broker with key extension",
+		orderingKeyAttribute: "partitionkey",
+		extensions:           map[string]interface{}{"partitionkey": "key"},
+		wantOrderingKey:      "key",
+	}, {
+		name:                 "ordered broker with key context attribute",
+		orderingKeyAttribute: "source",
+		wantOrderingKey:      "test-source",
+	}, {
+		name:                 "ordered broker without key",
+		orderingKeyAttribute: "partitionkey",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logtest.TestContextWithLogger(t)
+			psSrv := pstest.NewServer()
+			defer psSrv.Close()
+			psClient := createPubsubClient(ctx, t, psSrv)
+			if _, err := psClient.CreateTopic(ctx, "test_topic_1"); err != nil {
+				t.Fatal(err)
+			}
+
+			brokerConfig := memory.NewTargets(&config.TargetsConfig{
+				CellTenants: map[string]*config.CellTenant{
+					"test_ns_1/test_broker_1": {
+						Type:                 config.CellTenantType_BROKER,
+						DecoupleQueue:        &config.Queue{Topic: "test_topic_1", State: config.State_READY},
+						OrderingKeyAttribute: tt.orderingKeyAttribute,
+						Targets: map[string]*config.Target{"target_1": {
+							CellTenantType: config.CellTenantType_BROKER,
+						}},
+					},
+				},
+			})
+			sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, pubsub.DefaultPublishSettings)
+
+			ce := createTestEvent(uuid.New().String())
+			for k, v := range tt.extensions {
+				ce.SetExtension(k, v)
+			}
+			if err := sink.Send(ctx, config.TestOnlyBrokerKey("test_ns_1", "test_broker_1"), *ce); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			msgs := psSrv.Messages()
+			if len(msgs) != 1 {
+				t.Fatalf("Got %d published messages, want 1", len(msgs))
+			}
+			if got := msgs[0].OrderingKey; got != tt.wantOrderingKey {
+				t.Errorf("Ordering key got %q, want %q", got, tt.wantOrderingKey)
+			}
+		})
+	}
+}
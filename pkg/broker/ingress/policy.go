@@ -0,0 +1,173 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// bearerPrefix is the prefix of the Authorization header of requests carrying a bearer token.
+const bearerPrefix = "Bearer "
+
+// Reasons for rejecting requests, reported as a metric tag.
+const (
+	reasonMissingToken      = "missing_token"
+	reasonInvalidToken      = "invalid_token"
+	reasonUntrustedIssuer   = "untrusted_issuer"
+	reasonKeySetUnavailable = "key_set_unavailable"
+	reasonSenderNotAllowed  = "sender_not_allowed"
+	reasonEventNotAllowed   = "event_not_allowed"
+	reasonPolicyChanged     = "policy_changed"
+)
+
+// SenderAuthorizer authenticates and authorizes the senders of events.
+type SenderAuthorizer interface {
+	// Authenticate verifies the credentials of a request sent to the broker, before its event is
+	// read. It returns a nil Sender if the broker doesn't restrict its senders.
+	Authenticate(ctx context.Context, broker *config.CellTenantKey, header nethttp.Header) (*Sender, error)
+	// Authorize checks that the sender returned by Authenticate is allowed to send the event to
+	// the broker.
+	Authorize(ctx context.Context, broker *config.CellTenantKey, sender *Sender, event *cev2.Event) error
+}
+
+// Sender is the identity of an authenticated sender.
+type Sender struct {
+	// Issuer is the iss claim of the sender's token.
+	Issuer string
+	// Subject is the sub claim of the sender's token.
+	Subject string
+}
+
+// NewPolicyAuthorizer creates a new policyAuthorizer.
+func NewPolicyAuthorizer(brokerConfig config.ReadonlyTargets) *policyAuthorizer {
+	return &policyAuthorizer{
+		brokerConfig: brokerConfig,
+		keySets:      newKeySets(),
+	}
+}
+
+// policyAuthorizer implements SenderAuthorizer with the ingress policies of the brokers. Senders
+// authenticate with a JWT bearer token, verified with the JSON Web Key Set of its issuer.
+type policyAuthorizer struct {
+	// brokerConfig holds configurations for all brokers. It's a view of a configmap populated by
+	// the broker controller.
+	brokerConfig config.ReadonlyTargets
+	keySets      *keySets
+}
+
+func (a *policyAuthorizer) policy(broker *config.CellTenantKey) *config.IngressPolicy {
+	if brokerConfig, ok := a.brokerConfig.GetCellTenantByKey(broker); ok {
+		return brokerConfig.IngressPolicy
+	}
+	// The decouple sink reports unknown brokers.
+	return nil
+}
+
+// Authenticate implements SenderAuthorizer.
+func (a *policyAuthorizer) Authenticate(ctx context.Context, broker *config.CellTenantKey, header nethttp.Header) (*Sender, error) {
+	policy := a.policy(broker)
+	if policy == nil {
+		return nil, nil
+	}
+	authorization := header.Get("Authorization")
+	if !strings.HasPrefix(authorization, bearerPrefix) {
+		return nil, &policyError{reason: reasonMissingToken, err: fmt.Errorf("%w: missing bearer token", ErrUnauthenticated)}
+	}
+	t, claims, err := parseToken(strings.TrimSpace(strings.TrimPrefix(authorization, bearerPrefix)))
+	if err != nil {
+		return nil, &policyError{reason: reasonInvalidToken, err: fmt.Errorf("%w: %v", ErrUnauthenticated, err)}
+	}
+	var issuer *config.IngressIssuer
+	for _, i := range policy.Issuers {
+		if i.Issuer == claims.Issuer {
+			issuer = i
+			break
+		}
+	}
+	if issuer == nil {
+		return nil, &policyError{reason: reasonUntrustedIssuer, err: fmt.Errorf("%w: untrusted issuer %q", ErrUnauthenticated, claims.Issuer)}
+	}
+	if err := validateClaims(claims, a.keySets.now(), issuer.Audiences); err != nil {
+		return nil, &policyError{reason: reasonInvalidToken, err: fmt.Errorf("%w: %v", ErrUnauthenticated, err)}
+	}
+	keys, err := a.keySets.keys(ctx, issuer, t.Headers[0].KeyID)
+	if err != nil {
+		return nil, &policyError{reason: reasonKeySetUnavailable, err: fmt.Errorf("%w: %v", ErrKeySetUnavailable, err)}
+	}
+	for _, key := range keys {
+		verified := &jwt.Claims{}
+		if t.Claims(key.Key, verified) == nil {
+			return &Sender{Issuer: verified.Issuer, Subject: verified.Subject}, nil
+		}
+	}
+	return nil, &policyError{reason: reasonInvalidToken, err: fmt.Errorf("%w: invalid token signature", ErrUnauthenticated)}
+}
+
+// Authorize implements SenderAuthorizer.
+func (a *policyAuthorizer) Authorize(_ context.Context, broker *config.CellTenantKey, sender *Sender, event *cev2.Event) error {
+	policy := a.policy(broker)
+	if policy == nil {
+		return nil
+	}
+	if sender == nil {
+		// The policy was added after the request was authenticated.
+		return &policyError{reason: reasonPolicyChanged, err: fmt.Errorf("%w: ingress policy changed", ErrUnauthenticated)}
+	}
+	if len(policy.Rules) == 0 {
+		return nil
+	}
+	senderAllowed := false
+	for _, rule := range policy.Rules {
+		if (rule.Issuer != "" && rule.Issuer != sender.Issuer) || !matchesAny(rule.Subjects, sender.Subject) {
+			continue
+		}
+		senderAllowed = true
+		if matchesAny(rule.Types, event.Type()) && matchesAny(rule.Sources, event.Source()) {
+			return nil
+		}
+	}
+	if !senderAllowed {
+		return &policyError{reason: reasonSenderNotAllowed, err: fmt.Errorf("%w: %q is not allowed to send events", ErrForbidden, sender.Subject)}
+	}
+	return &policyError{reason: reasonEventNotAllowed, err: fmt.Errorf("%w: %q is not allowed to send events of type %q from source %q", ErrForbidden, sender.Subject, event.Type(), event.Source())}
+}
+
+// matchesAny reports whether the patterns are empty or one of them matches the value. Patterns
+// ending with "*" match the values starting with the rest of the pattern.
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+			if strings.HasPrefix(value, prefix) {
+				return true
+			}
+		} else if pattern == value {
+			return true
+		}
+	}
+	return false
+}
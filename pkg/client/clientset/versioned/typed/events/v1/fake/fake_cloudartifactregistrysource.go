@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCloudArtifactRegistrySources implements CloudArtifactRegistrySourceInterface
+type FakeCloudArtifactRegistrySources struct {
+	Fake *FakeEventsV1
+	ns   string
+}
+
+var cloudartifactregistrysourcesResource = schema.GroupVersionResource{Group: "events.cloud.google.com", Version: "v1", Resource: "cloudartifactregistrysources"}
+
+var cloudartifactregistrysourcesKind = schema.GroupVersionKind{Group: "events.cloud.google.com", Version: "v1", Kind: "CloudArtifactRegistrySource"}
+
+// Get takes name of the cloudArtifactRegistrySource, and returns the corresponding cloudArtifactRegistrySource object, and an error if there is any.
+func (c *FakeCloudArtifactRegistrySources) Get(ctx context.Context, name string, options v1.GetOptions) (result *eventsv1.CloudArtifactRegistrySource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cloudartifactregistrysourcesResource, c.ns, name), &eventsv1.CloudArtifactRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudArtifactRegistrySource), err
+}
+
+// List takes label and field selectors, and returns the list of CloudArtifactRegistrySources that match those selectors.
+func (c *FakeCloudArtifactRegistrySources) List(ctx context.Context, opts v1.ListOptions) (result *eventsv1.CloudArtifactRegistrySourceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cloudartifactregistrysourcesResource, cloudartifactregistrysourcesKind, c.ns, opts), &eventsv1.CloudArtifactRegistrySourceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &eventsv1.CloudArtifactRegistrySourceList{ListMeta: obj.(*eventsv1.CloudArtifactRegistrySourceList).ListMeta}
+	for _, item := range obj.(*eventsv1.CloudArtifactRegistrySourceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cloudArtifactRegistrySources.
+func (c *FakeCloudArtifactRegistrySources) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cloudartifactregistrysourcesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cloudArtifactRegistrySource and creates it.  Returns the server's representation of the cloudArtifactRegistrySource, and an error, if there is any.
+func (c *FakeCloudArtifactRegistrySources) Create(ctx context.Context, cloudArtifactRegistrySource *eventsv1.CloudArtifactRegistrySource, opts v1.CreateOptions) (result *eventsv1.CloudArtifactRegistrySource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cloudartifactregistrysourcesResource, c.ns, cloudArtifactRegistrySource), &eventsv1.CloudArtifactRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudArtifactRegistrySource), err
+}
+
+// Update takes the representation of a cloudArtifactRegistrySource and updates it. Returns the server's representation of the cloudArtifactRegistrySource, and an error, if there is any.
+func (c *FakeCloudArtifactRegistrySources) Update(ctx context.Context, cloudArtifactRegistrySource *eventsv1.CloudArtifactRegistrySource, opts v1.UpdateOptions) (result *eventsv1.CloudArtifactRegistrySource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cloudartifactregistrysourcesResource, c.ns, cloudArtifactRegistrySource), &eventsv1.CloudArtifactRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudArtifactRegistrySource), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCloudArtifactRegistrySources) UpdateStatus(ctx context.Context, cloudArtifactRegistrySource *eventsv1.CloudArtifactRegistrySource, opts v1.UpdateOptions) (*eventsv1.CloudArtifactRegistrySource, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(cloudartifactregistrysourcesResource, "status", c.ns, cloudArtifactRegistrySource), &eventsv1.CloudArtifactRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudArtifactRegistrySource), err
+}
+
+// Delete takes name of the cloudArtifactRegistrySource and deletes it. Returns an error if one occurs.
+func (c *FakeCloudArtifactRegistrySources) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cloudartifactregistrysourcesResource, c.ns, name), &eventsv1.CloudArtifactRegistrySource{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCloudArtifactRegistrySources) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cloudartifactregistrysourcesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &eventsv1.CloudArtifactRegistrySourceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cloudArtifactRegistrySource.
+func (c *FakeCloudArtifactRegistrySources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *eventsv1.CloudArtifactRegistrySource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cloudartifactregistrysourcesResource, c.ns, name, pt, data, subresources...), &eventsv1.CloudArtifactRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudArtifactRegistrySource), err
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCloudBillingBudgetSources implements CloudBillingBudgetSourceInterface
+type FakeCloudBillingBudgetSources struct {
+	Fake *FakeEventsV1
+	ns   string
+}
+
+var cloudbillingbudgetsourcesResource = schema.GroupVersionResource{Group: "events.cloud.google.com", Version: "v1", Resource: "cloudbillingbudgetsources"}
+
+var cloudbillingbudgetsourcesKind = schema.GroupVersionKind{Group: "events.cloud.google.com", Version: "v1", Kind: "CloudBillingBudgetSource"}
+
+// Get takes name of the cloudBillingBudgetSource, and returns the corresponding cloudBillingBudgetSource object, and an error if there is any.
+func (c *FakeCloudBillingBudgetSources) Get(ctx context.Context, name string, options v1.GetOptions) (result *eventsv1.CloudBillingBudgetSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cloudbillingbudgetsourcesResource, c.ns, name), &eventsv1.CloudBillingBudgetSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudBillingBudgetSource), err
+}
+
+// List takes label and field selectors, and returns the list of CloudBillingBudgetSources that match those selectors.
+func (c *FakeCloudBillingBudgetSources) List(ctx context.Context, opts v1.ListOptions) (result *eventsv1.CloudBillingBudgetSourceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cloudbillingbudgetsourcesResource, cloudbillingbudgetsourcesKind, c.ns, opts), &eventsv1.CloudBillingBudgetSourceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &eventsv1.CloudBillingBudgetSourceList{ListMeta: obj.(*eventsv1.CloudBillingBudgetSourceList).ListMeta}
+	for _, item := range obj.(*eventsv1.CloudBillingBudgetSourceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cloudBillingBudgetSources.
+func (c *FakeCloudBillingBudgetSources) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cloudbillingbudgetsourcesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cloudBillingBudgetSource and creates it.  Returns the server's representation of the cloudBillingBudgetSource, and an error, if there is any.
+func (c *FakeCloudBillingBudgetSources) Create(ctx context.Context, cloudBillingBudgetSource *eventsv1.CloudBillingBudgetSource, opts v1.CreateOptions) (result *eventsv1.CloudBillingBudgetSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cloudbillingbudgetsourcesResource, c.ns, cloudBillingBudgetSource), &eventsv1.CloudBillingBudgetSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudBillingBudgetSource), err
+}
+
+// Update takes the representation of a cloudBillingBudgetSource and updates it. Returns the server's representation of the cloudBillingBudgetSource, and an error, if there is any.
+func (c *FakeCloudBillingBudgetSources) Update(ctx context.Context, cloudBillingBudgetSource *eventsv1.CloudBillingBudgetSource, opts v1.UpdateOptions) (result *eventsv1.CloudBillingBudgetSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cloudbillingbudgetsourcesResource, c.ns, cloudBillingBudgetSource), &eventsv1.CloudBillingBudgetSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudBillingBudgetSource), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCloudBillingBudgetSources) UpdateStatus(ctx context.Context, cloudBillingBudgetSource *eventsv1.CloudBillingBudgetSource, opts v1.UpdateOptions) (*eventsv1.CloudBillingBudgetSource, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(cloudbillingbudgetsourcesResource, "status", c.ns, cloudBillingBudgetSource), &eventsv1.CloudBillingBudgetSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudBillingBudgetSource), err
+}
+
+// Delete takes name of the cloudBillingBudgetSource and deletes it. Returns an error if one occurs.
+func (c *FakeCloudBillingBudgetSources) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cloudbillingbudgetsourcesResource, c.ns, name), &eventsv1.CloudBillingBudgetSource{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCloudBillingBudgetSources) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cloudbillingbudgetsourcesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &eventsv1.CloudBillingBudgetSourceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cloudBillingBudgetSource.
+func (c *FakeCloudBillingBudgetSources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *eventsv1.CloudBillingBudgetSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cloudbillingbudgetsourcesResource, c.ns, name, pt, data, subresources...), &eventsv1.CloudBillingBudgetSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudBillingBudgetSource), err
+}
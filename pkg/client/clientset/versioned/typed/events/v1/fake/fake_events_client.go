@@ -28,14 +28,26 @@ type FakeEventsV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeEventsV1) CloudArtifactRegistrySources(namespace string) v1.CloudArtifactRegistrySourceInterface {
+	return &FakeCloudArtifactRegistrySources{c, namespace}
+}
+
 func (c *FakeEventsV1) CloudAuditLogsSources(namespace string) v1.CloudAuditLogsSourceInterface {
 	return &FakeCloudAuditLogsSources{c, namespace}
 }
 
+func (c *FakeEventsV1) CloudBillingBudgetSources(namespace string) v1.CloudBillingBudgetSourceInterface {
+	return &FakeCloudBillingBudgetSources{c, namespace}
+}
+
 func (c *FakeEventsV1) CloudBuildSources(namespace string) v1.CloudBuildSourceInterface {
 	return &FakeCloudBuildSources{c, namespace}
 }
 
+func (c *FakeEventsV1) CloudMonitoringAlertSources(namespace string) v1.CloudMonitoringAlertSourceInterface {
+	return &FakeCloudMonitoringAlertSources{c, namespace}
+}
+
 func (c *FakeEventsV1) CloudPubSubSources(namespace string) v1.CloudPubSubSourceInterface {
 	return &FakeCloudPubSubSources{c, namespace}
 }
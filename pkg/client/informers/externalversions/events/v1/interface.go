@@ -24,10 +24,16 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// CloudArtifactRegistrySources returns a CloudArtifactRegistrySourceInformer.
+	CloudArtifactRegistrySources() CloudArtifactRegistrySourceInformer
 	// CloudAuditLogsSources returns a CloudAuditLogsSourceInformer.
 	CloudAuditLogsSources() CloudAuditLogsSourceInformer
+	// CloudBillingBudgetSources returns a CloudBillingBudgetSourceInformer.
+	CloudBillingBudgetSources() CloudBillingBudgetSourceInformer
 	// CloudBuildSources returns a CloudBuildSourceInformer.
 	CloudBuildSources() CloudBuildSourceInformer
+	// CloudMonitoringAlertSources returns a CloudMonitoringAlertSourceInformer.
+	CloudMonitoringAlertSources() CloudMonitoringAlertSourceInformer
 	// CloudPubSubSources returns a CloudPubSubSourceInformer.
 	CloudPubSubSources() CloudPubSubSourceInformer
 	// CloudSchedulerSources returns a CloudSchedulerSourceInformer.
@@ -47,16 +53,31 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// CloudArtifactRegistrySources returns a CloudArtifactRegistrySourceInformer.
+func (v *version) CloudArtifactRegistrySources() CloudArtifactRegistrySourceInformer {
+	return &cloudArtifactRegistrySourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudAuditLogsSources returns a CloudAuditLogsSourceInformer.
 func (v *version) CloudAuditLogsSources() CloudAuditLogsSourceInformer {
 	return &cloudAuditLogsSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CloudBillingBudgetSources returns a CloudBillingBudgetSourceInformer.
+func (v *version) CloudBillingBudgetSources() CloudBillingBudgetSourceInformer {
+	return &cloudBillingBudgetSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudBuildSources returns a CloudBuildSourceInformer.
 func (v *version) CloudBuildSources() CloudBuildSourceInformer {
 	return &cloudBuildSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CloudMonitoringAlertSources returns a CloudMonitoringAlertSourceInformer.
+func (v *version) CloudMonitoringAlertSources() CloudMonitoringAlertSourceInformer {
+	return &cloudMonitoringAlertSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudPubSubSources returns a CloudPubSubSourceInformer.
 func (v *version) CloudPubSubSources() CloudPubSubSourceInformer {
 	return &cloudPubSubSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CloudArtifactRegistrySourceLister helps list CloudArtifactRegistrySources.
+// All objects returned here must be treated as read-only.
+type CloudArtifactRegistrySourceLister interface {
+	// List lists all CloudArtifactRegistrySources in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CloudArtifactRegistrySource, err error)
+	// CloudArtifactRegistrySources returns an object that can list and get CloudArtifactRegistrySources.
+	CloudArtifactRegistrySources(namespace string) CloudArtifactRegistrySourceNamespaceLister
+	CloudArtifactRegistrySourceListerExpansion
+}
+
+// cloudArtifactRegistrySourceLister implements the CloudArtifactRegistrySourceLister interface.
+type cloudArtifactRegistrySourceLister struct {
+	indexer cache.Indexer
+}
+
+// NewCloudArtifactRegistrySourceLister returns a new CloudArtifactRegistrySourceLister.
+func NewCloudArtifactRegistrySourceLister(indexer cache.Indexer) CloudArtifactRegistrySourceLister {
+	return &cloudArtifactRegistrySourceLister{indexer: indexer}
+}
+
+// List lists all CloudArtifactRegistrySources in the indexer.
+func (s *cloudArtifactRegistrySourceLister) List(selector labels.Selector) (ret []*v1.CloudArtifactRegistrySource, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudArtifactRegistrySource))
+	})
+	return ret, err
+}
+
+// CloudArtifactRegistrySources returns an object that can list and get CloudArtifactRegistrySources.
+func (s *cloudArtifactRegistrySourceLister) CloudArtifactRegistrySources(namespace string) CloudArtifactRegistrySourceNamespaceLister {
+	return cloudArtifactRegistrySourceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CloudArtifactRegistrySourceNamespaceLister helps list and get CloudArtifactRegistrySources.
+// All objects returned here must be treated as read-only.
+type CloudArtifactRegistrySourceNamespaceLister interface {
+	// List lists all CloudArtifactRegistrySources in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CloudArtifactRegistrySource, err error)
+	// Get retrieves the CloudArtifactRegistrySource from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.CloudArtifactRegistrySource, error)
+	CloudArtifactRegistrySourceNamespaceListerExpansion
+}
+
+// cloudArtifactRegistrySourceNamespaceLister implements the CloudArtifactRegistrySourceNamespaceLister
+// interface.
+type cloudArtifactRegistrySourceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CloudArtifactRegistrySources in the indexer for a given namespace.
+func (s cloudArtifactRegistrySourceNamespaceLister) List(selector labels.Selector) (ret []*v1.CloudArtifactRegistrySource, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudArtifactRegistrySource))
+	})
+	return ret, err
+}
+
+// Get retrieves the CloudArtifactRegistrySource from the indexer for a given namespace and name.
+func (s cloudArtifactRegistrySourceNamespaceLister) Get(name string) (*v1.CloudArtifactRegistrySource, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cloudartifactregistrysource"), name)
+	}
+	return obj.(*v1.CloudArtifactRegistrySource), nil
+}
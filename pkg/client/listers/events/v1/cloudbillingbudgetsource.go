@@ -0,0 +1,99 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CloudBillingBudgetSourceLister helps list CloudBillingBudgetSources.
+// All objects returned here must be treated as read-only.
+type CloudBillingBudgetSourceLister interface {
+	// List lists all CloudBillingBudgetSources in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CloudBillingBudgetSource, err error)
+	// CloudBillingBudgetSources returns an object that can list and get CloudBillingBudgetSources.
+	CloudBillingBudgetSources(namespace string) CloudBillingBudgetSourceNamespaceLister
+	CloudBillingBudgetSourceListerExpansion
+}
+
+// cloudBillingBudgetSourceLister implements the CloudBillingBudgetSourceLister interface.
+type cloudBillingBudgetSourceLister struct {
+	indexer cache.Indexer
+}
+
+// NewCloudBillingBudgetSourceLister returns a new CloudBillingBudgetSourceLister.
+func NewCloudBillingBudgetSourceLister(indexer cache.Indexer) CloudBillingBudgetSourceLister {
+	return &cloudBillingBudgetSourceLister{indexer: indexer}
+}
+
+// List lists all CloudBillingBudgetSources in the indexer.
+func (s *cloudBillingBudgetSourceLister) List(selector labels.Selector) (ret []*v1.CloudBillingBudgetSource, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudBillingBudgetSource))
+	})
+	return ret, err
+}
+
+// CloudBillingBudgetSources returns an object that can list and get CloudBillingBudgetSources.
+func (s *cloudBillingBudgetSourceLister) CloudBillingBudgetSources(namespace string) CloudBillingBudgetSourceNamespaceLister {
+	return cloudBillingBudgetSourceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CloudBillingBudgetSourceNamespaceLister helps list and get CloudBillingBudgetSources.
+// All objects returned here must be treated as read-only.
+type CloudBillingBudgetSourceNamespaceLister interface {
+	// List lists all CloudBillingBudgetSources in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CloudBillingBudgetSource, err error)
+	// Get retrieves the CloudBillingBudgetSource from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.CloudBillingBudgetSource, error)
+	CloudBillingBudgetSourceNamespaceListerExpansion
+}
+
+// cloudBillingBudgetSourceNamespaceLister implements the CloudBillingBudgetSourceNamespaceLister
+// interface.
+type cloudBillingBudgetSourceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CloudBillingBudgetSources in the indexer for a given namespace.
+func (s cloudBillingBudgetSourceNamespaceLister) List(selector labels.Selector) (ret []*v1.CloudBillingBudgetSource, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudBillingBudgetSource))
+	})
+	return ret, err
+}
+
+// Get retrieves the CloudBillingBudgetSource from the indexer for a given namespace and name.
+func (s cloudBillingBudgetSourceNamespaceLister) Get(name string) (*v1.CloudBillingBudgetSource, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cloudbillingbudgetsource"), name)
+	}
+	return obj.(*v1.CloudBillingBudgetSource), nil
+}
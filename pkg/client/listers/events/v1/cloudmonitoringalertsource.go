@@ -0,0 +1,99 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CloudMonitoringAlertSourceLister helps list CloudMonitoringAlertSources.
+// All objects returned here must be treated as read-only.
+type CloudMonitoringAlertSourceLister interface {
+	// List lists all CloudMonitoringAlertSources in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CloudMonitoringAlertSource, err error)
+	// CloudMonitoringAlertSources returns an object that can list and get CloudMonitoringAlertSources.
+	CloudMonitoringAlertSources(namespace string) CloudMonitoringAlertSourceNamespaceLister
+	CloudMonitoringAlertSourceListerExpansion
+}
+
+// cloudMonitoringAlertSourceLister implements the CloudMonitoringAlertSourceLister interface.
+type cloudMonitoringAlertSourceLister struct {
+	indexer cache.Indexer
+}
+
+// NewCloudMonitoringAlertSourceLister returns a new CloudMonitoringAlertSourceLister.
+func NewCloudMonitoringAlertSourceLister(indexer cache.Indexer) CloudMonitoringAlertSourceLister {
+	return &cloudMonitoringAlertSourceLister{indexer: indexer}
+}
+
+// List lists all CloudMonitoringAlertSources in the indexer.
+func (s *cloudMonitoringAlertSourceLister) List(selector labels.Selector) (ret []*v1.CloudMonitoringAlertSource, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudMonitoringAlertSource))
+	})
+	return ret, err
+}
+
+// CloudMonitoringAlertSources returns an object that can list and get CloudMonitoringAlertSources.
+func (s *cloudMonitoringAlertSourceLister) CloudMonitoringAlertSources(namespace string) CloudMonitoringAlertSourceNamespaceLister {
+	return cloudMonitoringAlertSourceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CloudMonitoringAlertSourceNamespaceLister helps list and get CloudMonitoringAlertSources.
+// All objects returned here must be treated as read-only.
+type CloudMonitoringAlertSourceNamespaceLister interface {
+	// List lists all CloudMonitoringAlertSources in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CloudMonitoringAlertSource, err error)
+	// Get retrieves the CloudMonitoringAlertSource from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.CloudMonitoringAlertSource, error)
+	CloudMonitoringAlertSourceNamespaceListerExpansion
+}
+
+// cloudMonitoringAlertSourceNamespaceLister implements the CloudMonitoringAlertSourceNamespaceLister
+// interface.
+type cloudMonitoringAlertSourceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CloudMonitoringAlertSources in the indexer for a given namespace.
+func (s cloudMonitoringAlertSourceNamespaceLister) List(selector labels.Selector) (ret []*v1.CloudMonitoringAlertSource, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudMonitoringAlertSource))
+	})
+	return ret, err
+}
+
+// Get retrieves the CloudMonitoringAlertSource from the indexer for a given namespace and name.
+func (s cloudMonitoringAlertSourceNamespaceLister) Get(name string) (*v1.CloudMonitoringAlertSource, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cloudmonitoringalertsource"), name)
+	}
+	return obj.(*v1.CloudMonitoringAlertSource), nil
+}
@@ -29,3 +29,32 @@ type PubSubable interface {
 	// PubSubStatus returns the PubSubStatus portion of the Status.
 	PubSubStatus() *duckv1.PubSubStatus
 }
+
+// SubscriptionPolicyable is implemented by the PubSubables that configure the
+// Filter, DeadLetterPolicy and RetryPolicy of the subscription of their
+// PullSubscription.
+type SubscriptionPolicyable interface {
+	// SubscriptionPolicySpec returns the SubscriptionPolicySpec portion of the Spec.
+	SubscriptionPolicySpec() *duckv1.SubscriptionPolicySpec
+}
+
+// EventMappable is implemented by the PubSubables that declare how the CloudEvents are derived
+// from the messages of their PullSubscription.
+type EventMappable interface {
+	// EventMapping returns the EventMapping of the Spec, if any.
+	EventMapping() *duckv1.EventMapping
+}
+
+// ImageFilterable is implemented by the PubSubables that filter the container image
+// notifications received by their PullSubscription.
+type ImageFilterable interface {
+	// ImageFilter returns the ImageFilter of the Spec, if any.
+	ImageFilter() *duckv1.ImageFilter
+}
+
+// BudgetFilterable is implemented by the PubSubables that filter the budget notifications
+// received by their PullSubscription.
+type BudgetFilterable interface {
+	// BudgetFilter returns the BudgetFilter of the Spec, if any.
+	BudgetFilter() *duckv1.BudgetFilter
+}
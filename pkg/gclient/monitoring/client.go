@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+)
+
+// CreateFn is a factory function to create a Cloud Monitoring client.
+type CreateFn func(ctx context.Context, opts ...option.ClientOption) (Client, error)
+
+// NewClient creates a new wrapped Cloud Monitoring notification channel client.
+func NewClient(ctx context.Context, opts ...option.ClientOption) (Client, error) {
+	client, err := monitoring.NewNotificationChannelClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &monitoringClient{
+		client: client,
+	}, nil
+}
+
+// monitoringClient wraps monitoring.NotificationChannelClient. Is the client that will be used everywhere except unit tests.
+type monitoringClient struct {
+	client *monitoring.NotificationChannelClient
+}
+
+// Verify that it satisfies the monitoring.NotificationChannelClient interface.
+var _ Client = &monitoringClient{}
+
+// Close implements monitoring.NotificationChannelClient.Close
+func (c *monitoringClient) Close() error {
+	return c.client.Close()
+}
+
+// CreateNotificationChannel implements monitoring.NotificationChannelClient.CreateNotificationChannel
+func (c *monitoringClient) CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.client.CreateNotificationChannel(ctx, req, opts...)
+}
+
+// GetNotificationChannel implements monitoring.NotificationChannelClient.GetNotificationChannel
+func (c *monitoringClient) GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.client.GetNotificationChannel(ctx, req, opts...)
+}
+
+// UpdateNotificationChannel implements monitoring.NotificationChannelClient.UpdateNotificationChannel
+func (c *monitoringClient) UpdateNotificationChannel(ctx context.Context, req *monitoringpb.UpdateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.client.UpdateNotificationChannel(ctx, req, opts...)
+}
+
+// DeleteNotificationChannel implements monitoring.NotificationChannelClient.DeleteNotificationChannel
+func (c *monitoringClient) DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error {
+	return c.client.DeleteNotificationChannel(ctx, req, opts...)
+}
+
+// NewMetricClient creates a new wrapped Cloud Monitoring metric client.
+func NewMetricClient(ctx context.Context, opts ...option.ClientOption) (MetricClient, error) {
+	client, err := monitoring.NewMetricClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &metricClient{
+		client: client,
+	}, nil
+}
+
+// metricClient wraps monitoring.MetricClient. Is the client that will be used everywhere except unit tests.
+type metricClient struct {
+	client *monitoring.MetricClient
+}
+
+// Verify that it satisfies the MetricClient interface.
+var _ MetricClient = &metricClient{}
+
+// Close implements monitoring.MetricClient.Close
+func (c *metricClient) Close() error {
+	return c.client.Close()
+}
+
+// ListTimeSeries implements monitoring.MetricClient.ListTimeSeries
+func (c *metricClient) ListTimeSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest, opts ...gax.CallOption) ([]*monitoringpb.TimeSeries, error) {
+	var series []*monitoringpb.TimeSeries
+	it := c.client.ListTimeSeries(ctx, req, opts...)
+	for {
+		s, err := it.Next()
+		if err == iterator.Done {
+			return series, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+
+	"google.golang.org/api/option"
+
+	"github.com/google/knative-gcp/pkg/gclient/monitoring"
+	"github.com/googleapis/gax-go/v2"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestClientCreator returns a monitoring.CreateFn used to construct the test Cloud Monitoring client.
+func TestClientCreator(value interface{}) monitoring.CreateFn {
+	var data TestClientData
+	var ok bool
+	if data, ok = value.(TestClientData); !ok {
+		data = TestClientData{}
+	}
+	if data.CreateClientErr != nil {
+		return func(_ context.Context, _ ...option.ClientOption) (monitoring.Client, error) {
+			return nil, data.CreateClientErr
+		}
+	}
+
+	return func(_ context.Context, _ ...option.ClientOption) (monitoring.Client, error) {
+		return &testClient{
+			data: data,
+		}, nil
+	}
+}
+
+// TestClientData is the data used to configure the test Cloud Monitoring client.
+type TestClientData struct {
+	CreateClientErr              error
+	CreateNotificationChannelErr error
+	GetNotificationChannelErr    error
+	UpdateNotificationChannelErr error
+	DeleteNotificationChannelErr error
+	CloseErr                     error
+	// NotificationChannelID is the ID given to the created notification channels.
+	NotificationChannelID string
+	// NotificationChannel is the notification channel returned by GetNotificationChannel.
+	// GetNotificationChannel returns a channel with only a name if NotificationChannel is nil.
+	NotificationChannel *monitoringpb.NotificationChannel
+}
+
+// testClient is the test Cloud Monitoring client.
+type testClient struct {
+	data TestClientData
+}
+
+// Verify that it satisfies the monitoring.Client interface.
+var _ monitoring.Client = &testClient{}
+
+// Close implements client.Close
+func (c *testClient) Close() error {
+	return c.data.CloseErr
+}
+
+// CreateNotificationChannel implements client.CreateNotificationChannel
+func (c *testClient) CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	if c.data.CreateNotificationChannelErr != nil {
+		return nil, c.data.CreateNotificationChannelErr
+	}
+	channel := proto.Clone(req.NotificationChannel).(*monitoringpb.NotificationChannel)
+	channel.Name = req.Name + "/notificationChannels/" + c.data.NotificationChannelID
+	return channel, nil
+}
+
+// GetNotificationChannel implements client.GetNotificationChannel
+func (c *testClient) GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	if c.data.GetNotificationChannelErr != nil {
+		return nil, c.data.GetNotificationChannelErr
+	}
+	if c.data.NotificationChannel != nil {
+		channel := proto.Clone(c.data.NotificationChannel).(*monitoringpb.NotificationChannel)
+		channel.Name = req.Name
+		return channel, nil
+	}
+	return &monitoringpb.NotificationChannel{
+		Name: req.Name,
+	}, nil
+}
+
+// UpdateNotificationChannel implements client.UpdateNotificationChannel
+func (c *testClient) UpdateNotificationChannel(ctx context.Context, req *monitoringpb.UpdateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	if c.data.UpdateNotificationChannelErr != nil {
+		return nil, c.data.UpdateNotificationChannelErr
+	}
+	return proto.Clone(req.NotificationChannel).(*monitoringpb.NotificationChannel), nil
+}
+
+// DeleteNotificationChannel implements client.DeleteNotificationChannel
+func (c *testClient) DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error {
+	return c.data.DeleteNotificationChannelErr
+}
+
+// TestMetricClient is the test Cloud Monitoring metric client.
+type TestMetricClient struct {
+	ListTimeSeriesErr error
+	CloseErr          error
+	// TimeSeries are the time series returned by ListTimeSeries, whatever the request.
+	TimeSeries []*monitoringpb.TimeSeries
+}
+
+// Verify that it satisfies the monitoring.MetricClient interface.
+var _ monitoring.MetricClient = &TestMetricClient{}
+
+// Close implements client.Close
+func (c *TestMetricClient) Close() error {
+	return c.CloseErr
+}
+
+// ListTimeSeries implements client.ListTimeSeries
+func (c *TestMetricClient) ListTimeSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest, opts ...gax.CallOption) ([]*monitoringpb.TimeSeries, error) {
+	if c.ListTimeSeriesErr != nil {
+		return nil, c.ListTimeSeriesErr
+	}
+	return c.TimeSeries, nil
+}
@@ -37,6 +37,13 @@ func NewClient(ctx context.Context, projectID string, opts ...option.ClientOptio
 	}, nil
 }
 
+// FromPubsubClient wraps an existing Pub/Sub client.
+func FromPubsubClient(client *pubsub.Client) Client {
+	return &pubsubClient{
+		client: client,
+	}
+}
+
 // pubsubClient wraps pubsub.Client. Is the client that will be used everywhere except unit tests.
 type pubsubClient struct {
 	client *pubsub.Client
@@ -97,3 +104,8 @@ func (c *pubsubClient) CreateTopicWithConfig(ctx context.Context, id string, cfg
 	}
 	return &pubsubTopic{topic: topic}, nil
 }
+
+// Snapshot implements pubsub.Client.Snapshot
+func (c *pubsubClient) Snapshot(id string) Snapshot {
+	return &pubsubSnapshot{snapshot: c.client.Snapshot(id)}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// pubsubSnapshot wraps pubsub.Snapshot. Is the snapshot that will be used everywhere except unit tests.
+type pubsubSnapshot struct {
+	snapshot *pubsub.Snapshot
+}
+
+// Verify that it satisfies the pubsub.Snapshot interface.
+var _ Snapshot = &pubsubSnapshot{}
+
+// ID implements pubsub.Snapshot.ID
+func (s *pubsubSnapshot) ID() string {
+	return s.snapshot.ID()
+}
+
+// Delete implements pubsub.Snapshot.Delete
+func (s *pubsubSnapshot) Delete(ctx context.Context) error {
+	return s.snapshot.Delete(ctx)
+}
@@ -91,3 +91,26 @@ func (s *pubsubSubscription) Delete(ctx context.Context) error {
 func (s *pubsubSubscription) ID() string {
 	return s.sub.ID()
 }
+
+// SeekToTime implements pubsub.Subscription.SeekToTime
+func (s *pubsubSubscription) SeekToTime(ctx context.Context, t time.Time) error {
+	return s.sub.SeekToTime(ctx, t)
+}
+
+// SeekToSnapshot implements pubsub.Subscription.SeekToSnapshot
+func (s *pubsubSubscription) SeekToSnapshot(ctx context.Context, snap Snapshot) error {
+	var snapshot *pubsub.Snapshot
+	if ps, ok := snap.(*pubsubSnapshot); ok {
+		snapshot = ps.snapshot
+	}
+	return s.sub.SeekToSnapshot(ctx, snapshot)
+}
+
+// CreateSnapshot implements pubsub.Subscription.CreateSnapshot
+func (s *pubsubSubscription) CreateSnapshot(ctx context.Context, id string) (Snapshot, error) {
+	cfg, err := s.sub.CreateSnapshot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pubsubSnapshot{snapshot: cfg.Snapshot}, nil
+}
@@ -18,6 +18,7 @@ package testing
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/gclient/pubsub"
 )
@@ -35,6 +36,10 @@ type TestSubscriptionData struct {
 	ConfigErr error
 	UpdateErr error
 	DeleteErr error
+	SeekErr   error
+	// CreateSnapshotErr is returned when creating a snapshot of the subscription.
+	CreateSnapshotErr error
+	SnapshotData      TestSnapshotData
 }
 
 // Verify that it satisfies the pubsub.Subscription interface.
@@ -63,3 +68,21 @@ func (s *testSubscription) Delete(ctx context.Context) error {
 func (s *testSubscription) ID() string {
 	return s.id
 }
+
+// SeekToTime implements Subscription.SeekToTime.
+func (s *testSubscription) SeekToTime(ctx context.Context, t time.Time) error {
+	return s.data.SeekErr
+}
+
+// SeekToSnapshot implements Subscription.SeekToSnapshot.
+func (s *testSubscription) SeekToSnapshot(ctx context.Context, snap pubsub.Snapshot) error {
+	return s.data.SeekErr
+}
+
+// CreateSnapshot implements Subscription.CreateSnapshot.
+func (s *testSubscription) CreateSnapshot(ctx context.Context, id string) (pubsub.Snapshot, error) {
+	if s.data.CreateSnapshotErr != nil {
+		return nil, s.data.CreateSnapshotErr
+	}
+	return &testSnapshot{data: s.data.SnapshotData, id: id}, nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/google/knative-gcp/pkg/gclient/scheduler"
 	"github.com/googleapis/gax-go/v2"
 	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
+	"google.golang.org/protobuf/proto"
 )
 
 // TestClientCreator returns a scheduler.CreateFn used to construct the test Scheduler client.
@@ -42,6 +43,7 @@ func TestClientCreator(value interface{}) scheduler.CreateFn {
 	return func(_ context.Context, _ ...option.ClientOption) (scheduler.Client, error) {
 		return &testClient{
 			data: data,
+			jobs: make(map[string]*schedulerpb.Job),
 		}, nil
 	}
 }
@@ -53,12 +55,18 @@ type TestClientData struct {
 	DeleteJobErr    error
 	UpdateJobErr    error
 	GetJobErr       error
+	PauseJobErr     error
+	ResumeJobErr    error
 	CloseErr        error
+	// Job is the job returned by GetJob. GetJob returns a job with only a name if Job is nil.
+	Job *schedulerpb.Job
 }
 
 // testClient is the test Scheduler client.
 type testClient struct {
 	data TestClientData
+	// jobs are the jobs created or updated with the client.
+	jobs map[string]*schedulerpb.Job
 }
 
 // Verify that it satisfies the scheduler.Client interface.
@@ -74,9 +82,10 @@ func (c *testClient) CreateJob(ctx context.Context, req *schedulerpb.CreateJobRe
 	if c.data.CreateJobErr != nil {
 		return nil, c.data.CreateJobErr
 	}
-	return &schedulerpb.Job{
-		Name: req.Job.Name,
-	}, nil
+	job := proto.Clone(req.Job).(*schedulerpb.Job)
+	job.State = schedulerpb.Job_ENABLED
+	c.jobs[job.Name] = job
+	return proto.Clone(job).(*schedulerpb.Job), nil
 }
 
 // CreateJob implements client.DeleteJob
@@ -99,7 +108,38 @@ func (c *testClient) GetJob(ctx context.Context, req *schedulerpb.GetJobRequest,
 	if c.data.GetJobErr != nil {
 		return nil, c.data.GetJobErr
 	}
+	if c.data.Job != nil {
+		job := proto.Clone(c.data.Job).(*schedulerpb.Job)
+		job.Name = req.Name
+		return job, nil
+	}
 	return &schedulerpb.Job{
 		Name: req.Name,
 	}, nil
 }
+
+// PauseJob implements client.PauseJob
+func (c *testClient) PauseJob(ctx context.Context, req *schedulerpb.PauseJobRequest, opts ...gax.CallOption) (*schedulerpb.Job, error) {
+	return c.setJobState(req.Name, schedulerpb.Job_PAUSED, c.data.PauseJobErr)
+}
+
+// ResumeJob implements client.ResumeJob
+func (c *testClient) ResumeJob(ctx context.Context, req *schedulerpb.ResumeJobRequest, opts ...gax.CallOption) (*schedulerpb.Job, error) {
+	return c.setJobState(req.Name, schedulerpb.Job_ENABLED, c.data.ResumeJobErr)
+}
+
+func (c *testClient) setJobState(name string, state schedulerpb.Job_State, err error) (*schedulerpb.Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	job := &schedulerpb.Job{}
+	if existing, ok := c.jobs[name]; ok {
+		job = existing
+	} else if c.data.Job != nil {
+		job = proto.Clone(c.data.Job).(*schedulerpb.Job)
+	}
+	job.Name = name
+	job.State = state
+	c.jobs[name] = job
+	return proto.Clone(job).(*schedulerpb.Job), nil
+}
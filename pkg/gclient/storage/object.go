@@ -0,0 +1,44 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+)
+
+// storageObject wraps storage.ObjectHandle. Is the object that will be used everywhere except unit tests.
+type storageObject struct {
+	handle *storage.ObjectHandle
+}
+
+// Verify that it satisfies the storage.Object interface.
+var _ Object = &storageObject{}
+
+func (o *storageObject) NewWriter(ctx context.Context) io.WriteCloser {
+	return o.handle.NewWriter(ctx)
+}
+
+func (o *storageObject) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	return o.handle.NewReader(ctx)
+}
+
+func (o *storageObject) Delete(ctx context.Context) error {
+	return o.handle.Delete(ctx)
+}
@@ -37,6 +37,12 @@ type TestBucketData struct {
 	DeleteErr          error
 	Attrs              *BucketAttrs
 	AttrsError         error
+	// Objects holds the content of the objects in the bucket by name. Written objects are
+	// only kept if it is not nil.
+	Objects         map[string][]byte
+	WriteErr        error
+	ReadErr         error
+	DeleteObjectErr error
 }
 
 // Verify that it satisfies the storage.Bucket interface.
@@ -65,3 +71,8 @@ func (b *testBucket) DeleteNotification(ctx context.Context, id string) error {
 func (b *testBucket) Attrs(ctx context.Context) (*BucketAttrs, error) {
 	return b.data.Attrs, b.data.AttrsError
 }
+
+// Object implements bucket.Object
+func (b *testBucket) Object(name string) storage.Object {
+	return &testObject{name: name, data: b.data}
+}
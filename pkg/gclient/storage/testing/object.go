@@ -0,0 +1,88 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+
+	. "cloud.google.com/go/storage"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
+)
+
+// testObject is a test Storage object.
+type testObject struct {
+	name string
+	data TestBucketData
+}
+
+// Verify that it satisfies the storage.Object interface.
+var _ storage.Object = &testObject{}
+
+// NewWriter implements object.NewWriter
+func (o *testObject) NewWriter(ctx context.Context) io.WriteCloser {
+	return &testWriter{object: o}
+}
+
+// NewReader implements object.NewReader
+func (o *testObject) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	if o.data.ReadErr != nil {
+		return nil, o.data.ReadErr
+	}
+	content, ok := o.data.Objects[o.name]
+	if !ok {
+		return nil, ErrObjectNotExist
+	}
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
+// Delete implements object.Delete
+func (o *testObject) Delete(ctx context.Context) error {
+	if o.data.DeleteObjectErr != nil {
+		return o.data.DeleteObjectErr
+	}
+	if _, ok := o.data.Objects[o.name]; !ok {
+		return ErrObjectNotExist
+	}
+	delete(o.data.Objects, o.name)
+	return nil
+}
+
+// testWriter stores the written content in the objects of the bucket when it is closed.
+type testWriter struct {
+	object *testObject
+	buf    bytes.Buffer
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	if w.object.data.WriteErr != nil {
+		return 0, w.object.data.WriteErr
+	}
+	return w.buf.Write(p)
+}
+
+func (w *testWriter) Close() error {
+	if w.object.data.WriteErr != nil {
+		return w.object.data.WriteErr
+	}
+	if w.object.data.Objects != nil {
+		w.object.data.Objects[w.object.name] = w.buf.Bytes()
+	}
+	return nil
+}
@@ -45,8 +45,18 @@ type DeliveryReporter struct {
 	containerName         ContainerName
 	dispatchTimeInMsecM   *stats.Float64Measure
 	processingTimeInMsecM *stats.Float64Measure
+	deliveryAttemptsM     *stats.Int64Measure
+	throttleTimeInMsecM   *stats.Float64Measure
+	circuitStateM         *stats.Int64Measure
 }
 
+// Circuit breaker states reported by ReportCircuitState.
+const (
+	CircuitClosed int64 = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
 func (r *DeliveryReporter) register() error {
 	return metrics.RegisterResourceView(
 		&view.View{
@@ -86,6 +96,41 @@ func (r *DeliveryReporter) register() error {
 				ContainerNameKey,
 			},
 		},
+		&view.View{
+			Name:        r.deliveryAttemptsM.Name(),
+			Description: r.deliveryAttemptsM.Description(),
+			Measure:     r.deliveryAttemptsM,
+			Aggregation: view.Distribution(1, 2, 3, 4, 5, 10, 20, 50, 100),
+			TagKeys: []tag.Key{
+				TriggerFilterTypeKey,
+				ResponseCodeKey,
+				ResponseCodeClassKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
+		&view.View{
+			Name:        r.throttleTimeInMsecM.Name(),
+			Description: r.throttleTimeInMsecM.Description(),
+			Measure:     r.throttleTimeInMsecM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000
+			TagKeys: []tag.Key{
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
+		&view.View{
+			Name:        r.circuitStateM.Name(),
+			Description: r.circuitStateM.Description(),
+			Measure:     r.circuitStateM,
+			Aggregation: view.LastValue(),
+			TagKeys: []tag.Key{
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
 	)
 }
 
@@ -108,6 +153,27 @@ func NewDeliveryReporter(podName PodName, containerName ContainerName) (*Deliver
 			"The time spent processing an event before it is dispatched to a Trigger subscriber",
 			stats.UnitMilliseconds,
 		),
+		// deliveryAttemptsM records the delivery attempt of an event dispatched to
+		// a Trigger subscriber, starting at 1 for the initial delivery.
+		deliveryAttemptsM: stats.Int64(
+			"event_delivery_attempts",
+			"The delivery attempt of an event dispatched to a Trigger subscriber",
+			stats.UnitDimensionless,
+		),
+		// throttleTimeInMsecM records the time a delivery to a Trigger
+		// subscriber waited for the Trigger's rate limit, in milliseconds.
+		throttleTimeInMsecM: stats.Float64(
+			"event_throttle_latencies",
+			"The time a delivery to a Trigger subscriber waited for the Trigger's rate limit",
+			stats.UnitMilliseconds,
+		),
+		// circuitStateM records the state of the circuit breaker of a Trigger
+		// subscriber: 0 if closed, 1 if open and 2 if half-open.
+		circuitStateM: stats.Int64(
+			"circuit_breaker_state",
+			"The state of the circuit breaker of a Trigger subscriber: 0 closed, 1 open, 2 half-open",
+			stats.UnitDimensionless,
+		),
 	}
 
 	if err := r.register(); err != nil {
@@ -123,6 +189,22 @@ func (r *DeliveryReporter) ReportEventDispatchTime(ctx context.Context, d time.D
 	metrics.Record(ctx, r.dispatchTimeInMsecM.M(float64(d/time.Millisecond)), stats.WithAttachments(attachments))
 }
 
+// ReportDeliveryAttempt captures the delivery attempt of a dispatched event.
+func (r *DeliveryReporter) ReportDeliveryAttempt(ctx context.Context, attempt int32) {
+	metrics.Record(ctx, r.deliveryAttemptsM.M(int64(attempt)))
+}
+
+// ReportThrottleTime captures the time a delivery waited for its rate limit.
+func (r *DeliveryReporter) ReportThrottleTime(ctx context.Context, d time.Duration) {
+	// convert time.Duration in nanoseconds to milliseconds.
+	metrics.Record(ctx, r.throttleTimeInMsecM.M(float64(d/time.Millisecond)))
+}
+
+// ReportCircuitState captures a change in the state of a subscriber's circuit breaker.
+func (r *DeliveryReporter) ReportCircuitState(ctx context.Context, state int64) {
+	metrics.Record(ctx, r.circuitStateM.M(state))
+}
+
 // StartEventProcessing records the start of event processing for delivery within the given context.
 func StartEventProcessing(ctx context.Context) context.Context {
 	return context.WithValue(ctx, startDeliveryProcessingTime, time.Now())
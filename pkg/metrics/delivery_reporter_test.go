@@ -74,6 +74,133 @@ func TestReportEventDispatchTime(t *testing.T) {
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
 }
 
+func TestReportDeliveryAttempt(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType:        "testeventtype",
+		metricskey.LabelResponseCode:      "500",
+		metricskey.LabelResponseCodeClass: "5xx",
+		metricskey.PodName:                "testpod",
+		metricskey.ContainerName:          "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace:      "testns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "testbroker",
+		Name:           "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cctx, _ := AddRespStatusCodeTags(ctx, 500)
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportDeliveryAttempt(cctx, 1)
+		return nil
+	})
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportDeliveryAttempt(cctx, 3)
+		return nil
+	})
+	metricstest.CheckDistributionData(t, "event_delivery_attempts", wantTags, 2, 1.0, 3.0)
+}
+
+func TestReportThrottleTime(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType: "testeventtype",
+		metricskey.PodName:         "testpod",
+		metricskey.ContainerName:   "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace:      "testns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "testbroker",
+		Name:           "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportThrottleTime(ctx, 0)
+		return nil
+	})
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportThrottleTime(ctx, 500*time.Millisecond)
+		return nil
+	})
+	metricstest.CheckDistributionData(t, "event_throttle_latencies", wantTags, 2, 0.0, 500.0)
+}
+
+func TestReportCircuitState(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType: "testeventtype",
+		metricskey.PodName:         "testpod",
+		metricskey.ContainerName:   "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace:      "testns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "testbroker",
+		Name:           "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportCircuitState(ctx, CircuitOpen)
+		return nil
+	})
+	metricstest.CheckLastValueData(t, "circuit_breaker_state", wantTags, float64(CircuitOpen))
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportCircuitState(ctx, CircuitHalfOpen)
+		return nil
+	})
+	metricstest.CheckLastValueData(t, "circuit_breaker_state", wantTags, float64(CircuitHalfOpen))
+}
+
 func TestReportEventProcessingTime(t *testing.T) {
 	reportertest.ResetDeliveryMetrics()
 
@@ -35,6 +35,9 @@ import (
 type IngressReportArgs struct {
 	EventType    string
 	ResponseCode int
+	// Reason is the reason a request was rejected by the Broker's ingress policy. It is only
+	// reported when set.
+	Reason string
 }
 
 func (r *IngressReporter) register() error {
@@ -42,6 +45,7 @@ func (r *IngressReporter) register() error {
 		EventTypeKey,
 		ResponseCodeKey,
 		ResponseCodeClassKey,
+		ReasonKey,
 		PodNameKey,
 		ContainerNameKey,
 	}
@@ -85,16 +89,20 @@ type IngressReporter struct {
 func (r *IngressReporter) ReportEventCount(ctx context.Context, args IngressReportArgs) error {
 	// Count does not support exemplar currently, but keeping this anyway for future support.
 	attachments := getSpanContextAttachments(ctx)
+	tags := []tag.Mutator{
+		tag.Insert(PodNameKey, string(r.podName)),
+		tag.Insert(ContainerNameKey, string(r.containerName)),
+		tag.Insert(EventTypeKey, EventTypeMetricValue(args.EventType)),
+		tag.Insert(ResponseCodeKey, strconv.Itoa(args.ResponseCode)),
+		tag.Insert(ResponseCodeClassKey, metrics.ResponseCodeClass(args.ResponseCode)),
+	}
+	if args.Reason != "" {
+		tags = append(tags, tag.Insert(ReasonKey, args.Reason))
+	}
 	metrics.Record(
 		ctx, r.eventCountM.M(1),
 		stats.WithAttachments(attachments),
-		stats.WithTags(
-			tag.Insert(PodNameKey, string(r.podName)),
-			tag.Insert(ContainerNameKey, string(r.containerName)),
-			tag.Insert(EventTypeKey, EventTypeMetricValue(args.EventType)),
-			tag.Insert(ResponseCodeKey, strconv.Itoa(args.ResponseCode)),
-			tag.Insert(ResponseCodeClassKey, metrics.ResponseCodeClass(args.ResponseCode)),
-		),
+		stats.WithTags(tags...),
 	)
 	return nil
 }
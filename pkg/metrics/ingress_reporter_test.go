@@ -86,3 +86,31 @@ func TestStatsReporterWithCustomEventType(t *testing.T) {
 	})
 	metricstest.CheckCountData(t, "event_count", wantTags, 2)
 }
+
+func TestStatsReporterWithReason(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	args := IngressReportArgs{
+		EventType:    "testeventtype",
+		ResponseCode: 403,
+		Reason:       "sender_not_allowed",
+	}
+	wantTags := map[string]string{
+		metricskey.LabelEventType:         "custom",
+		metricskey.LabelResponseCode:      "403",
+		metricskey.LabelResponseCodeClass: "4xx",
+		"reason":                          "sender_not_allowed",
+		metricskey.ContainerName:          "testcontainer",
+		metricskey.PodName:                "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reportertest.ExpectMetrics(t, func() error {
+		return r.ReportEventCount(context.Background(), args)
+	})
+	metricstest.CheckCountData(t, "event_count", wantTags, 1)
+}
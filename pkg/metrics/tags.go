@@ -27,6 +27,7 @@ const (
 	defaultEventType  = "custom"
 	labelResourceKind = "resource_kind"
 	labelResourceName = "resource_name"
+	labelReason       = "reason"
 )
 
 type PodName string
@@ -49,6 +50,7 @@ var (
 
 	ResponseCodeKey      = tag.MustNewKey(metricskey.LabelResponseCode)
 	ResponseCodeClassKey = tag.MustNewKey(metricskey.LabelResponseCodeClass)
+	ReasonKey            = tag.MustNewKey(labelReason)
 
 	PodNameKey       = tag.MustNewKey(metricskey.PodName)
 	ContainerNameKey = tag.MustNewKey(metricskey.ContainerName)
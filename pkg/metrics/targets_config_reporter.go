@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+const (
+	TargetsConfigLatencyMetricName string = "brokercell_targets_config_latency"
+
+	// IncrementalRebuild is the rebuild tag value of the targets configs updated by rebuilding
+	// only the Brokers and Channels that changed.
+	IncrementalRebuild = "incremental"
+	// FullRebuild is the rebuild tag value of the targets configs rebuilt from scratch.
+	FullRebuild = "full"
+)
+
+// RebuildKey tags the targets config latencies with the kind of rebuild.
+var RebuildKey = tag.MustNewKey("rebuild")
+
+// TargetsConfigReporter reports the time it takes to rebuild and write the targets configs of
+// the BrokerCells.
+type TargetsConfigReporter struct {
+	durationInMsecM *stats.Float64Measure
+}
+
+func (r *TargetsConfigReporter) register() error {
+	return metrics.RegisterResourceView(
+		&view.View{
+			Name:        r.durationInMsecM.Name(),
+			Description: r.durationInMsecM.Description(),
+			Measure:     r.durationInMsecM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 100000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000, 20000, 50000, 1000000
+			TagKeys: []tag.Key{
+				RebuildKey,
+			},
+		},
+	)
+}
+
+// NewTargetsConfigReporter creates a new TargetsConfigReporter
+func NewTargetsConfigReporter() (*TargetsConfigReporter, error) {
+	r := &TargetsConfigReporter{
+		durationInMsecM: stats.Float64(
+			TargetsConfigLatencyMetricName,
+			"Time it takes to rebuild the targets config of a BrokerCell and write it in milliseconds",
+			stats.UnitMilliseconds,
+		),
+	}
+	if err := r.register(); err != nil {
+		return nil, fmt.Errorf("failed to register TargetsConfigReporter: %w", err)
+	}
+	return r, nil
+}
+
+// ReportLatency records the time it took to rebuild and write a targets config. rebuild is
+// either IncrementalRebuild or FullRebuild.
+func (r *TargetsConfigReporter) ReportLatency(ctx context.Context, duration time.Duration, rebuild string) error {
+	tag, err := tag.New(
+		ctx,
+		tag.Insert(RebuildKey, rebuild),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create metrics tag: %w", err)
+	}
+	durationMetricValue := r.durationInMsecM.M(float64(duration / time.Millisecond))
+	metrics.Record(tag, durationMetricValue)
+	return nil
+}
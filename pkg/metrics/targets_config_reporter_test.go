@@ -0,0 +1,61 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+
+	"knative.dev/pkg/metrics/metricstest"
+)
+
+func TestReportTargetsConfigLatency(t *testing.T) {
+	cases := []struct {
+		rebuild   string
+		durations []time.Duration
+		min, max  float64
+	}{{
+		rebuild:   IncrementalRebuild,
+		durations: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond},
+		min:       10.0,
+		max:       100.0,
+	}, {
+		rebuild:   FullRebuild,
+		durations: []time.Duration{5 * time.Second},
+		min:       5000.0,
+		max:       5000.0,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.rebuild, func(t *testing.T) {
+			reportertest.ResetBrokerCellMetrics()
+			r, err := NewTargetsConfigReporter()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range tc.durations {
+				reportertest.ExpectMetrics(t, func() error {
+					return r.ReportLatency(context.Background(), d, tc.rebuild)
+				})
+			}
+			expectedTags := map[string]string{"rebuild": tc.rebuild}
+			metricstest.CheckDistributionData(t, TargetsConfigLatencyMetricName, expectedTags, int64(len(tc.durations)), tc.min, tc.max)
+		})
+	}
+}
@@ -13,12 +13,12 @@ func ResetIngressMetrics() {
 
 func ResetDeliveryMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
-	metricstest.Unregister("event_count", "event_dispatch_latencies", "event_processing_latencies")
+	metricstest.Unregister("event_count", "event_dispatch_latencies", "event_processing_latencies", "event_delivery_attempts", "event_throttle_latencies", "circuit_breaker_state")
 }
 
 func ResetBrokerCellMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
-	metricstest.Unregister("brokercell_delay")
+	metricstest.Unregister("brokercell_delay", "brokercell_targets_config_latency")
 }
 
 func ExpectMetrics(t *testing.T, f func() error) {
@@ -18,6 +18,7 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	nethttp "net/http"
 
 	"go.uber.org/zap"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	kntracing "knative.dev/eventing/pkg/tracing"
 
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	"github.com/google/knative-gcp/pkg/apis/messaging"
 	"github.com/google/knative-gcp/pkg/logging"
 	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
@@ -58,6 +60,13 @@ type AdapterArgs struct {
 	// ConverterType use to select which converter to use.
 	ConverterType converters.ConverterType
 
+	// EventMapping is the mapping used by the Mapping converter.
+	EventMapping *duckv1.EventMapping
+	// ImageFilter is the filter used by the CloudArtifactRegistry converter.
+	ImageFilter *duckv1.ImageFilter
+	// BudgetFilter is the filter used by the CloudBillingBudget converter.
+	BudgetFilter *duckv1.BudgetFilter
+
 	// AuthType is the authentication configuration mode the Pod uses.
 	AuthType authcheck.AuthType
 }
@@ -122,11 +131,7 @@ func NewAdapter(
 
 func (a *Adapter) Start(ctx context.Context) error {
 	ctx, a.cancel = context.WithCancel(ctx)
-
-	// Augment context so that we can use it to create CE attributes.
-	ctx = WithProjectKey(ctx, a.projectID)
-	ctx = WithTopicKey(ctx, a.args.TopicID)
-	ctx = WithSubscriptionKey(ctx, a.subscription.ID())
+	ctx = a.withArgs(ctx, a.subscription.ID())
 
 	// Initialize probe checker to run authentication check.
 	pc := authcheck.NewProbeChecker(logging.FromContext(ctx), a.args.AuthType)
@@ -134,20 +139,54 @@ func (a *Adapter) Start(ctx context.Context) error {
 	return a.subscription.Receive(ctx, a.receive)
 }
 
+// withArgs augments the context so that the converters can use it to create the CE attributes.
+func (a *Adapter) withArgs(ctx context.Context, subscriptionID string) context.Context {
+	ctx = WithProjectKey(ctx, a.projectID)
+	ctx = WithTopicKey(ctx, a.args.TopicID)
+	ctx = WithSubscriptionKey(ctx, subscriptionID)
+	if a.args.EventMapping != nil {
+		ctx = WithEventMappingKey(ctx, a.args.EventMapping)
+	}
+	if a.args.ImageFilter != nil {
+		ctx = WithImageFilterKey(ctx, a.args.ImageFilter)
+	}
+	if a.args.BudgetFilter != nil {
+		ctx = WithBudgetFilterKey(ctx, a.args.BudgetFilter)
+	}
+	return ctx
+}
+
 // Stop stops the adapter.
 func (a *Adapter) Stop() {
 	a.cancel()
 }
 
+func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
+	if a.deliver(ctx, msg) {
+		msg.Ack()
+	} else {
+		msg.Nack()
+	}
+}
+
+// Deliver converts a message received by the subscription with the given ID and sends the
+// resulting event to the sink. It returns whether the message should be acknowledged, i.e. false
+// if the delivery should be retried. It is used to deliver the messages of push subscriptions.
+func (a *Adapter) Deliver(ctx context.Context, subscriptionID string, msg *pubsub.Message) bool {
+	return a.deliver(a.withArgs(ctx, subscriptionID), msg)
+}
+
 // TODO refactor this method. As our RA code is used both for Sources and our Channel, it also supports replies
 //  (in the case of Channels) and the logic is more convoluted.
-func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
+func (a *Adapter) deliver(ctx context.Context, msg *pubsub.Message) bool {
 	event, err := a.converter.Convert(ctx, msg, a.args.ConverterType)
+	if errors.Is(err, converters.ErrMessageFiltered) {
+		return true
+	}
 	if err != nil {
 		a.logger.Debug("Failed to convert received message to an event, check the msg format: %v", zap.Error(err))
 		// Ack the message so it won't be retried, we consider all errors to be non-retryable.
-		msg.Ack()
-		return
+		return true
 	}
 
 	ctx, span := a.startSpan(ctx, event)
@@ -169,8 +208,7 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 		resp, err := a.sendMsg(ctx, a.args.TransformerURI, (*binding.EventMessage)(event))
 		if err != nil {
 			a.logger.Error("Failed to send message to transformer", zap.String("address", a.args.TransformerURI), zap.Error(err))
-			msg.Nack()
-			return
+			return false
 		}
 
 		defer func() {
@@ -183,15 +221,13 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 
 		if resp.StatusCode/100 != 2 {
 			a.logger.Error("Event delivery failed", zap.Int("StatusCode", resp.StatusCode))
-			msg.Nack()
-			return
+			return false
 		}
 
 		respMsg := cehttp.NewMessageFromHttpResponse(resp)
 		if respMsg.ReadEncoding() == binding.EncodingUnknown {
 			// No reply
-			msg.Ack()
-			return
+			return true
 		}
 
 		// If there was a reply, we need to send it to the sink.
@@ -200,8 +236,7 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 		if err != nil {
 			a.logger.Error("Failed to convert response message to event",
 				zap.Any("response", respMsg), zap.Error(err))
-			msg.Nack()
-			return
+			return false
 		}
 
 		// Update the arguments used to report metrics
@@ -223,8 +258,7 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 	response, err := a.sendMsg(ctx, a.args.SinkURI, (*binding.EventMessage)(event))
 	if err != nil {
 		a.logger.Error("Failed to send message to sink", zap.String("address", a.args.SinkURI), zap.Error(err))
-		msg.Nack()
-		return
+		return false
 	}
 
 	defer func() {
@@ -237,11 +271,10 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 
 	if response.StatusCode/100 != 2 {
 		a.logger.Error("Event delivery failed", zap.Int("StatusCode", response.StatusCode))
-		msg.Nack()
-		return
+		return false
 	}
 
-	msg.Ack()
+	return true
 }
 
 func (a *Adapter) sendMsg(ctx context.Context, address string, msg binding.Message) (*nethttp.Response, error) {
@@ -260,7 +293,8 @@ func (a *Adapter) startSpan(ctx context.Context, event *cev2.Event) (context.Con
 	// This receive adapter code is used both for Sources and Channels.
 	// An ugly way to identify whether it was created from a Channel is to look at the resourceGroup.
 	if a.resourceGroup == messaging.ChannelsResource.String() {
-		spanName = tracing.SubscriptionDestination(a.subscription.ID())
+		subscriptionID, _ := GetSubscriptionKey(ctx)
+		spanName = tracing.SubscriptionDestination(subscriptionID)
 	}
 	var span *trace.Span
 	if dt, ok := extensions.GetDistributedTracingExtension(*event); ok {
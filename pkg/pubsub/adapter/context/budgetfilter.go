@@ -0,0 +1,40 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+// The key used to store/retrieve the budget filter in the context.
+type budgetFilterKey struct{}
+
+// WithBudgetFilterKey sets an budget filter key in the context.
+func WithBudgetFilterKey(ctx context.Context, key *duckv1.BudgetFilter) context.Context {
+	return context.WithValue(ctx, budgetFilterKey{}, key)
+}
+
+// GetBudgetFilterKey gets the budget filter key from the context.
+func GetBudgetFilterKey(ctx context.Context) (*duckv1.BudgetFilter, error) {
+	untyped := ctx.Value(budgetFilterKey{})
+	if untyped == nil {
+		return nil, ErrBudgetFilterKeyNotPresent
+	}
+	return untyped.(*duckv1.BudgetFilter), nil
+}
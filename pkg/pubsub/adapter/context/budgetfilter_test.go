@@ -0,0 +1,41 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"testing"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+func TestBudgetFilterKey(t *testing.T) {
+	_, err := GetBudgetFilterKey(context.Background())
+	if err != ErrBudgetFilterKeyNotPresent {
+		t.Errorf("error from GetBudgetFilterKey got=%v, want=%v", err, ErrBudgetFilterKeyNotPresent)
+	}
+
+	wantKey := &duckv1.BudgetFilter{ThresholdPercent: 90}
+	ctx := WithBudgetFilterKey(context.Background(), wantKey)
+	gotKey, err := GetBudgetFilterKey(ctx)
+	if err != nil {
+		t.Errorf("unexpected error from GetBudgetFilterKey: %v", err)
+	}
+	if gotKey != wantKey {
+		t.Errorf("budget filter key from context got=%v, want=%v", gotKey, wantKey)
+	}
+}
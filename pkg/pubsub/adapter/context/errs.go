@@ -19,6 +19,9 @@ package context
 import "errors"
 
 var (
+	ErrBudgetFilterKeyNotPresent = errors.New("budget filter key not present in the context")
+	ErrEventMappingKeyNotPresent = errors.New("event mapping key not present in the context")
+	ErrImageFilterKeyNotPresent  = errors.New("image filter key not present in the context")
 	ErrProjectKeyNotPresent      = errors.New("project key not present in the context")
 	ErrSubscriptionKeyNotPresent = errors.New("subscription key not present in the context")
 	ErrTopicKeyNotPresent        = errors.New("topic key not present in the context")
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+// The key used to store/retrieve the event mapping in the context.
+type eventMappingKey struct{}
+
+// WithEventMappingKey sets an event mapping key in the context.
+func WithEventMappingKey(ctx context.Context, key *duckv1.EventMapping) context.Context {
+	return context.WithValue(ctx, eventMappingKey{}, key)
+}
+
+// GetEventMappingKey gets the event mapping key from the context.
+func GetEventMappingKey(ctx context.Context) (*duckv1.EventMapping, error) {
+	untyped := ctx.Value(eventMappingKey{})
+	if untyped == nil {
+		return nil, ErrEventMappingKeyNotPresent
+	}
+	return untyped.(*duckv1.EventMapping), nil
+}
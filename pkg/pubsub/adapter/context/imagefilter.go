@@ -0,0 +1,40 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+// The key used to store/retrieve the image filter in the context.
+type imageFilterKey struct{}
+
+// WithImageFilterKey sets an image filter key in the context.
+func WithImageFilterKey(ctx context.Context, key *duckv1.ImageFilter) context.Context {
+	return context.WithValue(ctx, imageFilterKey{}, key)
+}
+
+// GetImageFilterKey gets the image filter key from the context.
+func GetImageFilterKey(ctx context.Context) (*duckv1.ImageFilter, error) {
+	untyped := ctx.Value(imageFilterKey{})
+	if untyped == nil {
+		return nil, ErrImageFilterKeyNotPresent
+	}
+	return untyped.(*duckv1.ImageFilter), nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"testing"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+func TestImageFilterKey(t *testing.T) {
+	_, err := GetImageFilterKey(context.Background())
+	if err != ErrImageFilterKeyNotPresent {
+		t.Errorf("error from GetImageFilterKey got=%v, want=%v", err, ErrImageFilterKeyNotPresent)
+	}
+
+	wantKey := &duckv1.ImageFilter{Actions: []string{duckv1.ImageActionInsert}}
+	ctx := WithImageFilterKey(context.Background(), wantKey)
+	gotKey, err := GetImageFilterKey(ctx)
+	if err != nil {
+		t.Errorf("unexpected error from GetImageFilterKey: %v", err)
+	}
+	if gotKey != wantKey {
+		t.Errorf("image filter key from context got=%v, want=%v", gotKey, wantKey)
+	}
+}
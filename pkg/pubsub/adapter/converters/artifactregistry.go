@@ -0,0 +1,151 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+// imageNotification is the data of the Artifact Registry and Container Registry notifications,
+// see https://cloud.google.com/artifact-registry/docs/configure-notifications.
+type imageNotification struct {
+	Action string `json:"action"`
+	// Digest is the digest reference of the image, e.g. 'gcr.io/project/image@sha256:...'.
+	Digest string `json:"digest"`
+	// Tag is the tag reference of the image, e.g. 'gcr.io/project/image:latest'.
+	Tag string `json:"tag"`
+}
+
+// image returns the name of the image, its tag and its digest. The tag and the digest are
+// empty when they aren't part of the notification.
+func (n *imageNotification) image() (name, tag, digest string) {
+	if n.Digest != "" {
+		if i := strings.LastIndex(n.Digest, "@"); i >= 0 {
+			name, digest = n.Digest[:i], n.Digest[i+1:]
+		}
+	}
+	if n.Tag != "" {
+		// The tag follows the last colon that is after the last slash, the other colons
+		// separate the port of the registry.
+		if i := strings.LastIndex(n.Tag, ":"); i > strings.LastIndex(n.Tag, "/") {
+			name, tag = n.Tag[:i], n.Tag[i+1:]
+		}
+	}
+	return name, tag, digest
+}
+
+func convertCloudArtifactRegistry(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	project, err := GetProjectKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var n imageNotification
+	if err := json.Unmarshal(msg.Data, &n); err != nil {
+		return nil, fmt.Errorf("failed to parse the image notification: %w", err)
+	}
+	name, tag, digest := n.image()
+	if name == "" {
+		return nil, errors.New("received image notification did not have an image reference")
+	}
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+	event.SetSource(schemasv1.ArtifactRegistryEventSource(project))
+	switch n.Action {
+	case duckv1.ImageActionInsert:
+		event.SetType(schemasv1.ArtifactRegistryImagePushedEventType)
+	case duckv1.ImageActionDelete:
+		event.SetType(schemasv1.ArtifactRegistryImageDeletedEventType)
+	default:
+		return nil, fmt.Errorf("received image notification had an unknown action %q", n.Action)
+	}
+	// The subject is the most specific reference of the image.
+	if n.Tag != "" {
+		event.SetSubject(n.Tag)
+	} else {
+		event.SetSubject(n.Digest)
+	}
+
+	if filter, err := GetImageFilterKey(ctx); err == nil && !matchImage(filter, n.Action, name, tag, digest) {
+		return nil, ErrMessageFiltered
+	}
+
+	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// matchImage returns whether the notification of the image matches every non-empty list of
+// the filter.
+func matchImage(filter *duckv1.ImageFilter, action, name, tag, digest string) bool {
+	if len(filter.Actions) > 0 && !contains(filter.Actions, action) {
+		return false
+	}
+	if len(filter.Digests) > 0 && !contains(filter.Digests, digest) {
+		return false
+	}
+	if len(filter.Repositories) > 0 {
+		matched := false
+		for _, r := range filter.Repositories {
+			if name == r || strings.HasPrefix(name, r+"/") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(filter.Tags) > 0 {
+		matched := false
+		for _, pattern := range filter.Tags {
+			// The patterns are validated, thus the error can be ignored.
+			if ok, _ := path.Match(pattern, tag); ok && tag != "" {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	testImageDigest = "sha256:6ec128e26cd5ab8d12d1bba4b9fa5d1b3c9d8c3d6a8c2c1d1e2f3a4b5c6d7e8f"
+)
+
+func TestConvertCloudArtifactRegistry(t *testing.T) {
+	publishTime := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	pushed := []byte(`{"action":"INSERT","digest":"us-docker.pkg.dev/my-project/my-repo/app@` + testImageDigest + `","tag":"us-docker.pkg.dev/my-project/my-repo/app:v1.2"}`)
+	deleted := []byte(`{"action":"DELETE","digest":"localhost:5000/app@` + testImageDigest + `"}`)
+
+	tests := []struct {
+		name        string
+		filter      *duckv1.ImageFilter
+		data        []byte
+		wantEventFn func() *cev2.Event
+		wantErr     error
+	}{{
+		name: "pushed image",
+		data: pushed,
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.ArtifactRegistryEventSource("testproject"))
+			e.SetType(schemasv1.ArtifactRegistryImagePushedEventType)
+			e.SetSubject("us-docker.pkg.dev/my-project/my-repo/app:v1.2")
+			e.SetData(cev2.ApplicationJSON, pushed)
+			return &e
+		},
+	}, {
+		name: "deleted image",
+		data: deleted,
+		filter: &duckv1.ImageFilter{
+			Repositories: []string{"localhost:5000/app"},
+			Digests:      []string{testImageDigest},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.ArtifactRegistryEventSource("testproject"))
+			e.SetType(schemasv1.ArtifactRegistryImageDeletedEventType)
+			e.SetSubject("localhost:5000/app@" + testImageDigest)
+			e.SetData(cev2.ApplicationJSON, deleted)
+			return &e
+		},
+	}, {
+		name: "matching filter",
+		data: pushed,
+		filter: &duckv1.ImageFilter{
+			Repositories: []string{"gcr.io/my-project", "us-docker.pkg.dev/my-project/my-repo"},
+			Tags:         []string{"v1.*"},
+			Actions:      []string{duckv1.ImageActionInsert},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.ArtifactRegistryEventSource("testproject"))
+			e.SetType(schemasv1.ArtifactRegistryImagePushedEventType)
+			e.SetSubject("us-docker.pkg.dev/my-project/my-repo/app:v1.2")
+			e.SetData(cev2.ApplicationJSON, pushed)
+			return &e
+		},
+	}, {
+		name:    "filtered repository",
+		data:    pushed,
+		filter:  &duckv1.ImageFilter{Repositories: []string{"us-docker.pkg.dev/my-project/my"}},
+		wantErr: ErrMessageFiltered,
+	}, {
+		name:    "filtered tag",
+		data:    pushed,
+		filter:  &duckv1.ImageFilter{Tags: []string{"v2.*"}},
+		wantErr: ErrMessageFiltered,
+	}, {
+		name:    "untagged image with a tag filter",
+		data:    deleted,
+		filter:  &duckv1.ImageFilter{Tags: []string{"*"}},
+		wantErr: ErrMessageFiltered,
+	}, {
+		name:    "filtered action",
+		data:    deleted,
+		filter:  &duckv1.ImageFilter{Actions: []string{duckv1.ImageActionInsert}},
+		wantErr: ErrMessageFiltered,
+	}, {
+		name:    "unknown action",
+		data:    []byte(`{"action":"UPDATE","tag":"gcr.io/my-project/app:latest"}`),
+		wantErr: errors.New(`received image notification had an unknown action "UPDATE"`),
+	}, {
+		name:    "no image reference",
+		data:    []byte(`{"action":"INSERT"}`),
+		wantErr: errors.New("received image notification did not have an image reference"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithProjectKey(context.Background(), "testproject")
+			if test.filter != nil {
+				ctx = WithImageFilterKey(ctx, test.filter)
+			}
+			msg := &pubsub.Message{
+				ID:          "id",
+				Data:        test.data,
+				PublishTime: publishTime,
+			}
+
+			gotEvent, err := NewPubSubConverter().Convert(ctx, msg, CloudArtifactRegistry)
+			if test.wantErr != nil {
+				if err == nil || err.Error() != test.wantErr.Error() {
+					t.Fatalf("converters.convertCloudArtifactRegistry got error %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("converters.convertCloudArtifactRegistry got unexpected error %v", err)
+			}
+			if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+				t.Errorf("converters.convertCloudArtifactRegistry got unexpeceted cloudevents.Event (-want +got) %s", diff)
+			}
+		})
+	}
+}
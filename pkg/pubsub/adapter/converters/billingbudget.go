@@ -0,0 +1,159 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	// budgetCostRatioExtension is the extension with the ratio of the cost to the budget amount.
+	budgetCostRatioExtension = "costratio"
+	// budgetAlertThresholdExtension is the extension with the alert threshold exceeded by the
+	// cost, as a ratio of the budget amount.
+	budgetAlertThresholdExtension = "alertthreshold"
+)
+
+// budgetNotification is the part of the data of the budget notifications used by the converter,
+// see https://cloud.google.com/billing/docs/how-to/budgets-programmatic-notifications.
+type budgetNotification struct {
+	CostAmount        float64 `json:"costAmount"`
+	CostIntervalStart string  `json:"costIntervalStart"`
+	BudgetAmount      float64 `json:"budgetAmount"`
+	// AlertThresholdExceeded is only set once the cost exceeded an alert threshold.
+	AlertThresholdExceeded *float64 `json:"alertThresholdExceeded"`
+}
+
+// budgetConverter converts the budget notifications. Budgets send notifications several times a
+// day, so it remembers the last alert threshold crossing delivered for each budget in order to
+// only deliver the crossings of a higher threshold when the notifications are filtered.
+type budgetConverter struct {
+	// crossings holds the last crossing delivered for each subscription and budget.
+	crossings   map[budgetKey]budgetCrossing
+	crossingsMu sync.Mutex
+}
+
+type budgetKey struct {
+	subscription string
+	budgetID     string
+}
+
+// budgetCrossing is the notification of the last alert threshold crossed by the cost of a budget.
+type budgetCrossing struct {
+	messageID         string
+	costIntervalStart string
+	threshold         float64
+}
+
+func newBudgetConverter() *budgetConverter {
+	return &budgetConverter{
+		crossings: make(map[budgetKey]budgetCrossing),
+	}
+}
+
+func (c *budgetConverter) convert(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	billingAccountID, ok := msg.Attributes[schemasv1.CloudBillingBudgetBillingAccountID]
+	if !ok {
+		return nil, errors.New("received event did not have billingAccountId")
+	}
+	budgetID, ok := msg.Attributes[schemasv1.CloudBillingBudgetBudgetID]
+	if !ok {
+		return nil, errors.New("received event did not have budgetId")
+	}
+
+	var n budgetNotification
+	if err := json.Unmarshal(msg.Data, &n); err != nil {
+		return nil, fmt.Errorf("failed to parse the budget notification: %w", err)
+	}
+	if filter, err := GetBudgetFilterKey(ctx); err == nil && !filter.IsEmpty() {
+		subscription, _ := GetSubscriptionKey(ctx)
+		if !matchBudget(filter, &n) || !c.cross(budgetKey{subscription: subscription, budgetID: budgetID}, msg.ID, &n) {
+			return nil, ErrMessageFiltered
+		}
+	}
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+	event.SetSource(schemasv1.CloudBillingBudgetEventSource(billingAccountID))
+	event.SetSubject(schemasv1.CloudBillingBudgetEventSubject(budgetID))
+	if n.AlertThresholdExceeded != nil {
+		event.SetType(schemasv1.CloudBillingBudgetThresholdExceededEventType)
+		event.SetExtension(budgetAlertThresholdExtension, formatRatio(*n.AlertThresholdExceeded))
+	} else {
+		event.SetType(schemasv1.CloudBillingBudgetCostUpdatedEventType)
+	}
+	// The budget amount is zero for the budgets of the last period's amount before the first
+	// period ends.
+	if n.BudgetAmount > 0 {
+		event.SetExtension(budgetCostRatioExtension, formatRatio(n.CostAmount/n.BudgetAmount))
+	}
+
+	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// matchBudget returns whether the budget notification exceeded the threshold of the filter.
+func matchBudget(filter *duckv1.BudgetFilter, n *budgetNotification) bool {
+	if filter.ThresholdPercent == 0 {
+		return true
+	}
+	if n.AlertThresholdExceeded == nil {
+		return false
+	}
+	// Round the percentage to avoid floating point errors, e.g. 0.29 * 100 is 28.999999999999996.
+	return math.Round(*n.AlertThresholdExceeded*1e4)/1e2 >= float64(filter.ThresholdPercent)
+}
+
+// cross records the alert threshold exceeded by the notification, and returns whether the cost
+// crossed it, i.e. whether it is higher than the last threshold crossed in the same cost interval.
+// The redeliveries of the last crossing are crossings too, so that failed deliveries are retried.
+func (c *budgetConverter) cross(key budgetKey, messageID string, n *budgetNotification) bool {
+	c.crossingsMu.Lock()
+	defer c.crossingsMu.Unlock()
+	last, ok := c.crossings[key]
+	if ok && last.messageID != messageID && last.costIntervalStart == n.CostIntervalStart &&
+		*n.AlertThresholdExceeded <= last.threshold {
+		return false
+	}
+	c.crossings[key] = budgetCrossing{
+		messageID:         messageID,
+		costIntervalStart: n.CostIntervalStart,
+		threshold:         *n.AlertThresholdExceeded,
+	}
+	return true
+}
+
+// formatRatio formats a ratio with at most 4 decimals.
+func formatRatio(r float64) string {
+	return strconv.FormatFloat(math.Round(r*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+func TestConvertCloudBillingBudget(t *testing.T) {
+	publishTime := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	exceeded := []byte(`{"budgetDisplayName":"my-budget","alertThresholdExceeded":0.9,"costAmount":92.5,"costIntervalStart":"2021-03-01T08:00:00Z","budgetAmount":100.0,"budgetAmountType":"SPECIFIED_AMOUNT","currencyCode":"USD"}`)
+	updated := []byte(`{"budgetDisplayName":"my-budget","costAmount":33.333333,"costIntervalStart":"2021-03-01T08:00:00Z","budgetAmount":100.0,"budgetAmountType":"SPECIFIED_AMOUNT","currencyCode":"USD"}`)
+	attributes := map[string]string{
+		"billingAccountId": "01D4EE-079462-DFD6EC",
+		"budgetId":         "de72f49d-779b-4945-a127-4d6ce8def0bb",
+		"schemaVersion":    "1.0",
+	}
+
+	tests := []struct {
+		name        string
+		filter      *duckv1.BudgetFilter
+		attributes  map[string]string
+		data        []byte
+		wantEventFn func() *cev2.Event
+		wantErr     error
+	}{{
+		name:       "threshold exceeded",
+		attributes: attributes,
+		data:       exceeded,
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.CloudBillingBudgetEventSource("01D4EE-079462-DFD6EC"))
+			e.SetSubject(schemasv1.CloudBillingBudgetEventSubject("de72f49d-779b-4945-a127-4d6ce8def0bb"))
+			e.SetType(schemasv1.CloudBillingBudgetThresholdExceededEventType)
+			e.SetExtension("alertthreshold", "0.9")
+			e.SetExtension("costratio", "0.925")
+			e.SetData(cev2.ApplicationJSON, exceeded)
+			return &e
+		},
+	}, {
+		name:       "cost updated",
+		attributes: attributes,
+		data:       updated,
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.CloudBillingBudgetEventSource("01D4EE-079462-DFD6EC"))
+			e.SetSubject(schemasv1.CloudBillingBudgetEventSubject("de72f49d-779b-4945-a127-4d6ce8def0bb"))
+			e.SetType(schemasv1.CloudBillingBudgetCostUpdatedEventType)
+			e.SetExtension("costratio", "0.3333")
+			e.SetData(cev2.ApplicationJSON, updated)
+			return &e
+		},
+	}, {
+		name:       "threshold above the filter",
+		filter:     &duckv1.BudgetFilter{ThresholdPercent: 90},
+		attributes: attributes,
+		data:       exceeded,
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.CloudBillingBudgetEventSource("01D4EE-079462-DFD6EC"))
+			e.SetSubject(schemasv1.CloudBillingBudgetEventSubject("de72f49d-779b-4945-a127-4d6ce8def0bb"))
+			e.SetType(schemasv1.CloudBillingBudgetThresholdExceededEventType)
+			e.SetExtension("alertthreshold", "0.9")
+			e.SetExtension("costratio", "0.925")
+			e.SetData(cev2.ApplicationJSON, exceeded)
+			return &e
+		},
+	}, {
+		name:       "threshold below the filter",
+		filter:     &duckv1.BudgetFilter{ThresholdPercent: 100},
+		attributes: attributes,
+		data:       exceeded,
+		wantErr:    ErrMessageFiltered,
+	}, {
+		name:       "no threshold with a filter",
+		filter:     &duckv1.BudgetFilter{ThresholdPercent: 50},
+		attributes: attributes,
+		data:       updated,
+		wantErr:    ErrMessageFiltered,
+	}, {
+		name: "missing budget id",
+		attributes: map[string]string{
+			"billingAccountId": "01D4EE-079462-DFD6EC",
+		},
+		data:    exceeded,
+		wantErr: errors.New("received event did not have budgetId"),
+	}, {
+		name:       "data is not JSON",
+		attributes: attributes,
+		data:       []byte("test data"),
+		wantErr:    errors.New("failed to parse the budget notification: invalid character 'e' in literal true (expecting 'r')"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.filter != nil {
+				ctx = WithBudgetFilterKey(ctx, test.filter)
+			}
+			msg := &pubsub.Message{
+				ID:          "id",
+				Data:        test.data,
+				Attributes:  test.attributes,
+				PublishTime: publishTime,
+			}
+
+			gotEvent, err := NewPubSubConverter().Convert(ctx, msg, CloudBillingBudget)
+			if test.wantErr != nil {
+				if err == nil || err.Error() != test.wantErr.Error() {
+					t.Fatalf("converters.convertCloudBillingBudget got error %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("converters.convertCloudBillingBudget got unexpected error %v", err)
+			}
+			if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+				t.Errorf("converters.convertCloudBillingBudget got unexpeceted cloudevents.Event (-want +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestMatchBudget(t *testing.T) {
+	threshold := 0.29
+	n := &budgetNotification{AlertThresholdExceeded: &threshold}
+	if !matchBudget(&duckv1.BudgetFilter{ThresholdPercent: 29}, n) {
+		t.Errorf("matchBudget got false for a threshold of %v and a filter of 29 percent, want true", threshold)
+	}
+}
+
+func TestConvertCloudBillingBudgetCrossings(t *testing.T) {
+	attributes := map[string]string{
+		"billingAccountId": "01D4EE-079462-DFD6EC",
+		"budgetId":         "de72f49d-779b-4945-a127-4d6ce8def0bb",
+	}
+	notification := func(threshold, intervalStart string) []byte {
+		return []byte(`{"alertThresholdExceeded":` + threshold + `,"costAmount":95.0,"costIntervalStart":"` + intervalStart + `","budgetAmount":100.0}`)
+	}
+	march, april := "2021-03-01T08:00:00Z", "2021-04-01T07:00:00Z"
+
+	c := NewPubSubConverter()
+	filter := &duckv1.BudgetFilter{ThresholdPercent: 50}
+	tests := []struct {
+		name         string
+		subscription string
+		id           string
+		data         []byte
+		wantFiltered bool
+	}{{
+		name:         "first crossing",
+		subscription: "sub",
+		id:           "1",
+		data:         notification("0.5", march),
+	}, {
+		name:         "same threshold",
+		subscription: "sub",
+		id:           "2",
+		data:         notification("0.5", march),
+		wantFiltered: true,
+	}, {
+		name:         "crossing redelivered",
+		subscription: "sub",
+		id:           "1",
+		data:         notification("0.5", march),
+	}, {
+		name:         "higher threshold",
+		subscription: "sub",
+		id:           "3",
+		data:         notification("0.9", march),
+	}, {
+		name:         "lower threshold",
+		subscription: "sub",
+		id:           "4",
+		data:         notification("0.5", march),
+		wantFiltered: true,
+	}, {
+		name:         "other subscription",
+		subscription: "other",
+		id:           "4",
+		data:         notification("0.5", march),
+	}, {
+		name:         "next cost interval",
+		subscription: "sub",
+		id:           "5",
+		data:         notification("0.5", april),
+	}}
+	for _, test := range tests {
+		ctx := WithSubscriptionKey(WithBudgetFilterKey(context.Background(), filter), test.subscription)
+		msg := &pubsub.Message{
+			ID:         test.id,
+			Data:       test.data,
+			Attributes: attributes,
+		}
+		_, err := c.Convert(ctx, msg, CloudBillingBudget)
+		if test.wantFiltered {
+			if !errors.Is(err, ErrMessageFiltered) {
+				t.Errorf("%s: converters.convertCloudBillingBudget got error %v, want %v", test.name, err, ErrMessageFiltered)
+			}
+		} else if err != nil {
+			t.Errorf("%s: converters.convertCloudBillingBudget got unexpected error %v", test.name, err)
+		}
+	}
+}
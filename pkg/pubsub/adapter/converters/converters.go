@@ -20,6 +20,7 @@ package converters
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"cloud.google.com/go/pubsub"
@@ -50,8 +51,22 @@ const (
 	CloudScheduler ConverterType = "scheduler"
 	CloudBuild     ConverterType = "build"
 	PubSubPull     ConverterType = "pubsub_pull"
+	// Mapping converts the messages as declared by an EventMapping.
+	Mapping ConverterType = "mapping"
+	// CloudArtifactRegistry converts the container image notifications, optionally filtered by
+	// the ImageFilter in the context.
+	CloudArtifactRegistry ConverterType = "artifactregistry"
+	// CloudBillingBudget converts the budget notifications, optionally filtered by the
+	// BudgetFilter in the context. Filtered notifications are only converted when the cost
+	// crosses a higher alert threshold.
+	CloudBillingBudget ConverterType = "billingbudget"
+	// CloudMonitoring converts the Cloud Monitoring alerting incident notifications.
+	CloudMonitoring ConverterType = "monitoring"
 )
 
+// ErrMessageFiltered is returned by the converters for the messages that are filtered out.
+var ErrMessageFiltered = errors.New("message filtered out")
+
 type converterFn func(context.Context, *pubsub.Message) (*cev2.Event, error)
 
 type Converter interface {
@@ -71,12 +86,16 @@ type PubSubConverter struct {
 func NewPubSubConverter() Converter {
 	return &PubSubConverter{
 		converters: map[ConverterType]converterFn{
-			CloudPubSub:    convertCloudPubSub,
-			CloudAuditLogs: convertCloudAuditLogs,
-			CloudStorage:   convertCloudStorage,
-			CloudScheduler: convertCloudScheduler,
-			CloudBuild:     convertCloudBuild,
-			PubSubPull:     convertPubSubPull,
+			CloudPubSub:           convertCloudPubSub,
+			CloudAuditLogs:        convertCloudAuditLogs,
+			CloudStorage:          convertCloudStorage,
+			CloudScheduler:        convertCloudScheduler,
+			CloudBuild:            convertCloudBuild,
+			CloudArtifactRegistry: convertCloudArtifactRegistry,
+			CloudBillingBudget:    newBudgetConverter().convert,
+			CloudMonitoring:       convertCloudMonitoring,
+			PubSubPull:            convertPubSubPull,
+			Mapping:               convertMapping,
 		},
 	}
 }
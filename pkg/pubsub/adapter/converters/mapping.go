@@ -0,0 +1,217 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+// convertMapping converts a message as declared by the EventMapping in the context.
+func convertMapping(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	mapping, err := GetEventMappingKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	project, err := GetProjectKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	topic, err := GetTopicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &messageMapper{msg: msg}
+	event := cev2.NewEvent(cev2.VersionV1)
+
+	id, err := m.valueOrDefault("id", mapping.ID, msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	event.SetID(id)
+
+	eventType, err := m.valueOrDefault("type", mapping.Type, schemasv1.CloudPubSubMessagePublishedEventType)
+	if err != nil {
+		return nil, err
+	}
+	event.SetType(eventType)
+
+	source, err := m.valueOrDefault("source", mapping.Source, schemasv1.CloudPubSubEventSource(project, topic))
+	if err != nil {
+		return nil, err
+	}
+	event.SetSource(source)
+
+	if mapping.Subject != nil {
+		subject, err := m.value("subject", mapping.Subject)
+		if err != nil {
+			return nil, err
+		}
+		event.SetSubject(subject)
+	}
+
+	if mapping.Time != nil {
+		value, err := m.value("time", mapping.Time)
+		if err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("time %q is not in RFC 3339 format: %w", value, err)
+		}
+		event.SetTime(t)
+	} else {
+		event.SetTime(msg.PublishTime)
+	}
+
+	for name, v := range mapping.Extensions {
+		v := v
+		value, err := m.value("extension "+name, &v)
+		if err != nil {
+			return nil, err
+		}
+		event.SetExtension(name, value)
+	}
+
+	contentType := mapping.DataContentType
+	if contentType == "" {
+		if mapping.HasField() {
+			contentType = cev2.ApplicationJSON
+		} else {
+			// We do not know the content type, thus we set this generic one.
+			contentType = "application/octet-stream"
+		}
+	}
+	if err := event.SetData(contentType, msg.Data); err != nil {
+		return nil, err
+	}
+	if err := event.Validate(); err != nil {
+		return nil, fmt.Errorf("mapped event is invalid: %w", err)
+	}
+	return &event, nil
+}
+
+// messageMapper resolves the ValueMappings of a message. It parses the data of the message at
+// most once.
+type messageMapper struct {
+	msg *pubsub.Message
+
+	parsed  bool
+	data    interface{}
+	dataErr error
+}
+
+// valueOrDefault returns the value of v, or def if v is nil.
+func (m *messageMapper) valueOrDefault(attribute string, v *duckv1.ValueMapping, def string) (string, error) {
+	if v == nil {
+		return def, nil
+	}
+	return m.value(attribute, v)
+}
+
+// value returns the value of v, or its default if the value is missing.
+func (m *messageMapper) value(attribute string, v *duckv1.ValueMapping) (string, error) {
+	var value string
+	switch {
+	case v.Value != "":
+		return v.Value, nil
+	case v.Attribute != "":
+		value = m.msg.Attributes[v.Attribute]
+	case v.Property != "":
+		var err error
+		if value, err = m.property(v.Property); err != nil {
+			return "", err
+		}
+	case v.Field != "":
+		var err error
+		if value, err = m.field(v.Field); err != nil {
+			return "", err
+		}
+	}
+	if value != "" {
+		return value, nil
+	}
+	if v.Default != nil {
+		return *v.Default, nil
+	}
+	return "", fmt.Errorf("the value of %s is missing from the message", attribute)
+}
+
+func (m *messageMapper) property(p duckv1.MessageProperty) (string, error) {
+	switch p {
+	case duckv1.MessageID:
+		return m.msg.ID, nil
+	case duckv1.MessagePublishTime:
+		if m.msg.PublishTime.IsZero() {
+			return "", nil
+		}
+		return m.msg.PublishTime.UTC().Format(time.RFC3339Nano), nil
+	case duckv1.MessageOrderingKey:
+		return m.msg.OrderingKey, nil
+	default:
+		return "", fmt.Errorf("unknown message property %q", p)
+	}
+}
+
+// field returns the value of the field at path in the JSON data of the message, or the empty
+// string if the field is missing or null.
+func (m *messageMapper) field(path string) (string, error) {
+	if !m.parsed {
+		m.parsed = true
+		d := json.NewDecoder(bytes.NewReader(m.msg.Data))
+		d.UseNumber()
+		if err := d.Decode(&m.data); err != nil {
+			m.dataErr = fmt.Errorf("failed to parse the data of the message as JSON: %w", err)
+		}
+	}
+	if m.dataErr != nil {
+		return "", m.dataErr
+	}
+	value := m.data
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", nil
+		}
+		value = object[key]
+	}
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("field %q is not a string, a number or a boolean", path)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/ptr"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+func TestConvertMapping(t *testing.T) {
+	publishTime := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	orderData := []byte(`{"order":{"id":"o-123","total":42.5,"paid":true,"items":[]},"kind":"order.created"}`)
+
+	tests := []struct {
+		name        string
+		mapping     *duckv1.EventMapping
+		message     *pubsub.Message
+		wantEventFn func() *cev2.Event
+		wantErr     bool
+	}{{
+		name:    "defaults",
+		mapping: &duckv1.EventMapping{},
+		message: &pubsub.Message{
+			ID:          "id",
+			Data:        []byte("test data"),
+			PublishTime: publishTime,
+		},
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.CloudPubSubEventSource("testproject", "testtopic"))
+			e.SetType(schemasv1.CloudPubSubMessagePublishedEventType)
+			e.SetData("application/octet-stream", []byte("test data"))
+			return &e
+		},
+	}, {
+		name: "attributes, properties and fields",
+		mapping: &duckv1.EventMapping{
+			ID:      &duckv1.ValueMapping{Field: "order.id"},
+			Type:    &duckv1.ValueMapping{Field: "kind"},
+			Source:  &duckv1.ValueMapping{Attribute: "origin"},
+			Subject: &duckv1.ValueMapping{Property: duckv1.MessageOrderingKey},
+			Time:    &duckv1.ValueMapping{Attribute: "timestamp"},
+			Extensions: map[string]duckv1.ValueMapping{
+				"total":     {Field: "order.total"},
+				"paid":      {Field: "order.paid"},
+				"feed":      {Value: "partner"},
+				"publish":   {Property: duckv1.MessagePublishTime},
+				"messageid": {Property: duckv1.MessageID},
+				"region":    {Attribute: "region", Default: ptr.String("global")},
+			},
+		},
+		message: &pubsub.Message{
+			ID:          "id",
+			Data:        orderData,
+			PublishTime: publishTime,
+			OrderingKey: "customer-1",
+			Attributes: map[string]string{
+				"origin":    "//partner.example.com/orders",
+				"timestamp": "2021-03-04T05:00:00.5Z",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("o-123")
+			e.SetType("order.created")
+			e.SetSource("//partner.example.com/orders")
+			e.SetSubject("customer-1")
+			e.SetTime(time.Date(2021, 3, 4, 5, 0, 0, 500000000, time.UTC))
+			e.SetExtension("total", "42.5")
+			e.SetExtension("paid", "true")
+			e.SetExtension("feed", "partner")
+			e.SetExtension("publish", "2021-03-04T05:06:07Z")
+			e.SetExtension("messageid", "id")
+			e.SetExtension("region", "global")
+			e.SetData(cev2.ApplicationJSON, orderData)
+			return &e
+		},
+	}, {
+		name: "explicit data content type",
+		mapping: &duckv1.EventMapping{
+			Type:            &duckv1.ValueMapping{Value: "com.example.order"},
+			DataContentType: "text/plain",
+		},
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte("test data"),
+		},
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(time.Time{})
+			e.SetSource(schemasv1.CloudPubSubEventSource("testproject", "testtopic"))
+			e.SetType("com.example.order")
+			e.SetData("text/plain", []byte("test data"))
+			return &e
+		},
+	}, {
+		name: "missing attribute",
+		mapping: &duckv1.EventMapping{
+			Type: &duckv1.ValueMapping{Attribute: "type"},
+		},
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte("test data"),
+		},
+		wantErr: true,
+	}, {
+		name: "missing field",
+		mapping: &duckv1.EventMapping{
+			Extensions: map[string]duckv1.ValueMapping{
+				"customer": {Field: "order.customer.id"},
+			},
+		},
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: orderData,
+		},
+		wantErr: true,
+	}, {
+		name: "field is not a scalar",
+		mapping: &duckv1.EventMapping{
+			Subject: &duckv1.ValueMapping{Field: "order.items"},
+		},
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: orderData,
+		},
+		wantErr: true,
+	}, {
+		name: "data is not JSON",
+		mapping: &duckv1.EventMapping{
+			Type: &duckv1.ValueMapping{Field: "kind"},
+		},
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte("test data"),
+		},
+		wantErr: true,
+	}, {
+		name: "invalid time",
+		mapping: &duckv1.EventMapping{
+			Time: &duckv1.ValueMapping{Attribute: "timestamp"},
+		},
+		message: &pubsub.Message{
+			ID:         "id",
+			Data:       []byte("test data"),
+			Attributes: map[string]string{"timestamp": "yesterday"},
+		},
+		wantErr: true,
+	}, {
+		name: "empty mapped id",
+		mapping: &duckv1.EventMapping{
+			ID: &duckv1.ValueMapping{Attribute: "id", Default: ptr.String("")},
+		},
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte("test data"),
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithProjectKey(context.Background(), "testproject")
+			ctx = WithTopicKey(ctx, "testtopic")
+			ctx = WithSubscriptionKey(ctx, "testsubscription")
+			ctx = WithEventMappingKey(ctx, test.mapping)
+
+			gotEvent, err := NewPubSubConverter().Convert(ctx, test.message, Mapping)
+			if err != nil {
+				if !test.wantErr {
+					t.Errorf("converters.convertMapping got error %v want error=%v", err, test.wantErr)
+				}
+			} else {
+				if test.wantErr {
+					t.Fatalf("converters.convertMapping got event %v, want error", gotEvent)
+				}
+				if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+					t.Errorf("converters.convertMapping got unexpeceted cloudevents.Event (-want +got) %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertMappingWithoutMapping(t *testing.T) {
+	ctx := WithProjectKey(context.Background(), "testproject")
+	ctx = WithTopicKey(ctx, "testtopic")
+	if _, err := NewPubSubConverter().Convert(ctx, &pubsub.Message{ID: "id"}, Mapping); err != ErrEventMappingKeyNotPresent {
+		t.Errorf("converters.convertMapping got error %v, want %v", err, ErrEventMappingKeyNotPresent)
+	}
+}
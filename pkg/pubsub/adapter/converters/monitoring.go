@@ -0,0 +1,119 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	// incidentStateExtension is the extension with the state of the incident, open or closed.
+	incidentStateExtension = "incidentstate"
+	// incidentPolicyNameExtension is the extension with the display name of the alerting policy.
+	incidentPolicyNameExtension = "policyname"
+	// incidentResourceTypeExtension is the extension with the type of the monitored resource.
+	incidentResourceTypeExtension = "resourcetype"
+	// incidentResourceLabelPrefix prefixes the extensions with the labels of the monitored
+	// resource, e.g. the project_id label becomes the resourceprojectid extension.
+	incidentResourceLabelPrefix = "resource"
+)
+
+// monitoringNotification is the payload of the Pub/Sub notification channels, see
+// https://cloud.google.com/monitoring/support/notification-options#pubsub.
+type monitoringNotification struct {
+	Incident *monitoringIncident `json:"incident"`
+}
+
+type monitoringIncident struct {
+	IncidentID       string `json:"incident_id"`
+	ScopingProjectID string `json:"scoping_project_id"`
+	State            string `json:"state"`
+	PolicyName       string `json:"policy_name"`
+	Resource         struct {
+		Type   string            `json:"type"`
+		Labels map[string]string `json:"labels"`
+	} `json:"resource"`
+}
+
+func convertCloudMonitoring(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	var n monitoringNotification
+	if err := json.Unmarshal(msg.Data, &n); err != nil {
+		return nil, fmt.Errorf("failed to parse the incident notification: %w", err)
+	}
+	incident := n.Incident
+	if incident == nil {
+		return nil, errors.New("received event did not have incident")
+	}
+	if incident.IncidentID == "" {
+		return nil, errors.New("received event did not have incident_id")
+	}
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+	switch incident.State {
+	case schemasv1.CloudMonitoringIncidentStateOpen:
+		event.SetType(schemasv1.CloudMonitoringIncidentOpenedEventType)
+	case schemasv1.CloudMonitoringIncidentStateClosed:
+		event.SetType(schemasv1.CloudMonitoringIncidentClosedEventType)
+	default:
+		return nil, fmt.Errorf("received event had unknown incident state %q", incident.State)
+	}
+	// The scoping project is missing from the notifications of the older schema versions, fall
+	// back to the project of the monitored resource.
+	project := incident.ScopingProjectID
+	if project == "" {
+		project = incident.Resource.Labels["project_id"]
+	}
+	event.SetSource(schemasv1.CloudMonitoringEventSource(project))
+	event.SetSubject(schemasv1.CloudMonitoringIncidentEventSubject(incident.IncidentID))
+
+	event.SetExtension(incidentStateExtension, incident.State)
+	if incident.PolicyName != "" {
+		event.SetExtension(incidentPolicyNameExtension, incident.PolicyName)
+	}
+	if incident.Resource.Type != "" {
+		event.SetExtension(incidentResourceTypeExtension, incident.Resource.Type)
+	}
+	// The labels become extensions, without the characters that aren't valid in extension names.
+	for k, v := range incident.Resource.Labels {
+		name := incidentResourceLabelPrefix + strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			if r >= 'A' && r <= 'Z' {
+				return r + 'a' - 'A'
+			}
+			return -1
+		}, k)
+		event.SetExtension(name, v)
+	}
+
+	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
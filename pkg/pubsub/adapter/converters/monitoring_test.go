@@ -0,0 +1,119 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+func TestConvertCloudMonitoring(t *testing.T) {
+	publishTime := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	opened := []byte(`{"incident":{"incident_id":"0.lxfiw61fsv5p","scoping_project_id":"my-project","url":"https://console.cloud.google.com/monitoring/alerting/incidents/0.lxfiw61fsv5p?project=my-project","started_at":1614834367,"ended_at":null,"state":"open","summary":"CPU utilization for my-instance is above the threshold of 0.8 with a value of 0.95.","resource":{"type":"gce_instance","labels":{"instance_id":"1234567890","project_id":"my-project","zone":"us-central1-a"}},"policy_name":"High CPU","condition_name":"VM Instance - CPU utilization"},"version":"1.2"}`)
+	closed := []byte(`{"incident":{"incident_id":"0.lxfiw61fsv5p","state":"closed","resource":{"type":"gce_instance","labels":{"project_id":"my-project"}},"policy_name":"High CPU"},"version":"1.1"}`)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		wantEventFn func() *cev2.Event
+		wantErr     error
+	}{{
+		name: "incident opened",
+		data: opened,
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.CloudMonitoringEventSource("my-project"))
+			e.SetSubject(schemasv1.CloudMonitoringIncidentEventSubject("0.lxfiw61fsv5p"))
+			e.SetType(schemasv1.CloudMonitoringIncidentOpenedEventType)
+			e.SetExtension("incidentstate", "open")
+			e.SetExtension("policyname", "High CPU")
+			e.SetExtension("resourcetype", "gce_instance")
+			e.SetExtension("resourceinstanceid", "1234567890")
+			e.SetExtension("resourceprojectid", "my-project")
+			e.SetExtension("resourcezone", "us-central1-a")
+			e.SetData(cev2.ApplicationJSON, opened)
+			return &e
+		},
+	}, {
+		name: "incident closed without scoping project",
+		data: closed,
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetTime(publishTime)
+			e.SetSource(schemasv1.CloudMonitoringEventSource("my-project"))
+			e.SetSubject(schemasv1.CloudMonitoringIncidentEventSubject("0.lxfiw61fsv5p"))
+			e.SetType(schemasv1.CloudMonitoringIncidentClosedEventType)
+			e.SetExtension("incidentstate", "closed")
+			e.SetExtension("policyname", "High CPU")
+			e.SetExtension("resourcetype", "gce_instance")
+			e.SetExtension("resourceprojectid", "my-project")
+			e.SetData(cev2.ApplicationJSON, closed)
+			return &e
+		},
+	}, {
+		name:    "unknown state",
+		data:    []byte(`{"incident":{"incident_id":"0.lxfiw61fsv5p","state":"acknowledged"}}`),
+		wantErr: errors.New(`received event had unknown incident state "acknowledged"`),
+	}, {
+		name:    "missing incident",
+		data:    []byte(`{"version":"1.2"}`),
+		wantErr: errors.New("received event did not have incident"),
+	}, {
+		name:    "missing incident id",
+		data:    []byte(`{"incident":{"state":"open"}}`),
+		wantErr: errors.New("received event did not have incident_id"),
+	}, {
+		name:    "data is not JSON",
+		data:    []byte("test data"),
+		wantErr: errors.New("failed to parse the incident notification: invalid character 'e' in literal true (expecting 'r')"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := &pubsub.Message{
+				ID:          "id",
+				Data:        test.data,
+				PublishTime: publishTime,
+			}
+
+			gotEvent, err := NewPubSubConverter().Convert(context.Background(), msg, CloudMonitoring)
+			if test.wantErr != nil {
+				if err == nil || err.Error() != test.wantErr.Error() {
+					t.Fatalf("converters.convertCloudMonitoring got error %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("converters.convertCloudMonitoring got unexpected error %v", err)
+			}
+			if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+				t.Errorf("converters.convertCloudMonitoring got unexpeceted cloudevents.Event (-want +got) %s", diff)
+			}
+		})
+	}
+}
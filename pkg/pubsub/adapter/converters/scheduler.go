@@ -19,29 +19,60 @@ package converters
 import (
 	"context"
 	"errors"
+	"regexp"
 
-	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
 	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 
 	"cloud.google.com/go/pubsub"
 	cev2 "github.com/cloudevents/sdk-go/v2"
 )
 
+// extensionNameRegex matches the valid names of CloudEvents extensions. Unlike
+// event.IsAlphaNumeric, it doesn't match attributes with upper-case letters such as jobName.
+var extensionNameRegex = regexp.MustCompile(`^[a-z0-9]+$`)
+
 func convertCloudScheduler(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
 	event := cev2.NewEvent(cev2.VersionV1)
 	event.SetID(msg.ID)
 	event.SetTime(msg.PublishTime)
 	event.SetType(schemasv1.CloudSchedulerJobExecutedEventType)
-	event.SetDataSchema(schemasv1.CloudSchedulerEventDataSchema)
 
-	jobName, ok := msg.Attributes[v1beta1.CloudSchedulerSourceJobName]
+	jobName, ok := msg.Attributes[v1.CloudSchedulerSourceJobName]
 	if !ok {
 		return nil, errors.New("received event did not have jobName")
 	}
 	event.SetSource(schemasv1.CloudSchedulerEventSource(jobName))
 
+	// The custom attributes of the job become extensions. Attributes that aren't valid
+	// extension names are dropped.
+	for k, v := range msg.Attributes {
+		if !extensionNameRegex.MatchString(k) || isContextAttribute(k) {
+			continue
+		}
+		event.SetExtension(k, v)
+	}
+
+	// The JSON data of a job is the data of the event. The other data is wrapped in the
+	// SchedulerJobData.
+	if msg.Attributes[v1.CloudSchedulerSourceDataContentType] == v1.SchedulerDataContentTypeJSON {
+		if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}
+	event.SetDataSchema(schemasv1.CloudSchedulerEventDataSchema)
 	if err := event.SetData(cev2.ApplicationJSON, &schemasv1.SchedulerJobData{CustomData: msg.Data}); err != nil {
 		return nil, err
 	}
 	return &event, nil
 }
+
+// isContextAttribute returns whether name is the name of a CloudEvents context attribute.
+func isContextAttribute(name string) bool {
+	switch name {
+	case "id", "source", "specversion", "type", "datacontenttype", "dataschema", "subject", "time", "data":
+		return true
+	}
+	return false
+}
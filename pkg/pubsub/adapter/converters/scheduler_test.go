@@ -49,6 +49,69 @@ func TestConvertCloudSchedulerSource(t *testing.T) {
 				"attribute2":    "value2",
 			},
 		},
+		wantEventFn: func() *cev2.Event {
+			e := schedulerCloudEvent("//cloudscheduler.googleapis.com/projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test")
+			e.SetExtension("attribute1", "value1")
+			e.SetExtension("attribute2", "value2")
+			return e
+		},
+	}, {
+		name: "only jobName attribute",
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte("test data"),
+			Attributes: map[string]string{
+				"jobName": "projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			return schedulerCloudEvent("//cloudscheduler.googleapis.com/projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test")
+		},
+	}, {
+		name: "attributes that aren't extension names are dropped",
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte("test data"),
+			Attributes: map[string]string{
+				"jobName":   "projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test",
+				"team-name": "billing",
+				"source":    "other",
+				"env":       "prod",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := schedulerCloudEvent("//cloudscheduler.googleapis.com/projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test")
+			e.SetExtension("env", "prod")
+			return e
+		},
+	}, {
+		name: "JSON data is the event data",
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte(`{"batch":"nightly"}`),
+			Attributes: map[string]string{
+				"jobName":         "projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test",
+				"dataContentType": "application/json",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := cev2.NewEvent(cev2.VersionV1)
+			e.SetID("id")
+			e.SetData(cev2.ApplicationJSON, []byte(`{"batch":"nightly"}`))
+			e.SetType(schemasv1.CloudSchedulerJobExecutedEventType)
+			e.SetSource("//cloudscheduler.googleapis.com/projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test")
+			return &e
+		},
+	}, {
+		name: "binary data is wrapped",
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte("test data"),
+			Attributes: map[string]string{
+				"jobName":         "projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test",
+				"dataContentType": "application/octet-stream",
+			},
+		},
 		wantEventFn: func() *cev2.Event {
 			return schedulerCloudEvent("//cloudscheduler.googleapis.com/projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test")
 		},
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/storage"
 	cev2 "github.com/cloudevents/sdk-go/v2"
 	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 )
@@ -40,7 +41,6 @@ func convertCloudStorage(ctx context.Context, msg *pubsub.Message) (*cev2.Event,
 	event := cev2.NewEvent(cev2.VersionV1)
 	event.SetID(msg.ID)
 	event.SetTime(msg.PublishTime)
-	event.SetDataSchema(schemasv1.CloudStorageEventDataSchema)
 
 	// TODO: figure out if we want to continue to add these as extensions.
 	if val, ok := msg.Attributes["bucketId"]; ok {
@@ -64,6 +64,20 @@ func convertCloudStorage(ctx context.Context, msg *pubsub.Message) (*cev2.Event,
 		return nil, errors.New("received event did not have eventType")
 	}
 
+	// The custom attributes of the notification become extensions. The attributes set by GCS
+	// aren't valid extension names.
+	for k, v := range msg.Attributes {
+		if !extensionNameRegex.MatchString(k) || isContextAttribute(k) {
+			continue
+		}
+		event.SetExtension(k, v)
+	}
+
+	// Notifications with no payload only carry the metadata of the object in their attributes.
+	if msg.Attributes["payloadFormat"] == storage.NoPayload {
+		return &event, nil
+	}
+	event.SetDataSchema(schemasv1.CloudStorageEventDataSchema)
 	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
 		return nil, err
 	}
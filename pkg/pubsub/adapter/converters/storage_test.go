@@ -38,9 +38,11 @@ var (
 func TestConvertCloudStorageSource(t *testing.T) {
 
 	tests := []struct {
-		name    string
-		message *pubsub.Message
-		wantErr bool
+		name           string
+		message        *pubsub.Message
+		wantErr        bool
+		wantNoData     bool
+		wantExtensions map[string]interface{}
 	}{{
 		name: "no attributes",
 		message: &pubsub.Message{
@@ -101,6 +103,37 @@ func TestConvertCloudStorageSource(t *testing.T) {
 				"objectId":  objectId,
 			},
 		},
+	}, {
+		name: "valid message with custom attributes",
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: storagePublishTime,
+			Data:        []byte("test data"),
+			Attributes: map[string]string{
+				"bucketId":           bucket,
+				"eventType":          eventType,
+				"objectId":           objectId,
+				"notificationConfig": "projects/_/buckets/my-bucket/notificationConfigs/1",
+				"team":               "billing",
+				"type":               "dropped",
+			},
+		},
+		wantExtensions: map[string]interface{}{
+			"team": "billing",
+		},
+	}, {
+		name: "valid message without payload",
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: storagePublishTime,
+			Attributes: map[string]string{
+				"bucketId":      bucket,
+				"eventType":     eventType,
+				"objectId":      objectId,
+				"payloadFormat": "NONE",
+			},
+		},
+		wantNoData: true,
 	}}
 
 	for _, test := range tests {
@@ -127,9 +160,21 @@ func TestConvertCloudStorageSource(t *testing.T) {
 				if want := schemasv1.CloudStorageEventSubject(objectId); gotEvent.Subject() != want {
 					t.Errorf("Subject %q != %q", gotEvent.Subject(), objectId)
 				}
-				if gotEvent.DataSchema() != schemasv1.CloudStorageEventDataSchema {
+				if test.wantNoData {
+					if gotEvent.DataSchema() != "" || gotEvent.Data() != nil {
+						t.Errorf("event without payload has DataSchema %q and data %q", gotEvent.DataSchema(), gotEvent.Data())
+					}
+				} else if gotEvent.DataSchema() != schemasv1.CloudStorageEventDataSchema {
 					t.Errorf("DataSchema %q != %q", gotEvent.DataSchema(), schemasv1.CloudStorageEventDataSchema)
 				}
+				for k, v := range test.wantExtensions {
+					if got := gotEvent.Extensions()[k]; got != v {
+						t.Errorf("Extension %q got=%v, want=%v", k, got, v)
+					}
+				}
+				if _, ok := gotEvent.Extensions()["type"]; ok {
+					t.Error("context attribute type became an extension")
+				}
 			}
 		})
 	}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 
 	"go.opencensus.io/stats/view"
 	"knative.dev/pkg/metrics"
@@ -65,6 +66,12 @@ type StatsReporter interface {
 var _ StatsReporter = (*reporter)(nil)
 var emptyContext = context.Background()
 
+var (
+	// The views are registered once, as the push receiver creates a reporter per resource.
+	registerOnce sync.Once
+	registerErr  error
+)
+
 // reporter holds cached metric objects to report metrics.
 type reporter struct {
 	name          string
@@ -107,6 +114,13 @@ func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Cont
 }
 
 func (r *reporter) register() error {
+	registerOnce.Do(func() {
+		registerErr = registerViews()
+	})
+	return registerErr
+}
+
+func registerViews() error {
 	tagKeys := []tag.Key{
 		namespaceKey,
 		eventSourceKey,
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package push
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PullSubscriptionPath returns the path of the push endpoint of a PullSubscription.
+// The format is /pullSubscriptionNamespace/pullSubscriptionName
+func PullSubscriptionPath(namespace, name string) string {
+	return fmt.Sprintf("/%s/%s", namespace, name)
+}
+
+// ConvertPathToNamespacedName converts the path of a push endpoint to the NamespacedName of its
+// PullSubscription.
+func ConvertPathToNamespacedName(path string) (types.NamespacedName, error) {
+	// Path should be in the form of "/<ns>/<pullsubscription>".
+	pieces := strings.Split(path, "/")
+	if len(pieces) != 3 || pieces[1] == "" || pieces[2] == "" {
+		return types.NamespacedName{}, fmt.Errorf("malformed request path; expect format '/<ns>/<pullsubscription>'")
+	}
+	return types.NamespacedName{
+		Namespace: pieces[1],
+		Name:      pieces[2],
+	}, nil
+}
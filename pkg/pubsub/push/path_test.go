@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package push
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPullSubscriptionPath(t *testing.T) {
+	if got, want := PullSubscriptionPath("ns", "name"), "/ns/name"; got != want {
+		t.Errorf("PullSubscriptionPath() = %q, want %q", got, want)
+	}
+}
+
+func TestConvertPathToNamespacedName(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    types.NamespacedName
+		wantErr bool
+	}{{
+		path: "/ns/name",
+		want: types.NamespacedName{Namespace: "ns", Name: "name"},
+	}, {
+		path:    "/ns",
+		wantErr: true,
+	}, {
+		path:    "/ns/name/extra",
+		wantErr: true,
+	}, {
+		path:    "/ns/",
+		wantErr: true,
+	}, {
+		path:    "",
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			got, err := ConvertPathToNamespacedName(test.path)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ConvertPathToNamespacedName() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("ConvertPathToNamespacedName() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package push implements the shared push receiver, which receives the messages that Pub/Sub
+// pushes to the PullSubscriptions in the push delivery mode and delivers them to their sinks.
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+	"google.golang.org/api/idtoken"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	"github.com/google/knative-gcp/pkg/apis/intevents"
+	v1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	listers "github.com/google/knative-gcp/pkg/client/listers/intevents/v1"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/utils/clients"
+)
+
+const (
+	// defaultResourceGroup is the resource group of the metrics, the same as the receive adapters'.
+	defaultResourceGroup = "pullsubscriptions.internal.events.cloud.google.com"
+
+	// DefaultMaxRequestBodyBytes is the default limit for request payload in bytes. Pub/Sub
+	// messages are at most 10MB, and their data is base64 encoded in the push requests.
+	DefaultMaxRequestBodyBytes = 15000000
+)
+
+// ValidateTokenFunc validates an OIDC token for the given audience and returns its payload.
+type ValidateTokenFunc func(ctx context.Context, token string, audience string) (*idtoken.Payload, error)
+
+// ReceiverArgs are the arguments needed to create a Receiver.
+type ReceiverArgs struct {
+	// Audience is the audience of the OIDC tokens of the push requests. All the requests are
+	// rejected if it is empty.
+	Audience string
+	// ServiceAccount is the email of the service account the OIDC tokens are issued for. All the
+	// requests are rejected if it is empty.
+	ServiceAccount string
+	// ValidateTokenFn validates the OIDC tokens. Defaults to idtoken.Validate.
+	ValidateTokenFn ValidateTokenFunc
+	// MaxRequestBodyBytes is the limit for request payload in bytes.
+	MaxRequestBodyBytes int64
+}
+
+// Receiver receives the messages pushed by the Pub/Sub subscriptions of the PullSubscriptions in
+// the push delivery mode, and delivers them to the sinks of the PullSubscriptions the same way the
+// receive adapters do.
+type Receiver struct {
+	lister    listers.PullSubscriptionLister
+	outbound  *nethttp.Client
+	converter converters.Converter
+	args      ReceiverArgs
+	logger    *zap.Logger
+}
+
+// pushRequest is the body of the requests of the Pub/Sub push subscriptions.
+// See https://cloud.google.com/pubsub/docs/push#receiving_messages.
+type pushRequest struct {
+	Message struct {
+		ID          string            `json:"messageId"`
+		Data        []byte            `json:"data"`
+		Attributes  map[string]string `json:"attributes"`
+		PublishTime time.Time         `json:"publishTime"`
+		OrderingKey string            `json:"orderingKey"`
+	} `json:"message"`
+	Subscription    string `json:"subscription"`
+	DeliveryAttempt *int   `json:"deliveryAttempt"`
+}
+
+// NewReceiver creates a new push receiver.
+func NewReceiver(ctx context.Context, lister listers.PullSubscriptionLister, outbound *nethttp.Client, converter converters.Converter, args ReceiverArgs) *Receiver {
+	if args.ValidateTokenFn == nil {
+		args.ValidateTokenFn = idtoken.Validate
+	}
+	if args.MaxRequestBodyBytes <= 0 {
+		args.MaxRequestBodyBytes = DefaultMaxRequestBodyBytes
+	}
+	return &Receiver{
+		lister:    lister,
+		outbound:  outbound,
+		converter: converter,
+		args:      args,
+		logger:    logging.FromContext(ctx),
+	}
+}
+
+// ServeHTTP implements net/http Handler interface method.
+// 1. Authenticates the OIDC token of the request.
+// 2. Parses the request URL to get the PullSubscription.
+// 3. Checks that the message was pushed by the subscription of the PullSubscription.
+// 4. Converts the message and delivers the event to the sink of the PullSubscription.
+// Pub/Sub acknowledges the message on a 2xx response, and retries it otherwise.
+func (r *Receiver) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
+	ctx := logging.WithLogger(request.Context(), r.logger)
+	if request.Method != nethttp.MethodPost {
+		response.WriteHeader(nethttp.StatusMethodNotAllowed)
+		return
+	}
+
+	if code, err := r.authenticate(ctx, request); err != nil {
+		logging.FromContext(ctx).Debug("Unauthenticated push request", zap.Error(err))
+		nethttp.Error(response, err.Error(), code)
+		return
+	}
+
+	key, err := ConvertPathToNamespacedName(request.URL.Path)
+	if err != nil {
+		logging.FromContext(ctx).Debug("Malformed request path", zap.String("path", request.URL.Path))
+		nethttp.Error(response, err.Error(), nethttp.StatusNotFound)
+		return
+	}
+	ctx = logging.With(ctx, zap.Stringer("pullsubscription", key))
+
+	ps, err := r.lister.PullSubscriptions(key.Namespace).Get(key.Name)
+	if apierrors.IsNotFound(err) || (err == nil && ps.Annotations[duck.DeliveryModeAnnotation] != duck.DeliveryModePush) {
+		nethttp.Error(response, "PullSubscription not found", nethttp.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get PullSubscription", zap.Error(err))
+		nethttp.Error(response, "Failed to get PullSubscription", nethttp.StatusInternalServerError)
+		return
+	}
+
+	if request.ContentLength > r.args.MaxRequestBodyBytes {
+		response.WriteHeader(nethttp.StatusRequestEntityTooLarge)
+		return
+	}
+	var pr pushRequest
+	if err := json.NewDecoder(nethttp.MaxBytesReader(nil, request.Body, r.args.MaxRequestBodyBytes)).Decode(&pr); err != nil {
+		nethttp.Error(response, fmt.Sprintf("Malformed push request: %v", err), nethttp.StatusBadRequest)
+		return
+	}
+
+	// Only the subscription of the PullSubscription can push to its endpoint.
+	if pr.Subscription != fmt.Sprintf("projects/%s/subscriptions/%s", ps.Status.ProjectID, ps.Status.SubscriptionID) {
+		logging.FromContext(ctx).Debug("Message pushed by another subscription", zap.String("subscription", pr.Subscription))
+		nethttp.Error(response, "Message pushed by another subscription", nethttp.StatusForbidden)
+		return
+	}
+
+	if ps.Status.SinkURI == nil {
+		// The message is retried until the PullSubscription has a sink.
+		nethttp.Error(response, "PullSubscription has no sink", nethttp.StatusServiceUnavailable)
+		return
+	}
+
+	a, err := r.newAdapter(ctx, ps)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create adapter", zap.Error(err))
+		nethttp.Error(response, "Failed to create adapter", nethttp.StatusInternalServerError)
+		return
+	}
+	msg := &pubsub.Message{
+		ID:              pr.Message.ID,
+		Data:            pr.Message.Data,
+		Attributes:      pr.Message.Attributes,
+		PublishTime:     pr.Message.PublishTime,
+		OrderingKey:     pr.Message.OrderingKey,
+		DeliveryAttempt: pr.DeliveryAttempt,
+	}
+	if !a.Deliver(ctx, ps.Status.SubscriptionID, msg) {
+		nethttp.Error(response, "Failed to deliver the message", nethttp.StatusBadGateway)
+		return
+	}
+	response.WriteHeader(nethttp.StatusNoContent)
+}
+
+// authenticate validates the OIDC token of the request, and returns the status code of the
+// response if it is invalid.
+func (r *Receiver) authenticate(ctx context.Context, request *nethttp.Request) (int, error) {
+	if r.args.Audience == "" || r.args.ServiceAccount == "" {
+		// Without an audience and a service account any Google-signed token would be accepted.
+		return nethttp.StatusServiceUnavailable, fmt.Errorf("push receiver is not configured")
+	}
+	token := strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == request.Header.Get("Authorization") {
+		return nethttp.StatusUnauthorized, fmt.Errorf("missing bearer token")
+	}
+	payload, err := r.args.ValidateTokenFn(ctx, token, r.args.Audience)
+	if err != nil {
+		return nethttp.StatusUnauthorized, fmt.Errorf("invalid token: %w", err)
+	}
+	if email, _ := payload.Claims["email"].(string); email != r.args.ServiceAccount {
+		return nethttp.StatusForbidden, fmt.Errorf("token issued for unexpected service account %q", email)
+	}
+	if verified, _ := payload.Claims["email_verified"].(bool); !verified {
+		return nethttp.StatusForbidden, fmt.Errorf("token email is not verified")
+	}
+	return 0, nil
+}
+
+// newAdapter creates an adapter for the PullSubscription, configured the same way as its receive
+// adapter would be.
+func (r *Receiver) newAdapter(ctx context.Context, ps *v1.PullSubscription) (*adapter.Adapter, error) {
+	resourceGroup := defaultResourceGroup
+	if rg, ok := ps.Annotations["metrics-resource-group"]; ok {
+		resourceGroup = rg
+	}
+	resourceName := ps.Name
+	if rn, ok := ps.Annotations["metrics-resource-name"]; ok {
+		resourceName = rn
+	}
+	reporter, err := adapter.NewStatsReporter(adapter.Name(resourceName), adapter.Namespace(ps.Namespace), adapter.ResourceGroup(resourceGroup))
+	if err != nil {
+		return nil, err
+	}
+
+	args := &adapter.AdapterArgs{
+		TopicID:       ps.Spec.Topic,
+		SinkURI:       ps.Status.SinkURI.String(),
+		ConverterType: converterType(ps),
+		EventMapping:  ps.Spec.EventMapping,
+		ImageFilter:   ps.Spec.ImageFilter,
+		BudgetFilter:  ps.Spec.BudgetFilter,
+	}
+	if ps.Status.TransformerURI != nil {
+		args.TransformerURI = ps.Status.TransformerURI.String()
+	}
+	if ps.Spec.CloudEventOverrides != nil {
+		args.Extensions = ps.Spec.CloudEventOverrides.Extensions
+	}
+	return adapter.NewAdapter(ctx,
+		clients.ProjectID(ps.Status.ProjectID),
+		adapter.Namespace(ps.Namespace),
+		adapter.Name(resourceName),
+		adapter.ResourceGroup(resourceGroup),
+		nil,
+		r.outbound,
+		r.converter,
+		reporter,
+		args), nil
+}
+
+// converterType returns the type of the converter of the PullSubscription, the same as the adapter
+// type of its receive adapter.
+func converterType(ps *v1.PullSubscription) converters.ConverterType {
+	// The EventMapping replaces the conversion of the adapter type.
+	if ps.Spec.EventMapping != nil {
+		return converters.Mapping
+	}
+	// If the PullSubscription has no Channel nor Source label, users created it manually.
+	_, isFromSource := ps.Labels[intevents.SourceLabelKey]
+	_, isFromChannel := ps.Labels[intevents.ChannelLabelKey]
+	if !isFromSource && !isFromChannel {
+		return converters.PubSubPull
+	}
+	return converters.ConverterType(ps.Spec.AdapterType)
+}
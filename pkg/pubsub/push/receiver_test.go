@@ -0,0 +1,291 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"google.golang.org/api/idtoken"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	_ "knative.dev/pkg/metrics/testing"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	"github.com/google/knative-gcp/pkg/apis/intevents"
+	v1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	reconcilertesting "github.com/google/knative-gcp/pkg/reconciler/testing"
+	reconcilertestingv1 "github.com/google/knative-gcp/pkg/reconciler/testing/v1"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	testNS             = "testnamespace"
+	testProject        = "test-project"
+	testTopic          = "test-topic"
+	testSubscriptionID = "test-subscription"
+	testAudience       = "https://push.example.com"
+	testServiceAccount = "push@test-project.iam.gserviceaccount.com"
+
+	validToken        = "valid-token"
+	otherAccountToken = "other-account-token"
+	unverifiedToken   = "unverified-token"
+)
+
+var testSubscription = fmt.Sprintf("projects/%s/subscriptions/%s", testProject, testSubscriptionID)
+
+func validateToken(_ context.Context, token string, audience string) (*idtoken.Payload, error) {
+	if audience != testAudience {
+		return nil, fmt.Errorf("unexpected audience %q", audience)
+	}
+	switch token {
+	case validToken:
+		return &idtoken.Payload{Claims: map[string]interface{}{"email": testServiceAccount, "email_verified": true}}, nil
+	case otherAccountToken:
+		return &idtoken.Payload{Claims: map[string]interface{}{"email": "other@test-project.iam.gserviceaccount.com", "email_verified": true}}, nil
+	case unverifiedToken:
+		return &idtoken.Payload{Claims: map[string]interface{}{"email": testServiceAccount, "email_verified": false}}, nil
+	default:
+		return nil, errors.New("invalid token")
+	}
+}
+
+func newPullSubscription(name string, annotations map[string]string, sink *apis.URL) *v1.PullSubscription {
+	return reconcilertestingv1.NewPullSubscription(name, testNS,
+		reconcilertestingv1.WithPullSubscriptionAnnotations(annotations),
+		reconcilertestingv1.WithPullSubscriptionLabels(map[string]string{intevents.SourceLabelKey: name}),
+		reconcilertestingv1.WithPullSubscriptionTopic(testTopic),
+		reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+		reconcilertestingv1.WithPullSubscriptionSubscriptionID(testSubscriptionID),
+		reconcilertestingv1.WithPullSubscriptionMarkSink(sink),
+		func(ps *v1.PullSubscription) {
+			ps.Spec.AdapterType = string(converters.CloudPubSub)
+		},
+	)
+}
+
+func pushBody(subscription string) string {
+	return fmt.Sprintf(`{"message":{"messageId":"1234","data":%q,"attributes":{"key":"value"},"publishTime":"2021-01-01T00:00:00Z","orderingKey":"order"},"subscription":%q}`,
+		base64.StdEncoding.EncodeToString([]byte(`{"hello":"world"}`)), subscription)
+}
+
+func TestReceiver(t *testing.T) {
+	var received []cev2.Event
+	sinkStatus := nethttp.StatusAccepted
+	sink := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		event, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("Sink received an invalid event: %v", err)
+		} else {
+			received = append(received, *event)
+		}
+		w.WriteHeader(sinkStatus)
+	}))
+	defer sink.Close()
+	sinkURI, _ := apis.ParseURL(sink.URL)
+
+	pushMode := map[string]string{duck.DeliveryModeAnnotation: duck.DeliveryModePush}
+	mapping := newPullSubscription("mapping", pushMode, sinkURI)
+	mapping.Spec.EventMapping = &duckv1.EventMapping{
+		Subject: &duckv1.ValueMapping{Property: duckv1.MessageOrderingKey},
+	}
+	listers := reconcilertesting.NewListers([]runtime.Object{
+		newPullSubscription("push", pushMode, sinkURI),
+		newPullSubscription("pull", nil, sinkURI),
+		newPullSubscription("no-sink", pushMode, nil),
+		mapping,
+	})
+	receiver := NewReceiver(context.Background(), listers.GetPullSubscriptionLister(), nethttp.DefaultClient, converters.NewPubSubConverter(), ReceiverArgs{
+		Audience:        testAudience,
+		ServiceAccount:  testServiceAccount,
+		ValidateTokenFn: validateToken,
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       string
+		sinkStatus int
+		wantCode   int
+		wantEvent  bool
+		// wantSubject is the subject of the event sent to the sink.
+		wantSubject string
+	}{{
+		name:      "delivered",
+		path:      "/testnamespace/push",
+		token:     validToken,
+		body:      pushBody(testSubscription),
+		wantCode:  nethttp.StatusNoContent,
+		wantEvent: true,
+	}, {
+		name:        "ordering key mapped",
+		path:        "/testnamespace/mapping",
+		token:       validToken,
+		body:        pushBody(testSubscription),
+		wantCode:    nethttp.StatusNoContent,
+		wantEvent:   true,
+		wantSubject: "order",
+	}, {
+		name:     "method not allowed",
+		method:   nethttp.MethodGet,
+		path:     "/testnamespace/push",
+		token:    validToken,
+		wantCode: nethttp.StatusMethodNotAllowed,
+	}, {
+		name:     "missing token",
+		path:     "/testnamespace/push",
+		body:     pushBody(testSubscription),
+		wantCode: nethttp.StatusUnauthorized,
+	}, {
+		name:     "invalid token",
+		path:     "/testnamespace/push",
+		token:    "invalid",
+		body:     pushBody(testSubscription),
+		wantCode: nethttp.StatusUnauthorized,
+	}, {
+		name:     "other service account",
+		path:     "/testnamespace/push",
+		token:    otherAccountToken,
+		body:     pushBody(testSubscription),
+		wantCode: nethttp.StatusForbidden,
+	}, {
+		name:     "unverified email",
+		path:     "/testnamespace/push",
+		token:    unverifiedToken,
+		body:     pushBody(testSubscription),
+		wantCode: nethttp.StatusForbidden,
+	}, {
+		name:     "malformed path",
+		path:     "/testnamespace",
+		token:    validToken,
+		body:     pushBody(testSubscription),
+		wantCode: nethttp.StatusNotFound,
+	}, {
+		name:     "pullsubscription not found",
+		path:     "/testnamespace/missing",
+		token:    validToken,
+		body:     pushBody(testSubscription),
+		wantCode: nethttp.StatusNotFound,
+	}, {
+		name:     "pull delivery mode",
+		path:     "/testnamespace/pull",
+		token:    validToken,
+		body:     pushBody(testSubscription),
+		wantCode: nethttp.StatusNotFound,
+	}, {
+		name:     "malformed body",
+		path:     "/testnamespace/push",
+		token:    validToken,
+		body:     "{",
+		wantCode: nethttp.StatusBadRequest,
+	}, {
+		name:     "other subscription",
+		path:     "/testnamespace/push",
+		token:    validToken,
+		body:     pushBody("projects/test-project/subscriptions/other"),
+		wantCode: nethttp.StatusForbidden,
+	}, {
+		name:     "no sink",
+		path:     "/testnamespace/no-sink",
+		token:    validToken,
+		body:     pushBody(testSubscription),
+		wantCode: nethttp.StatusServiceUnavailable,
+	}, {
+		name:       "sink failure",
+		path:       "/testnamespace/push",
+		token:      validToken,
+		body:       pushBody(testSubscription),
+		sinkStatus: nethttp.StatusInternalServerError,
+		wantCode:   nethttp.StatusBadGateway,
+		wantEvent:  true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			received = nil
+			sinkStatus = nethttp.StatusAccepted
+			if test.sinkStatus != 0 {
+				sinkStatus = test.sinkStatus
+			}
+			method := nethttp.MethodPost
+			if test.method != "" {
+				method = test.method
+			}
+			request := httptest.NewRequest(method, "http://push.example.com"+test.path, bytes.NewBufferString(test.body))
+			if test.token != "" {
+				request.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			recorder := httptest.NewRecorder()
+
+			receiver.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.wantCode {
+				t.Errorf("Unexpected status code, got %d, want %d: %s", recorder.Code, test.wantCode, recorder.Body.String())
+			}
+			if !test.wantEvent {
+				if len(received) != 0 {
+					t.Errorf("Unexpected events sent to the sink: %v", received)
+				}
+				return
+			}
+			if len(received) != 1 {
+				t.Fatalf("Unexpected number of events sent to the sink, got %d, want 1", len(received))
+			}
+			event := received[0]
+			if event.Type() != schemasv1.CloudPubSubMessagePublishedEventType {
+				t.Errorf("Unexpected event type %q", event.Type())
+			}
+			if want := schemasv1.CloudPubSubEventSource(testProject, testTopic); event.Source() != want {
+				t.Errorf("Unexpected event source %q, want %q", event.Source(), want)
+			}
+			if event.ID() != "1234" {
+				t.Errorf("Unexpected event ID %q", event.ID())
+			}
+			if event.Subject() != test.wantSubject {
+				t.Errorf("Unexpected event subject %q, want %q", event.Subject(), test.wantSubject)
+			}
+		})
+	}
+}
+
+func TestReceiverNotConfigured(t *testing.T) {
+	listers := reconcilertesting.NewListers(nil)
+	receiver := NewReceiver(context.Background(), listers.GetPullSubscriptionLister(), nethttp.DefaultClient, converters.NewPubSubConverter(), ReceiverArgs{
+		ValidateTokenFn: validateToken,
+	})
+	request := httptest.NewRequest(nethttp.MethodPost, "http://push.example.com/testnamespace/push", bytes.NewBufferString(pushBody(testSubscription)))
+	request.Header.Set("Authorization", "Bearer "+validToken)
+	recorder := httptest.NewRecorder()
+
+	receiver.ServeHTTP(recorder, request)
+
+	if recorder.Code != nethttp.StatusServiceUnavailable {
+		t.Errorf("Unexpected status code, got %d, want %d", recorder.Code, nethttp.StatusServiceUnavailable)
+	}
+}
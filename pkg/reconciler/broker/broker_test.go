@@ -170,6 +170,7 @@ func TestAllCases(t *testing.T) {
 			Object: NewBroker(brokerName, testNS,
 				WithBrokerClass(brokerv1beta1.BrokerClass),
 				WithBrokerUID(testUID),
+				WithBrokerStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
 				WithBrokerSetDefaults,
@@ -191,6 +192,48 @@ func TestAllCases(t *testing.T) {
 			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
 			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
 		},
+	}, {
+		Name: "Create broker with ordering key attribute, decoupling subscription is ordered",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerOrderingKeyAttribute("partitionkey"),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerSetDefaults),
+			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerStatusBrokerCell(resources.DefaultBrokerCellName),
+				WithBrokerOrderingKeyAttribute("partitionkey"),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionHasMessageOrdering("cre-bkr_testnamespace_test-broker_abc123", true),
+		},
 	}, {
 		Name: "Create broker with unready brokercell, broker is created",
 		Key:  testKey,
@@ -210,6 +253,7 @@ func TestAllCases(t *testing.T) {
 			Object: NewBroker(brokerName, testNS,
 				WithBrokerClass(brokerv1beta1.BrokerClass),
 				WithBrokerUID(testUID),
+				WithBrokerStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
 				WithBrokerBrokerCellUnknown("BrokerCellNotReady", "BrokerCell knative-testing/default is not ready"),
@@ -251,6 +295,7 @@ func TestAllCases(t *testing.T) {
 				Object: NewBroker(brokerName, testNS,
 					WithBrokerClass(brokerv1beta1.BrokerClass),
 					WithBrokerUID(testUID),
+					WithBrokerStatusBrokerCell(resources.DefaultBrokerCellName),
 					WithBrokerDeliverySpec(brokerDeliverySpec),
 					WithInitBrokerConditions,
 					WithBrokerBrokerCellFailed("BrokerCellCreationFailed", "Failed to create BrokerCell knative-testing/default"),
@@ -258,7 +303,7 @@ func TestAllCases(t *testing.T) {
 				),
 			},
 		},
-		WantCreates:             []runtime.Object{resources.CreateBrokerCell(nil) /*Currently brokercell doesn't require broker information*/},
+		WantCreates:             []runtime.Object{resources.CreateBrokerCell(resources.DefaultBrokerCellName)},
 		SkipNamespaceValidation: true, // The brokercell resource is created in a different namespace (system namespace) than the broker
 		WantEvents: []string{
 			brokerFinalizerUpdatedEvent,
@@ -284,6 +329,7 @@ func TestAllCases(t *testing.T) {
 				Object: NewBroker(brokerName, testNS,
 					WithBrokerClass(brokerv1beta1.BrokerClass),
 					WithBrokerUID(testUID),
+					WithBrokerStatusBrokerCell(resources.DefaultBrokerCellName),
 					WithBrokerDeliverySpec(brokerDeliverySpec),
 					WithBrokerReadyURI(brokerAddress),
 					WithBrokerBrokerCellUnknown("BrokerCellNotReady", "BrokerCell knative-testing/default is not ready"),
@@ -291,7 +337,7 @@ func TestAllCases(t *testing.T) {
 				),
 			},
 		},
-		WantCreates:             []runtime.Object{resources.CreateBrokerCell(nil) /*Currently brokercell doesn't require broker information*/},
+		WantCreates:             []runtime.Object{resources.CreateBrokerCell(resources.DefaultBrokerCellName)},
 		SkipNamespaceValidation: true, // The brokercell resource is created in a different namespace (system namespace) than the broker
 		WantEvents: []string{
 			brokerFinalizerUpdatedEvent,
@@ -314,6 +360,127 @@ func TestAllCases(t *testing.T) {
 			}),
 			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
 		},
+	}, {
+		Name: "Create broker assigned to a missing dedicated brokercell, the brokercell is not created",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerBrokerCell("dedicated"),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerSetDefaults,
+			),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+			{
+				Object: NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithBrokerUID(testUID),
+					WithBrokerBrokerCell("dedicated"),
+					WithBrokerStatusBrokerCell("dedicated"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithInitBrokerConditions,
+					WithBrokerBrokerCellFailed("BrokerCellNotFound", "BrokerCell knative-testing/dedicated does not exist"),
+					WithBrokerSetDefaults,
+				),
+			},
+		},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeWarning, "InternalError", `failed to reconcile broker: brokercell reconcile failed: brokercell knative-testing/dedicated does not exist`),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		WantErr: true,
+	}, {
+		Name: "Create broker assigned to a dedicated brokercell allowing its namespace",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerBrokerCell("dedicated"),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerSetDefaults,
+			),
+			NewBrokerCell("dedicated", systemNS,
+				WithBrokerCellAllowedNamespaces(testNS),
+				WithBrokerCellReady,
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+			{
+				Object: NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithBrokerUID(testUID),
+					WithBrokerBrokerCell("dedicated"),
+					WithBrokerStatusBrokerCell("dedicated"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerReadyURI(&apis.URL{
+						Scheme: "http",
+						Host:   fmt.Sprintf("%s.%s.svc.%s", brokercellresources.Name("dedicated", brokercellresources.IngressName), systemNS, network.GetClusterDomainName()),
+						Path:   ingress.BrokerPath(testNS, brokerName),
+					}),
+					WithBrokerSetDefaults,
+				),
+			},
+		},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Create broker assigned to a dedicated brokercell not allowing its namespace",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerBrokerCell("dedicated"),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerSetDefaults,
+			),
+			NewBrokerCell("dedicated", systemNS,
+				WithBrokerCellAllowedNamespaces("other-namespace"),
+				WithBrokerCellReady,
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+			{
+				Object: NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithBrokerUID(testUID),
+					WithBrokerBrokerCell("dedicated"),
+					WithBrokerStatusBrokerCell("dedicated"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithInitBrokerConditions,
+					WithBrokerBrokerCellFailed("BrokerCellNotAllowed", "BrokerCell knative-testing/dedicated does not allow namespace testnamespace"),
+					WithBrokerSetDefaults,
+				),
+			},
+		},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeWarning, "InternalError", `failed to reconcile broker: brokercell reconcile failed: brokercell knative-testing/dedicated does not allow namespace testnamespace`),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		WantErr: true,
 	}, {
 		Name: "Check topic config with correct data residency and label",
 		Key:  testKey,
@@ -331,6 +498,7 @@ func TestAllCases(t *testing.T) {
 			Object: NewBroker(brokerName, testNS,
 				WithBrokerClass(brokerv1beta1.BrokerClass),
 				WithBrokerUID(testUID),
+				WithBrokerStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
 				WithBrokerSetDefaults,
@@ -376,6 +544,7 @@ func TestAllCases(t *testing.T) {
 			Object: NewBroker(brokerName, testNS,
 				WithBrokerClass(brokerv1beta1.BrokerClass),
 				WithBrokerUID(testUID),
+				WithBrokerStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
 				WithBrokerSetDefaults,
@@ -417,6 +586,7 @@ func TestAllCases(t *testing.T) {
 			Object: NewBroker(brokerName, testNS,
 				WithBrokerClass(brokerv1beta1.BrokerClass),
 				WithBrokerUID(testUID),
+				WithBrokerStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
 				WithBrokerSetDefaults,
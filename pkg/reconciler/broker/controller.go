@@ -33,6 +33,7 @@ import (
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
+	"knative.dev/pkg/system"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
@@ -115,17 +116,17 @@ func newController(ctx context.Context, cmw configmap.Watcher, brds *brokerdeliv
 
 	bcInformer.Informer().AddEventHandler(controller.HandleAll(
 		func(obj interface{}) {
-			if _, ok := obj.(*inteventsv1alpha1.BrokerCell); ok {
-				// TODO(#866) Only select brokers that point to this brokercell by label selector once the
-				// webhook assigns the brokercell label, i.e.,
-				// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
+			if bc, ok := obj.(*inteventsv1alpha1.BrokerCell); ok && bc.Namespace == system.Namespace() {
 				brokers, err := brokerInformer.Lister().List(labels.Everything())
 				if err != nil {
 					r.Logger.Error("Failed to list brokers", zap.Error(err))
 					return
 				}
+				// Only enqueue the brokers assigned to this brokercell.
 				for _, broker := range brokers {
-					impl.Enqueue(broker)
+					if inteventsv1alpha1.BrokerCellNameOf(broker) == bc.Name {
+						impl.Enqueue(broker)
+					}
 				}
 			}
 		},
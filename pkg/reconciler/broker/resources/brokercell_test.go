@@ -24,5 +24,5 @@ import (
 
 // This is already tested in broker_test.go, this test is just to make coverage tool happy.
 func TestBrokerCellCreation(t *testing.T) {
-	CreateBrokerCell(nil)
+	CreateBrokerCell(DefaultBrokerCellName)
 }
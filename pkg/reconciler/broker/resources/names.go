@@ -60,3 +60,11 @@ func GenerateRetryTopicName(t *brokerv1beta1.Trigger) string {
 func GenerateRetrySubscriptionName(t *brokerv1beta1.Trigger) string {
 	return naming.TruncatedPubsubResourceName("cre-tgr", t.Namespace, t.Name, t.UID)
 }
+
+// GenerateReplaySubscriptionName generates a deterministic name for the
+// subscription of a Trigger's replay to its Broker's decoupling topic. If the
+// subscription name would be longer than allowed by PubSub, the Trigger name is
+// truncated to fit.
+func GenerateReplaySubscriptionName(t *brokerv1beta1.Trigger) string {
+	return naming.TruncatedPubsubResourceName("cre-tgr-rpl", t.Namespace, t.Name, t.UID)
+}
@@ -18,22 +18,32 @@ package brokercell
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 
 	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/rickb777/date/period"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/apis/eventing"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/config"
-	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
 	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/celltenant"
 	channelresources "github.com/google/knative-gcp/pkg/reconciler/messaging/channel/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/utils"
 	"github.com/google/knative-gcp/pkg/reconciler/utils/volume"
@@ -43,38 +53,106 @@ const (
 	configFailed = "BrokerTargetsConfigFailed"
 )
 
-func (r *Reconciler) reconcileConfig(ctx context.Context, bc *intv1alpha1.BrokerCell) error {
-	// Start with a fresh config and add into it. This approach is straightforward and reliable,
-	// however not efficient if there are too many triggers/subscriptions. If performance becomes
-	// an issue, we can consider maintaining 2 queues for updated brokers and triggers, and only
-	// update the config for updated triggers/subscriptions.
-	targets := memory.NewEmptyTargets()
-
-	err := r.addBrokersAndTriggersToTargets(ctx, bc, targets)
-	if err != nil {
-		return fmt.Errorf("unable to add Broker and Triggers to targets: %w", err)
-	}
-
-	err = r.addChannelsToTargets(ctx, bc, targets)
-	if err != nil {
-		return fmt.Errorf("unable to add Channels to targets: %w", err)
+// reconcileConfig reconciles the targets config of the BrokerCell, and returns its targets.
+func (r *Reconciler) reconcileConfig(ctx context.Context, bc *intv1alpha1.BrokerCell) (config.ReadonlyTargets, error) {
+	// The targets config is kept in memory between reconciliations. It is only built from scratch
+	// the first time, and whenever the changes since the last reconciliation can't be tracked.
+	// Otherwise only the Brokers and Channels that changed, or whose Triggers changed, are rebuilt.
+	start := time.Now()
+	targets, dirty, rebuild := r.targetsCache.take(bc)
+	if err := r.updateTargets(ctx, bc, targets, dirty, rebuild); err != nil {
+		r.targetsCache.markStale(types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name})
+		return nil, err
 	}
 
 	if err := r.updateTargetsConfig(ctx, bc, targets); err != nil {
 		logging.FromContext(ctx).Error("Failed to update broker targets configmap", zap.Error(err))
 		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to update configmap: %v", err)
-		return err
+		return nil, err
 	}
 	bc.Status.MarkTargetsConfigReady()
+	r.reportTargetsConfigLatency(ctx, time.Since(start), rebuild)
+	return targets, nil
+}
+
+// reportTargetsConfigLatency records how long it took to rebuild and write the targets config.
+func (r *Reconciler) reportTargetsConfigLatency(ctx context.Context, d time.Duration, rebuild bool) {
+	if r.targetsConfigReporter == nil {
+		return
+	}
+	kind := metrics.IncrementalRebuild
+	if rebuild {
+		kind = metrics.FullRebuild
+	}
+	if err := r.targetsConfigReporter.ReportLatency(ctx, d, kind); err != nil {
+		logging.FromContext(ctx).Error("Failed to report the targets config latency", zap.Error(err))
+	}
+}
+
+// updateTargets brings the targets up to date, either by rebuilding them from scratch, or by
+// rebuilding the dirty CellTenants.
+func (r *Reconciler) updateTargets(ctx context.Context, bc *intv1alpha1.BrokerCell, targets config.Targets, dirty []cellTenantKey, rebuild bool) error {
+	if rebuild {
+		if err := r.addBrokersAndTriggersToTargets(ctx, bc, targets); err != nil {
+			return fmt.Errorf("unable to add Broker and Triggers to targets: %w", err)
+		}
+		if err := r.addChannelsToTargets(ctx, bc, targets); err != nil {
+			return fmt.Errorf("unable to add Channels to targets: %w", err)
+		}
+		return nil
+	}
+	for _, key := range dirty {
+		if err := r.rebuildCellTenant(ctx, bc, key, targets); err != nil {
+			return fmt.Errorf("unable to rebuild %v: %w", key.NamespacedName, err)
+		}
+	}
 	return nil
 }
 
-// addBrokersAndTriggersToTargets adds all Brokers that are associated with the `bc` BrokerCell to
+// rebuildCellTenant rebuilds the entry of a single Broker or Channel in the targets, or deletes it
+// if the Broker or Channel no longer belongs to the targets.
+func (r *Reconciler) rebuildCellTenant(ctx context.Context, bc *intv1alpha1.BrokerCell, key cellTenantKey, targets config.Targets) error {
+	switch key.cellTenantType {
+	case config.CellTenantType_BROKER:
+		b, err := r.brokerLister.Brokers(key.Namespace).Get(key.Name)
+		if apierrs.IsNotFound(err) || (err == nil && (!utils.BrokerClassFilter(b) || !isServed(bc, b))) {
+			deleteCellTenant(targets, config.KeyFromBroker(&brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}))
+			return nil
+		}
+		if err != nil {
+			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to get broker %v: %v", key.NamespacedName, err)
+			return err
+		}
+		triggers, err := r.triggerLister.Triggers(b.Namespace).List(labels.SelectorFromSet(map[string]string{eventing.BrokerLabelKey: b.Name}))
+		if err != nil {
+			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list triggers for broker %v: %v", b.Name, err)
+			return err
+		}
+		r.addBrokerAndTriggersToConfig(ctx, b, triggers, targets)
+	case config.CellTenantType_CHANNEL:
+		c, err := r.channelLister.Channels(key.Namespace).Get(key.Name)
+		if apierrs.IsNotFound(err) || (err == nil && (c.Status.Address == nil || !isServed(bc, c))) {
+			deleteCellTenant(targets, config.KeyFromChannel(&v1beta1.Channel{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}))
+			return nil
+		}
+		if err != nil {
+			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to get channel %v: %v", key.NamespacedName, err)
+			return err
+		}
+		addChannelToConfig(ctx, c, targets)
+	}
+	return nil
+}
+
+func deleteCellTenant(targets config.Targets, key *config.CellTenantKey) {
+	targets.MutateCellTenant(key, func(m config.CellTenantMutation) {
+		m.Delete()
+	})
+}
+
+// addBrokersAndTriggersToTargets adds all Brokers that are assigned to the `bc` BrokerCell to
 // `targets`, along with all Triggers that target those Brokers.
 func (r *Reconciler) addBrokersAndTriggersToTargets(ctx context.Context, bc *intv1alpha1.BrokerCell, targets config.Targets) error {
-	// TODO(#866) Only select brokers that point to this brokercell by label selector once the
-	// webhook assigns the brokercell label, i.e.,
-	// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
 	brokers, err := r.brokerLister.List(labels.Everything())
 	if err != nil {
 		logging.FromContext(ctx).Error("Failed to list brokers", zap.Error(err))
@@ -82,7 +160,7 @@ func (r *Reconciler) addBrokersAndTriggersToTargets(ctx context.Context, bc *int
 		return err
 	}
 	for _, broker := range brokers {
-		if !utils.BrokerClassFilter(broker) {
+		if !utils.BrokerClassFilter(broker) || !isServed(bc, broker) {
 			continue
 		}
 		// Filter by `eventing.knative.dev/broker: <name>` here
@@ -94,13 +172,13 @@ func (r *Reconciler) addBrokersAndTriggersToTargets(ctx context.Context, bc *int
 			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list triggers for broker %v: %v", broker.Name, err)
 			return err
 		}
-		addBrokerAndTriggersToConfig(ctx, broker, triggers, targets)
+		r.addBrokerAndTriggersToConfig(ctx, broker, triggers, targets)
 	}
 	return nil
 }
 
 // addBrokerAndTriggersToConfig reconstructs the data entry for the given broker and adds it to targets-config.
-func addBrokerAndTriggersToConfig(_ context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger, brokerTargets config.Targets) {
+func (r *Reconciler) addBrokerAndTriggersToConfig(ctx context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger, brokerTargets config.Targets) {
 	// TODO Maybe get rid of GCPCellAddressableMutation and add Delete() and Upsert(broker) methods to TargetsConfig. Now we always
 	//  delete or update the entire broker entry and we don't need partial updates per trigger.
 	// The code can be simplified to r.targetsConfig.Upsert(brokerConfigEntry)
@@ -122,6 +200,15 @@ func addBrokerAndTriggersToConfig(_ context.Context, b *brokerv1beta1.Broker, tr
 			Subscription: brokerresources.GenerateDecouplingSubscriptionName(b),
 			State:        brokerQueueState,
 		})
+		m.SetOrderingKeyAttribute(b.GetOrderingKeyAttribute())
+		policy, err := b.GetIngressPolicy()
+		if err != nil {
+			// The webhook validates the ingress policy, so this should not happen. Fail closed
+			// with a policy that has no issuers, so that no event is accepted.
+			logging.FromContext(ctx).Error("Failed to parse Broker ingress policy", zap.String("broker", b.Name), zap.Error(err))
+			policy = &brokerv1beta1.IngressPolicy{}
+		}
+		m.SetIngressPolicy(convertIngressPolicy(policy))
 		if b.Status.IsReady() {
 			m.SetState(config.State_READY)
 		} else {
@@ -147,6 +234,55 @@ func addBrokerAndTriggersToConfig(_ context.Context, b *brokerv1beta1.Broker, tr
 				if t.Spec.Filter != nil && t.Spec.Filter.Attributes != nil {
 					target.FilterAttributes = t.Spec.Filter.Attributes
 				}
+				filters, err := t.GetFilters()
+				if err != nil {
+					// The webhook validates the filters, so this should not happen. Leave the
+					// Trigger out of the config rather than delivering events it didn't ask for.
+					logging.FromContext(ctx).Error("Failed to parse Trigger filters", zap.String("trigger", t.Name), zap.Error(err))
+					continue
+				}
+				target.Filters = convertFilters(filters)
+				transform, err := convertTransform(t)
+				if err != nil {
+					// The webhook validates the transform, so this should not happen. Leave the
+					// Trigger out of the config rather than delivering untransformed events.
+					logging.FromContext(ctx).Error("Failed to convert Trigger transform", zap.String("trigger", t.Name), zap.Error(err))
+					continue
+				}
+				target.Transform = transform
+				batching, err := convertBatching(t)
+				if err != nil {
+					// The webhook validates the batching, so this should not happen. Events can
+					// still be delivered one at a time.
+					logging.FromContext(ctx).Error("Failed to convert Trigger batching", zap.String("trigger", t.Name), zap.Error(err))
+				}
+				target.Batching = batching
+				rateLimit, err := t.GetRateLimit()
+				if err != nil {
+					// The webhook validates the rate limit, so this should not happen. Deliver the
+					// events unthrottled rather than not at all.
+					logging.FromContext(ctx).Error("Failed to parse Trigger rate limit", zap.String("trigger", t.Name), zap.Error(err))
+				}
+				target.RateLimit = convertRateLimit(rateLimit)
+				deliverySpec, err := r.resolveDeliverySpec(ctx, t, b)
+				if err != nil {
+					// Keep the Trigger in the config without its dead letter sink, so that failed
+					// events are retried indefinitely. The URI resolver tracks the dead letter sink
+					// and triggers another reconciliation once it becomes addressable.
+					logging.FromContext(ctx).Error("Failed to resolve Trigger dead letter sink", zap.String("trigger", t.Name), zap.Error(err))
+				}
+				target.DeliverySpec = deliverySpec
+				// Only hand the replay subscription to the data plane once it has been seeked,
+				// otherwise the Trigger would receive the events published since its creation.
+				if t.Status.IsReplayReady() {
+					target.ReplayQueue = &config.Queue{
+						Topic:        brokerresources.GenerateDecouplingTopicName(b),
+						Subscription: brokerresources.GenerateReplaySubscriptionName(t),
+					}
+					if end, ok := t.Status.GetReplayEndTime(); ok {
+						target.ReplayEndTime = timestamppb.New(end)
+					}
+				}
 				// TODO(#939) May need to use "data plane readiness" for trigger in stead of the
 				//  overall status, see https://github.com/google/knative-gcp/issues/939#issuecomment-644337937
 				if t.Status.IsReady() {
@@ -159,10 +295,166 @@ func addBrokerAndTriggersToConfig(_ context.Context, b *brokerv1beta1.Broker, tr
 		}
 	})
 }
+
+// resolveDeliverySpec returns the delivery spec the data plane uses to retry and dead letter the
+// Trigger's events, or nil if it doesn't have one. Triggers with a dead letter topic (pubsub://
+// URI) don't get a delivery spec, as they are retried and dead lettered by the policies of the
+// Trigger's retry subscription, which rely on Pub/Sub counting the redeliveries. Events of
+// Triggers without a dead letter sink are retried until they expire, with the backoff of the
+// delivery spec.
+func (r *Reconciler) resolveDeliverySpec(ctx context.Context, t *brokerv1beta1.Trigger, b *brokerv1beta1.Broker) (*config.DeliverySpec, error) {
+	spec := celltenant.TriggerDeliverySpec(t, b.Spec.Delivery)
+	if spec == nil {
+		return nil, nil
+	}
+	deliverySpec := &config.DeliverySpec{}
+	if spec.BackoffPolicy != nil && *spec.BackoffPolicy == eventingduckv1beta1.BackoffPolicyLinear {
+		deliverySpec.BackoffPolicy = config.BackoffPolicy_LINEAR
+	}
+	if spec.BackoffDelay != nil {
+		p, err := period.Parse(*spec.BackoffDelay)
+		if err != nil {
+			// The webhook validates the backoff delay, so this should not happen. Retry without
+			// delay rather than not at all.
+			logging.FromContext(ctx).Error("Unable to parse DeliverySpec.BackoffDelay",
+				zap.Error(err), zap.Stringp("backoffDelay", spec.BackoffDelay))
+		} else {
+			d, _ := p.Duration()
+			deliverySpec.BackoffDelay = durationpb.New(d)
+		}
+	}
+	if spec.DeadLetterSink == nil {
+		return deliverySpec, nil
+	}
+
+	// Work on a copy as the Trigger comes from the informer cache.
+	dls := *spec.DeadLetterSink
+	if dls.URI != nil && dls.URI.Scheme == "pubsub" {
+		return nil, nil
+	}
+	if dls.Ref != nil && dls.Ref.Namespace == "" {
+		ref := *dls.Ref
+		ref.Namespace = t.Namespace
+		dls.Ref = &ref
+	}
+	uri, err := r.uriResolver.URIFromDestinationV1(ctx, dls, t)
+	if err != nil {
+		return deliverySpec, err
+	}
+	deliverySpec.DeadLetterAddress = uri.String()
+	if spec.Retry != nil {
+		deliverySpec.Retry = *spec.Retry
+	}
+	return deliverySpec, nil
+}
+
+// convertFilters converts the Trigger's SubscriptionsAPIFilters to their targets config
+// representation.
+func convertFilters(filters []brokerv1beta1.SubscriptionsAPIFilter) []*config.Filter {
+	if len(filters) == 0 {
+		return nil
+	}
+	converted := make([]*config.Filter, 0, len(filters))
+	for i := range filters {
+		converted = append(converted, convertFilter(&filters[i]))
+	}
+	return converted
+}
+
+func convertFilter(f *brokerv1beta1.SubscriptionsAPIFilter) *config.Filter {
+	if f == nil {
+		return nil
+	}
+	return &config.Filter{
+		Exact:  f.Exact,
+		Prefix: f.Prefix,
+		Suffix: f.Suffix,
+		All:    convertFilters(f.All),
+		Any:    convertFilters(f.Any),
+		Not:    convertFilter(f.Not),
+		Cesql:  f.SQL,
+	}
+}
+
+// convertTransform converts the Trigger's EventTransform to its targets config representation.
+func convertTransform(t *brokerv1beta1.Trigger) (*config.Transform, error) {
+	transform, err := t.GetTransform()
+	if err != nil || transform == nil {
+		return nil, err
+	}
+	converted := &config.Transform{
+		SetAttributes:    transform.SetAttributes,
+		RemoveAttributes: transform.RemoveAttributes,
+	}
+	if len(transform.DataPatch) > 0 {
+		patch, err := json.Marshal(transform.DataPatch)
+		if err != nil {
+			return nil, err
+		}
+		converted.DataPatch = string(patch)
+	}
+	return converted, nil
+}
+
+// convertBatching converts the Trigger's EventBatching to its targets config representation.
+func convertBatching(t *brokerv1beta1.Trigger) (*config.Batching, error) {
+	batching, err := t.GetBatching()
+	if err != nil || batching == nil {
+		return nil, err
+	}
+	converted := &config.Batching{
+		MaxCount: batching.MaxCount,
+		MaxBytes: batching.MaxBytes,
+	}
+	if batching.Linger != "" {
+		p, err := period.Parse(batching.Linger)
+		if err != nil {
+			return nil, err
+		}
+		d, _ := p.Duration()
+		converted.Linger = durationpb.New(d)
+	}
+	return converted, nil
+}
+
+// convertRateLimit converts the Trigger's EventRateLimit to its targets config representation.
+func convertRateLimit(rateLimit *brokerv1beta1.EventRateLimit) *config.RateLimit {
+	if rateLimit == nil {
+		return nil
+	}
+	return &config.RateLimit{
+		RequestsPerSecond: rateLimit.RequestsPerSecond,
+		Burst:             rateLimit.Burst,
+		MaxInFlight:       rateLimit.MaxInFlight,
+	}
+}
+
+// convertIngressPolicy converts the Broker's IngressPolicy to its targets config representation.
+func convertIngressPolicy(policy *brokerv1beta1.IngressPolicy) *config.IngressPolicy {
+	if policy == nil {
+		return nil
+	}
+	converted := &config.IngressPolicy{}
+	for _, issuer := range policy.Issuers {
+		converted.Issuers = append(converted.Issuers, &config.IngressIssuer{
+			Issuer:     issuer.Issuer,
+			JwksUrl:    issuer.JWKSURL,
+			Audiences:  issuer.Audiences,
+			Kubernetes: issuer.Kubernetes,
+		})
+	}
+	for _, rule := range policy.Rules {
+		converted.Rules = append(converted.Rules, &config.IngressRule{
+			Issuer:   rule.Issuer,
+			Subjects: rule.Subjects,
+			Types:    rule.Types,
+			Sources:  rule.Sources,
+		})
+	}
+	return converted
+}
+
 func (r *Reconciler) addChannelsToTargets(ctx context.Context, bc *intv1alpha1.BrokerCell, targets config.Targets) error {
-	// TODO(#866) Only select Channels that point to this brokercell by label selector once the
-	// webhook assigns the brokercell label, i.e.,
-	// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
 	channels, err := r.channelLister.List(labels.Everything())
 	if err != nil {
 		logging.FromContext(ctx).Error("Failed to list Channels", zap.Error(err))
@@ -170,7 +462,9 @@ func (r *Reconciler) addChannelsToTargets(ctx context.Context, bc *intv1alpha1.B
 		return err
 	}
 	for _, channel := range channels {
-		addChannelToConfig(ctx, channel, targets)
+		if isServed(bc, channel) {
+			addChannelToConfig(ctx, channel, targets)
+		}
 	}
 	return nil
 }
@@ -242,20 +536,56 @@ func addChannelToConfig(_ context.Context, c *v1beta1.Channel, targets config.Ta
 
 //TODO all this stuff should be in a configmap variant of the config object
 func (r *Reconciler) updateTargetsConfig(ctx context.Context, bc *intv1alpha1.BrokerCell, brokerTargets config.Targets) error {
-	desired, err := resources.MakeTargetsConfig(bc, brokerTargets)
-	if err != nil {
-		return fmt.Errorf("error creating targets config: %w", err)
+	if logger := logging.FromContext(ctx); logger.Core().Enabled(zap.DebugLevel) {
+		logger.Debug("Current targets config", zap.Any("targetsConfig", brokerTargets.DebugString()))
 	}
 
-	logging.FromContext(ctx).Debug("Current targets config", zap.Any("targetsConfig", brokerTargets.DebugString()))
-
 	handlerFuncs := cache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj interface{}) { r.refreshPodVolume(ctx, bc) },
 		UpdateFunc: func(oldObj, newObj interface{}) { r.refreshPodVolume(ctx, bc) },
 		DeleteFunc: nil,
 	}
-	_, err = r.cmRec.ReconcileConfigMap(ctx, bc, desired, resources.TargetsConfigMapEqual, handlerFuncs)
-	return err
+	shards := resources.SplitTargets(bc, brokerTargets)
+	for i, shard := range shards {
+		data, err := shard.Bytes()
+		if err != nil {
+			return fmt.Errorf("error serializing targets config: %w", err)
+		}
+		// Only write the shards whose serialized config changed.
+		if current, err := r.configMapLister.ConfigMaps(bc.Namespace).Get(resources.TargetsConfigMapName(bc.Name, i)); err == nil && resources.TargetsConfigMapHasData(current, data) {
+			continue
+		}
+		desired, err := resources.MakeTargetsConfig(bc, i, shard)
+		if err != nil {
+			return fmt.Errorf("error creating targets config: %w", err)
+		}
+		if _, err := r.cmRec.ReconcileConfigMap(ctx, bc, desired, resources.TargetsConfigMapEqual, handlerFuncs); err != nil {
+			return err
+		}
+	}
+	return r.deleteStaleTargetsConfigs(ctx, bc, len(shards))
+}
+
+// deleteStaleTargetsConfigs deletes the ConfigMaps of the shards of the targets config that are
+// left over from a larger number of shards.
+func (r *Reconciler) deleteStaleTargetsConfigs(ctx context.Context, bc *intv1alpha1.BrokerCell, shards int) error {
+	cms, err := r.configMapLister.ConfigMaps(bc.Namespace).List(labels.SelectorFromSet(resources.TargetsConfigLabels(bc.Name)))
+	if err != nil {
+		return err
+	}
+	current := make(map[string]bool, shards)
+	for i := 0; i < shards; i++ {
+		current[resources.TargetsConfigMapName(bc.Name, i)] = true
+	}
+	for _, cm := range cms {
+		if current[cm.Name] || !metav1.IsControlledBy(cm, bc) {
+			continue
+		}
+		if err := r.KubeClientSet.CoreV1().ConfigMaps(cm.Namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to delete stale targets config %s: %w", cm.Name, err)
+		}
+	}
+	return nil
 }
 
 func (r *Reconciler) refreshPodVolume(ctx context.Context, bc *intv1alpha1.BrokerCell) {
@@ -30,10 +30,13 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	hpav2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"knative.dev/pkg/network"
+	"knative.dev/pkg/resolver"
 
 	pkgreconciler "knative.dev/pkg/reconciler"
 
@@ -41,6 +44,7 @@ import (
 	bcreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	reconcilerutils "github.com/google/knative-gcp/pkg/reconciler/utils"
@@ -100,6 +104,8 @@ func NewReconciler(base *reconciler.Base, ls listers) (*Reconciler, error) {
 		svcRec:        svcRec,
 		deploymentRec: deploymentRec,
 		cmRec:         cmRec,
+		targetsCache:  newTargetsCache(),
+		discoveryFn:   discovery.ServerSupportsVersion,
 	}
 	return r, nil
 }
@@ -114,6 +120,22 @@ type Reconciler struct {
 	deploymentRec *reconcilerutils.DeploymentReconciler
 	cmRec         *reconcilerutils.ConfigMapReconciler
 
+	// targetsCache keeps the targets config of the BrokerCells between reconciliations.
+	targetsCache *targetsCache
+	// targetsConfigReporter reports how long it takes to rebuild and write the targets config. If
+	// nil, it isn't reported.
+	targetsConfigReporter *metrics.TargetsConfigReporter
+
+	// uriResolver resolves the addressable dead letter sinks of Triggers.
+	uriResolver *resolver.URIResolver
+
+	// projectID is the project of the subscriptions the fanout and retry are scaled on by their
+	// ScaledObjects. It is looked up when empty.
+	projectID string
+
+	// discoveryFn is the function used to discover whether KEDA is installed or not. Needed for UTs purposes.
+	discoveryFn discoverFunc
+
 	env envConfig
 }
 
@@ -138,9 +160,11 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, bc *intv1alpha1.BrokerCe
 
 	// Reconcile broker targets configmap first so that data plane pods are guaranteed to have the configmap volume
 	// mount available.
-	if err := r.reconcileConfig(ctx, bc); err != nil {
+	targets, err := r.reconcileConfig(ctx, bc)
+	if err != nil {
 		return err
 	}
+	decoupleSubscriptions, retrySubscriptions := backlogSubscriptions(targets)
 
 	authType, err := authcheck.GetAuthTypeForBrokerCell(ctx, r.serviceAccountLister, r.secretLister, authcheck.AuthTypeArgs{
 		Namespace:          bc.Namespace,
@@ -191,7 +215,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, bc *intv1alpha1.BrokerCe
 	hostName := network.GetServiceHostname(endpoints.GetName(), endpoints.GetNamespace())
 	bc.Status.IngressTemplate = fmt.Sprintf("http://%s/{namespace}/{name}", hostName)
 
-	// Reconcile fanout deployment and HPA.
+	// Reconcile fanout deployment and its HPA or ScaledObject.
 	fd, err := r.deploymentRec.ReconcileDeployment(ctx, bc, resources.MakeFanoutDeployment(r.makeFanoutArgs(bc, authType)))
 	if err != nil {
 		logging.FromContext(ctx).Error("Failed to reconcile fanout deployment", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
@@ -200,11 +224,30 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, bc *intv1alpha1.BrokerCe
 	}
 
 	fanoutHPA := resources.MakeHorizontalPodAutoscaler(fd, r.makeFanoutHPAArgs(bc))
-	if err := r.reconcileAutoscaling(ctx, bc, fanoutHPA); err != nil {
-		logging.FromContext(ctx).Error("Failed to reconcile fanout HPA", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
-		bc.Status.MarkFanoutFailed("HorizontalPodAutoscalerFailed", "Failed to reconcile fanout HorizontalPodAutoscaler: %v", err)
+	soArgs, err := r.makeScaledObjectArgs(bc, resources.FanoutName, bc.Spec.Components.Fanout, decoupleSubscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to make fanout ScaledObject", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
+		bc.Status.MarkFanoutFailed("ScaledObjectFailed", "Failed to reconcile fanout ScaledObject: %v", err)
 		return err
 	}
+	if soArgs != nil {
+		if err := r.reconcileScaledObject(ctx, bc, resources.MakeScaledObject(fd, *soArgs), fanoutHPA); err != nil {
+			logging.FromContext(ctx).Error("Failed to reconcile fanout ScaledObject", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
+			bc.Status.MarkFanoutFailed("ScaledObjectFailed", "Failed to reconcile fanout ScaledObject: %v", err)
+			return err
+		}
+	} else {
+		if err := r.reconcileAutoscaling(ctx, bc, fanoutHPA); err != nil {
+			logging.FromContext(ctx).Error("Failed to reconcile fanout HPA", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
+			bc.Status.MarkFanoutFailed("HorizontalPodAutoscalerFailed", "Failed to reconcile fanout HorizontalPodAutoscaler: %v", err)
+			return err
+		}
+		if err := r.deleteScaledObject(ctx, bc, fd.Namespace, resources.ScaledObjectName(fd)); err != nil {
+			logging.FromContext(ctx).Error("Failed to delete fanout ScaledObject", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
+			bc.Status.MarkFanoutFailed("ScaledObjectFailed", "Failed to delete fanout ScaledObject: %v", err)
+			return err
+		}
+	}
 	// If deployment has replicaUnavailable error, it potentially has authentication configuration issues.
 	if replicaAvailable := bc.Status.PropagateFanoutAvailability(fd); !replicaAvailable {
 		podList, err := authcheck.GetPodList(ctx, resources.GetLabelSelector(bc.Name, resources.FanoutName), r.KubeClientSet, bc.Namespace)
@@ -216,7 +259,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, bc *intv1alpha1.BrokerCe
 			bc.Status.MarkFanoutUnknown(authcheck.AuthenticationCheckUnknownReason, authenticationCheckMessage)
 		}
 	}
-	// Reconcile retry deployment and HPA.
+	// Reconcile retry deployment and its HPA or ScaledObject.
 	rd, err := r.deploymentRec.ReconcileDeployment(ctx, bc, resources.MakeRetryDeployment(r.makeRetryArgs(bc, authType)))
 	if err != nil {
 		logging.FromContext(ctx).Error("Failed to reconcile retry deployment", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
@@ -225,11 +268,30 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, bc *intv1alpha1.BrokerCe
 	}
 
 	retryHPA := resources.MakeHorizontalPodAutoscaler(rd, r.makeRetryHPAArgs(bc))
-	if err := r.reconcileAutoscaling(ctx, bc, retryHPA); err != nil {
-		logging.FromContext(ctx).Error("Failed to reconcile retry HPA", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
-		bc.Status.MarkRetryFailed("HorizontalPodAutoscalerFailed", "Failed to reconcile retry HorizontalPodAutoscaler: %v", err)
+	soArgs, err = r.makeScaledObjectArgs(bc, resources.RetryName, bc.Spec.Components.Retry, retrySubscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to make retry ScaledObject", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
+		bc.Status.MarkRetryFailed("ScaledObjectFailed", "Failed to reconcile retry ScaledObject: %v", err)
 		return err
 	}
+	if soArgs != nil {
+		if err := r.reconcileScaledObject(ctx, bc, resources.MakeScaledObject(rd, *soArgs), retryHPA); err != nil {
+			logging.FromContext(ctx).Error("Failed to reconcile retry ScaledObject", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
+			bc.Status.MarkRetryFailed("ScaledObjectFailed", "Failed to reconcile retry ScaledObject: %v", err)
+			return err
+		}
+	} else {
+		if err := r.reconcileAutoscaling(ctx, bc, retryHPA); err != nil {
+			logging.FromContext(ctx).Error("Failed to reconcile retry HPA", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
+			bc.Status.MarkRetryFailed("HorizontalPodAutoscalerFailed", "Failed to reconcile retry HorizontalPodAutoscaler: %v", err)
+			return err
+		}
+		if err := r.deleteScaledObject(ctx, bc, rd.Namespace, resources.ScaledObjectName(rd)); err != nil {
+			logging.FromContext(ctx).Error("Failed to delete retry ScaledObject", zap.Any("namespace", bc.Namespace), zap.Any("name", bc.Name), zap.Error(err))
+			bc.Status.MarkRetryFailed("ScaledObjectFailed", "Failed to delete retry ScaledObject: %v", err)
+			return err
+		}
+	}
 	// If deployment has replicaUnavailable error, it potentially has authentication configuration issues.
 	if replicaAvailable := bc.Status.PropagateRetryAvailability(rd); !replicaAvailable {
 		podList, err := authcheck.GetPodList(ctx, resources.GetLabelSelector(bc.Name, resources.RetryName), r.KubeClientSet, bc.Namespace)
@@ -249,7 +311,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, bc *intv1alpha1.BrokerCe
 // shouldGC returns true if
 // 1. the brokercell was automatically created by GCP broker controller (with annotation
 // internal.events.cloud.google.com/creator: googlecloud), and
-// 2. there is no brokers pointing to it
+// 2. there is no brokers or channels assigned to it
 func (r *Reconciler) shouldGC(ctx context.Context, bc *intv1alpha1.BrokerCell) bool {
 	// TODO use the constants in #1132 once it's merged
 	// We only garbage collect brokercells that were automatically created by the GCP broker controller.
@@ -257,45 +319,54 @@ func (r *Reconciler) shouldGC(ctx context.Context, bc *intv1alpha1.BrokerCell) b
 		return false
 	}
 
-	// TODO(#866) Only select brokers that point to this brokercell by label selector once the
-	// webhook assigns the brokercell label, i.e.,
-	// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
 	brokers, err := r.brokerLister.List(labels.Everything())
 	if err != nil {
 		logging.FromContext(ctx).Error("Failed to list brokers, skipping garbage collection logic", zap.String("brokercell", bc.Name), zap.String("Namespace", bc.Namespace))
 		return false
 	}
-
-	if len(brokers) > 0 {
-		// There are still Brokers using this BrokerCell, do not garbage collect it.
-		return false
+	for _, broker := range brokers {
+		if isAssigned(bc, broker) {
+			// There are still Brokers using this BrokerCell, do not garbage collect it.
+			return false
+		}
 	}
 
-	// TODO(#866) Only select Channels that point to this brokercell by label selector once the
-	// webhook assigns the brokercell label, i.e.,
-	// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
 	channels, err := r.channelLister.List(labels.Everything())
 	if err != nil {
 		logging.FromContext(ctx).Error("Failed to list Channels, skipping garbage collection logic", zap.String("brokercell", bc.Name), zap.String("Namespace", bc.Namespace))
 		return false
 	}
-	if len(channels) > 0 {
-		// There are still Channels using this BrokerCell, do not garbage collect it.
-		return false
+	for _, channel := range channels {
+		if isAssigned(bc, channel) {
+			// There are still Channels using this BrokerCell, do not garbage collect it.
+			return false
+		}
 	}
 
 	return true
 }
 
+// isAssigned returns true if the Broker or Channel is assigned to the BrokerCell.
+func isAssigned(bc *intv1alpha1.BrokerCell, obj metav1.Object) bool {
+	return intv1alpha1.BrokerCellNameOf(obj) == bc.Name
+}
+
+// isServed returns true if the Broker or Channel is assigned to the BrokerCell and its namespace
+// is allowed by the BrokerCell. The data plane of the BrokerCell only serves those.
+func isServed(bc *intv1alpha1.BrokerCell, obj metav1.Object) bool {
+	return isAssigned(bc, obj) && bc.AllowsNamespace(obj.GetNamespace())
+}
+
 func (r *Reconciler) delete(ctx context.Context, bc *intv1alpha1.BrokerCell) pkgreconciler.Event {
 	if err := r.RunClientSet.InternalV1alpha1().BrokerCells(bc.Namespace).Delete(ctx, bc.Name, metav1.DeleteOptions{}); err != nil {
 		return fmt.Errorf("failed to garbage collect brokercell: %w", err)
 	}
+	r.targetsCache.forget(types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name})
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "BrokerCellGarbageCollected", "BrokerCell garbage collected: \"%s/%s\"", bc.Namespace, bc.Name)
 }
 
 func (r *Reconciler) makeIngressArgs(bc *intv1alpha1.BrokerCell, authType authcheck.AuthType) resources.IngressArgs {
-	return resources.IngressArgs{
+	args := resources.IngressArgs{
 		Args: resources.Args{
 			ComponentName:      resources.IngressName,
 			BrokerCell:         bc,
@@ -314,6 +385,27 @@ func (r *Reconciler) makeIngressArgs(bc *intv1alpha1.BrokerCell, authType authch
 		// TODO(#1804): remove this arg when enabling the feature by default.
 		EnableIngressFilter: getIngressFilteringEnabled(bc),
 	}
+	if payload := bc.Spec.Payload; payload != nil {
+		if payload.MaxSizeBytes != nil {
+			args.MaxRequestBodyBytes = *payload.MaxSizeBytes
+		}
+		if payload.ClaimCheck != nil {
+			args.ClaimCheckBucket = payload.ClaimCheck.Bucket
+			if payload.ClaimCheck.ThresholdBytes != nil {
+				args.ClaimCheckThresholdBytes = *payload.ClaimCheck.ThresholdBytes
+			}
+		}
+	}
+	return args
+}
+
+// claimCheckBucket returns the bucket the data of large events of the BrokerCell is offloaded to,
+// or an empty string if the data is not offloaded.
+func claimCheckBucket(bc *intv1alpha1.BrokerCell) string {
+	if payload := bc.Spec.Payload; payload != nil && payload.ClaimCheck != nil {
+		return payload.ClaimCheck.Bucket
+	}
+	return ""
 }
 
 // TODO(#1804): remove this function when enabling the feature by default.
@@ -352,6 +444,7 @@ func (r *Reconciler) makeFanoutArgs(bc *intv1alpha1.BrokerCell, authType authche
 			RolloutRestartTime: bc.GetAnnotations()[resources.FanoutRestartTimeAnnotationKey],
 			AuthType:           authType,
 		},
+		ClaimCheckBucket: claimCheckBucket(bc),
 	}
 }
 
@@ -382,6 +475,7 @@ func (r *Reconciler) makeRetryArgs(bc *intv1alpha1.BrokerCell, authType authchec
 			RolloutRestartTime: bc.GetAnnotations()[resources.RetryRestartTimeAnnotationKey],
 			AuthType:           authType,
 		},
+		ClaimCheckBucket: claimCheckBucket(bc),
 	}
 }
 
@@ -18,11 +18,14 @@ package brokercell
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	duckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 
@@ -30,13 +33,20 @@ import (
 	hpav2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/ptr"
 	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/resolver"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/proto"
@@ -48,6 +58,7 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/testingdata"
+	channelresources "github.com/google/knative-gcp/pkg/reconciler/messaging/channel/resources"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 )
@@ -56,12 +67,21 @@ const (
 	testNS         = "testnamespace"
 	brokerCellName = "test-brokercell"
 	targetsCMName  = "broker-targets"
+	testProject    = "test-project-id"
 	targetsCMKey   = "targets"
+	// kedaInstalled is the key of the test data telling whether KEDA is installed.
+	kedaInstalled = "kedaInstalled"
 )
 
 var (
-	testKey     = fmt.Sprintf("%s/%s", testNS, brokerCellName)
-	testKeyAuth = fmt.Sprintf("%s/%s", authcheck.ControlPlaneNamespace, brokerCellName)
+	retry        int32 = 3
+	backoffDelay       = "PT5S"
+	linear             = duckv1beta1.BackoffPolicyLinear
+
+	testKey = fmt.Sprintf("%s/%s", testNS, brokerCellName)
+
+	kedaNotInstalledErr = errors.New("server does not support API version \"keda.sh/v1alpha1\"")
+	testKeyAuth         = fmt.Sprintf("%s/%s", authcheck.ControlPlaneNamespace, brokerCellName)
 
 	creatorAnnotation       = map[string]string{"internal.events.cloud.google.com/creator": "googlecloud"}
 	restartedTimeAnnotation = map[string]string{
@@ -74,35 +94,50 @@ var (
 		"events.cloud.google.com/ingressFilteringEnabled": "true",
 	}
 
-	brokerCellReconciledEvent     = Eventf(corev1.EventTypeNormal, "BrokerCellReconciled", `BrokerCell reconciled: "testnamespace/test-brokercell"`)
-	brokerCellGCEvent             = Eventf(corev1.EventTypeNormal, "BrokerCellGarbageCollected", `BrokerCell garbage collected: "testnamespace/test-brokercell"`)
-	brokerCellGCFailedEvent       = Eventf(corev1.EventTypeWarning, "InternalError", `failed to garbage collect brokercell: inducing failure for delete brokercells`)
-	brokerCellUpdateFailedEvent   = Eventf(corev1.EventTypeWarning, "UpdateFailed", `Failed to update status for "test-brokercell": inducing failure for update brokercells`)
-	ingressDeploymentCreatedEvent = Eventf(corev1.EventTypeNormal, "DeploymentCreated", "Created deployment testnamespace/test-brokercell-brokercell-ingress")
-	ingressDeploymentUpdatedEvent = Eventf(corev1.EventTypeNormal, "DeploymentUpdated", "Updated deployment testnamespace/test-brokercell-brokercell-ingress")
-	ingressHPACreatedEvent        = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerCreated", "Created HPA testnamespace/test-brokercell-brokercell-ingress-hpa")
-	ingressHPAUpdatedEvent        = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerUpdated", "Updated HPA testnamespace/test-brokercell-brokercell-ingress-hpa")
-	fanoutDeploymentCreatedEvent  = Eventf(corev1.EventTypeNormal, "DeploymentCreated", "Created deployment testnamespace/test-brokercell-brokercell-fanout")
-	fanoutDeploymentUpdatedEvent  = Eventf(corev1.EventTypeNormal, "DeploymentUpdated", "Updated deployment testnamespace/test-brokercell-brokercell-fanout")
-	fanoutHPACreatedEvent         = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerCreated", "Created HPA testnamespace/test-brokercell-brokercell-fanout-hpa")
-	fanoutHPAUpdatedEvent         = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerUpdated", "Updated HPA testnamespace/test-brokercell-brokercell-fanout-hpa")
-	retryDeploymentCreatedEvent   = Eventf(corev1.EventTypeNormal, "DeploymentCreated", "Created deployment testnamespace/test-brokercell-brokercell-retry")
-	retryDeploymentUpdatedEvent   = Eventf(corev1.EventTypeNormal, "DeploymentUpdated", "Updated deployment testnamespace/test-brokercell-brokercell-retry")
-	retryHPACreatedEvent          = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerCreated", "Created HPA testnamespace/test-brokercell-brokercell-retry-hpa")
-	retryHPAUpdatedEvent          = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerUpdated", "Updated HPA testnamespace/test-brokercell-brokercell-retry-hpa")
-	ingressServiceCreatedEvent    = Eventf(corev1.EventTypeNormal, "ServiceCreated", "Created service testnamespace/test-brokercell-brokercell-ingress")
-	ingressServiceUpdatedEvent    = Eventf(corev1.EventTypeNormal, "ServiceUpdated", "Updated service testnamespace/test-brokercell-brokercell-ingress")
-	deploymentCreationFailedEvent = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create deployments")
-	deploymentUpdateFailedEvent   = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update deployments")
-	serviceCreationFailedEvent    = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create services")
-	serviceUpdateFailedEvent      = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update services")
-	hpaCreationFailedEvent        = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create horizontalpodautoscalers")
-	hpaUpdateFailedEvent          = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update horizontalpodautoscalers")
-	configmapCreationFailedEvent  = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create configmaps")
-	configmapUpdateFailedEvent    = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update configmaps")
-	configmapCreatedEvent         = Eventf(corev1.EventTypeNormal, "ConfigMapCreated", "Created configmap testnamespace/test-brokercell-brokercell-broker-targets")
-	configmapUpdatedEvent         = Eventf(corev1.EventTypeNormal, "ConfigMapUpdated", "Updated configmap testnamespace/test-brokercell-brokercell-broker-targets")
-	authTypeEvent                 = Eventf(corev1.EventTypeWarning, "InternalError", "authentication is not configured, when checking Kubernetes Service Account broker, got error: can't find Kubernetes Service Account broker, when checking Kubernetes Secret google-broker-key, got error: can't find Kubernetes Secret google-broker-key")
+	claimCheckPayload = &intv1alpha1.PayloadSpec{
+		MaxSizeBytes: ptr.Int64(100000000),
+		ClaimCheck: &intv1alpha1.ClaimCheckSpec{
+			Bucket:         "claim-check-bucket",
+			ThresholdBytes: ptr.Int64(1000000),
+		},
+	}
+
+	brokerCellReconciledEvent      = Eventf(corev1.EventTypeNormal, "BrokerCellReconciled", `BrokerCell reconciled: "testnamespace/test-brokercell"`)
+	brokerCellGCEvent              = Eventf(corev1.EventTypeNormal, "BrokerCellGarbageCollected", `BrokerCell garbage collected: "testnamespace/test-brokercell"`)
+	brokerCellGCFailedEvent        = Eventf(corev1.EventTypeWarning, "InternalError", `failed to garbage collect brokercell: inducing failure for delete brokercells`)
+	brokerCellUpdateFailedEvent    = Eventf(corev1.EventTypeWarning, "UpdateFailed", `Failed to update status for "test-brokercell": inducing failure for update brokercells`)
+	ingressDeploymentCreatedEvent  = Eventf(corev1.EventTypeNormal, "DeploymentCreated", "Created deployment testnamespace/test-brokercell-brokercell-ingress")
+	ingressDeploymentUpdatedEvent  = Eventf(corev1.EventTypeNormal, "DeploymentUpdated", "Updated deployment testnamespace/test-brokercell-brokercell-ingress")
+	ingressHPACreatedEvent         = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerCreated", "Created HPA testnamespace/test-brokercell-brokercell-ingress-hpa")
+	ingressHPAUpdatedEvent         = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerUpdated", "Updated HPA testnamespace/test-brokercell-brokercell-ingress-hpa")
+	fanoutDeploymentCreatedEvent   = Eventf(corev1.EventTypeNormal, "DeploymentCreated", "Created deployment testnamespace/test-brokercell-brokercell-fanout")
+	fanoutDeploymentUpdatedEvent   = Eventf(corev1.EventTypeNormal, "DeploymentUpdated", "Updated deployment testnamespace/test-brokercell-brokercell-fanout")
+	fanoutHPACreatedEvent          = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerCreated", "Created HPA testnamespace/test-brokercell-brokercell-fanout-hpa")
+	fanoutHPAUpdatedEvent          = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerUpdated", "Updated HPA testnamespace/test-brokercell-brokercell-fanout-hpa")
+	retryDeploymentCreatedEvent    = Eventf(corev1.EventTypeNormal, "DeploymentCreated", "Created deployment testnamespace/test-brokercell-brokercell-retry")
+	retryDeploymentUpdatedEvent    = Eventf(corev1.EventTypeNormal, "DeploymentUpdated", "Updated deployment testnamespace/test-brokercell-brokercell-retry")
+	retryHPACreatedEvent           = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerCreated", "Created HPA testnamespace/test-brokercell-brokercell-retry-hpa")
+	retryHPAUpdatedEvent           = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerUpdated", "Updated HPA testnamespace/test-brokercell-brokercell-retry-hpa")
+	ingressServiceCreatedEvent     = Eventf(corev1.EventTypeNormal, "ServiceCreated", "Created service testnamespace/test-brokercell-brokercell-ingress")
+	ingressServiceUpdatedEvent     = Eventf(corev1.EventTypeNormal, "ServiceUpdated", "Updated service testnamespace/test-brokercell-brokercell-ingress")
+	deploymentCreationFailedEvent  = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create deployments")
+	deploymentUpdateFailedEvent    = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update deployments")
+	serviceCreationFailedEvent     = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create services")
+	serviceUpdateFailedEvent       = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update services")
+	hpaCreationFailedEvent         = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create horizontalpodautoscalers")
+	hpaUpdateFailedEvent           = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update horizontalpodautoscalers")
+	configmapCreationFailedEvent   = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create configmaps")
+	configmapUpdateFailedEvent     = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update configmaps")
+	configmapCreatedEvent          = Eventf(corev1.EventTypeNormal, "ConfigMapCreated", "Created configmap testnamespace/test-brokercell-brokercell-broker-targets")
+	configmapUpdatedEvent          = Eventf(corev1.EventTypeNormal, "ConfigMapUpdated", "Updated configmap testnamespace/test-brokercell-brokercell-broker-targets")
+	fanoutScaledObjectCreatedEvent = Eventf(corev1.EventTypeNormal, "ScaledObjectCreated", "Created ScaledObject testnamespace/test-brokercell-brokercell-fanout-scaledobject")
+	fanoutScaledObjectUpdatedEvent = Eventf(corev1.EventTypeNormal, "ScaledObjectUpdated", "Updated ScaledObject testnamespace/test-brokercell-brokercell-fanout-scaledobject")
+	fanoutScaledObjectDeletedEvent = Eventf(corev1.EventTypeNormal, "ScaledObjectDeleted", "Deleted ScaledObject testnamespace/test-brokercell-brokercell-fanout-scaledobject")
+	fanoutHPADeletedEvent          = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerDeleted", "Deleted HPA testnamespace/test-brokercell-brokercell-fanout-hpa")
+	retryScaledObjectCreatedEvent  = Eventf(corev1.EventTypeNormal, "ScaledObjectCreated", "Created ScaledObject testnamespace/test-brokercell-brokercell-retry-scaledobject")
+	retryScaledObjectDeletedEvent  = Eventf(corev1.EventTypeNormal, "ScaledObjectDeleted", "Deleted ScaledObject testnamespace/test-brokercell-brokercell-retry-scaledobject")
+	retryHPADeletedEvent           = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerDeleted", "Deleted HPA testnamespace/test-brokercell-brokercell-retry-hpa")
+	authTypeEvent                  = Eventf(corev1.EventTypeWarning, "InternalError", "authentication is not configured, when checking Kubernetes Service Account broker, got error: can't find Kubernetes Service Account broker, when checking Kubernetes Secret google-broker-key, got error: can't find Kubernetes Secret google-broker-key")
 )
 
 func init() {
@@ -155,7 +190,7 @@ func TestAllCases(t *testing.T) {
 			Objects: []runtime.Object{
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
-				NewBroker("broker", testNS, WithBrokerSetDefaults),
+				NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{InduceFailure("update", "configmaps")},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
@@ -170,7 +205,7 @@ func TestAllCases(t *testing.T) {
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 				testingdata.BrokerCellObjects{
 					BrokersToTriggers: map[*brokerv1beta1.Broker][]*brokerv1beta1.Trigger{
-						NewBroker("broker", testNS, WithBrokerSetDefaults): {},
+						NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults): {},
 					},
 				},
 			)}},
@@ -721,7 +756,7 @@ func TestAllCases(t *testing.T) {
 			Objects: []runtime.Object{
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
-				NewBroker("broker", testNS, WithBrokerSetDefaults),
+				NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults),
 				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
 				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS),
 				NewDeployment(brokerCellName+"-brokercell-ingress", testNS,
@@ -756,7 +791,7 @@ func TestAllCases(t *testing.T) {
 					NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 					testingdata.BrokerCellObjects{
 						BrokersToTriggers: map[*brokerv1beta1.Broker][]*brokerv1beta1.Trigger{
-							NewBroker("broker", testNS, WithBrokerSetDefaults): {},
+							NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults): {},
 						},
 					})},
 				{Object: testingdata.IngressDeployment(t)},
@@ -881,6 +916,123 @@ func TestAllCases(t *testing.T) {
 				brokerCellReconciledEvent,
 			},
 		},
+		{
+			Name: "BrokerCell with claim check created successfully",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults,
+					WithBrokerCellPayload(claimCheckPayload)),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithStatus(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: testingdata.IngressDeploymentWithClaimCheck(t)},
+				{Object: testingdata.FanoutDeploymentWithClaimCheck(t)},
+				{Object: testingdata.RetryDeploymentWithClaimCheck(t)},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithBrokerCellPayload(claimCheckPayload),
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				ingressDeploymentUpdatedEvent,
+				fanoutDeploymentUpdatedEvent,
+				retryDeploymentUpdatedEvent,
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "Stale targets config shards are deleted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				testingdata.Configs(NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults, WithBrokerCellTargetsConfigShards(2)),
+					testingdata.BrokerCellObjects{})[1],
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithStatus(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantDeletes: []clientgotesting.DeleteActionImpl{
+				{
+					Name: brokerCellName + "-brokercell-broker-targets-1",
+					ActionImpl: clientgotesting.ActionImpl{
+						Namespace: testNS,
+						Verb:      "delete",
+						Resource:  corev1.SchemeGroupVersion.WithResource("configmaps"),
+					},
+				},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "Brokers of namespaces the BrokerCell doesn't allow are left out of the targets config",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellAllowedNamespaces(testNS),
+					WithBrokerCellSetDefaults),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults),
+				NewBroker("broker", "other-namespace", WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithStatus(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantUpdates: []clientgotesting.UpdateActionImpl{{Object: testingdata.Config(
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+				testingdata.BrokerCellObjects{
+					BrokersToTriggers: map[*brokerv1beta1.Broker][]*brokerv1beta1.Trigger{
+						NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults): {},
+					},
+				},
+			)}},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellAllowedNamespaces(testNS),
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				configmapUpdatedEvent,
+				brokerCellReconciledEvent,
+			},
+		},
 		{
 			Name: "googlecloud created BrokerCell shouldn't be gc'ed because there are brokers",
 			Key:  testKey,
@@ -891,10 +1043,10 @@ func TestAllCases(t *testing.T) {
 				testingdata.Config(NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 					testingdata.BrokerCellObjects{
 						BrokersToTriggers: map[*brokerv1beta1.Broker][]*brokerv1beta1.Trigger{
-							NewBroker("broker", testNS, WithBrokerSetDefaults): {},
+							NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults): {},
 						},
 					}),
-				NewBroker("broker", testNS, WithBrokerSetDefaults),
+				NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerSetDefaults),
 				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
 					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
 				testingdata.IngressDeploymentWithStatus(t),
@@ -927,10 +1079,10 @@ func TestAllCases(t *testing.T) {
 				testingdata.Config(NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 					testingdata.BrokerCellObjects{
 						Channels: []*v1beta1.Channel{
-							NewChannel("channel", testNS, WithChannelSetDefaults, WithChannelAddress("http://example.com")),
+							NewChannel("channel", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults, WithChannelAddress("http://example.com")),
 						},
 					}),
-				NewChannel("channel", testNS, WithChannelSetDefaults, WithChannelAddress("http://example.com")),
+				NewChannel("channel", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults, WithChannelAddress("http://example.com")),
 				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
 					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
 				testingdata.IngressDeploymentWithStatus(t),
@@ -995,6 +1147,30 @@ func TestAllCases(t *testing.T) {
 			},
 			WantEvents: []string{brokerCellGCEvent},
 		},
+		{
+			Name: "googlecloud created BrokerCell is gc'ed successfully if the brokers and channels are assigned to other brokercells",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellAnnotations(creatorAnnotation),
+					WithBrokerCellSetDefaults,
+					WithInitBrokerCellConditions,
+				),
+				NewBroker("broker", testNS, WithBrokerBrokerCell("other"), WithBrokerSetDefaults),
+				NewChannel("channel", testNS, WithChannelBrokerCell("other"), WithChannelSetDefaults, WithChannelAddress("http://example.com")),
+			},
+			WantDeletes: []clientgotesting.DeleteActionImpl{
+				{
+					Name: brokerCellName,
+					ActionImpl: clientgotesting.ActionImpl{
+						Namespace: testNS,
+						Verb:      "delete",
+						Resource:  intv1alpha1.SchemeGroupVersion.WithResource("brokercells"),
+					},
+				},
+			},
+			WantEvents: []string{brokerCellGCEvent},
+		},
 		{
 			Name: "Brokercell has restart time annotation, deployments are updated with restart time annotation successfully",
 			Key:  testKey,
@@ -1032,6 +1208,178 @@ func TestAllCases(t *testing.T) {
 				brokerCellReconciledEvent,
 			},
 		},
+		{
+			Name: "Backlog autoscaling, ScaledObjects created and HPAs deleted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				backlogBrokerCell(),
+				testingdata.Config(backlogBrokerCell(), testingdata.BrokerCellObjects{Channels: []*v1beta1.Channel{backlogChannel()}}),
+				backlogChannel(),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithStatus(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			OtherTestData: map[string]interface{}{kedaInstalled: true},
+			WantCreates: []runtime.Object{
+				fanoutScaledObject(t),
+				retryScaledObject(t),
+			},
+			WantDeletes: []clientgotesting.DeleteActionImpl{
+				hpaDelete(testingdata.FanoutHPA(t)),
+				hpaDelete(testingdata.RetryHPA(t)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellBacklogAutoscaling("keda-gcp"),
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				fanoutScaledObjectCreatedEvent,
+				fanoutHPADeletedEvent,
+				retryScaledObjectCreatedEvent,
+				retryHPADeletedEvent,
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "Backlog autoscaling, ScaledObject updated",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				backlogBrokerCell(),
+				testingdata.Config(backlogBrokerCell(), testingdata.BrokerCellObjects{Channels: []*v1beta1.Channel{backlogChannel()}}),
+				backlogChannel(),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithStatus(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				scaledObject(testingdata.FanoutDeployment(t), resources.FanoutName, "deleted-subscription"),
+				retryScaledObject(t),
+			},
+			OtherTestData: map[string]interface{}{kedaInstalled: true},
+			WantUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: fanoutScaledObject(t)},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellBacklogAutoscaling("keda-gcp"),
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				fanoutScaledObjectUpdatedEvent,
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "Backlog autoscaling, KEDA not installed",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				backlogBrokerCell(),
+				testingdata.Config(backlogBrokerCell(), testingdata.BrokerCellObjects{Channels: []*v1beta1.Channel{backlogChannel()}}),
+				backlogChannel(),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithStatus(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellBacklogAutoscaling("keda-gcp"),
+					WithInitBrokerCellConditions,
+					WithTargetsCofigReady(),
+					WithBrokerCellIngressAvailable(),
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellFanoutFailed("ScaledObjectFailed", `Failed to reconcile fanout ScaledObject: failed to check whether KEDA is installed: `+kedaNotInstalledErr.Error()),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeWarning, "InternalError", "failed to check whether KEDA is installed: "+kedaNotInstalledErr.Error()),
+			},
+			WantErr: true,
+		},
+		{
+			Name: "Backlog autoscaling without subscriptions, HPAs kept",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				backlogBrokerCell(),
+				testingdata.EmptyConfig(t, backlogBrokerCell()),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithStatus(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellBacklogAutoscaling("keda-gcp"),
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "Resource autoscaling, ScaledObjects deleted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithStatus(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+				fanoutScaledObject(t),
+				retryScaledObject(t),
+			},
+			WantDeletes: []clientgotesting.DeleteActionImpl{
+				scaledObjectDelete(fanoutScaledObject(t)),
+				scaledObjectDelete(retryScaledObject(t)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				fanoutScaledObjectDeletedEvent,
+				retryScaledObjectDeletedEvent,
+				brokerCellReconciledEvent,
+			},
+		},
 	}
 
 	table.Test(t, MakeFactory(func(ctx context.Context, testingListers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
@@ -1055,10 +1403,78 @@ func TestAllCases(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to created BrokerCell reconciler: %v", err)
 		}
+		r.uriResolver = resolver.NewURIResolver(addressable.WithDuck(ctx), func(types.NamespacedName) {})
+		r.projectID = testProject
+		r.discoveryFn = func(discovery.DiscoveryInterface, schema.GroupVersion) error {
+			if testData[kedaInstalled] == true {
+				return nil
+			}
+			return kedaNotInstalledErr
+		}
 		return bcreconciler.NewReconciler(ctx, r.Logger, r.RunClientSet, testingListers.GetBrokerCellLister(), r.Recorder, r)
 	}))
 }
 
+// backlogBrokerCell is a BrokerCell scaling its fanout and retry on the backlog of their subscriptions.
+func backlogBrokerCell() *intv1alpha1.BrokerCell {
+	return NewBrokerCell(brokerCellName, testNS, WithBrokerCellBacklogAutoscaling("keda-gcp"), WithBrokerCellSetDefaults)
+}
+
+// backlogChannel is a Channel with ready decouple and retry subscriptions.
+func backlogChannel() *v1beta1.Channel {
+	return NewChannel("channel", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults,
+		WithChannelAddress("http://example.com"), WithChannelTopic(), WithChannelSubscriptionReady(),
+		WithChannelSubscribers(duckv1beta1.SubscriberSpec{
+			UID:           "subscriber-uid",
+			SubscriberURI: uri("http://example.com/subscriber"),
+		}))
+}
+
+func scaledObject(d *appsv1.Deployment, componentName string, subscriptions ...string) *unstructured.Unstructured {
+	bc := backlogBrokerCell()
+	return resources.MakeScaledObject(d, resources.ScaledObjectArgs{
+		ComponentName: componentName,
+		BrokerCell:    bc,
+		Backlog:       bc.Spec.Autoscaling.Backlog,
+		ProjectID:     testProject,
+		Subscriptions: subscriptions,
+		MaxReplicas:   *bc.Spec.Components.Fanout.MaxReplicas,
+		MinReplicas:   *bc.Spec.Components.Fanout.MinReplicas,
+	})
+}
+
+func fanoutScaledObject(t *testing.T) *unstructured.Unstructured {
+	return scaledObject(testingdata.FanoutDeployment(t), resources.FanoutName,
+		channelresources.GenerateDecouplingSubscriptionName(backlogChannel()))
+}
+
+func retryScaledObject(t *testing.T) *unstructured.Unstructured {
+	return scaledObject(testingdata.RetryDeployment(t), resources.RetryName,
+		channelresources.GenerateSubscriberRetrySubscriptionName(backlogChannel(), "subscriber-uid"))
+}
+
+func hpaDelete(hpa *hpav2beta2.HorizontalPodAutoscaler) clientgotesting.DeleteActionImpl {
+	return clientgotesting.DeleteActionImpl{
+		Name: hpa.Name,
+		ActionImpl: clientgotesting.ActionImpl{
+			Namespace: hpa.Namespace,
+			Verb:      "delete",
+			Resource:  hpav2beta2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"),
+		},
+	}
+}
+
+func scaledObjectDelete(so *unstructured.Unstructured) clientgotesting.DeleteActionImpl {
+	return clientgotesting.DeleteActionImpl{
+		Name: so.GetName(),
+		ActionImpl: clientgotesting.ActionImpl{
+			Namespace: so.GetNamespace(),
+			Verb:      "delete",
+			Resource:  resources.ScaledObjectGVK.GroupVersion().WithResource("scaledobjects"),
+		},
+	}
+}
+
 func emptyHPASpec(template *hpav2beta2.HorizontalPodAutoscaler) *hpav2beta2.HorizontalPodAutoscaler {
 	template.Spec = hpav2beta2.HorizontalPodAutoscalerSpec{}
 	return template
@@ -1078,7 +1494,7 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 	}{
 		{
 			name:   "reconcile config of one broker and its triggers",
-			broker: NewBroker("broker", testNS, WithBrokerClass(brokerv1beta1.BrokerClass)),
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass)),
 			triggers: []*brokerv1beta1.Trigger{
 				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
 				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
@@ -1086,10 +1502,126 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 			expectEmptyMap: false,
 		},
-
+		{
+			name:   "reconcile config of one broker and its triggers with filters",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass)),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerFiltersAnnotation(`[{"prefix":{"type":"com.example.order."}},{"any":[{"exact":{"source":"a"}},{"not":{"cesql":"subject LIKE 'b%'"}}]}]`)),
+				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
+		{
+			name:   "reconcile config of one broker and its triggers with transforms",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass)),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerTransformAnnotation(`{"setAttributes":{"type":"com.example.new"},"removeAttributes":["region"],"dataPatch":[{"op":"add","path":"/foo","value":{"bar":1}}]}`)),
+				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
+		{
+			name: "reconcile config of one broker with ordering key attribute and its triggers",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerOrderingKeyAttribute("partitionkey")),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
+		{
+			name: "reconcile config of one broker with ingress policy and its triggers",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerIngressPolicyAnnotation(`{"issuers":[{"issuer":"https://kubernetes.default.svc","kubernetes":true,"audiences":["broker"]},`+
+					`{"issuer":"https://accounts.google.com","jwksUrl":"https://www.googleapis.com/oauth2/v3/certs","audiences":["broker","events"]}],`+
+					`"rules":[{"issuer":"https://kubernetes.default.svc","subjects":["system:serviceaccount:team-a:*"],"types":["com.example.*"],"sources":["//team-a/*"]}]}`)),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
+		{
+			name:   "reconcile config of one broker and its triggers with batching",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass)),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerBatchingAnnotation(`{"maxCount":500,"maxBytes":2097152,"linger":"PT0.5S"}`)),
+				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
+		{
+			name:   "reconcile config of one broker and its triggers with rate limits",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass)),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerRateLimitAnnotation(`{"requestsPerSecond":10,"burst":20,"maxInFlight":5}`)),
+				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
+		{
+			name:   "reconcile config of one broker and its triggers with replays",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass)),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerReplayAnnotation(`{"time":"2021-03-04T05:06:07Z"}`),
+					WithTriggerReplayReady(`{"time":"2021-03-04T05:06:07Z"}`, "2021-03-05T00:00:00Z", "Replaying")),
+				// The replay subscription has not been seeked yet.
+				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerReplayAnnotation(`{"snapshot":"snapshot"}`)),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
+		{
+			name:   "reconcile config of one broker and its triggers with dead letter sinks",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass)),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerDeliverySpec(&eventingduckv1.DeliverySpec{
+						DeadLetterSink: &duckv1.Destination{URI: uri("http://example.com/dead-letter")},
+						Retry:          &retry,
+						BackoffDelay:   &backoffDelay,
+					})),
+				// Dead letter topics are handled by Pub/Sub, so they are not in the config.
+				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerDeliverySpec(&eventingduckv1.DeliverySpec{
+						DeadLetterSink: &duckv1.Destination{URI: uri("pubsub://dead-letter-topic")},
+					})),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
+		{
+			name: "reconcile config of one broker with delivery spec and its triggers",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerDeliverySpec(&duckv1beta1.DeliverySpec{
+					BackoffPolicy: &linear,
+					BackoffDelay:  &backoffDelay,
+				})),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
+				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults,
+					WithTriggerDeliverySpec(&eventingduckv1.DeliverySpec{
+						DeadLetterSink: &duckv1.Destination{URI: uri("http://example.com/dead-letter")},
+						Retry:          &retry,
+					})),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: false,
+		},
 		{
 			name:   "reconcile config when the broker is not gcp broker",
-			broker: NewBroker("broker", testNS, WithBrokerClass("some-other-broker-class")),
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass("some-other-broker-class")),
 			triggers: []*brokerv1beta1.Trigger{
 				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
 				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
@@ -1097,18 +1629,30 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 			expectEmptyMap: true,
 		},
+		{
+			name:   "reconcile config when the broker is assigned to another brokercell",
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell("other"), WithBrokerClass(brokerv1beta1.BrokerClass)),
+			triggers: []*brokerv1beta1.Trigger{
+				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
+			},
+			channels: []*v1beta1.Channel{
+				NewChannel("channel1", testNS, WithChannelBrokerCell("other"), WithChannelSetDefaults, WithChannelAddress("http://example.com/1")),
+			},
+			bc:             NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+			expectEmptyMap: true,
+		},
 		{
 			name: "Channels",
 			channels: []*v1beta1.Channel{
-				NewChannel("channel1", testNS, WithChannelSetDefaults, WithChannelAddress("http://example.com/1")),
-				NewChannel("channel2", testNS, WithChannelSetDefaults, WithChannelAddress("http://example.com/2")),
+				NewChannel("channel1", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults, WithChannelAddress("http://example.com/1")),
+				NewChannel("channel2", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults, WithChannelAddress("http://example.com/2")),
 			},
 			bc: NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 		},
 		{
 			name: "Channels with Subscribers",
 			channels: []*v1beta1.Channel{
-				NewChannel("channel1", testNS, WithChannelSetDefaults, WithChannelAddress("http://example.com/1"),
+				NewChannel("channel1", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults, WithChannelAddress("http://example.com/1"),
 					WithChannelSubscribers(duckv1beta1.SubscriberSpec{
 						UID:           "subscriber-1-uid",
 						SubscriberURI: uri("http://example.com/subscriber-1-uri"),
@@ -1120,7 +1664,7 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 						ReplyURI:      uri("http://example.com/subscriber-2-reply"),
 					}),
 				),
-				NewChannel("channel2", testNS, WithChannelSetDefaults, WithChannelAddress("http://example.com/2"),
+				NewChannel("channel2", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults, WithChannelAddress("http://example.com/2"),
 					WithChannelSubscribers(duckv1beta1.SubscriberSpec{
 						UID:           "subscriber-3-uid",
 						SubscriberURI: uri("http://example.com/subscriber-3-uri"),
@@ -1137,14 +1681,14 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 		},
 		{
 			name:   "Brokers and Channels",
-			broker: NewBroker("broker", testNS, WithBrokerClass(brokerv1beta1.BrokerClass)),
+			broker: NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass)),
 			triggers: []*brokerv1beta1.Trigger{
 				NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
 				NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
 			},
 			bc: NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 			channels: []*v1beta1.Channel{
-				NewChannel("channel1", testNS, WithChannelSetDefaults, WithChannelAddress("http://example.com/1"),
+				NewChannel("channel1", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults, WithChannelAddress("http://example.com/1"),
 					WithChannelSubscribers(duckv1beta1.SubscriberSpec{
 						UID:           "subscriber-1-uid",
 						SubscriberURI: uri("http://example.com/subscriber-1-uri"),
@@ -1156,7 +1700,7 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 						ReplyURI:      uri("http://example.com/subscriber-2-reply"),
 					}),
 				),
-				NewChannel("channel2", testNS, WithChannelSetDefaults, WithChannelAddress("http://example.com/2"),
+				NewChannel("channel2", testNS, WithChannelBrokerCell(brokerCellName), WithChannelSetDefaults, WithChannelAddress("http://example.com/2"),
 					WithChannelSubscribers(duckv1beta1.SubscriberSpec{
 						UID:           "subscriber-3-uid",
 						SubscriberURI: uri("http://example.com/subscriber-3-uri"),
@@ -1206,6 +1750,7 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Failed to create BrokerCell reconciler: %v", err)
 			}
+			r.uriResolver = resolver.NewURIResolver(addressable.WithDuck(ctx), func(types.NamespacedName) {})
 			// here we only want to test the functionality of the reconcileConfig that it should create a brokerTargets config successfully
 			r.reconcileConfig(ctx, bc)
 			var wantMap *corev1.ConfigMap
@@ -1245,6 +1790,86 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 	}
 }
 
+// TestBrokerTargetsReconcileConfigIncremental tests that after the first reconciliation, only
+// the Brokers and Channels that were marked dirty are rebuilt, and that an unchanged targets config
+// is not written again.
+func TestBrokerTargetsReconcileConfigIncremental(t *testing.T) {
+	setReconcilerEnv()
+	bc := NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)
+	broker := NewBroker("broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass))
+	trigger1 := NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults)
+	trigger2 := NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults)
+	otherBroker := NewBroker("other-broker", testNS, WithBrokerBrokerCell(brokerCellName), WithBrokerClass(brokerv1beta1.BrokerClass))
+
+	ctx, _ := SetupFakeContext(t)
+	ctx, client := fakekubeclient.With(ctx)
+	base := reconciler.NewBase(ctx, controllerAgentName, configmap.NewStaticWatcher())
+	setListers := func(r *Reconciler, objects ...runtime.Object) {
+		testingListers := NewListers(objects)
+		r.brokerLister = testingListers.GetBrokerLister()
+		r.triggerLister = testingListers.GetTriggerLister()
+		r.channelLister = testingListers.GetChannelLister()
+		r.configMapLister = testingListers.GetConfigMapLister()
+		r.podLister = testingListers.GetPodLister()
+		r.cmRec.Lister = r.configMapLister
+	}
+	getConfigMap := func() *corev1.ConfigMap {
+		cm, err := client.CoreV1().ConfigMaps(testNS).Get(context.Background(), resources.Name(bc.Name, targetsCMName), metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get ConfigMap from client: %v", err)
+		}
+		return cm
+	}
+	wantTargets := func(brokersToTriggers map[*brokerv1beta1.Broker][]*brokerv1beta1.Trigger) {
+		t.Helper()
+		want := testingdata.Config(bc, testingdata.BrokerCellObjects{BrokersToTriggers: brokersToTriggers})
+		var wantTargets, gotTargets config.TargetsConfig
+		if err := proto.Unmarshal(want.BinaryData[targetsCMKey], &wantTargets); err != nil {
+			t.Fatalf("Failed to deserialize the binary data in ConfigMap: %v", err)
+		}
+		if err := proto.Unmarshal(getConfigMap().BinaryData[targetsCMKey], &gotTargets); err != nil {
+			t.Fatalf("Failed to deserialize the binary data in ConfigMap: %v", err)
+		}
+		if diff := cmp.Diff(wantTargets.String(), gotTargets.String()); diff != "" {
+			t.Fatalf("Unexpected brokerTargets in ConfigMap(-want, +got): %s", diff)
+		}
+	}
+
+	r, err := NewReconciler(base, listers{})
+	if err != nil {
+		t.Fatalf("Failed to create BrokerCell reconciler: %v", err)
+	}
+	r.uriResolver = resolver.NewURIResolver(addressable.WithDuck(ctx), func(types.NamespacedName) {})
+
+	// The first reconciliation builds the whole targets config.
+	setListers(r, bc, broker, trigger1, trigger2)
+	if _, err := r.reconcileConfig(ctx, bc); err != nil {
+		t.Fatalf("reconcileConfig() = %v", err)
+	}
+	wantTargets(map[*brokerv1beta1.Broker][]*brokerv1beta1.Trigger{broker: {trigger1, trigger2}})
+
+	// Only the dirty broker is rebuilt, the other broker isn't added until it's marked dirty too.
+	setListers(r, bc, broker, trigger1, otherBroker, getConfigMap())
+	r.targetsCache.markDirty(types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name},
+		cellTenantKey{cellTenantType: config.CellTenantType_BROKER, NamespacedName: types.NamespacedName{Namespace: testNS, Name: "broker"}})
+	if _, err := r.reconcileConfig(ctx, bc); err != nil {
+		t.Fatalf("reconcileConfig() = %v", err)
+	}
+	wantTargets(map[*brokerv1beta1.Broker][]*brokerv1beta1.Trigger{broker: {trigger1}})
+
+	// An unchanged targets config isn't written again.
+	setListers(r, bc, broker, trigger1, otherBroker, getConfigMap())
+	client.ClearActions()
+	if _, err := r.reconcileConfig(ctx, bc); err != nil {
+		t.Fatalf("reconcileConfig() = %v", err)
+	}
+	for _, action := range client.Actions() {
+		if action.Matches("update", "configmaps") || action.Matches("create", "configmaps") {
+			t.Errorf("Unexpected action on an unchanged targets config: %v", action)
+		}
+	}
+}
+
 func uri(uri string) *apis.URL {
 	url, _ := apis.ParseURL(uri)
 	return url
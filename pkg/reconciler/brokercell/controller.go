@@ -27,7 +27,9 @@ import (
 	"go.uber.org/zap"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
 	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
 	brokercellinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell"
@@ -36,7 +38,6 @@ import (
 	"github.com/google/knative-gcp/pkg/logging"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/reconciler"
-	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 	customresourceutil "github.com/google/knative-gcp/pkg/utils/customresource"
@@ -55,6 +56,7 @@ import (
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
 	systemnamespacesecretinformer "knative.dev/pkg/injection/clients/namespacedkube/informers/core/v1/secret"
+	"knative.dev/pkg/resolver"
 	"knative.dev/pkg/system"
 )
 
@@ -102,6 +104,18 @@ func NewController(
 		logger.Fatal("Failed to create BrokerCell reconciler", zap.Error(err))
 	}
 	impl := v1alpha1brokercell.NewImpl(ctx, r)
+	r.uriResolver = resolver.NewURIResolver(ctx, func(key types.NamespacedName) {
+		// The tracked dead letter sink is used by the Trigger, so rebuild the entry of its Broker
+		// in the BrokerCell the Broker is assigned to. Only that BrokerCell serves the Trigger.
+		t, err := r.triggerLister.Triggers(key.Namespace).Get(key.Name)
+		if err != nil {
+			return
+		}
+		if brokerCell, tenant, ok := r.triggerCellTenant(t); ok {
+			r.targetsCache.markDirty(brokerCell, tenant)
+			impl.EnqueueKey(brokerCell)
+		}
+	})
 
 	var latencyReporter *metrics.BrokerCellLatencyReporter
 	if r.env.InternalMetricsEnabled {
@@ -111,37 +125,56 @@ func NewController(
 		}
 	}
 
+	if r.targetsConfigReporter, err = metrics.NewTargetsConfigReporter(); err != nil {
+		logger.Error("Failed to create targets config reporter", zap.Error(err))
+	}
+
 	logger.Info("Setting up event handlers.")
 
 	brokerCellInformer.Informer().AddEventHandlerWithResyncPeriod(controller.HandleAll(impl.Enqueue), reconciler.DefaultResyncPeriod)
 	brokerCellLister := brokerCellInformer.Lister()
 
 	// Watch brokers and triggers to invoke configmap update immediately.
-	brokerinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
+	brokerinformer.Get(ctx).Informer().AddEventHandler(handleCellTenantChange(impl, r.targetsCache,
+		func(obj interface{}) (types.NamespacedName, cellTenantKey, bool) {
+			b, ok := obj.(*brokerv1beta1.Broker)
+			if !ok {
+				return types.NamespacedName{}, cellTenantKey{}, false
+			}
+			return brokerCellOf(b), cellTenantKey{cellTenantType: config.CellTenantType_BROKER, NamespacedName: types.NamespacedName{Namespace: b.Namespace, Name: b.Name}}, true
+		},
 		func(obj interface{}) {
 			if b, ok := obj.(*brokerv1beta1.Broker); ok {
-				// TODO(#866) Select the brokercell that's associated with the given broker.
-				impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
 				reportLatency(ctx, b, latencyReporter, "Broker", b.Name, b.Namespace)
 			}
 		},
 	))
-	triggerinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
+	triggerinformer.Get(ctx).Informer().AddEventHandler(handleCellTenantChange(impl, r.targetsCache,
+		func(obj interface{}) (types.NamespacedName, cellTenantKey, bool) {
+			t, ok := obj.(*brokerv1beta1.Trigger)
+			if !ok {
+				return types.NamespacedName{}, cellTenantKey{}, false
+			}
+			return r.triggerCellTenant(t)
+		},
 		func(obj interface{}) {
 			if t, ok := obj.(*brokerv1beta1.Trigger); ok {
-				// TODO(#866) Select the brokercell that's associated with the given broker.
-				impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
 				reportLatency(ctx, t, latencyReporter, "Trigger", t.Name, t.Namespace)
 			}
 		},
 	))
 
 	// Watch GCP Channels and subscriptions on those channels to invoke configmap update immediately.
-	channelinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
+	channelinformer.Get(ctx).Informer().AddEventHandler(handleCellTenantChange(impl, r.targetsCache,
+		func(obj interface{}) (types.NamespacedName, cellTenantKey, bool) {
+			c, ok := obj.(*v1beta1.Channel)
+			if !ok {
+				return types.NamespacedName{}, cellTenantKey{}, false
+			}
+			return brokerCellOf(c), cellTenantKey{cellTenantType: config.CellTenantType_CHANNEL, NamespacedName: types.NamespacedName{Namespace: c.Namespace, Name: c.Name}}, true
+		},
 		func(obj interface{}) {
 			if c, ok := obj.(*v1beta1.Channel); ok {
-				// TODO(#866) Select the brokercell that's associated with the given broker.
-				impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
 				reportLatency(ctx, c, latencyReporter, "Channel", c.Name, c.Namespace)
 			}
 		},
@@ -173,6 +206,50 @@ func NewController(
 	return impl
 }
 
+// handleCellTenantChange returns an event handler for the Brokers, Triggers and Channels, which
+// marks the CellTenant of the changed object dirty in the targets cache of its BrokerCell, and
+// enqueues the BrokerCell to update its targets config.
+func handleCellTenantChange(impl *controller.Impl, tc *targetsCache, keyOf func(obj interface{}) (types.NamespacedName, cellTenantKey, bool), onChange func(obj interface{})) cache.ResourceEventHandler {
+	changed := func(objs ...interface{}) {
+		enqueued := make(map[types.NamespacedName]bool, len(objs))
+		for _, obj := range objs {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if brokerCell, key, ok := keyOf(obj); ok {
+				tc.markDirty(brokerCell, key)
+				if !enqueued[brokerCell] {
+					impl.EnqueueKey(brokerCell)
+					enqueued[brokerCell] = true
+				}
+			}
+		}
+		onChange(objs[len(objs)-1])
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { changed(obj) },
+		// The old object is marked dirty as well, in case the object moved to another CellTenant.
+		UpdateFunc: func(oldObj, newObj interface{}) { changed(oldObj, newObj) },
+		DeleteFunc: func(obj interface{}) { changed(obj) },
+	}
+}
+
+// brokerCellOf returns the key of the BrokerCell that the Broker or Channel is assigned to.
+func brokerCellOf(obj metav1.Object) types.NamespacedName {
+	return types.NamespacedName{Namespace: system.Namespace(), Name: intv1alpha1.BrokerCellNameOf(obj)}
+}
+
+// triggerCellTenant returns the BrokerCell and the CellTenant of the Broker targeted by the
+// Trigger. It returns false if the Broker doesn't exist, as its own deletion already updated its
+// BrokerCell.
+func (r *Reconciler) triggerCellTenant(t *brokerv1beta1.Trigger) (types.NamespacedName, cellTenantKey, bool) {
+	b, err := r.brokerLister.Brokers(t.Namespace).Get(t.Spec.Broker)
+	if err != nil {
+		return types.NamespacedName{}, cellTenantKey{}, false
+	}
+	return brokerCellOf(b), cellTenantKey{cellTenantType: config.CellTenantType_BROKER, NamespacedName: types.NamespacedName{Namespace: t.Namespace, Name: t.Spec.Broker}}, true
+}
+
 // handleResourceUpdate returns an event handler for resources created by brokercell such as the ingress deployment.
 func handleResourceUpdate(impl *controller.Impl) cache.ResourceEventHandler {
 	// Since resources created by brokercell live in the same namespace as the brokercell, we use an
@@ -71,16 +71,31 @@ type IngressArgs struct {
 	Port int
 	// TODO(#1804): remove this field when enabling the feature by default.
 	EnableIngressFilter bool
+	// MaxRequestBodyBytes is the maximum size of the events the ingress accepts. If zero, the
+	// ingress uses its default.
+	MaxRequestBodyBytes int64
+	// ClaimCheckBucket is the GCS bucket the data of large events is offloaded to. If empty,
+	// the data is not offloaded.
+	ClaimCheckBucket string
+	// ClaimCheckThresholdBytes is the data size above which the data is offloaded. If zero,
+	// the ingress uses its default.
+	ClaimCheckThresholdBytes int64
 }
 
 // FanoutArgs are the arguments to create a Broker's fanout Deployment.
 type FanoutArgs struct {
 	Args
+	// ClaimCheckBucket is the GCS bucket the data of large events is read back from. If empty,
+	// the data of offloaded events is not read back.
+	ClaimCheckBucket string
 }
 
 // RetryArgs are the arguments to create a Broker's retry Deployment.
 type RetryArgs struct {
 	Args
+	// ClaimCheckBucket is the GCS bucket the data of large events is read back from. If empty,
+	// the data of offloaded events is not read back.
+	ClaimCheckBucket string
 }
 
 // AutoscalingArgs are the arguments to create HPA for deployments.
@@ -93,6 +108,21 @@ type AutoscalingArgs struct {
 	MinReplicas       int32
 }
 
+// ScaledObjectArgs are the arguments to create the KEDA ScaledObject scaling a
+// BrokerCell component on the backlog of its subscriptions.
+type ScaledObjectArgs struct {
+	ComponentName string
+	BrokerCell    *intv1alpha1.BrokerCell
+	Backlog       *intv1alpha1.BacklogAutoscalingSpec
+	// ProjectID is the project of the Pub/Sub subscriptions.
+	ProjectID string
+	// Subscriptions are the IDs of the Pub/Sub subscriptions the component
+	// pulls from.
+	Subscriptions []string
+	MaxReplicas   int32
+	MinReplicas   int32
+}
+
 // Labels generates the labels present on all resources representing the
 // component of the given BrokerCell.
 func Labels(brokerCellName, componentName string) map[string]string {
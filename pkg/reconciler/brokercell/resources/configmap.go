@@ -17,13 +17,17 @@ limitations under the License.
 package resources
 
 import (
+	"bytes"
 	"fmt"
 
 	"google.golang.org/protobuf/proto"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/ptr"
 
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,6 +35,10 @@ import (
 const (
 	targetsCMName = "broker-targets"
 	targetsCMKey  = "targets"
+
+	// maxTargetsConfigMapBytes is the size above which the data of a targets
+	// ConfigMap doesn't fit in the 1MiB limit of Kubernetes objects.
+	maxTargetsConfigMapBytes = 1000000
 )
 
 // TargetsConfigMapEqual compares the binary data contained in two TargetsConfig
@@ -59,20 +67,113 @@ func TargetsConfigMapEqual(cm1, cm2 *corev1.ConfigMap) bool {
 	return proto.Equal(proto1, proto2)
 }
 
-func MakeTargetsConfig(bc *intv1alpha1.BrokerCell, brokerTargets config.Targets) (*corev1.ConfigMap, error) {
+// TargetsConfigShards returns the number of ConfigMaps the targets config of
+// the BrokerCell is split across.
+func TargetsConfigShards(bc *intv1alpha1.BrokerCell) int {
+	if tc := bc.Spec.TargetsConfig; tc != nil && tc.Shards != nil && *tc.Shards > 1 {
+		return int(*tc.Shards)
+	}
+	return 1
+}
+
+// TargetsConfigMapName returns the name of the ConfigMap of the given shard of
+// the targets config of the BrokerCell.
+func TargetsConfigMapName(brokerCellName string, shard int) string {
+	if shard == 0 {
+		return Name(brokerCellName, targetsCMName)
+	}
+	return Name(brokerCellName, fmt.Sprintf("%s-%d", targetsCMName, shard))
+}
+
+// TargetsConfigLabels returns the labels of the targets ConfigMaps of the
+// BrokerCell.
+func TargetsConfigLabels(brokerCellName string) map[string]string {
+	return Labels(brokerCellName, targetsCMName)
+}
+
+// SplitTargets splits the targets into the shards of the BrokerCell's targets
+// config. Each CellTenant is stored in the shard picked by config.Shard.
+func SplitTargets(bc *intv1alpha1.BrokerCell, brokerTargets config.Targets) []config.Targets {
+	tc := &config.TargetsConfig{CellTenants: make(map[string]*config.CellTenant)}
+	brokerTargets.RangeCellTenants(func(ct *config.CellTenant) bool {
+		tc.CellTenants[ct.Key().PersistenceString()] = ct
+		return true
+	})
+	split := config.SplitTargetsConfig(tc, TargetsConfigShards(bc))
+	shards := make([]config.Targets, 0, len(split))
+	for _, shard := range split {
+		shards = append(shards, memory.NewTargets(shard))
+	}
+	return shards
+}
+
+// MakeTargetsConfigs splits the targets into the ConfigMaps of the shards of
+// the BrokerCell's targets config.
+func MakeTargetsConfigs(bc *intv1alpha1.BrokerCell, brokerTargets config.Targets) ([]*corev1.ConfigMap, error) {
+	shards := SplitTargets(bc, brokerTargets)
+	cms := make([]*corev1.ConfigMap, 0, len(shards))
+	for i, shard := range shards {
+		cm, err := MakeTargetsConfig(bc, i, shard)
+		if err != nil {
+			return nil, err
+		}
+		cms = append(cms, cm)
+	}
+	return cms, nil
+}
+
+// MakeTargetsConfig makes the ConfigMap of the given shard of the BrokerCell's
+// targets config.
+func MakeTargetsConfig(bc *intv1alpha1.BrokerCell, shard int, brokerTargets config.Targets) (*corev1.ConfigMap, error) {
 	data, err := brokerTargets.Bytes()
 	if err != nil {
 		return nil, fmt.Errorf("error serializing targets config: %w", err)
 	}
+	if len(data) > maxTargetsConfigMapBytes {
+		return nil, fmt.Errorf("shard %d of the targets config is %d bytes, more than the %d bytes a ConfigMap can hold; increase spec.targetsConfig.shards", shard, len(data), maxTargetsConfigMapBytes)
+	}
+	// Write out the text version for debugging purposes only, if it fits.
+	var debugData map[string]string
+	if debug := brokerTargets.DebugString(); len(data)+len(debug) <= maxTargetsConfigMapBytes {
+		debugData = map[string]string{"debugOnlyTargets.txt": debug}
+	}
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            Name(bc.Name, targetsCMName),
+			Name:            TargetsConfigMapName(bc.Name, shard),
 			Namespace:       bc.Namespace,
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(bc)},
-			Labels:          Labels(bc.Name, "broker-targets"),
+			Labels:          TargetsConfigLabels(bc.Name),
 		},
 		BinaryData: map[string][]byte{targetsCMKey: data},
-		// Write out the text version for debugging purposes only
-		Data: map[string]string{"debugOnlyTargets.txt": brokerTargets.DebugString()},
+		Data:       debugData,
 	}, nil
 }
+
+// TargetsConfigMapHasData returns true if the targets ConfigMap holds exactly
+// the given serialized targets config. It is a cheap check to skip the
+// ConfigMaps that are up to date, without unmarshaling them.
+func TargetsConfigMapHasData(cm *corev1.ConfigMap, data []byte) bool {
+	current, ok := cm.BinaryData[targetsCMKey]
+	return ok && bytes.Equal(current, data)
+}
+
+// targetsConfigVolumeSource returns the source of the volume the targets
+// config is mounted from. The ConfigMaps of all the shards are projected into
+// the same volume, so that the data plane loads them atomically.
+func targetsConfigVolumeSource(bc *intv1alpha1.BrokerCell) corev1.VolumeSource {
+	shards := TargetsConfigShards(bc)
+	if shards == 1 {
+		return corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: TargetsConfigMapName(bc.Name, 0)}}}
+	}
+	sources := make([]corev1.VolumeProjection, 0, shards)
+	for i := 0; i < shards; i++ {
+		// The shards other than the first are optional, so that the pods can start before the
+		// controller creates them.
+		sources = append(sources, corev1.VolumeProjection{ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: TargetsConfigMapName(bc.Name, i)},
+			Items:                []corev1.KeyToPath{{Key: targetsCMKey, Path: volume.ShardPath(targetsCMKey, i)}},
+			Optional:             ptr.Bool(i > 0),
+		}})
+	}
+	return corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: sources}}
+}
@@ -21,12 +21,14 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/ptr"
 	_ "knative.dev/pkg/system/testing"
 )
 
@@ -109,9 +111,113 @@ func TestTargetsConfigMapEqual(t *testing.T) {
 	}
 }
 
-func TestMakeTargetsConfig(t *testing.T) {
-	_, err := MakeTargetsConfig(NewBrokerCell("name", "ns"), memory.NewEmptyTargets())
+func TestMakeTargetsConfigs(t *testing.T) {
+	targets := &config.TargetsConfig{CellTenants: make(map[string]*config.CellTenant)}
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("broker-%d", i)
+		targets.CellTenants["ns/"+name] = &config.CellTenant{
+			Type:      config.CellTenantType_BROKER,
+			Name:      name,
+			Namespace: "ns",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		bc        *intv1alpha1.BrokerCell
+		wantNames []string
+	}{{
+		name:      "single shard",
+		bc:        NewBrokerCell("name", "ns"),
+		wantNames: []string{"name-brokercell-broker-targets"},
+	}, {
+		name: "three shards",
+		bc:   NewBrokerCell("name", "ns", WithBrokerCellTargetsConfigShards(3)),
+		wantNames: []string{
+			"name-brokercell-broker-targets",
+			"name-brokercell-broker-targets-1",
+			"name-brokercell-broker-targets-2",
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cms, err := MakeTargetsConfigs(test.bc, memory.NewTargets(targets))
+			if err != nil {
+				t.Fatalf("Error making TargetsConfigs: %v", err)
+			}
+			var gotNames []string
+			shards := make([]*config.TargetsConfig, 0, len(cms))
+			for _, cm := range cms {
+				gotNames = append(gotNames, cm.Name)
+				if diff := cmp.Diff(TargetsConfigLabels("name"), cm.Labels); diff != "" {
+					t.Errorf("Unexpected labels (-want +got): %s", diff)
+				}
+				shard := &config.TargetsConfig{}
+				if err := proto.Unmarshal(cm.BinaryData[targetsCMKey], shard); err != nil {
+					t.Fatalf("Error unmarshaling shard %s: %v", cm.Name, err)
+				}
+				shards = append(shards, shard)
+			}
+			if diff := cmp.Diff(test.wantNames, gotNames); diff != "" {
+				t.Errorf("Unexpected ConfigMap names (-want +got): %s", diff)
+			}
+			if merged := config.MergeTargetsConfigs(shards...); !proto.Equal(targets, merged) {
+				t.Errorf("Unexpected merged targets config, got %v, want %v", merged, targets)
+			}
+		})
+	}
+}
+
+func TestMakeTargetsConfigsTooLarge(t *testing.T) {
+	ct := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Name:      "broker",
+		Namespace: "ns",
+		Targets:   make(map[string]*config.Target),
+	}
+	for i := 0; i < 10000; i++ {
+		name := fmt.Sprintf("trigger-%d", i)
+		ct.Targets[name] = &config.Target{Name: name, Namespace: "ns", Address: "http://" + name + ".ns.svc.cluster.local"}
+	}
+	targets := &config.TargetsConfig{CellTenants: map[string]*config.CellTenant{"ns/broker": ct}}
+
+	cms, err := MakeTargetsConfigs(NewBrokerCell("name", "ns"), memory.NewTargets(targets))
 	if err != nil {
-		t.Errorf("Error making TargetsConfig: %v", err)
+		t.Fatalf("Error making TargetsConfigs: %v", err)
+	}
+	if _, ok := cms[0].Data["debugOnlyTargets.txt"]; ok {
+		t.Errorf("Debug targets should be left out of a ConfigMap that can't hold them")
+	}
+
+	for i := 10000; i < 20000; i++ {
+		name := fmt.Sprintf("trigger-%d", i)
+		ct.Targets[name] = &config.Target{Name: name, Namespace: "ns", Address: "http://" + name + ".ns.svc.cluster.local"}
+	}
+	if _, err := MakeTargetsConfigs(NewBrokerCell("name", "ns"), memory.NewTargets(targets)); err == nil {
+		t.Errorf("Expected an error for a shard larger than a ConfigMap can hold")
+	}
+}
+
+func TestTargetsConfigVolumeSource(t *testing.T) {
+	if got := targetsConfigVolumeSource(NewBrokerCell("name", "ns")); got.ConfigMap == nil || got.ConfigMap.Name != "name-brokercell-broker-targets" {
+		t.Errorf("Unexpected volume source for a single shard: %v", got)
+	}
+
+	got := targetsConfigVolumeSource(NewBrokerCell("name", "ns", WithBrokerCellTargetsConfigShards(2)))
+	want := corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{{
+		ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "name-brokercell-broker-targets"},
+			Items:                []corev1.KeyToPath{{Key: "targets", Path: "targets"}},
+			Optional:             ptr.Bool(false),
+		},
+	}, {
+		ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "name-brokercell-broker-targets-1"},
+			Items:                []corev1.KeyToPath{{Key: "targets", Path: "targets-1"}},
+			Optional:             ptr.Bool(true),
+		},
+	}}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected volume source (-want +got): %s", diff)
 	}
 }
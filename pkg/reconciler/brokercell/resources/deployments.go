@@ -43,6 +43,25 @@ func MakeIngressDeployment(args IngressArgs) *appsv1.Deployment {
 		Value: strconv.FormatBool(args.EnableIngressFilter),
 	})
 
+	if args.MaxRequestBodyBytes > 0 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "MAX_REQUEST_BODY_BYTES",
+			Value: strconv.FormatInt(args.MaxRequestBodyBytes, 10),
+		})
+	}
+	if args.ClaimCheckBucket != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "CLAIM_CHECK_BUCKET",
+			Value: args.ClaimCheckBucket,
+		})
+	}
+	if args.ClaimCheckThresholdBytes > 0 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "CLAIM_CHECK_THRESHOLD_BYTES",
+			Value: strconv.FormatInt(args.ClaimCheckThresholdBytes, 10),
+		})
+	}
+
 	container.Ports = append(container.Ports, corev1.ContainerPort{Name: "http", ContainerPort: int32(args.Port)})
 	container.ReadinessProbe = &corev1.Probe{
 		Handler: corev1.Handler{
@@ -89,6 +108,12 @@ func MakeFanoutDeployment(args FanoutArgs) *appsv1.Deployment {
 		Name:  "MAX_CONCURRENCY_PER_EVENT",
 		Value: "100",
 	})
+	if args.ClaimCheckBucket != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "CLAIM_CHECK_BUCKET",
+			Value: args.ClaimCheckBucket,
+		})
+	}
 	container.LivenessProbe = &corev1.Probe{
 		Handler: corev1.Handler{
 			HTTPGet: &corev1.HTTPGetAction{
@@ -109,6 +134,12 @@ func MakeFanoutDeployment(args FanoutArgs) *appsv1.Deployment {
 // MakeRetryDeployment creates the retry Deployment object.
 func MakeRetryDeployment(args RetryArgs) *appsv1.Deployment {
 	container := containerTemplate(args.Args)
+	if args.ClaimCheckBucket != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "CLAIM_CHECK_BUCKET",
+			Value: args.ClaimCheckBucket,
+		})
+	}
 	container.Resources = resourceutil.BuildResourceRequirements(args.CPURequest, args.CPULimit, args.MemoryRequest, args.MemoryLimit)
 	container.Ports = append(container.Ports,
 		corev1.ContainerPort{
@@ -167,7 +198,7 @@ func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deploy
 					Volumes: []corev1.Volume{
 						{
 							Name:         "broker-config",
-							VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: Name(args.BrokerCell.Name, targetsCMName)}}},
+							VolumeSource: targetsConfigVolumeSource(args.BrokerCell),
 						},
 						{
 							Name:         "google-broker-key",
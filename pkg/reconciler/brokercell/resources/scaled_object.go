@@ -0,0 +1,139 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/kmeta"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+)
+
+var (
+	// ScaledObjectGVK is the GroupVersionKind of the KEDA 2 ScaledObjects. Unlike
+	// the ones of the KEDA PullSubscriptions, they can scale on the age of the
+	// oldest unacked message.
+	ScaledObjectGVK = schema.GroupVersionKind{
+		Group:   "keda.sh",
+		Version: "v1alpha1",
+		Kind:    "ScaledObject",
+	}
+
+	KedaSchemeGroupVersion = ScaledObjectGVK.GroupVersion()
+)
+
+// ScaledObjectName returns the name of the ScaledObject scaling the deployment.
+func ScaledObjectName(deployment *appsv1.Deployment) string {
+	return deployment.Name + "-scaledobject"
+}
+
+// backlogMetrics are the Cloud Monitoring metric types of the backlog metrics,
+// and how they are aggregated across the subscriptions of a component: the
+// undelivered messages are summed, and the oldest unacked message is the oldest
+// of all the subscriptions.
+var backlogMetrics = map[intv1alpha1.BacklogMetric]struct {
+	metricType string
+	reducer    string
+}{
+	intv1alpha1.UndeliveredMessagesBacklogMetric:     {"pubsub.googleapis.com/subscription/num_undelivered_messages", "sum"},
+	intv1alpha1.OldestUnackedMessageAgeBacklogMetric: {"pubsub.googleapis.com/subscription/oldest_unacked_message_age", "max"},
+}
+
+// maxBacklogFilterLength bounds the length of the filter of a trigger, keeping it within the
+// limits of Cloud Monitoring. The subscriptions of a large component are split across several
+// triggers.
+const maxBacklogFilterLength = 2048
+
+// backlogFilters returns the Cloud Monitoring filters selecting the metric of the subscriptions,
+// each selecting as many subscriptions as fit in maxBacklogFilterLength.
+func backlogFilters(metricType string, subscriptions []string) []string {
+	prefix := fmt.Sprintf(`metric.type=%q AND resource.type="pubsub_subscription" AND resource.labels.subscription_id=one_of(`, metricType)
+	var filters []string
+	var quoted []string
+	length := len(prefix) + len(")")
+	for _, s := range subscriptions {
+		q := strconv.Quote(s)
+		if len(quoted) > 0 && length+len(",")+len(q) > maxBacklogFilterLength {
+			filters = append(filters, prefix+strings.Join(quoted, ",")+")")
+			quoted = nil
+			length = len(prefix) + len(")")
+		}
+		if len(quoted) > 0 {
+			length += len(",")
+		}
+		quoted = append(quoted, q)
+		length += len(q)
+	}
+	return append(filters, prefix+strings.Join(quoted, ",")+")")
+}
+
+// MakeScaledObject makes a ScaledObject scaling the deployment with gcp-stackdriver triggers on
+// the backlog metric aggregated across the subscriptions in the arguments, so that the replicas
+// follow the backlog of the whole component rather than the largest backlog of a single
+// subscription. The subscriptions are split across several triggers if they don't fit in a
+// single filter, in which case KEDA scales on the trigger with the largest backlog.
+func MakeScaledObject(deployment *appsv1.Deployment, args ScaledObjectArgs) *unstructured.Unstructured {
+	metric := backlogMetrics[args.Backlog.Metric]
+	var triggers []interface{}
+	for _, filter := range backlogFilters(metric.metricType, args.Subscriptions) {
+		triggers = append(triggers, map[string]interface{}{
+			"type": "gcp-stackdriver",
+			"authenticationRef": map[string]interface{}{
+				"name": args.Backlog.TriggerAuthentication,
+			},
+			"metadata": map[string]interface{}{
+				"projectId":              args.ProjectID,
+				"filter":                 filter,
+				"targetValue":            strconv.FormatInt(*args.Backlog.Target, 10),
+				"alignmentPeriodSeconds": "60",
+				"alignmentAligner":       "max",
+				"alignmentReducer":       metric.reducer,
+			},
+		})
+	}
+
+	// Using Unstructured instead of adding the Keda dependency, like the KEDA PullSubscriptions.
+	so := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"scaleTargetRef": map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"name":       deployment.Name,
+				},
+				"minReplicaCount": int64(args.MinReplicas),
+				"maxReplicaCount": int64(args.MaxReplicas),
+				"pollingInterval": int64(*args.Backlog.PollingIntervalSeconds),
+				"cooldownPeriod":  int64(*args.Backlog.CooldownPeriodSeconds),
+				"triggers":        triggers,
+			},
+		},
+	}
+	so.SetGroupVersionKind(ScaledObjectGVK)
+	so.SetNamespace(deployment.Namespace)
+	so.SetName(ScaledObjectName(deployment))
+	so.SetLabels(Labels(args.BrokerCell.Name, args.ComponentName))
+	so.SetOwnerReferences([]metav1.OwnerReference{*kmeta.NewControllerRef(args.BrokerCell)})
+	return so
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/ptr"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+)
+
+func TestMakeScaledObject(t *testing.T) {
+	bc := &intv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "events-system", UID: "uid"},
+	}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-brokercell-fanout", Namespace: "events-system"},
+	}
+	got := MakeScaledObject(d, ScaledObjectArgs{
+		ComponentName: FanoutName,
+		BrokerCell:    bc,
+		Backlog: &intv1alpha1.BacklogAutoscalingSpec{
+			Metric:                 intv1alpha1.OldestUnackedMessageAgeBacklogMetric,
+			Target:                 ptr.Int64(30),
+			TriggerAuthentication:  "keda-gcp",
+			PollingIntervalSeconds: ptr.Int32(15),
+			CooldownPeriodSeconds:  ptr.Int32(120),
+		},
+		ProjectID:     "test-project",
+		Subscriptions: []string{"sub-1", "sub-2"},
+		MaxReplicas:   10,
+		MinReplicas:   1,
+	})
+
+	trigger := map[string]interface{}{
+		"type":              "gcp-stackdriver",
+		"authenticationRef": map[string]interface{}{"name": "keda-gcp"},
+		"metadata": map[string]interface{}{
+			"projectId": "test-project",
+			"filter": `metric.type="pubsub.googleapis.com/subscription/oldest_unacked_message_age" AND ` +
+				`resource.type="pubsub_subscription" AND resource.labels.subscription_id=one_of("sub-1","sub-2")`,
+			"targetValue":            "30",
+			"alignmentPeriodSeconds": "60",
+			"alignmentAligner":       "max",
+			"alignmentReducer":       "max",
+		},
+	}
+	want := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "keda.sh/v1alpha1",
+			"kind":       "ScaledObject",
+			"metadata": map[string]interface{}{
+				"namespace": "events-system",
+				"name":      "default-brokercell-fanout-scaledobject",
+				"labels": map[string]interface{}{
+					"app":        "events-system",
+					"brokerCell": "default",
+					"role":       "fanout",
+				},
+				"ownerReferences": []interface{}{
+					map[string]interface{}{
+						"apiVersion":         "internal.events.cloud.google.com/v1alpha1",
+						"kind":               "BrokerCell",
+						"name":               "default",
+						"uid":                "uid",
+						"controller":         true,
+						"blockOwnerDeletion": true,
+					},
+				},
+			},
+			"spec": map[string]interface{}{
+				"scaleTargetRef": map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"name":       "default-brokercell-fanout",
+				},
+				"minReplicaCount": int64(1),
+				"maxReplicaCount": int64(10),
+				"pollingInterval": int64(15),
+				"cooldownPeriod":  int64(120),
+				"triggers":        []interface{}{trigger},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Unexpected ScaledObject (-want, +got):", diff)
+	}
+}
+
+func TestMakeScaledObjectSplitsLargeCells(t *testing.T) {
+	bc := &intv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "events-system", UID: "uid"},
+	}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-brokercell-retry", Namespace: "events-system"},
+	}
+	var subscriptions []string
+	for i := 0; i < 1000; i++ {
+		subscriptions = append(subscriptions, fmt.Sprintf("cre-tgr_namespace-%d_trigger-%d_01234567-89ab-cdef-0123-456789abcdef", i%10, i))
+	}
+	so := MakeScaledObject(d, ScaledObjectArgs{
+		ComponentName: RetryName,
+		BrokerCell:    bc,
+		Backlog: &intv1alpha1.BacklogAutoscalingSpec{
+			Metric:                 intv1alpha1.UndeliveredMessagesBacklogMetric,
+			Target:                 ptr.Int64(100),
+			TriggerAuthentication:  "keda-gcp",
+			PollingIntervalSeconds: ptr.Int32(15),
+			CooldownPeriodSeconds:  ptr.Int32(120),
+		},
+		ProjectID:     "test-project",
+		Subscriptions: subscriptions,
+		MaxReplicas:   10,
+		MinReplicas:   1,
+	})
+
+	triggers, _, err := unstructured.NestedSlice(so.Object, "spec", "triggers")
+	if err != nil {
+		t.Fatal("Failed to get the triggers:", err)
+	}
+	if len(triggers) < 2 {
+		t.Fatalf("Got %d triggers, want the subscriptions split across several", len(triggers))
+	}
+	subscriptionID := regexp.MustCompile(`"([^"]+)"`)
+	var got []string
+	for _, trigger := range triggers {
+		filter, _, _ := unstructured.NestedString(trigger.(map[string]interface{}), "metadata", "filter")
+		if len(filter) > maxBacklogFilterLength {
+			t.Errorf("Filter has length %d, want at most %d", len(filter), maxBacklogFilterLength)
+		}
+		const prefix = `metric.type="pubsub.googleapis.com/subscription/num_undelivered_messages" AND ` +
+			`resource.type="pubsub_subscription" AND resource.labels.subscription_id=one_of(`
+		if !strings.HasPrefix(filter, prefix) {
+			t.Fatalf("Unexpected filter %q", filter)
+		}
+		for _, m := range subscriptionID.FindAllStringSubmatch(filter[len(prefix):], -1) {
+			got = append(got, m[1])
+		}
+	}
+	if diff := cmp.Diff(subscriptions, got); diff != "" {
+		t.Error("Unexpected subscriptions (-want, +got):", diff)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	hpav2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+type discoverFunc func(discovery.DiscoveryInterface, schema.GroupVersion) error
+
+// backlogSubscriptions returns the sorted IDs of the ready subscriptions the fanout and the retry
+// pull from: the decouple subscriptions of the CellTenants, and the retry subscriptions of their
+// targets.
+func backlogSubscriptions(targets config.ReadonlyTargets) (decouple []string, retry []string) {
+	targets.RangeCellTenants(func(ct *config.CellTenant) bool {
+		if q := ct.GetDecoupleQueue(); q.GetState() == config.State_READY && q.GetSubscription() != "" {
+			decouple = append(decouple, q.GetSubscription())
+		}
+		for _, t := range ct.GetTargets() {
+			if q := t.GetRetryQueue(); t.GetState() == config.State_READY && q.GetSubscription() != "" {
+				retry = append(retry, q.GetSubscription())
+			}
+		}
+		return true
+	})
+	sort.Strings(decouple)
+	sort.Strings(retry)
+	return decouple, retry
+}
+
+// makeScaledObjectArgs returns the arguments of the ScaledObject of the component, or nil if the
+// component is scaled by its HPA. Components without any subscription to scale on keep their HPA.
+func (r *Reconciler) makeScaledObjectArgs(bc *intv1alpha1.BrokerCell, componentName string, params *intv1alpha1.ComponentParameters, subscriptions []string) (*resources.ScaledObjectArgs, error) {
+	as := bc.Spec.Autoscaling
+	if as == nil || as.Class != intv1alpha1.BacklogAutoscalingClass || as.Backlog == nil || len(subscriptions) == 0 {
+		return nil, nil
+	}
+	projectID, err := utils.ProjectIDOrDefault(r.projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the project of the subscriptions: %w", err)
+	}
+	return &resources.ScaledObjectArgs{
+		ComponentName: componentName,
+		BrokerCell:    bc,
+		Backlog:       as.Backlog,
+		ProjectID:     projectID,
+		Subscriptions: subscriptions,
+		MaxReplicas:   *params.MaxReplicas,
+		MinReplicas:   *params.MinReplicas,
+	}, nil
+}
+
+// reconcileScaledObject makes sure the ScaledObject is the only autoscaler of its deployment, by
+// deleting the HPA of the deployment once the ScaledObject is reconciled.
+func (r *Reconciler) reconcileScaledObject(ctx context.Context, bc *intv1alpha1.BrokerCell, desired *unstructured.Unstructured, hpa *hpav2beta2.HorizontalPodAutoscaler) error {
+	// Ideally this should be checked in the webhook, like for the KEDA PullSubscriptions.
+	if err := r.discoveryFn(r.KubeClientSet.Discovery(), resources.KedaSchemeGroupVersion); err != nil {
+		return fmt.Errorf("failed to check whether KEDA is installed: %w", err)
+	}
+	client := r.scaledObjectClient(desired.GetNamespace())
+	existing, err := client.Get(ctx, desired.GetName(), metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		if _, err := client.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(bc, corev1.EventTypeNormal, "ScaledObjectCreated", "Created ScaledObject %s/%s", desired.GetNamespace(), desired.GetName())
+		return r.deleteHorizontalPodAutoscaler(ctx, bc, hpa)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !equality.Semantic.DeepDerivative(desired.Object["spec"], existing.Object["spec"]) {
+		existing.Object["spec"] = desired.Object["spec"]
+		if _, err := client.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(bc, corev1.EventTypeNormal, "ScaledObjectUpdated", "Updated ScaledObject %s/%s", desired.GetNamespace(), desired.GetName())
+	}
+	return r.deleteHorizontalPodAutoscaler(ctx, bc, hpa)
+}
+
+// deleteScaledObject deletes the ScaledObject of the deployment scaled by the HPA, if any.
+func (r *Reconciler) deleteScaledObject(ctx context.Context, bc *intv1alpha1.BrokerCell, namespace, name string) error {
+	client := r.scaledObjectClient(namespace)
+	// Without KEDA, the ScaledObjects are not found either.
+	if _, err := client.Get(ctx, name, metav1.GetOptions{}); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	r.Recorder.Eventf(bc, corev1.EventTypeNormal, "ScaledObjectDeleted", "Deleted ScaledObject %s/%s", namespace, name)
+	return nil
+}
+
+func (r *Reconciler) deleteHorizontalPodAutoscaler(ctx context.Context, bc *intv1alpha1.BrokerCell, hpa *hpav2beta2.HorizontalPodAutoscaler) error {
+	if _, err := r.hpaLister.HorizontalPodAutoscalers(hpa.Namespace).Get(hpa.Name); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := r.KubeClientSet.AutoscalingV2beta2().HorizontalPodAutoscalers(hpa.Namespace).Delete(ctx, hpa.Name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	r.Recorder.Eventf(bc, corev1.EventTypeNormal, "HorizontalPodAutoscalerDeleted", "Deleted HPA %s/%s", hpa.Namespace, hpa.Name)
+	return nil
+}
+
+func (r *Reconciler) scaledObjectClient(namespace string) dynamic.ResourceInterface {
+	gvr, _ := meta.UnsafeGuessKindToResource(resources.ScaledObjectGVK)
+	return r.DynamicClientSet.Resource(gvr).Namespace(namespace)
+}
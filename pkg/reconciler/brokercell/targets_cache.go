@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+)
+
+// cellTenantKey identifies the Broker or Channel of a CellTenant.
+type cellTenantKey struct {
+	cellTenantType config.CellTenantType
+	types.NamespacedName
+}
+
+// targetsCache keeps the targets config of each BrokerCell in memory between reconciliations, so
+// that only the CellTenants whose Broker, Triggers or Channel changed since the last
+// reconciliation are rebuilt.
+type targetsCache struct {
+	mux   sync.Mutex
+	cells map[types.NamespacedName]*cellTargets
+}
+
+// cellTargets is the targets config of a BrokerCell, along with the CellTenants to rebuild.
+type cellTargets struct {
+	uid types.UID
+	// generation is the generation of the BrokerCell when the targets were rebuilt. Its spec
+	// decides which CellTenants it serves, so they are all rebuilt when it changes.
+	generation int64
+	targets    config.Targets
+	// dirty are the CellTenants to rebuild on the next reconciliation.
+	dirty map[cellTenantKey]bool
+	// stale is true if all the CellTenants must be rebuilt on the next reconciliation.
+	stale bool
+}
+
+func newTargetsCache() *targetsCache {
+	return &targetsCache{cells: make(map[types.NamespacedName]*cellTargets)}
+}
+
+// markDirty marks the CellTenant to be rebuilt on the next reconciliation of the BrokerCell.
+func (c *targetsCache) markDirty(bc types.NamespacedName, key cellTenantKey) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if cell, ok := c.cells[bc]; ok {
+		cell.dirty[key] = true
+	}
+}
+
+// markStale marks all the CellTenants of the BrokerCell to be rebuilt on its next reconciliation.
+func (c *targetsCache) markStale(bc types.NamespacedName) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if cell, ok := c.cells[bc]; ok {
+		cell.stale = true
+	}
+}
+
+// take returns the targets config of the BrokerCell along with the CellTenants to rebuild, and
+// resets them. If the targets config has to be rebuilt from scratch, it returns a new empty one
+// and true.
+func (c *targetsCache) take(bc *intv1alpha1.BrokerCell) (config.Targets, []cellTenantKey, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	key := types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name}
+	cell, ok := c.cells[key]
+	if !ok || cell.stale || cell.uid != bc.UID || cell.generation != bc.Generation {
+		// Start tracking the changes before the BrokerCell is rebuilt, so that none is missed.
+		c.cells[key] = &cellTargets{
+			uid:        bc.UID,
+			generation: bc.Generation,
+			targets:    memory.NewEmptyTargets(),
+			dirty:      make(map[cellTenantKey]bool),
+		}
+		return c.cells[key].targets, nil, true
+	}
+	dirty := make([]cellTenantKey, 0, len(cell.dirty))
+	for k := range cell.dirty {
+		dirty = append(dirty, k)
+	}
+	cell.dirty = make(map[cellTenantKey]bool)
+	return cell.targets, dirty, false
+}
+
+// forget removes the targets config of the BrokerCell.
+func (c *targetsCache) forget(bc types.NamespacedName) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	delete(c.cells, bc)
+}
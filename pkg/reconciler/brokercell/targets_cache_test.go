@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestTargetsCache(t *testing.T) {
+	bc := &intv1alpha1.BrokerCell{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "bc", UID: "uid-1"}}
+	bcKey := types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name}
+	brokerKey := cellTenantKey{cellTenantType: config.CellTenantType_BROKER, NamespacedName: types.NamespacedName{Namespace: "ns", Name: "broker"}}
+	channelKey := cellTenantKey{cellTenantType: config.CellTenantType_CHANNEL, NamespacedName: types.NamespacedName{Namespace: "ns", Name: "broker"}}
+
+	c := newTargetsCache()
+	// Changes to an unknown BrokerCell are ignored since it will be rebuilt anyway.
+	c.markDirty(bcKey, brokerKey)
+	targets, dirty, rebuild := c.take(bc)
+	if !rebuild || len(dirty) != 0 {
+		t.Errorf("take() of a new BrokerCell = (%v, %v), want (nil, true)", dirty, rebuild)
+	}
+
+	c.markDirty(bcKey, brokerKey)
+	c.markDirty(bcKey, channelKey)
+	c.markDirty(bcKey, brokerKey)
+	got, dirty, rebuild := c.take(bc)
+	if rebuild {
+		t.Error("take() of a cached BrokerCell got a rebuild")
+	}
+	if got != targets {
+		t.Error("take() of a cached BrokerCell got different targets")
+	}
+	want := map[cellTenantKey]bool{brokerKey: true, channelKey: true}
+	gotDirty := make(map[cellTenantKey]bool)
+	for _, k := range dirty {
+		gotDirty[k] = true
+	}
+	if diff := cmp.Diff(want, gotDirty, cmp.AllowUnexported(cellTenantKey{})); diff != "" || len(dirty) != len(want) {
+		t.Errorf("take() dirty keys (-want,+got): %v, got %v", diff, dirty)
+	}
+
+	if _, dirty, rebuild := c.take(bc); rebuild || len(dirty) != 0 {
+		t.Errorf("take() after the dirty keys were taken = (%v, %v), want (nil, false)", dirty, rebuild)
+	}
+
+	c.markStale(bcKey)
+	if _, _, rebuild := c.take(bc); !rebuild {
+		t.Error("take() of a stale BrokerCell didn't get a rebuild")
+	}
+
+	updated := bc.DeepCopy()
+	updated.Generation++
+	if _, _, rebuild := c.take(updated); !rebuild {
+		t.Error("take() of an updated BrokerCell didn't get a rebuild")
+	}
+	if _, _, rebuild := c.take(updated); rebuild {
+		t.Error("take() of a rebuilt BrokerCell got a rebuild")
+	}
+
+	recreated := bc.DeepCopy()
+	recreated.UID = "uid-2"
+	if _, _, rebuild := c.take(recreated); !rebuild {
+		t.Error("take() of a recreated BrokerCell didn't get a rebuild")
+	}
+
+	c.forget(bcKey)
+	if _, _, rebuild := c.take(recreated); !rebuild {
+		t.Error("take() of a forgotten BrokerCell didn't get a rebuild")
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package testingdata
 
 import (
+	"encoding/json"
 	"testing"
 
 	channelresources "github.com/google/knative-gcp/pkg/reconciler/messaging/channel/resources"
@@ -29,12 +30,18 @@ import (
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/celltenant"
+	"github.com/rickb777/date/period"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	corev1 "k8s.io/api/core/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 )
 
 func EmptyConfig(t *testing.T, bc *intv1alpha1.BrokerCell) *corev1.ConfigMap {
-	cm, _ := resources.MakeTargetsConfig(bc, memory.NewEmptyTargets())
-	return cm
+	cms, _ := resources.MakeTargetsConfigs(bc, memory.NewEmptyTargets())
+	return cms[0]
 }
 
 type BrokerCellObjects struct {
@@ -42,7 +49,13 @@ type BrokerCellObjects struct {
 	Channels          []*v1beta1.Channel
 }
 
+// Config returns the targets ConfigMap of a BrokerCell with a single shard.
 func Config(bc *intv1alpha1.BrokerCell, bco BrokerCellObjects) *corev1.ConfigMap {
+	return Configs(bc, bco)[0]
+}
+
+// Configs returns the targets ConfigMaps of all the shards of a BrokerCell.
+func Configs(bc *intv1alpha1.BrokerCell, bco BrokerCellObjects) []*corev1.ConfigMap {
 	targets := &config.TargetsConfig{
 		CellTenants: map[string]*config.CellTenant{},
 	}
@@ -55,8 +68,8 @@ func Config(bc *intv1alpha1.BrokerCell, bco BrokerCellObjects) *corev1.ConfigMap
 	}
 
 	memoryTargets := memory.NewTargets(targets)
-	cm, _ := resources.MakeTargetsConfig(bc, memoryTargets)
-	return cm
+	cms, _ := resources.MakeTargetsConfigs(bc, memoryTargets)
+	return cms
 }
 
 func addBroker(targets *config.TargetsConfig, broker *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger) {
@@ -82,15 +95,99 @@ func addBroker(targets *config.TargetsConfig, broker *brokerv1beta1.Broker, trig
 			Subscription: brokerresources.GenerateDecouplingSubscriptionName(broker),
 			State:        brokerQueueState,
 		},
-		Targets: make(map[string]*config.Target),
-		State:   state,
+		OrderingKeyAttribute: broker.GetOrderingKeyAttribute(),
+		Targets:              make(map[string]*config.Target),
+		State:                state,
+	}
+	if raw, ok := broker.Annotations[brokerv1beta1.IngressPolicyAnnotationKey]; ok {
+		var p struct {
+			Issuers []struct {
+				Issuer     string   `json:"issuer"`
+				JWKSURL    string   `json:"jwksUrl"`
+				Kubernetes bool     `json:"kubernetes"`
+				Audiences  []string `json:"audiences"`
+			} `json:"issuers"`
+			Rules []*config.IngressRule `json:"rules"`
+		}
+		if err := json.Unmarshal([]byte(raw), &p); err != nil {
+			return
+		}
+		brokerConfig.IngressPolicy = &config.IngressPolicy{Rules: p.Rules}
+		for _, issuer := range p.Issuers {
+			brokerConfig.IngressPolicy.Issuers = append(brokerConfig.IngressPolicy.Issuers, &config.IngressIssuer{
+				Issuer:     issuer.Issuer,
+				JwksUrl:    issuer.JWKSURL,
+				Audiences:  issuer.Audiences,
+				Kubernetes: issuer.Kubernetes,
+			})
+		}
 	}
 	for _, trigger := range triggers {
 		var filterAttributes map[string]string
 		if trigger.Spec.Filter != nil && trigger.Spec.Filter.Attributes != nil {
 			filterAttributes = trigger.Spec.Filter.Attributes
 		}
+		var filters []*config.Filter
+		if raw, ok := trigger.Annotations[brokerv1beta1.FiltersAnnotationKey]; ok {
+			if err := json.Unmarshal([]byte(raw), &filters); err != nil {
+				continue
+			}
+		}
+		var transform *config.Transform
+		if raw, ok := trigger.Annotations[brokerv1beta1.TransformAnnotationKey]; ok {
+			var t struct {
+				SetAttributes    map[string]string `json:"setAttributes"`
+				RemoveAttributes []string          `json:"removeAttributes"`
+				DataPatch        json.RawMessage   `json:"dataPatch"`
+			}
+			if err := json.Unmarshal([]byte(raw), &t); err != nil {
+				continue
+			}
+			transform = &config.Transform{
+				SetAttributes:    t.SetAttributes,
+				RemoveAttributes: t.RemoveAttributes,
+				DataPatch:        string(t.DataPatch),
+			}
+		}
+		var batching *config.Batching
+		if raw, ok := trigger.Annotations[brokerv1beta1.BatchingAnnotationKey]; ok {
+			var b struct {
+				MaxCount int32  `json:"maxCount"`
+				MaxBytes int64  `json:"maxBytes"`
+				Linger   string `json:"linger"`
+			}
+			if err := json.Unmarshal([]byte(raw), &b); err != nil {
+				continue
+			}
+			batching = &config.Batching{MaxCount: b.MaxCount, MaxBytes: b.MaxBytes}
+			if b.Linger != "" {
+				d, _ := period.MustParse(b.Linger).Duration()
+				batching.Linger = durationpb.New(d)
+			}
+		}
+		var rateLimit *config.RateLimit
+		if raw, ok := trigger.Annotations[brokerv1beta1.RateLimitAnnotationKey]; ok {
+			rateLimit = &config.RateLimit{}
+			if err := protojson.Unmarshal([]byte(raw), rateLimit); err != nil {
+				continue
+			}
+		}
+		var replayQueue *config.Queue
+		var replayEndTime *timestamppb.Timestamp
+		if trigger.Status.IsReplayReady() {
+			replayQueue = &config.Queue{
+				Topic:        brokerresources.GenerateDecouplingTopicName(broker),
+				Subscription: brokerresources.GenerateReplaySubscriptionName(trigger),
+			}
+			if end, ok := trigger.Status.GetReplayEndTime(); ok {
+				replayEndTime = timestamppb.New(end)
+			}
+		}
 		brokerConfig.Targets[trigger.Name] = &config.Target{
+			DeliverySpec:   deliverySpec(broker, trigger),
+			Transform:      transform,
+			Batching:       batching,
+			RateLimit:      rateLimit,
 			Id:             string(trigger.UID),
 			Name:           trigger.Name,
 			Namespace:      trigger.Namespace,
@@ -102,13 +199,44 @@ func addBroker(targets *config.TargetsConfig, broker *brokerv1beta1.Broker, trig
 				Topic:        brokerresources.GenerateRetryTopicName(trigger),
 				Subscription: brokerresources.GenerateRetrySubscriptionName(trigger),
 			},
+			ReplayQueue:      replayQueue,
+			ReplayEndTime:    replayEndTime,
 			State:            state,
 			FilterAttributes: filterAttributes,
+			Filters:          filters,
 		}
 	}
 	targets.CellTenants[brokerConfig.Key().PersistenceString()] = brokerConfig
 }
 
+// deliverySpec only supports dead letter sinks specified as absolute URIs.
+func deliverySpec(broker *brokerv1beta1.Broker, trigger *brokerv1beta1.Trigger) *config.DeliverySpec {
+	ds := celltenant.TriggerDeliverySpec(trigger, broker.Spec.Delivery)
+	if ds == nil {
+		return nil
+	}
+	spec := &config.DeliverySpec{}
+	if ds.BackoffPolicy != nil && *ds.BackoffPolicy == eventingduckv1beta1.BackoffPolicyLinear {
+		spec.BackoffPolicy = config.BackoffPolicy_LINEAR
+	}
+	if ds.BackoffDelay != nil {
+		p := period.MustParse(*ds.BackoffDelay)
+		d, _ := p.Duration()
+		spec.BackoffDelay = durationpb.New(d)
+	}
+	if ds.DeadLetterSink == nil {
+		return spec
+	}
+	if ds.DeadLetterSink.URI.Scheme == "pubsub" {
+		return nil
+	}
+	spec.DeadLetterAddress = ds.DeadLetterSink.URI.String()
+	if ds.Retry != nil {
+		spec.Retry = *ds.Retry
+	}
+	return spec
+}
+
 func addChannel(targets *config.TargetsConfig, channel *v1beta1.Channel) {
 	if channel == nil {
 		return
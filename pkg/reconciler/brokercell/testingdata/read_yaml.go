@@ -38,6 +38,18 @@ func IngressDeploymentWithFilteringAnnotation(t *testing.T) *appsv1.Deployment {
 	return getDeployment(t, "testingdata/ingress_deployment_with_filtering_annotation.yaml")
 }
 
+func IngressDeploymentWithClaimCheck(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/ingress_deployment_with_claim_check.yaml")
+}
+
+func FanoutDeploymentWithClaimCheck(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/fanout_deployment_with_claim_check.yaml")
+}
+
+func RetryDeploymentWithClaimCheck(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/retry_deployment_with_claim_check.yaml")
+}
+
 func FanoutDeployment(t *testing.T) *appsv1.Deployment {
 	return getDeployment(t, "testingdata/fanout_deployment.yaml")
 }
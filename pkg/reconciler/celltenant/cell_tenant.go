@@ -139,8 +139,9 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 	// Check if PullSub exists, and if not, create it.
 	subID := b.GetSubscriptionName()
 	subConfig := pubsub.SubscriptionConfig{
-		Topic:  topic,
-		Labels: b.GetLabels(),
+		Topic:                 topic,
+		Labels:                b.GetLabels(),
+		EnableMessageOrdering: b.MessageOrdering(),
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
@@ -208,13 +209,15 @@ func (r *Reconciler) getClientOrCreateNew(ctx context.Context, projectID string,
 	return client, nil
 }
 
-// ensureBrokerCellExists creates a BrokerCell if it doesn't exist, and update broker status based on brokercell status.
+// ensureBrokerCellExists creates the default BrokerCell if it doesn't exist, and updates the
+// status of the CellTenant based on the status of its BrokerCell. The other BrokerCells are
+// created by the cluster operator, and only serve the CellTenants of the namespaces they allow.
 func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, s Statusable) error {
 	var bc *inteventsv1alpha1.BrokerCell
 	var err error
-	// TODO(#866) Get brokercell based on the label (or annotation) on the broker.
 	bcNS := system.Namespace()
-	bcName := resources.DefaultBrokerCellName
+	bcName := s.BrokerCellName()
+	s.SetBrokerCell(bcName)
 	bc, err = r.BrokerCellLister.BrokerCells(bcNS).Get(bcName)
 	if err != nil && !apierrs.IsNotFound(err) {
 		logging.FromContext(ctx).Error("Error getting BrokerCell", zap.String("namespace", bcNS), zap.String("brokerCell", bcName), zap.Error(err))
@@ -222,8 +225,14 @@ func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, s Statusable) e
 		return err
 	}
 
+	if apierrs.IsNotFound(err) && bcName != inteventsv1alpha1.DefaultBrokerCellName {
+		// The BrokerCell informer triggers another reconciliation once the BrokerCell is created.
+		s.MarkBrokerCellFailed("BrokerCellNotFound", "BrokerCell %s/%s does not exist", bcNS, bcName)
+		return fmt.Errorf("brokercell %s/%s does not exist", bcNS, bcName)
+	}
+
 	if apierrs.IsNotFound(err) {
-		want := resources.CreateBrokerCell(nil)
+		want := resources.CreateBrokerCell(bcName)
 		bc, err = r.RunClientSet.InternalV1alpha1().BrokerCells(want.Namespace).Create(ctx, want, metav1.CreateOptions{})
 		if err != nil && !apierrs.IsAlreadyExists(err) {
 			logging.FromContext(ctx).Error("Error creating brokerCell", zap.String("namespace", want.Namespace), zap.String("brokerCell", want.Name), zap.Error(err))
@@ -246,6 +255,11 @@ func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, s Statusable) e
 		}
 	}
 
+	if !bc.AllowsNamespace(s.GetNamespace()) {
+		s.MarkBrokerCellFailed("BrokerCellNotAllowed", "BrokerCell %s/%s does not allow namespace %s", bc.Namespace, bc.Name, s.GetNamespace())
+		return fmt.Errorf("brokercell %s/%s does not allow namespace %s", bc.Namespace, bc.Name, s.GetNamespace())
+	}
+
 	if bc.Status.IsReady() {
 		s.MarkBrokerCellReady()
 	} else {
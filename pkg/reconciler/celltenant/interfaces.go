@@ -21,6 +21,7 @@ import (
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/duck"
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
@@ -42,24 +43,54 @@ type Target interface {
 	GetLabels() map[string]string
 	DeliverySpec() *eventingduckv1beta1.DeliverySpec
 	SetStatusProjectID(projectID string)
+	// MessageOrdering reports whether the retry subscription delivers messages in ordering key
+	// order.
+	MessageOrdering() bool
 }
 
 var _ Target = (*targetForTrigger)(nil)
 
 type targetForTrigger struct {
-	trigger      *brokerv1beta1.Trigger
-	deliverySpec *eventingduckv1beta1.DeliverySpec
+	trigger         *brokerv1beta1.Trigger
+	deliverySpec    *eventingduckv1beta1.DeliverySpec
+	messageOrdering bool
 }
 
 // TargetFromTrigger creates a Target for the given Trigger and associated
-// Broker's deliverySpec.
-func TargetFromTrigger(t *brokerv1beta1.Trigger, deliverySpec *eventingduckv1beta1.DeliverySpec) Target {
+// Broker's deliverySpec. messageOrdering is set if the Broker is ordered, so
+// that retries are delivered in order too.
+func TargetFromTrigger(t *brokerv1beta1.Trigger, deliverySpec *eventingduckv1beta1.DeliverySpec, messageOrdering bool) Target {
 	return &targetForTrigger{
-		trigger:      t,
-		deliverySpec: deliverySpec,
+		trigger:         t,
+		deliverySpec:    deliverySpec,
+		messageOrdering: messageOrdering,
 	}
 }
 
+// TriggerDeliverySpec returns the delivery spec that applies to the given Trigger. The Trigger's
+// own delivery spec takes precedence over its Broker's brokerSpec, but backoff settings it leaves
+// unset are inherited from the Broker.
+func TriggerDeliverySpec(t *brokerv1beta1.Trigger, brokerSpec *eventingduckv1beta1.DeliverySpec) *eventingduckv1beta1.DeliverySpec {
+	ts := t.Spec.Delivery
+	if ts == nil {
+		return brokerSpec
+	}
+	spec := &eventingduckv1beta1.DeliverySpec{
+		DeadLetterSink: ts.DeadLetterSink,
+		Retry:          ts.Retry,
+		BackoffDelay:   ts.BackoffDelay,
+	}
+	if ts.BackoffPolicy != nil {
+		policy := eventingduckv1beta1.BackoffPolicyType(*ts.BackoffPolicy)
+		spec.BackoffPolicy = &policy
+	}
+	if brokerSpec != nil && spec.BackoffPolicy == nil && spec.BackoffDelay == nil {
+		spec.BackoffPolicy = brokerSpec.BackoffPolicy
+		spec.BackoffDelay = brokerSpec.BackoffDelay
+	}
+	return spec
+}
+
 func (t *targetForTrigger) Object() runtime.Object {
 	return t.trigger
 }
@@ -94,6 +125,10 @@ func (t *targetForTrigger) SetStatusProjectID(_ string) {
 	// t.trigger.Status.ProjectID = projectID
 }
 
+func (t *targetForTrigger) MessageOrdering() bool {
+	return t.messageOrdering
+}
+
 var _ Target = (*targetForSubscriberSpec)(nil)
 
 type targetForSubscriberSpec struct {
@@ -153,6 +188,11 @@ func (s *targetForSubscriberSpec) SetStatusProjectID(_ string) {
 	// ProjectID is stored on the Channel's status, not each subscriber's, so this is a noop.
 }
 
+func (s *targetForSubscriberSpec) MessageOrdering() bool {
+	// Channels are not ordered.
+	return false
+}
+
 var _ Target = (*targetForSubscriberStatus)(nil)
 
 type targetForSubscriberStatus struct {
@@ -175,6 +215,11 @@ func (s *targetForSubscriberStatus) SetStatusProjectID(_ string) {
 	// ProjectID is stored on the Channel's status, not each subscriber's, so this is a noop.
 }
 
+func (s *targetForSubscriberStatus) MessageOrdering() bool {
+	// Channels are not ordered.
+	return false
+}
+
 func TargetFromSubscriberStatus(channel *v1beta1.Channel, subscriberStatus eventingduckv1beta1.SubscriberStatus) (Target, *SubscriberStatus) {
 	status := &SubscriberStatus{}
 	return &targetForSubscriberStatus{
@@ -262,6 +307,12 @@ func (s *SubscriberStatus) Message() string {
 // Statusable is the interface used by the Reconciler.
 type Statusable interface {
 	Key() *config.CellTenantKey
+	// GetNamespace returns the namespace of the CellTenant.
+	GetNamespace() string
+	// BrokerCellName returns the name of the BrokerCell that the CellTenant is assigned to.
+	BrokerCellName() string
+	// SetBrokerCell reports the name of the BrokerCell serving the CellTenant in its status.
+	SetBrokerCell(name string)
 	MarkBrokerCellReady()
 	MarkBrokerCellUnknown(reason, format string, args ...interface{})
 	MarkBrokerCellFailed(reason, format string, args ...interface{})
@@ -271,6 +322,9 @@ type Statusable interface {
 	GetLabels() map[string]string
 	GetTopicID() string
 	GetSubscriptionName() string
+	// MessageOrdering reports whether the decoupling subscription delivers messages in ordering
+	// key order.
+	MessageOrdering() bool
 }
 
 var _ Statusable = (*statusableForBroker)(nil)
@@ -289,6 +343,18 @@ func (b *statusableForBroker) Key() *config.CellTenantKey {
 	return config.KeyFromBroker(b.broker)
 }
 
+func (b *statusableForBroker) GetNamespace() string {
+	return b.broker.Namespace
+}
+
+func (b *statusableForBroker) BrokerCellName() string {
+	return inteventsv1alpha1.BrokerCellNameOf(b.broker)
+}
+
+func (b *statusableForBroker) SetBrokerCell(name string) {
+	b.broker.Status.SetBrokerCell(name)
+}
+
 func (b *statusableForBroker) MarkBrokerCellReady() {
 	b.broker.Status.MarkBrokerCellReady()
 }
@@ -331,6 +397,10 @@ func (b *statusableForBroker) GetSubscriptionName() string {
 	return brokerresources.GenerateDecouplingSubscriptionName(b.broker)
 }
 
+func (b *statusableForBroker) MessageOrdering() bool {
+	return b.broker.GetOrderingKeyAttribute() != ""
+}
+
 var _ Statusable = (*statusableForChannel)(nil)
 
 type statusableForChannel struct {
@@ -347,6 +417,18 @@ func (c *statusableForChannel) Key() *config.CellTenantKey {
 	return config.KeyFromChannel(c.ch)
 }
 
+func (c *statusableForChannel) GetNamespace() string {
+	return c.ch.Namespace
+}
+
+func (c *statusableForChannel) BrokerCellName() string {
+	return inteventsv1alpha1.BrokerCellNameOf(c.ch)
+}
+
+func (c *statusableForChannel) SetBrokerCell(name string) {
+	c.ch.Status.SetBrokerCell(name)
+}
+
 func (c *statusableForChannel) MarkBrokerCellReady() {
 	c.ch.Status.MarkBrokerCellReady()
 }
@@ -387,3 +469,7 @@ func (c *statusableForChannel) GetTopicID() string {
 func (c *statusableForChannel) GetSubscriptionName() string {
 	return channelresources.GenerateDecouplingSubscriptionName(c.ch)
 }
+
+func (c *statusableForChannel) MessageOrdering() bool {
+	return false
+}
@@ -108,10 +108,11 @@ func (r *TargetReconciler) ReconcileRetryTopicAndSubscription(ctx context.Contex
 	// Check if PullSub exists, and if not, create it.
 	subID := t.GetSubscriptionName()
 	subConfig := pubsub.SubscriptionConfig{
-		Topic:            topic,
-		Labels:           t.GetLabels(),
-		RetryPolicy:      retryPolicy,
-		DeadLetterPolicy: deadLetterPolicy,
+		Topic:                 topic,
+		Labels:                t.GetLabels(),
+		RetryPolicy:           retryPolicy,
+		DeadLetterPolicy:      deadLetterPolicy,
+		EnableMessageOrdering: t.MessageOrdering(),
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
@@ -179,6 +180,10 @@ func getPubsubDeadLetterPolicy(projectID string, spec *eventingduckv1beta1.Deliv
 	if spec == nil || spec.DeadLetterSink == nil {
 		return nil
 	}
+	// Addressable dead letter sinks are handled by the broker data plane instead of Pub/Sub.
+	if spec.DeadLetterSink.URI == nil || spec.DeadLetterSink.URI.Scheme != "pubsub" {
+		return nil
+	}
 	// Translate to the pubsub dead letter policy format.
 
 	dlp := &pubsub.DeadLetterPolicy{
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifactregistry
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	knduckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	"github.com/google/knative-gcp/pkg/apis/events"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudartifactregistrysourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudartifactregistrysource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	finalizerName = controllerAgentName
+
+	resourceGroup = "cloudartifactregistrysources.events.cloud.google.com"
+
+	createFailedReason           = "PullSubscriptionCreateFailed"
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+	reconciledSuccessReason      = "CloudArtifactRegistrySourceReconciled"
+)
+
+// Reconciler is the controller implementation for the CloudArtifactRegistrySource source.
+type Reconciler struct {
+	*intevents.PubSubBase
+
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// Registrar for registering the CloudEvent types of the sources as EventTypes.
+	*eventtype.Registrar
+	// artifactRegistryLister for reading cloudartifactregistrysources.
+	artifactRegistryLister listers.CloudArtifactRegistrySourceLister
+	// serviceAccountLister for reading serviceAccounts.
+	serviceAccountLister corev1listers.ServiceAccountLister
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudartifactregistrysourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.CloudArtifactRegistrySource) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("source", source)))
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if source.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudArtifactRegistrySource workload identity: %s", err.Error())
+		}
+	}
+	ps, event := r.PubSubBase.ReconcilePullSubscription(ctx, source, events.ArtifactRegistryTopic, resourceGroup)
+	if event != nil {
+		return event
+	}
+
+	source.Status.CloudEventAttributes = getCloudEventAttributes(source, ps.Status.ProjectID)
+	if err := r.ReconcileEventTypes(ctx, source); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventtype.ReconcileFailedReason, "Failed to reconcile CloudArtifactRegistrySource EventTypes: %s", err.Error())
+	}
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudArtifactRegistrySource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, source *v1.CloudArtifactRegistrySource) pkgreconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if source.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudArtifactRegistrySource workload identity: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// getCloudEventAttributes returns the CloudEvent attributes of the events of the actions selected by
+// the ImageFilter of the CloudArtifactRegistrySource, without a source until the project is known.
+func getCloudEventAttributes(source *v1.CloudArtifactRegistrySource, projectID string) []knduckv1.CloudEventAttributes {
+	actions := map[string]string{
+		gcpduckv1.ImageActionInsert: schemasv1.ArtifactRegistryImagePushedEventType,
+		gcpduckv1.ImageActionDelete: schemasv1.ArtifactRegistryImageDeletedEventType,
+	}
+	selected := []string{gcpduckv1.ImageActionInsert, gcpduckv1.ImageActionDelete}
+	if f := source.ImageFilter(); f != nil && len(f.Actions) > 0 {
+		selected = f.Actions
+	}
+	var ceSource string
+	if projectID != "" {
+		ceSource = schemasv1.ArtifactRegistryEventSource(projectID)
+	}
+	var ceAttributes []knduckv1.CloudEventAttributes
+	for _, action := range selected {
+		ceAttributes = append(ceAttributes, knduckv1.CloudEventAttributes{
+			Type:   actions[action],
+			Source: ceSource,
+		})
+	}
+	return ceAttributes
+}
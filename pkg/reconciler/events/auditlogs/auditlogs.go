@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 
+	knduckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/reconciler"
 
@@ -35,8 +36,10 @@ import (
 	glogadmin "github.com/google/knative-gcp/pkg/gclient/logging/logadmin"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 )
 
 const (
@@ -56,6 +59,8 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// Registrar for registering the CloudEvent types of the sources as EventTypes.
+	*eventtype.Registrar
 	auditLogsSourceLister  listers.CloudAuditLogsSourceLister
 	logadminClientProvider glogadmin.CreateFn
 	pubsubClientProvider   gpubsub.CreateFn
@@ -94,6 +99,14 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, s *v1.CloudAuditLogsSour
 	s.Status.MarkSinkReady()
 	c.Logger.Debugf("Reconciled Stackdriver sink: %+v", sink)
 
+	// The source of the events depends on the log of each entry, so it is left out.
+	s.Status.CloudEventAttributes = []knduckv1.CloudEventAttributes{{
+		Type: schemasv1.CloudAuditLogsLogWrittenEventType,
+	}}
+	if err := c.ReconcileEventTypes(ctx, s); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, eventtype.ReconcileFailedReason, "Failed to reconcile CloudAuditLogsSource EventTypes: %s", err.Error())
+	}
+
 	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudAuditLogsSource reconciled: "%s/%s"`, s.Namespace, s.Name)
 }
 
@@ -116,7 +129,7 @@ func (c *Reconciler) ensureSinkCreated(ctx context.Context, s *v1.CloudAuditLogs
 	if sinkID == "" {
 		sinkID = resources.GenerateSinkName(s)
 	}
-	logadminClient, err := c.logadminClientProvider(ctx, s.Status.ProjectID)
+	logadminClient, err := c.logadminClientProvider(ctx, resources.GenerateSinkParent(s))
 	if err != nil {
 		logging.FromContext(ctx).Desugar().Error("Failed to create LogAdmin client", zap.Error(err))
 		return nil, err
@@ -128,10 +141,14 @@ func (c *Reconciler) ensureSinkCreated(ctx context.Context, s *v1.CloudAuditLogs
 		if s.Spec.ResourceName != "" {
 			filterBuilder.WithResourceName(s.Spec.ResourceName)
 		}
+		if s.Spec.Filter != "" {
+			filterBuilder.WithFilter(s.Spec.Filter)
+		}
 		sink = &logadmin.Sink{
-			ID:          sinkID,
-			Destination: resources.GenerateTopicResourceName(s),
-			Filter:      filterBuilder.GetFilterQuery(),
+			ID:              sinkID,
+			Destination:     resources.GenerateTopicResourceName(s),
+			Filter:          filterBuilder.GetFilterQuery(),
+			IncludeChildren: resources.SinkIncludesChildren(s),
 		}
 		sink, err = logadminClient.CreateSinkOpt(ctx, sink, logadmin.SinkOptions{UniqueWriterIdentity: true})
 		// Handle AlreadyExists in-case of a race between another create call.
@@ -173,7 +190,7 @@ func (c *Reconciler) deleteSink(ctx context.Context, s *v1.CloudAuditLogsSource)
 	if s.Status.StackdriverSink == "" {
 		return nil
 	}
-	logadminClient, err := c.logadminClientProvider(ctx, s.Status.ProjectID)
+	logadminClient, err := c.logadminClientProvider(ctx, resources.GenerateSinkParent(s))
 	if err != nil {
 		logging.FromContext(ctx).Desugar().Error("Failed to create LogAdmin client", zap.Error(err))
 		s.Status.MarkSinkUnknown(deleteSinkFailed, "Failed to create LogAdmin Client: %s", err.Error())
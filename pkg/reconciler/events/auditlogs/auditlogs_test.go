@@ -43,6 +43,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/knative-gcp/pkg/apis/duck"
 	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudauditlogssource"
 	testiam "github.com/google/knative-gcp/pkg/gclient/iam/testing"
@@ -51,9 +52,11 @@ import (
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub/testing"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 )
 
 const (
@@ -63,9 +66,13 @@ const (
 	testProject  = "test-project-id"
 	testTopicURI = "http://" + sourceName + "-topic." + testNS + ".svc.cluster.local"
 
-	testServiceName = "test-service"
-	testMethodName  = "test-method"
-	testFilter      = `protoPayload.methodName="test-method" AND protoPayload.serviceName="test-service" AND protoPayload."@type"="type.googleapis.com/google.cloud.audit.AuditLog"`
+	testServiceName    = "test-service"
+	testMethodName     = "test-method"
+	testFilter         = `protoPayload.methodName="test-method" AND protoPayload.serviceName="test-service" AND protoPayload."@type"="type.googleapis.com/google.cloud.audit.AuditLog"`
+	testAdvancedFilter = `protoPayload.methodName:"SetIamPolicy" AND severity>=NOTICE`
+	testFolderFilter   = `(protoPayload.methodName:"SetIamPolicy" AND severity>=NOTICE) AND protoPayload."@type"="type.googleapis.com/google.cloud.audit.AuditLog"`
+	testFolderID       = "123456789"
+	testFolderParent   = "folders/" + testFolderID
 
 	sinkName = "sink"
 	sinkDNS  = sinkName + ".mynamespace.svc.cluster.local"
@@ -1150,6 +1157,77 @@ func TestAllCases(t *testing.T) {
 				v1.WithCloudAuditLogsSourceTopicReady(testTopicID),
 				v1.WithCloudAuditLogsSourcePullSubscriptionReady,
 				v1.WithCloudAuditLogsSourceSinkURI(calSinkURL),
+				v1.WithCloudAuditLogsSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudAuditLogsLogWrittenEventType}),
+				v1.WithCloudAuditLogsSourceSinkReady,
+				v1.WithCloudAuditLogsSourceSinkID(testSinkID),
+				v1.WithCloudAuditLogsSourceSetDefaults,
+			),
+		}},
+	}, {
+		Name: "folder sink with filter created",
+		Objects: []runtime.Object{
+			v1.NewCloudAuditLogsSource(sourceName, testNS,
+				v1.WithCloudAuditLogsSourceUID(sourceUID),
+				v1.WithCloudAuditLogsSourceFilter(testAdvancedFilter),
+				v1.WithCloudAuditLogsSourceParent(eventsv1.AuditLogsParentFolder, testFolderID),
+				v1.WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				v1.WithCloudAuditLogsSourceSetDefaults,
+			),
+			v1.NewTopic(sourceName, testNS,
+				v1.WithTopicSpec(inteventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				v1.WithTopicReady(testTopicID),
+				v1.WithTopicAddress(testTopicURI),
+				v1.WithTopicProjectID(testProject),
+				v1.WithTopicSetDefaults,
+			),
+			v1.NewPullSubscription(sourceName, testNS,
+				v1.WithPullSubscriptionReady(sinkURI),
+				v1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+					Topic: testTopicID,
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret: &secret,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: newSinkDestination(),
+						},
+					},
+					AdapterType: string(converters.CloudAuditLogs),
+				})),
+		},
+		Key: testNS + "/" + sourceName,
+		OtherTestData: map[string]interface{}{
+			"sinkParent": testFolderParent,
+			"expectedSinks": map[string]*logadmin.Sink{
+				testSinkID: {
+					ID:              testSinkID,
+					Filter:          testFolderFilter,
+					Destination:     testTopicResource,
+					IncludeChildren: true,
+				}},
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudAuditLogsSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: v1.NewCloudAuditLogsSource(sourceName, testNS,
+				v1.WithCloudAuditLogsSourceUID(sourceUID),
+				v1.WithCloudAuditLogsSourceFilter(testAdvancedFilter),
+				v1.WithCloudAuditLogsSourceParent(eventsv1.AuditLogsParentFolder, testFolderID),
+				v1.WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				v1.WithCloudAuditLogsSourceProjectID(testProject),
+				v1.WithCloudAuditLogsSourceSubscriptionID(v1.SubscriptionID),
+				v1.WithInitCloudAuditLogsSourceConditions,
+				v1.WithCloudAuditLogsSourceTopicReady(testTopicID),
+				v1.WithCloudAuditLogsSourcePullSubscriptionReady,
+				v1.WithCloudAuditLogsSourceSinkURI(calSinkURL),
+				v1.WithCloudAuditLogsSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudAuditLogsLogWrittenEventType}),
 				v1.WithCloudAuditLogsSourceSinkReady,
 				v1.WithCloudAuditLogsSourceSinkID(testSinkID),
 				v1.WithCloudAuditLogsSourceSetDefaults,
@@ -1222,6 +1300,7 @@ func TestAllCases(t *testing.T) {
 				v1.WithCloudAuditLogsSourceTopicReady(testTopicID),
 				v1.WithCloudAuditLogsSourcePullSubscriptionReady,
 				v1.WithCloudAuditLogsSourceSinkURI(calSinkURL),
+				v1.WithCloudAuditLogsSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudAuditLogsLogWrittenEventType}),
 				v1.WithCloudAuditLogsSourceSinkReady,
 				v1.WithCloudAuditLogsSourceSinkID(testSinkID),
 				v1.WithCloudAuditLogsSourceSetDefaults,
@@ -1355,6 +1434,71 @@ func TestAllCases(t *testing.T) {
 				Name: sourceName,
 			},
 		},
+	}, {
+		Name: "folder sink delete succeeds",
+		Objects: []runtime.Object{
+			v1.NewCloudAuditLogsSource(sourceName, testNS,
+				v1.WithCloudAuditLogsSourceUID(sourceUID),
+				v1.WithCloudAuditLogsSourceFilter(testAdvancedFilter),
+				v1.WithCloudAuditLogsSourceParent(eventsv1.AuditLogsParentFolder, testFolderID),
+				v1.WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				v1.WithCloudAuditLogsSourceProjectID(testProject),
+				v1.WithInitCloudAuditLogsSourceConditions,
+				v1.WithCloudAuditLogsSourceTopicReady(testTopicID),
+				v1.WithCloudAuditLogsSourcePullSubscriptionReady,
+				v1.WithCloudAuditLogsSourceSinkURI(calSinkURL),
+				v1.WithCloudAuditLogsSourceSinkReady,
+				v1.WithCloudAuditLogsSourceSinkID(testSinkID),
+				v1.WithCloudAuditLogsSourceDeletionTimestamp,
+				v1.WithCloudAuditLogsSourceSetDefaults,
+			),
+			v1.NewTopic(sourceName, testNS,
+				v1.WithTopicReady(testTopicID),
+				v1.WithTopicAddress(testTopicURI),
+				v1.WithTopicProjectID(testProject),
+				v1.WithTopicSetDefaults,
+			),
+			v1.NewPullSubscription(sourceName, testNS,
+				v1.WithPullSubscriptionReady(sinkURI),
+			),
+		},
+		Key: testNS + "/" + sourceName,
+		OtherTestData: map[string]interface{}{
+			"sinkParent": testFolderParent,
+			"existingSinks": []logadmin.Sink{{
+				ID:              testSinkID,
+				Filter:          testFolderFilter,
+				Destination:     testTopicResource,
+				IncludeChildren: true,
+			}},
+			"expectedSinks": map[string]*logadmin.Sink{
+				testSinkID: nil,
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: v1.NewCloudAuditLogsSource(sourceName, testNS,
+				v1.WithCloudAuditLogsSourceUID(sourceUID),
+				v1.WithCloudAuditLogsSourceFilter(testAdvancedFilter),
+				v1.WithCloudAuditLogsSourceParent(eventsv1.AuditLogsParentFolder, testFolderID),
+				v1.WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				v1.WithInitCloudAuditLogsSourceConditions,
+				v1.WithCloudAuditLogsSourceSinkDeleted,
+				v1.WithCloudAuditLogsSourceTopicDeleted,
+				v1.WithCloudAuditLogsSourcePullSubscriptionDeleted,
+				v1.WithCloudAuditLogsSourceDeletionTimestamp,
+				v1.WithCloudAuditLogsSourceSetDefaults,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"}},
+				Name: sourceName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"}},
+				Name: sourceName,
+			},
+		},
 	}, {
 		Name: "delete succeeds, sink does not exist",
 		Objects: []runtime.Object{
@@ -1418,8 +1562,12 @@ func TestAllCases(t *testing.T) {
 	for _, tt := range table {
 		t.Run(tt.Name, func(t *testing.T) {
 			logadminClientProvider := glogadmintesting.TestClientCreator(tt.OtherTestData["logadmin"])
+			sinkParent := testProject
+			if parent := tt.OtherTestData["sinkParent"]; parent != nil {
+				sinkParent = parent.(string)
+			}
 			if existingSinks := tt.OtherTestData["existingSinks"]; existingSinks != nil {
-				createSinks(t, logadminClientProvider, existingSinks.([]logadmin.Sink))
+				createSinks(t, logadminClientProvider, sinkParent, existingSinks.([]logadmin.Sink))
 			}
 			tt.Test(t, MakeFactory(
 				func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
@@ -1432,6 +1580,7 @@ func TestAllCases(t *testing.T) {
 								ConfigWatcher:       cmw,
 							}),
 						Identity:               identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+						Registrar:              eventtype.NewRegistrar(ctx),
 						auditLogsSourceLister:  listers.GetCloudAuditLogsSourceLister(),
 						logadminClientProvider: logadminClientProvider,
 						pubsubClientProvider:   gpubsub.TestClientCreator(testData["pubsub"]),
@@ -1440,14 +1589,14 @@ func TestAllCases(t *testing.T) {
 					return cloudauditlogssource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudAuditLogsSourceLister(), r.Recorder, r)
 				}))
 			if expectedSinks := tt.OtherTestData["expectedSinks"]; expectedSinks != nil {
-				expectSinks(t, logadminClientProvider, expectedSinks.(map[string]*logadmin.Sink))
+				expectSinks(t, logadminClientProvider, sinkParent, expectedSinks.(map[string]*logadmin.Sink))
 			}
 		})
 	}
 }
 
-func createSinks(t *testing.T, clientProvider glogadmin.CreateFn, sinks []logadmin.Sink) {
-	logadminClient, err := clientProvider(context.Background(), testProject)
+func createSinks(t *testing.T, clientProvider glogadmin.CreateFn, parent string, sinks []logadmin.Sink) {
+	logadminClient, err := clientProvider(context.Background(), parent)
 	if err != nil {
 		t.Fatalf("failed to create logadmin client during setup: %s", err)
 	}
@@ -1456,8 +1605,8 @@ func createSinks(t *testing.T, clientProvider glogadmin.CreateFn, sinks []logadm
 	}
 }
 
-func expectSinks(t *testing.T, clientProvider glogadmin.CreateFn, sinks map[string]*logadmin.Sink) {
-	logadminClient, err := clientProvider(context.Background(), testProject)
+func expectSinks(t *testing.T, clientProvider glogadmin.CreateFn, parent string, sinks map[string]*logadmin.Sink) {
+	logadminClient, err := clientProvider(context.Background(), parent)
 	if err != nil {
 		t.Fatalf("failed to create logadmin client during verification: %s", err)
 	}
@@ -16,11 +16,12 @@ const (
 
 // Stackdriver query builder for querying audit logs. Currently
 // supports querying by the AuditLog serviceName, methodName, and
-// resourceName.
+// resourceName, and by an arbitrary advanced logs filter.
 type FilterBuilder struct {
 	serviceName  string
 	methodName   string
 	resourceName string
+	filter       string
 }
 
 func (fb *FilterBuilder) WithServiceName(serviceName string) *FilterBuilder {
@@ -38,6 +39,12 @@ func (fb *FilterBuilder) WithResourceName(resourceName string) *FilterBuilder {
 	return fb
 }
 
+// WithFilter adds an advanced logs filter the audit logs must match.
+func (fb *FilterBuilder) WithFilter(filter string) *FilterBuilder {
+	fb.filter = filter
+	return fb
+}
+
 func (fb *FilterBuilder) GetFilterQuery() string {
 	var filters []string
 	if fb.methodName != "" {
@@ -52,6 +59,11 @@ func (fb *FilterBuilder) GetFilterQuery() string {
 		filters = append(filters, filter{resourceKey, fb.resourceName}.String())
 	}
 
+	if fb.filter != "" {
+		// Parenthesize the filter so that its OR operators don't apply to
+		// the other restrictions.
+		filters = append(filters, "("+fb.filter+")")
+	}
 	filters = append(filters, filter{typeKey, typeValue}.String())
 	filter := strings.Join(filters, " AND ")
 	return filter
@@ -41,3 +41,32 @@ func GenerateTopicResourceName(s *v1.CloudAuditLogsSource) string {
 func GenerateSinkName(s *v1.CloudAuditLogsSource) string {
 	return naming.TruncatedLoggingSinkResourceName("cre-src", s.Namespace, s.Name, s.UID)
 }
+
+// GenerateSinkParent generates the parent of the Stackdriver sink of an
+// CloudAuditLogsSource, in the form expected by logadmin.NewClient.
+func GenerateSinkParent(s *v1.CloudAuditLogsSource) string {
+	if s.Spec.Parent == nil {
+		return s.Status.ProjectID
+	}
+	switch s.Spec.Parent.Type {
+	case v1.AuditLogsParentFolder:
+		return "folders/" + s.Spec.Parent.ID
+	case v1.AuditLogsParentOrganization:
+		return "organizations/" + s.Spec.Parent.ID
+	case v1.AuditLogsParentBillingAccount:
+		return "billingAccounts/" + s.Spec.Parent.ID
+	default:
+		if s.Spec.Parent.ID != "" {
+			return s.Spec.Parent.ID
+		}
+		return s.Status.ProjectID
+	}
+}
+
+// SinkIncludesChildren returns whether the Stackdriver sink of an
+// CloudAuditLogsSource exports the audit logs of the children of its
+// parent as well.
+func SinkIncludesChildren(s *v1.CloudAuditLogsSource) bool {
+	return s.Spec.Parent != nil &&
+		(s.Spec.Parent.Type == v1.AuditLogsParentFolder || s.Spec.Parent.Type == v1.AuditLogsParentOrganization)
+}
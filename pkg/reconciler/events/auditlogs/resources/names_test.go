@@ -70,3 +70,57 @@ func TestGenerateSinkName(t *testing.T) {
 		t.Errorf("unexpected (-want, +got) = %v", diff)
 	}
 }
+
+func TestGenerateSinkParent(t *testing.T) {
+	testCases := []struct {
+		name             string
+		parent           *v1.AuditLogsParent
+		want             string
+		wantIncludeChild bool
+	}{{
+		name: "default",
+		want: "project",
+	}, {
+		name:   "project",
+		parent: &v1.AuditLogsParent{Type: v1.AuditLogsParentProject},
+		want:   "project",
+	}, {
+		name:   "other project",
+		parent: &v1.AuditLogsParent{Type: v1.AuditLogsParentProject, ID: "other-project"},
+		want:   "other-project",
+	}, {
+		name:             "folder",
+		parent:           &v1.AuditLogsParent{Type: v1.AuditLogsParentFolder, ID: "123"},
+		want:             "folders/123",
+		wantIncludeChild: true,
+	}, {
+		name:             "organization",
+		parent:           &v1.AuditLogsParent{Type: v1.AuditLogsParentOrganization, ID: "456"},
+		want:             "organizations/456",
+		wantIncludeChild: true,
+	}, {
+		name:   "billing account",
+		parent: &v1.AuditLogsParent{Type: v1.AuditLogsParentBillingAccount, ID: "012345-6789AB-CDEF01"},
+		want:   "billingAccounts/012345-6789AB-CDEF01",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &v1.CloudAuditLogsSource{
+				Spec: v1.CloudAuditLogsSourceSpec{
+					Parent: tc.parent,
+				},
+				Status: v1.CloudAuditLogsSourceStatus{
+					PubSubStatus: duckv1.PubSubStatus{
+						ProjectID: "project",
+					},
+				},
+			}
+			if diff := cmp.Diff(tc.want, GenerateSinkParent(s)); diff != "" {
+				t.Errorf("unexpected (-want, +got) = %v", diff)
+			}
+			if got := SinkIncludesChildren(s); got != tc.wantIncludeChild {
+				t.Errorf("SinkIncludesChildren got=%v, want=%v", got, tc.wantIncludeChild)
+			}
+		})
+	}
+}
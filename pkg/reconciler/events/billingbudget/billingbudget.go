@@ -0,0 +1,119 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package billingbudget
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	knduckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudbillingbudgetsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudbillingbudgetsource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	finalizerName = controllerAgentName
+
+	resourceGroup = "cloudbillingbudgetsources.events.cloud.google.com"
+
+	createFailedReason           = "PullSubscriptionCreateFailed"
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+	reconciledSuccessReason      = "CloudBillingBudgetSourceReconciled"
+)
+
+// Reconciler is the controller implementation for the CloudBillingBudgetSource source.
+type Reconciler struct {
+	*intevents.PubSubBase
+
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// Registrar for registering the CloudEvent types of the sources as EventTypes.
+	*eventtype.Registrar
+	// billingBudgetLister for reading cloudbillingbudgetsources.
+	billingBudgetLister listers.CloudBillingBudgetSourceLister
+	// serviceAccountLister for reading serviceAccounts.
+	serviceAccountLister corev1listers.ServiceAccountLister
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudbillingbudgetsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.CloudBillingBudgetSource) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("source", source)))
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if source.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudBillingBudgetSource workload identity: %s", err.Error())
+		}
+	}
+	_, event := r.PubSubBase.ReconcilePullSubscription(ctx, source, source.Spec.Topic, resourceGroup)
+	if event != nil {
+		return event
+	}
+
+	source.Status.CloudEventAttributes = getCloudEventAttributes(source)
+	if err := r.ReconcileEventTypes(ctx, source); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventtype.ReconcileFailedReason, "Failed to reconcile CloudBillingBudgetSource EventTypes: %s", err.Error())
+	}
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudBillingBudgetSource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, source *v1.CloudBillingBudgetSource) pkgreconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if source.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudBillingBudgetSource workload identity: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// getCloudEventAttributes returns the CloudEvent attributes of the events of the
+// CloudBillingBudgetSource. The source of the events depends on the billing account of each
+// budget, so it is left out.
+func getCloudEventAttributes(source *v1.CloudBillingBudgetSource) []knduckv1.CloudEventAttributes {
+	ceAttributes := []knduckv1.CloudEventAttributes{{
+		Type: schemasv1.CloudBillingBudgetThresholdExceededEventType,
+	}}
+	// The BudgetFilter only delivers the notifications of the budgets whose cost exceeded a
+	// threshold.
+	if source.BudgetFilter() == nil {
+		ceAttributes = append(ceAttributes, knduckv1.CloudEventAttributes{
+			Type: schemasv1.CloudBillingBudgetCostUpdatedEventType,
+		})
+	}
+	return ceAttributes
+}
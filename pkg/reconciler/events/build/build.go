@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 
+	knduckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 
@@ -31,8 +32,10 @@ import (
 	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
 	cloudbuildsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudbuildsource"
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 )
 
 const (
@@ -52,6 +55,8 @@ type Reconciler struct {
 
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// Registrar for registering the CloudEvent types of the sources as EventTypes.
+	*eventtype.Registrar
 	// buildLister for reading cloudbuildsources.
 	buildLister listers.CloudBuildSourceLister
 	// serviceAccountLister for reading serviceAccounts.
@@ -77,6 +82,14 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, build *v1.CloudBuildSour
 		return event
 	}
 
+	// The source of the events depends on the build, so it is left out.
+	build.Status.CloudEventAttributes = []knduckv1.CloudEventAttributes{{
+		Type: schemasv1.CloudBuildSourceEventType,
+	}}
+	if err := r.ReconcileEventTypes(ctx, build); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventtype.ReconcileFailedReason, "Failed to reconcile CloudBuildSource EventTypes: %s", err.Error())
+	}
+
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudBuildSource reconciled: "%s/%s"`, build.Namespace, build.Name)
 }
 
@@ -46,9 +46,11 @@ import (
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudbuildsource"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 )
 
 const (
@@ -343,6 +345,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertestingv1.WithInitCloudBuildSourceConditions,
 					reconcilertestingv1.WithCloudBuildSourcePullSubscriptionReady(),
 					reconcilertestingv1.WithCloudBuildSourceSinkURI(pubsubSinkURL),
+					reconcilertestingv1.WithCloudBuildSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudBuildSourceEventType}),
 					reconcilertestingv1.WithCloudBuildSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 					reconcilertestingv1.WithCloudBuildSourceSetDefault,
 				),
@@ -354,6 +357,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertestingv1.WithInitCloudBuildSourceConditions,
 					reconcilertestingv1.WithCloudBuildSourcePullSubscriptionReady(),
 					reconcilertestingv1.WithCloudBuildSourceSinkURI(pubsubSinkURL),
+					reconcilertestingv1.WithCloudBuildSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudBuildSourceEventType}),
 					reconcilertestingv1.WithCloudBuildSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 					reconcilertestingv1.WithCloudBuildSourceFinalizers("cloudbuildsources.events.cloud.google.com"),
 					reconcilertestingv1.WithCloudBuildSourceSetDefault,
@@ -378,6 +382,7 @@ func TestAllCases(t *testing.T) {
 					ConfigWatcher:       cmw,
 				}),
 			Identity:             identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			Registrar:            eventtype.NewRegistrar(ctx),
 			buildLister:          listers.GetCloudBuildSourceLister(),
 			serviceAccountLister: listers.GetServiceAccountLister(),
 		}
@@ -30,6 +30,7 @@ import (
 	cloudbuildsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudbuildsource"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
@@ -75,6 +76,7 @@ func newController(
 				ConfigWatcher:       cmw,
 			}),
 		Identity:             identity.NewIdentity(ctx, ipm, gcpas),
+		Registrar:            eventtype.NewRegistrar(ctx),
 		buildLister:          cloudbuildsourceInformer.Lister(),
 		serviceAccountLister: serviceAccountInformer.Lister(),
 	}
@@ -0,0 +1,238 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	corev1 "k8s.io/api/core/v1"
+	knduckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudmonitoringalertsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudmonitoringalertsource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	resourceGroup = "cloudmonitoringalertsources.events.cloud.google.com"
+
+	deleteNotificationChannelFailed = "NotificationChannelDeleteFailed"
+	deletePubSubFailed              = "PubSubDeleteFailed"
+	deleteWorkloadIdentityFailed    = "WorkloadIdentityDeleteFailed"
+	reconciledPubSubFailedReason    = "PubSubReconcileFailed"
+	reconciledFailedReason          = "NotificationChannelReconcileFailed"
+	reconciledSuccessReason         = "CloudMonitoringAlertSourceReconciled"
+	workloadIdentityFailed          = "WorkloadIdentityReconcileFailed"
+)
+
+// Reconciler is the controller implementation for Google Cloud Monitoring alert notification channels.
+type Reconciler struct {
+	*intevents.PubSubBase
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// Registrar for registering the CloudEvent types of the sources as EventTypes.
+	*eventtype.Registrar
+	// monitoringAlertLister for reading CloudMonitoringAlertSources.
+	monitoringAlertLister listers.CloudMonitoringAlertSourceLister
+
+	createClientFn gmonitoring.CreateFn
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudmonitoringalertsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.CloudMonitoringAlertSource) reconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("monitoringalert", source)))
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if source.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudMonitoringAlertSource workload identity: %s", err.Error())
+		}
+	}
+
+	topic := resources.GenerateTopicName(source)
+	_, _, err := r.PubSubBase.ReconcilePubSub(ctx, source, topic, resourceGroup)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: %s", err.Error())
+	}
+
+	channelName, err := r.reconcileNotificationChannel(ctx, source, topic)
+	if err != nil {
+		source.Status.MarkNotificationChannelNotReady(reconciledFailedReason, "Failed to reconcile CloudMonitoringAlertSource notification channel: %s", err.Error())
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: %s", err.Error())
+	}
+	source.Status.MarkNotificationChannelReady(channelName)
+
+	source.Status.CloudEventAttributes = []knduckv1.CloudEventAttributes{{
+		Type:   schemasv1.CloudMonitoringIncidentOpenedEventType,
+		Source: schemasv1.CloudMonitoringEventSource(source.Status.ProjectID),
+	}, {
+		Type:   schemasv1.CloudMonitoringIncidentClosedEventType,
+		Source: schemasv1.CloudMonitoringEventSource(source.Status.ProjectID),
+	}}
+	if err := r.ReconcileEventTypes(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, eventtype.ReconcileFailedReason, "Failed to reconcile CloudMonitoringAlertSource EventTypes: %s", err.Error())
+	}
+	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+// reconcileNotificationChannel creates the notification channel unless status.NotificationChannelName names an
+// existing one, in which case it is updated to match the spec. It returns the name of the notification channel.
+func (r *Reconciler) reconcileNotificationChannel(ctx context.Context, source *v1.CloudMonitoringAlertSource, topic string) (string, error) {
+	if source.Status.ProjectID == "" {
+		projectID, err := utils.ProjectIDOrDefault(source.Spec.Project)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+			return "", err
+		}
+		// Set the projectID in the status.
+		source.Status.ProjectID = projectID
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource client", zap.Error(err))
+		return "", err
+	}
+	defer client.Close()
+
+	desired := resources.MakeNotificationChannel(source, topic)
+	channelName := source.Status.NotificationChannelName
+	if channelName != "" {
+		// Check if the notification channel exists.
+		channel, err := client.GetNotificationChannel(ctx, &monitoringpb.GetNotificationChannelRequest{Name: channelName})
+		if err == nil {
+			return channelName, r.updateNotificationChannel(ctx, client, channel, desired)
+		}
+		if st, ok := gstatus.FromError(err); !ok || st.Code() != codes.NotFound {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while retrieving CloudMonitoringAlertSource notification channel", zap.String("channelName", channelName), zap.Error(err))
+			return "", err
+		}
+		// The notification channel was deleted out of band, create it again.
+		logging.FromContext(ctx).Desugar().Info("CloudMonitoringAlertSource notification channel not found, recreating it", zap.String("channelName", channelName))
+	}
+
+	channel, err := client.CreateNotificationChannel(ctx, &monitoringpb.CreateNotificationChannelRequest{
+		Name:                resources.GenerateProjectName(source),
+		NotificationChannel: desired,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource notification channel", zap.Error(err))
+		return "", err
+	}
+	return channel.Name, nil
+}
+
+// updateNotificationChannel updates the display name and the topic of channel if they don't match desired.
+func (r *Reconciler) updateNotificationChannel(ctx context.Context, client gmonitoring.Client, channel, desired *monitoringpb.NotificationChannel) error {
+	var paths []string
+	if channel.DisplayName != desired.DisplayName {
+		paths = append(paths, "display_name")
+	}
+	for k, v := range desired.Labels {
+		if channel.Labels[k] != v {
+			paths = append(paths, "labels")
+			break
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	desired.Name = channel.Name
+	if _, err := client.UpdateNotificationChannel(ctx, &monitoringpb.UpdateNotificationChannelRequest{
+		UpdateMask:          &fieldmaskpb.FieldMask{Paths: paths},
+		NotificationChannel: desired,
+	}); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to update CloudMonitoringAlertSource notification channel", zap.String("channelName", channel.Name), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// deleteNotificationChannel looks at status.NotificationChannelName and if non-empty,
+// hence indicating that we have created a notification channel successfully,
+// remove it. The alerting policies that use it stop notifying it.
+func (r *Reconciler) deleteNotificationChannel(ctx context.Context, source *v1.CloudMonitoringAlertSource) error {
+	if source.Status.NotificationChannelName == "" {
+		return nil
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource client", zap.Error(err))
+		source.Status.MarkNotificationChannelUnknown(deleteNotificationChannelFailed, "Failed to create CloudMonitoringAlertSource client: %s", err.Error())
+		return err
+	}
+	defer client.Close()
+
+	// Force the deletion of the notification channels that are still referenced by alerting policies.
+	err = client.DeleteNotificationChannel(ctx, &monitoringpb.DeleteNotificationChannelRequest{Name: source.Status.NotificationChannelName, Force: true})
+	if err == nil {
+		logging.FromContext(ctx).Desugar().Debug("Deleted CloudMonitoringAlertSource notification channel", zap.String("channelName", source.Status.NotificationChannelName))
+		return nil
+	}
+	if st, ok := gstatus.FromError(err); !ok {
+		logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while deleting CloudMonitoringAlertSource notification channel", zap.String("channelName", source.Status.NotificationChannelName), zap.Error(err))
+		source.Status.MarkNotificationChannelUnknown(deleteNotificationChannelFailed, "Failed from CloudMonitoringAlertSource client while deleting CloudMonitoringAlertSource notification channel: %s", err.Error())
+		return err
+	} else if st.Code() != codes.NotFound {
+		logging.FromContext(ctx).Desugar().Error("Failed to delete CloudMonitoringAlertSource notification channel", zap.String("channelName", source.Status.NotificationChannelName), zap.Error(err))
+		source.Status.MarkNotificationChannelUnknown(deleteNotificationChannelFailed, "Failed to delete CloudMonitoringAlertSource notification channel: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, source *v1.CloudMonitoringAlertSource) reconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if source.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudMonitoringAlertSource workload identity: %s", err.Error())
+		}
+	}
+
+	logging.FromContext(ctx).Desugar().Debug("Deleting CloudMonitoringAlertSource notification channel")
+	if err := r.deleteNotificationChannel(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteNotificationChannelFailed, "Failed to delete CloudMonitoringAlertSource notification channel: %s", err.Error())
+	}
+
+	if err := r.PubSubBase.DeletePubSub(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailed, "Failed to delete CloudMonitoringAlertSource PubSub: %s", err.Error())
+	}
+
+	return nil
+}
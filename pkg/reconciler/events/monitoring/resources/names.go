@@ -0,0 +1,41 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"github.com/google/knative-gcp/pkg/utils/naming"
+)
+
+// GenerateTopicName generates a topic name for the CloudMonitoringAlertSource. This refers to the underlying Pub/Sub
+// topic, and not our Topic resource.
+func GenerateTopicName(source *v1.CloudMonitoringAlertSource) string {
+	return naming.TruncatedPubsubResourceName("cre-src", source.Namespace, source.Name, source.UID)
+}
+
+// GenerateProjectName generates the name of the project in which the notification channel is created, like this:
+// projects/PROJECT_ID.
+func GenerateProjectName(source *v1.CloudMonitoringAlertSource) string {
+	return fmt.Sprintf("projects/%s", source.Status.ProjectID)
+}
+
+// GeneratePubSubTopic generates the full name of the topic that the notification channel publishes to.
+func GeneratePubSubTopic(source *v1.CloudMonitoringAlertSource, topic string) string {
+	return fmt.Sprintf("projects/%s/topics/%s", source.Status.ProjectID, topic)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+const (
+	// notificationChannelType is the type of the notification channels that publish to Pub/Sub.
+	notificationChannelType = "pubsub"
+	// notificationChannelTopicLabel is the label of the notification channels with the topic they publish to.
+	notificationChannelTopicLabel = "topic"
+	// notificationChannelSourceLabel is the user label of the notification channels with the UID of their
+	// CloudMonitoringAlertSource.
+	notificationChannelSourceLabel = "knative-gcp-source"
+)
+
+// MakeNotificationChannel makes the Cloud Monitoring notification channel of a CloudMonitoringAlertSource, which
+// publishes to topic.
+func MakeNotificationChannel(source *v1.CloudMonitoringAlertSource, topic string) *monitoringpb.NotificationChannel {
+	return &monitoringpb.NotificationChannel{
+		Type:        notificationChannelType,
+		DisplayName: DisplayName(source),
+		Labels: map[string]string{
+			notificationChannelTopicLabel: GeneratePubSubTopic(source, topic),
+		},
+		UserLabels: map[string]string{
+			notificationChannelSourceLabel: string(source.UID),
+		},
+	}
+}
+
+// DisplayName returns the display name of the notification channel of a CloudMonitoringAlertSource.
+func DisplayName(source *v1.CloudMonitoringAlertSource) string {
+	if source.Spec.DisplayName != "" {
+		return source.Spec.DisplayName
+	}
+	return fmt.Sprintf("%s/%s", source.Namespace, source.Name)
+}
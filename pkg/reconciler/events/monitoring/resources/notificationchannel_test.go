@@ -0,0 +1,84 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/protobuf/testing/protocmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+func TestMakeNotificationChannel(t *testing.T) {
+	source := &v1.CloudMonitoringAlertSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myname",
+			Namespace: "mynamespace",
+			UID:       "uid",
+		},
+		Status: v1.CloudMonitoringAlertSourceStatus{
+			PubSubStatus: duckv1.PubSubStatus{
+				ProjectID: "project",
+			},
+		},
+	}
+	testCases := map[string]struct {
+		displayName string
+		want        *monitoringpb.NotificationChannel
+	}{
+		"default display name": {
+			want: &monitoringpb.NotificationChannel{
+				Type:        "pubsub",
+				DisplayName: "mynamespace/myname",
+				Labels: map[string]string{
+					"topic": "projects/project/topics/topic",
+				},
+				UserLabels: map[string]string{
+					"knative-gcp-source": "uid",
+				},
+			},
+		},
+		"display name": {
+			displayName: "On-call incidents",
+			want: &monitoringpb.NotificationChannel{
+				Type:        "pubsub",
+				DisplayName: "On-call incidents",
+				Labels: map[string]string{
+					"topic": "projects/project/topics/topic",
+				},
+				UserLabels: map[string]string{
+					"knative-gcp-source": "uid",
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			s := source.DeepCopy()
+			s.Spec.DisplayName = tc.displayName
+			got := MakeNotificationChannel(s, "topic")
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
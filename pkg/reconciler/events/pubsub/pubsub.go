@@ -21,14 +21,17 @@ import (
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	knduckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 
 	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
 	cloudpubsubsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudpubsubsource"
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 )
 
 const (
@@ -44,6 +47,8 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// Registrar for registering the CloudEvent types of the sources as EventTypes.
+	*eventtype.Registrar
 	// pubsubLister for reading cloudpubsubsources.
 	pubsubLister listers.CloudPubSubSourceLister
 }
@@ -64,10 +69,15 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pubsub *v1.CloudPubSubSo
 		}
 	}
 
-	_, event := r.PubSubBase.ReconcilePullSubscription(ctx, pubsub, pubsub.Spec.Topic, resourceGroup)
+	ps, event := r.PubSubBase.ReconcilePullSubscription(ctx, pubsub, pubsub.Spec.Topic, resourceGroup)
 	if event != nil {
 		return event
 	}
+
+	pubsub.Status.CloudEventAttributes = getCloudEventAttributes(pubsub, ps.Status.ProjectID)
+	if err := r.ReconcileEventTypes(ctx, pubsub); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventtype.ReconcileFailedReason, "Failed to reconcile CloudPubSubSource EventTypes: %s", err.Error())
+	}
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudPubSubSource reconciled: "%s/%s"`, pubsub.Namespace, pubsub.Name)
 }
 
@@ -82,3 +92,28 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, pubsub *v1.CloudPubSubSou
 	}
 	return nil
 }
+
+// getCloudEventAttributes returns the CloudEvent attributes of the events of the CloudPubSubSource,
+// without a source until the project of the topic is known. The EventMapping of the source
+// overrides them if it maps them to constant values, and leaves them unknown if it maps them from
+// the messages.
+func getCloudEventAttributes(pubsub *v1.CloudPubSubSource, projectID string) []knduckv1.CloudEventAttributes {
+	ceAttributes := knduckv1.CloudEventAttributes{
+		Type: schemasv1.CloudPubSubMessagePublishedEventType,
+	}
+	if projectID != "" {
+		ceAttributes.Source = schemasv1.CloudPubSubEventSource(projectID, pubsub.Spec.Topic)
+	}
+	if mapping := pubsub.EventMapping(); mapping != nil {
+		if mapping.Type != nil {
+			if mapping.Type.Value == "" {
+				return nil
+			}
+			ceAttributes.Type = mapping.Type.Value
+		}
+		if mapping.Source != nil {
+			ceAttributes.Source = mapping.Source.Value
+		}
+	}
+	return []knduckv1.CloudEventAttributes{ceAttributes}
+}
@@ -45,9 +45,11 @@ import (
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudpubsubsource"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 )
 
 const (
@@ -345,6 +347,7 @@ func TestAllCases(t *testing.T) {
 				reconcilertestingv1.WithInitCloudPubSubSourceConditions,
 				reconcilertestingv1.WithCloudPubSubSourcePullSubscriptionReady,
 				reconcilertestingv1.WithCloudPubSubSourceSinkURI(pubsubSinkURL),
+				reconcilertestingv1.WithCloudPubSubSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudPubSubMessagePublishedEventType}),
 				reconcilertestingv1.WithCloudPubSubSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 				reconcilertestingv1.WithCloudPubSubSourceSetDefaults,
 			),
@@ -357,6 +360,7 @@ func TestAllCases(t *testing.T) {
 				reconcilertestingv1.WithInitCloudPubSubSourceConditions,
 				reconcilertestingv1.WithCloudPubSubSourcePullSubscriptionReady,
 				reconcilertestingv1.WithCloudPubSubSourceSinkURI(pubsubSinkURL),
+				reconcilertestingv1.WithCloudPubSubSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudPubSubMessagePublishedEventType}),
 				reconcilertestingv1.WithCloudPubSubSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 				reconcilertestingv1.WithCloudPubSubSourceFinalizers("cloudpubsubsources.events.cloud.google.com"),
 				reconcilertestingv1.WithCloudPubSubSourceSetDefaults,
@@ -381,6 +385,7 @@ func TestAllCases(t *testing.T) {
 					ConfigWatcher:       cmw,
 				}),
 			Identity:     identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			Registrar:    eventtype.NewRegistrar(ctx),
 			pubsubLister: listers.GetCloudPubSubSourceLister(),
 		}
 		return cloudpubsubsource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudPubSubSourceLister(), r.Recorder, r)
@@ -25,6 +25,7 @@ import (
 	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
@@ -81,6 +82,7 @@ func newController(
 				ConfigWatcher:       cmw,
 			}),
 		Identity:        identity.NewIdentity(ctx, ipm, gcpas),
+		Registrar:       eventtype.NewRegistrar(ctx),
 		schedulerLister: cloudschedulersourceInformer.Lister(),
 		createClientFn:  gscheduler.NewClient,
 	}
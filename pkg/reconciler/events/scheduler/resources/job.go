@@ -0,0 +1,128 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/rickb777/date/period"
+	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// MakeJob makes the Cloud Scheduler Job of a CloudSchedulerSource, which publishes to topic.
+func MakeJob(scheduler *v1.CloudSchedulerSource, topic, jobName string) (*schedulerpb.Job, error) {
+	data, err := jobData(scheduler)
+	if err != nil {
+		return nil, err
+	}
+	retryConfig, err := jobRetryConfig(scheduler.Spec.RetryConfig)
+	if err != nil {
+		return nil, err
+	}
+	// Add jobName as customAttribute.
+	attributes := map[string]string{
+		v1.CloudSchedulerSourceJobName: jobName,
+	}
+	// The converter needs the content type to emit JSON data as is.
+	if scheduler.Spec.DataContentType != "" {
+		attributes[v1.CloudSchedulerSourceDataContentType] = scheduler.Spec.DataContentType
+	}
+	for k, v := range scheduler.Spec.Attributes {
+		attributes[k] = v
+	}
+	return &schedulerpb.Job{
+		Name: jobName,
+		Target: &schedulerpb.Job_PubsubTarget{
+			PubsubTarget: &schedulerpb.PubsubTarget{
+				TopicName:  GeneratePubSubTargetTopic(scheduler, topic),
+				Data:       data,
+				Attributes: attributes,
+			},
+		},
+		Schedule:    scheduler.Spec.Schedule,
+		TimeZone:    scheduler.Spec.TimeZone,
+		RetryConfig: retryConfig,
+	}, nil
+}
+
+// MakeRetryConfigStatus makes the retry configuration reported in the status of a
+// CloudSchedulerSource from the retry configuration of its Job.
+func MakeRetryConfigStatus(retryConfig *schedulerpb.RetryConfig) *v1.SchedulerRetryConfig {
+	if retryConfig == nil {
+		return nil
+	}
+	retryCount := retryConfig.RetryCount
+	return &v1.SchedulerRetryConfig{
+		RetryCount:         &retryCount,
+		MinBackoffDuration: durationString(retryConfig.MinBackoffDuration),
+		MaxBackoffDuration: durationString(retryConfig.MaxBackoffDuration),
+	}
+}
+
+func jobData(scheduler *v1.CloudSchedulerSource) ([]byte, error) {
+	if scheduler.Spec.DataContentType != v1.SchedulerDataContentTypeBinary {
+		return []byte(scheduler.Spec.Data), nil
+	}
+	data, err := base64.StdEncoding.DecodeString(scheduler.Spec.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data: %w", err)
+	}
+	return data, nil
+}
+
+func jobRetryConfig(retryConfig *v1.SchedulerRetryConfig) (*schedulerpb.RetryConfig, error) {
+	if retryConfig == nil {
+		return nil, nil
+	}
+	var err error
+	c := &schedulerpb.RetryConfig{}
+	if retryConfig.RetryCount != nil {
+		c.RetryCount = *retryConfig.RetryCount
+	}
+	if c.MinBackoffDuration, err = durationProto(retryConfig.MinBackoffDuration); err != nil {
+		return nil, fmt.Errorf("failed to parse minBackoffDuration: %w", err)
+	}
+	if c.MaxBackoffDuration, err = durationProto(retryConfig.MaxBackoffDuration); err != nil {
+		return nil, fmt.Errorf("failed to parse maxBackoffDuration: %w", err)
+	}
+	return c, nil
+}
+
+func durationProto(d *string) (*durationpb.Duration, error) {
+	if d == nil {
+		return nil, nil
+	}
+	p, err := period.Parse(*d)
+	if err != nil {
+		return nil, err
+	}
+	duration, _ := p.Duration()
+	return durationpb.New(duration), nil
+}
+
+func durationString(d *durationpb.Duration) *string {
+	if d == nil {
+		return nil
+	}
+	p, _ := period.NewOf(d.AsDuration())
+	s := p.String()
+	return &s
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"knative.dev/pkg/ptr"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+func TestMakeJob(t *testing.T) {
+	scheduler := &v1.CloudSchedulerSource{
+		Spec: v1.CloudSchedulerSourceSpec{
+			Schedule:        "0 2 * * *",
+			Data:            "AAECAw==",
+			DataContentType: v1.SchedulerDataContentTypeBinary,
+			TimeZone:        "America/New_York",
+			Attributes: map[string]string{
+				"team": "billing",
+			},
+			RetryConfig: &v1.SchedulerRetryConfig{
+				RetryCount:         ptr.Int32(3),
+				MinBackoffDuration: ptr.String("PT10S"),
+			},
+		},
+		Status: v1.CloudSchedulerSourceStatus{
+			PubSubStatus: duckv1.PubSubStatus{
+				ProjectID: "project",
+			},
+		},
+	}
+	want := &schedulerpb.Job{
+		Name: "job",
+		Target: &schedulerpb.Job_PubsubTarget{
+			PubsubTarget: &schedulerpb.PubsubTarget{
+				TopicName: "projects/project/topics/topic",
+				Data:      []byte{0, 1, 2, 3},
+				Attributes: map[string]string{
+					v1.CloudSchedulerSourceJobName:         "job",
+					v1.CloudSchedulerSourceDataContentType: v1.SchedulerDataContentTypeBinary,
+					"team":                                 "billing",
+				},
+			},
+		},
+		Schedule: "0 2 * * *",
+		TimeZone: "America/New_York",
+		RetryConfig: &schedulerpb.RetryConfig{
+			RetryCount:         3,
+			MinBackoffDuration: durationpb.New(10 * time.Second),
+		},
+	}
+	got, err := MakeJob(scheduler, "topic", "job")
+	if err != nil {
+		t.Fatalf("MakeJob got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakeRetryConfigStatus(t *testing.T) {
+	want := &v1.SchedulerRetryConfig{
+		RetryCount:         ptr.Int32(0),
+		MinBackoffDuration: ptr.String("PT5S"),
+		MaxBackoffDuration: ptr.String("PT1H"),
+	}
+	got := MakeRetryConfigStatus(&schedulerpb.RetryConfig{
+		MinBackoffDuration: durationpb.New(5 * time.Second),
+		MaxBackoffDuration: durationpb.New(time.Hour),
+	})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+	if got := MakeRetryConfigStatus(nil); got != nil {
+		t.Errorf("MakeRetryConfigStatus(nil) got=%v, want=nil", got)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	knduckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/reconciler"
 
@@ -32,8 +33,10 @@ import (
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
 	gscheduler "github.com/google/knative-gcp/pkg/gclient/scheduler"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 	"github.com/google/knative-gcp/pkg/utils"
 )
 
@@ -54,6 +57,8 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// Registrar for registering the CloudEvent types of the sources as EventTypes.
+	*eventtype.Registrar
 	// schedulerLister for reading schedulers.
 	schedulerLister listers.CloudSchedulerSourceLister
 
@@ -89,6 +94,14 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, scheduler *v1.CloudSched
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Job failed with: %s", err.Error())
 	}
 	scheduler.Status.MarkJobReady(jobName)
+
+	scheduler.Status.CloudEventAttributes = []knduckv1.CloudEventAttributes{{
+		Type:   schemasv1.CloudSchedulerJobExecutedEventType,
+		Source: schemasv1.CloudSchedulerEventSource(jobName),
+	}}
+	if err := r.ReconcileEventTypes(ctx, scheduler); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, eventtype.ReconcileFailedReason, "Failed to reconcile CloudSchedulerSource EventTypes: %s", err.Error())
+	}
 	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSchedulerSource reconciled: "%s/%s"`, scheduler.Namespace, scheduler.Name)
 }
 
@@ -111,7 +124,7 @@ func (r *Reconciler) reconcileJob(ctx context.Context, scheduler *v1.CloudSchedu
 	defer client.Close()
 
 	// Check if the job exists.
-	_, err = client.GetJob(ctx, &schedulerpb.GetJobRequest{Name: jobName})
+	job, err := client.GetJob(ctx, &schedulerpb.GetJobRequest{Name: jobName})
 	if err != nil {
 		if st, ok := gstatus.FromError(err); !ok {
 			logging.FromContext(ctx).Desugar().Error("Failed from CloudSchedulerSource client while retrieving CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
@@ -119,23 +132,14 @@ func (r *Reconciler) reconcileJob(ctx context.Context, scheduler *v1.CloudSchedu
 		} else if st.Code() == codes.NotFound {
 			// Create the job as it does not exist. For creation, we need a parent, extract it from the jobName.
 			parent := resources.ExtractParentName(jobName)
-			// Add jobName as customAttribute.
-			customAttributes := map[string]string{
-				v1.CloudSchedulerSourceJobName: jobName,
+			desired, err := resources.MakeJob(scheduler, topic, jobName)
+			if err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to make CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
+				return err
 			}
-			_, err = client.CreateJob(ctx, &schedulerpb.CreateJobRequest{
+			job, err = client.CreateJob(ctx, &schedulerpb.CreateJobRequest{
 				Parent: parent,
-				Job: &schedulerpb.Job{
-					Name: jobName,
-					Target: &schedulerpb.Job_PubsubTarget{
-						PubsubTarget: &schedulerpb.PubsubTarget{
-							TopicName:  resources.GeneratePubSubTargetTopic(scheduler, topic),
-							Data:       []byte(scheduler.Spec.Data),
-							Attributes: customAttributes,
-						},
-					},
-					Schedule: scheduler.Spec.Schedule,
-				},
+				Job:    desired,
 			})
 			if err != nil {
 				logging.FromContext(ctx).Desugar().Error("Failed to create CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
@@ -146,6 +150,26 @@ func (r *Reconciler) reconcileJob(ctx context.Context, scheduler *v1.CloudSchedu
 			return err
 		}
 	}
+
+	// Pause or resume the job if its state doesn't match the spec.
+	if scheduler.Spec.Paused && job.State != schedulerpb.Job_PAUSED {
+		job, err = client.PauseJob(ctx, &schedulerpb.PauseJobRequest{Name: jobName})
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to pause CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
+			return err
+		}
+	} else if !scheduler.Spec.Paused && job.State == schedulerpb.Job_PAUSED {
+		job, err = client.ResumeJob(ctx, &schedulerpb.ResumeJobRequest{Name: jobName})
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to resume CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
+			return err
+		}
+	}
+
+	if job.State != schedulerpb.Job_STATE_UNSPECIFIED {
+		scheduler.Status.JobState = job.State.String()
+	}
+	scheduler.Status.RetryConfig = resources.MakeRetryConfigStatus(job.RetryConfig)
 	return nil
 }
 
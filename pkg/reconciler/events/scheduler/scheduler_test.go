@@ -36,6 +36,7 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/ptr"
 	. "knative.dev/pkg/reconciler/testing"
 
 	"github.com/google/knative-gcp/pkg/apis/duck"
@@ -47,10 +48,13 @@ import (
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 	gscheduler "github.com/google/knative-gcp/pkg/gclient/scheduler/testing"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 
+	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
 )
@@ -101,6 +105,12 @@ var (
 	}
 
 	gServiceAccount = "test123@test123.iam.gserviceaccount.com"
+
+	testRetryConfig = &schedulerv1.SchedulerRetryConfig{
+		RetryCount:         ptr.Int32(3),
+		MinBackoffDuration: ptr.String("PT10S"),
+		MaxBackoffDuration: ptr.String("PT10M"),
+	}
 )
 
 func init() {
@@ -1036,7 +1046,84 @@ func TestAllCases(t *testing.T) {
 					reconcilertestingv1.WithCloudSchedulerSourcePullSubscriptionReady,
 					reconcilertestingv1.WithCloudSchedulerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 					reconcilertestingv1.WithCloudSchedulerSourceJobReady(jobName),
+					reconcilertestingv1.WithCloudSchedulerSourceJobState("ENABLED"),
+					reconcilertestingv1.WithCloudSchedulerSourceSinkURI(schedulerSinkURL),
+					reconcilertestingv1.WithCloudSchedulerSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudSchedulerJobExecutedEventType, Source: schemasv1.CloudSchedulerEventSource(jobName)}),
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+			}},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, schedulerName, true),
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", schedulerName),
+				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSchedulerSource reconciled: "%s/%s"`, testNS, schedulerName),
+			},
+		}, {
+			Name: "topic and pullsubscription exist and ready, get job fails with grpc not found error, create paused job with retry config succeeds",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithCloudSchedulerSourcePaused,
+					reconcilertestingv1.WithCloudSchedulerSourceRetryConfig(testRetryConfig),
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(schedulerName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(schedulerName, testNS,
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Secret: &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+							Project: testProject,
+						},
+						AdapterType: string(converters.CloudScheduler),
+					}),
+				),
+				newSink(),
+			},
+			OtherTestData: map[string]interface{}{
+				"scheduler": gscheduler.TestClientData{
+					GetJobErr: gstatus.Error(codes.NotFound, "get-job-induced-error"),
+				},
+			},
+			Key: testNS + "/" + schedulerName,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithCloudSchedulerSourcePaused,
+					reconcilertestingv1.WithCloudSchedulerSourceRetryConfig(testRetryConfig),
+					reconcilertestingv1.WithInitCloudSchedulerSourceConditions,
+					reconcilertestingv1.WithCloudSchedulerSourceTopicReady(testTopicID, testProject),
+					reconcilertestingv1.WithCloudSchedulerSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudSchedulerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudSchedulerSourceJobReady(jobName),
+					reconcilertestingv1.WithCloudSchedulerSourceJobState("PAUSED"),
+					reconcilertestingv1.WithCloudSchedulerSourceRetryConfigStatus(testRetryConfig),
 					reconcilertestingv1.WithCloudSchedulerSourceSinkURI(schedulerSinkURL),
+					reconcilertestingv1.WithCloudSchedulerSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudSchedulerJobExecutedEventType, Source: schemasv1.CloudSchedulerEventSource(jobName)}),
 					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
 				),
 			}},
@@ -1047,6 +1134,147 @@ func TestAllCases(t *testing.T) {
 				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", schedulerName),
 				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSchedulerSource reconciled: "%s/%s"`, testNS, schedulerName),
 			},
+		}, {
+			Name: "topic and pullsubscription exist and ready, paused job exists, resume job succeeds",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(schedulerName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(schedulerName, testNS,
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Secret: &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+							Project: testProject,
+						},
+						AdapterType: string(converters.CloudScheduler),
+					}),
+				),
+				newSink(),
+			},
+			OtherTestData: map[string]interface{}{
+				"scheduler": gscheduler.TestClientData{
+					Job: &schedulerpb.Job{State: schedulerpb.Job_PAUSED},
+				},
+			},
+			Key: testNS + "/" + schedulerName,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithInitCloudSchedulerSourceConditions,
+					reconcilertestingv1.WithCloudSchedulerSourceTopicReady(testTopicID, testProject),
+					reconcilertestingv1.WithCloudSchedulerSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudSchedulerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudSchedulerSourceJobReady(jobName),
+					reconcilertestingv1.WithCloudSchedulerSourceJobState("ENABLED"),
+					reconcilertestingv1.WithCloudSchedulerSourceSinkURI(schedulerSinkURL),
+					reconcilertestingv1.WithCloudSchedulerSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudSchedulerJobExecutedEventType, Source: schemasv1.CloudSchedulerEventSource(jobName)}),
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+			}},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, schedulerName, true),
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", schedulerName),
+				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSchedulerSource reconciled: "%s/%s"`, testNS, schedulerName),
+			},
+		}, {
+			Name: "topic and pullsubscription exist and ready, job exists, pause job fails",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithCloudSchedulerSourcePaused,
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(schedulerName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(schedulerName, testNS,
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Secret: &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+							Project: testProject,
+						},
+						AdapterType: string(converters.CloudScheduler),
+					}),
+				),
+				newSink(),
+			},
+			OtherTestData: map[string]interface{}{
+				"scheduler": gscheduler.TestClientData{
+					Job:         &schedulerpb.Job{State: schedulerpb.Job_ENABLED},
+					PauseJobErr: errors.New("pause-job-induced-error"),
+				},
+			},
+			Key: testNS + "/" + schedulerName,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithCloudSchedulerSourcePaused,
+					reconcilertestingv1.WithInitCloudSchedulerSourceConditions,
+					reconcilertestingv1.WithCloudSchedulerSourceTopicReady(testTopicID, testProject),
+					reconcilertestingv1.WithCloudSchedulerSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudSchedulerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudSchedulerSourceJobNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileJobMsg, "pause-job-induced-error")),
+					reconcilertestingv1.WithCloudSchedulerSourceSinkURI(schedulerSinkURL),
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+			}},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, schedulerName, true),
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", schedulerName),
+				Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Job failed with: pause-job-induced-error"),
+			},
 		}, {
 			Name: "topic and pullsubscription exist and ready, job exists",
 			Objects: []runtime.Object{
@@ -1100,6 +1328,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertestingv1.WithCloudSchedulerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 					reconcilertestingv1.WithCloudSchedulerSourceJobReady(jobName),
 					reconcilertestingv1.WithCloudSchedulerSourceSinkURI(schedulerSinkURL),
+					reconcilertestingv1.WithCloudSchedulerSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudSchedulerJobExecutedEventType, Source: schemasv1.CloudSchedulerEventSource(jobName)}),
 					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
 				),
 			}},
@@ -1298,6 +1527,7 @@ func TestAllCases(t *testing.T) {
 					ConfigWatcher:       cmw,
 				}),
 			Identity:        identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			Registrar:       eventtype.NewRegistrar(ctx),
 			schedulerLister: listers.GetCloudSchedulerSourceLister(),
 			createClientFn:  gscheduler.TestClientCreator(testData["scheduler"]),
 		}
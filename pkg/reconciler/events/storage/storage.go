@@ -18,12 +18,20 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"go.uber.org/zap"
 
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	knduckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/reconciler"
 
@@ -34,6 +42,7 @@ import (
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
 	gstorage "github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
@@ -68,6 +77,8 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// Registrar for registering the CloudEvent types of the sources as EventTypes.
+	*eventtype.Registrar
 	// storageLister for reading storages.
 	storageLister listers.CloudStorageSourceLister
 
@@ -98,22 +109,29 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, storage *v1.CloudStorage
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailed, "Failed to reconcile CloudStorageSource PubSub: %s", err.Error())
 	}
 
-	notification, err := r.reconcileNotification(ctx, storage)
-	if err != nil {
+	if err := r.reconcileNotifications(ctx, storage); err != nil {
 		storage.Status.MarkNotificationNotReady(reconciledNotificationFailed, "Failed to reconcile CloudStorageSource notification: %s", err.Error())
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledNotificationFailed, "Failed to reconcile CloudStorageSource notification: %s", err.Error())
 	}
-	storage.Status.MarkNotificationReady(notification)
+	storage.Status.MarkNotificationReady(storage.Status.NotificationID)
+
+	storage.Status.CloudEventAttributes = getCloudEventAttributes(storage)
+	if err := r.ReconcileEventTypes(ctx, storage); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, eventtype.ReconcileFailedReason, "Failed to reconcile CloudStorageSource EventTypes: %s", err.Error())
+	}
 
 	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudStorageSource reconciled: "%s/%s"`, storage.Namespace, storage.Name)
 }
 
-func (r *Reconciler) reconcileNotification(ctx context.Context, storage *v1.CloudStorageSource) (string, error) {
+// reconcileNotifications reconciles the notifications of all the buckets of the
+// CloudStorageSource, and reports them in its status. The notifications of the buckets that
+// stopped matching the bucket selector are deleted.
+func (r *Reconciler) reconcileNotifications(ctx context.Context, storage *v1.CloudStorageSource) error {
 	if storage.Status.ProjectID == "" {
 		projectID, err := utils.ProjectIDOrDefault(storage.Spec.Project)
 		if err != nil {
 			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
-			return "", err
+			return err
 		}
 		// Set the projectID in the status.
 		storage.Status.ProjectID = projectID
@@ -122,19 +140,136 @@ func (r *Reconciler) reconcileNotification(ctx context.Context, storage *v1.Clou
 	client, err := r.createClientFn(ctx)
 	if err != nil {
 		logging.FromContext(ctx).Desugar().Error("Failed to create CloudStorageSource client", zap.Error(err))
-		return "", err
+		return err
 	}
 	defer client.Close()
 
+	buckets, err := r.resolveBuckets(ctx, client, storage)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to resolve buckets", zap.Error(err))
+		return err
+	}
+
+	previous := notificationStatuses(storage)
+	statuses := make([]v1.BucketNotificationStatus, 0, len(buckets))
+	var errs []string
+	for _, bucket := range buckets {
+		status := v1.BucketNotificationStatus{
+			Bucket:         bucket,
+			NotificationID: previous[bucket],
+		}
+		delete(previous, bucket)
+		id, err := r.reconcileNotification(ctx, client, storage, bucket, status.NotificationID)
+		if err != nil {
+			status.Message = err.Error()
+			errs = append(errs, bucketError(storage, bucket, err))
+		} else {
+			status.NotificationID = id
+			status.Ready = true
+		}
+		statuses = append(statuses, status)
+	}
+
+	// The remaining buckets stopped matching the bucket selector.
+	for _, bucket := range sets.StringKeySet(previous).List() {
+		if err := r.deleteNotification(ctx, client, storage, bucket, previous[bucket]); err != nil {
+			statuses = append(statuses, v1.BucketNotificationStatus{
+				Bucket:         bucket,
+				NotificationID: previous[bucket],
+				Message:        fmt.Sprintf("Failed to delete notification of bucket no longer selected: %s", err.Error()),
+			})
+			errs = append(errs, bucketError(storage, bucket, err))
+		}
+	}
+
+	storage.Status.Notifications = statuses
+	if storage.Spec.Bucket != "" {
+		storage.Status.NotificationID = statuses[0].NotificationID
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	if len(buckets) == 0 {
+		return fmt.Errorf("no bucket of project %q matches the bucket selector", storage.Status.ProjectID)
+	}
+	return nil
+}
+
+// resolveBuckets returns the names of the buckets of the CloudStorageSource.
+func (r *Reconciler) resolveBuckets(ctx context.Context, client gstorage.Client, storage *v1.CloudStorageSource) ([]string, error) {
+	switch {
+	case storage.Spec.BucketSelector != nil:
+		selector, err := metav1.LabelSelectorAsSelector(storage.Spec.BucketSelector)
+		if err != nil {
+			return nil, err
+		}
+		attrs, err := client.Buckets(ctx, storage.Status.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		var buckets []string
+		for _, a := range attrs {
+			if selector.Matches(labels.Set(a.Labels)) {
+				buckets = append(buckets, a.Name)
+			}
+		}
+		sort.Strings(buckets)
+		return buckets, nil
+	case len(storage.Spec.Buckets) > 0:
+		return storage.Spec.Buckets, nil
+	default:
+		return []string{storage.Spec.Bucket}, nil
+	}
+}
+
+// notificationStatuses returns the IDs of the notifications reported in the status of the
+// CloudStorageSource by bucket. The status of sources created before notifications were reported
+// per bucket only holds the notification ID of their bucket.
+// getCloudEventAttributes returns the CloudEvent attributes of the events of each of the buckets
+// of the CloudStorageSource.
+func getCloudEventAttributes(storage *v1.CloudStorageSource) []knduckv1.CloudEventAttributes {
+	var ceAttributes []knduckv1.CloudEventAttributes
+	for _, n := range storage.Status.Notifications {
+		for _, eventType := range storage.Spec.EventTypes {
+			ceAttributes = append(ceAttributes, knduckv1.CloudEventAttributes{
+				Type:   eventType,
+				Source: schemasv1.CloudStorageEventSource(n.Bucket),
+			})
+		}
+	}
+	return ceAttributes
+}
+
+func notificationStatuses(storage *v1.CloudStorageSource) map[string]string {
+	ids := make(map[string]string, len(storage.Status.Notifications))
+	for _, n := range storage.Status.Notifications {
+		ids[n.Bucket] = n.NotificationID
+	}
+	if len(ids) == 0 && storage.Spec.Bucket != "" && storage.Status.NotificationID != "" {
+		ids[storage.Spec.Bucket] = storage.Status.NotificationID
+	}
+	return ids
+}
+
+// bucketError returns the message of the error of a bucket, which names the bucket unless the
+// CloudStorageSource has a single bucket.
+func bucketError(storage *v1.CloudStorageSource, bucket string, err error) string {
+	if storage.Spec.Bucket != "" {
+		return err.Error()
+	}
+	return fmt.Sprintf("bucket %s: %s", bucket, err.Error())
+}
+
+func (r *Reconciler) reconcileNotification(ctx context.Context, client gstorage.Client, storage *v1.CloudStorageSource, bucketName, notificationID string) (string, error) {
 	// Load the Bucket.
-	bucket := client.Bucket(storage.Spec.Bucket)
+	bucket := client.Bucket(bucketName)
 	//Check whether Bucket exists or not
 	if _, err := bucket.Attrs(ctx); err != nil {
 		if err == ErrBucketNotExist {
-			logging.FromContext(ctx).Desugar().Error("Bucket doesn't exist", zap.String("bucketName", storage.Spec.Bucket), zap.Error(err))
+			logging.FromContext(ctx).Desugar().Error("Bucket doesn't exist", zap.String("bucketName", bucketName), zap.Error(err))
 			return "", err
 		}
-		logging.FromContext(ctx).Desugar().Error("Failed to fetch attrs of bucket", zap.String("bucketName", storage.Spec.Bucket), zap.Error(err))
+		logging.FromContext(ctx).Desugar().Error("Failed to fetch attrs of bucket", zap.String("bucketName", bucketName), zap.Error(err))
 		return "", err
 	}
 
@@ -145,21 +280,12 @@ func (r *Reconciler) reconcileNotification(ctx context.Context, storage *v1.Clou
 	}
 
 	// If the notification does exist, then return its ID.
-	if existing, ok := notifications[storage.Status.NotificationID]; ok {
+	if existing, ok := notifications[notificationID]; ok {
 		return existing.ID, nil
 	}
 
 	// If the notification does not exist, then create it.
-
-	nc := &Notification{
-		TopicProjectID:   storage.Status.ProjectID,
-		TopicID:          storage.Status.TopicID,
-		PayloadFormat:    JSONPayload,
-		EventTypes:       r.toCloudStorageSourceEventTypes(storage.Spec.EventTypes),
-		ObjectNamePrefix: storage.Spec.ObjectNamePrefix,
-	}
-
-	notification, err := bucket.AddNotification(ctx, nc)
+	notification, err := bucket.AddNotification(ctx, makeNotification(storage))
 	if err != nil {
 		logging.FromContext(ctx).Desugar().Error("Failed to create CloudStorageSource notification", zap.Error(err))
 		return "", err
@@ -167,7 +293,23 @@ func (r *Reconciler) reconcileNotification(ctx context.Context, storage *v1.Clou
 	return notification.ID, nil
 }
 
-func (r *Reconciler) toCloudStorageSourceEventTypes(eventTypes []string) []string {
+// makeNotification makes the notification added to the buckets of the CloudStorageSource.
+func makeNotification(storage *v1.CloudStorageSource) *Notification {
+	payloadFormat := storage.Spec.PayloadFormat
+	if payloadFormat == "" {
+		payloadFormat = JSONPayload
+	}
+	return &Notification{
+		TopicProjectID:   storage.Status.ProjectID,
+		TopicID:          storage.Status.TopicID,
+		PayloadFormat:    payloadFormat,
+		EventTypes:       toCloudStorageSourceEventTypes(storage.Spec.EventTypes),
+		ObjectNamePrefix: storage.Spec.ObjectNamePrefix,
+		CustomAttributes: storage.Spec.Attributes,
+	}
+}
+
+func toCloudStorageSourceEventTypes(eventTypes []string) []string {
 	storageTypes := make([]string, 0, len(eventTypes))
 	for _, eventType := range eventTypes {
 		storageTypes = append(storageTypes, storageEventTypes[eventType])
@@ -175,11 +317,11 @@ func (r *Reconciler) toCloudStorageSourceEventTypes(eventTypes []string) []strin
 	return storageTypes
 }
 
-// deleteNotification looks at the status.NotificationID and if non-empty,
-// hence indicating that we have created a notification successfully
-// in the CloudStorageSource, remove it.
-func (r *Reconciler) deleteNotification(ctx context.Context, storage *v1.CloudStorageSource) error {
-	if storage.Status.NotificationID == "" {
+// deleteNotifications deletes the notifications reported in the status of the
+// CloudStorageSource.
+func (r *Reconciler) deleteNotifications(ctx context.Context, storage *v1.CloudStorageSource) error {
+	ids := notificationStatuses(storage)
+	if len(ids) == 0 {
 		return nil
 	}
 
@@ -193,17 +335,31 @@ func (r *Reconciler) deleteNotification(ctx context.Context, storage *v1.CloudSt
 	}
 	defer client.Close()
 
+	for _, bucket := range sets.StringKeySet(ids).List() {
+		if err := r.deleteNotification(ctx, client, storage, bucket, ids[bucket]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteNotification deletes the notification of a bucket if both still exist.
+func (r *Reconciler) deleteNotification(ctx context.Context, client gstorage.Client, storage *v1.CloudStorageSource, bucketName, notificationID string) error {
+	if notificationID == "" {
+		return nil
+	}
+
 	// Load the Bucket.
-	bucket := client.Bucket(storage.Spec.Bucket)
+	bucket := client.Bucket(bucketName)
 
 	// Check whether bucket exists or not
 	if _, err := bucket.Attrs(ctx); err != nil {
 		// If the bucket was already deleted, then we should  proceed.
 		if err == ErrBucketNotExist {
-			logging.FromContext(ctx).Desugar().Info("Bucket does not exist.", zap.String("bucketName", storage.Spec.Bucket), zap.Error(err))
+			logging.FromContext(ctx).Desugar().Info("Bucket does not exist.", zap.String("bucketName", bucketName), zap.Error(err))
 			return nil
 		}
-		logging.FromContext(ctx).Desugar().Error("Failed to fetch attrs of bucket", zap.String("bucketName", storage.Spec.Bucket), zap.Error(err))
+		logging.FromContext(ctx).Desugar().Error("Failed to fetch attrs of bucket", zap.String("bucketName", bucketName), zap.Error(err))
 		storage.Status.MarkNotificationUnknown(deleteNotificationFailed, "Failed to fetch attrs of bucket: %s", err.Error())
 		return err
 	}
@@ -218,19 +374,19 @@ func (r *Reconciler) deleteNotification(ctx context.Context, storage *v1.CloudSt
 	// This is bit wonky because, we could always just try to delete, but figuring out
 	// if an error returned is NotFound seems to not really work, so, we'll try
 	// checking first the list and only then deleting.
-	if existing, ok := notifications[storage.Status.NotificationID]; ok {
+	if existing, ok := notifications[notificationID]; ok {
 		logging.FromContext(ctx).Desugar().Debug("Found existing notification", zap.Any("notification", existing))
-		err = bucket.DeleteNotification(ctx, storage.Status.NotificationID)
+		err = bucket.DeleteNotification(ctx, notificationID)
 		if err == nil {
-			logging.FromContext(ctx).Desugar().Debug("Deleted Notification", zap.String("notificationId", storage.Status.NotificationID))
+			logging.FromContext(ctx).Desugar().Debug("Deleted Notification", zap.String("notificationId", notificationID))
 			return nil
 		}
 		if st, ok := gstatus.FromError(err); !ok {
-			logging.FromContext(ctx).Desugar().Error("Failed from CloudStorageSource client while deleting CloudStorageSource notification", zap.String("notificationId", storage.Status.NotificationID), zap.Error(err))
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudStorageSource client while deleting CloudStorageSource notification", zap.String("notificationId", notificationID), zap.Error(err))
 			storage.Status.MarkNotificationUnknown(deleteNotificationFailed, "Failed from CloudStorageSource client while deleting CloudStorageSource notification: %s", err.Error())
 			return err
 		} else if st.Code() != codes.NotFound {
-			logging.FromContext(ctx).Desugar().Error("Failed to delete CloudStorageSource notification", zap.String("notificationId", storage.Status.NotificationID), zap.Error(err))
+			logging.FromContext(ctx).Desugar().Error("Failed to delete CloudStorageSource notification", zap.String("notificationId", notificationID), zap.Error(err))
 			storage.Status.MarkNotificationUnknown(deleteNotificationFailed, "Failed to delete CloudStorageSource notification: %s", err.Error())
 			return err
 		}
@@ -249,7 +405,7 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, storage *v1.CloudStorageS
 	}
 
 	logging.FromContext(ctx).Desugar().Debug("Deleting CloudStorageSource notification")
-	if err := r.deleteNotification(ctx, storage); err != nil {
+	if err := r.deleteNotifications(ctx, storage); err != nil {
 		return reconciler.NewEvent(corev1.EventTypeWarning, deleteNotificationFailed, "Failed to delete CloudStorageSource notification: %s", err.Error())
 	}
 
@@ -25,6 +25,7 @@ import (
 	reconcilertestingv1 "github.com/google/knative-gcp/pkg/reconciler/testing/v1"
 
 	"cloud.google.com/go/storage"
+	"github.com/google/go-cmp/cmp"
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
@@ -50,6 +51,7 @@ import (
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 	gstorage "github.com/google/knative-gcp/pkg/gclient/storage/testing"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
@@ -839,6 +841,10 @@ func TestAllCases(t *testing.T) {
 					reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 					reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
 					reconcilertestingv1.WithCloudStorageSourceNotificationNotReady(reconciledNotificationFailed, fmt.Sprintf("%s: %s", failedToReconcileNotificationMsg, "bucket-notifications-induced-error")),
+					reconcilertestingv1.WithCloudStorageSourceBucketNotifications(storagev1.BucketNotificationStatus{
+						Bucket:  bucket,
+						Message: "bucket-notifications-induced-error",
+					}),
 					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
 				),
 			}},
@@ -912,6 +918,10 @@ func TestAllCases(t *testing.T) {
 					reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 					reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
 					reconcilertestingv1.WithCloudStorageSourceNotificationNotReady(reconciledNotificationFailed, fmt.Sprintf("%s: %s", failedToReconcileNotificationMsg, "bucket-add-notification-induced-error")),
+					reconcilertestingv1.WithCloudStorageSourceBucketNotifications(storagev1.BucketNotificationStatus{
+						Bucket:  bucket,
+						Message: "bucket-add-notification-induced-error",
+					}),
 					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
 				),
 			}},
@@ -985,6 +995,10 @@ func TestAllCases(t *testing.T) {
 					reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 					reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
 					reconcilertestingv1.WithCloudStorageSourceNotificationNotReady(reconciledNotificationFailed, fmt.Sprintf("%s: %s", failedToReconcileNotificationMsg, "storage: bucket doesn't exist")),
+					reconcilertestingv1.WithCloudStorageSourceBucketNotifications(storagev1.BucketNotificationStatus{
+						Bucket:  bucket,
+						Message: storage.ErrBucketNotExist.Error(),
+					}),
 					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
 				),
 			}},
@@ -1057,7 +1071,194 @@ func TestAllCases(t *testing.T) {
 					reconcilertestingv1.WithCloudStorageSourcePullSubscriptionReady,
 					reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
 					reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
+					reconcilertestingv1.WithCloudStorageSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudStorageObjectFinalizedEventType, Source: schemasv1.CloudStorageEventSource(bucket)}),
 					reconcilertestingv1.WithCloudStorageSourceNotificationReady(notificationId),
+					reconcilertestingv1.WithCloudStorageSourceBucketNotifications(storagev1.BucketNotificationStatus{
+						Bucket:         bucket,
+						NotificationID: notificationId,
+						Ready:          true,
+					}),
+					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				),
+			}},
+		},
+		{
+			Name: "bucket selector notification created and unselected bucket notification deleted",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+					reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceBucketSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"team": "billing"}}),
+					reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+					reconcilertestingv1.WithCloudStorageSourceBucketNotifications(storagev1.BucketNotificationStatus{
+						Bucket:         "my-old-bucket",
+						NotificationID: "246",
+						Ready:          true,
+					}),
+					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(storageName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(storageName, testNS,
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Project: testProject,
+							Secret:  &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+						},
+						AdapterType: string(converters.CloudStorage),
+					}),
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+				),
+				newSink(),
+			},
+			Key: testNS + "/" + storageName,
+			OtherTestData: map[string]interface{}{
+				"storage": gstorage.TestClientData{
+					Buckets: []*storage.BucketAttrs{
+						{Name: bucket, Labels: map[string]string{"team": "billing"}},
+						{Name: "my-other-bucket", Labels: map[string]string{"team": "other"}},
+					},
+					BucketData: gstorage.TestBucketData{
+						AddNotificationID: notificationId,
+						Notifications: map[string]*storage.Notification{
+							"246": {
+								ID: "246",
+							},
+						},
+					},
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudStorageSource reconciled: "%s/%s"`, testNS, storageName),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, storageName, true),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+					reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceStatusObservedGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceBucketSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"team": "billing"}}),
+					reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+					reconcilertestingv1.WithInitCloudStorageSourceConditions,
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceTopicReady(testTopicID),
+					reconcilertestingv1.WithCloudStorageSourceProjectID(testProject),
+					reconcilertestingv1.WithCloudStorageSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
+					reconcilertestingv1.WithCloudStorageSourceCloudEventAttributes(duckv1.CloudEventAttributes{Type: schemasv1.CloudStorageObjectFinalizedEventType, Source: schemasv1.CloudStorageEventSource(bucket)}),
+					reconcilertestingv1.WithCloudStorageSourceNotificationReady(""),
+					reconcilertestingv1.WithCloudStorageSourceBucketNotifications(storagev1.BucketNotificationStatus{
+						Bucket:         bucket,
+						NotificationID: notificationId,
+						Ready:          true,
+					}),
+					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				),
+			}},
+		},
+		{
+			Name: "notification of one of multiple buckets fails",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+					reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceBuckets(bucket, "my-other-bucket"),
+					reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(storageName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(storageName, testNS,
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Project: testProject,
+							Secret:  &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+						},
+						AdapterType: string(converters.CloudStorage),
+					}),
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+				),
+				newSink(),
+			},
+			Key: testNS + "/" + storageName,
+			OtherTestData: map[string]interface{}{
+				"storage": gstorage.TestClientData{
+					BucketData: gstorage.TestBucketData{
+						AddNotificationID: notificationId,
+					},
+					BucketsData: map[string]gstorage.TestBucketData{
+						"my-other-bucket": {
+							AttrsError: storage.ErrBucketNotExist,
+						},
+					},
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+				Eventf(corev1.EventTypeWarning, reconciledNotificationFailed, fmt.Sprintf("%s: bucket my-other-bucket: %s", failedToReconcileNotificationMsg, "storage: bucket doesn't exist")),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, storageName, true),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+					reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceStatusObservedGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceBuckets(bucket, "my-other-bucket"),
+					reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+					reconcilertestingv1.WithInitCloudStorageSourceConditions,
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceTopicReady(testTopicID),
+					reconcilertestingv1.WithCloudStorageSourceProjectID(testProject),
+					reconcilertestingv1.WithCloudStorageSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
+					reconcilertestingv1.WithCloudStorageSourceNotificationNotReady(reconciledNotificationFailed, fmt.Sprintf("%s: bucket my-other-bucket: %s", failedToReconcileNotificationMsg, "storage: bucket doesn't exist")),
+					reconcilertestingv1.WithCloudStorageSourceBucketNotifications(storagev1.BucketNotificationStatus{
+						Bucket:         bucket,
+						NotificationID: notificationId,
+						Ready:          true,
+					}, storagev1.BucketNotificationStatus{
+						Bucket:  "my-other-bucket",
+						Message: storage.ErrBucketNotExist.Error(),
+					}),
 					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
 				),
 			}},
@@ -1317,6 +1518,7 @@ func TestAllCases(t *testing.T) {
 					ConfigWatcher:       cmw,
 				}),
 			Identity:       identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			Registrar:      eventtype.NewRegistrar(ctx),
 			storageLister:  listers.GetCloudStorageSourceLister(),
 			createClientFn: gstorage.TestClientCreator(testData["storage"]),
 		}
@@ -1324,3 +1526,32 @@ func TestAllCases(t *testing.T) {
 	}))
 
 }
+
+func TestMakeNotification(t *testing.T) {
+	s := reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+		reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+		reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+		reconcilertestingv1.WithCloudStorageSourceProjectID(testProject),
+		reconcilertestingv1.WithCloudStorageSourceTopicID(testTopicID),
+	)
+	s.Spec.Attributes = map[string]string{"team": "billing"}
+
+	want := &storage.Notification{
+		TopicProjectID: testProject,
+		TopicID:        testTopicID,
+		PayloadFormat:  storage.JSONPayload,
+		EventTypes:     []string{"OBJECT_FINALIZE"},
+		CustomAttributes: map[string]string{
+			"team": "billing",
+		},
+	}
+	if diff := cmp.Diff(want, makeNotification(s)); diff != "" {
+		t.Errorf("unexpected notification (-want, +got) = %v", diff)
+	}
+
+	s.Spec.PayloadFormat = storagev1.CloudStorageSourcePayloadFormatNone
+	want.PayloadFormat = storage.NoPayload
+	if diff := cmp.Diff(want, makeNotification(s)); diff != "" {
+		t.Errorf("unexpected notification with no payload (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventtype contains the reconciler of the EventTypes of the sources.
+package eventtype
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	eventtypeinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1beta1/eventtype"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+
+	duck "github.com/google/knative-gcp/pkg/duck/v1"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype/resources"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	// ReconcileFailedReason is the reason of the events of the sources whose EventTypes failed to
+	// be reconciled.
+	ReconcileFailedReason = "EventTypesReconcileFailed"
+)
+
+type envConfig struct {
+	// Enabled makes the source reconcilers register the CloudEvent types their sources send to a
+	// Broker as EventTypes.
+	Enabled bool `envconfig:"EVENT_TYPE_REGISTRATION_ENABLED" default:"false"`
+}
+
+// NewRegistrar creates a Registrar, enabled when the EVENT_TYPE_REGISTRATION_ENABLED environment
+// variable of the controller is true.
+func NewRegistrar(ctx context.Context) *Registrar {
+	var env envConfig
+	if err := envconfig.Process("", &env); err != nil {
+		logging.FromContext(ctx).Desugar().Fatal("Failed to process env var", zap.Error(err))
+	}
+	r := &Registrar{
+		eventingClient: eventingclient.Get(ctx),
+		enabled:        env.Enabled,
+	}
+	if r.enabled {
+		// The lister is only used when the registration is enabled.
+		r.eventTypeLister = eventtypeinformer.Get(ctx).Lister()
+	}
+	return r
+}
+
+// Registrar registers the CloudEvent types in the status of the sources as EventTypes of the
+// Broker they sink to.
+type Registrar struct {
+	eventingClient eventingclientset.Interface
+	// eventTypeLister is nil if the registration is disabled.
+	eventTypeLister eventinglisters.EventTypeLister
+	enabled         bool
+}
+
+// ReconcileEventTypes creates an EventType, owned by the source, for each of the CloudEvent
+// attributes in the status of the source if the source sinks to a Broker, and deletes the EventTypes
+// of the source that are no longer needed. It is a no-op if the registration is disabled.
+func (r *Registrar) ReconcileEventTypes(ctx context.Context, pubsubable duck.PubSubable) error {
+	if !r.enabled {
+		return nil
+	}
+	desired, err := desiredEventTypes(pubsubable)
+	if err != nil {
+		return err
+	}
+
+	namespace := pubsubable.GetObjectMeta().GetNamespace()
+	eventTypes := r.eventingClient.EventingV1beta1().EventTypes(namespace)
+	existing, err := r.eventTypeLister.EventTypes(namespace).List(
+		labels.SelectorFromSet(resources.GetLabels(pubsubable.GetObjectMeta().GetName())))
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to list EventTypes", zap.Error(err))
+		return fmt.Errorf("failed to list EventTypes: %w", err)
+	}
+
+	current := make(map[string]*eventingv1beta1.EventType, len(existing))
+	for _, et := range existing {
+		if !metav1.IsControlledBy(et, pubsubable.GetObjectMeta()) {
+			continue
+		}
+		current[et.Name] = et
+	}
+
+	for _, et := range desired {
+		cur, ok := current[et.Name]
+		if !ok {
+			logging.FromContext(ctx).Desugar().Debug("Creating EventType", zap.Any("eventType", et))
+			if _, err := eventTypes.Create(ctx, et, metav1.CreateOptions{}); err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to create EventType", zap.Any("eventType", et), zap.Error(err))
+				return fmt.Errorf("failed to create EventType: %w", err)
+			}
+			continue
+		}
+		delete(current, et.Name)
+		if equality.Semantic.DeepEqual(et.Spec, cur.Spec) {
+			continue
+		}
+		updated := cur.DeepCopy()
+		updated.Spec = et.Spec
+		logging.FromContext(ctx).Desugar().Debug("Updating EventType", zap.Any("eventType", updated))
+		if _, err := eventTypes.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to update EventType", zap.Any("eventType", updated), zap.Error(err))
+			return fmt.Errorf("failed to update EventType: %w", err)
+		}
+	}
+
+	// Garbage collect the EventTypes of the CloudEvent types the source no longer sends to a Broker.
+	for _, et := range existing {
+		if _, ok := current[et.Name]; !ok {
+			continue
+		}
+		logging.FromContext(ctx).Desugar().Debug("Deleting EventType", zap.String("name", et.Name))
+		if err := eventTypes.Delete(ctx, et.Name, metav1.DeleteOptions{}); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to delete EventType", zap.String("name", et.Name), zap.Error(err))
+			return fmt.Errorf("failed to delete EventType: %w", err)
+		}
+	}
+	return nil
+}
+
+// desiredEventTypes returns the EventTypes of the CloudEvent attributes in the status of the
+// source, in order, or none if the source doesn't sink to a Broker in its namespace.
+func desiredEventTypes(pubsubable duck.PubSubable) ([]*eventingv1beta1.EventType, error) {
+	broker := sinkBroker(pubsubable)
+	if broker == "" {
+		return nil, nil
+	}
+	var desired []*eventingv1beta1.EventType
+	seen := make(map[string]bool)
+	for _, attr := range pubsubable.PubSubStatus().CloudEventAttributes {
+		if attr.Type == "" {
+			continue
+		}
+		source, err := apis.ParseURL(attr.Source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source %q of CloudEvent type %q: %w", attr.Source, attr.Type, err)
+		}
+		args := &resources.EventTypeArgs{
+			Owner:  pubsubable,
+			Broker: broker,
+			Type:   attr.Type,
+			Source: source,
+		}
+		if info, ok := schemasv1.LookupEventType(attr.Type); ok {
+			args.Description = info.Description
+			// The data schemas are constants, they are always valid URLs.
+			args.Schema, _ = apis.ParseURL(info.DataSchema)
+		}
+		et := resources.MakeEventType(args)
+		if seen[et.Name] {
+			continue
+		}
+		seen[et.Name] = true
+		desired = append(desired, et)
+	}
+	return desired, nil
+}
+
+// sinkBroker returns the name of the Broker the source sinks to, or the empty string if the sink of
+// the source isn't a Broker in the namespace of the source. EventTypes can't be owned by a source
+// in another namespace.
+func sinkBroker(pubsubable duck.PubSubable) string {
+	ref := pubsubable.PubSubSpec().Sink.Ref
+	if ref == nil || ref.Kind != "Broker" {
+		return ""
+	}
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil || gv.Group != eventing.GroupName {
+		return ""
+	}
+	if ref.Namespace != "" && ref.Namespace != pubsubable.GetObjectMeta().GetNamespace() {
+		return ""
+	}
+	return ref.Name
+}
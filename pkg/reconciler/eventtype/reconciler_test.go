@@ -0,0 +1,250 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventtype
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	fakeeventingclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype/resources"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	testNS     = "testnamespace"
+	sourceName = "my-source"
+	sourceUID  = "my-source-uid"
+	brokerName = "default"
+	jobName    = "projects/p/locations/l/jobs/j"
+)
+
+var (
+	brokerSink = duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "eventing.knative.dev/v1",
+			Kind:       "Broker",
+			Name:       brokerName,
+		},
+	}
+	serviceSink = duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Name:       "event-display",
+		},
+	}
+	executedAttributes = duckv1.CloudEventAttributes{
+		Type:   schemasv1.CloudSchedulerJobExecutedEventType,
+		Source: schemasv1.CloudSchedulerEventSource(jobName),
+	}
+)
+
+func newSource(sink duckv1.Destination, ceAttributes ...duckv1.CloudEventAttributes) *v1.CloudSchedulerSource {
+	return &v1.CloudSchedulerSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sourceName,
+			Namespace: testNS,
+			UID:       sourceUID,
+		},
+		Spec: v1.CloudSchedulerSourceSpec{
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				SourceSpec: duckv1.SourceSpec{
+					Sink: sink,
+				},
+			},
+		},
+		Status: v1.CloudSchedulerSourceStatus{
+			PubSubStatus: gcpduckv1.PubSubStatus{
+				CloudEventAttributes: ceAttributes,
+			},
+		},
+	}
+}
+
+func newEventType(source *v1.CloudSchedulerSource, ceAttributes duckv1.CloudEventAttributes) *eventingv1beta1.EventType {
+	ceSource, _ := apis.ParseURL(ceAttributes.Source)
+	schema, _ := apis.ParseURL(schemasv1.CloudSchedulerEventDataSchema)
+	return resources.MakeEventType(&resources.EventTypeArgs{
+		Owner:       source,
+		Broker:      brokerName,
+		Type:        ceAttributes.Type,
+		Source:      ceSource,
+		Schema:      schema,
+		Description: "A Cloud Scheduler job was executed.",
+	})
+}
+
+// newEventTypeLister returns a lister of the given EventTypes.
+func newEventTypeLister(t *testing.T, objects ...runtime.Object) eventinglisters.EventTypeLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objects {
+		if err := indexer.Add(obj); err != nil {
+			t.Fatalf("Failed to add %v to the indexer: %v", obj, err)
+		}
+	}
+	return eventinglisters.NewEventTypeLister(indexer)
+}
+
+func TestReconcileEventTypes(t *testing.T) {
+	source := newSource(brokerSink, executedAttributes)
+	wanted := newEventType(source, executedAttributes)
+
+	outdated := wanted.DeepCopy()
+	outdated.Spec.Description = "outdated"
+
+	stale := newEventType(source, duckv1.CloudEventAttributes{Type: "com.example.stale"})
+
+	foreign := newEventType(source, duckv1.CloudEventAttributes{Type: "com.example.foreign"})
+	foreign.OwnerReferences[0].UID = types.UID("other-uid")
+
+	testCases := []struct {
+		name        string
+		disabled    bool
+		source      *v1.CloudSchedulerSource
+		objects     []runtime.Object
+		wantCreates []runtime.Object
+		wantUpdates []runtime.Object
+		wantDeletes []string
+	}{{
+		name:     "registration disabled",
+		disabled: true,
+		source:   source,
+	}, {
+		name:        "event type created",
+		source:      source,
+		wantCreates: []runtime.Object{wanted},
+	}, {
+		name:    "event type up to date",
+		source:  source,
+		objects: []runtime.Object{wanted},
+	}, {
+		name:        "event type updated",
+		source:      source,
+		objects:     []runtime.Object{outdated},
+		wantUpdates: []runtime.Object{wanted},
+	}, {
+		name:        "stale event type deleted, foreign event type kept",
+		source:      source,
+		objects:     []runtime.Object{wanted, stale, foreign},
+		wantDeletes: []string{stale.Name},
+	}, {
+		name:        "sink is not a broker, event types deleted",
+		source:      newSource(serviceSink, executedAttributes),
+		objects:     []runtime.Object{wanted},
+		wantDeletes: []string{wanted.Name},
+	}, {
+		name: "broker in another namespace, no event types",
+		source: newSource(duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: "eventing.knative.dev/v1",
+				Kind:       "Broker",
+				Name:       brokerName,
+				Namespace:  "other",
+			},
+		}, executedAttributes),
+	}, {
+		name:        "event types of the same type and source deduplicated",
+		source:      newSource(brokerSink, executedAttributes, executedAttributes),
+		wantCreates: []runtime.Object{wanted},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			client := fakeeventingclientset.NewSimpleClientset(tc.objects...)
+			r := &Registrar{
+				eventingClient:  client,
+				eventTypeLister: newEventTypeLister(t, tc.objects...),
+				enabled:         !tc.disabled,
+			}
+			if err := r.ReconcileEventTypes(ctx, tc.source); err != nil {
+				t.Fatalf("ReconcileEventTypes() = %v", err)
+			}
+
+			var creates, updates []runtime.Object
+			var deletes []string
+			for _, action := range client.Actions() {
+				switch action.GetVerb() {
+				case "list", "watch":
+					t.Errorf("Unexpected %s of EventTypes, they must be read from the lister", action.GetVerb())
+				case "create":
+					creates = append(creates, action.(clientgotesting.CreateAction).GetObject())
+				case "update":
+					updates = append(updates, action.(clientgotesting.UpdateAction).GetObject())
+				case "delete":
+					deletes = append(deletes, action.(clientgotesting.DeleteAction).GetName())
+				}
+			}
+			if diff := cmp.Diff(tc.wantCreates, creates); diff != "" {
+				t.Errorf("Unexpected creates (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantUpdates, updates); diff != "" {
+				t.Errorf("Unexpected updates (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDeletes, deletes); diff != "" {
+				t.Errorf("Unexpected deletes (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileEventTypesCreateError(t *testing.T) {
+	client := fakeeventingclientset.NewSimpleClientset()
+	client.PrependReactor("create", "eventtypes", func(clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("inducing failure for create eventtypes")
+	})
+	r := &Registrar{
+		eventingClient:  client,
+		eventTypeLister: newEventTypeLister(t),
+		enabled:         true,
+	}
+	if err := r.ReconcileEventTypes(context.Background(), newSource(brokerSink, executedAttributes)); err == nil {
+		t.Error("ReconcileEventTypes() = nil, want error")
+	}
+}
+
+func TestNewRegistrarDisabledWithoutInformer(t *testing.T) {
+	os.Unsetenv("EVENT_TYPE_REGISTRATION_ENABLED")
+	// The context has no EventType informer, which must not be needed when the registration is
+	// disabled.
+	ctx, _ := fakeeventingclient.With(logtesting.TestContextWithLogger(t))
+	r := NewRegistrar(ctx)
+	if r.enabled {
+		t.Error("NewRegistrar() is enabled, want disabled")
+	}
+	if err := r.ReconcileEventTypes(ctx, newSource(brokerSink, executedAttributes)); err != nil {
+		t.Errorf("ReconcileEventTypes() = %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"crypto/md5"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents"
+)
+
+// EventTypeArgs are the arguments needed to create an EventType of a source.
+type EventTypeArgs struct {
+	Owner       kmeta.OwnerRefable
+	Broker      string
+	Type        string
+	Source      *apis.URL
+	Schema      *apis.URL
+	Description string
+}
+
+// MakeEventType generates an EventType, in the namespace of its source, of the CloudEvents the
+// source sends to a Broker.
+func MakeEventType(args *EventTypeArgs) *eventingv1beta1.EventType {
+	return &eventingv1beta1.EventType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            GenerateEventTypeName(args.Owner, args.Type, args.Source),
+			Namespace:       args.Owner.GetObjectMeta().GetNamespace(),
+			Labels:          GetLabels(args.Owner.GetObjectMeta().GetName()),
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(args.Owner)},
+		},
+		Spec: eventingv1beta1.EventTypeSpec{
+			Type:        args.Type,
+			Source:      args.Source,
+			Schema:      args.Schema,
+			Broker:      args.Broker,
+			Description: args.Description,
+		},
+	}
+}
+
+// GenerateEventTypeName generates the name of the EventType of a source for the given CloudEvent
+// type and source. The name is unique per CloudEvent type and source, and stable across reconciles.
+func GenerateEventTypeName(owner kmeta.OwnerRefable, eventType string, source *apis.URL) string {
+	return kmeta.ChildName(owner.GetObjectMeta().GetName(), fmt.Sprintf("-%x", md5.Sum([]byte(eventType+" "+source.String()))))
+}
+
+// GetLabels returns the labels of the EventTypes of the named source.
+func GetLabels(source string) map[string]string {
+	return map[string]string{
+		intevents.SourceLabelKey: source,
+	}
+}
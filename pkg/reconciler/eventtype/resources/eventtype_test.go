@@ -0,0 +1,98 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"github.com/google/knative-gcp/pkg/apis/intevents"
+)
+
+func newSource(name string) *v1.CloudSchedulerSource {
+	return &v1.CloudSchedulerSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "testnamespace",
+			UID:       "test-scheduler-uid",
+		},
+	}
+}
+
+func TestMakeEventType(t *testing.T) {
+	source := newSource("my-source")
+	ceSource := apis.HTTP("example.com")
+	schema := apis.HTTP("example.com/schema")
+	got := MakeEventType(&EventTypeArgs{
+		Owner:       source,
+		Broker:      "default",
+		Type:        "com.example.type",
+		Source:      ceSource,
+		Schema:      schema,
+		Description: "An example event.",
+	})
+
+	want := &eventingv1beta1.EventType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateEventTypeName(source, "com.example.type", ceSource),
+			Namespace: "testnamespace",
+			Labels: map[string]string{
+				intevents.SourceLabelKey: "my-source",
+			},
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(source)},
+		},
+		Spec: eventingv1beta1.EventTypeSpec{
+			Type:        "com.example.type",
+			Source:      ceSource,
+			Schema:      schema,
+			Broker:      "default",
+			Description: "An example event.",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestGenerateEventTypeName(t *testing.T) {
+	source := newSource("my-source")
+	ceSource := apis.HTTP("example.com")
+
+	name := GenerateEventTypeName(source, "com.example.type", ceSource)
+	if !strings.HasPrefix(name, "my-source-") {
+		t.Errorf("GenerateEventTypeName() = %q, want prefix %q", name, "my-source-")
+	}
+	if again := GenerateEventTypeName(source, "com.example.type", ceSource); again != name {
+		t.Errorf("GenerateEventTypeName() is not stable, got %q and %q", name, again)
+	}
+	if other := GenerateEventTypeName(source, "com.example.other", ceSource); other == name {
+		t.Errorf("GenerateEventTypeName() = %q for different types", name)
+	}
+	if other := GenerateEventTypeName(source, "com.example.type", nil); other == name {
+		t.Errorf("GenerateEventTypeName() = %q for different sources", name)
+	}
+	if long := GenerateEventTypeName(newSource(strings.Repeat("a", 100)), "com.example.type", ceSource); len(long) > 63 {
+		t.Errorf("GenerateEventTypeName() = %q, longer than 63 characters", long)
+	}
+}
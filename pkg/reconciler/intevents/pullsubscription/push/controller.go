@@ -0,0 +1,125 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package push
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/pullsubscription"
+	pullsubscriptionreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1/pullsubscription"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
+	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
+)
+
+const (
+	// reconcilerName is the name of the reconciler
+	reconcilerName = "PushPullSubscriptions"
+
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "events-system-pubsub-push-pullsubscription-controller"
+
+	resourceGroup = "pullsubscriptions.internal.events.cloud.google.com"
+)
+
+type envConfig struct {
+	// ReceiverURL is the HTTPS URL at which Pub/Sub reaches the shared push receiver. If empty, the
+	// PullSubscriptions in the push delivery mode are not reconciled.
+	ReceiverURL string `envconfig:"PUBSUB_PUSH_RECEIVER_URL"`
+
+	// ServiceAccount is the email of the Google service account Pub/Sub issues the OIDC tokens of
+	// the push requests for.
+	ServiceAccount string `envconfig:"PUBSUB_PUSH_SERVICE_ACCOUNT"`
+}
+
+type Constructor injection.ControllerConstructor
+
+// NewConstructor creates a constructor to make a push PullSubscription controller.
+func NewConstructor(ipm iam.IAMPolicyManager, gcpas *gcpauth.StoreSingleton) Constructor {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		return newController(ctx, cmw, ipm, gcpas.Store(ctx, cmw))
+	}
+}
+
+func newController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+	ipm iam.IAMPolicyManager,
+	gcpas *gcpauth.Store,
+) *controller.Impl {
+	pullSubscriptionInformer := pullsubscriptioninformers.Get(ctx)
+
+	logger := logging.FromContext(ctx).Named(controllerAgentName).Desugar()
+
+	var env envConfig
+	if err := envconfig.Process("", &env); err != nil {
+		logger.Fatal("Failed to process env var", zap.Error(err))
+	}
+
+	var receiverURL *apis.URL
+	if env.ReceiverURL != "" {
+		var err error
+		if receiverURL, err = apis.ParseURL(env.ReceiverURL); err != nil {
+			logger.Fatal("Failed to parse the push receiver URL", zap.String("url", env.ReceiverURL), zap.Error(err))
+		}
+	}
+
+	r := &Reconciler{
+		Base: &psreconciler.Base{
+			Base:                   reconciler.NewBase(ctx, controllerAgentName, cmw),
+			Identity:               identity.NewIdentity(ctx, ipm, gcpas),
+			PullSubscriptionLister: pullSubscriptionInformer.Lister(),
+			CreateClientFn:         pubsub.NewClient,
+			ControllerAgentName:    controllerAgentName,
+			ResourceGroup:          resourceGroup,
+		},
+		receiverURL:    receiverURL,
+		serviceAccount: env.ServiceAccount,
+	}
+	r.PushConfigFn = r.pushConfig
+
+	impl := pullsubscriptionreconciler.NewImpl(ctx, r)
+
+	r.Logger.Info("Setting up event handlers")
+
+	onlyPush := pkgreconciler.AnnotationFilterFunc(duck.DeliveryModeAnnotation, duck.DeliveryModePush, false)
+
+	pullSubscriptionInformer.Informer().AddEventHandlerWithResyncPeriod(cache.FilteringResourceEventHandler{
+		FilterFunc: onlyPush,
+		Handler:    controller.HandleAll(impl.Enqueue),
+	}, reconciler.DefaultResyncPeriod)
+
+	r.UriResolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
+
+	return impl
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package push
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsub"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	pullsubscriptionreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1/pullsubscription"
+	pushreceiver "github.com/google/knative-gcp/pkg/pubsub/push"
+	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
+)
+
+const (
+	pushReceiverNotConfiguredReason = "PushReceiverNotConfigured"
+)
+
+// Reconciler implements controller.Reconciler for PullSubscription resources.
+type Reconciler struct {
+	*psreconciler.Base
+
+	// receiverURL is the URL at which Pub/Sub reaches the shared push receiver.
+	receiverURL *apis.URL
+	// serviceAccount is the email of the service account of the OIDC tokens of the push requests.
+	serviceAccount string
+}
+
+// Check that our Reconciler implements Interface.
+var _ pullsubscriptionreconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, ps *v1.PullSubscription) reconciler.Event {
+	if r.receiverURL == nil || r.serviceAccount == "" {
+		ps.Status.InitializeConditions()
+		ps.Status.ObservedGeneration = ps.Generation
+		ps.Status.MarkNoSubscription(pushReceiverNotConfiguredReason, "The push receiver URL and service account are not configured")
+		return reconciler.NewEvent(corev1.EventTypeWarning, pushReceiverNotConfiguredReason, "The push receiver URL and service account are not configured")
+	}
+	return r.Base.ReconcileKind(ctx, ps)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, ps *v1.PullSubscription) reconciler.Event {
+	return r.Base.FinalizeKind(ctx, ps)
+}
+
+// pushConfig makes the push configuration of the subscription of the PullSubscription, which pushes
+// to its path on the shared push receiver with an OIDC token for the receiver.
+func (r *Reconciler) pushConfig(ps *v1.PullSubscription) pubsub.PushConfig {
+	endpoint := *r.receiverURL
+	endpoint.Path = pushreceiver.PullSubscriptionPath(ps.Namespace, ps.Name)
+	return pubsub.PushConfig{
+		Endpoint: endpoint.String(),
+		AuthenticationMethod: &pubsub.OIDCToken{
+			Audience:            r.receiverURL.String(),
+			ServiceAccountEmail: r.serviceAccount,
+		},
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
@@ -96,11 +97,19 @@ type Base struct {
 
 	// ReconcileDataPlaneFn is the function used to reconcile the data plane resources.
 	ReconcileDataPlaneFn ReconcileDataPlaneFunc
+
+	// PushConfigFn is the function used to make the push configuration of the Pub/Sub subscription.
+	// If set, Pub/Sub pushes the messages to the shared push receiver, and there are no data plane
+	// resources to reconcile.
+	PushConfigFn PushConfigFunc
 }
 
 // ReconcileDataPlaneFunc is used to reconcile the data plane component(s).
 type ReconcileDataPlaneFunc func(ctx context.Context, d *appsv1.Deployment, ps *v1.PullSubscription) error
 
+// PushConfigFunc is used to make the push configuration of the Pub/Sub subscription.
+type PushConfigFunc func(ps *v1.PullSubscription) pubsub.PushConfig
+
 func (r *Base) ReconcileKind(ctx context.Context, ps *v1.PullSubscription) pkgreconciler.Event {
 	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("pullsubscription", ps)))
 
@@ -145,8 +154,10 @@ func (r *Base) ReconcileKind(ctx context.Context, ps *v1.PullSubscription) pkgre
 	}
 	ps.Status.MarkSubscribed(subscriptionID)
 
-	err = r.reconcileDataPlaneResources(ctx, ps, r.ReconcileDataPlaneFn)
-	if err != nil {
+	if r.PushConfigFn != nil {
+		// The shared push receiver delivers the messages.
+		ps.Status.MarkPushDeployed()
+	} else if err := r.reconcileDataPlaneResources(ctx, ps, r.ReconcileDataPlaneFn); err != nil {
 		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledDataPlaneFailedReason, "Failed to reconcile Data Plane resource(s): %s", err.Error())
 	}
 
@@ -219,6 +230,13 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 		subConfig.RetentionDuration = retentionDuration
 	}
 
+	subConfig.Filter = ps.Spec.Filter
+	subConfig.DeadLetterPolicy = deadLetterPolicy(ps)
+	subConfig.RetryPolicy = retryPolicy(ps)
+	if r.PushConfigFn != nil {
+		subConfig.PushConfig = r.PushConfigFn(ps)
+	}
+
 	// Check if the topic of the subscription is "_deleted-topic_"
 	if subExists {
 		config, err := sub.Config(ctx)
@@ -239,6 +257,42 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 				logging.FromContext(ctx).Desugar().Error("Failed to create subscription", zap.Error(err))
 				return "", err
 			}
+		} else if config.Filter != subConfig.Filter {
+			logging.FromContext(ctx).Desugar().Info("Detected filter change. Going to recreate the pull subscription. Unacked messages will be lost.")
+			// The filter of a subscription cannot be updated. In order to change it, we first delete the sub and
+			// then create it. Unacked messages will be lost.
+			if err := sub.Delete(ctx); err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to delete the subscription to change its filter", zap.Error(err))
+				return "", fmt.Errorf("failed to delete the subscription to change its filter: %v", err)
+			}
+			sub, err = client.CreateSubscription(ctx, subID, subConfig)
+			if err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to create subscription", zap.Error(err))
+				return "", err
+			}
+		} else if !equality.Semantic.DeepEqual(config.DeadLetterPolicy, subConfig.DeadLetterPolicy) ||
+			!equality.Semantic.DeepEqual(config.RetryPolicy, subConfig.RetryPolicy) ||
+			pushConfigChanged(config.PushConfig, subConfig.PushConfig) {
+			// Empty policies remove the policies of the subscription.
+			updateSubConfig := pubsub.SubscriptionConfigToUpdate{
+				DeadLetterPolicy: &pubsub.DeadLetterPolicy{},
+				RetryPolicy:      &pubsub.RetryPolicy{},
+			}
+			if subConfig.DeadLetterPolicy != nil {
+				updateSubConfig.DeadLetterPolicy = subConfig.DeadLetterPolicy
+			}
+			if subConfig.RetryPolicy != nil {
+				updateSubConfig.RetryPolicy = subConfig.RetryPolicy
+			}
+			if pushConfigChanged(config.PushConfig, subConfig.PushConfig) {
+				// An empty push config turns the subscription into a pull subscription.
+				updateSubConfig.PushConfig = &subConfig.PushConfig
+			}
+			if _, err := sub.Update(ctx, updateSubConfig); err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to update subscription config", zap.Error(err))
+				return "", fmt.Errorf("failed to update the subscription config: %w", err)
+			}
+			logging.FromContext(ctx).Desugar().Info("Updated Pub/Sub subscription config", zap.String("name", subID))
 		}
 	} else {
 		sub, err = client.CreateSubscription(ctx, subID, subConfig)
@@ -247,10 +301,42 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 			return "", err
 		}
 	}
-	// TODO update the subscription's config if needed.
 	return subID, nil
 }
 
+// deadLetterPolicy returns the dead letter policy of the subscription of the PullSubscription,
+// with its defaults set so that it can be compared to the policy of an existing subscription.
+func deadLetterPolicy(ps *v1.PullSubscription) *pubsub.DeadLetterPolicy {
+	dlp := ps.Spec.DeadLetterPolicy
+	if dlp == nil {
+		return nil
+	}
+	return &pubsub.DeadLetterPolicy{
+		DeadLetterTopic:     fmt.Sprintf("projects/%s/topics/%s", ps.Status.ProjectID, dlp.Topic),
+		MaxDeliveryAttempts: int(dlp.GetMaxDeliveryAttempts()),
+	}
+}
+
+// retryPolicy returns the retry policy of the subscription of the PullSubscription, with its
+// defaults set so that it can be compared to the policy of an existing subscription.
+func retryPolicy(ps *v1.PullSubscription) *pubsub.RetryPolicy {
+	rp := ps.Spec.RetryPolicy
+	if rp == nil {
+		return nil
+	}
+	return &pubsub.RetryPolicy{
+		MinimumBackoff: rp.GetMinimumBackoff(),
+		MaximumBackoff: rp.GetMaximumBackoff(),
+	}
+}
+
+// pushConfigChanged returns whether the push endpoint or authentication of an existing subscription
+// differ from the wanted ones. The attributes are ignored, as Pub/Sub sets the x-goog-version one.
+func pushConfigChanged(existing, wanted pubsub.PushConfig) bool {
+	return existing.Endpoint != wanted.Endpoint ||
+		!equality.Semantic.DeepEqual(existing.AuthenticationMethod, wanted.AuthenticationMethod)
+}
+
 // deleteSubscription looks at the status.SubscriptionID and if non-empty,
 // hence indicating that we have created a subscription successfully
 // in the PullSubscription, remove it.
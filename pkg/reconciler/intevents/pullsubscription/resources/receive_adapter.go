@@ -18,6 +18,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -102,6 +103,41 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 		adapterType = string(converters.PubSubPull)
 	}
 
+	// The EventMapping replaces the conversion of the adapter type.
+	var eventMapping string
+	if args.PullSubscription.Spec.EventMapping != nil {
+		adapterType = string(converters.Mapping)
+		if b, err := json.Marshal(args.PullSubscription.Spec.EventMapping); err != nil {
+			logging.FromContext(ctx).Warnw("failed to marshal event mapping",
+				zap.Error(err),
+				zap.Any("eventMapping", args.PullSubscription.Spec.EventMapping))
+		} else {
+			eventMapping = string(b)
+		}
+	}
+
+	var imageFilter string
+	if args.PullSubscription.Spec.ImageFilter != nil {
+		if b, err := json.Marshal(args.PullSubscription.Spec.ImageFilter); err != nil {
+			logging.FromContext(ctx).Warnw("failed to marshal image filter",
+				zap.Error(err),
+				zap.Any("imageFilter", args.PullSubscription.Spec.ImageFilter))
+		} else {
+			imageFilter = string(b)
+		}
+	}
+
+	var budgetFilter string
+	if args.PullSubscription.Spec.BudgetFilter != nil {
+		if b, err := json.Marshal(args.PullSubscription.Spec.BudgetFilter); err != nil {
+			logging.FromContext(ctx).Warnw("failed to marshal budget filter",
+				zap.Error(err),
+				zap.Any("budgetFilter", args.PullSubscription.Spec.BudgetFilter))
+		} else {
+			budgetFilter = string(b)
+		}
+	}
+
 	receiveAdapterContainer := corev1.Container{
 		Name:  "receive-adapter",
 		Image: args.Image,
@@ -186,6 +222,30 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 		},
 	}
 
+	// Only set when there is an EventMapping, so that the existing Deployments are unchanged.
+	if eventMapping != "" {
+		receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+			Name:  "K_EVENT_MAPPING",
+			Value: eventMapping,
+		})
+	}
+
+	// Only set when there is an ImageFilter, for the same reason.
+	if imageFilter != "" {
+		receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+			Name:  "K_IMAGE_FILTER",
+			Value: imageFilter,
+		})
+	}
+
+	// Only set when there is a BudgetFilter, for the same reason.
+	if budgetFilter != "" {
+		receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+			Name:  "K_BUDGET_FILTER",
+			Value: budgetFilter,
+		})
+	}
+
 	// This is added purely for the TestCloudLogging E2E tests, which verify that the log line is
 	// written certain annotations are present.
 	receiveAdapterContainer.Env = testloggingutil.PropagateLoggingE2ETestAnnotation(
@@ -571,3 +571,136 @@ func TestMakeReceiveAdapterWithServiceAccount(t *testing.T) {
 		t.Errorf("unexpected deploy (-want, +got) = %v", diff)
 	}
 }
+
+func TestMakeReceiveAdapterWithEventMapping(t *testing.T) {
+	ps := &intereventsv1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+			Labels: map[string]string{
+				intevents.SourceLabelKey: "my-source-name",
+			},
+		},
+		Spec: intereventsv1.PullSubscriptionSpec{
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				IdentitySpec: gcpduckv1.IdentitySpec{
+					ServiceAccountName: "test-ksa",
+				},
+				Project: "eventing-name",
+			},
+			Topic:       "topic",
+			AdapterType: "source-adapter-type",
+			EventMapping: &gcpduckv1.EventMapping{
+				Type: &gcpduckv1.ValueMapping{Attribute: "type"},
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		AuthType:         authcheck.WorkloadIdentityGSA,
+	})
+
+	env := make(map[string]string)
+	for _, e := range got.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if diff := cmp.Diff(string(converters.Mapping), env["ADAPTER_TYPE"]); diff != "" {
+		t.Errorf("unexpected ADAPTER_TYPE (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(`{"type":{"attribute":"type"}}`, env["K_EVENT_MAPPING"]); diff != "" {
+		t.Errorf("unexpected K_EVENT_MAPPING (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakeReceiveAdapterWithImageFilter(t *testing.T) {
+	ps := &intereventsv1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+			Labels: map[string]string{
+				intevents.SourceLabelKey: "my-source-name",
+			},
+		},
+		Spec: intereventsv1.PullSubscriptionSpec{
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				IdentitySpec: gcpduckv1.IdentitySpec{
+					ServiceAccountName: "test-ksa",
+				},
+				Project: "eventing-name",
+			},
+			Topic:       "gcr",
+			AdapterType: string(converters.CloudArtifactRegistry),
+			ImageFilter: &gcpduckv1.ImageFilter{
+				Tags:    []string{"v1.*"},
+				Actions: []string{gcpduckv1.ImageActionInsert},
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		AuthType:         authcheck.WorkloadIdentityGSA,
+	})
+
+	env := make(map[string]string)
+	for _, e := range got.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if diff := cmp.Diff(string(converters.CloudArtifactRegistry), env["ADAPTER_TYPE"]); diff != "" {
+		t.Errorf("unexpected ADAPTER_TYPE (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(`{"tags":["v1.*"],"actions":["INSERT"]}`, env["K_IMAGE_FILTER"]); diff != "" {
+		t.Errorf("unexpected K_IMAGE_FILTER (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakeReceiveAdapterWithBudgetFilter(t *testing.T) {
+	ps := &intereventsv1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+			Labels: map[string]string{
+				intevents.SourceLabelKey: "my-source-name",
+			},
+		},
+		Spec: intereventsv1.PullSubscriptionSpec{
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				IdentitySpec: gcpduckv1.IdentitySpec{
+					ServiceAccountName: "test-ksa",
+				},
+				Project: "eventing-name",
+			},
+			Topic:       "budgets",
+			AdapterType: string(converters.CloudBillingBudget),
+			BudgetFilter: &gcpduckv1.BudgetFilter{
+				ThresholdPercent: 90,
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		AuthType:         authcheck.WorkloadIdentityGSA,
+	})
+
+	env := make(map[string]string)
+	for _, e := range got.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if diff := cmp.Diff(string(converters.CloudBillingBudget), env["ADAPTER_TYPE"]); diff != "" {
+		t.Errorf("unexpected ADAPTER_TYPE (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(`{"thresholdPercent":90}`, env["K_BUDGET_FILTER"]); diff != "" {
+		t.Errorf("unexpected K_BUDGET_FILTER (-want, +got) = %v", diff)
+	}
+}
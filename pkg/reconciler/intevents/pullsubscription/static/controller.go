@@ -113,9 +113,11 @@ func newController(
 	// TODO revisit once we introduce new scaling strategies.
 	onlyKedaScaler := pkgreconciler.AnnotationFilterFunc(duck.AutoscalingClassAnnotation, duck.KEDA, false)
 	notKedaScaler := pkgreconciler.Not(onlyKedaScaler)
+	// The PullSubscriptions in the push delivery mode have no receive adapter.
+	notPush := pkgreconciler.Not(pkgreconciler.AnnotationFilterFunc(duck.DeliveryModeAnnotation, duck.DeliveryModePush, false))
 
 	pullSubscriptionHandler := cache.FilteringResourceEventHandler{
-		FilterFunc: notKedaScaler,
+		FilterFunc: pkgreconciler.ChainFilterFuncs(notKedaScaler, notPush),
 		Handler:    controller.HandleAll(impl.Enqueue),
 	}
 	pullSubscriptionInformer.Informer().AddEventHandlerWithResyncPeriod(pullSubscriptionHandler, reconciler.DefaultResyncPeriod)
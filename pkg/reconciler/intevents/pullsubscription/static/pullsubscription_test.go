@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
@@ -49,6 +50,7 @@ import (
 
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/ptr"
 	. "knative.dev/pkg/reconciler/testing"
 	"knative.dev/pkg/resolver"
 
@@ -79,6 +81,9 @@ const (
 
 	secretName = "testing-secret"
 
+	testDeadLetterTopicID = "dead-letter"
+	testFilter            = `attributes.type = "order"`
+
 	failedToReconcileSubscriptionMsg = `Failed to reconcile Pub/Sub subscription`
 	failedToDeleteSubscriptionMsg    = `Failed to delete Pub/Sub subscription`
 )
@@ -104,6 +109,24 @@ var (
 		Kind:    "Transformer",
 	}
 
+	specWithSubscriptionPolicy = pubsubv1.PullSubscriptionSpec{
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			Secret:  &secret,
+			Project: testProject,
+		},
+		SubscriptionPolicySpec: gcpduckv1.SubscriptionPolicySpec{
+			Filter: testFilter,
+			DeadLetterPolicy: &gcpduckv1.DeadLetterPolicy{
+				Topic:               testDeadLetterTopicID,
+				MaxDeliveryAttempts: ptr.Int32(10),
+			},
+			RetryPolicy: &gcpduckv1.RetryPolicy{
+				MinimumBackoff: ptr.String("5s"),
+			},
+		},
+		Topic: testTopicID,
+	}
+
 	secret = corev1.SecretKeySelector{
 		LocalObjectReference: corev1.LocalObjectReference{
 			Name: secretName,
@@ -571,6 +594,199 @@ func TestAllCases(t *testing.T) {
 		PostConditions: []func(*testing.T, *TableRow){
 			OnlySubscriptions(testSubscriptionID),
 		},
+	}, {
+		Name: "successfully created subscription with filter, dead letter and retry policies",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(specWithSubscriptionPolicy),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		WantCreates: []runtime.Object{
+			newReceiveAdapter(context.Background(), testImage, nil),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(specWithSubscriptionPolicy),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasFilter(testSubscriptionID, testFilter),
+			SubscriptionHasDeadLetterPolicy(testSubscriptionID, &pubsub.DeadLetterPolicy{
+				DeadLetterTopic:     "projects/" + testProject + "/topics/" + testDeadLetterTopicID,
+				MaxDeliveryAttempts: 10,
+			}),
+			SubscriptionHasRetryPolicy(testSubscriptionID, &pubsub.RetryPolicy{
+				MinimumBackoff: 5 * time.Second,
+				MaximumBackoff: gcpduckv1.DefaultMaximumBackoff,
+			}),
+		},
+	}, {
+		Name: "subscription with another filter is recreated",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(specWithSubscriptionPolicy),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+				SubscriptionWithConfig(testSubscriptionID, testTopicID, pubsub.SubscriptionConfig{
+					Filter: `attributes.type = "other"`,
+				}),
+			},
+		},
+		WantCreates: []runtime.Object{
+			newReceiveAdapter(context.Background(), testImage, nil),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(specWithSubscriptionPolicy),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasFilter(testSubscriptionID, testFilter),
+			SubscriptionHasDeadLetterPolicy(testSubscriptionID, &pubsub.DeadLetterPolicy{
+				DeadLetterTopic:     "projects/" + testProject + "/topics/" + testDeadLetterTopicID,
+				MaxDeliveryAttempts: 10,
+			}),
+			SubscriptionHasRetryPolicy(testSubscriptionID, &pubsub.RetryPolicy{
+				MinimumBackoff: 5 * time.Second,
+				MaximumBackoff: gcpduckv1.DefaultMaximumBackoff,
+			}),
+		},
+	}, {
+		Name: "subscription config is updated",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(specWithSubscriptionPolicy),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+				SubscriptionWithConfig(testSubscriptionID, testTopicID, pubsub.SubscriptionConfig{
+					Filter: testFilter,
+					RetryPolicy: &pubsub.RetryPolicy{
+						MinimumBackoff: time.Second,
+						MaximumBackoff: time.Minute,
+					},
+				}),
+			},
+		},
+		WantCreates: []runtime.Object{
+			newReceiveAdapter(context.Background(), testImage, nil),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(specWithSubscriptionPolicy),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasFilter(testSubscriptionID, testFilter),
+			SubscriptionHasDeadLetterPolicy(testSubscriptionID, &pubsub.DeadLetterPolicy{
+				DeadLetterTopic:     "projects/" + testProject + "/topics/" + testDeadLetterTopicID,
+				MaxDeliveryAttempts: 10,
+			}),
+			SubscriptionHasRetryPolicy(testSubscriptionID, &pubsub.RetryPolicy{
+				MinimumBackoff: 5 * time.Second,
+				MaximumBackoff: gcpduckv1.DefaultMaximumBackoff,
+			}),
+		},
 	}, {
 		Name: "sink namespace empty, default to the source one",
 		Objects: []runtime.Object{
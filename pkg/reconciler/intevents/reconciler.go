@@ -155,6 +155,18 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 		Labels:      resources.GetLabels(psb.receiveAdapterName, name),
 		Annotations: resources.GetAnnotations(annotations, resourceGroup),
 	}
+	if sp, ok := pubsubable.(duck.SubscriptionPolicyable); ok {
+		args.SubscriptionPolicy = sp.SubscriptionPolicySpec()
+	}
+	if em, ok := pubsubable.(duck.EventMappable); ok {
+		args.EventMapping = em.EventMapping()
+	}
+	if f, ok := pubsubable.(duck.ImageFilterable); ok {
+		args.ImageFilter = f.ImageFilter()
+	}
+	if f, ok := pubsubable.(duck.BudgetFilterable); ok {
+		args.BudgetFilter = f.BudgetFilter()
+	}
 
 	if v, present := pubsubable.GetObjectMeta().GetAnnotations()[testloggingutil.LoggingE2ETestAnnotation]; present {
 		// This is added purely for the TestCloudLogging E2E tests, which verify that the log line
@@ -34,6 +34,15 @@ type PullSubscriptionArgs struct {
 	AdapterType string
 	Labels      map[string]string
 	Annotations map[string]string
+	// SubscriptionPolicy is the optional Filter, DeadLetterPolicy and RetryPolicy of the
+	// subscription.
+	SubscriptionPolicy *gcpduckv1.SubscriptionPolicySpec
+	// EventMapping is the optional mapping of the messages to CloudEvents.
+	EventMapping *gcpduckv1.EventMapping
+	// ImageFilter is the optional filter of the container image notifications.
+	ImageFilter *gcpduckv1.ImageFilter
+	// BudgetFilter is the optional filter of the budget notifications.
+	BudgetFilter *gcpduckv1.BudgetFilter
 }
 
 // MakePullSubscription creates the spec for, but does not create, a GCP PullSubscription
@@ -62,6 +71,18 @@ func MakePullSubscription(args *PullSubscriptionArgs) *inteventsv1.PullSubscript
 			AdapterType: args.AdapterType,
 		},
 	}
+	if args.SubscriptionPolicy != nil {
+		ps.Spec.SubscriptionPolicySpec = *args.SubscriptionPolicy.DeepCopy()
+	}
+	if args.EventMapping != nil {
+		ps.Spec.EventMapping = args.EventMapping.DeepCopy()
+	}
+	if args.ImageFilter != nil {
+		ps.Spec.ImageFilter = args.ImageFilter.DeepCopy()
+	}
+	if args.BudgetFilter != nil {
+		ps.Spec.BudgetFilter = args.BudgetFilter.DeepCopy()
+	}
 	if args.Spec.CloudEventOverrides != nil && args.Spec.CloudEventOverrides.Extensions != nil {
 		ps.Spec.SourceSpec.CloudEventOverrides = &duckv1.CloudEventOverrides{
 			Extensions: args.Spec.CloudEventOverrides.Extensions,
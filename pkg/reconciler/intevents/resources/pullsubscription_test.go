@@ -131,3 +131,46 @@ func TestMakePullSubscription(t *testing.T) {
 		t.Errorf("unexpected (-want, +got) = %v", diff)
 	}
 }
+
+func TestMakePullSubscriptionWithSubscriptionPolicyAndEventMapping(t *testing.T) {
+	source := &v1.CloudPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pubsub-name",
+			Namespace: "pubsub-namespace",
+			UID:       "pubsub-uid",
+		},
+		Spec: v1.CloudPubSubSourceSpec{
+			Topic: "topic-abc",
+			SubscriptionPolicySpec: gcpduckv1.SubscriptionPolicySpec{
+				Filter: `attributes.type = "order"`,
+				DeadLetterPolicy: &gcpduckv1.DeadLetterPolicy{
+					Topic: "dead-letter",
+				},
+			},
+			EventMapping: &gcpduckv1.EventMapping{
+				Type: &gcpduckv1.ValueMapping{Attribute: "type"},
+			},
+		},
+	}
+	args := &PullSubscriptionArgs{
+		Namespace:          source.Namespace,
+		Name:               source.Name,
+		Spec:               &source.Spec.PubSubSpec,
+		Owner:              source,
+		Topic:              source.Spec.Topic,
+		AdapterType:        "google.pubsub",
+		SubscriptionPolicy: source.SubscriptionPolicySpec(),
+		EventMapping:       source.EventMapping(),
+	}
+	got := MakePullSubscription(args)
+
+	if diff := cmp.Diff(source.Spec.SubscriptionPolicySpec, got.Spec.SubscriptionPolicySpec); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(source.Spec.EventMapping, got.Spec.EventMapping); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+	if got.Spec.DeadLetterPolicy == source.Spec.DeadLetterPolicy {
+		t.Error("MakePullSubscription shares the DeadLetterPolicy of the source")
+	}
+}
@@ -257,6 +257,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithChannelReadyURI(channelURI),
 				WithChannelSetDefaults,
 			),
@@ -293,6 +294,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithChannelReadyURI(channelURI),
 				WithChannelBrokerCellUnknown("BrokerCellNotReady", "BrokerCell knative-testing/default is not ready"),
 				WithChannelSetDefaults,
@@ -330,13 +332,14 @@ func TestAllCasesChannel(t *testing.T) {
 			{
 				Object: NewChannel(channelName, testNS,
 					WithChannelUID(channelUID),
+					WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 					WithInitChannelConditions,
 					WithChannelBrokerCellFailed("BrokerCellCreationFailed", "Failed to create BrokerCell knative-testing/default"),
 					WithChannelSetDefaults,
 				),
 			},
 		},
-		WantCreates:             []runtime.Object{resources.CreateBrokerCell(nil) /*Currently brokerCell doesn't require channel information*/},
+		WantCreates:             []runtime.Object{resources.CreateBrokerCell(resources.DefaultBrokerCellName)},
 		SkipNamespaceValidation: true, // The brokerCell resource is created in a different namespace (system namespace) than the channel
 		WantEvents: []string{
 			channelFinalizerUpdatedEvent,
@@ -359,13 +362,14 @@ func TestAllCasesChannel(t *testing.T) {
 			{
 				Object: NewChannel(channelName, testNS,
 					WithChannelUID(channelUID),
+					WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 					WithChannelReadyURI(channelURI),
 					WithChannelBrokerCellUnknown("BrokerCellNotReady", "BrokerCell knative-testing/default is not ready"),
 					WithChannelSetDefaults,
 				),
 			},
 		},
-		WantCreates:             []runtime.Object{resources.CreateBrokerCell(nil) /*Currently brokerCell doesn't require channel information*/},
+		WantCreates:             []runtime.Object{resources.CreateBrokerCell(resources.DefaultBrokerCellName)},
 		SkipNamespaceValidation: true, // The brokerCell resource is created in a different namespace (system namespace) than the channel
 		WantEvents: []string{
 			channelFinalizerUpdatedEvent,
@@ -402,6 +406,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithChannelReadyURI(channelURI),
 				WithChannelSetDefaults,
 			),
@@ -443,6 +448,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithChannelReadyURI(channelURI),
 				WithChannelSetDefaults,
 			),
@@ -480,6 +486,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				// TopicID is the empty string because we are using Ready just as a shortcut, rather
 				// than calling each method directly.
 				WithChannelReadyURI(channelURI),
@@ -522,6 +529,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithChannelReadyURI(channelURI),
 				WithChannelSetDefaults,
 				WithChannelSubscribers(
@@ -587,6 +595,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithChannelReadyURI(channelURI),
 				WithChannelSetDefaults,
 				WithChannelSubscribers(
@@ -645,6 +654,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithChannelReadyURI(channelURI),
 				WithChannelSetDefaults,
 			),
@@ -786,6 +796,7 @@ func TestAllCasesChannel(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
+				WithChannelStatusBrokerCell(resources.DefaultBrokerCellName),
 				WithChannelReadyURI(channelURI),
 				WithChannelSetDefaults,
 				WithChannelSubscribers(
@@ -38,6 +38,7 @@ import (
 
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/system"
 
 	channelinformer "github.com/google/knative-gcp/pkg/client/injection/informers/messaging/v1beta1/channel"
 	channelreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/channel"
@@ -117,21 +118,21 @@ func newController(
 }
 
 // filterChannelsForBrokerCell creates a filter that is intended to be used on the BrokerCell
-//  informer. It will enqueue all Channels associated with the changed BrokerCell.
+// informer. It will enqueue all Channels assigned to the changed BrokerCell.
 func filterChannelsForBrokerCell(
 	logger *zap.Logger, channelInformer channellister.ChannelLister, enqueue func(interface{})) func(obj interface{}) {
 	return func(obj interface{}) {
-		if _, ok := obj.(*inteventsv1alpha1.BrokerCell); ok {
-			// TODO(#866) Only select brokers that point to this brokercell by label selector once the
-			// webhook assigns the brokercell label, i.e.,
-			// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
+		if bc, ok := obj.(*inteventsv1alpha1.BrokerCell); ok && bc.Namespace == system.Namespace() {
 			channels, err := channelInformer.List(labels.Everything())
 			if err != nil {
 				logger.Error("Failed to list Channels", zap.Error(err))
 				return
 			}
+			// Only enqueue the Channels assigned to this BrokerCell.
 			for _, channel := range channels {
-				enqueue(channel)
+				if inteventsv1alpha1.BrokerCellNameOf(channel) == bc.Name {
+					enqueue(channel)
+				}
 			}
 		}
 	}
@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/pkg/configmap"
 	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/system"
 
 	// Fake injection informers
 
@@ -62,7 +63,7 @@ func TestFilterChannelsForBrokerCell(t *testing.T) {
 			},
 		},
 		"all channels are enqueued": {
-			objectChanged: &v1alpha1.BrokerCell{},
+			objectChanged: brokerCell(v1alpha1.DefaultBrokerCellName),
 			channels: []runtime.Object{
 				channel("foo"),
 				channel("bar"),
@@ -72,6 +73,25 @@ func TestFilterChannelsForBrokerCell(t *testing.T) {
 				channel("bar"),
 			},
 		},
+		"only channels assigned to the BrokerCell are enqueued": {
+			objectChanged: brokerCell("dedicated"),
+			channels: []runtime.Object{
+				channel("foo"),
+				channelInBrokerCell("bar", "dedicated"),
+				channelInBrokerCell("baz", "other"),
+			},
+			wantEnqueued: []interface{}{
+				channelInBrokerCell("bar", "dedicated"),
+			},
+		},
+		"BrokerCell outside of the system namespace": {
+			objectChanged: &v1alpha1.BrokerCell{
+				ObjectMeta: v1.ObjectMeta{Namespace: "other", Name: v1alpha1.DefaultBrokerCellName},
+			},
+			channels: []runtime.Object{
+				channel("foo"),
+			},
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -100,6 +120,21 @@ func channel(name string) *v1beta1.Channel {
 	}
 }
 
+func channelInBrokerCell(name, brokerCell string) *v1beta1.Channel {
+	c := channel(name)
+	c.Annotations = map[string]string{v1alpha1.BrokerCellAnnotationKey: brokerCell}
+	return c
+}
+
+func brokerCell(name string) *v1alpha1.BrokerCell {
+	return &v1alpha1.BrokerCell{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: system.Namespace(),
+			Name:      name,
+		},
+	}
+}
+
 type fakeImpl struct {
 	enqueued []interface{}
 }
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
@@ -134,6 +135,12 @@ func WithBrokerBrokerCellUnknown(reason, msg string) BrokerOption {
 	}
 }
 
+func WithBrokerStatusBrokerCell(name string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		b.Status.SetBrokerCell(name)
+	}
+}
+
 func WithBrokerBrokerCellReady(b *brokerv1beta1.Broker) {
 	b.Status.MarkBrokerCellReady()
 }
@@ -169,6 +176,42 @@ func WithBrokerClass(bc string) BrokerOption {
 	}
 }
 
+// WithBrokerOrderingKeyAttribute sets the event attribute used as the Broker's ordering key.
+func WithBrokerOrderingKeyAttribute(attr string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.OrderingKeyAttributeAnnotationKey] = attr
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerIngressPolicyAnnotation sets the Broker's ingress policy annotation.
+func WithBrokerIngressPolicyAnnotation(policy string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.IngressPolicyAnnotationKey] = policy
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerBrokerCell assigns the Broker to the BrokerCell.
+func WithBrokerBrokerCell(name string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[inteventsv1alpha1.BrokerCellAnnotationKey] = name
+		b.SetAnnotations(annotations)
+	}
+}
+
 func WithBrokerSetDefaults(b *brokerv1beta1.Broker) {
 	b.SetDefaults(context.Background())
 }
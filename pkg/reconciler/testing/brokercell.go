@@ -48,6 +48,38 @@ func WithBrokerCellAnnotations(annotations map[string]string) BrokerCellOption {
 	}
 }
 
+func WithBrokerCellPayload(payload *intv1alpha1.PayloadSpec) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Spec.Payload = payload
+	}
+}
+
+// WithBrokerCellTargetsConfigShards sets the number of shards of the BrokerCell's targets config.
+func WithBrokerCellTargetsConfigShards(shards int32) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Spec.TargetsConfig = &intv1alpha1.TargetsConfigSpec{Shards: &shards}
+	}
+}
+
+// WithBrokerCellAllowedNamespaces restricts the BrokerCell to the Brokers and Channels of the
+// namespaces.
+func WithBrokerCellAllowedNamespaces(namespaces ...string) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Spec.AllowedNamespaces = namespaces
+	}
+}
+
+// WithBrokerCellBacklogAutoscaling scales the fanout and retry of the BrokerCell on the backlog of
+// their subscriptions, with the KEDA TriggerAuthentication.
+func WithBrokerCellBacklogAutoscaling(triggerAuthentication string) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Spec.Autoscaling = &intv1alpha1.AutoscalingSpec{
+			Class:   intv1alpha1.BacklogAutoscalingClass,
+			Backlog: &intv1alpha1.BacklogAutoscalingSpec{TriggerAuthentication: triggerAuthentication},
+		}
+	}
+}
+
 // WithInitBrokerCellConditions initializes the BrokerCell's conditions.
 func WithInitBrokerCellConditions(bc *intv1alpha1.BrokerCell) {
 	bc.Status.InitializeConditions()
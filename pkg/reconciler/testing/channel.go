@@ -29,6 +29,7 @@ import (
 	duckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 )
 
@@ -110,6 +111,22 @@ func WithChannelSubscriptionUnknown(reason, message string) ChannelOption {
 	}
 }
 
+// WithChannelBrokerCell assigns the Channel to the BrokerCell.
+func WithChannelBrokerCell(name string) ChannelOption {
+	return func(c *v1beta1.Channel) {
+		if c.Annotations == nil {
+			c.Annotations = make(map[string]string, 1)
+		}
+		c.Annotations[inteventsv1alpha1.BrokerCellAnnotationKey] = name
+	}
+}
+
+func WithChannelStatusBrokerCell(name string) ChannelOption {
+	return func(c *v1beta1.Channel) {
+		c.Status.SetBrokerCell(name)
+	}
+}
+
 func WithChannelBrokerCellReady() ChannelOption {
 	return func(c *v1beta1.Channel) {
 		c.Status.MarkBrokerCellReady()
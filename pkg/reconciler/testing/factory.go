@@ -32,6 +32,9 @@ import (
 	logtesting "knative.dev/pkg/logging/testing"
 
 	fakerunclient "github.com/google/knative-gcp/pkg/client/injection/client/fake"
+	eventinginformers "knative.dev/eventing/pkg/client/informers/externalversions"
+	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
+	eventtypeinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1beta1/eventtype"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	fakedynamicclient "knative.dev/pkg/injection/clients/dynamicclient/fake"
 	fakeservingclient "knative.dev/serving/pkg/client/injection/client/fake"
@@ -60,6 +63,11 @@ func MakeFactory(ctor Ctor) Factory {
 		ctx, kubeClient := fakekubeclient.With(ctx, ls.GetKubeObjects()...)
 		ctx, client := fakerunclient.With(ctx, ls.GetEventsObjects()...)
 		ctx, servingclient := fakeservingclient.With(ctx, ls.GetServingObjects()...)
+		// The EventTypes share their group with the Brokers of our own clientset, so they can't be
+		// sorted by the listers, the eventing client starts empty.
+		ctx, eventingclient := fakeeventingclient.With(ctx)
+		ctx = context.WithValue(ctx, eventtypeinformer.Key{},
+			eventinginformers.NewSharedInformerFactory(eventingclient, 0).Eventing().V1beta1().EventTypes())
 
 		dynamicScheme := runtime.NewScheme()
 		for _, addTo := range clientSetSchemes {
@@ -94,6 +102,7 @@ func MakeFactory(ctor Ctor) Factory {
 			client.PrependReactor("*", "*", reactor)
 			dynamicClient.PrependReactor("*", "*", reactor)
 			servingclient.PrependReactor("*", "*", reactor)
+			eventingclient.PrependReactor("*", "*", reactor)
 		}
 
 		// Validate all Create operations through the serving client.
@@ -104,7 +113,7 @@ func MakeFactory(ctor Ctor) Factory {
 			return ValidateUpdates(ctx, action)
 		})
 
-		actionRecorderList := ActionRecorderList{dynamicClient, client, kubeClient, servingclient}
+		actionRecorderList := ActionRecorderList{dynamicClient, client, kubeClient, servingclient, eventingclient}
 		eventList := EventList{Recorder: eventRecorder}
 
 		return c, actionRecorderList, eventList
@@ -150,10 +150,22 @@ func (l *Listers) GetCloudPubSubSourceLister() eventslisters.CloudPubSubSourceLi
 	return eventslisters.NewCloudPubSubSourceLister(l.indexerFor(&EventsV1.CloudPubSubSource{}))
 }
 
+func (l *Listers) GetCloudArtifactRegistrySourceLister() eventslisters.CloudArtifactRegistrySourceLister {
+	return eventslisters.NewCloudArtifactRegistrySourceLister(l.indexerFor(&EventsV1.CloudArtifactRegistrySource{}))
+}
+
+func (l *Listers) GetCloudBillingBudgetSourceLister() eventslisters.CloudBillingBudgetSourceLister {
+	return eventslisters.NewCloudBillingBudgetSourceLister(l.indexerFor(&EventsV1.CloudBillingBudgetSource{}))
+}
+
 func (l *Listers) GetCloudBuildSourceLister() eventslisters.CloudBuildSourceLister {
 	return eventslisters.NewCloudBuildSourceLister(l.indexerFor(&EventsV1.CloudBuildSource{}))
 }
 
+func (l *Listers) GetCloudMonitoringAlertSourceLister() eventslisters.CloudMonitoringAlertSourceLister {
+	return eventslisters.NewCloudMonitoringAlertSourceLister(l.indexerFor(&EventsV1.CloudMonitoringAlertSource{}))
+}
+
 func (l *Listers) GetDeploymentLister() appsv1listers.DeploymentLister {
 	return appsv1listers.NewDeploymentLister(l.indexerFor(&appsv1.Deployment{}))
 }
@@ -53,6 +53,18 @@ func SubscriptionWithTopic(id string, tid string) PubsubAction {
 	}
 }
 
+// SubscriptionWithConfig creates the subscription id of the topic tid with the given config.
+func SubscriptionWithConfig(id string, tid string, cfg pubsub.SubscriptionConfig) PubsubAction {
+	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
+		cfg.Topic = c.Topic(tid)
+		_, err := c.CreateSubscription(ctx, id, cfg)
+		if err != nil {
+			t.Fatalf("Error creating subscription %q: %v", id, err)
+		}
+		t.Logf("Created subscription %q", id)
+	}
+}
+
 func TopicAndSub(tid, sid string) PubsubAction {
 	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
 		Topic(tid)(ctx, t, c)
@@ -169,6 +181,20 @@ func SubscriptionHasRetryPolicy(id string, wantPolicy *pubsub.RetryPolicy) func(
 	}
 }
 
+func SubscriptionHasPushConfig(id string, wantConfig pubsub.PushConfig) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if diff := cmp.Diff(wantConfig, cfg.PushConfig); diff != "" {
+			t.Errorf("Pubsub config push config (-want,+got): %v", diff)
+		}
+	}
+}
+
 func SubscriptionHasDeadLetterPolicy(id string, wantPolicy *pubsub.DeadLetterPolicy) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
@@ -183,6 +209,34 @@ func SubscriptionHasDeadLetterPolicy(id string, wantPolicy *pubsub.DeadLetterPol
 	}
 }
 
+func SubscriptionHasFilter(id string, want string) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if cfg.Filter != want {
+			t.Errorf("Pubsub config filter got %q, want %q", cfg.Filter, want)
+		}
+	}
+}
+
+func SubscriptionHasMessageOrdering(id string, want bool) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if cfg.EnableMessageOrdering != want {
+			t.Errorf("Pubsub config message ordering got %v, want %v", cfg.EnableMessageOrdering, want)
+		}
+	}
+}
+
 func OnlySubscriptions(ids ...string) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
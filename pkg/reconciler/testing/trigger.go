@@ -23,6 +23,7 @@ import (
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/apis/eventing"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
@@ -151,6 +152,85 @@ func WithDependencyAnnotation(dependencyAnnotation string) TriggerOption {
 	}
 }
 
+func WithTriggerFiltersAnnotation(filters string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.FiltersAnnotationKey] = filters
+	}
+}
+
+func WithTriggerDeliverySpec(delivery *eventingduckv1.DeliverySpec) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Spec.Delivery = delivery
+	}
+}
+
+func WithTriggerTransformAnnotation(transform string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.TransformAnnotationKey] = transform
+	}
+}
+
+// WithTriggerBatchingAnnotation sets the Trigger's batching annotation.
+func WithTriggerBatchingAnnotation(batching string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.BatchingAnnotationKey] = batching
+	}
+}
+
+// WithTriggerRateLimitAnnotation sets the Trigger's rate limit annotation.
+func WithTriggerRateLimitAnnotation(rateLimit string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.RateLimitAnnotationKey] = rateLimit
+	}
+}
+
+func WithTriggerReplayAnnotation(replay string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.ReplayAnnotationKey] = replay
+	}
+}
+
+// WithTriggerReplayReady marks the replay of the Trigger ready, recording that its replay
+// subscription was seeked for replay at endTime.
+func WithTriggerReplayReady(replay, endTime, message string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		withTriggerReplayStatusAnnotations(t, replay, endTime)
+		t.Status.MarkReplayReady("Seeked", message)
+	}
+}
+
+// WithTriggerReplayCompleted marks the replay of the Trigger completed, recording that its replay
+// subscription was seeked for replay at endTime.
+func WithTriggerReplayCompleted(replay, endTime, message string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		withTriggerReplayStatusAnnotations(t, replay, endTime)
+		t.Status.MarkReplayCompleted(message)
+	}
+}
+
+func withTriggerReplayStatusAnnotations(t *brokerv1beta1.Trigger, replay, endTime string) {
+	if t.Status.Annotations == nil {
+		t.Status.Annotations = make(map[string]string)
+	}
+	t.Status.Annotations[brokerv1beta1.ReplayAnnotationKey] = replay
+	t.Status.Annotations[brokerv1beta1.ReplayEndTimeAnnotationKey] = endTime
+}
+
 func WithTriggerDependencyReady(t *brokerv1beta1.Trigger) {
 	t.Status.MarkDependencySucceeded()
 }
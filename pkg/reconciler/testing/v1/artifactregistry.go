@@ -0,0 +1,168 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Veroute.on 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// CloudArtifactRegistrySourceOption enables further configuration of a CloudArtifactRegistrySource.
+type CloudArtifactRegistrySourceOption func(*v1.CloudArtifactRegistrySource)
+
+// NewCloudArtifactRegistrySource creates a CloudArtifactRegistrySource with CloudArtifactRegistrySourceOptions
+func NewCloudArtifactRegistrySource(name, namespace string, so ...CloudArtifactRegistrySourceOption) *v1.CloudArtifactRegistrySource {
+	s := &v1.CloudArtifactRegistrySource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-artifactregistry-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	return s
+}
+
+func WithCloudArtifactRegistrySourceSink(gvk metav1.GroupVersionKind, name string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: testing.ApiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudArtifactRegistrySourceDeletionTimestamp(s *v1.CloudArtifactRegistrySource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithCloudArtifactRegistrySourceProject(project string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudArtifactRegistrySourceServiceAccount(kServiceAccount string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+// WithInitCloudArtifactRegistrySourceConditions initializes the CloudArtifactRegistrySource's conditions.
+func WithInitCloudArtifactRegistrySourceConditions(s *v1.CloudArtifactRegistrySource) {
+	s.Status.InitializeConditions()
+}
+
+func WithCloudArtifactRegistrySourceWorkloadIdentityFailed(reason, message string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudArtifactRegistrySourcePullSubscriptionFailed marks the condition that the
+// status of PullSubscription is False
+func WithCloudArtifactRegistrySourcePullSubscriptionFailed(reason, message string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Status.MarkPullSubscriptionFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudArtifactRegistrySourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown
+func WithCloudArtifactRegistrySourcePullSubscriptionUnknown(reason, message string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudArtifactRegistrySourcePullSubscriptionReady marks the condition that the
+// topic is not ready
+func WithCloudArtifactRegistrySourcePullSubscriptionReady() CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+	}
+}
+
+// WithCloudArtifactRegistrySourceSinkURI sets the status for sink URI
+func WithCloudArtifactRegistrySourceSinkURI(url *apis.URL) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudArtifactRegistrySourceCloudEventAttributes(ceAttributes ...duckv1.CloudEventAttributes) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
+func WithCloudArtifactRegistrySourceSubscriptionID(subscriptionID string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudArtifactRegistrySourceFinalizers(finalizers ...string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Finalizers = finalizers
+	}
+}
+
+func WithCloudArtifactRegistrySourceStatusObservedGeneration(generation int64) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Status.Status.ObservedGeneration = generation
+	}
+}
+
+func WithCloudArtifactRegistrySourceObjectMetaGeneration(generation int64) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.ObjectMeta.Generation = generation
+	}
+}
+
+func WithCloudArtifactRegistrySourceAnnotations(Annotations map[string]string) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudArtifactRegistrySourceSetDefault(s *v1.CloudArtifactRegistrySource) {
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+}
+
+func WithCloudArtifactRegistrySourceImageFilter(filter gcpduckv1.ImageFilter) CloudArtifactRegistrySourceOption {
+	return func(s *v1.CloudArtifactRegistrySource) {
+		s.Spec.ImageFilter = filter
+	}
+}
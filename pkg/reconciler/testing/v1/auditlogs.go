@@ -176,6 +176,12 @@ func WithCloudAuditLogsSourceSinkURI(url *apis.URL) CloudAuditLogsSourceOption {
 	}
 }
 
+func WithCloudAuditLogsSourceCloudEventAttributes(ceAttributes ...duckv1.CloudEventAttributes) CloudAuditLogsSourceOption {
+	return func(s *v1.CloudAuditLogsSource) {
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
 func WithCloudAuditLogsSourceProjectID(projectID string) CloudAuditLogsSourceOption {
 	return func(s *v1.CloudAuditLogsSource) {
 		s.Status.ProjectID = projectID
@@ -224,6 +230,21 @@ func WithCloudAuditLogsSourceMethodName(methodName string) CloudAuditLogsSourceO
 	}
 }
 
+func WithCloudAuditLogsSourceFilter(filter string) CloudAuditLogsSourceOption {
+	return func(s *v1.CloudAuditLogsSource) {
+		s.Spec.Filter = filter
+	}
+}
+
+func WithCloudAuditLogsSourceParent(parentType v1.AuditLogsParentType, id string) CloudAuditLogsSourceOption {
+	return func(s *v1.CloudAuditLogsSource) {
+		s.Spec.Parent = &v1.AuditLogsParent{
+			Type: parentType,
+			ID:   id,
+		}
+	}
+}
+
 func WithCloudAuditLogsSourceFinalizers(finalizers ...string) CloudAuditLogsSourceOption {
 	return func(s *v1.CloudAuditLogsSource) {
 		s.Finalizers = finalizers
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Veroute.on 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// CloudBillingBudgetSourceOption enables further configuration of a CloudBillingBudgetSource.
+type CloudBillingBudgetSourceOption func(*v1.CloudBillingBudgetSource)
+
+// NewCloudBillingBudgetSource creates a CloudBillingBudgetSource with CloudBillingBudgetSourceOptions
+func NewCloudBillingBudgetSource(name, namespace string, so ...CloudBillingBudgetSourceOption) *v1.CloudBillingBudgetSource {
+	s := &v1.CloudBillingBudgetSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-billingbudget-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	return s
+}
+
+func WithCloudBillingBudgetSourceSink(gvk metav1.GroupVersionKind, name string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: testing.ApiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudBillingBudgetSourceDeletionTimestamp(s *v1.CloudBillingBudgetSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithCloudBillingBudgetSourceProject(project string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudBillingBudgetSourceServiceAccount(kServiceAccount string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+// WithInitCloudBillingBudgetSourceConditions initializes the CloudBillingBudgetSource's conditions.
+func WithInitCloudBillingBudgetSourceConditions(s *v1.CloudBillingBudgetSource) {
+	s.Status.InitializeConditions()
+}
+
+func WithCloudBillingBudgetSourceWorkloadIdentityFailed(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionFailed marks the condition that the
+// status of PullSubscription is False
+func WithCloudBillingBudgetSourcePullSubscriptionFailed(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkPullSubscriptionFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown
+func WithCloudBillingBudgetSourcePullSubscriptionUnknown(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionReady marks the condition that the
+// topic is not ready
+func WithCloudBillingBudgetSourcePullSubscriptionReady() CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+	}
+}
+
+// WithCloudBillingBudgetSourceSinkURI sets the status for sink URI
+func WithCloudBillingBudgetSourceSinkURI(url *apis.URL) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudBillingBudgetSourceCloudEventAttributes(ceAttributes ...duckv1.CloudEventAttributes) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
+func WithCloudBillingBudgetSourceSubscriptionID(subscriptionID string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudBillingBudgetSourceFinalizers(finalizers ...string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Finalizers = finalizers
+	}
+}
+
+func WithCloudBillingBudgetSourceStatusObservedGeneration(generation int64) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.Status.ObservedGeneration = generation
+	}
+}
+
+func WithCloudBillingBudgetSourceObjectMetaGeneration(generation int64) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.ObjectMeta.Generation = generation
+	}
+}
+
+func WithCloudBillingBudgetSourceAnnotations(Annotations map[string]string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudBillingBudgetSourceSetDefault(s *v1.CloudBillingBudgetSource) {
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+}
+
+func WithCloudBillingBudgetSourceTopic(topicID string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.Topic = topicID
+	}
+}
+
+func WithCloudBillingBudgetSourceBudgetFilter(filter gcpduckv1.BudgetFilter) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.BudgetFilter = filter
+	}
+}
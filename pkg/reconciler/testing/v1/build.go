@@ -120,6 +120,12 @@ func WithCloudBuildSourceSinkURI(url *apis.URL) CloudBuildSourceOption {
 	}
 }
 
+func WithCloudBuildSourceCloudEventAttributes(ceAttributes ...duckv1.CloudEventAttributes) CloudBuildSourceOption {
+	return func(s *v1.CloudBuildSource) {
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
 func WithCloudBuildSourceSubscriptionID(subscriptionID string) CloudBuildSourceOption {
 	return func(bs *v1.CloudBuildSource) {
 		bs.Status.SubscriptionID = subscriptionID
@@ -0,0 +1,234 @@
+/*
+Copyright 2021 Google LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// CloudMonitoringAlertSourceOption enables further configuration of a CloudMonitoringAlertSource.
+type CloudMonitoringAlertSourceOption func(*v1.CloudMonitoringAlertSource)
+
+// NewCloudMonitoringAlertSource creates a CloudMonitoringAlertSource with CloudMonitoringAlertSourceOptions
+func NewCloudMonitoringAlertSource(name, namespace string, so ...CloudMonitoringAlertSourceOption) *v1.CloudMonitoringAlertSource {
+	s := &v1.CloudMonitoringAlertSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-monitoringalert-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	return s
+}
+
+func WithCloudMonitoringAlertSourceSink(gvk metav1.GroupVersionKind, name string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: testing.ApiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudMonitoringAlertSourceProject(project string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudMonitoringAlertSourceDisplayName(displayName string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Spec.DisplayName = displayName
+	}
+}
+
+func WithCloudMonitoringAlertSourceServiceAccount(kServiceAccount string) CloudMonitoringAlertSourceOption {
+	return func(ps *v1.CloudMonitoringAlertSource) {
+		ps.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+func WithCloudMonitoringAlertSourceDeletionTimestamp(s *v1.CloudMonitoringAlertSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+// WithInitCloudMonitoringAlertSourceConditions initializes the CloudMonitoringAlertSources's conditions.
+func WithInitCloudMonitoringAlertSourceConditions(s *v1.CloudMonitoringAlertSource) {
+	s.Status.InitializeConditions()
+}
+
+func WithCloudMonitoringAlertSourceWorkloadIdentityFailed(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceTopicFailed marks the condition that the
+// status of topic is False.
+func WithCloudMonitoringAlertSourceTopicFailed(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkTopicFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceTopicUnknown marks the condition that the
+// status of topic is Unknown.
+func WithCloudMonitoringAlertSourceTopicUnknown(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkTopicUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceTopicNotReady marks the condition that the
+// topic is not ready.
+func WithCloudMonitoringAlertSourceTopicReady(topicID, projectID string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkTopicReady(s.ConditionSet())
+		s.Status.TopicID = topicID
+		s.Status.ProjectID = projectID
+	}
+}
+
+// WithCloudAuditLogsSourceTopicDeleted is a wrapper to indicate that the
+// topic is deleted. Inside the function, we still mark the status of topic to be ready,
+// as the status of topic is unchanged if the deletion is successful. We do not set the
+// topicID and projectID because they are set to empty when deleting the topic.
+func WithCloudMonitoringAlertSourceTopicDeleted(s *v1.CloudMonitoringAlertSource) {
+	s.Status.MarkTopicReady(s.ConditionSet())
+}
+
+// WithCloudMonitoringAlertSourcePullSubscriptionFailed marks the condition that the
+// topic is False.
+func WithCloudMonitoringAlertSourcePullSubscriptionFailed(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkPullSubscriptionFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown.
+func WithCloudMonitoringAlertSourcePullSubscriptionUnknown(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourcePullSubscriptionReady marks the condition that the
+// topic is ready.
+func WithCloudMonitoringAlertSourcePullSubscriptionReady(s *v1.CloudMonitoringAlertSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudMonitoringAlertSourcePullSubscriptionDeleted is a wrapper to indicate that the
+// PullSubscription is deleted. Inside the function, we still mark the status of
+// PullSubscription to be ready, as the status of PullSubscription is unchanged
+// if the deletion is successful.
+func WithCloudMonitoringAlertSourcePullSubscriptionDeleted(s *v1.CloudMonitoringAlertSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudMonitoringAlertSourceNotificationChannelNotReady marks the condition that the
+// CloudMonitoringAlertSource notification channel is not ready.
+func WithCloudMonitoringAlertSourceNotificationChannelNotReady(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkNotificationChannelNotReady(reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceNotificationChannelUnknown marks the condition that the
+// CloudMonitoringAlertSource notification channel is unknown.
+func WithCloudMonitoringAlertSourceNotificationChannelUnknown(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkNotificationChannelUnknown(reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceNotificationChannelReady marks the condition that the
+// CloudMonitoringAlertSource notification channel is ready and sets Status.NotificationChannelName to channelName.
+func WithCloudMonitoringAlertSourceNotificationChannelReady(channelName string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkNotificationChannelReady(channelName)
+	}
+}
+
+// WithCloudMonitoringAlertSourceNotificationChannelDeleted is a wrapper to indicate that the
+// notification channel is deleted. Inside the function, we still mark the status of the channel to be ready,
+// as the status of the channel is unchanged if the deletion is successful.
+func WithCloudMonitoringAlertSourceNotificationChannelDeleted(channelName string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.MarkNotificationChannelReady(channelName)
+	}
+}
+
+func WithCloudMonitoringAlertSourceSinkURI(url *apis.URL) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudMonitoringAlertSourceCloudEventAttributes(ceAttributes ...duckv1.CloudEventAttributes) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
+func WithCloudMonitoringAlertSourceSubscriptionID(subscriptionID string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+// WithCloudMonitoringAlertSourceNotificationChannelName sets the status for the notification channel name
+func WithCloudMonitoringAlertSourceNotificationChannelName(channelName string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Status.NotificationChannelName = channelName
+	}
+}
+
+func WithCloudMonitoringAlertSourceFinalizers(finalizers ...string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.Finalizers = finalizers
+	}
+}
+
+func WithCloudMonitoringAlertSourceAnnotations(Annotations map[string]string) CloudMonitoringAlertSourceOption {
+	return func(s *v1.CloudMonitoringAlertSource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudMonitoringAlertSourceSetDefaults(s *v1.CloudMonitoringAlertSource) {
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+}
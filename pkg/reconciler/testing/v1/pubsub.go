@@ -124,6 +124,12 @@ func WithCloudPubSubSourceSinkURI(url *apis.URL) CloudPubSubSourceOption {
 	}
 }
 
+func WithCloudPubSubSourceCloudEventAttributes(ceAttributes ...duckv1.CloudEventAttributes) CloudPubSubSourceOption {
+	return func(s *v1.CloudPubSubSource) {
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
 func WithCloudPubSubSourceSubscriptionID(subscriptionID string) CloudPubSubSourceOption {
 	return func(ps *v1.CloudPubSubSource) {
 		ps.Status.SubscriptionID = subscriptionID
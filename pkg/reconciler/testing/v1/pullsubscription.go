@@ -162,6 +162,10 @@ func WithPullSubscriptionMarkDeployed(name, namespace string) PullSubscriptionOp
 	}
 }
 
+func WithPullSubscriptionMarkPushDeployed(s *v1.PullSubscription) {
+	s.Status.MarkPushDeployed()
+}
+
 func WithPullSubscriptionMarkNoDeployed(name, namespace string) PullSubscriptionOption {
 	return func(s *v1.PullSubscription) {
 		s.Status.PropagateDeploymentAvailability(testing.NewDeployment(name, namespace))
@@ -91,6 +91,16 @@ func WithCloudSchedulerSourceData(data string) CloudSchedulerSourceOption {
 	}
 }
 
+func WithCloudSchedulerSourcePaused(s *v1.CloudSchedulerSource) {
+	s.Spec.Paused = true
+}
+
+func WithCloudSchedulerSourceRetryConfig(retryConfig *v1.SchedulerRetryConfig) CloudSchedulerSourceOption {
+	return func(s *v1.CloudSchedulerSource) {
+		s.Spec.RetryConfig = retryConfig
+	}
+}
+
 func WithCloudSchedulerSourceDeletionTimestamp(s *v1.CloudSchedulerSource) {
 	t := metav1.NewTime(time.Unix(1e9, 0))
 	s.ObjectMeta.SetDeletionTimestamp(&t)
@@ -205,12 +215,30 @@ func WithCloudSchedulerSourceJobDeleted(jobName string) CloudSchedulerSourceOpti
 }
 
 // WithCloudSchedulerSourceSinkURI sets the status for sink URI
+func WithCloudSchedulerSourceJobState(state string) CloudSchedulerSourceOption {
+	return func(s *v1.CloudSchedulerSource) {
+		s.Status.JobState = state
+	}
+}
+
+func WithCloudSchedulerSourceRetryConfigStatus(retryConfig *v1.SchedulerRetryConfig) CloudSchedulerSourceOption {
+	return func(s *v1.CloudSchedulerSource) {
+		s.Status.RetryConfig = retryConfig
+	}
+}
+
 func WithCloudSchedulerSourceSinkURI(url *apis.URL) CloudSchedulerSourceOption {
 	return func(s *v1.CloudSchedulerSource) {
 		s.Status.SinkURI = url
 	}
 }
 
+func WithCloudSchedulerSourceCloudEventAttributes(ceAttributes ...duckv1.CloudEventAttributes) CloudSchedulerSourceOption {
+	return func(s *v1.CloudSchedulerSource) {
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
 func WithCloudSchedulerSourceSubscriptionID(subscriptionID string) CloudSchedulerSourceOption {
 	return func(s *v1.CloudSchedulerSource) {
 		s.Status.SubscriptionID = subscriptionID
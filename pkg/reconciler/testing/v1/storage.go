@@ -60,6 +60,22 @@ func WithCloudStorageSourceBucket(bucket string) CloudStorageSourceOption {
 	}
 }
 
+// WithCloudStorageSourceBuckets sets the buckets of the CloudStorageSource.
+func WithCloudStorageSourceBuckets(buckets ...string) CloudStorageSourceOption {
+	return func(s *v1.CloudStorageSource) {
+		s.Spec.Bucket = ""
+		s.Spec.Buckets = buckets
+	}
+}
+
+// WithCloudStorageSourceBucketSelector sets the bucket selector of the CloudStorageSource.
+func WithCloudStorageSourceBucketSelector(selector *metav1.LabelSelector) CloudStorageSourceOption {
+	return func(s *v1.CloudStorageSource) {
+		s.Spec.Bucket = ""
+		s.Spec.BucketSelector = selector
+	}
+}
+
 func WithCloudStorageSourceProject(project string) CloudStorageSourceOption {
 	return func(s *v1.CloudStorageSource) {
 		s.Spec.Project = project
@@ -217,6 +233,12 @@ func WithCloudStorageSourceSinkURI(url *apis.URL) CloudStorageSourceOption {
 	}
 }
 
+func WithCloudStorageSourceCloudEventAttributes(ceAttributes ...duckv1.CloudEventAttributes) CloudStorageSourceOption {
+	return func(s *v1.CloudStorageSource) {
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
 // WithCloudStorageSourceNotificationId sets the status for Notification ID.
 func WithCloudStorageSourceNotificationID(notificationID string) CloudStorageSourceOption {
 	return func(s *v1.CloudStorageSource) {
@@ -224,6 +246,13 @@ func WithCloudStorageSourceNotificationID(notificationID string) CloudStorageSou
 	}
 }
 
+// WithCloudStorageSourceBucketNotifications sets the status for the notifications of each bucket.
+func WithCloudStorageSourceBucketNotifications(notifications ...v1.BucketNotificationStatus) CloudStorageSourceOption {
+	return func(s *v1.CloudStorageSource) {
+		s.Status.Notifications = notifications
+	}
+}
+
 // WithCloudStorageSourceProjectId sets the status for Project ID.
 func WithCloudStorageSourceProjectID(projectID string) CloudStorageSourceOption {
 	return func(s *v1.CloudStorageSource) {
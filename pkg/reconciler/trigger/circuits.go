@@ -0,0 +1,184 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+)
+
+const (
+	// circuitPollPeriod is how often the fanout and retry pods are polled for their circuits.
+	circuitPollPeriod = 30 * time.Second
+	// circuitPollTimeout is the timeout of polling a single pod.
+	circuitPollTimeout = 5 * time.Second
+)
+
+// triggerCircuit is the circuit of a Trigger's subscriber aggregated over the fanout and retry
+// pods.
+type triggerCircuit struct {
+	// state is "Open" if the circuit is open in any pod, and "HalfOpen" otherwise.
+	state string
+	// pods is the number of fanout and retry pods in which the circuit isn't closed.
+	pods int
+}
+
+// circuitWatcher polls the fanout and retry pods of all the BrokerCells for the circuits of
+// Trigger subscribers that are not closed, and enqueues the Triggers whose circuit changed.
+type circuitWatcher struct {
+	podLister corev1listers.PodLister
+	client    *http.Client
+	port      int
+	enqueue   func(types.NamespacedName)
+
+	mu       sync.RWMutex
+	circuits map[types.NamespacedName]triggerCircuit
+}
+
+func newCircuitWatcher(podLister corev1listers.PodLister, enqueue func(types.NamespacedName)) *circuitWatcher {
+	return &circuitWatcher{
+		podLister: podLister,
+		client:    &http.Client{Timeout: circuitPollTimeout},
+		port:      handler.DefaultProbeCheckPort,
+		enqueue:   enqueue,
+		circuits:  make(map[types.NamespacedName]triggerCircuit),
+	}
+}
+
+// run polls the fanout and retry pods every period until ctx is done.
+func (w *circuitWatcher) run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// circuit returns the circuit of the Trigger's subscriber if it isn't closed.
+func (w *circuitWatcher) circuit(namespace, name string) (triggerCircuit, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	c, ok := w.circuits[types.NamespacedName{Namespace: namespace, Name: name}]
+	return c, ok
+}
+
+func (w *circuitWatcher) poll(ctx context.Context) {
+	// Both the fanout and retry pods deliver events to subscribers, so both have circuits.
+	var pods []*corev1.Pod
+	for _, role := range []string{resources.FanoutName, resources.RetryName} {
+		selector := labels.SelectorFromSet(map[string]string{
+			"app":  "events-system",
+			"role": role,
+		})
+		rolePods, err := w.podLister.List(selector)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list pods", zap.String("role", role), zap.Error(err))
+			return
+		}
+		pods = append(pods, rolePods...)
+	}
+
+	circuits := make(map[types.NamespacedName]triggerCircuit)
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		statuses, err := w.getStatuses(ctx, pod.Status.PodIP)
+		if err != nil {
+			// Pods that can't be polled leave their circuits out, rather than keeping stale ones.
+			logging.FromContext(ctx).Warn("Failed to poll pod for circuits",
+				zap.String("pod", pod.Namespace+"/"+pod.Name), zap.Error(err))
+			continue
+		}
+		for _, s := range statuses {
+			if s.CellTenantType != config.CellTenantType_BROKER.String() {
+				continue
+			}
+			key := types.NamespacedName{Namespace: s.Namespace, Name: s.Name}
+			c := circuits[key]
+			if c.state != "Open" {
+				c.state = s.State
+			}
+			c.pods++
+			circuits[key] = c
+		}
+	}
+
+	w.mu.Lock()
+	previous := w.circuits
+	w.circuits = circuits
+	w.mu.Unlock()
+
+	for key, c := range circuits {
+		if previous[key] != c {
+			w.enqueue(key)
+		}
+	}
+	for key := range previous {
+		if _, ok := circuits[key]; !ok {
+			w.enqueue(key)
+		}
+	}
+}
+
+func (w *circuitWatcher) getStatuses(ctx context.Context, podIP string) ([]deliver.CircuitStatus, error) {
+	url := "http://" + net.JoinHostPort(podIP, strconv.Itoa(w.port)) + deliver.CircuitsPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		// The circuit breakers are disabled in the pod.
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status code %d", resp.StatusCode)
+	}
+	var statuses []deliver.CircuitStatus
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
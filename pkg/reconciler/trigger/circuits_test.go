@@ -0,0 +1,145 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	logtest "knative.dev/pkg/logging/testing"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
+	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+func TestCircuitWatcherPoll(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+
+	var mu sync.Mutex
+	statuses := []deliver.CircuitStatus{{
+		Namespace:      testNS,
+		Name:           triggerName,
+		CellTenantType: "BROKER",
+		State:          "Open",
+	}, {
+		Namespace:      testNS,
+		Name:           "channel-subscription",
+		CellTenantType: "CHANNEL",
+		State:          "Open",
+	}}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != deliver.CircuitsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		json.NewEncoder(w).Encode(statuses)
+	}))
+	defer svr.Close()
+	host, port, err := net.SplitHostPort(svr.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pod := func(name, role, ip string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "cloud-run-events",
+				Name:      name,
+				Labels:    resources.Labels("default", role),
+			},
+			Status: corev1.PodStatus{Phase: phase, PodIP: ip},
+		}
+	}
+	listers := NewListers([]runtime.Object{
+		pod("fanout-1", resources.FanoutName, host, corev1.PodRunning),
+		pod("fanout-2", resources.FanoutName, host, corev1.PodRunning),
+		pod("fanout-pending", resources.FanoutName, "", corev1.PodPending),
+		pod("retry-1", resources.RetryName, host, corev1.PodRunning),
+		pod("ingress-1", resources.IngressName, host, corev1.PodRunning),
+	})
+
+	var enqueued []types.NamespacedName
+	w := newCircuitWatcher(listers.GetPodLister(), func(key types.NamespacedName) {
+		enqueued = append(enqueued, key)
+	})
+	w.port, _ = strconv.Atoi(port)
+
+	key := types.NamespacedName{Namespace: testNS, Name: triggerName}
+	w.poll(ctx)
+	if diff := cmp.Diff([]types.NamespacedName{key}, enqueued); diff != "" {
+		t.Errorf("unexpected enqueued Triggers (-want, +got) = %v", diff)
+	}
+	if c, ok := w.circuit(testNS, triggerName); !ok || c != (triggerCircuit{state: "Open", pods: 3}) {
+		t.Errorf("circuit got=(%+v, %v), want open in 3 pods", c, ok)
+	}
+
+	// Polling again without changes doesn't enqueue the Trigger.
+	enqueued = nil
+	w.poll(ctx)
+	if len(enqueued) != 0 {
+		t.Errorf("enqueued Triggers without circuit changes got=%v, want none", enqueued)
+	}
+
+	// The circuit closes.
+	mu.Lock()
+	statuses = []deliver.CircuitStatus{}
+	mu.Unlock()
+	w.poll(ctx)
+	if diff := cmp.Diff([]types.NamespacedName{key}, enqueued); diff != "" {
+		t.Errorf("unexpected enqueued Triggers (-want, +got) = %v", diff)
+	}
+	if _, ok := w.circuit(testNS, triggerName); ok {
+		t.Error("closed circuit still reported")
+	}
+}
+
+func TestPropagateCircuitBreaker(t *testing.T) {
+	r := &Reconciler{
+		circuits: &circuitWatcher{
+			circuits: map[types.NamespacedName]triggerCircuit{
+				{Namespace: testNS, Name: triggerName}: {state: "Open", pods: 1},
+			},
+		},
+	}
+
+	tr := NewTrigger(triggerName, testNS, brokerName)
+	r.propagateCircuitBreaker(tr)
+	c := tr.Status.GetCondition(brokerv1beta1.TriggerConditionCircuitBreaker)
+	if c == nil || c.Status != corev1.ConditionFalse || c.Reason != "CircuitOpen" {
+		t.Errorf("circuit breaker condition got=%+v, want False with reason CircuitOpen", c)
+	}
+
+	r.circuits.circuits = map[types.NamespacedName]triggerCircuit{}
+	r.propagateCircuitBreaker(tr)
+	if c := tr.Status.GetCondition(brokerv1beta1.TriggerConditionCircuitBreaker); c != nil {
+		t.Errorf("circuit breaker condition after closing got=%+v, want none", c)
+	}
+}
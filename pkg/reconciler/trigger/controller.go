@@ -24,6 +24,7 @@ import (
 	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/google/knative-gcp/pkg/logging"
@@ -31,6 +32,7 @@ import (
 	"knative.dev/eventing/pkg/duck"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/source"
+	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	pkgcontroller "knative.dev/pkg/controller"
@@ -42,6 +44,7 @@ import (
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
 	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	reconcilerutils "github.com/google/knative-gcp/pkg/reconciler/utils"
 	"github.com/google/knative-gcp/pkg/utils"
@@ -89,6 +92,19 @@ func newController(ctx context.Context, cmw configmap.Watcher, drs *dataresidenc
 			client.Close()
 		}()
 	}
+	// Without a Cloud Monitoring client, replay subscriptions are deleted once they expire rather
+	// than once drained.
+	metricClient, err := gmonitoring.NewMetricClient(ctx)
+	if err != nil {
+		metricClient = nil
+		logging.FromContext(ctx).Error("Failed to create controller-wide Cloud Monitoring client", zap.Error(err))
+	} else {
+		go func() {
+			<-ctx.Done()
+			metricClient.Close()
+		}()
+	}
+
 	r := &Reconciler{
 		Base:         reconciler.NewBase(ctx, controllerAgentName, cmw),
 		brokerLister: brokerinformer.Get(ctx).Lister(),
@@ -97,12 +113,17 @@ func newController(ctx context.Context, cmw configmap.Watcher, drs *dataresidenc
 			PubsubClient:       client,
 			DataresidencyStore: drs,
 		},
+		clock:        clock.RealClock{},
+		metricClient: metricClient,
 	}
 
 	impl := triggerreconciler.NewImpl(ctx, r, withAgentAndFinalizer)
 	r.sourceTracker = duck.NewListableTracker(ctx, source.Get, impl.EnqueueKey, controller.GetTrackerLease(ctx))
 	r.addressableTracker = duck.NewListableTracker(ctx, addressable.Get, impl.EnqueueKey, controller.GetTrackerLease(ctx))
 	r.uriResolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
+	r.enqueueAfter = impl.EnqueueKeyAfter
+	r.circuits = newCircuitWatcher(podinformer.Get(ctx).Lister(), impl.EnqueueKey)
+	go r.circuits.run(ctx, circuitPollPeriod)
 
 	r.Logger.Info("Setting up event handlers")
 
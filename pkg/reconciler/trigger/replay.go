@@ -0,0 +1,228 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/apimachinery/pkg/types"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+	"github.com/google/knative-gcp/pkg/logging"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+// replayAckDeadline is the ack deadline of replay subscriptions. It matches the maximum time the
+// retry pods take to process an event.
+const replayAckDeadline = 10 * time.Minute
+
+// replayRetention is the message retention of replay subscriptions, the largest allowed by
+// Pub/Sub. Events that were not delivered this long after the end of the replay have expired, so
+// the replay subscription is then deleted.
+const replayRetention = 7 * 24 * time.Hour
+
+// replayDrainCheckPeriod is how often the controller checks whether a replay subscription has
+// delivered the events published before the end of its replay.
+const replayDrainCheckPeriod = 5 * time.Minute
+
+// oldestUnackedMessageAgeMetric is the age in seconds of the oldest unacknowledged message of a
+// subscription, sampled every minute.
+const oldestUnackedMessageAgeMetric = "pubsub.googleapis.com/subscription/oldest_unacked_message_age"
+
+// replayClient returns the Pub/Sub client used for replays. The retry topic and subscription of
+// the Trigger must have been reconciled first, so that the controller-wide client exists.
+func (r *Reconciler) replayClient() gpubsub.Client {
+	return gpubsub.FromPubsubClient(r.targetReconciler.PubsubClient)
+}
+
+// reconcileReplay creates the replay subscription of the Trigger on the decoupling topic of its
+// Broker, and seeks it to the starting point of the Trigger's replay. The subscription is only
+// seeked again when the replay annotation changes. The replay ends when the subscription is
+// seeked, as the fanout delivers the events published since then. The subscription keeps
+// receiving the events published after the end, which the retry pods drop, so it is deleted as
+// soon as the events published before the end have been delivered, or at the latest once they
+// can no longer be retained by it. The replay subscription is also deleted once the Trigger no
+// longer has a replay.
+func (r *Reconciler) reconcileReplay(ctx context.Context, t *brokerv1beta1.Trigger, b *brokerv1beta1.Broker) error {
+	replay, err := t.GetReplay()
+	if err != nil {
+		// The webhook rejects invalid replays, so this should never happen.
+		t.Status.MarkReplayFailed("InvalidReplay", "Failed to parse the replay annotation: %v", err)
+		return nil
+	}
+	if replay == nil {
+		return r.deleteReplay(ctx, t)
+	}
+
+	raw := t.GetAnnotations()[brokerv1beta1.ReplayAnnotationKey]
+	seeked := t.Status.Annotations[brokerv1beta1.ReplayAnnotationKey] == raw
+	if seeked && t.Status.IsReplayCompleted() {
+		return nil
+	}
+
+	client := r.replayClient()
+	subID := brokerresources.GenerateReplaySubscriptionName(t)
+	sub := client.Subscription(subID)
+	if seeked && t.Status.IsReplayReady() {
+		// The subscription has already been seeked for this replay.
+		end, ok := t.Status.GetReplayEndTime()
+		if !ok {
+			// The replay was seeked before replays had an end, so it ends now.
+			end = r.clock.Now()
+			t.Status.Annotations[brokerv1beta1.ReplayEndTimeAnnotationKey] = end.UTC().Format(time.RFC3339Nano)
+		}
+		if expiry := end.Add(replayRetention); r.clock.Now().Before(expiry) {
+			drained, err := r.replayDrained(ctx, subID, end)
+			if err != nil {
+				// Check again later, the subscription is deleted when it expires at the latest.
+				logging.FromContext(ctx).Warn("Failed to check the backlog of the replay subscription", zap.Error(err))
+			}
+			if !drained {
+				delay := replayDrainCheckPeriod
+				if left := expiry.Sub(r.clock.Now()); left < delay {
+					delay = left
+				}
+				r.enqueueAfter(types.NamespacedName{Namespace: t.Namespace, Name: t.Name}, delay)
+				return nil
+			}
+		}
+		if err := sub.Delete(ctx); err != nil && status.Code(err) != codes.NotFound {
+			// Keep the replay ready, as it would be seeked again otherwise.
+			logging.FromContext(ctx).Error("Failed to delete replay subscription", zap.Error(err))
+			return err
+		}
+		t.Status.MarkReplayCompleted("Replayed the events of the Broker until %s", end.UTC().Format(time.RFC3339))
+		return nil
+	}
+
+	exists, err := sub.Exists(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to verify replay subscription exists", zap.Error(err))
+		t.Status.MarkReplayFailed("SubscriptionVerificationFailed", "Failed to verify the replay subscription exists: %v", err)
+		return err
+	}
+	if !exists {
+		sub, err = client.CreateSubscription(ctx, subID, gpubsub.SubscriptionConfig{
+			Topic:             client.Topic(brokerresources.GenerateDecouplingTopicName(b)),
+			AckDeadline:       replayAckDeadline,
+			RetentionDuration: replayRetention,
+			Labels: map[string]string{
+				"resource":  "triggers",
+				"namespace": t.Namespace,
+				"name":      t.Name,
+			},
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to create replay subscription", zap.Error(err))
+			t.Status.MarkReplayFailed("SubscriptionCreationFailed", "Failed to create the replay subscription: %v", err)
+			return err
+		}
+	}
+
+	var from string
+	end := r.clock.Now()
+	if replay.Time != "" {
+		// The webhook has validated the time.
+		since, _ := time.Parse(time.RFC3339, replay.Time)
+		err = sub.SeekToTime(ctx, since)
+		from = fmt.Sprintf("time %s", replay.Time)
+	} else {
+		err = sub.SeekToSnapshot(ctx, client.Snapshot(replay.Snapshot))
+		from = fmt.Sprintf("snapshot %q", replay.Snapshot)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to seek replay subscription", zap.Error(err))
+		t.Status.MarkReplayFailed("SeekFailed", "Failed to seek the replay subscription to %s: %v", from, err)
+		return err
+	}
+
+	if t.Status.Annotations == nil {
+		t.Status.Annotations = make(map[string]string)
+	}
+	t.Status.Annotations[brokerv1beta1.ReplayAnnotationKey] = raw
+	t.Status.Annotations[brokerv1beta1.ReplayEndTimeAnnotationKey] = end.UTC().Format(time.RFC3339Nano)
+	t.Status.MarkReplayReady("Seeked", "Replaying the events of the Broker since %s until %s", from, end.UTC().Format(time.RFC3339))
+	r.enqueueAfter(types.NamespacedName{Namespace: t.Namespace, Name: t.Name}, replayDrainCheckPeriod)
+	return nil
+}
+
+// replayDrained returns whether the replay subscription has delivered all the events published
+// before the end of the replay. That is the case once a sample of the age of its oldest
+// unacknowledged message, taken after the end, shows that message was published after the end.
+// An empty backlog has an age of zero. It returns false if the controller has no Cloud Monitoring
+// client, or no sample was taken after the end yet.
+func (r *Reconciler) replayDrained(ctx context.Context, subID string, end time.Time) (bool, error) {
+	if r.metricClient == nil {
+		return false, nil
+	}
+	projectID, err := utils.ProjectIDOrDefault(r.targetReconciler.ProjectID)
+	if err != nil {
+		return false, err
+	}
+	series, err := r.metricClient.ListTimeSeries(ctx, &monitoringpb.ListTimeSeriesRequest{
+		Name:   "projects/" + projectID,
+		Filter: fmt.Sprintf(`metric.type = %q AND resource.labels.subscription_id = %q`, oldestUnackedMessageAgeMetric, subID),
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(end),
+			EndTime:   timestamppb.New(r.clock.Now()),
+		},
+		View: monitoringpb.ListTimeSeriesRequest_FULL,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, s := range series {
+		// Points are returned from the newest to the oldest.
+		if len(s.Points) == 0 {
+			continue
+		}
+		p := s.Points[0]
+		sampled := p.GetInterval().GetEndTime().AsTime()
+		age := time.Duration(p.GetValue().GetInt64Value()) * time.Second
+		return sampled.Add(-age).After(end), nil
+	}
+	return false, nil
+}
+
+// deleteReplay deletes the replay subscription of the Trigger if it has one.
+func (r *Reconciler) deleteReplay(ctx context.Context, t *brokerv1beta1.Trigger) error {
+	if t.Status.GetCondition(brokerv1beta1.TriggerConditionReplay) == nil {
+		return nil
+	}
+	sub := r.replayClient().Subscription(brokerresources.GenerateReplaySubscriptionName(t))
+	if err := sub.Delete(ctx); err != nil && status.Code(err) != codes.NotFound {
+		logging.FromContext(ctx).Error("Failed to delete replay subscription", zap.Error(err))
+		t.Status.MarkReplayFailed("SubscriptionDeletionFailed", "Failed to delete the replay subscription: %v", err)
+		return err
+	}
+	delete(t.Status.Annotations, brokerv1beta1.ReplayAnnotationKey)
+	delete(t.Status.Annotations, brokerv1beta1.ReplayEndTimeAnnotationKey)
+	if len(t.Status.Annotations) == 0 {
+		t.Status.Annotations = nil
+	}
+	t.Status.MarkReplayFinished()
+	return nil
+}
@@ -19,12 +19,15 @@ package trigger
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/reconciler/celltenant"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
 
 	"github.com/google/knative-gcp/pkg/logging"
 	"knative.dev/eventing/pkg/duck"
@@ -35,6 +38,7 @@ import (
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	reconcilerutils "github.com/google/knative-gcp/pkg/reconciler/utils"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
@@ -59,6 +63,19 @@ type Reconciler struct {
 	// Dynamic tracker to track AddressableTypes. It tracks Trigger subscribers.
 	addressableTracker duck.ListableTracker
 	uriResolver        *resolver.URIResolver
+
+	// circuits holds the circuits of Trigger subscribers that are not closed in the fanout. If
+	// nil, the circuit breaker condition is never set.
+	circuits *circuitWatcher
+
+	// clock is used to end replays.
+	clock clock.Clock
+	// enqueueAfter enqueues a Trigger to be reconciled after a delay, so that its replay
+	// subscription is deleted once the replay completes.
+	enqueueAfter func(types.NamespacedName, time.Duration)
+	// metricClient reads the backlog of replay subscriptions, so that they are deleted once
+	// drained. If nil, replay subscriptions are deleted once they expire.
+	metricClient gmonitoring.MetricClient
 }
 
 // Check that TriggerReconciler implements Interface
@@ -132,15 +149,21 @@ func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b
 		b.SetDefaults(ctx)
 	}
 
-	ct := celltenant.TargetFromTrigger(t, b.Spec.Delivery)
+	ct := celltenant.TargetFromTrigger(t, celltenant.TriggerDeliverySpec(t, b.Spec.Delivery), b.GetOrderingKeyAttribute() != "")
 	if err := r.targetReconciler.ReconcileRetryTopicAndSubscription(ctx, r.Recorder, ct); err != nil {
 		return err
 	}
 
+	if err := r.reconcileReplay(ctx, t, b); err != nil {
+		return err
+	}
+
 	if err := r.checkDependencyAnnotation(ctx, t); err != nil {
 		return err
 	}
 
+	r.propagateCircuitBreaker(t)
+
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, triggerReconciled, "Trigger reconciled: \"%s/%s\"", t.Namespace, t.Name)
 }
 
@@ -156,10 +179,13 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, t *brokerv1beta1.Trigger)
 	if !hasGCPBrokerFinalizer(t) {
 		return nil
 	}
-	ct := celltenant.TargetFromTrigger(t, nil)
+	ct := celltenant.TargetFromTrigger(t, nil, false)
 	if err := r.targetReconciler.DeleteRetryTopicAndSubscription(ctx, r.Recorder, ct); err != nil {
 		return err
 	}
+	if err := r.deleteReplay(ctx, t); err != nil {
+		return err
+	}
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, triggerFinalized, "Trigger finalized: \"%s/%s\"", t.Namespace, t.Name)
 }
 
@@ -183,6 +209,24 @@ func (r *Reconciler) resolveSubscriber(ctx context.Context, t *brokerv1beta1.Tri
 	return nil
 }
 
+// propagateCircuitBreaker sets the circuit breaker condition of the Trigger from the circuit of
+// its subscriber in the fanout and retry pods.
+func (r *Reconciler) propagateCircuitBreaker(t *brokerv1beta1.Trigger) {
+	if r.circuits == nil {
+		return
+	}
+	c, ok := r.circuits.circuit(t.Namespace, t.Name)
+	if !ok {
+		t.Status.MarkCircuitBreakerClosed()
+		return
+	}
+	if c.state == "HalfOpen" {
+		t.Status.MarkCircuitBreakerOpen("CircuitHalfOpen", "Deliveries to the subscriber are short-circuited while it is probed in %d fanout or retry pod(s)", c.pods)
+		return
+	}
+	t.Status.MarkCircuitBreakerOpen("CircuitOpen", "Deliveries to the subscriber are short-circuited in %d fanout or retry pod(s)", c.pods)
+}
+
 // hasGCPBrokerFinalizer checks if the Trigger object has a finalizer matching the one added by this controller.
 func hasGCPBrokerFinalizer(t *brokerv1beta1.Trigger) bool {
 	for _, f := range t.Finalizers {
@@ -26,13 +26,17 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/duck"
 	"knative.dev/pkg/apis"
@@ -48,7 +52,10 @@ import (
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1alpha1/resource"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	gmonitoringtesting "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 )
 
@@ -75,6 +82,12 @@ var (
 
 	testKey = fmt.Sprintf("%s/%s", testNS, triggerName)
 
+	testReplay          = `{"time": "2021-03-04T05:06:07Z"}`
+	testReplayEnd       = "2021-03-05T00:00:00Z"
+	testReplayMessage   = "Replaying the events of the Broker since time 2021-03-04T05:06:07Z until 2021-03-05T00:00:00Z"
+	testNow, _          = time.Parse(time.RFC3339, testReplayEnd)
+	testDecouplingTopic = brokerresources.GenerateDecouplingTopicName(NewBroker(brokerName, testNS))
+
 	triggerFinalizerUpdatedEvent   = Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "test-trigger" finalizers`)
 	triggerReconciledEvent         = Eventf(corev1.EventTypeNormal, "TriggerReconciled", `Trigger reconciled: "testnamespace/test-trigger"`)
 	triggerFinalizedEvent          = Eventf(corev1.EventTypeNormal, "TriggerFinalized", `Trigger finalized: "testnamespace/test-trigger"`)
@@ -101,6 +114,15 @@ var (
 			},
 		},
 	}
+	triggerDeliverySpec = &eventingduckv1.DeliverySpec{
+		Retry: &retry,
+		DeadLetterSink: &duckv1.Destination{
+			URI: &apis.URL{
+				Scheme: "http",
+				Host:   "dead-letter.example.com",
+			},
+		},
+	}
 	brokerDeliverySpecWithoutRetry = &eventingduckv1beta1.DeliverySpec{
 		BackoffDelay:  &backoffDelay,
 		BackoffPolicy: &backoffPolicy,
@@ -431,6 +453,365 @@ func TestAllCasesTrigger(t *testing.T) {
 				}),
 			},
 		},
+		{
+			Name: "Trigger of ordered broker, retry subscription ordered",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithBrokerOrderingKeyAttribute("partitionkey"),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasMessageOrdering("cre-tgr_testnamespace_test-trigger_abc123", true),
+			},
+		},
+		{
+			Name: "Trigger with replay, replay subscription created and seeked",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerReplayReady(testReplay, testReplayEnd, testReplayMessage),
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopic),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr-rpl_testnamespace_test-trigger_abc123"),
+			},
+		},
+		{
+			Name: "Trigger replay seeked, replay subscription retained until it expires",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerReplayReady(testReplay, "2021-03-01T00:00:00Z", "Replaying"),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerReplayReady(testReplay, "2021-03-01T00:00:00Z", "Replaying"),
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+					TopicAndSub(testDecouplingTopic, "cre-tgr-rpl_testnamespace_test-trigger_abc123"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr-rpl_testnamespace_test-trigger_abc123"),
+			},
+		},
+		{
+			Name: "Trigger replay seeked, replay subscription retained until its backlog is drained",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerReplayReady(testReplay, "2021-03-01T00:00:00Z", "Replaying"),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerReplayReady(testReplay, "2021-03-01T00:00:00Z", "Replaying"),
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			OtherTestData: map[string]interface{}{
+				// The oldest unacknowledged message was published before the end of the replay.
+				"replayBacklogAge": 5 * 24 * time.Hour,
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+					TopicAndSub(testDecouplingTopic, "cre-tgr-rpl_testnamespace_test-trigger_abc123"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr-rpl_testnamespace_test-trigger_abc123"),
+			},
+		},
+		{
+			Name: "Trigger replay drained, replay subscription deleted and replay completed",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerReplayReady(testReplay, "2021-03-01T00:00:00Z", "Replaying"),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerReplayCompleted(testReplay, "2021-03-01T00:00:00Z", "Replayed the events of the Broker until 2021-03-01T00:00:00Z"),
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			OtherTestData: map[string]interface{}{
+				// The oldest unacknowledged message was published after the end of the replay.
+				"replayBacklogAge": 2 * time.Hour,
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+					TopicAndSub(testDecouplingTopic, "cre-tgr-rpl_testnamespace_test-trigger_abc123"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+			},
+		},
+		{
+			Name: "Trigger replay expired, replay subscription deleted and replay completed",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerReplayReady(testReplay, "2021-02-25T00:00:00Z", "Replaying"),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayAnnotation(testReplay),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerReplayCompleted(testReplay, "2021-02-25T00:00:00Z", "Replayed the events of the Broker until 2021-02-25T00:00:00Z"),
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+					TopicAndSub(testDecouplingTopic, "cre-tgr-rpl_testnamespace_test-trigger_abc123"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+			},
+		},
+		{
+			Name: "Trigger replay removed, replay subscription deleted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayReady(testReplay, testReplayEnd, testReplayMessage),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+					TopicAndSub(testDecouplingTopic, "cre-tgr-rpl_testnamespace_test-trigger_abc123"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+			},
+		},
 		{
 			Name: "Sub already exists, update config",
 			Key:  testKey,
@@ -490,6 +871,63 @@ func TestAllCasesTrigger(t *testing.T) {
 					}),
 			},
 		},
+		{
+			Name: "Check subscription config - trigger with addressable dead letter sink",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliverySpec(triggerDeliverySpec),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliverySpec(triggerDeliverySpec),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"dataResidencyConfigMap": NewDataresidencyConfigMapFromRegions([]string{"us-east1"}),
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				// The backoff settings are inherited from the broker.
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 5 * time.Second,
+						MinimumBackoff: 5 * time.Second,
+					}),
+				// Addressable dead letter sinks are handled by the broker data plane.
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123", nil),
+			},
+		},
 		{
 			Name: "Check topic config and labels - broker without spec.delivery.retry",
 			Key:  testKey,
@@ -751,6 +1189,7 @@ func TestAllCasesTrigger(t *testing.T) {
 			celltenant.CreatePubsubClientFn = savedCreateFn
 		})
 		var drStore *dataresidency.Store
+		var metricClient gmonitoring.MetricClient
 		if testData != nil {
 			InjectPubsubClient(testData, psclient)
 			if testData["pre"] != nil {
@@ -764,6 +1203,14 @@ func TestAllCasesTrigger(t *testing.T) {
 			if cm, ok := testData["dataResidencyConfigMap"]; ok {
 				drStore = NewDataresidencyTestStore(t, cm.(*corev1.ConfigMap))
 			}
+
+			// If we found "replayBacklogAge" in OtherData, the replay subscription's oldest
+			// unacknowledged message had this age when last sampled, one minute ago.
+			if age, ok := testData["replayBacklogAge"]; ok {
+				metricClient = &gmonitoringtesting.TestMetricClient{
+					TimeSeries: []*monitoringpb.TimeSeries{replayBacklogAge(age.(time.Duration))},
+				}
+			}
 		}
 
 		// If maxPSClientCreateTime is in testData, no pubsub client is passed to reconciler, the reconciler
@@ -786,6 +1233,9 @@ func TestAllCasesTrigger(t *testing.T) {
 			sourceTracker:      duck.NewListableTracker(ctx, source.Get, func(types.NamespacedName) {}, 0),
 			addressableTracker: duck.NewListableTracker(ctx, addressable.Get, func(types.NamespacedName) {}, 0),
 			uriResolver:        resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
+			clock:              clock.NewFakeClock(testNow),
+			enqueueAfter:       func(types.NamespacedName, time.Duration) {},
+			metricClient:       metricClient,
 			targetReconciler: &celltenant.TargetReconciler{
 				ProjectID:          testProject,
 				PubsubClient:       testPSClient,
@@ -798,6 +1248,18 @@ func TestAllCasesTrigger(t *testing.T) {
 	}))
 }
 
+// replayBacklogAge returns the time series of the age of the oldest unacknowledged message of a
+// replay subscription, sampled one minute ago.
+func replayBacklogAge(age time.Duration) *monitoringpb.TimeSeries {
+	sampled := timestamppb.New(testNow.Add(-time.Minute))
+	return &monitoringpb.TimeSeries{
+		Points: []*monitoringpb.Point{{
+			Interval: &monitoringpb.TimeInterval{StartTime: sampled, EndTime: sampled},
+			Value:    &monitoringpb.TypedValue{Value: &monitoringpb.TypedValue_Int64Value{Int64Value: int64(age / time.Second)}},
+		}},
+	}
+}
+
 func makeSubscriberAddressableAsUnstructured() *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
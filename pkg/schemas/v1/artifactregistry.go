@@ -0,0 +1,35 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+)
+
+const (
+	// ArtifactRegistryImagePushedEventType is the CloudEvent type of the notifications of the
+	// images pushed to Artifact Registry or Container Registry.
+	ArtifactRegistryImagePushedEventType = "google.cloud.artifactregistry.image.v1.pushed"
+	// ArtifactRegistryImageDeletedEventType is the CloudEvent type of the notifications of the
+	// images deleted from Artifact Registry or Container Registry.
+	ArtifactRegistryImageDeletedEventType = "google.cloud.artifactregistry.image.v1.deleted"
+)
+
+// ArtifactRegistryEventSource returns the Artifact Registry CloudEvent source value.
+func ArtifactRegistryEventSource(googleCloudProject string) string {
+	return fmt.Sprintf("//artifactregistry.googleapis.com/projects/%s", googleCloudProject)
+}
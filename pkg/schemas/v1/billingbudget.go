@@ -0,0 +1,45 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+)
+
+const (
+	// CloudBillingBudgetThresholdExceededEventType is the CloudEvent type of the budget
+	// notifications whose cost exceeded an alert threshold of the budget.
+	CloudBillingBudgetThresholdExceededEventType = "google.cloud.billing.budget.v1.thresholdExceeded"
+	// CloudBillingBudgetCostUpdatedEventType is the CloudEvent type of the other budget
+	// notifications, which report the current cost.
+	CloudBillingBudgetCostUpdatedEventType = "google.cloud.billing.budget.v1.costUpdated"
+	// CloudBillingBudgetBillingAccountID is the Pub/Sub message attribute key with the ID of the
+	// billing account of the budget.
+	CloudBillingBudgetBillingAccountID = "billingAccountId"
+	// CloudBillingBudgetBudgetID is the Pub/Sub message attribute key with the ID of the budget.
+	CloudBillingBudgetBudgetID = "budgetId"
+)
+
+// CloudBillingBudgetEventSource returns the Cloud Billing budget CloudEvent source value.
+func CloudBillingBudgetEventSource(billingAccountID string) string {
+	return fmt.Sprintf("//billingbudgets.googleapis.com/billingAccounts/%s", billingAccountID)
+}
+
+// CloudBillingBudgetEventSubject returns the Cloud Billing budget CloudEvent subject value.
+func CloudBillingBudgetEventSubject(budgetID string) string {
+	return fmt.Sprintf("budgets/%s", budgetID)
+}
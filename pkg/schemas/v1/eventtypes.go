@@ -0,0 +1,84 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// EventTypeInfo describes a CloudEvent type emitted by the sources.
+type EventTypeInfo struct {
+	// Description is a human readable description of the events of the type.
+	Description string
+	// DataSchema is the URL of the schema of the data of the events, if any.
+	DataSchema string
+}
+
+var eventTypes = map[string]EventTypeInfo{
+	CloudAuditLogsLogWrittenEventType: {
+		Description: "An entry was written to a Cloud Audit Logs log.",
+		DataSchema:  CloudAuditLogsEventDataSchema,
+	},
+	CloudStorageObjectFinalizedEventType: {
+		Description: "An object was created in, or overwritten in, a Cloud Storage bucket.",
+		DataSchema:  CloudStorageEventDataSchema,
+	},
+	CloudStorageObjectArchivedEventType: {
+		Description: "A live version of an object in a Cloud Storage bucket became a noncurrent version.",
+		DataSchema:  CloudStorageEventDataSchema,
+	},
+	CloudStorageObjectDeletedEventType: {
+		Description: "An object was permanently deleted from a Cloud Storage bucket.",
+		DataSchema:  CloudStorageEventDataSchema,
+	},
+	CloudStorageObjectMetadataUpdatedEventType: {
+		Description: "The metadata of an existing object in a Cloud Storage bucket changed.",
+		DataSchema:  CloudStorageEventDataSchema,
+	},
+	CloudSchedulerJobExecutedEventType: {
+		Description: "A Cloud Scheduler job was executed.",
+		DataSchema:  CloudSchedulerEventDataSchema,
+	},
+	CloudPubSubMessagePublishedEventType: {
+		Description: "A message was published to a Cloud Pub/Sub topic.",
+		DataSchema:  CloudPubSubEventDataSchema,
+	},
+	CloudBuildSourceEventType: {
+		Description: "The status of a Cloud Build build changed.",
+	},
+	ArtifactRegistryImagePushedEventType: {
+		Description: "A container image was pushed to Artifact Registry.",
+	},
+	ArtifactRegistryImageDeletedEventType: {
+		Description: "A container image was deleted from Artifact Registry.",
+	},
+	CloudBillingBudgetThresholdExceededEventType: {
+		Description: "The cost of a Cloud Billing budget exceeded one of its alert thresholds.",
+	},
+	CloudBillingBudgetCostUpdatedEventType: {
+		Description: "The current cost of a Cloud Billing budget was reported.",
+	},
+	CloudMonitoringIncidentOpenedEventType: {
+		Description: "An incident of a Cloud Monitoring alerting policy was opened.",
+	},
+	CloudMonitoringIncidentClosedEventType: {
+		Description: "An incident of a Cloud Monitoring alerting policy was closed.",
+	},
+}
+
+// LookupEventType returns the EventTypeInfo of the given CloudEvent type, and whether the type is
+// emitted by any of the sources.
+func LookupEventType(eventType string) (EventTypeInfo, bool) {
+	info, ok := eventTypes[eventType]
+	return info, ok
+}
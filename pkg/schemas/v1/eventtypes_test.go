@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+)
+
+func TestLookupEventType(t *testing.T) {
+	info, ok := LookupEventType(CloudStorageObjectFinalizedEventType)
+	if !ok {
+		t.Fatalf("LookupEventType(%q) not found", CloudStorageObjectFinalizedEventType)
+	}
+	if info.DataSchema != CloudStorageEventDataSchema {
+		t.Errorf("LookupEventType DataSchema got=%s, want=%s", info.DataSchema, CloudStorageEventDataSchema)
+	}
+	if info.Description == "" {
+		t.Error("LookupEventType Description is empty")
+	}
+
+	if _, ok := LookupEventType("com.example.unknown"); ok {
+		t.Error("LookupEventType found an unknown event type")
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+)
+
+const (
+	// CloudMonitoringIncidentOpenedEventType is the CloudEvent type of the notifications of the
+	// Cloud Monitoring alerting incidents that are opened.
+	CloudMonitoringIncidentOpenedEventType = "google.cloud.monitoring.incident.v1.opened"
+	// CloudMonitoringIncidentClosedEventType is the CloudEvent type of the notifications of the
+	// Cloud Monitoring alerting incidents that are closed.
+	CloudMonitoringIncidentClosedEventType = "google.cloud.monitoring.incident.v1.closed"
+	// CloudMonitoringIncidentStateOpen is the state of the incidents that are open.
+	CloudMonitoringIncidentStateOpen = "open"
+	// CloudMonitoringIncidentStateClosed is the state of the incidents that are closed.
+	CloudMonitoringIncidentStateClosed = "closed"
+)
+
+// CloudMonitoringEventSource returns the Cloud Monitoring CloudEvent source value.
+func CloudMonitoringEventSource(googleCloudProject string) string {
+	return fmt.Sprintf("//monitoring.googleapis.com/projects/%s", googleCloudProject)
+}
+
+// CloudMonitoringIncidentEventSubject returns the Cloud Monitoring incident CloudEvent subject value.
+func CloudMonitoringIncidentEventSubject(incidentID string) string {
+	return fmt.Sprintf("incidents/%s", incidentID)
+}
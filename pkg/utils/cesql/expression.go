@@ -0,0 +1,439 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cesql implements the CloudEvents SQL expression language used by
+// Trigger filters.
+//
+// Values are either strings, 32 bit integers or booleans. When an operator or
+// function receives a value of another type, the value is cast following the
+// CloudEvents SQL casting rules. Evaluation errors such as failed casts,
+// division by zero or references to attributes missing from the event are
+// returned alongside the zero value of the expected type.
+package cesql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// Expression is a parsed CloudEvents SQL expression.
+type Expression interface {
+	// Evaluate evaluates the expression against the given event. The result
+	// is either a string, an int32 or a bool.
+	Evaluate(event cloudevents.Event) (interface{}, error)
+}
+
+// Match evaluates the expression against the given event and reports whether
+// the result is the boolean true. Evaluation errors are reported as a
+// non-match.
+func Match(e Expression, event cloudevents.Event) (bool, error) {
+	v, err := e.Evaluate(event)
+	if err != nil {
+		return false, err
+	}
+	b, err := castToBool(v)
+	if err != nil {
+		return false, err
+	}
+	return b, nil
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) Evaluate(cloudevents.Event) (interface{}, error) {
+	return l.value, nil
+}
+
+type attributeExpression struct {
+	name string
+}
+
+func (a attributeExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	v, ok := attribute(event, a.name)
+	if !ok {
+		return false, fmt.Errorf("missing attribute %q", a.name)
+	}
+	return v, nil
+}
+
+type existsExpression struct {
+	attribute string
+}
+
+func (e existsExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	_, ok := attribute(event, e.attribute)
+	return ok, nil
+}
+
+// attribute returns the value of the named context attribute or extension of
+// the event. Optional attributes that are not set are reported as missing.
+func attribute(event cloudevents.Event, name string) (interface{}, bool) {
+	var s string
+	switch name {
+	case "specversion":
+		s = event.SpecVersion()
+	case "id":
+		s = event.ID()
+	case "source":
+		s = event.Source()
+	case "type":
+		s = event.Type()
+	case "subject":
+		s = event.Subject()
+	case "dataschema", "schemaurl":
+		s = event.DataSchema()
+	case "datacontenttype":
+		s = event.DataContentType()
+	case "time":
+		if event.Time().IsZero() {
+			return nil, false
+		}
+		s = event.Time().Format(time.RFC3339Nano)
+	default:
+		v, ok := event.Extensions()[name]
+		if !ok {
+			return nil, false
+		}
+		switch v := v.(type) {
+		case string:
+			return v, true
+		case int32:
+			return v, true
+		case bool:
+			return v, true
+		default:
+			f, err := types.Format(v)
+			if err != nil {
+				return nil, false
+			}
+			return f, true
+		}
+	}
+	if s == "" {
+		return nil, false
+	}
+	return s, true
+}
+
+type notExpression struct {
+	operand Expression
+}
+
+func (n notExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	v, err := evaluateBool(n.operand, event)
+	if err != nil {
+		return false, err
+	}
+	return !v, nil
+}
+
+type negateExpression struct {
+	operand Expression
+}
+
+func (n negateExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	v, err := evaluateInt(n.operand, event)
+	if err != nil {
+		return int32(0), err
+	}
+	return -v, nil
+}
+
+type logicalExpression struct {
+	op          string
+	left, right Expression
+}
+
+func (l logicalExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	left, err := evaluateBool(l.left, event)
+	if err != nil {
+		return false, err
+	}
+	// AND and OR short circuit.
+	switch {
+	case l.op == "AND" && !left:
+		return false, nil
+	case l.op == "OR" && left:
+		return true, nil
+	}
+	right, err := evaluateBool(l.right, event)
+	if err != nil {
+		return false, err
+	}
+	switch l.op {
+	case "AND":
+		return left && right, nil
+	case "OR":
+		return left || right, nil
+	default:
+		return left != right, nil
+	}
+}
+
+type comparisonExpression struct {
+	op          string
+	left, right Expression
+}
+
+func (c comparisonExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	left, err := c.left.Evaluate(event)
+	if err != nil {
+		return false, err
+	}
+	right, err := c.right.Evaluate(event)
+	if err != nil {
+		return false, err
+	}
+	if c.op == "=" || c.op == "!=" {
+		eq, err := equal(left, right)
+		if err != nil {
+			return false, err
+		}
+		return eq == (c.op == "="), nil
+	}
+
+	// The ordering operators only apply to integers.
+	l, err := castToInt(left)
+	if err != nil {
+		return false, err
+	}
+	r, err := castToInt(right)
+	if err != nil {
+		return false, err
+	}
+	switch c.op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	default:
+		return l >= r, nil
+	}
+}
+
+// equal compares two values after casting the right value to the type of the
+// left one.
+func equal(left, right interface{}) (bool, error) {
+	switch l := left.(type) {
+	case int32:
+		r, err := castToInt(right)
+		return l == r, err
+	case bool:
+		r, err := castToBool(right)
+		return l == r, err
+	default:
+		r, err := castToString(right)
+		return left == r, err
+	}
+}
+
+type arithmeticExpression struct {
+	op          string
+	left, right Expression
+}
+
+func (a arithmeticExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	l, err := evaluateInt(a.left, event)
+	if err != nil {
+		return int32(0), err
+	}
+	r, err := evaluateInt(a.right, event)
+	if err != nil {
+		return int32(0), err
+	}
+	switch a.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	}
+	if r == 0 {
+		return int32(0), errors.New("division by zero")
+	}
+	if a.op == "/" {
+		return l / r, nil
+	}
+	return l % r, nil
+}
+
+type likeExpression struct {
+	operand Expression
+	// pattern is the LIKE pattern, compiled once when the expression is parsed.
+	pattern *regexp.Regexp
+}
+
+func (l likeExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	s, err := evaluateString(l.operand, event)
+	if err != nil {
+		return false, err
+	}
+	return l.pattern.MatchString(s), nil
+}
+
+// likePatternToRegexp converts a LIKE pattern to an anchored regular
+// expression. '%' matches any sequence of characters, '_' matches exactly one
+// character, and a backslash escapes the following character.
+func likePatternToRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("(?s)^")
+	rs := []rune(pattern)
+	for i := 0; i < len(rs); i++ {
+		switch {
+		case rs[i] == '\\' && i+1 < len(rs):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(rs[i])))
+		case rs[i] == '%':
+			sb.WriteString(".*")
+		case rs[i] == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(rs[i])))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+type inExpression struct {
+	operand Expression
+	set     []Expression
+}
+
+func (in inExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	v, err := in.operand.Evaluate(event)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range in.set {
+		candidate, err := e.Evaluate(event)
+		if err != nil {
+			return false, err
+		}
+		eq, err := equal(v, candidate)
+		if err != nil {
+			return false, err
+		}
+		if eq {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type functionExpression struct {
+	name string
+	fn   function
+	args []Expression
+}
+
+func (f functionExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	args := make([]interface{}, 0, len(f.args))
+	for _, a := range f.args {
+		v, err := a.Evaluate(event)
+		if err != nil {
+			return f.fn.zero, err
+		}
+		args = append(args, v)
+	}
+	v, err := f.fn.call(args)
+	if err != nil {
+		return f.fn.zero, fmt.Errorf("%s: %w", f.name, err)
+	}
+	return v, nil
+}
+
+func evaluateBool(e Expression, event cloudevents.Event) (bool, error) {
+	v, err := e.Evaluate(event)
+	if err != nil {
+		return false, err
+	}
+	return castToBool(v)
+}
+
+func evaluateInt(e Expression, event cloudevents.Event) (int32, error) {
+	v, err := e.Evaluate(event)
+	if err != nil {
+		return 0, err
+	}
+	return castToInt(v)
+}
+
+func evaluateString(e Expression, event cloudevents.Event) (string, error) {
+	v, err := e.Evaluate(event)
+	if err != nil {
+		return "", err
+	}
+	return castToString(v)
+}
+
+func castToBool(v interface{}) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot cast %q to boolean", v)
+	}
+	return false, fmt.Errorf("cannot cast %v to boolean", v)
+}
+
+func castToInt(v interface{}) (int32, error) {
+	switch v := v.(type) {
+	case int32:
+		return v, nil
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || i > math.MaxInt32 || i < math.MinInt32 {
+			return 0, fmt.Errorf("cannot cast %q to integer", v)
+		}
+		return int32(i), nil
+	}
+	return 0, fmt.Errorf("cannot cast %v to integer", v)
+}
+
+func castToString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case int32:
+		return strconv.Itoa(int(v)), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	}
+	return "", fmt.Errorf("cannot cast %v to string", v)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func testEvent() cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID("abc-123")
+	e.SetSource("//pubsub.googleapis.com/projects/p/topics/t")
+	e.SetType("com.example.order.created")
+	e.SetSubject("orders/42")
+	e.SetTime(time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC))
+	e.SetExtension("region", "us-central1")
+	e.SetExtension("priority", 3)
+	e.SetExtension("urgent", true)
+	return e
+}
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		expr    string
+		want    interface{}
+		wantErr bool
+	}{
+		{expr: "type = 'com.example.order.created'", want: true},
+		{expr: `type = "com.example.order.created"`, want: true},
+		{expr: "TYPE <> 'com.example.order.created'", want: false},
+		{expr: "type LIKE 'com.example.order.%'", want: true},
+		{expr: "type LIKE 'com.example.order._reated'", want: true},
+		{expr: "type NOT LIKE '%.deleted'", want: true},
+		{expr: "subject LIKE 'orders/4\\_'", want: false},
+		{expr: "region IN ('us-east1', 'us-central1')", want: true},
+		{expr: "region NOT IN ('us-east1', 'us-central1')", want: false},
+		{expr: "priority > 2 AND urgent", want: true},
+		{expr: "priority >= 4 OR NOT urgent", want: false},
+		{expr: "urgent XOR TRUE", want: false},
+		{expr: "priority = '3'", want: true},
+		{expr: "'3' = priority", want: true},
+		{expr: "urgent = 'TRUE'", want: true},
+		{expr: "priority * 2 + 1", want: int32(7)},
+		{expr: "-priority % 2", want: int32(-1)},
+		{expr: "10 / (priority - 3)", want: int32(0), wantErr: true},
+		{expr: "EXISTS region", want: true},
+		{expr: "EXISTS dataschema", want: false},
+		{expr: "missing = 'x'", want: false, wantErr: true},
+		{expr: "NOT EXISTS missing OR missing = 'x'", want: true},
+		{expr: "time = '2021-02-03T04:05:06Z'", want: true},
+		{expr: "LENGTH(subject)", want: int32(9)},
+		{expr: "UPPER(region) = 'US-CENTRAL1'", want: true},
+		{expr: "CONCAT_WS('/', 'a', priority, urgent)", want: "a/3/true"},
+		{expr: "SUBSTRING(type, -7)", want: "created"},
+		{expr: "LEFT(subject, 6) = 'orders'", want: true},
+		{expr: "RIGHT(subject, 100)", want: "orders/42"},
+		{expr: "IS_INT(subject) OR IS_BOOL('false')", want: true},
+		{expr: "ABS(-2147483648 + 1)", want: int32(2147483647)},
+		{expr: "INT('abc')", want: int32(0), wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tc.expr, err)
+			}
+			got, err := e.Evaluate(testEvent())
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Evaluate error got=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Evaluate (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"type =",
+		"type = 'unterminated",
+		"(type = 'a'",
+		"type == 'a'",
+		"type LIKE 3",
+		"type NOT = 'a'",
+		"UNKNOWN(type)",
+		"LENGTH(type, subject)",
+		"type IN 'a'",
+		"9999999999",
+		"ty-pe = 'a' ;",
+		"region = 'a' region",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Parse(expr); err == nil {
+				t.Errorf("Parse(%q) succeeded, want error", expr)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: "type LIKE 'com.example.%'", want: true},
+		{expr: "subject = 'orders/43'", want: false},
+		{expr: "'true'", want: true},
+		{expr: "priority", want: false, wantErr: true},
+		{expr: "missing = 'x'", want: false, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tc.expr, err)
+			}
+			got, err := Match(e, testEvent())
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Match error got=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Match got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
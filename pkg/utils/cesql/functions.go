@@ -0,0 +1,171 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"errors"
+	"strings"
+)
+
+// function is a built-in CloudEvents SQL function.
+type function struct {
+	// minArgs and maxArgs bound the number of arguments. A negative maxArgs
+	// means the function is variadic.
+	minArgs, maxArgs int
+	// zero is the value returned when the evaluation fails.
+	zero interface{}
+	call func(args []interface{}) (interface{}, error)
+}
+
+func (f function) acceptsArity(n int) bool {
+	return n >= f.minArgs && (f.maxArgs < 0 || n <= f.maxArgs)
+}
+
+var functions = map[string]function{
+	"LENGTH": {minArgs: 1, maxArgs: 1, zero: int32(0), call: func(args []interface{}) (interface{}, error) {
+		s, err := castToString(args[0])
+		return int32(len([]rune(s))), err
+	}},
+	"CONCAT": {minArgs: 0, maxArgs: -1, zero: "", call: func(args []interface{}) (interface{}, error) {
+		ss, err := castAllToString(args)
+		return strings.Join(ss, ""), err
+	}},
+	"CONCAT_WS": {minArgs: 1, maxArgs: -1, zero: "", call: func(args []interface{}) (interface{}, error) {
+		ss, err := castAllToString(args)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(ss[1:], ss[0]), nil
+	}},
+	"LOWER": stringFunction(strings.ToLower),
+	"UPPER": stringFunction(strings.ToUpper),
+	"TRIM":  stringFunction(strings.TrimSpace),
+	"LEFT": {minArgs: 2, maxArgs: 2, zero: "", call: func(args []interface{}) (interface{}, error) {
+		rs, n, err := runesAndLength(args)
+		if err != nil {
+			return "", err
+		}
+		if n > len(rs) {
+			n = len(rs)
+		}
+		return string(rs[:n]), nil
+	}},
+	"RIGHT": {minArgs: 2, maxArgs: 2, zero: "", call: func(args []interface{}) (interface{}, error) {
+		rs, n, err := runesAndLength(args)
+		if err != nil {
+			return "", err
+		}
+		if n > len(rs) {
+			n = len(rs)
+		}
+		return string(rs[len(rs)-n:]), nil
+	}},
+	"SUBSTRING": {minArgs: 2, maxArgs: 3, zero: "", call: substring},
+	"ABS": {minArgs: 1, maxArgs: 1, zero: int32(0), call: func(args []interface{}) (interface{}, error) {
+		i, err := castToInt(args[0])
+		if i < 0 {
+			i = -i
+		}
+		return i, err
+	}},
+	"INT": {minArgs: 1, maxArgs: 1, zero: int32(0), call: func(args []interface{}) (interface{}, error) {
+		return castToInt(args[0])
+	}},
+	"BOOL": {minArgs: 1, maxArgs: 1, zero: false, call: func(args []interface{}) (interface{}, error) {
+		return castToBool(args[0])
+	}},
+	"STRING": {minArgs: 1, maxArgs: 1, zero: "", call: func(args []interface{}) (interface{}, error) {
+		return castToString(args[0])
+	}},
+	"IS_BOOL": {minArgs: 1, maxArgs: 1, zero: false, call: func(args []interface{}) (interface{}, error) {
+		_, err := castToBool(args[0])
+		return err == nil, nil
+	}},
+	"IS_INT": {minArgs: 1, maxArgs: 1, zero: false, call: func(args []interface{}) (interface{}, error) {
+		_, err := castToInt(args[0])
+		return err == nil, nil
+	}},
+}
+
+func stringFunction(f func(string) string) function {
+	return function{minArgs: 1, maxArgs: 1, zero: "", call: func(args []interface{}) (interface{}, error) {
+		s, err := castToString(args[0])
+		return f(s), err
+	}}
+}
+
+func castAllToString(args []interface{}) ([]string, error) {
+	ss := make([]string, 0, len(args))
+	for _, a := range args {
+		s, err := castToString(a)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+
+func runesAndLength(args []interface{}) ([]rune, int, error) {
+	s, err := castToString(args[0])
+	if err != nil {
+		return nil, 0, err
+	}
+	n, err := castToInt(args[1])
+	if err != nil {
+		return nil, 0, err
+	}
+	if n < 0 {
+		return nil, 0, errors.New("length must not be negative")
+	}
+	return []rune(s), int(n), nil
+}
+
+// substring implements SUBSTRING(s, pos[, len]). Positions are 1 based and a
+// negative position counts from the end of the string.
+func substring(args []interface{}) (interface{}, error) {
+	s, err := castToString(args[0])
+	if err != nil {
+		return "", err
+	}
+	rs := []rune(s)
+	pos, err := castToInt(args[1])
+	if err != nil {
+		return "", err
+	}
+	start := int(pos) - 1
+	if pos < 0 {
+		start = len(rs) + int(pos)
+	}
+	if pos == 0 || start < 0 || start >= len(rs) {
+		return "", errors.New("position out of range")
+	}
+	end := len(rs)
+	if len(args) == 3 {
+		n, err := castToInt(args[2])
+		if err != nil {
+			return "", err
+		}
+		if n < 0 {
+			return "", errors.New("length must not be negative")
+		}
+		if start+int(n) < end {
+			end = start + int(n)
+		}
+	}
+	return string(rs[start:end]), nil
+}
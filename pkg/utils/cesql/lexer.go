@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenInteger
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	// text is the raw text of identifiers and operators, or the unquoted
+	// value of string literals.
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// isKeyword reports whether the token is the given keyword. Keywords are case
+// insensitive.
+func (t token) isKeyword(kw string) bool {
+	return t.kind == tokenIdentifier && strings.EqualFold(t.text, kw)
+}
+
+func (t token) isOperator(op string) bool {
+	return t.kind == tokenOperator && t.text == op
+}
+
+// tokenize splits the expression into tokens. The returned slice always ends
+// with a tokenEOF token.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	rs := []rune(expr)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case r == '\'' || r == '"':
+			s, next, err := scanString(rs, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s, pos: i})
+			i = next
+		case r >= '0' && r <= '9':
+			start := i
+			for i < len(rs) && rs[i] >= '0' && rs[i] <= '9' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenInteger, text: string(rs[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(rs[start:i]), pos: start})
+		default:
+			op, ok := scanOperator(rs, i)
+			if !ok {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(rs)}), nil
+}
+
+// scanString scans a quoted string literal starting at rs[start]. A quote
+// character is escaped either by doubling it or by a preceding backslash.
+func scanString(rs []rune, start int) (string, int, error) {
+	quote := rs[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(rs); i++ {
+		switch {
+		case rs[i] == '\\' && i+1 < len(rs) && rs[i+1] == quote:
+			sb.WriteRune(quote)
+			i++
+		case rs[i] == quote && i+1 < len(rs) && rs[i+1] == quote:
+			sb.WriteRune(quote)
+			i++
+		case rs[i] == quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteRune(rs[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string literal at position %d", start)
+}
+
+var operators = []string{"<=", ">=", "!=", "<>", "=", "<", ">", "+", "-", "*", "/", "%"}
+
+func scanOperator(rs []rune, start int) (string, bool) {
+	rest := string(rs[start:])
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			return op, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,345 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a CloudEvents SQL expression.
+//
+// Operator precedence, from lowest to highest, is OR, XOR, AND, NOT,
+// comparisons (=, !=, <>, <, <=, >, >=, LIKE, IN), additive (+, -),
+// multiplicative (*, /, %) and unary minus.
+func Parse(expr string) (Expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %v at position %d", t, t.pos)
+	}
+	return e, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, fmt.Errorf("expected %s at position %d, got %v", what, t.pos, t)
+	}
+	return t, nil
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseXor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("OR") {
+		p.next()
+		right, err := p.parseXor()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpression{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseXor() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("XOR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpression{op: "XOR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpression{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expression, error) {
+	if p.peek().isKeyword("NOT") {
+		p.next()
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{operand: e}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expression, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	negate := false
+	if t.isKeyword("NOT") {
+		// Only "NOT LIKE" and "NOT IN" are valid after an operand.
+		p.next()
+		negate = true
+		t = p.peek()
+		if !t.isKeyword("LIKE") && !t.isKeyword("IN") {
+			return nil, fmt.Errorf("expected LIKE or IN after NOT at position %d, got %v", t.pos, t)
+		}
+	}
+
+	var e Expression
+	switch {
+	case t.isKeyword("LIKE"):
+		p.next()
+		pt, err := p.expect(tokenString, "string pattern")
+		if err != nil {
+			return nil, err
+		}
+		e = likeExpression{operand: left, pattern: likePatternToRegexp(pt.text)}
+	case t.isKeyword("IN"):
+		p.next()
+		set, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		e = inExpression{operand: left, set: set}
+	case t.kind == tokenOperator && isComparisonOperator(t.text):
+		p.next()
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		op := t.text
+		if op == "<>" {
+			op = "!="
+		}
+		return comparisonExpression{op: op, left: left, right: right}, nil
+	default:
+		return left, nil
+	}
+	if negate {
+		return notExpression{operand: e}, nil
+	}
+	return e, nil
+}
+
+func isComparisonOperator(op string) bool {
+	switch op {
+	case "=", "!=", "<>", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseAdditive() (Expression, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.isOperator("+") || t.isOperator("-"); t = p.peek() {
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticExpression{op: t.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.isOperator("*") || t.isOperator("/") || t.isOperator("%"); t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticExpression{op: t.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if p.peek().isOperator("-") {
+		p.next()
+		// Negative integer literals are folded so that math.MinInt32 can be
+		// expressed.
+		if t := p.peek(); t.kind == tokenInteger {
+			p.next()
+			return parseInteger("-"+t.text, t.pos)
+		}
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateExpression{operand: e}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literal{value: t.text}, nil
+	case tokenInteger:
+		return parseInteger(t.text, t.pos)
+	case tokenLParen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case tokenIdentifier:
+		switch {
+		case t.isKeyword("TRUE"):
+			return literal{value: true}, nil
+		case t.isKeyword("FALSE"):
+			return literal{value: false}, nil
+		case t.isKeyword("EXISTS"):
+			a, err := p.expect(tokenIdentifier, "attribute name")
+			if err != nil {
+				return nil, err
+			}
+			return existsExpression{attribute: strings.ToLower(a.text)}, nil
+		}
+		if p.peek().kind == tokenLParen {
+			return p.parseFunction(t)
+		}
+		if !isValidAttributeName(t.text) {
+			return nil, fmt.Errorf("invalid attribute name %q at position %d", t.text, t.pos)
+		}
+		return attributeExpression{name: strings.ToLower(t.text)}, nil
+	}
+	return nil, fmt.Errorf("unexpected %v at position %d", t, t.pos)
+}
+
+func (p *parser) parseFunction(name token) (Expression, error) {
+	args, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := functions[strings.ToUpper(name.text)]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+	if !fn.acceptsArity(len(args)) {
+		return nil, fmt.Errorf("wrong number of arguments for function %q at position %d", name.text, name.pos)
+	}
+	return functionExpression{name: strings.ToUpper(name.text), fn: fn, args: args}, nil
+}
+
+// parseList parses a parenthesized, comma separated list of expressions.
+func (p *parser) parseList() ([]Expression, error) {
+	if _, err := p.expect(tokenLParen, "'('"); err != nil {
+		return nil, err
+	}
+	var list []Expression
+	if p.peek().kind == tokenRParen {
+		p.next()
+		return list, nil
+	}
+	for {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		t := p.next()
+		if t.kind == tokenRParen {
+			return list, nil
+		}
+		if t.kind != tokenComma {
+			return nil, fmt.Errorf("expected ',' or ')' at position %d, got %v", t.pos, t)
+		}
+	}
+}
+
+func parseInteger(s string, pos int) (Expression, error) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || i > math.MaxInt32 || i < math.MinInt32 {
+		return nil, fmt.Errorf("integer literal %s out of range at position %d", s, pos)
+	}
+	return literal{value: int32(i)}, nil
+}
+
+// isValidAttributeName reports whether name is a valid CloudEvents attribute
+// name, i.e. it only consists of ASCII letters and digits.
+func isValidAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}